@@ -11,20 +11,33 @@ import (
         "syscall"
         "time"
 
+        "github.com/shopspring/decimal"
+        "velocimex/internal/alerts"
         "velocimex/internal/api"
         "velocimex/internal/backtesting"
+        "velocimex/internal/chaos"
         "velocimex/internal/config"
         "velocimex/internal/feeds"
+        "velocimex/internal/instruments"
+        "velocimex/internal/logger"
         "velocimex/internal/metrics"
         "velocimex/internal/normalizer"
+        "velocimex/internal/numeric"
         "velocimex/internal/orderbook"
         "velocimex/internal/orders"
         "velocimex/internal/plugins"
+        "velocimex/internal/reports"
         "velocimex/internal/risk"
+        "velocimex/internal/security"
         "velocimex/internal/strategy"
 )
 
 func main() {
+        // Subcommands
+        if len(os.Args) > 1 && os.Args[1] == "selftest" {
+                os.Exit(runSelfTest(os.Args[2:], os.Stdout))
+        }
+
         // Parse command line flags
         configPath := flag.String("config", "config.yaml", "Path to configuration file")
         flag.Parse()
@@ -34,14 +47,87 @@ func main() {
         if err != nil {
                 log.Fatalf("Failed to load configuration: %v", err)
         }
+        if err := cfg.Validate(); err != nil {
+                log.Fatalf("Invalid configuration: %v", err)
+        }
+
+        // Apply decimal precision before any component does arithmetic
+        numeric.ApplyPrecision(cfg.Decimal)
 
         // Initialize components
         normalizer := normalizer.New()
+        normalizer.SetSymbolMappings(cfg.SymbolMappings)
         orderBookManager := orderbook.NewManager()
+        orderBookManager.SetTopOfBookConfig(cfg.TopOfBook)
+        orderBookManager.SetFeedPriority(cfg.FeedPriority)
+        orderBookManager.SetCoalesceConfig(cfg.BookCoalescing)
+        orderBookManager.SetIndexConfig(cfg.IndexPrice)
+        
+        // Restore books from the last snapshot; they are stale until feeds update them
+        var bookSnapshotter *orderbook.Snapshotter
+        if cfg.OrderBookSnapshot.Enabled {
+                restored, err := orderBookManager.LoadSnapshot(cfg.OrderBookSnapshot.Path)
+                if err != nil {
+                        log.Printf("Failed to restore order book snapshot: %v", err)
+                } else if restored > 0 {
+                        log.Printf("Restored %d order books from %s", restored, cfg.OrderBookSnapshot.Path)
+                }
+                bookSnapshotter = orderbook.NewSnapshotter(orderBookManager, cfg.OrderBookSnapshot)
+        }
         
         // Initialize order management system
         smartRouter := orders.NewSmartRouter(orders.DefaultSmartRouterConfig(), orderBookManager)
-        orderManager := orders.NewManager(orders.DefaultManagerConfig(), smartRouter, nil)
+        managerConfig := orders.DefaultManagerConfig()
+        if cfg.TCA.Benchmark != "" {
+                managerConfig.TCA = cfg.TCA
+        }
+        if cfg.StaleBook.MaxAge > 0 {
+                managerConfig.StaleBook.MaxAge = cfg.StaleBook.MaxAge
+                if cfg.StaleBook.Action != "" {
+                        managerConfig.StaleBook.Action = cfg.StaleBook.Action
+                }
+        }
+        if cfg.SymbolLimits != nil {
+                managerConfig.SymbolLimits = cfg.SymbolLimits
+        }
+        managerConfig.DailyOrderLimit = cfg.DailyOrderLimit
+        managerConfig.MaxSlippageBps = cfg.MaxSlippageBps
+        managerConfig.MaxOrderValue = decimal.NewFromFloat(cfg.MaxOrderValue)
+        managerConfig.AckTimeout = cfg.AckTimeout
+        managerConfig.CancelRemainderOnTimeout = cfg.CancelRemainderOnTimeout
+        if cfg.ExpirySweepInterval > 0 {
+                managerConfig.ExpirySweepInterval = cfg.ExpirySweepInterval
+        }
+        if fills := cfg.Simulation.PaperTrading.LimitFills; fills.Model != "" {
+                managerConfig.PaperFill.Model = fills.Model
+                if fills.TouchProbability > 0 {
+                        managerConfig.PaperFill.TouchProbability = fills.TouchProbability
+                }
+                if fills.CheckInterval > 0 {
+                        managerConfig.PaperFill.CheckInterval = fills.CheckInterval
+                }
+        }
+        orderManager := orders.NewManager(managerConfig, smartRouter, nil)
+        orderManager.SetSymbolMapper(normalizer.Symbols())
+        orderManager.SetOrderBooks(orderBookManager)
+        if cfg.IndexPrice.MarkToMarket {
+                orderManager.SetMarkPrices(orderBookManager)
+        }
+
+        // Artificial latency for resilience testing, attached only when it can be turned on
+        latencyInjector := chaos.NewLatencyInjector(cfg.LatencyInjection)
+        injectLatency := cfg.LatencyInjection.Enabled || cfg.LatencyInjection.AllowAPI
+        if injectLatency {
+                log.Printf("Latency injection attached (enabled: %v)", cfg.LatencyInjection.Enabled)
+                orderManager.SetLatencyInjector(latencyInjector)
+        }
+        
+        // Load instrument contract specifications
+        instrumentStore, err := instruments.NewStore(cfg.Instruments)
+        if err != nil {
+                log.Fatalf("Failed to load instruments: %v", err)
+        }
+        orderManager.SetInstruments(instrumentStore)
         
         // Initialize risk management system
         riskManager := risk.NewManager(cfg.Risk, nil)
@@ -54,6 +140,9 @@ func main() {
         if err := backtestEngine.SetConfig(cfg.Backtesting); err != nil {
                 log.Fatalf("Failed to configure backtesting engine: %v", err)
         }
+        if cfg.Metrics.Pushgateway.Enabled {
+                backtestEngine.SetMetricsPusher(metrics.NewBacktestPusher(cfg.Metrics.Pushgateway))
+        }
         
         // Initialize plugin manager
         pluginManager := plugins.NewManager()
@@ -74,10 +163,22 @@ func main() {
                 EnablePprof: cfg.Metrics.EnablePprof,
         }
         metricsServer := metrics.NewServer(metricsConfig, metricsInstance)
+        metricsWrapper := metrics.NewWrapper(metricsInstance, cfg.Metrics.Enabled)
+        backtestEngine.SetMetricsRecorder(metricsWrapper)
+        normalizer.SetMetrics(metricsWrapper)
+        // A present section replaces the defaults, so checks can be turned off
+        if cfg.FeedValidation != nil {
+                normalizer.SetValidationConfig(*cfg.FeedValidation)
+        }
         
         // Setup market data feeds
         feedManager := feeds.NewManager(normalizer, cfg.Feeds)
         feedManager.SetOrderBookManager(orderBookManager)
+        feedManager.SetMetrics(metricsWrapper)
+        feedManager.SetHealthConfig(cfg.FeedHealth)
+        if injectLatency {
+                feedManager.SetLatencyInjector(latencyInjector)
+        }
         if err := feedManager.Connect(); err != nil {
                 log.Fatalf("Failed to connect to feeds: %v", err)
         }
@@ -86,21 +187,181 @@ func main() {
         strategyEngine := strategy.NewEngine(orderBookManager)
         arbitrageStrategy := strategy.NewArbitrageStrategy(cfg.Strategies.Arbitrage)
         strategyEngine.RegisterStrategy(arbitrageStrategy)
+        var rebalanceStrategy *strategy.RebalanceStrategy
+        if cfg.Strategies.Rebalance.Enabled {
+                rebalanceStrategy = strategy.NewRebalanceStrategy(cfg.Strategies.Rebalance)
+                strategyEngine.RegisterStrategy(rebalanceStrategy)
+        }
+        var imbalanceStrategy *strategy.ImbalanceStrategy
+        if cfg.Strategies.Imbalance.Enabled {
+                imbalanceStrategy = strategy.NewImbalanceStrategy(cfg.Strategies.Imbalance)
+                strategyEngine.RegisterStrategy(imbalanceStrategy)
+        }
+        
+        // Stop strategies whose realized trades breach the kill-switch limits
+        if cfg.Strategies.KillSwitch.Enabled {
+                strategyEngine.SetKillSwitch(cfg.Strategies.KillSwitch)
+        }
+        // Flatten positions and pause strategies outside the trading session
+        var sessionScheduler *strategy.SessionScheduler
+        if cfg.Strategies.Session.Enabled {
+                sessionScheduler, err = strategy.NewSessionScheduler(cfg.Strategies.Session, strategyEngine, orderManager)
+                if err != nil {
+                        log.Fatalf("Failed to create session scheduler: %v", err)
+                }
+        }
+        // Setup the daily summary report
+        var reportScheduler *reports.Scheduler
+        var reportChannel alerts.AlertChannel
+        if cfg.Reports.Enabled {
+                reportChannel, err = reports.NewChannel(cfg.Reports)
+                if err != nil {
+                        log.Fatalf("Failed to create report channel: %v", err)
+                }
+                reportScheduler, err = reports.NewScheduler(cfg.Reports, reportChannel)
+                if err != nil {
+                        log.Fatalf("Failed to create report scheduler: %v", err)
+                }
+        }
+        
+        orderManager.SetRealizedTradeHandler(func(trade orders.RealizedTrade) {
+                pnl, _ := trade.PnL.Float64()
+                strategyEngine.RecordTradeResult(strategy.TradeResult{
+                        Strategy:  trade.StrategyName,
+                        Symbol:    trade.Symbol,
+                        PnL:       pnl,
+                        Timestamp: trade.Timestamp,
+                })
+                if reportScheduler != nil {
+                        reportScheduler.RecordRealizedTrade(trade)
+                }
+        })
         
         // Register strategy with backtesting engine
         if err := backtestEngine.RegisterStrategy(arbitrageStrategy); err != nil {
                 log.Fatalf("Failed to register strategy with backtesting engine: %v", err)
         }
+        if rebalanceStrategy != nil {
+                if err := backtestEngine.RegisterStrategy(rebalanceStrategy); err != nil {
+                        log.Fatalf("Failed to register strategy with backtesting engine: %v", err)
+                }
+        }
+        if imbalanceStrategy != nil {
+                if err := backtestEngine.RegisterStrategy(imbalanceStrategy); err != nil {
+                        log.Fatalf("Failed to register strategy with backtesting engine: %v", err)
+                }
+        }
         
         // Start the HTTP and WebSocket server
         router := http.NewServeMux()
         
+        // Apply API display rounding if configured
+        if len(cfg.API.Rounding.Classes) > 0 {
+                api.SetRoundingConfig(api.RoundingConfig(cfg.API.Rounding))
+        }
+        
         // Register API endpoints
         api.RegisterRESTHandlers(router, orderBookManager, strategyEngine, orderManager, riskManager, backtestEngine, pluginManager)
         
         // Setup WebSocket server
         wsServer := api.NewWebSocketServer(orderBookManager, strategyEngine, orderManager, riskManager)
         router.Handle("/ws", wsServer)
+        wsServer.SetMessagePack(cfg.API.WebSocketMessagePack)
+        wsServer.SetMetrics(metricsWrapper)
+        wsServer.SetTopicRateLimits(cfg.API.WebSocketTopicRates)
+        wsServer.SetMaxMessageAge(cfg.API.WebSocketMaxMessageAge)
+        
+        // Require WebSocket clients to authenticate with the security manager
+        var securityManager *security.Manager
+        if cfg.API.WebSocketAuth.Enabled {
+                securityManager = security.NewManager(cfg.Security)
+                if err := securityManager.Start(); err != nil {
+                        log.Fatalf("Failed to start security manager: %v", err)
+                }
+                wsServer.SetAuthenticator(securityManager, cfg.API.WebSocketAuth.Timeout)
+        }
+        
+        // Setup the operator heartbeat dead-man's switch
+        heartbeatWatchdog := orders.NewHeartbeatWatchdog(cfg.Heartbeat, orderManager, metricsWrapper)
+        heartbeatWatchdog.SetTimeoutHandler(func(lastHeartbeat time.Time) {
+                wsServer.BroadcastAlert("critical", fmt.Sprintf("Trading halted: no operator heartbeat since %s", lastHeartbeat.Format(time.RFC3339)))
+        })
+        wsServer.SetHeartbeatWatchdog(heartbeatWatchdog)
+        
+        // Setup per-symbol circuit breakers on extreme price moves
+        var circuitBreaker *orders.CircuitBreaker
+        if cfg.CircuitBreaker.Enabled {
+                circuitBreaker = orders.NewCircuitBreaker(cfg.CircuitBreaker, orderBookManager, metricsWrapper)
+                circuitBreaker.SetEventHandler(func(event orders.CircuitBreakerEvent) {
+                        if event.Halted {
+                                wsServer.BroadcastAlert("critical", fmt.Sprintf("Trading halted on %s: price moved %.2f%% on %s, resuming at %s",
+                                        event.Symbol, event.MovePercent, event.Exchange, event.ResumeAt.Format(time.RFC3339)))
+                                return
+                        }
+                        wsServer.BroadcastAlert("info", fmt.Sprintf("Trading resumed on %s after circuit breaker cooldown", event.Symbol))
+                })
+                orderManager.SetSymbolHalts(circuitBreaker)
+        }
+        strategyEngine.SetKillSwitchHandler(func(event strategy.KillSwitchEvent) {
+                metricsWrapper.RecordRiskEvent("strategy_kill_switch", "critical")
+                wsServer.BroadcastAlert("critical", fmt.Sprintf("Strategy %s stopped: %s", event.Strategy, event.Reason))
+        })
+        if sessionScheduler != nil {
+                sessionScheduler.SetSessionEndHandler(func(event strategy.SessionEndEvent) {
+                        severity := "info"
+                        if len(event.Failed) > 0 {
+                                severity = "critical"
+                        }
+                        wsServer.BroadcastAlert(severity, fmt.Sprintf("Trading session ended: %d strategies stopped, %d positions flattened, %d failed to close",
+                                len(event.Stopped), len(event.Orders), len(event.Failed)))
+                })
+        }
+        feedManager.SetFailoverHandler(func(event feeds.FailoverEvent) {
+                if event.Active == feeds.EndpointBackup {
+                        wsServer.BroadcastAlert("warning", fmt.Sprintf("Feed %s failed over to its backup endpoint: %s", event.Feed, event.Reason))
+                        return
+                }
+                wsServer.BroadcastAlert("info", fmt.Sprintf("Feed %s failed back to its primary endpoint", event.Feed))
+        })
+        feedManager.SetClockSkewHandler(func(event feeds.ClockSkewEvent) {
+                if event.Exceeded {
+                        wsServer.BroadcastAlert("warning", fmt.Sprintf("Clock skew against %s is %s, beyond %s", event.Exchange, event.Skew, event.Threshold))
+                        return
+                }
+                wsServer.BroadcastAlert("info", fmt.Sprintf("Clock skew against %s is back within %s", event.Exchange, event.Threshold))
+        })
+        if cfg.TopOfBook.Enabled {
+                orderBookManager.SubscribeTopOfBook(wsServer.PublishTopOfBook)
+        }
+        api.RegisterHeartbeatHandlers(router, heartbeatWatchdog, orderManager)
+        api.RegisterInstrumentHandlers(router, instrumentStore)
+        api.RegisterFeedHandlers(router, feedManager)
+        if cfg.LatencyInjection.AllowAPI {
+                api.RegisterChaosHandlers(router, latencyInjector)
+        }
+        
+        // Setup alert manager and its REST endpoints; its alerts are delivered
+        // through the engine, which reports the delivery metrics
+        alertConfig := alerts.DefaultAlertConfig()
+        alertConfig.QuietHours = cfg.Alerts.QuietHours
+        if cfg.Alerts.ChannelBreaker != nil {
+                alertConfig.ChannelBreaker = *cfg.Alerts.ChannelBreaker
+        }
+        alertEngine := alerts.NewAlertEngine(alertConfig, logger.GetLogger())
+        alertManager := alerts.NewAlertManager(nil)
+        alertManager.SetEngine(alertEngine)
+        if err := alertManager.Start(); err != nil {
+                log.Fatalf("Failed to start alert manager: %v", err)
+        }
+        // The report channel can be checked with POST /api/v1/alerts/test
+        if reportChannel != nil {
+                alertManager.RegisterChannel(reportChannel)
+        }
+        api.RegisterAlertHandlers(router, alertManager)
+        api.RegisterAlertMetricsHandler(router, alertEngine)
+        if reportScheduler != nil {
+                reportScheduler.SetSources(orderManager, riskManager, alertManager)
+        }
         
         // Start order manager
         ctx := context.Background()
@@ -108,11 +369,56 @@ func main() {
                 log.Fatalf("Failed to start order manager: %v", err)
         }
         
+        // Start heartbeat watchdog
+        if cfg.Heartbeat.Enabled {
+                if err := heartbeatWatchdog.Start(ctx); err != nil {
+                        log.Fatalf("Failed to start heartbeat watchdog: %v", err)
+                }
+        }
+        
+        // Start circuit breakers
+        if circuitBreaker != nil {
+                if err := circuitBreaker.Start(ctx); err != nil {
+                        log.Fatalf("Failed to start circuit breakers: %v", err)
+                }
+        }
+        
+        // Start order book snapshots
+        if bookSnapshotter != nil {
+                if err := bookSnapshotter.Start(ctx); err != nil {
+                        log.Fatalf("Failed to start order book snapshots: %v", err)
+                }
+        }
+        
+        // Start daily report scheduler
+        if reportScheduler != nil {
+                if err := reportScheduler.Start(ctx); err != nil {
+                        log.Fatalf("Failed to start report scheduler: %v", err)
+                }
+        }
+        
+        // Start trading session scheduler
+        if sessionScheduler != nil {
+                if err := sessionScheduler.Start(ctx); err != nil {
+                        log.Fatalf("Failed to start session scheduler: %v", err)
+                }
+        }
+        
         // Start plugin manager
         if err := pluginManager.Start(); err != nil {
                 log.Fatalf("Failed to start plugin manager: %v", err)
         }
         
+        // Sample internal queue depths
+        queueMonitor := metrics.NewQueueMonitor(metricsWrapper, cfg.Metrics.QueueInterval)
+        queueMonitor.Register(orderManager)
+        queueMonitor.Register(alertManager)
+        if cfg.Metrics.Enabled {
+                if err := queueMonitor.Start(ctx); err != nil {
+                        log.Fatalf("Failed to start queue monitor: %v", err)
+                }
+        }
+        
         // Start metrics server
         if cfg.Metrics.Enabled {
                 go func() {
@@ -126,15 +432,24 @@ func main() {
         go wsServer.Run()
         
         // Subscribe to orderbook manager and strategy engine updates and forward them to clients
+        forwardCtx, stopForwarding := context.WithCancel(ctx)
+        forwardDone := make(chan struct{})
         go func() {
+            defer close(forwardDone)
             log.Println("Starting forwarding updates to WebSocket clients")
             // Use a slower ticker (2s) to prevent UI blocking from too frequent updates
             ticker := time.NewTicker(2 * time.Second)
             defer ticker.Stop()
             
-            for range ticker.C {
-                // Just simulate sending some data to clients for now (test only)
-                wsServer.BroadcastSampleData()
+            for {
+                select {
+                case <-forwardCtx.Done():
+                    return
+                case <-ticker.C:
+                    // Just simulate sending some data to clients for now (test only)
+                    wsServer.BroadcastSampleData()
+                    wsServer.PublishOrderBookDiffs()
+                }
             }
         }()
         
@@ -143,10 +458,13 @@ func main() {
         router.Handle("/", fs)
 
         // Start the HTTP server
+        httpServer := &http.Server{
+                Addr:    fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
+                Handler: router,
+        }
         go func() {
-                addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
-                log.Printf("Starting HTTP server on %s", addr)
-                if err := http.ListenAndServe(addr, router); err != nil {
+                log.Printf("Starting HTTP server on %s", httpServer.Addr)
+                if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
                         log.Fatalf("HTTP server error: %v", err)
                 }
         }()
@@ -162,16 +480,99 @@ func main() {
         sig := <-sigChan
         log.Printf("Received signal %v, shutting down...", sig)
         
-        // Graceful shutdown
-        orderManager.Stop(ctx)
-        riskManager.Stop()
-        backtestEngine.Stop()
-        pluginManager.Stop()
+        // Graceful shutdown: stop signal producers, drain consumers, then close infrastructure
+        shutdownTimeout := cfg.Server.ShutdownTimeout
+        if shutdownTimeout <= 0 {
+                shutdownTimeout = 10 * time.Second
+        }
+        shutdown := newShutdownSequence(shutdownTimeout / 2)
+        
+        if sessionScheduler != nil {
+                shutdown.Add(stageProducers, "session scheduler", func(ctx context.Context) error {
+                        sessionScheduler.Stop()
+                        return nil
+                })
+        }
+        shutdown.Add(stageProducers, "strategy engine", func(ctx context.Context) error {
+                return strategyEngine.StopAll()
+        })
+        shutdown.Add(stageProducers, "heartbeat watchdog", func(ctx context.Context) error {
+                heartbeatWatchdog.Stop()
+                return nil
+        })
+        if circuitBreaker != nil {
+                shutdown.Add(stageProducers, "circuit breakers", func(ctx context.Context) error {
+                        circuitBreaker.Stop()
+                        return nil
+                })
+        }
+        shutdown.Add(stageProducers, "backtesting engine", func(ctx context.Context) error {
+                return backtestEngine.Stop()
+        })
+        if reportScheduler != nil {
+                shutdown.Add(stageProducers, "report scheduler", func(ctx context.Context) error {
+                        reportScheduler.Stop()
+                        return nil
+                })
+        }
+        shutdown.Add(stageProducers, "queue monitor", func(ctx context.Context) error {
+                queueMonitor.Stop()
+                return nil
+        })
+        shutdown.Add(stageConsumers, "order manager", func(ctx context.Context) error {
+                return orderManager.Stop(ctx)
+        })
+        shutdown.Add(stageConsumers, "risk manager", func(ctx context.Context) error {
+                return riskManager.Stop()
+        })
+        shutdown.Add(stageConsumers, "plugin manager", func(ctx context.Context) error {
+                return pluginManager.Stop()
+        })
+        shutdown.Add(stageConsumers, "alert manager", func(ctx context.Context) error {
+                return alertManager.Stop()
+        })
+        shutdown.Add(stageConsumers, "alert engine", func(ctx context.Context) error {
+                return alertEngine.Close()
+        })
+        shutdown.Add(stageInfrastructure, "websocket forwarding", func(ctx context.Context) error {
+                stopForwarding()
+                <-forwardDone
+                return nil
+        })
+        shutdown.Add(stageInfrastructure, "http server", func(ctx context.Context) error {
+                return httpServer.Shutdown(ctx)
+        })
+        shutdown.Add(stageInfrastructure, "websocket server", func(ctx context.Context) error {
+                wsServer.Close()
+                return nil
+        })
+        if securityManager != nil {
+                shutdown.Add(stageInfrastructure, "security manager", func(ctx context.Context) error {
+                        return securityManager.Stop()
+                })
+        }
+        shutdown.Add(stageInfrastructure, "feeds", func(ctx context.Context) error {
+                feedManager.Disconnect()
+                return nil
+        })
+        if bookSnapshotter != nil {
+                shutdown.Add(stageInfrastructure, "order book snapshots", func(ctx context.Context) error {
+                        bookSnapshotter.Stop()
+                        return nil
+                })
+        }
         if cfg.Metrics.Enabled {
-                metricsServer.Stop()
+                shutdown.Add(stageInfrastructure, "metrics server", func(ctx context.Context) error {
+                        return metricsServer.Stop()
+                })
+        }
+        
+        shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+        defer cancelShutdown()
+        if errs := shutdown.Run(shutdownCtx); len(errs) > 0 {
+                log.Printf("Shutdown completed with %d errors", len(errs))
+                return
         }
-        feedManager.Disconnect()
-        wsServer.Close()
         
         log.Println("Shutdown complete")
 }
@@ -0,0 +1,184 @@
+package main
+
+import (
+        "context"
+        "flag"
+        "fmt"
+        "io"
+        "net"
+        "net/url"
+        "time"
+
+        "github.com/shopspring/decimal"
+        "velocimex/internal/config"
+        "velocimex/internal/orders"
+)
+
+// selfTestCheck is a single component check run by the selftest command
+type selfTestCheck struct {
+        name string
+        run  func(ctx context.Context) error
+}
+
+// dialFunc opens a network connection, matching net.Dialer.DialContext
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// runSelfTest runs the selftest subcommand and returns the process exit code
+func runSelfTest(args []string, out io.Writer) int {
+        fs := flag.NewFlagSet("selftest", flag.ContinueOnError)
+        fs.SetOutput(out)
+        configPath := fs.String("config", "config.yaml", "Path to configuration file")
+        timeout := fs.Duration("timeout", 5*time.Second, "Timeout for each check")
+        if err := fs.Parse(args); err != nil {
+                return 2
+        }
+
+        cfg, err := config.Load(*configPath)
+        if err != nil {
+                fmt.Fprintf(out, "FAIL  config: %v\n", err)
+                return 1
+        }
+
+        dialer := &net.Dialer{}
+        return runSelfTestChecks(context.Background(), selfTestChecks(cfg, dialer.DialContext), *timeout, out)
+}
+
+// selfTestChecks builds the checks for config validity, feed reachability and a paper order round-trip
+func selfTestChecks(cfg *config.Config, dial dialFunc) []selfTestCheck {
+        checks := []selfTestCheck{
+                {name: "config", run: func(ctx context.Context) error { return cfg.Validate() }},
+        }
+
+        for _, feed := range cfg.Feeds {
+                feed := feed
+                checks = append(checks, selfTestCheck{
+                        name: "feed " + feed.Name,
+                        run: func(ctx context.Context) error {
+                                return checkFeedReachable(ctx, feed, dial)
+                        },
+                })
+        }
+
+        checks = append(checks, selfTestCheck{name: "paper order round-trip", run: checkPaperRoundTrip})
+        return checks
+}
+
+// runSelfTestChecks runs each check with a timeout, prints pass/fail with latency,
+// and returns a non-zero exit code if any check failed
+func runSelfTestChecks(ctx context.Context, checks []selfTestCheck, timeout time.Duration, out io.Writer) int {
+        failed := 0
+        for _, check := range checks {
+                checkCtx, cancel := context.WithTimeout(ctx, timeout)
+                start := time.Now()
+                err := check.run(checkCtx)
+                elapsed := time.Since(start).Round(time.Millisecond)
+                cancel()
+
+                if err != nil {
+                        failed++
+                        fmt.Fprintf(out, "FAIL  %s (%s): %v\n", check.name, elapsed, err)
+                        continue
+                }
+                fmt.Fprintf(out, "PASS  %s (%s)\n", check.name, elapsed)
+        }
+
+        if failed > 0 {
+                fmt.Fprintf(out, "%d of %d checks failed\n", failed, len(checks))
+                return 1
+        }
+
+        fmt.Fprintf(out, "All %d checks passed\n", len(checks))
+        return 0
+}
+
+// checkFeedReachable verifies a TCP connection can be opened to the feed endpoint
+func checkFeedReachable(ctx context.Context, feed config.FeedConfig, dial dialFunc) error {
+        address, err := feedAddress(feed.URL)
+        if err != nil {
+                return err
+        }
+
+        conn, err := dial(ctx, "tcp", address)
+        if err != nil {
+                return fmt.Errorf("unreachable %s: %w", address, err)
+        }
+        return conn.Close()
+}
+
+// feedAddress resolves a feed URL to a host:port address
+func feedAddress(rawURL string) (string, error) {
+        u, err := url.Parse(rawURL)
+        if err != nil {
+                return "", fmt.Errorf("invalid url: %w", err)
+        }
+
+        // FIX endpoints are configured as plain host:port
+        if u.Host == "" {
+                return rawURL, nil
+        }
+        if u.Port() != "" {
+                return u.Host, nil
+        }
+
+        switch u.Scheme {
+        case "wss", "https":
+                return net.JoinHostPort(u.Hostname(), "443"), nil
+        default:
+                return net.JoinHostPort(u.Hostname(), "80"), nil
+        }
+}
+
+// selfTestRouter routes every order to the paper exchange
+type selfTestRouter struct{}
+
+func (r *selfTestRouter) RouteOrder(ctx context.Context, req *orders.OrderRequest) (*orders.RoutingDecision, error) {
+        return &orders.RoutingDecision{Exchange: "paper", Symbol: req.Symbol, Side: req.Side, Timestamp: time.Now()}, nil
+}
+
+func (r *selfTestRouter) UpdateMarketData(exchange string, data interface{}) {}
+
+func (r *selfTestRouter) GetBestPrice(ctx context.Context, symbol string, side orders.OrderSide, quantity decimal.Decimal) (*orders.RoutingDecision, error) {
+        return &orders.RoutingDecision{Exchange: "paper", Symbol: symbol, Side: side, Timestamp: time.Now()}, nil
+}
+
+// checkPaperRoundTrip submits a paper order and waits for its execution
+func checkPaperRoundTrip(ctx context.Context) error {
+        managerConfig := orders.DefaultManagerConfig()
+        managerConfig.EnablePaperTrading = true
+
+        manager := orders.NewManager(managerConfig, &selfTestRouter{}, nil)
+        if err := manager.Start(ctx); err != nil {
+                return fmt.Errorf("failed to start order manager: %w", err)
+        }
+        defer manager.Stop(context.Background())
+
+        order, err := manager.SubmitOrder(ctx, &orders.OrderRequest{
+                Symbol:   "SELFTEST/USD",
+                Side:     orders.OrderSideBuy,
+                Type:     orders.OrderTypeLimit,
+                Quantity: decimal.NewFromInt(1),
+                Price:    decimal.NewFromInt(100),
+        })
+        if err != nil {
+                return fmt.Errorf("failed to submit order: %w", err)
+        }
+
+        ticker := time.NewTicker(10 * time.Millisecond)
+        defer ticker.Stop()
+
+        for {
+                executions, err := manager.GetExecutions(ctx, map[string]interface{}{"order_id": order.ID})
+                if err != nil {
+                        return err
+                }
+                if len(executions) > 0 {
+                        return nil
+                }
+
+                select {
+                case <-ctx.Done():
+                        return fmt.Errorf("order %s not filled: %w", order.ID, ctx.Err())
+                case <-ticker.C:
+                }
+        }
+}
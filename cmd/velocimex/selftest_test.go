@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/config"
+)
+
+// fakeDialer succeeds for reachable addresses and fails for everything else
+func fakeDialer(reachable ...string) dialFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		for _, addr := range reachable {
+			if addr == address {
+				client, server := net.Pipe()
+				server.Close()
+				return client, nil
+			}
+		}
+		return nil, errors.New("connection refused")
+	}
+}
+
+func testSelfTestConfig() *config.Config {
+	return &config.Config{
+		Server: config.ServerConfig{Host: "localhost", Port: 8080},
+		Feeds: []config.FeedConfig{
+			{Name: "binance", Type: "websocket", URL: "wss://stream.binance.com:9443/ws"},
+			{Name: "kraken", Type: "websocket", URL: "wss://ws.kraken.com"},
+		},
+	}
+}
+
+// TestSelfTestHealthy tests that a healthy system exits zero
+func TestSelfTestHealthy(t *testing.T) {
+	checks := selfTestChecks(testSelfTestConfig(), fakeDialer("stream.binance.com:9443", "ws.kraken.com:443"))
+
+	var out bytes.Buffer
+	code := runSelfTestChecks(context.Background(), checks, 2*time.Second, &out)
+
+	assert.Equal(t, 0, code, out.String())
+	assert.Contains(t, out.String(), "PASS  config")
+	assert.Contains(t, out.String(), "PASS  feed binance")
+	assert.Contains(t, out.String(), "PASS  feed kraken")
+	assert.Contains(t, out.String(), "PASS  paper order round-trip")
+	assert.NotContains(t, out.String(), "FAIL")
+}
+
+// TestSelfTestUnhealthy tests that any failing component produces a non-zero exit code
+func TestSelfTestUnhealthy(t *testing.T) {
+	t.Run("unreachable feed", func(t *testing.T) {
+		checks := selfTestChecks(testSelfTestConfig(), fakeDialer("stream.binance.com:9443"))
+
+		var out bytes.Buffer
+		code := runSelfTestChecks(context.Background(), checks, 2*time.Second, &out)
+
+		assert.Equal(t, 1, code)
+		assert.Contains(t, out.String(), "PASS  feed binance")
+		assert.Contains(t, out.String(), "FAIL  feed kraken")
+		assert.Contains(t, out.String(), "1 of 4 checks failed")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		cfg := testSelfTestConfig()
+		cfg.Server.Port = 0
+
+		var out bytes.Buffer
+		code := runSelfTestChecks(context.Background(), selfTestChecks(cfg, fakeDialer("stream.binance.com:9443", "ws.kraken.com:443")), 2*time.Second, &out)
+
+		assert.Equal(t, 1, code)
+		assert.Contains(t, out.String(), "FAIL  config")
+	})
+
+	t.Run("check timeout", func(t *testing.T) {
+		checks := []selfTestCheck{{
+			name: "slow",
+			run: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}}
+
+		var out bytes.Buffer
+		code := runSelfTestChecks(context.Background(), checks, 20*time.Millisecond, &out)
+
+		assert.Equal(t, 1, code)
+		assert.True(t, strings.HasPrefix(out.String(), "FAIL  slow"))
+	})
+}
+
+// TestRunSelfTestConfigFile tests the subcommand entry point against config files
+func TestRunSelfTestConfigFile(t *testing.T) {
+	var out bytes.Buffer
+	code := runSelfTest([]string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}, &out)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, out.String(), "FAIL  config")
+
+	// A config with no feeds only needs the config and paper checks to pass
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("server:\n  host: localhost\n  port: 8080\n"), 0o644))
+
+	out.Reset()
+	code = runSelfTest([]string{"-config", path, "-timeout", "2s"}, &out)
+	assert.Equal(t, 0, code, out.String())
+	assert.Contains(t, out.String(), "All 2 checks passed")
+}
+
+// TestFeedAddress tests URL to dial address resolution
+func TestFeedAddress(t *testing.T) {
+	tests := map[string]string{
+		"wss://ws.kraken.com":                               "ws.kraken.com:443",
+		"wss://stream.binance.com:9443/ws":                  "stream.binance.com:9443",
+		"https://query1.finance.yahoo.com/v7/finance/quote": "query1.finance.yahoo.com:443",
+		"ws://localhost/feed":                               "localhost:80",
+	}
+
+	for raw, want := range tests {
+		got, err := feedAddress(raw)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, raw)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+        "context"
+        "fmt"
+        "log"
+        "sync"
+        "time"
+)
+
+// shutdownStage groups components that can stop together. Stages stop in order,
+// so nothing is stopped while a component in an earlier stage still depends on it.
+type shutdownStage int
+
+const (
+        // stageProducers stops signal producers so no new work is generated
+        stageProducers shutdownStage = iota
+        // stageConsumers drains components that act on produced work
+        stageConsumers
+        // stageInfrastructure closes feeds, servers and other shared infrastructure
+        stageInfrastructure
+)
+
+func (s shutdownStage) String() string {
+        switch s {
+        case stageProducers:
+                return "producers"
+        case stageConsumers:
+                return "consumers"
+        case stageInfrastructure:
+                return "infrastructure"
+        default:
+                return fmt.Sprintf("stage %d", int(s))
+        }
+}
+
+// shutdownStep stops a single component
+type shutdownStep struct {
+        name  string
+        stage shutdownStage
+        stop  func(ctx context.Context) error
+}
+
+// shutdownSequence stops registered components stage by stage, each with a timeout
+type shutdownSequence struct {
+        mu          sync.Mutex
+        steps       []shutdownStep
+        stepTimeout time.Duration
+}
+
+// newShutdownSequence creates a shutdown sequence with a per-component timeout
+func newShutdownSequence(stepTimeout time.Duration) *shutdownSequence {
+        return &shutdownSequence{stepTimeout: stepTimeout}
+}
+
+// Add registers a component to stop during the given stage. Components within a
+// stage stop in registration order.
+func (s *shutdownSequence) Add(stage shutdownStage, name string, stop func(ctx context.Context) error) {
+        s.mu.Lock()
+        defer s.mu.Unlock()
+        s.steps = append(s.steps, shutdownStep{name: name, stage: stage, stop: stop})
+}
+
+// Run stops every component, returning the errors from components that failed or
+// timed out. A failing component does not prevent later ones from stopping.
+func (s *shutdownSequence) Run(ctx context.Context) []error {
+        s.mu.Lock()
+        steps := make([]shutdownStep, len(s.steps))
+        copy(steps, s.steps)
+        s.mu.Unlock()
+
+        var errs []error
+        for stage := stageProducers; stage <= stageInfrastructure; stage++ {
+                for _, step := range steps {
+                        if step.stage != stage {
+                                continue
+                        }
+                        if err := s.runStep(ctx, step); err != nil {
+                                log.Printf("Shutdown of %s (%s) failed: %v", step.name, stage, err)
+                                errs = append(errs, fmt.Errorf("%s: %w", step.name, err))
+                        }
+                }
+        }
+
+        return errs
+}
+
+// runStep stops a component, giving up once the step timeout or overall deadline passes
+func (s *shutdownSequence) runStep(ctx context.Context, step shutdownStep) error {
+        stepCtx := ctx
+        if s.stepTimeout > 0 {
+                var cancel context.CancelFunc
+                stepCtx, cancel = context.WithTimeout(ctx, s.stepTimeout)
+                defer cancel()
+        }
+
+        done := make(chan error, 1)
+        go func() {
+                done <- step.stop(stepCtx)
+        }()
+
+        select {
+        case err := <-done:
+                return err
+        case <-stepCtx.Done():
+                return fmt.Errorf("timed out: %w", stepCtx.Err())
+        }
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestShutdownOrder tests that producers stop before consumers, and consumers before infrastructure
+func TestShutdownOrder(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	// Register out of order to show stages, not registration, drive the sequence
+	shutdown := newShutdownSequence(time.Second)
+	shutdown.Add(stageInfrastructure, "feeds", record("feeds"))
+	shutdown.Add(stageConsumers, "order manager", record("order manager"))
+	shutdown.Add(stageProducers, "strategy engine", record("strategy engine"))
+	shutdown.Add(stageInfrastructure, "http server", record("http server"))
+	shutdown.Add(stageConsumers, "risk manager", record("risk manager"))
+
+	errs := shutdown.Run(context.Background())
+	require.Empty(t, errs)
+	assert.Equal(t, []string{"strategy engine", "order manager", "risk manager", "feeds", "http server"}, order)
+}
+
+// TestShutdownDeadline tests that hung or failing components do not block the rest of the shutdown
+func TestShutdownDeadline(t *testing.T) {
+	var stopped []string
+	shutdown := newShutdownSequence(50 * time.Millisecond)
+
+	shutdown.Add(stageProducers, "hung strategy", func(ctx context.Context) error {
+		select {}
+	})
+	shutdown.Add(stageConsumers, "failing manager", func(ctx context.Context) error {
+		return errors.New("boom")
+	})
+	shutdown.Add(stageInfrastructure, "feeds", func(ctx context.Context) error {
+		stopped = append(stopped, "feeds")
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	errs := shutdown.Run(ctx)
+	elapsed := time.Since(start)
+
+	require.Len(t, errs, 2)
+	assert.ErrorIs(t, errs[0], context.DeadlineExceeded)
+	assert.Contains(t, errs[0].Error(), "hung strategy")
+	assert.Contains(t, errs[1].Error(), "failing manager")
+	assert.Equal(t, []string{"feeds"}, stopped)
+	assert.Less(t, elapsed, 500*time.Millisecond)
+}
+
+// TestShutdownOverallDeadline tests that the overall deadline bounds the whole sequence
+func TestShutdownOverallDeadline(t *testing.T) {
+	shutdown := newShutdownSequence(time.Minute)
+	for _, stage := range []shutdownStage{stageProducers, stageConsumers, stageInfrastructure} {
+		shutdown.Add(stage, stage.String(), func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	errs := shutdown.Run(ctx)
+
+	assert.Len(t, errs, 3)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
@@ -2,9 +2,11 @@ package alerts
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -417,6 +419,42 @@ func TestChannelRegistration(t *testing.T) {
 	}
 }
 
+// TestSendTestAlert tests that a test alert is delivered only to the named
+// channel, is not stored, and reports the channel's failure
+func TestSendTestAlert(t *testing.T) {
+	am := NewAlertManager(nil)
+	slack := NewTestConsoleChannel("slack")
+	email := NewTestConsoleChannel("email")
+	am.RegisterChannel(slack)
+	am.RegisterChannel(email)
+
+	alert, err := am.SendTestAlert("slack", "", "")
+	if err != nil {
+		t.Fatalf("SendTestAlert failed: %v", err)
+	}
+	if alert.Type != AlertTypeTest || alert.Severity != SeverityLow || !strings.Contains(alert.Message, "slack") {
+		t.Errorf("Unexpected test alert %+v", alert)
+	}
+	if sent := slack.GetAlerts(); len(sent) != 1 || sent[0].ID != alert.ID {
+		t.Errorf("Expected the test alert on slack, got %v", sent)
+	}
+	if len(email.GetAlerts()) != 0 {
+		t.Error("Expected no alert on email")
+	}
+	if stored, _ := am.GetAlerts(nil); len(stored) != 0 {
+		t.Errorf("Expected the test alert not to be stored, got %d alerts", len(stored))
+	}
+
+	email.failNext = true
+	if _, err := am.SendTestAlert("email", "ping", SeverityCritical); err == nil || !strings.Contains(err.Error(), "simulated send failure") {
+		t.Errorf("Expected the delivery failure, got %v", err)
+	}
+
+	if _, err := am.SendTestAlert("pager", "", ""); !errors.Is(err, ErrChannelNotFound) {
+		t.Errorf("Expected ErrChannelNotFound, got %v", err)
+	}
+}
+
 func TestAlertFiltering(t *testing.T) {
 	logger, _ := logger.New(&logger.Config{
 		Level:  logger.DEBUG,
@@ -840,4 +878,982 @@ func TestAlertRuleValidation(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestRuleCooldownPersistence(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "alert_rules.json")
+	config := &AlertConfig{
+		Enabled:   true,
+		StateFile: stateFile,
+		Rules: []map[string]interface{}{
+			{
+				"id":       "btc-spike",
+				"name":     "BTC spike",
+				"type":     "price",
+				"severity": "high",
+				"cooldown": "1h",
+				"enabled":  true,
+			},
+		},
+	}
+
+	first, err := SetupAlertManager(config, nil)
+	if err != nil {
+		t.Fatalf("SetupAlertManager failed: %v", err)
+	}
+	rule, err := first.GetRule("btc-spike")
+	if err != nil {
+		t.Fatalf("GetRule failed: %v", err)
+	}
+	if err := first.TriggerAlert(rule, nil); err != nil {
+		t.Fatalf("TriggerAlert failed: %v", err)
+	}
+	if active, _ := first.GetActiveAlerts(); len(active) != 1 {
+		t.Fatalf("Expected 1 alert before restart, got %d", len(active))
+	}
+	triggeredAt := rule.LastTriggered
+
+	if _, err := os.Stat(stateFile); err != nil {
+		t.Fatalf("Expected rule state to be persisted after trigger: %v", err)
+	}
+
+	// Simulate a restart from the same configuration
+	second, err := SetupAlertManager(config, nil)
+	if err != nil {
+		t.Fatalf("SetupAlertManager after restart failed: %v", err)
+	}
+	restored, err := second.GetRule("btc-spike")
+	if err != nil {
+		t.Fatalf("GetRule after restart failed: %v", err)
+	}
+	if !restored.LastTriggered.Equal(triggeredAt) {
+		t.Errorf("Expected last triggered %v, got %v", triggeredAt, restored.LastTriggered)
+	}
+	if restored.TriggerCount != 1 {
+		t.Errorf("Expected trigger count 1, got %d", restored.TriggerCount)
+	}
+
+	if err := second.TriggerAlert(restored, nil); err != nil {
+		t.Fatalf("TriggerAlert after restart failed: %v", err)
+	}
+	if active, _ := second.GetActiveAlerts(); len(active) != 0 {
+		t.Errorf("Expected rule to stay in cooldown after restart, got %d alerts", len(active))
+	}
+
+	// Once the cooldown has elapsed the rule fires again
+	restored.LastTriggered = time.Now().Add(-2 * time.Hour)
+	if err := second.TriggerAlert(restored, nil); err != nil {
+		t.Fatalf("TriggerAlert after cooldown failed: %v", err)
+	}
+	if active, _ := second.GetActiveAlerts(); len(active) != 1 {
+		t.Errorf("Expected rule to fire after cooldown, got %d alerts", len(active))
+	}
+}
+
+func TestLoadRulesSkipsUnregisteredRules(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "alert_rules.json")
+	rules := []map[string]interface{}{
+		{"id": "btc-spike", "name": "BTC spike", "type": "price", "severity": "high", "cooldown": "1h", "enabled": true},
+		{"name": "ETH drop", "type": "price", "severity": "high", "cooldown": "1h", "enabled": true},
+	}
+	config := &AlertConfig{Enabled: true, StateFile: stateFile, Rules: rules}
+
+	first, err := SetupAlertManager(config, nil)
+	if err != nil {
+		t.Fatalf("SetupAlertManager failed: %v", err)
+	}
+	for _, rule := range first.GetRules() {
+		if err := first.TriggerAlert(rule, nil); err != nil {
+			t.Fatalf("TriggerAlert failed: %v", err)
+		}
+	}
+
+	// The rule without an ID gets a new one on restart; its old copy must not come back
+	second, err := SetupAlertManager(config, nil)
+	if err != nil {
+		t.Fatalf("SetupAlertManager after restart failed: %v", err)
+	}
+	if got := len(second.GetRules()); got != 2 {
+		t.Errorf("Expected 2 rules after restart, got %d", got)
+	}
+
+	// A rule removed from the configuration stays removed
+	config.Rules = rules[1:]
+	third, err := SetupAlertManager(config, nil)
+	if err != nil {
+		t.Fatalf("SetupAlertManager after removing a rule failed: %v", err)
+	}
+	if _, err := third.GetRule("btc-spike"); err == nil {
+		t.Error("Expected removed rule not to be restored")
+	}
+	if got := len(third.GetRules()); got != 1 {
+		t.Errorf("Expected 1 rule after removing one, got %d", got)
+	}
+}
+
+func TestLoadRulesMissingFile(t *testing.T) {
+	am := NewAlertManager(nil)
+	if err := am.LoadRules(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Errorf("Expected missing state file to be ignored, got %v", err)
+	}
+}
+
+func TestPercentPriceCondition(t *testing.T) {
+	mas := &MarketEventAlertSystem{}
+
+	tests := []struct {
+		name     string
+		operator string
+		previous float64
+		current  float64
+		want     bool
+	}{
+		{name: "rise above threshold", operator: "above", previous: 100, current: 106, want: true},
+		{name: "rise below threshold", operator: "above", previous: 100, current: 104, want: false},
+		{name: "drop below negative threshold", operator: "below", previous: 100, current: 94, want: true},
+		{name: "no previous price", operator: "above", previous: 0, current: 106, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threshold := 5.0
+			if tt.operator == "below" {
+				threshold = -5.0
+			}
+			alert := &PriceAlert{
+				PreviousPrice: tt.previous,
+				CurrentPrice:  tt.current,
+				Threshold:     threshold,
+				Condition:     MarketCondition{Operator: tt.operator, Percent: true},
+			}
+			if got := mas.evaluatePriceCondition(alert); got != tt.want {
+				t.Errorf("evaluatePriceCondition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// mixedLogicGroup is (price > 100 OR volume > 1000) AND severity = high
+func mixedLogicGroup(priceOp, volumeOp, equalsOp string) ConditionGroup {
+	return AllOf(
+		[]AlertCondition{{Field: "severity", Operator: equalsOp, Value: "high"}},
+		AnyOf([]AlertCondition{
+			{Field: "price", Operator: priceOp, Value: 100.0},
+			{Field: "volume", Operator: volumeOp, Value: 1000.0},
+		}),
+	)
+}
+
+var mixedLogicCases = []struct {
+	name     string
+	price    float64
+	volume   float64
+	severity string
+	want     bool
+}{
+	{name: "price leg", price: 150, volume: 10, severity: "high", want: true},
+	{name: "volume leg", price: 50, volume: 5000, severity: "high", want: true},
+	{name: "both legs", price: 150, volume: 5000, severity: "high", want: true},
+	{name: "neither leg", price: 50, volume: 10, severity: "high", want: false},
+	{name: "wrong severity", price: 150, volume: 5000, severity: "low", want: false},
+}
+
+func TestConditionGroupManager(t *testing.T) {
+	am := NewAlertManager(nil)
+	group := mixedLogicGroup("gt", "gt", "eq")
+
+	for _, tt := range mixedLogicCases {
+		t.Run(tt.name, func(t *testing.T) {
+			data := map[string]interface{}{"price": tt.price, "volume": tt.volume, "severity": tt.severity}
+			if got := am.evaluateConditionGroup(&group, data); got != tt.want {
+				t.Errorf("evaluateConditionGroup() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Triggering ANDs the rule's plain conditions with its group
+	rule := NewAlertRuleBuilder().
+		Name("Mixed").
+		Type(AlertTypePrice).
+		Severity(SeverityHigh).
+		Message("price {{price}} volume {{volume}}").
+		Condition("symbol", "eq", "BTC/USD").
+		Group(group).
+		Build()
+	rule.Enabled = true
+	if err := am.AddRule(rule); err != nil {
+		t.Fatalf("AddRule failed: %v", err)
+	}
+
+	count := func() int {
+		alerts, err := am.GetAlerts(nil)
+		if err != nil {
+			t.Fatalf("GetAlerts failed: %v", err)
+		}
+		return len(alerts)
+	}
+	trigger := func(symbol string, price float64) int {
+		before := count()
+		rule.LastTriggered = time.Time{}
+		data := map[string]interface{}{"symbol": symbol, "price": price, "volume": 10.0, "severity": "high"}
+		if err := am.TriggerAlert(rule, data); err != nil {
+			t.Fatalf("TriggerAlert failed: %v", err)
+		}
+		return count() - before
+	}
+	if got := trigger("BTC/USD", 150); got != 1 {
+		t.Errorf("matching data raised %d alerts, want 1", got)
+	}
+	if got := trigger("ETH/USD", 150); got != 0 {
+		t.Errorf("data failing a plain condition raised %d alerts, want 0", got)
+	}
+	if got := trigger("BTC/USD", 50); got != 0 {
+		t.Errorf("data failing the group raised %d alerts, want 0", got)
+	}
+}
+
+func TestConditionGroupEngine(t *testing.T) {
+	ae := &AlertEngine{}
+	rule := &AlertRule{EventType: "market"}
+	group := mixedLogicGroup("greater_than", "greater_than", "equals")
+	rule.ConditionGroup = &group
+
+	for _, tt := range mixedLogicCases {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &AlertEvent{
+				Type:     "market",
+				Severity: AlertSeverity(tt.severity),
+				Metadata: map[string]interface{}{"price": tt.price, "volume": tt.volume},
+			}
+			if got := ae.evaluateRule(rule, event); got != tt.want {
+				t.Errorf("evaluateRule() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionGroupValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		group   ConditionGroup
+		wantErr bool
+	}{
+		{name: "valid nested", group: mixedLogicGroup("gt", "gt", "eq")},
+		{name: "default logic", group: ConditionGroup{Conditions: []AlertCondition{{Field: "price", Operator: "gt", Value: 1.0}}}},
+		{name: "unknown logic", group: ConditionGroup{Logic: "xor", Conditions: []AlertCondition{{Field: "price"}}}, wantErr: true},
+		{name: "empty", group: ConditionGroup{Logic: LogicOr}, wantErr: true},
+		{name: "empty nested", group: AllOf([]AlertCondition{{Field: "price"}}, AnyOf(nil)), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.group.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	am := NewAlertManager(nil)
+	rule := &AlertRule{Name: "bad", ConditionGroup: &ConditionGroup{Logic: "xor"}}
+	if err := am.AddRule(rule); err == nil {
+		t.Error("AddRule accepted an invalid condition group")
+	}
+}
+
+func TestEngineQueueDepths(t *testing.T) {
+	logger, _ := logger.New(&logger.Config{
+		Level:  logger.DEBUG,
+		Output: "console",
+	})
+
+	// Without event workers nothing drains the event queue
+	config := DefaultAlertConfig()
+	config.MaxWorkers = 0
+	config.QueueSize = 10
+	engine := NewAlertEngine(config, logger)
+	defer engine.Close()
+
+	for i := 0; i < 4; i++ {
+		if err := engine.ProcessEvent(&AlertEvent{Type: "price", Timestamp: time.Now()}); err != nil {
+			t.Fatalf("Failed to enqueue event: %v", err)
+		}
+	}
+
+	var found bool
+	for _, depth := range engine.QueueDepths() {
+		if depth.Queue != "alert_events" {
+			continue
+		}
+		found = true
+		if depth.Depth != 4 {
+			t.Errorf("Expected 4 queued events, got %d", depth.Depth)
+		}
+		if depth.Capacity != 10 {
+			t.Errorf("Expected capacity 10, got %d", depth.Capacity)
+		}
+	}
+	if !found {
+		t.Error("Expected alert_events queue depth")
+	}
+}
+
+func TestMarketRuleSeverity(t *testing.T) {
+	logger, _ := logger.New(&logger.Config{
+		Level:  logger.DEBUG,
+		Output: "console",
+	})
+
+	// Without event workers emitted events stay on the queue to inspect
+	config := DefaultAlertConfig()
+	config.MaxWorkers = 0
+	engine := NewAlertEngine(config, logger)
+	defer engine.Close()
+	mas := NewMarketEventAlertSystem(engine, logger)
+	defer mas.Close()
+
+	next := func() *AlertEvent {
+		select {
+		case event := <-engine.eventQueue:
+			return event
+		default:
+			t.Fatal("Expected an alert event")
+			return nil
+		}
+	}
+
+	tests := []struct {
+		name     string
+		rule     *MarketAlertRule
+		data     map[string]interface{}
+		severity AlertSeverity
+	}{
+		{
+			name:     "price rule severity",
+			rule:     &MarketAlertRule{Symbol: "BTC/USD", Exchange: "binance", Type: MarketAlertPrice, Threshold: 60000, Severity: SeverityCritical},
+			data:     map[string]interface{}{"price": 65000.0},
+			severity: SeverityCritical,
+		},
+		{
+			name:     "price default severity",
+			rule:     &MarketAlertRule{Symbol: "ETH/USD", Exchange: "binance", Type: MarketAlertPrice, Threshold: 3000},
+			data:     map[string]interface{}{"price": 3500.0},
+			severity: SeverityMedium,
+		},
+		{
+			name:     "volatility rule severity",
+			rule:     &MarketAlertRule{Symbol: "SOL/USD", Exchange: "binance", Type: MarketAlertVolatility, Threshold: 0.5, Severity: SeverityLow},
+			data:     map[string]interface{}{"volatility": 0.8},
+			severity: SeverityLow,
+		},
+		{
+			name:     "volatility default severity",
+			rule:     &MarketAlertRule{Symbol: "ADA/USD", Exchange: "binance", Type: MarketAlertVolatility, Threshold: 0.5},
+			data:     map[string]interface{}{"volatility": 0.8},
+			severity: SeverityHigh,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.Enabled = true
+			tt.rule.Condition = MarketCondition{Operator: "above"}
+			if err := mas.AddMarketRule(tt.rule); err != nil {
+				t.Fatalf("Failed to add rule: %v", err)
+			}
+
+			mas.ProcessMarketData(tt.rule.Symbol, tt.rule.Exchange, tt.data)
+			if event := next(); event.Severity != tt.severity {
+				t.Errorf("Expected severity %s, got %s", tt.severity, event.Severity)
+			}
+		})
+	}
+
+	t.Run("arbitrage rule severity", func(t *testing.T) {
+		rule := &MarketAlertRule{Symbol: "XRP/USD", Exchange: "any", Type: MarketAlertArbitrage, Threshold: 0.5,
+			Severity: SeverityCritical, Enabled: true, Condition: MarketCondition{Operator: "above"}}
+		if err := mas.AddMarketRule(rule); err != nil {
+			t.Fatalf("Failed to add rule: %v", err)
+		}
+
+		mas.ProcessArbitrageData([]map[string]interface{}{{"symbol": "XRP/USD", "profit_percent": 1.2}})
+		if event := next(); event.Severity != SeverityCritical {
+			t.Errorf("Expected severity %s, got %s", SeverityCritical, event.Severity)
+		}
+	})
+
+	t.Run("unknown severity", func(t *testing.T) {
+		rule := &MarketAlertRule{Symbol: "BTC/USD", Exchange: "binance", Type: MarketAlertPrice, Threshold: 1,
+			Severity: "urgent", Condition: MarketCondition{Operator: "above"}}
+		if err := mas.AddMarketRule(rule); err == nil {
+			t.Error("Expected unknown severity to be rejected")
+		}
+	})
+}
+
+func TestMarketRuleRearm(t *testing.T) {
+	logger, _ := logger.New(&logger.Config{
+		Level:  logger.DEBUG,
+		Output: "console",
+	})
+
+	tests := []struct {
+		name     string
+		operator string
+		rearm    float64
+		prices   []float64
+		fired    []bool
+	}{
+		{
+			name:     "rearms after clearing",
+			operator: "above",
+			prices:   []float64{101, 102, 100, 101},
+			fired:    []bool{true, false, false, true},
+		},
+		{
+			// Dipping back under the threshold by less than the rearm distance does not rearm
+			name:     "hysteresis",
+			operator: "above",
+			rearm:    5,
+			prices:   []float64{101, 98, 101, 94, 101},
+			fired:    []bool{true, false, false, false, true},
+		},
+		{
+			name:     "below",
+			operator: "below",
+			rearm:    2,
+			prices:   []float64{99, 101, 99, 103, 99},
+			fired:    []bool{true, false, false, false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultAlertConfig()
+			config.MaxWorkers = 0
+			engine := NewAlertEngine(config, logger)
+			defer engine.Close()
+			mas := NewMarketEventAlertSystem(engine, logger)
+			defer mas.Close()
+
+			rule := &MarketAlertRule{Symbol: "BTC/USD", Exchange: "binance", Type: MarketAlertPrice, Threshold: 100,
+				Rearm: tt.rearm, Enabled: true, Condition: MarketCondition{Operator: tt.operator}}
+			if err := mas.AddMarketRule(rule); err != nil {
+				t.Fatalf("Failed to add rule: %v", err)
+			}
+
+			alerts := 0
+			for i, price := range tt.prices {
+				mas.ProcessMarketData("BTC/USD", "binance", map[string]interface{}{"price": price})
+				fired := len(engine.eventQueue) == alerts+1
+				if fired {
+					alerts++
+				}
+				if fired != tt.fired[i] {
+					t.Errorf("Price %v (step %d): fired = %v, want %v", price, i, fired, tt.fired[i])
+				}
+			}
+			if alerts != 2 {
+				t.Errorf("Expected 2 alerts, got %d", alerts)
+			}
+		})
+	}
+
+	t.Run("negative rearm", func(t *testing.T) {
+		mas := &MarketEventAlertSystem{}
+		rule := &MarketAlertRule{Symbol: "BTC/USD", Exchange: "binance", Type: MarketAlertPrice, Threshold: 100,
+			Rearm: -1, Condition: MarketCondition{Operator: "above"}}
+		if err := mas.validateMarketRule(rule); err == nil {
+			t.Error("Expected negative rearm to be rejected")
+		}
+	})
+}
+
+func TestQuietHoursActive(t *testing.T) {
+	config := QuietHoursConfig{
+		Enabled:  true,
+		Timezone: "UTC",
+		Windows: []QuietWindow{
+			{Start: "22:00", End: "07:00"},
+			{Start: "12:00", End: "14:00", Days: []string{"sat"}},
+		},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	// 2024-01-05 is a Friday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{name: "before night", at: at(5, 21, 59), want: false},
+		{name: "night start", at: at(5, 22, 0), want: true},
+		{name: "after midnight", at: at(6, 3, 0), want: true},
+		{name: "night end", at: at(6, 7, 0), want: false},
+		{name: "maintenance on saturday", at: at(6, 13, 0), want: true},
+		{name: "maintenance window on friday", at: at(5, 13, 0), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := config.Active(tt.at); got != tt.want {
+				t.Errorf("Active(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+
+	config.Enabled = false
+	if config.Active(at(6, 3, 0)) {
+		t.Error("Disabled quiet hours should never be active")
+	}
+
+	invalid := []QuietHoursConfig{
+		{Enabled: true, Windows: []QuietWindow{{Start: "25:00", End: "07:00"}}},
+		{Enabled: true, Windows: []QuietWindow{{Start: "22:00", End: "07:00", Days: []string{"someday"}}}},
+		{Enabled: true, Action: "defer"},
+		{Enabled: true, Timezone: "Mars/Olympus"},
+	}
+	for _, c := range invalid {
+		if err := c.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", c)
+		}
+	}
+}
+
+func TestQuietHoursDelivery(t *testing.T) {
+	logger, _ := logger.New(&logger.Config{
+		Level:  logger.DEBUG,
+		Output: "console",
+	})
+
+	// A window around the current time, and one well clear of it
+	now := time.Now().UTC()
+	quiet := []QuietWindow{{Start: now.Add(-time.Hour).Format("15:04"), End: now.Add(time.Hour).Format("15:04")}}
+	outside := []QuietWindow{{Start: now.Add(3 * time.Hour).Format("15:04"), End: now.Add(4 * time.Hour).Format("15:04")}}
+
+	newAlert := func(title string, severity AlertSeverity) *Alert {
+		return &Alert{ID: title, Title: title, Message: title + " fired", Severity: severity, Channels: []string{"ops"}, CreatedAt: time.Now()}
+	}
+
+	for _, action := range []string{QuietActionDigest, QuietActionSuppress} {
+		t.Run(action, func(t *testing.T) {
+			config := DefaultAlertConfig()
+			config.MaxWorkers = 0
+			config.QuietHours = QuietHoursConfig{Enabled: true, Timezone: "UTC", Action: action, Windows: quiet}
+			engine := NewAlertEngine(config, logger)
+			defer engine.Close()
+			channel := NewTestConsoleChannel("ops")
+			engine.RegisterChannel("ops", channel)
+
+			engine.processAlert(newAlert("spread", SeverityLow))
+			engine.processAlert(newAlert("volume", SeverityHigh))
+			if got := len(channel.GetAlerts()); got != 0 {
+				t.Fatalf("Expected sub-critical alerts to be held during quiet hours, got %d delivered", got)
+			}
+
+			engine.processAlert(newAlert("outage", SeverityCritical))
+			delivered := channel.GetAlerts()
+			if len(delivered) != 1 || delivered[0].Title != "outage" {
+				t.Fatalf("Expected the critical alert to be delivered, got %v", delivered)
+			}
+			if got := engine.GetMetrics().SuppressedAlerts; got != 2 {
+				t.Errorf("Expected 2 suppressed alerts, got %d", got)
+			}
+
+			// Quiet hours end: the next alert releases the digest ahead of itself
+			engine.config.QuietHours.Windows = outside
+			engine.processAlert(newAlert("latency", SeverityLow))
+			delivered = channel.GetAlerts()[1:]
+
+			if action == QuietActionSuppress {
+				if len(delivered) != 1 || delivered[0].Title != "latency" {
+					t.Fatalf("Expected only the new alert after suppression, got %v", delivered)
+				}
+				return
+			}
+
+			if len(delivered) != 2 {
+				t.Fatalf("Expected the digest and the new alert, got %d alerts", len(delivered))
+			}
+			digest := delivered[0]
+			if digest.Title != "Quiet hours digest: 2 alerts" {
+				t.Errorf("Unexpected digest title %q", digest.Title)
+			}
+			if digest.Severity != SeverityHigh {
+				t.Errorf("Expected the digest to take the highest held severity, got %s", digest.Severity)
+			}
+			for _, held := range []string{"spread fired", "volume fired"} {
+				if !strings.Contains(digest.Message, held) {
+					t.Errorf("Expected digest to include %q, got %q", held, digest.Message)
+				}
+			}
+			if delivered[1].Title != "latency" {
+				t.Errorf("Expected the new alert after the digest, got %q", delivered[1].Title)
+			}
+
+			// The digest is only sent once
+			engine.releaseQuietDigest(time.Now())
+			if got := len(channel.GetAlerts()); got != 3 {
+				t.Errorf("Expected no further alerts, got %d", got)
+			}
+		})
+	}
+}
+
+func TestChannelBreaker(t *testing.T) {
+	logger, _ := logger.New(&logger.Config{
+		Level:  logger.DEBUG,
+		Output: "console",
+	})
+
+	config := DefaultAlertConfig()
+	config.MaxWorkers = 0
+	config.ChannelBreaker = ChannelBreakerConfig{Enabled: true, FailureThreshold: 3, Cooldown: 50 * time.Millisecond}
+	engine := NewAlertEngine(config, logger)
+	defer engine.Close()
+	channel := NewTestConsoleChannel("slack")
+	engine.RegisterChannel("slack", channel)
+
+	alert := &Alert{ID: "outage", Title: "outage", Message: "outage fired", Severity: SeverityHigh, Channels: []string{"slack"}, CreatedAt: time.Now()}
+	state := func() string {
+		return engine.ChannelBreakers()["slack"].State
+	}
+
+	// Consecutive failures open the breaker once the threshold is reached
+	for i := 0; i < 3; i++ {
+		channel.SetFailNext(true)
+		engine.deliver(alert)
+	}
+	if got := state(); got != BreakerOpen {
+		t.Fatalf("Expected breaker to open after 3 failures, got %s", got)
+	}
+	if got := engine.GetMetrics().FailedAlerts; got != 3 {
+		t.Errorf("Expected 3 failed alerts, got %d", got)
+	}
+
+	// The open channel is skipped
+	engine.deliver(alert)
+	if got := len(channel.GetAlerts()); got != 0 {
+		t.Errorf("Expected no alerts sent through an open breaker, got %d", got)
+	}
+	if got := engine.GetMetrics().SkippedAlerts; got != 1 {
+		t.Errorf("Expected 1 skipped alert, got %d", got)
+	}
+
+	// After the cooldown a failed test send reopens the breaker
+	time.Sleep(60 * time.Millisecond)
+	channel.SetFailNext(true)
+	engine.deliver(alert)
+	if got := state(); got != BreakerOpen {
+		t.Fatalf("Expected breaker to reopen after a failed test send, got %s", got)
+	}
+	engine.deliver(alert)
+	if got := engine.GetMetrics().SkippedAlerts; got != 2 {
+		t.Errorf("Expected 2 skipped alerts, got %d", got)
+	}
+
+	// A successful test send closes it again
+	time.Sleep(60 * time.Millisecond)
+	engine.deliver(alert)
+	if got := state(); got != BreakerClosed {
+		t.Fatalf("Expected breaker to close after the channel recovered, got %s", got)
+	}
+	if got := engine.ChannelBreakers()["slack"].Failures; got != 0 {
+		t.Errorf("Expected failures to reset on recovery, got %d", got)
+	}
+	engine.deliver(alert)
+	if got := len(channel.GetAlerts()); got != 2 {
+		t.Errorf("Expected 2 alerts delivered after recovery, got %d", got)
+	}
+
+	invalid := []ChannelBreakerConfig{
+		{Enabled: true, FailureThreshold: 0, Cooldown: time.Minute},
+		{Enabled: true, FailureThreshold: 3, Cooldown: 0},
+	}
+	for _, c := range invalid {
+		if err := c.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", c)
+		}
+	}
+}
+
+func TestAlertBatching(t *testing.T) {
+	logger, _ := logger.New(&logger.Config{
+		Level:  logger.DEBUG,
+		Output: "console",
+	})
+
+	config := DefaultAlertConfig()
+	config.MaxWorkers = 0
+	config.Batching = BatchingConfig{Enabled: true, Windows: map[AlertSeverity]time.Duration{SeverityLow: 50 * time.Millisecond}}
+	engine := NewAlertEngine(config, logger)
+	defer engine.Close()
+	ops := NewTestConsoleChannel("ops")
+	pager := NewTestConsoleChannel("pager")
+	engine.RegisterChannel("ops", ops)
+	engine.RegisterChannel("pager", pager)
+
+	newAlert := func(title string, severity AlertSeverity, channels ...string) *Alert {
+		return &Alert{ID: title, Title: title, Message: title + " fired", Severity: severity, Channels: channels, CreatedAt: time.Now()}
+	}
+
+	for i := 0; i < 30; i++ {
+		engine.processAlert(newAlert("spread", SeverityLow, "ops"))
+	}
+	for i := 0; i < 10; i++ {
+		engine.processAlert(newAlert("volume", SeverityLow, "ops", "pager"))
+	}
+	engine.processAlert(newAlert("latency", SeverityHigh, "ops"))
+
+	delivered := ops.GetAlerts()
+	if len(delivered) != 1 || delivered[0].Title != "latency" {
+		t.Fatalf("Expected only the unbatched alert before the window ends, got %v", delivered)
+	}
+	if got := engine.GetMetrics().BatchedAlerts; got != 40 {
+		t.Errorf("Expected 40 batched alerts, got %d", got)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for (len(ops.GetAlerts()) < 2 || len(pager.GetAlerts()) < 1) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	delivered = ops.GetAlerts()[1:]
+	if len(delivered) != 1 {
+		t.Fatalf("Expected a single digest on ops, got %d alerts", len(delivered))
+	}
+	digest := delivered[0]
+	if digest.Title != "Alert digest (low): 40 alerts in 50ms" {
+		t.Errorf("Unexpected digest title %q", digest.Title)
+	}
+	if digest.Severity != SeverityLow {
+		t.Errorf("Expected a low severity digest, got %s", digest.Severity)
+	}
+	if digest.Message != "- spread: 30\n- volume: 10" {
+		t.Errorf("Unexpected digest summary %q", digest.Message)
+	}
+	if digest.Metadata["alert_count"] != 40 {
+		t.Errorf("Expected alert_count 40, got %v", digest.Metadata["alert_count"])
+	}
+
+	paged := pager.GetAlerts()
+	if len(paged) != 1 || paged[0].Title != "Alert digest (low): 10 alerts in 50ms" || paged[0].Message != "- volume: 10" {
+		t.Fatalf("Expected a single digest of the pager's alerts, got %v", paged)
+	}
+
+	// The window closed with the digest, so the next alert opens a new batch
+	engine.processAlert(newAlert("spread", SeverityLow, "ops"))
+	engine.flushAllBatches(time.Now())
+	delivered = ops.GetAlerts()
+	if len(delivered) != 3 || delivered[2].Title != "Alert digest (low): 1 alerts in 50ms" {
+		t.Errorf("Expected a digest of the new batch, got %v", delivered)
+	}
+}
+
+func TestBatchingConfigValidate(t *testing.T) {
+	valid := []BatchingConfig{
+		{},
+		DefaultBatchingConfig(),
+		{Enabled: true, Windows: map[AlertSeverity]time.Duration{SeverityLow: time.Minute, SeverityMedium: 5 * time.Minute}},
+	}
+	for _, c := range valid {
+		if err := c.Validate(); err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", c, err)
+		}
+	}
+
+	invalid := []BatchingConfig{
+		{Enabled: true, Windows: map[AlertSeverity]time.Duration{SeverityCritical: time.Minute}},
+		{Enabled: true, Windows: map[AlertSeverity]time.Duration{"urgent": time.Minute}},
+		{Enabled: true, Windows: map[AlertSeverity]time.Duration{SeverityLow: -time.Second}},
+	}
+	for _, c := range invalid {
+		if err := c.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", c)
+		}
+	}
+}
+
+func TestManagerDeliversThroughEngine(t *testing.T) {
+	logger, _ := logger.New(&logger.Config{
+		Level:  logger.DEBUG,
+		Output: "console",
+	})
+
+	engine := NewAlertEngine(DefaultAlertConfig(), logger)
+	defer engine.Close()
+	am := NewAlertManager(logger)
+	ops := NewTestConsoleChannel("ops")
+	am.RegisterChannel(ops)
+	am.SetEngine(engine)
+	pager := NewTestConsoleChannel("pager")
+	am.RegisterChannel(pager)
+
+	rule := &AlertRule{ID: "spread", Name: "Spread", Type: AlertTypePrice, Severity: SeverityMedium, Enabled: true}
+	if err := am.TriggerAlert(rule, map[string]interface{}{"price": 101.0}); err != nil {
+		t.Fatalf("TriggerAlert failed: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for (len(ops.GetAlerts()) == 0 || len(pager.GetAlerts()) == 0) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(ops.GetAlerts()) != 1 || len(pager.GetAlerts()) != 1 {
+		t.Fatalf("Expected the alert on both channels, got %d and %d", len(ops.GetAlerts()), len(pager.GetAlerts()))
+	}
+
+	metrics := engine.GetMetrics()
+	if metrics.TotalAlerts != 1 || metrics.ProcessedAlerts != 2 {
+		t.Errorf("Expected 1 alert processed on 2 channels, got %d and %d", metrics.TotalAlerts, metrics.ProcessedAlerts)
+	}
+	if metrics.AlertsBySeverity[SeverityMedium] != 1 || metrics.AlertsByChannel["pager"] != 1 {
+		t.Errorf("Expected the alert counted by severity and channel, got %v and %v", metrics.AlertsBySeverity, metrics.AlertsByChannel)
+	}
+}
+
+func TestManagerQueueDepths(t *testing.T) {
+	logger, _ := logger.New(&logger.Config{
+		Level:  logger.DEBUG,
+		Output: "console",
+	})
+
+	// Without dispatch workers the engine's queue holds the manager's alerts
+	config := DefaultAlertConfig()
+	config.MaxWorkers = 0
+	engine := NewAlertEngine(config, logger)
+	engine.cancel()
+	engine.wg.Wait()
+	defer engine.Close()
+	am := NewAlertManager(logger)
+	am.RegisterChannel(NewTestConsoleChannel("ops"))
+	am.SetEngine(engine)
+
+	rule := &AlertRule{ID: "spread", Name: "Spread", Type: AlertTypePrice, Severity: SeverityLow, Enabled: true}
+	for i := 0; i < 3; i++ {
+		rule.LastTriggered = time.Time{}
+		if err := am.TriggerAlert(rule, nil); err != nil {
+			t.Fatalf("TriggerAlert failed: %v", err)
+		}
+	}
+
+	depths := make(map[string]int)
+	for _, depth := range am.QueueDepths() {
+		depths[depth.Queue] = depth.Depth
+	}
+	if depths["alert_manager_events"] != 3 {
+		t.Errorf("Expected 3 queued manager events, got %d", depths["alert_manager_events"])
+	}
+	if depths["alert_dispatch"] != 3 {
+		t.Errorf("Expected 3 alerts queued for dispatch, got %d", depths["alert_dispatch"])
+	}
+}
+
+func TestManagerQuietHours(t *testing.T) {
+	logger, _ := logger.New(&logger.Config{
+		Level:  logger.DEBUG,
+		Output: "console",
+	})
+
+	now := time.Now().UTC()
+	config := DefaultAlertConfig()
+	config.QuietHours = QuietHoursConfig{
+		Enabled:  true,
+		Timezone: "UTC",
+		Windows:  []QuietWindow{{Start: now.Add(-time.Hour).Format("15:04"), End: now.Add(time.Hour).Format("15:04")}},
+	}
+	engine := NewAlertEngine(config, logger)
+	defer engine.Close()
+	am := NewAlertManager(logger)
+	ops := NewTestConsoleChannel("ops")
+	am.RegisterChannel(ops)
+	am.SetEngine(engine)
+
+	low := &AlertRule{ID: "spread", Name: "Spread", Type: AlertTypePrice, Severity: SeverityLow, Enabled: true}
+	critical := &AlertRule{ID: "outage", Name: "Outage", Type: AlertTypeSystem, Severity: SeverityCritical, Enabled: true}
+	for _, rule := range []*AlertRule{low, critical} {
+		if err := am.TriggerAlert(rule, nil); err != nil {
+			t.Fatalf("TriggerAlert failed: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for engine.GetMetrics().SuppressedAlerts+len(ops.GetAlerts()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	delivered := ops.GetAlerts()
+	if len(delivered) != 1 || delivered[0].Severity != SeverityCritical {
+		t.Fatalf("Expected only the critical alert delivered during quiet hours, got %v", delivered)
+	}
+	if got := engine.GetMetrics().SuppressedAlerts; got != 1 {
+		t.Errorf("Expected the low alert held, got %d suppressed", got)
+	}
+}
+
+// failingChannel fails every send, counting them
+type failingChannel struct {
+	name  string
+	mu    sync.Mutex
+	sends int
+}
+
+func (c *failingChannel) Send(alert *Alert) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sends++
+	return fmt.Errorf("simulated send failure")
+}
+
+func (c *failingChannel) Name() string { return c.name }
+
+func (c *failingChannel) Type() string { return "test" }
+
+func (c *failingChannel) Sends() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sends
+}
+
+func TestManagerChannelBreaker(t *testing.T) {
+	logger, _ := logger.New(&logger.Config{
+		Level:  logger.DEBUG,
+		Output: "console",
+	})
+
+	config := DefaultAlertConfig()
+	config.ChannelBreaker = ChannelBreakerConfig{Enabled: true, FailureThreshold: 2, Cooldown: time.Minute}
+	engine := NewAlertEngine(config, logger)
+	defer engine.Close()
+	am := NewAlertManager(logger)
+	slack := &failingChannel{name: "slack"}
+	am.RegisterChannel(slack)
+	am.SetEngine(engine)
+
+	rule := &AlertRule{ID: "spread", Name: "Spread", Type: AlertTypePrice, Severity: SeverityHigh, Enabled: true}
+	for i := 0; i < 4; i++ {
+		rule.LastTriggered = time.Time{}
+		if err := am.TriggerAlert(rule, nil); err != nil {
+			t.Fatalf("TriggerAlert failed: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for engine.GetMetrics().FailedAlerts+engine.GetMetrics().SkippedAlerts < 4 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for the alerts to be delivered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// The breaker opens after two failures and the channel is skipped after
+	if got := slack.Sends(); got != 2 {
+		t.Errorf("Expected 2 sends before the breaker opened, got %d", got)
+	}
+	if got := engine.ChannelBreakers()["slack"].State; got != BreakerOpen {
+		t.Errorf("Expected the slack breaker open, got %s", got)
+	}
+	if got := engine.GetMetrics().SkippedAlerts; got != 2 {
+		t.Errorf("Expected 2 skipped alerts, got %d", got)
+	}
+}
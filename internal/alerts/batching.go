@@ -0,0 +1,168 @@
+package alerts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BatchingConfig batches noisy alerts by severity. The first alert of a
+// batched severity opens a window; alerts of that severity are held until it
+// ends, and each channel is then sent one digest summarising them. Critical
+// alerts are never batched.
+type BatchingConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Windows maps a severity to the window its alerts are batched over; unlisted severities are sent at once
+	Windows map[AlertSeverity]time.Duration `json:"windows" yaml:"windows"`
+}
+
+// DefaultBatchingConfig returns default alert batching configuration
+func DefaultBatchingConfig() BatchingConfig {
+	return BatchingConfig{
+		Enabled: false,
+		Windows: map[AlertSeverity]time.Duration{SeverityLow: time.Minute},
+	}
+}
+
+// Validate checks that only known, sub-critical severities are batched
+func (c BatchingConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	for severity, window := range c.Windows {
+		if _, ok := severityRank[severity]; !ok {
+			return fmt.Errorf("unknown batching severity: %s", severity)
+		}
+		if severity == SeverityCritical {
+			return fmt.Errorf("critical alerts cannot be batched")
+		}
+		if window < 0 {
+			return fmt.Errorf("batching window for %s alerts cannot be negative", severity)
+		}
+	}
+	return nil
+}
+
+// window returns the window a severity is batched over, zero if it is not
+func (c BatchingConfig) window(severity AlertSeverity) time.Duration {
+	if !c.Enabled || severity == SeverityCritical {
+		return 0
+	}
+	return c.Windows[severity]
+}
+
+// alertBatch holds the alerts of one severity until its window ends
+type alertBatch struct {
+	opened time.Time
+	window time.Duration
+	alerts []*Alert
+	timer  *time.Timer
+}
+
+// batchAlert holds an alert for its severity's digest, reporting false if
+// the severity is not batched
+func (ae *AlertEngine) batchAlert(alert *Alert, now time.Time) bool {
+	window := ae.config.Batching.window(alert.Severity)
+	if window <= 0 {
+		return false
+	}
+
+	ae.batchMu.Lock()
+	batch, ok := ae.batches[alert.Severity]
+	if !ok {
+		severity := alert.Severity
+		batch = &alertBatch{opened: now, window: window}
+		batch.timer = time.AfterFunc(window, func() { ae.flushBatch(severity, time.Now()) })
+		ae.batches[severity] = batch
+	}
+	batch.alerts = append(batch.alerts, alert)
+	ae.batchMu.Unlock()
+
+	ae.metrics.mu.Lock()
+	ae.metrics.BatchedAlerts++
+	ae.metrics.mu.Unlock()
+	return true
+}
+
+// flushBatch sends each channel a digest of the alerts batched for it
+func (ae *AlertEngine) flushBatch(severity AlertSeverity, now time.Time) {
+	ae.batchMu.Lock()
+	batch, ok := ae.batches[severity]
+	delete(ae.batches, severity)
+	ae.batchMu.Unlock()
+	if !ok {
+		return
+	}
+	batch.timer.Stop()
+
+	for channel, alerts := range alertsByChannel(batch.alerts) {
+		ae.deliver(batchDigest(severity, batch, channel, alerts, now))
+	}
+}
+
+// flushAllBatches sends the digests of every open batch without waiting for
+// their windows to end
+func (ae *AlertEngine) flushAllBatches(now time.Time) {
+	ae.batchMu.Lock()
+	severities := make([]AlertSeverity, 0, len(ae.batches))
+	for severity := range ae.batches {
+		severities = append(severities, severity)
+	}
+	ae.batchMu.Unlock()
+
+	for _, severity := range severities {
+		ae.flushBatch(severity, now)
+	}
+}
+
+// alertsByChannel groups held alerts by each channel they were meant for
+func alertsByChannel(alerts []*Alert) map[string][]*Alert {
+	byChannel := make(map[string][]*Alert)
+	for _, alert := range alerts {
+		for _, channel := range alert.Channels {
+			byChannel[channel] = append(byChannel[channel], alert)
+		}
+	}
+	return byChannel
+}
+
+// batchDigest summarises the alerts batched for a channel in a single alert,
+// counting them by title, most frequent first
+func batchDigest(severity AlertSeverity, batch *alertBatch, channel string, alerts []*Alert, now time.Time) *Alert {
+	counts := make(map[string]int)
+	titles := make([]string, 0)
+	for _, alert := range alerts {
+		if counts[alert.Title] == 0 {
+			titles = append(titles, alert.Title)
+		}
+		counts[alert.Title]++
+	}
+	sort.SliceStable(titles, func(i, j int) bool { return counts[titles[i]] > counts[titles[j]] })
+
+	lines := make([]string, 0, len(titles))
+	for _, title := range titles {
+		lines = append(lines, fmt.Sprintf("- %s: %d", title, counts[title]))
+	}
+
+	return &Alert{
+		ID:       uuid.NewString(),
+		Type:     AlertTypeSystem,
+		Severity: severity,
+		Title:    fmt.Sprintf("Alert digest (%s): %d alerts in %s", severity, len(alerts), batch.window),
+		Message:  strings.Join(lines, "\n"),
+		Channels: []string{channel},
+		Metadata: map[string]interface{}{
+			"digest":       true,
+			"alert_count":  len(alerts),
+			"counts":       counts,
+			"window_start": batch.opened,
+			"window_end":   now,
+		},
+		CreatedAt: now,
+		Timestamp: now,
+		Status:    AlertStatusActive,
+	}
+}
@@ -0,0 +1,159 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+)
+
+// Channel circuit breaker states
+const (
+	BreakerClosed   = "closed"    // Alerts are sent
+	BreakerOpen     = "open"      // The channel is skipped until the cooldown passes
+	BreakerHalfOpen = "half_open" // A single alert is sent to test whether the channel has recovered
+)
+
+// ChannelBreakerConfig configures the per-channel circuit breakers that stop
+// the engine sending to a channel that keeps failing, such as during an
+// outage of the service behind it
+type ChannelBreakerConfig struct {
+	Enabled          bool          `json:"enabled" yaml:"enabled"`
+	FailureThreshold int           `json:"failure_threshold" yaml:"failure_threshold"` // Consecutive failures that open the breaker
+	Cooldown         time.Duration `json:"cooldown" yaml:"cooldown"`                   // How long an open breaker skips the channel
+}
+
+// DefaultChannelBreakerConfig returns default channel circuit breaker configuration
+func DefaultChannelBreakerConfig() ChannelBreakerConfig {
+	return ChannelBreakerConfig{
+		Enabled:          true,
+		FailureThreshold: 5,
+		Cooldown:         time.Minute,
+	}
+}
+
+// Validate checks the threshold and cooldown of enabled breakers
+func (c ChannelBreakerConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.FailureThreshold <= 0 {
+		return fmt.Errorf("channel breaker failure threshold must be positive")
+	}
+	if c.Cooldown <= 0 {
+		return fmt.Errorf("channel breaker cooldown must be positive")
+	}
+	return nil
+}
+
+// ChannelBreakerStatus reports the circuit breaker of one channel
+type ChannelBreakerStatus struct {
+	State    string    `json:"state"`
+	Failures int       `json:"failures"` // Consecutive failed sends
+	OpenedAt time.Time `json:"opened_at,omitempty"`
+}
+
+// channelBreaker tracks the consecutive failures of one channel
+type channelBreaker struct {
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool // A half-open test send is in flight
+}
+
+// allowChannel reports whether an alert may be sent to a channel. An open
+// breaker half-opens once its cooldown has passed and lets one alert through
+// to test the channel.
+func (ae *AlertEngine) allowChannel(name string, now time.Time) bool {
+	config := ae.config.ChannelBreaker
+	if !config.Enabled {
+		return true
+	}
+
+	ae.breakerMu.Lock()
+	defer ae.breakerMu.Unlock()
+
+	breaker := ae.breakers[name]
+	if breaker == nil {
+		return true
+	}
+
+	switch breaker.state {
+	case BreakerOpen:
+		if now.Sub(breaker.openedAt) < config.Cooldown {
+			return false
+		}
+		breaker.state = BreakerHalfOpen
+		breaker.probing = true
+		return true
+	case BreakerHalfOpen:
+		if breaker.probing {
+			return false
+		}
+		breaker.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// recordChannelResult feeds the outcome of a send into the channel's breaker.
+// A success closes it; a failure while half-open, or the threshold of
+// consecutive failures, opens it.
+func (ae *AlertEngine) recordChannelResult(name string, err error, now time.Time) {
+	config := ae.config.ChannelBreaker
+	if !config.Enabled {
+		return
+	}
+
+	ae.breakerMu.Lock()
+	breaker := ae.breakers[name]
+	if breaker == nil {
+		breaker = &channelBreaker{state: BreakerClosed}
+		ae.breakers[name] = breaker
+	}
+	breaker.probing = false
+
+	if err == nil {
+		recovered := breaker.state != BreakerClosed
+		breaker.state = BreakerClosed
+		breaker.failures = 0
+		breaker.openedAt = time.Time{}
+		ae.breakerMu.Unlock()
+
+		if recovered {
+			ae.logger.Info("alerts", fmt.Sprintf("Channel %s recovered, circuit breaker closed", name), nil)
+		}
+		return
+	}
+
+	breaker.failures++
+	opened := breaker.state == BreakerHalfOpen || (breaker.state == BreakerClosed && breaker.failures >= config.FailureThreshold)
+	if opened {
+		breaker.state = BreakerOpen
+		breaker.openedAt = now
+	}
+	failures := breaker.failures
+	ae.breakerMu.Unlock()
+
+	if opened {
+		ae.logger.Warn("alerts", fmt.Sprintf("Channel %s circuit breaker opened", name), map[string]interface{}{
+			"failures": failures,
+			"cooldown": config.Cooldown.String(),
+		})
+	}
+}
+
+// ChannelBreakers returns the circuit breaker status of each channel that has been sent to
+func (ae *AlertEngine) ChannelBreakers() map[string]ChannelBreakerStatus {
+	ae.breakerMu.Lock()
+	defer ae.breakerMu.Unlock()
+
+	statuses := make(map[string]ChannelBreakerStatus, len(ae.breakers))
+	for name, breaker := range ae.breakers {
+		statuses[name] = ChannelBreakerStatus{
+			State:    breaker.state,
+			Failures: breaker.failures,
+			OpenedAt: breaker.openedAt,
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,102 @@
+package alerts
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Condition group logic
+const (
+	LogicAnd = "and"
+	LogicOr  = "or"
+)
+
+// ConditionGroup combines conditions and nested groups with AND or OR logic,
+// e.g. (price > X OR volume > Y) AND severity = high is an AND group holding
+// the severity condition and an OR group of the price and volume conditions.
+type ConditionGroup struct {
+	Logic      string           `json:"logic"` // "and" (default) or "or"
+	Conditions []AlertCondition `json:"conditions,omitempty"`
+	Groups     []ConditionGroup `json:"groups,omitempty"`
+}
+
+// AllOf returns a group that holds when every condition and group holds
+func AllOf(conditions []AlertCondition, groups ...ConditionGroup) ConditionGroup {
+	return ConditionGroup{Logic: LogicAnd, Conditions: conditions, Groups: groups}
+}
+
+// AnyOf returns a group that holds when any condition or group holds
+func AnyOf(conditions []AlertCondition, groups ...ConditionGroup) ConditionGroup {
+	return ConditionGroup{Logic: LogicOr, Conditions: conditions, Groups: groups}
+}
+
+// Match reports whether the group holds, using match to evaluate each
+// condition. Evaluation short-circuits; a nil group always holds.
+func (g *ConditionGroup) Match(match func(condition *AlertCondition) bool) bool {
+	if g == nil {
+		return true
+	}
+
+	// An AND group fails on the first false member, an OR group succeeds on the first true one
+	or := strings.EqualFold(g.Logic, LogicOr)
+	for i := range g.Conditions {
+		if match(&g.Conditions[i]) == or {
+			return or
+		}
+	}
+	for i := range g.Groups {
+		if g.Groups[i].Match(match) == or {
+			return or
+		}
+	}
+	return !or
+}
+
+// Validate checks the group and its nested groups for unknown logic and empty groups
+func (g *ConditionGroup) Validate() error {
+	switch strings.ToLower(g.Logic) {
+	case "", LogicAnd, LogicOr:
+	default:
+		return fmt.Errorf("unknown condition group logic: %s", g.Logic)
+	}
+	if len(g.Conditions) == 0 && len(g.Groups) == 0 {
+		return fmt.Errorf("condition group must have at least one condition or group")
+	}
+	for i := range g.Groups {
+		if err := g.Groups[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// valuesEqual compares field and condition values, treating string kinds such
+// as AlertSeverity as equal to plain strings and numbers of any type by value
+func valuesEqual(a, b interface{}) bool {
+	if a == b {
+		return true
+	}
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			return x == y
+		}
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	return va.IsValid() && vb.IsValid() && va.Kind() == reflect.String && vb.Kind() == reflect.String &&
+		va.String() == vb.String()
+}
+
+// toFloat converts a numeric value of any type to float64
+func toFloat(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	}
+	return 0, false
+}
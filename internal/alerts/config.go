@@ -30,6 +30,16 @@ type AlertConfig struct {
 	EnableScheduling  bool          `json:"enable_scheduling" yaml:"enable_scheduling"`
 	CleanupInterval   time.Duration `json:"cleanup_interval" yaml:"cleanup_interval"`
 	MaxAlertAge       time.Duration `json:"max_alert_age" yaml:"max_alert_age"`
+	StateFile         string        `json:"state_file,omitempty" yaml:"state_file,omitempty"` // Persists rule cooldown state across restarts
+
+	// Periods in which sub-critical alerts are held back
+	QuietHours QuietHoursConfig `json:"quiet_hours" yaml:"quiet_hours"`
+
+	// Skips channels that keep failing until they recover
+	ChannelBreaker ChannelBreakerConfig `json:"channel_breaker" yaml:"channel_breaker"`
+
+	// Sends noisy severities as periodic digests
+	Batching BatchingConfig `json:"batching" yaml:"batching"`
 }
 
 // AlertDefaults contains default settings for alerts
@@ -131,6 +141,13 @@ func DefaultAlertConfig() *AlertConfig {
 		EnableScheduling:  true,
 		CleanupInterval:   1 * time.Hour,
 		MaxAlertAge:       24 * time.Hour,
+		QuietHours: QuietHoursConfig{
+			Enabled:  false,
+			Timezone: "UTC",
+			Action:   QuietActionDigest,
+		},
+		ChannelBreaker: DefaultChannelBreakerConfig(),
+		Batching:       DefaultBatchingConfig(),
 
 		// Default values
 		Defaults: AlertDefaults{
@@ -161,6 +178,15 @@ func LoadAlertConfig(filename string) (*AlertConfig, error) {
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
+	if err := config.QuietHours.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := config.ChannelBreaker.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := config.Batching.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
 
 	return &config, nil
 }
@@ -216,6 +242,14 @@ func SetupAlertManager(config *AlertConfig, logger interface{}) (*VelocimexAlert
 		}
 	}
 	
+	// Restore last-triggered times so rules still in cooldown do not re-fire
+	if config.StateFile != "" {
+		if err := am.LoadRules(config.StateFile); err != nil {
+			return nil, fmt.Errorf("failed to load rule state: %w", err)
+		}
+		am.SetStateFile(config.StateFile)
+	}
+	
 	return am, nil
 }
 
@@ -261,8 +295,14 @@ func createRuleFromConfig(config map[string]interface{}) (*AlertRule, error) {
 		}
 	}
 	
+	// Rules need a stable ID for their persisted state to be matched on reload
+	id := getString(config, "id")
+	if id == "" {
+		id = uuid.NewString()
+	}
+	
 	return &AlertRule{
-		ID:         uuid.NewString(),
+		ID:         id,
 		Name:       name,
 		Type:       AlertType(typeStr),
 		Severity:   AlertSeverity(severityStr),
@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"velocimex/internal/logger"
+	"velocimex/internal/metrics"
 )
 
 // AlertEngine provides advanced alert processing and management
@@ -24,6 +25,18 @@ type AlertEngine struct {
 	ruleQueue     chan *AlertRule
 	alertQueue    chan *Alert
 	
+	// Sub-critical alerts held during quiet hours for the digest
+	quietHeld     []*Alert
+	quietMu       sync.Mutex
+	
+	// Circuit breakers of failing channels
+	breakers      map[string]*channelBreaker
+	breakerMu     sync.Mutex
+	
+	// Alerts held for their severity's batch digest
+	batches       map[AlertSeverity]*alertBatch
+	batchMu       sync.Mutex
+	
 	// State management
 	mu            sync.RWMutex
 	ctx           context.Context
@@ -67,6 +80,9 @@ type AlertMetrics struct {
 	TotalAlerts       int                    `json:"total_alerts"`
 	ProcessedAlerts   int                    `json:"processed_alerts"`
 	FailedAlerts      int                    `json:"failed_alerts"`
+	SuppressedAlerts  int                    `json:"suppressed_alerts"` // Held back during quiet hours
+	SkippedAlerts     int                    `json:"skipped_alerts"`    // Not sent to channels with an open circuit breaker
+	BatchedAlerts     int                    `json:"batched_alerts"`    // Held for a batch digest
 	AlertsByType      map[string]int         `json:"alerts_by_type"`
 	AlertsBySeverity  map[AlertSeverity]int  `json:"alerts_by_severity"`
 	AlertsByChannel   map[string]int         `json:"alerts_by_channel"`
@@ -94,6 +110,8 @@ func NewAlertEngine(config *AlertConfig, logger logger.Logger) *AlertEngine {
 		eventQueue:    make(chan *AlertEvent, config.QueueSize),
 		ruleQueue:     make(chan *AlertRule, 100),
 		alertQueue:    make(chan *Alert, config.QueueSize),
+		breakers:      make(map[string]*channelBreaker),
+		batches:       make(map[AlertSeverity]*alertBatch),
 		ctx:           ctx,
 		cancel:        cancel,
 		metrics: &AlertMetrics{
@@ -128,6 +146,12 @@ func NewAlertEngine(config *AlertConfig, logger logger.Logger) *AlertEngine {
 		go ae.metricsWorker()
 	}
 
+	// Send the quiet hours digest when they end
+	if config.QuietHours.Enabled && config.QuietHours.digest() {
+		ae.wg.Add(1)
+		go ae.quietHoursWorker()
+	}
+
 	return ae
 }
 
@@ -254,6 +278,13 @@ func (ae *AlertEngine) RegisterChannel(name string, channel AlertChannel) {
 	ae.channels[name] = channel
 }
 
+// UnregisterChannel removes an alert channel
+func (ae *AlertEngine) UnregisterChannel(name string) {
+	ae.mu.Lock()
+	defer ae.mu.Unlock()
+	delete(ae.channels, name)
+}
+
 // RegisterProcessor registers an alert processor
 func (ae *AlertEngine) RegisterProcessor(name string, processor AlertProcessor) {
 	ae.mu.Lock()
@@ -311,6 +342,9 @@ func (ae *AlertEngine) GetMetrics() *AlertMetrics {
 		TotalAlerts:      ae.metrics.TotalAlerts,
 		ProcessedAlerts:  ae.metrics.ProcessedAlerts,
 		FailedAlerts:     ae.metrics.FailedAlerts,
+		SuppressedAlerts: ae.metrics.SuppressedAlerts,
+		SkippedAlerts:    ae.metrics.SkippedAlerts,
+		BatchedAlerts:    ae.metrics.BatchedAlerts,
 		AlertsByType:     copyStringIntMap(ae.metrics.AlertsByType),
 		AlertsBySeverity: copySeverityIntMap(ae.metrics.AlertsBySeverity),
 		AlertsByChannel:  copyStringIntMap(ae.metrics.AlertsByChannel),
@@ -320,8 +354,18 @@ func (ae *AlertEngine) GetMetrics() *AlertMetrics {
 	}
 }
 
+// QueueDepths reports how many events and alerts are waiting to be processed
+func (ae *AlertEngine) QueueDepths() []metrics.QueueDepth {
+	return []metrics.QueueDepth{
+		{Queue: "alert_events", Depth: len(ae.eventQueue), Capacity: cap(ae.eventQueue)},
+		{Queue: "alert_rules", Depth: len(ae.ruleQueue), Capacity: cap(ae.ruleQueue)},
+		{Queue: "alert_dispatch", Depth: len(ae.alertQueue), Capacity: cap(ae.alertQueue)},
+	}
+}
+
 // Close shuts down the alert engine
 func (ae *AlertEngine) Close() error {
+	ae.flushAllBatches(time.Now())
 	ae.cancel()
 	close(ae.eventQueue)
 	close(ae.ruleQueue)
@@ -452,6 +496,18 @@ func (ae *AlertEngine) processRule(rule *AlertRule) {
 }
 
 func (ae *AlertEngine) processAlert(alert *Alert) {
+	now := time.Now()
+	if ae.holdForQuietHours(alert, now) {
+		return
+	}
+	if ae.batchAlert(alert, now) {
+		return
+	}
+	ae.deliver(alert)
+}
+
+// deliver sends an alert to each of its channels
+func (ae *AlertEngine) deliver(alert *Alert) {
 	start := time.Now()
 
 	// Process alert through channels
@@ -467,7 +523,17 @@ func (ae *AlertEngine) processAlert(alert *Alert) {
 			continue
 		}
 
-		if err := channel.Send(alert); err != nil {
+		if !ae.allowChannel(channelName, time.Now()) {
+			ae.logger.Debug("alerts", fmt.Sprintf("Skipping channel %s, circuit breaker open", channelName), map[string]interface{}{
+				"alert_id": alert.ID,
+			})
+			ae.updateSkippedAlertMetrics()
+			continue
+		}
+
+		err := channel.Send(alert)
+		ae.recordChannelResult(channelName, err, time.Now())
+		if err != nil {
 			ae.logger.Error("alerts", fmt.Sprintf("Failed to send alert to channel %s", channelName), map[string]interface{}{
 				"alert_id": alert.ID,
 				"error":    err.Error(),
@@ -488,7 +554,9 @@ func (ae *AlertEngine) evaluateRule(rule *AlertRule, event *AlertEvent) bool {
 			return false
 		}
 	}
-	return true
+	return rule.ConditionGroup.Match(func(condition *AlertCondition) bool {
+		return ae.evaluateCondition(condition, event)
+	})
 }
 
 func (ae *AlertEngine) evaluateCondition(condition *AlertCondition, event *AlertEvent) bool {
@@ -498,9 +566,9 @@ func (ae *AlertEngine) evaluateCondition(condition *AlertCondition, event *Alert
 	// Evaluate based on operator
 	switch condition.Operator {
 	case "equals":
-		return fieldValue == condition.Value
+		return valuesEqual(fieldValue, condition.Value)
 	case "not_equals":
-		return fieldValue != condition.Value
+		return !valuesEqual(fieldValue, condition.Value)
 	case "contains":
 		if str, ok := fieldValue.(string); ok {
 			if val, ok := condition.Value.(string); ok {
@@ -562,14 +630,22 @@ func (ae *AlertEngine) triggerRule(rule *AlertRule, event *AlertEvent) {
 		Status:    AlertStatusActive,
 	}
 
-	// Send to alert queue
+	ae.Dispatch(alert)
+}
+
+// Dispatch queues an alert for delivery to its channels, which must be
+// registered with the engine
+func (ae *AlertEngine) Dispatch(alert *Alert) error {
 	select {
 	case ae.alertQueue <- alert:
 		ae.updateAlertMetrics(alert)
+		return nil
 	default:
 		ae.logger.Error("alerts", "Alert queue is full", map[string]interface{}{
 			"alert_id": alert.ID,
 		})
+		ae.updateFailedAlertMetrics()
+		return fmt.Errorf("alert queue is full")
 	}
 }
 
@@ -639,9 +715,14 @@ func (ae *AlertEngine) validateRule(rule *AlertRule) error {
 	if rule.EventType == "" {
 		return fmt.Errorf("event type is required")
 	}
-	if len(rule.Conditions) == 0 {
+	if len(rule.Conditions) == 0 && rule.ConditionGroup == nil {
 		return fmt.Errorf("rule must have at least one condition")
 	}
+	if rule.ConditionGroup != nil {
+		if err := rule.ConditionGroup.Validate(); err != nil {
+			return err
+		}
+	}
 	if len(rule.Channels) == 0 {
 		return fmt.Errorf("rule must have at least one channel")
 	}
@@ -703,6 +784,12 @@ func (ae *AlertEngine) updateFailedAlertMetrics() {
 	ae.metrics.FailedAlerts++
 }
 
+func (ae *AlertEngine) updateSkippedAlertMetrics() {
+	ae.metrics.mu.Lock()
+	defer ae.metrics.mu.Unlock()
+	ae.metrics.SkippedAlerts++
+}
+
 func (ae *AlertEngine) updateProcessingMetrics(duration time.Duration) {
 	ae.metrics.mu.Lock()
 	defer ae.metrics.mu.Unlock()
@@ -743,9 +830,23 @@ func containsMiddle(s, substr string) bool {
 }
 
 func compareValues(a, b interface{}, op string) bool {
-	// Simple comparison implementation
-	// In a real implementation, this would handle different types properly
-	return false
+	x, ok := toFloat(a)
+	if !ok {
+		return false
+	}
+	y, ok := toFloat(b)
+	if !ok {
+		return false
+	}
+
+	switch op {
+	case ">":
+		return x > y
+	case "<":
+		return x < y
+	default:
+		return false
+	}
 }
 
 func replaceAll(s, old, new string) string {
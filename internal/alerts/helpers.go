@@ -6,7 +6,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"velocimex/internal/logger"
+	"velocimex/internal/numeric"
 )
 
 // GlobalAlertManager is the global instance of the alert manager
@@ -79,10 +81,7 @@ func TriggerPriceAlert(symbol string, price, previous float64) error {
 	}
 	
 	change := price - previous
-	changePct := 0.0
-	if previous != 0 {
-		changePct = (change / previous) * 100
-	}
+	changePct := numeric.PercentChange(decimal.NewFromFloat(previous), decimal.NewFromFloat(price)).InexactFloat64()
 	
 	data := PriceAlertData{
 		Symbol:    symbol,
@@ -458,6 +457,12 @@ func (b *AlertRuleBuilder) Condition(field, operator string, value interface{})
 	return b
 }
 
+// Group ANDs a condition group with the rule's conditions, replacing any previous group
+func (b *AlertRuleBuilder) Group(group ConditionGroup) *AlertRuleBuilder {
+	b.rule.ConditionGroup = &group
+	return b
+}
+
 func (b *AlertRuleBuilder) Cooldown(duration time.Duration) *AlertRuleBuilder {
 	b.rule.Cooldown = duration
 	return b
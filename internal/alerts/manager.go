@@ -3,13 +3,23 @@ package alerts
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"velocimex/internal/logger"
+	"velocimex/internal/metrics"
+)
+
+// Alert lifecycle errors
+var (
+	ErrAlertNotFound   = errors.New("alert not found")
+	ErrAlertResolved   = errors.New("alert already resolved")
+	ErrChannelNotFound = errors.New("alert channel not found")
 )
 
 // VelocimexAlertManager implements the AlertManager interface
@@ -21,8 +31,15 @@ type VelocimexAlertManager struct {
 	alertMutex sync.RWMutex
 	channelMutex sync.RWMutex
 	
+	// Rules are persisted here after each trigger so cooldowns survive restarts
+	stateFile  string
+	stateMutex sync.Mutex
+	
 	logger logger.Logger
 	
+	// Delivers alerts to their channels when set; otherwise each channel is sent to directly
+	engine *AlertEngine
+	
 	ctx    context.Context
 	cancel context.CancelFunc
 	
@@ -51,6 +68,12 @@ func (am *VelocimexAlertManager) AddRule(rule *AlertRule) error {
 	am.ruleMutex.Lock()
 	defer am.ruleMutex.Unlock()
 	
+	if rule.ConditionGroup != nil {
+		if err := rule.ConditionGroup.Validate(); err != nil {
+			return fmt.Errorf("invalid condition group: %w", err)
+		}
+	}
+	
 	if rule.ID == "" {
 		rule.ID = uuid.NewString()
 	}
@@ -142,7 +165,7 @@ func (am *VelocimexAlertManager) TriggerAlert(rule *AlertRule, data interface{})
 	}
 	
 	// Check conditions
-	if !am.evaluateConditions(rule.Conditions, data) {
+	if !am.evaluateConditions(rule.Conditions, data) || !am.evaluateConditionGroup(rule.ConditionGroup, data) {
 		return nil
 	}
 	
@@ -155,8 +178,11 @@ func (am *VelocimexAlertManager) TriggerAlert(rule *AlertRule, data interface{})
 		Title:     rule.Name,
 		Message:   am.formatMessage(rule.Message, data),
 		Data:      data,
+		Channels:  am.channelNames(rule.Channels),
 		Timestamp: time.Now(),
+		Status:    AlertStatusActive,
 	}
+	alert.CreatedAt = alert.Timestamp
 	
 	// Store alert
 	am.alertMutex.Lock()
@@ -166,10 +192,12 @@ func (am *VelocimexAlertManager) TriggerAlert(rule *AlertRule, data interface{})
 	// Update rule last triggered time
 	am.ruleMutex.Lock()
 	rule.LastTriggered = time.Now()
+	rule.TriggerCount++
 	am.ruleMutex.Unlock()
+	am.persistRules()
 	
 	// Send to channels
-	am.sendAlertToChannels(alert, rule.Channels)
+	am.sendAlertToChannels(alert)
 	
 	// Log alert
 	if am.logger != nil {
@@ -195,10 +223,14 @@ func (am *VelocimexAlertManager) AcknowledgeAlert(alertID string) error {
 	
 	alert, exists := am.alerts[alertID]
 	if !exists {
-		return fmt.Errorf("alert %s not found", alertID)
+		return fmt.Errorf("%w: %s", ErrAlertNotFound, alertID)
+	}
+	if alert.Resolved {
+		return fmt.Errorf("%w: %s", ErrAlertResolved, alertID)
 	}
 	
 	alert.Acknowledged = true
+	alert.Status = AlertStatusAcknowledged
 	
 	if am.logger != nil {
 		am.logger.Info("alert", "Alert acknowledged")
@@ -214,10 +246,14 @@ func (am *VelocimexAlertManager) ResolveAlert(alertID string) error {
 	
 	alert, exists := am.alerts[alertID]
 	if !exists {
-		return fmt.Errorf("alert %s not found", alertID)
+		return fmt.Errorf("%w: %s", ErrAlertNotFound, alertID)
+	}
+	if alert.Resolved {
+		return nil
 	}
 	
 	alert.Resolved = true
+	alert.Status = AlertStatusResolved
 	now := time.Now()
 	alert.ResolvedAt = &now
 	
@@ -240,9 +276,27 @@ func (am *VelocimexAlertManager) GetAlerts(filters map[string]interface{}) ([]*A
 		}
 	}
 	
+	// Newest first
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Timestamp.After(alerts[j].Timestamp)
+	})
+	
 	return alerts, nil
 }
 
+// GetAlert retrieves a specific alert
+func (am *VelocimexAlertManager) GetAlert(alertID string) (*Alert, error) {
+	am.alertMutex.RLock()
+	defer am.alertMutex.RUnlock()
+	
+	alert, exists := am.alerts[alertID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrAlertNotFound, alertID)
+	}
+	
+	return alert, nil
+}
+
 // GetActiveAlerts returns all active (unresolved) alerts
 func (am *VelocimexAlertManager) GetActiveAlerts() ([]*Alert, error) {
 	return am.GetAlerts(map[string]interface{}{
@@ -256,6 +310,9 @@ func (am *VelocimexAlertManager) RegisterChannel(channel AlertChannel) error {
 	defer am.channelMutex.Unlock()
 	
 	am.channels[channel.Name()] = channel
+	if am.engine != nil {
+		am.engine.RegisterChannel(channel.Name(), channel)
+	}
 	
 	if am.logger != nil {
 		am.logger.Info("alert", "Registered alert channel")
@@ -274,6 +331,9 @@ func (am *VelocimexAlertManager) RemoveChannel(channelName string) error {
 	}
 	
 	delete(am.channels, channelName)
+	if am.engine != nil {
+		am.engine.UnregisterChannel(channelName)
+	}
 	
 	if am.logger != nil {
 		am.logger.Info("alert", "Removed alert channel")
@@ -282,6 +342,72 @@ func (am *VelocimexAlertManager) RemoveChannel(channelName string) error {
 	return nil
 }
 
+// SetEngine delivers alerts through the engine, so its quiet hours, batching,
+// channel circuit breakers and metrics apply to them. The manager's channels
+// are registered with the engine.
+func (am *VelocimexAlertManager) SetEngine(engine *AlertEngine) {
+	am.channelMutex.Lock()
+	defer am.channelMutex.Unlock()
+	
+	am.engine = engine
+	for name, channel := range am.channels {
+		engine.RegisterChannel(name, channel)
+	}
+}
+
+// SendTestAlert sends a synthetic alert through the named channel and
+// returns the channel's delivery error, if any. The alert is neither stored
+// nor matched against rules, so operators can check a channel's wiring
+// without waiting for a real condition.
+func (am *VelocimexAlertManager) SendTestAlert(channelName, message string, severity AlertSeverity) (*Alert, error) {
+	am.channelMutex.RLock()
+	channel, exists := am.channels[channelName]
+	am.channelMutex.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrChannelNotFound, channelName)
+	}
+	
+	if message == "" {
+		message = fmt.Sprintf("Test alert sent through channel %s", channelName)
+	}
+	if severity == "" {
+		severity = SeverityLow
+	}
+	now := time.Now()
+	alert := &Alert{
+		ID:        uuid.New().String(),
+		Type:      AlertTypeTest,
+		Severity:  severity,
+		Title:     "Test alert",
+		Message:   message,
+		Channels:  []string{channelName},
+		Timestamp: now,
+		CreatedAt: now,
+		Status:    AlertStatusActive,
+	}
+	
+	if err := channel.Send(alert); err != nil {
+		return alert, fmt.Errorf("channel %s failed to deliver test alert: %w", channelName, err)
+	}
+	return alert, nil
+}
+
+// QueueDepths reports the alert events waiting to be processed and, with an
+// engine set, the engine's queues, which carry the manager's alerts
+func (am *VelocimexAlertManager) QueueDepths() []metrics.QueueDepth {
+	depths := []metrics.QueueDepth{
+		{Queue: "alert_manager_events", Depth: len(am.eventChan), Capacity: cap(am.eventChan)},
+	}
+	
+	am.channelMutex.RLock()
+	engine := am.engine
+	am.channelMutex.RUnlock()
+	if engine != nil {
+		depths = append(depths, engine.QueueDepths()...)
+	}
+	return depths
+}
+
 // Start starts the alert manager
 func (am *VelocimexAlertManager) Start() error {
 	if am.logger != nil {
@@ -314,12 +440,7 @@ func (am *VelocimexAlertManager) evaluateConditions(conditions []AlertCondition,
 		return true
 	}
 	
-	dataMap := make(map[string]interface{})
-	if data != nil {
-		jsonData, _ := json.Marshal(data)
-		_ = json.Unmarshal(jsonData, &dataMap)
-	}
-	
+	dataMap := conditionData(data)
 	for _, condition := range conditions {
 		if !am.evaluateCondition(condition, dataMap) {
 			return false
@@ -329,6 +450,28 @@ func (am *VelocimexAlertManager) evaluateConditions(conditions []AlertCondition,
 	return true
 }
 
+// evaluateConditionGroup evaluates a condition group against data; a nil group always holds
+func (am *VelocimexAlertManager) evaluateConditionGroup(group *ConditionGroup, data interface{}) bool {
+	if group == nil {
+		return true
+	}
+	
+	dataMap := conditionData(data)
+	return group.Match(func(condition *AlertCondition) bool {
+		return am.evaluateCondition(*condition, dataMap)
+	})
+}
+
+// conditionData converts alert data to the field map conditions are evaluated against
+func conditionData(data interface{}) map[string]interface{} {
+	dataMap := make(map[string]interface{})
+	if data != nil {
+		jsonData, _ := json.Marshal(data)
+		_ = json.Unmarshal(jsonData, &dataMap)
+	}
+	return dataMap
+}
+
 // evaluateCondition evaluates a single condition
 func (am *VelocimexAlertManager) evaluateCondition(condition AlertCondition, data map[string]interface{}) bool {
 	fieldValue, exists := data[condition.Field]
@@ -339,6 +482,7 @@ func (am *VelocimexAlertManager) evaluateCondition(condition AlertCondition, dat
 	// Convert value to float64 for numeric comparisons
 	var numericValue float64
 	var stringValue string
+	isString := false
 	
 	switch v := fieldValue.(type) {
 	case float64:
@@ -347,6 +491,7 @@ func (am *VelocimexAlertManager) evaluateCondition(condition AlertCondition, dat
 		numericValue = float64(v)
 	case string:
 		stringValue = v
+		isString = true
 	default:
 		return false
 	}
@@ -370,9 +515,15 @@ func (am *VelocimexAlertManager) evaluateCondition(condition AlertCondition, dat
 	case "lt":
 		return numericValue < conditionValue
 	case "eq":
-		return numericValue == conditionValue || stringValue == conditionString
+		if isString {
+			return stringValue == conditionString
+		}
+		return numericValue == conditionValue
 	case "ne":
-		return numericValue != conditionValue || stringValue != conditionString
+		if isString {
+			return stringValue != conditionString
+		}
+		return numericValue != conditionValue
 	case "contains":
 		return stringValue == conditionString
 	default:
@@ -401,27 +552,42 @@ func (am *VelocimexAlertManager) formatMessage(template string, data interface{}
 	return template
 }
 
-// sendAlertToChannels sends an alert to registered channels
-func (am *VelocimexAlertManager) sendAlertToChannels(alert *Alert, channelNames []string) {
+// channelNames returns the registered channels among names, or every
+// registered channel if names is empty
+func (am *VelocimexAlertManager) channelNames(names []string) []string {
 	am.channelMutex.RLock()
 	defer am.channelMutex.RUnlock()
 	
-	// If no specific channels specified, use all registered channels
-	if len(channelNames) == 0 {
-		for _, channel := range am.channels {
-			go func(ch AlertChannel) {
-				if err := ch.Send(alert); err != nil {
-					if am.logger != nil {
-						am.logger.Error("alert", "Failed to send alert to channel")
-					}
-				}
-			}(channel)
+	resolved := make([]string, 0, len(am.channels))
+	if len(names) == 0 {
+		for name := range am.channels {
+			resolved = append(resolved, name)
+		}
+		sort.Strings(resolved)
+		return resolved
+	}
+	for _, name := range names {
+		if _, exists := am.channels[name]; exists {
+			resolved = append(resolved, name)
+		}
+	}
+	return resolved
+}
+
+// sendAlertToChannels sends an alert to each of its channels, through the
+// engine if one is set
+func (am *VelocimexAlertManager) sendAlertToChannels(alert *Alert) {
+	am.channelMutex.RLock()
+	defer am.channelMutex.RUnlock()
+	
+	if am.engine != nil {
+		if err := am.engine.Dispatch(alert); err != nil && am.logger != nil {
+			am.logger.Error("alert", "Failed to dispatch alert")
 		}
 		return
 	}
 	
-	// Send to specific channels
-	for _, channelName := range channelNames {
+	for _, channelName := range alert.Channels {
 		if channel, exists := am.channels[channelName]; exists {
 			go func(ch AlertChannel) {
 				if err := ch.Send(alert); err != nil {
@@ -434,7 +600,6 @@ func (am *VelocimexAlertManager) sendAlertToChannels(alert *Alert, channelNames
 	}
 }
 
-
 // matchesFilters checks if an alert matches the given filters
 func (am *VelocimexAlertManager) matchesFilters(alert *Alert, filters map[string]interface{}) bool {
 	for key, value := range filters {
@@ -455,6 +620,14 @@ func (am *VelocimexAlertManager) matchesFilters(alert *Alert, filters map[string
 			if alert.Acknowledged != value.(bool) {
 				return false
 			}
+		case "status":
+			if alert.Status != value.(AlertStatus) {
+				return false
+			}
+		case "rule_id":
+			if alert.RuleID != value.(string) {
+				return false
+			}
 		}
 	}
 	return true
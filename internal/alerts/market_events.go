@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"velocimex/internal/logger"
+	"velocimex/internal/numeric"
 )
 
 // MarketEventAlertSystem handles market-specific alerts
@@ -36,6 +38,8 @@ type MarketAlertRule struct {
 	Threshold   float64                `json:"threshold"`
 	Timeframe   time.Duration          `json:"timeframe"`
 	Enabled     bool                   `json:"enabled"`
+	Severity    AlertSeverity          `json:"severity,omitempty"` // Severity of the alerts the rule raises; empty uses the type's default
+	Rearm       float64                `json:"rearm,omitempty"`    // How far back past the threshold the value must move before the rule can fire again
 	Channels    []string               `json:"channels"`
 	Metadata    map[string]interface{} `json:"metadata"`
 	CreatedAt   time.Time              `json:"created_at"`
@@ -54,6 +58,25 @@ const (
 	MarketAlertLiquidity MarketAlertType = "liquidity"
 )
 
+// defaultMarketSeverities is the severity of each type's alerts when the rule sets none
+var defaultMarketSeverities = map[MarketAlertType]AlertSeverity{
+	MarketAlertPrice:      SeverityMedium,
+	MarketAlertVolume:     SeverityMedium,
+	MarketAlertVolatility: SeverityHigh,
+	MarketAlertArbitrage:  SeverityHigh,
+}
+
+// severity returns the severity of the alerts the rule raises
+func (r *MarketAlertRule) severity() AlertSeverity {
+	if r.Severity != "" {
+		return r.Severity
+	}
+	if severity, ok := defaultMarketSeverities[r.Type]; ok {
+		return severity
+	}
+	return SeverityMedium
+}
+
 // MarketCondition represents the condition for a market alert
 type MarketCondition struct {
 	Operator string  `json:"operator"` // "above", "below", "crosses_above", "crosses_below"
@@ -103,6 +126,7 @@ type ArbitrageAlert struct {
 	SellExchange  string    `json:"sell_exchange"`
 	ProfitPercent float64   `json:"profit_percent"`
 	Threshold     float64   `json:"threshold"`
+	Severity      AlertSeverity `json:"severity"`
 	LastCheck     time.Time `json:"last_check"`
 	Triggered     bool      `json:"triggered"`
 }
@@ -192,6 +216,7 @@ func (mas *MarketEventAlertSystem) AddMarketRule(rule *MarketAlertRule) error {
 		mas.arbitrageAlerts[rule.ID] = &ArbitrageAlert{
 			Symbol:    rule.Symbol,
 			Threshold: rule.Threshold,
+			Severity:  rule.severity(),
 			LastCheck: now,
 		}
 	}
@@ -234,8 +259,8 @@ func (mas *MarketEventAlertSystem) ProcessMarketData(symbol, exchange string, da
 
 // ProcessArbitrageData processes arbitrage data and checks for alerts
 func (mas *MarketEventAlertSystem) ProcessArbitrageData(arbitrageData []map[string]interface{}) {
-	mas.mu.RLock()
-	defer mas.mu.RUnlock()
+	mas.mu.Lock()
+	defer mas.mu.Unlock()
 
 	for _, data := range arbitrageData {
 		symbol, ok := data["symbol"].(string)
@@ -254,7 +279,7 @@ func (mas *MarketEventAlertSystem) ProcessArbitrageData(arbitrageData []map[stri
 				event := &AlertEvent{
 					ID:        uuid.New().String(),
 					Type:      string(MarketAlertArbitrage),
-					Severity:  SeverityHigh,
+					Severity:  alert.Severity,
 					Source:    "arbitrage",
 					Message:   fmt.Sprintf("Arbitrage opportunity: %.4f%% profit for %s between %s and %s",
 						profitPercent, alert.Symbol, data["buy_exchange"], data["sell_exchange"]),
@@ -276,9 +301,7 @@ func (mas *MarketEventAlertSystem) ProcessArbitrageData(arbitrageData []map[stri
 					})
 
 				// Update alert state
-				mas.mu.Lock()
 				alert.Triggered = true
-				mas.mu.Unlock()
 			}
 		}
 	}
@@ -301,15 +324,15 @@ func (mas *MarketEventAlertSystem) checkPriceAlert(rule *MarketAlertRule, data m
 	alert.PreviousPrice = alert.CurrentPrice
 	alert.CurrentPrice = price
 	alert.LastCheck = time.Now()
+	fire := mas.updatePriceTrigger(rule, alert)
 	mas.mu.Unlock()
 
-	// Check condition
-	if mas.evaluatePriceCondition(alert) {
+	if fire {
 		// Create alert event
 		event := &AlertEvent{
 			ID:        uuid.New().String(),
 			Type:      string(MarketAlertPrice),
-			Severity:  SeverityMedium,
+			Severity:  rule.severity(),
 			Source:    "market",
 			Message:   fmt.Sprintf("Price alert for %s/%s: %.8f %s %.8f", 
 				alert.Symbol, alert.Exchange, 
@@ -330,11 +353,6 @@ func (mas *MarketEventAlertSystem) checkPriceAlert(rule *MarketAlertRule, data m
 				"price":     alert.CurrentPrice,
 				"threshold": rule.Threshold,
 			})
-
-		// Update alert state
-		mas.mu.Lock()
-		alert.Triggered = true
-		mas.mu.Unlock()
 	}
 }
 
@@ -354,15 +372,15 @@ func (mas *MarketEventAlertSystem) checkVolumeAlert(rule *MarketAlertRule, data
 
 	alert.CurrentVolume = volume
 	alert.LastCheck = time.Now()
+	fire := mas.updateVolumeTrigger(rule, alert)
 	mas.mu.Unlock()
 
-	// Check condition
-	if mas.evaluateVolumeCondition(alert) {
+	if fire {
 		// Create alert event
 		event := &AlertEvent{
 			ID:        uuid.New().String(),
 			Type:      string(MarketAlertVolume),
-			Severity:  SeverityMedium,
+			Severity:  rule.severity(),
 			Source:    "market",
 			Message:   fmt.Sprintf("Volume alert for %s/%s: %.2f %s %.2f (avg: %.2f)",
 				alert.Symbol, alert.Exchange,
@@ -384,11 +402,6 @@ func (mas *MarketEventAlertSystem) checkVolumeAlert(rule *MarketAlertRule, data
 				"average_volume": alert.AverageVolume,
 				"threshold":      rule.Threshold,
 			})
-
-		// Update alert state
-		mas.mu.Lock()
-		alert.Triggered = true
-		mas.mu.Unlock()
 	}
 }
 
@@ -408,15 +421,15 @@ func (mas *MarketEventAlertSystem) checkVolatilityAlert(rule *MarketAlertRule, d
 
 	alert.CurrentVolatility = volatility
 	alert.LastCheck = time.Now()
+	fire := mas.updateVolatilityTrigger(rule, alert)
 	mas.mu.Unlock()
 
-	// Check condition
-	if mas.evaluateVolatilityCondition(alert) {
+	if fire {
 		// Create alert event
 		event := &AlertEvent{
 			ID:        uuid.New().String(),
 			Type:      string(MarketAlertVolatility),
-			Severity:  SeverityHigh,
+			Severity:  rule.severity(),
 			Source:    "market",
 			Message:   fmt.Sprintf("Volatility alert for %s/%s: %.4f %s %.4f",
 				alert.Symbol, alert.Exchange,
@@ -437,16 +450,81 @@ func (mas *MarketEventAlertSystem) checkVolatilityAlert(rule *MarketAlertRule, d
 				"volatility": alert.CurrentVolatility,
 				"threshold":  rule.Threshold,
 			})
+	}
+}
 
-		// Update alert state
-		mas.mu.Lock()
-		alert.Triggered = true
-		mas.mu.Unlock()
+// updatePriceTrigger reports whether a price alert fires now. A triggered
+// alert does not fire again until its condition has cleared by the rule's
+// rearm distance. The caller holds mas.mu.
+func (mas *MarketEventAlertSystem) updatePriceTrigger(rule *MarketAlertRule, alert *PriceAlert) bool {
+	if alert.Triggered {
+		value := alert.CurrentPrice
+		if alert.Condition.Percent {
+			if alert.PreviousPrice == 0 {
+				return false
+			}
+			value = numeric.PercentChange(decimal.NewFromFloat(alert.PreviousPrice), decimal.NewFromFloat(alert.CurrentPrice)).InexactFloat64()
+		}
+		alert.Triggered = !conditionCleared(alert.Condition.Operator, value, alert.Threshold, rule.Rearm)
+		return false
+	}
+	alert.Triggered = mas.evaluatePriceCondition(alert)
+	return alert.Triggered
+}
+
+// updateVolumeTrigger reports whether a volume alert fires now, rearming it
+// as updatePriceTrigger does. The caller holds mas.mu.
+func (mas *MarketEventAlertSystem) updateVolumeTrigger(rule *MarketAlertRule, alert *VolumeAlert) bool {
+	if alert.Triggered {
+		alert.Triggered = !conditionCleared(alert.Condition.Operator, alert.CurrentVolume, alert.Threshold, rule.Rearm)
+		return false
+	}
+	alert.Triggered = mas.evaluateVolumeCondition(alert)
+	return alert.Triggered
+}
+
+// updateVolatilityTrigger reports whether a volatility alert fires now,
+// rearming it as updatePriceTrigger does. The caller holds mas.mu.
+func (mas *MarketEventAlertSystem) updateVolatilityTrigger(rule *MarketAlertRule, alert *VolatilityAlert) bool {
+	if alert.Triggered {
+		alert.Triggered = !conditionCleared(alert.Condition.Operator, alert.CurrentVolatility, alert.Threshold, rule.Rearm)
+		return false
+	}
+	alert.Triggered = mas.evaluateVolatilityCondition(alert)
+	return alert.Triggered
+}
+
+// conditionCleared reports whether a value has moved back past a threshold by
+// at least the rearm distance, so that its alert can fire again
+func conditionCleared(operator string, value, threshold, rearm float64) bool {
+	switch operator {
+	case "above", "crosses_above":
+		return value <= threshold-rearm
+	case "below", "crosses_below":
+		return value >= threshold+rearm
+	default:
+		return false
 	}
 }
 
 // evaluatePriceCondition evaluates a price alert condition
 func (mas *MarketEventAlertSystem) evaluatePriceCondition(alert *PriceAlert) bool {
+	// Percent conditions compare the change since the previous price against the threshold
+	if alert.Condition.Percent {
+		if alert.PreviousPrice == 0 {
+			return false
+		}
+		change := numeric.PercentChange(decimal.NewFromFloat(alert.PreviousPrice), decimal.NewFromFloat(alert.CurrentPrice)).InexactFloat64()
+		switch alert.Condition.Operator {
+		case "above", "crosses_above":
+			return change > alert.Threshold
+		case "below", "crosses_below":
+			return change < alert.Threshold
+		default:
+			return false
+		}
+	}
+
 	switch alert.Condition.Operator {
 	case "above":
 		return alert.CurrentPrice > alert.Threshold
@@ -498,7 +576,7 @@ func (mas *MarketEventAlertSystem) triggerVolumeAlert(rule *MarketAlertRule, ale
 	event := &AlertEvent{
 		ID:        uuid.New().String(),
 		Type:      string(MarketAlertVolume),
-		Severity:  SeverityMedium, // Use the correct severity constant
+		Severity:  rule.severity(),
 		Source:    "market",
 		Message:   fmt.Sprintf("Volume alert for %s/%s: %.2f %s %.2f (avg: %.2f)", 
 			alert.Symbol, alert.Exchange, 
@@ -531,7 +609,7 @@ func (mas *MarketEventAlertSystem) triggerVolatilityAlert(rule *MarketAlertRule,
 	event := &AlertEvent{
 		ID:        uuid.New().String(),
 		Type:      string(MarketAlertVolatility),
-		Severity:  SeverityHigh, // Use the correct severity constant
+		Severity:  rule.severity(),
 		Source:    "market",
 		Message:   fmt.Sprintf("Volatility alert for %s/%s: %.2f %s %.2f", 
 			alert.Symbol, alert.Exchange, 
@@ -563,7 +641,7 @@ func (mas *MarketEventAlertSystem) triggerArbitrageAlert(alert *ArbitrageAlert,
 	event := &AlertEvent{
 		ID:        uuid.New().String(),
 		Type:      string(MarketAlertArbitrage),
-		Severity:  SeverityHigh, // Use the correct severity constant
+		Severity:  alert.Severity,
 		Source:    "arbitrage",
 		Message:   fmt.Sprintf("Arbitrage opportunity: %.4f%% profit for %s between %s and %s", 
 			data["profit_percent"].(float64), alert.Symbol, data["buy_exchange"], data["sell_exchange"]),
@@ -678,6 +756,14 @@ func (mas *MarketEventAlertSystem) validateMarketRule(rule *MarketAlertRule) err
 	if rule.Condition.Operator == "" {
 		return fmt.Errorf("condition operator is required")
 	}
+	if rule.Rearm < 0 {
+		return fmt.Errorf("rearm cannot be negative")
+	}
+	switch rule.Severity {
+	case "", SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical:
+	default:
+		return fmt.Errorf("unknown severity %q", rule.Severity)
+	}
 	return nil
 }
 
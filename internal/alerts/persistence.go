@@ -0,0 +1,113 @@
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SetStateFile sets the file rules and their cooldown state are persisted to.
+// An empty path disables persistence.
+func (am *VelocimexAlertManager) SetStateFile(filename string) {
+	am.stateMutex.Lock()
+	defer am.stateMutex.Unlock()
+	am.stateFile = filename
+}
+
+// SaveRules writes all rules, including their last-triggered times, to a file
+func (am *VelocimexAlertManager) SaveRules(filename string) error {
+	am.ruleMutex.RLock()
+	rules := make([]AlertRule, 0, len(am.rules))
+	for _, rule := range am.rules {
+		rules = append(rules, *rule)
+	}
+	data, err := json.MarshalIndent(rules, "", "  ")
+	am.ruleMutex.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal rules: %w", err)
+	}
+
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Write to a temporary file and rename so a crash never leaves a truncated file
+	tmp, err := os.CreateTemp(dir, filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write rules: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write rules: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("failed to replace rules file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadRules restores rule state from a file written by SaveRules. Rules that
+// are already registered keep their definition but take the persisted
+// last-triggered time and trigger count, so cooldowns survive restarts.
+// Saved rules that are not registered, e.g. removed from the configuration or
+// given a fresh ID because the configuration has none, are skipped.
+// A missing file is not an error.
+func (am *VelocimexAlertManager) LoadRules(filename string) error {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var rules []AlertRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return fmt.Errorf("failed to parse rules file: %w", err)
+	}
+
+	am.ruleMutex.Lock()
+	defer am.ruleMutex.Unlock()
+
+	for _, saved := range rules {
+		if saved.ID == "" {
+			continue
+		}
+
+		existing, exists := am.rules[saved.ID]
+		if !exists {
+			continue
+		}
+		if saved.LastTriggered.After(existing.LastTriggered) {
+			existing.LastTriggered = saved.LastTriggered
+		}
+		if saved.TriggerCount > existing.TriggerCount {
+			existing.TriggerCount = saved.TriggerCount
+		}
+	}
+
+	return nil
+}
+
+// persistRules saves rules to the configured state file, if any
+func (am *VelocimexAlertManager) persistRules() {
+	am.stateMutex.Lock()
+	defer am.stateMutex.Unlock()
+
+	if am.stateFile == "" {
+		return
+	}
+	if err := am.SaveRules(am.stateFile); err != nil && am.logger != nil {
+		am.logger.Error("alert", "Failed to persist alert rules", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package alerts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Quiet hours actions for sub-critical alerts
+const (
+	QuietActionSuppress = "suppress" // Drop the alert
+	QuietActionDigest   = "digest"   // Hold the alert and send a digest when quiet hours end
+)
+
+// QuietWindow is a daily period of quiet hours, in "15:04" clock times. A
+// window whose end is before its start runs past midnight.
+type QuietWindow struct {
+	Start string   `json:"start" yaml:"start"`
+	End   string   `json:"end" yaml:"end"`
+	Days  []string `json:"days,omitempty" yaml:"days,omitempty"` // Weekdays the window starts on, e.g. "sat"; empty is every day
+}
+
+// QuietHoursConfig configures periods, such as nights or maintenance
+// windows, in which sub-critical alerts are held back. Critical alerts are
+// always delivered.
+type QuietHoursConfig struct {
+	Enabled  bool          `json:"enabled" yaml:"enabled"`
+	Timezone string        `json:"timezone" yaml:"timezone"` // IANA name the windows are in, default UTC
+	Action   string        `json:"action" yaml:"action"`     // suppress or digest, default digest
+	Windows  []QuietWindow `json:"windows" yaml:"windows"`
+}
+
+// Validate checks the timezone, action and windows
+func (c QuietHoursConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		return fmt.Errorf("invalid quiet hours timezone %q: %w", c.Timezone, err)
+	}
+	switch c.Action {
+	case "", QuietActionSuppress, QuietActionDigest:
+	default:
+		return fmt.Errorf("unknown quiet hours action: %s", c.Action)
+	}
+	for _, window := range c.Windows {
+		if _, err := clockMinutes(window.Start); err != nil {
+			return err
+		}
+		if _, err := clockMinutes(window.End); err != nil {
+			return err
+		}
+		for _, day := range window.Days {
+			if _, ok := weekdays[strings.ToLower(day)]; !ok {
+				return fmt.Errorf("unknown quiet hours day: %s", day)
+			}
+		}
+	}
+	return nil
+}
+
+// digest reports whether held alerts are sent as a digest rather than dropped
+func (c QuietHoursConfig) digest() bool {
+	return c.Action != QuietActionSuppress
+}
+
+// Active reports whether t falls within any quiet window
+func (c QuietHoursConfig) Active(t time.Time) bool {
+	if !c.Enabled {
+		return false
+	}
+	if location, err := time.LoadLocation(c.Timezone); err == nil {
+		t = t.In(location)
+	}
+
+	now := t.Hour()*60 + t.Minute()
+	for _, window := range c.Windows {
+		start, err := clockMinutes(window.Start)
+		if err != nil {
+			continue
+		}
+		end, err := clockMinutes(window.End)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case start <= end:
+			if now >= start && now < end && window.onDay(t.Weekday()) {
+				return true
+			}
+		case now >= start:
+			// Before midnight in a window that started today
+			if window.onDay(t.Weekday()) {
+				return true
+			}
+		case now < end:
+			// After midnight in a window that started yesterday
+			if window.onDay((t.Weekday() + 6) % 7) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// severityRank orders severities from least to most severe
+var severityRank = map[AlertSeverity]int{
+	SeverityLow:      0,
+	SeverityMedium:   1,
+	SeverityHigh:     2,
+	SeverityCritical: 3,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// onDay reports whether the window starts on a weekday
+func (w QuietWindow) onDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if weekdays[strings.ToLower(d)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// clockMinutes parses a "15:04" clock time into minutes after midnight
+func clockMinutes(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quiet hours time %q: want HH:MM", clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// holdForQuietHours keeps sub-critical alerts from being delivered during
+// quiet hours, holding them for the digest or dropping them. Held alerts are
+// released once quiet hours have ended.
+func (ae *AlertEngine) holdForQuietHours(alert *Alert, now time.Time) bool {
+	quiet := ae.config.QuietHours
+	if !quiet.Active(now) {
+		ae.releaseQuietDigest(now)
+		return false
+	}
+	if alert.Severity == SeverityCritical {
+		return false
+	}
+
+	ae.quietMu.Lock()
+	if quiet.digest() {
+		ae.quietHeld = append(ae.quietHeld, alert)
+	}
+	ae.quietMu.Unlock()
+
+	ae.metrics.mu.Lock()
+	ae.metrics.SuppressedAlerts++
+	ae.metrics.mu.Unlock()
+	return true
+}
+
+// releaseQuietDigest sends each channel a digest of the alerts held for it
+// during quiet hours, once they have ended
+func (ae *AlertEngine) releaseQuietDigest(now time.Time) {
+	if ae.config.QuietHours.Active(now) {
+		return
+	}
+
+	ae.quietMu.Lock()
+	held := ae.quietHeld
+	ae.quietHeld = nil
+	ae.quietMu.Unlock()
+	if len(held) == 0 {
+		return
+	}
+
+	for channel, alerts := range alertsByChannel(held) {
+		ae.deliver(quietDigest(channel, alerts, now))
+	}
+}
+
+// quietDigest summarises the alerts held for a channel in a single alert
+func quietDigest(channel string, alerts []*Alert, now time.Time) *Alert {
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].CreatedAt.Before(alerts[j].CreatedAt) })
+
+	severity := SeverityLow
+	lines := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		if severityRank[alert.Severity] > severityRank[severity] {
+			severity = alert.Severity
+		}
+		lines = append(lines, fmt.Sprintf("- %s [%s] %s: %s", alert.CreatedAt.Format(time.RFC3339), alert.Severity, alert.Title, alert.Message))
+	}
+
+	return &Alert{
+		ID:        uuid.NewString(),
+		Type:      AlertTypeSystem,
+		Severity:  severity,
+		Title:     fmt.Sprintf("Quiet hours digest: %d alerts", len(alerts)),
+		Message:   strings.Join(lines, "\n"),
+		Channels:  []string{channel},
+		Metadata:  map[string]interface{}{"digest": true, "alert_count": len(alerts)},
+		CreatedAt: now,
+		Timestamp: now,
+		Status:    AlertStatusActive,
+	}
+}
+
+// quietHoursWorker sends the digest when quiet hours end, even if no
+// further alert arrives to release it
+func (ae *AlertEngine) quietHoursWorker() {
+	defer ae.wg.Done()
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			ae.releaseQuietDigest(now)
+		case <-ae.ctx.Done():
+			return
+		}
+	}
+}
@@ -25,6 +25,8 @@ const (
 	AlertTypeSystem        AlertType = "system"
 	AlertTypeConnectivity  AlertType = "connectivity"
 	AlertTypePerformance   AlertType = "performance"
+	AlertTypeReport        AlertType = "report"
+	AlertTypeTest          AlertType = "test"
 )
 
 // AlertCondition defines a condition that triggers an alert
@@ -43,6 +45,8 @@ type AlertRule struct {
 	EventType     string                 `json:"event_type,omitempty"`
 	Severity      AlertSeverity          `json:"severity"`
 	Conditions    []AlertCondition       `json:"conditions"`
+	// ConditionGroup holds AND/OR logic over further conditions; it is ANDed with Conditions
+	ConditionGroup *ConditionGroup       `json:"condition_group,omitempty"`
 	Message       string                 `json:"message"`
 	TemplateID    string                 `json:"template_id,omitempty"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
@@ -0,0 +1,194 @@
+package api
+
+import (
+        "encoding/json"
+        "errors"
+        "fmt"
+        "net/http"
+        "strconv"
+        "strings"
+
+        "velocimex/internal/alerts"
+)
+
+// RegisterAlertHandlers registers the alert listing and lifecycle endpoints
+func RegisterAlertHandlers(router *http.ServeMux, manager *alerts.VelocimexAlertManager) {
+        const apiBase = "/api/v1"
+
+        router.HandleFunc(apiBase+"/alerts", func(w http.ResponseWriter, r *http.Request) {
+                if r.Method != http.MethodGet {
+                        writeMethodNotAllowed(w)
+                        return
+                }
+
+                filters, err := alertFilters(r)
+                if err != nil {
+                        writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
+                        return
+                }
+
+                list, err := manager.GetAlerts(filters)
+                if err != nil {
+                        writeError(w, http.StatusInternalServerError, ErrCodeInternal, err.Error())
+                        return
+                }
+                writeJSON(w, list)
+        })
+
+        // Sends a synthetic alert through one channel to check its delivery
+        router.HandleFunc(apiBase+"/alerts/test", func(w http.ResponseWriter, r *http.Request) {
+                if r.Method != http.MethodPost {
+                        writeMethodNotAllowed(w)
+                        return
+                }
+
+                var request alertTestRequest
+                if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+                        writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("Invalid test alert: %v", err))
+                        return
+                }
+                if request.Channel == "" {
+                        writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "Test alert requires a channel")
+                        return
+                }
+                switch request.Severity {
+                case "", alerts.SeverityLow, alerts.SeverityMedium, alerts.SeverityHigh, alerts.SeverityCritical:
+                default:
+                        writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("Unknown severity: %s", request.Severity))
+                        return
+                }
+
+                alert, err := manager.SendTestAlert(request.Channel, request.Message, request.Severity)
+                if errors.Is(err, alerts.ErrChannelNotFound) {
+                        writeError(w, http.StatusNotFound, ErrCodeNotFound, err.Error())
+                        return
+                }
+
+                // A failed delivery is the test's answer, not a failed request
+                result := alertTestResult{Channel: request.Channel, Delivered: err == nil, Alert: alert}
+                if err != nil {
+                        result.Error = err.Error()
+                }
+                writeJSON(w, result)
+        })
+
+        // /api/v1/alerts/{id}, /api/v1/alerts/{id}/ack and /api/v1/alerts/{id}/resolve
+        router.HandleFunc(apiBase+"/alerts/", func(w http.ResponseWriter, r *http.Request) {
+                parts := strings.Split(strings.TrimPrefix(r.URL.Path, apiBase+"/alerts/"), "/")
+                if parts[0] == "" || len(parts) > 2 {
+                        writeError(w, http.StatusNotFound, ErrCodeNotFound, "Not found")
+                        return
+                }
+                alertID := parts[0]
+
+                if len(parts) == 1 {
+                        if r.Method != http.MethodGet {
+                                writeMethodNotAllowed(w)
+                                return
+                        }
+                        alert, err := manager.GetAlert(alertID)
+                        if err != nil {
+                                writeAlertError(w, err)
+                                return
+                        }
+                        writeJSON(w, alert)
+                        return
+                }
+
+                if r.Method != http.MethodPost {
+                        writeMethodNotAllowed(w)
+                        return
+                }
+
+                var err error
+                switch parts[1] {
+                case "ack":
+                        err = manager.AcknowledgeAlert(alertID)
+                case "resolve":
+                        err = manager.ResolveAlert(alertID)
+                default:
+                        writeError(w, http.StatusNotFound, ErrCodeNotFound, "Not found")
+                        return
+                }
+                if err != nil {
+                        writeAlertError(w, err)
+                        return
+                }
+
+                alert, err := manager.GetAlert(alertID)
+                if err != nil {
+                        writeAlertError(w, err)
+                        return
+                }
+                writeJSON(w, alert)
+        })
+}
+
+// RegisterAlertMetricsHandler registers the alert engine metrics endpoint
+func RegisterAlertMetricsHandler(router *http.ServeMux, engine *alerts.AlertEngine) {
+        router.HandleFunc("/api/v1/alerts/metrics", func(w http.ResponseWriter, r *http.Request) {
+                if r.Method != http.MethodGet {
+                        writeMethodNotAllowed(w)
+                        return
+                }
+                writeJSON(w, engine.GetMetrics())
+        })
+}
+
+// alertTestRequest is the body of POST /api/v1/alerts/test
+type alertTestRequest struct {
+        Channel  string               `json:"channel"`
+        Message  string               `json:"message"`  // Optional; a default names the channel
+        Severity alerts.AlertSeverity `json:"severity"` // Optional; default low
+}
+
+// alertTestResult reports whether a test alert reached its channel
+type alertTestResult struct {
+        Channel   string        `json:"channel"`
+        Delivered bool          `json:"delivered"`
+        Error     string        `json:"error,omitempty"`
+        Alert     *alerts.Alert `json:"alert"`
+}
+
+// alertFilters builds alert manager filters from query parameters
+func alertFilters(r *http.Request) (map[string]interface{}, error) {
+        query := r.URL.Query()
+        filters := make(map[string]interface{})
+
+        if v := query.Get("type"); v != "" {
+                filters["type"] = alerts.AlertType(v)
+        }
+        if v := query.Get("severity"); v != "" {
+                filters["severity"] = alerts.AlertSeverity(v)
+        }
+        if v := query.Get("status"); v != "" {
+                filters["status"] = alerts.AlertStatus(v)
+        }
+        if v := query.Get("rule_id"); v != "" {
+                filters["rule_id"] = v
+        }
+        for _, key := range []string{"acknowledged", "resolved"} {
+                v := query.Get(key)
+                if v == "" {
+                        continue
+                }
+                b, err := strconv.ParseBool(v)
+                if err != nil {
+                        return nil, fmt.Errorf("invalid %s filter: %q", key, v)
+                }
+                filters[key] = b
+        }
+
+        return filters, nil
+}
+
+func writeAlertError(w http.ResponseWriter, err error) {
+        switch {
+        case errors.Is(err, alerts.ErrAlertNotFound):
+                writeError(w, http.StatusNotFound, ErrCodeNotFound, err.Error())
+        case errors.Is(err, alerts.ErrAlertResolved):
+                writeError(w, http.StatusConflict, ErrCodeConflict, err.Error())
+        default:
+                writeError(w, http.StatusInternalServerError, ErrCodeInternal, err.Error())
+        }
+}
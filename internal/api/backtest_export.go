@@ -0,0 +1,152 @@
+package api
+
+import (
+        "encoding/csv"
+        "encoding/json"
+        "errors"
+        "fmt"
+        "log"
+        "net/http"
+        "strings"
+        "time"
+
+        "github.com/shopspring/decimal"
+        "velocimex/internal/backtesting"
+)
+
+// backtestTradeColumns is the CSV header of a backtest trade export
+var backtestTradeColumns = []string{
+        "id", "symbol", "exchange", "side", "quantity", "entry_price", "exit_price",
+        "entry_time", "exit_time", "duration", "pnl", "pnl_pct", "commission", "slippage",
+        "strategy_id", "strategy_name", "metadata",
+}
+
+// handleBacktestResults serves /api/v1/backtesting/results/{id},
+// /api/v1/backtesting/results/{id}/trades?format=csv|json and
+// /api/v1/backtesting/results/{id}/sensitivity?multipliers=0.5,1,2
+func handleBacktestResults(w http.ResponseWriter, r *http.Request, backtestEngine backtesting.BacktestEngine) {
+        parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/backtesting/results/"), "/")
+        if parts[0] == "" || len(parts) > 2 || (len(parts) == 2 && parts[1] != "trades" && parts[1] != "sensitivity") {
+                writeError(w, http.StatusNotFound, ErrCodeNotFound, "Not found")
+                return
+        }
+        if r.Method != http.MethodGet {
+                writeMethodNotAllowed(w)
+                return
+        }
+
+        result, err := backtestEngine.GetResult(parts[0])
+        if err != nil {
+                if errors.Is(err, backtesting.ErrResultNotFound) {
+                        writeError(w, http.StatusNotFound, ErrCodeNotFound, err.Error())
+                } else {
+                        writeError(w, http.StatusInternalServerError, ErrCodeInternal, err.Error())
+                }
+                return
+        }
+
+        if len(parts) == 1 {
+                writeJSON(w, result)
+                return
+        }
+        if parts[1] == "sensitivity" {
+                writeCostSensitivity(w, r, result)
+                return
+        }
+
+        switch format := r.URL.Query().Get("format"); format {
+        case "", "json":
+                writeJSON(w, map[string]interface{}{
+                        "result_id": result.ID,
+                        "trades":    result.Trades,
+                        "count":     len(result.Trades),
+                })
+        case "csv":
+                writeBacktestTradesCSV(w, result)
+        default:
+                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("Unsupported format: %s", format))
+        }
+}
+
+// writeCostSensitivity reports a result re-priced under the comma-separated
+// cost multipliers in the query, or the default ones
+func writeCostSensitivity(w http.ResponseWriter, r *http.Request, result *backtesting.BacktestResult) {
+        var multipliers []decimal.Decimal
+        if param := r.URL.Query().Get("multipliers"); param != "" {
+                for _, value := range strings.Split(param, ",") {
+                        multiplier, err := decimal.NewFromString(strings.TrimSpace(value))
+                        if err != nil {
+                                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("Invalid multiplier: %s", value))
+                                return
+                        }
+                        multipliers = append(multipliers, multiplier)
+                }
+        }
+
+        scenarios, err := result.CostSensitivity(multipliers)
+        if err != nil {
+                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
+                return
+        }
+        writeJSON(w, map[string]interface{}{
+                "result_id": result.ID,
+                "scenarios": scenarios,
+        })
+}
+
+// writeBacktestTradesCSV streams a result's trades as CSV, one row per trade.
+// Decimals keep their exact string form so no precision is lost.
+func writeBacktestTradesCSV(w http.ResponseWriter, result *backtesting.BacktestResult) {
+        w.Header().Set("Content-Type", "text/csv")
+        w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"backtest-%s-trades.csv\"", result.ID))
+
+        writer := csv.NewWriter(w)
+        writer.Write(backtestTradeColumns)
+
+        for i, trade := range result.Trades {
+                metadata := ""
+                if len(trade.Metadata) > 0 {
+                        if data, err := json.Marshal(trade.Metadata); err == nil {
+                                metadata = string(data)
+                        }
+                }
+
+                writer.Write([]string{
+                        trade.ID,
+                        trade.Symbol,
+                        trade.Exchange,
+                        trade.Side,
+                        trade.Quantity.String(),
+                        trade.EntryPrice.String(),
+                        trade.ExitPrice.String(),
+                        formatExportTime(trade.EntryTime),
+                        formatExportTime(trade.ExitTime),
+                        trade.Duration.String(),
+                        trade.PnL.String(),
+                        trade.PnLPct.String(),
+                        trade.Commission.String(),
+                        trade.Slippage.String(),
+                        trade.StrategyID,
+                        trade.StrategyName,
+                        metadata,
+                })
+
+                // Flush periodically so large exports stream rather than buffer
+                if i%500 == 499 {
+                        writer.Flush()
+                }
+        }
+
+        writer.Flush()
+        if err := writer.Error(); err != nil {
+                log.Printf("Error writing backtest trades CSV: %v", err)
+        }
+}
+
+// formatExportTime formats a timestamp for export, leaving unset times empty
+func formatExportTime(t time.Time) string {
+        if t.IsZero() {
+                return ""
+        }
+        return t.UTC().Format(time.RFC3339Nano)
+}
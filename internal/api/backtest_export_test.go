@@ -0,0 +1,179 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/backtesting"
+	"velocimex/internal/strategy"
+)
+
+// runExportBacktest runs a rebalancing backtest over oscillating prices so it trades repeatedly
+func runExportBacktest(t *testing.T, s *testServer) *backtesting.BacktestResult {
+	t.Helper()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ticks := 30
+	config := backtesting.DefaultBacktestConfig()
+	config.StartDate = start
+	config.EndDate = start.Add(time.Duration(ticks) * time.Minute)
+	config.DataFrequency = time.Minute
+	config.Latency = 0
+	config.Symbols = []string{"BTC/USD"}
+	config.Exchanges = []string{"test"}
+	require.NoError(t, s.backtestEngine.SetConfig(config))
+
+	data := &backtesting.HistoricalData{
+		Symbol:    "BTC/USD",
+		Exchange:  "test",
+		StartTime: start,
+		EndTime:   config.EndDate,
+		Frequency: time.Minute,
+	}
+	for i := 0; i < ticks; i++ {
+		price := decimal.NewFromFloat(100.125)
+		if i%2 == 1 {
+			price = decimal.NewFromFloat(120.375)
+		}
+		data.DataPoints = append(data.DataPoints, &backtesting.DataPoint{
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Open:      price,
+			High:      price,
+			Low:       price,
+			Close:     price,
+			Volume:    decimal.NewFromInt(100),
+			Bid:       price,
+			Ask:       price,
+			BidSize:   decimal.NewFromInt(1000),
+			AskSize:   decimal.NewFromInt(1000),
+		})
+	}
+	require.NoError(t, s.backtestEngine.AddHistoricalData(data))
+
+	require.NoError(t, s.backtestEngine.RegisterStrategy(strategy.NewRebalanceStrategy(strategy.RebalanceConfig{
+		Exchange:       "test",
+		TargetWeights:  map[string]float64{"BTC/USD": 0.5},
+		DriftThreshold: 0.01,
+		InitialCash:    10000,
+	})))
+
+	rec := s.do(t, http.MethodPost, "/api/v1/backtesting/run", map[string]string{"strategy_id": "rebalance"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var run struct {
+		ID string `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &run))
+	require.NotEmpty(t, run.ID)
+
+	result, err := s.backtestEngine.GetResult(run.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Trades)
+	return result
+}
+
+func TestBacktestTradesExportCSV(t *testing.T) {
+	s := newTestServer(t)
+	result := runExportBacktest(t, s)
+
+	rec := s.do(t, http.MethodGet, "/api/v1/backtesting/results/"+result.ID+"/trades?format=csv", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), result.ID)
+
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, len(result.Trades)+1)
+	assert.Equal(t, backtestTradeColumns, rows[0])
+
+	column := make(map[string]int, len(rows[0]))
+	for i, name := range rows[0] {
+		column[name] = i
+	}
+	for i, trade := range result.Trades {
+		row := rows[i+1]
+		assert.Equal(t, trade.ID, row[column["id"]])
+		assert.Equal(t, trade.Side, row[column["side"]])
+		assert.Equal(t, trade.Quantity.String(), row[column["quantity"]])
+		assert.Equal(t, trade.EntryPrice.String(), row[column["entry_price"]])
+		assert.Equal(t, trade.Commission.String(), row[column["commission"]])
+		assert.Equal(t, trade.EntryTime.UTC().Format(time.RFC3339Nano), row[column["entry_time"]])
+	}
+}
+
+func TestBacktestTradesExportJSON(t *testing.T) {
+	s := newTestServer(t)
+	result := runExportBacktest(t, s)
+
+	rec := s.do(t, http.MethodGet, "/api/v1/backtesting/results/"+result.ID+"/trades", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		ResultID string                       `json:"result_id"`
+		Count    int                          `json:"count"`
+		Trades   []*backtesting.BacktestTrade `json:"trades"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, result.ID, body.ResultID)
+	assert.Equal(t, len(result.Trades), body.Count)
+	require.Len(t, body.Trades, len(result.Trades))
+	for i, trade := range result.Trades {
+		assert.Equal(t, trade.ID, body.Trades[i].ID)
+		assert.True(t, trade.Quantity.Equal(body.Trades[i].Quantity))
+		assert.True(t, trade.EntryPrice.Equal(body.Trades[i].EntryPrice))
+		assert.True(t, trade.EntryTime.Equal(body.Trades[i].EntryTime))
+	}
+
+	// Decimals are exported as exact strings rather than floats
+	assert.True(t, strings.Contains(rec.Body.String(), `"entry_price":"`))
+}
+
+func TestBacktestTradesExportErrors(t *testing.T) {
+	s := newTestServer(t)
+	result := runExportBacktest(t, s)
+
+	rec := s.do(t, http.MethodGet, "/api/v1/backtesting/results/missing/trades", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = s.do(t, http.MethodGet, "/api/v1/backtesting/results/"+result.ID+"/trades?format=xml", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = s.do(t, http.MethodPost, "/api/v1/backtesting/results/"+result.ID+"/trades", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestBacktestCostSensitivityEndpoint(t *testing.T) {
+	s := newTestServer(t)
+	result := runExportBacktest(t, s)
+
+	rec := s.do(t, http.MethodGet, "/api/v1/backtesting/results/"+result.ID+"/sensitivity?multipliers=0.5,1,2", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var body struct {
+		ResultID  string                     `json:"result_id"`
+		Scenarios []backtesting.CostScenario `json:"scenarios"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, result.ID, body.ResultID)
+	require.Len(t, body.Scenarios, 3)
+	assert.True(t, body.Scenarios[1].Multiplier.Equal(decimal.NewFromInt(1)))
+	assert.True(t, body.Scenarios[0].NetReturn.GreaterThanOrEqual(body.Scenarios[1].NetReturn))
+	assert.True(t, body.Scenarios[1].NetReturn.GreaterThanOrEqual(body.Scenarios[2].NetReturn))
+
+	rec = s.do(t, http.MethodGet, "/api/v1/backtesting/results/"+result.ID+"/sensitivity", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Len(t, body.Scenarios, len(backtesting.DefaultCostMultipliers))
+
+	rec = s.do(t, http.MethodGet, "/api/v1/backtesting/results/"+result.ID+"/sensitivity?multipliers=1,x", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = s.do(t, http.MethodGet, "/api/v1/backtesting/results/"+result.ID+"/sensitivity?multipliers=-1", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
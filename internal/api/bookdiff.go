@@ -0,0 +1,271 @@
+package api
+
+import (
+        "encoding/json"
+        "log"
+        "sort"
+        "sync"
+        "time"
+
+        "velocimex/internal/normalizer"
+)
+
+// defaultBookDiffDepth is the number of levels per side tracked by diff streams
+const defaultBookDiffDepth = 20
+
+// BookLevelChange describes a single price level change in an order book
+type BookLevelChange struct {
+        Side   string  `json:"side"`   // "bid" or "ask"
+        Action string  `json:"action"` // "add", "update" or "remove"
+        Price  float64 `json:"price"`
+        Volume float64 `json:"volume"`
+}
+
+// BookDiffMessage is sent to clients subscribed to incremental order book updates.
+// A client applies a snapshot, then every delta whose sequence is exactly one more
+// than the last applied; on a gap it should resubscribe for a fresh snapshot.
+// A subscription to a symbol without a book is answered with an error.
+type BookDiffMessage struct {
+        Channel   string                  `json:"channel"`
+        Type      string                  `json:"type"` // "snapshot", "delta" or "error"
+        Symbol    string                  `json:"symbol"`
+        Sequence  uint64                  `json:"sequence"`
+        Timestamp time.Time               `json:"timestamp"`
+        Bids      []normalizer.PriceLevel `json:"bids,omitempty"`
+        Asks      []normalizer.PriceLevel `json:"asks,omitempty"`
+        Changes   []BookLevelChange       `json:"changes,omitempty"`
+        Error     string                  `json:"error,omitempty"`
+}
+
+// bookStream holds the last published state of a symbol's book
+type bookStream struct {
+        mu       sync.Mutex
+        sequence uint64
+        bids     map[float64]float64
+        asks     map[float64]float64
+}
+
+func newBookStream() *bookStream {
+        return &bookStream{
+                bids: make(map[float64]float64),
+                asks: make(map[float64]float64),
+        }
+}
+
+// update replaces the stream state and returns the level changes
+func (bs *bookStream) update(bids, asks []normalizer.PriceLevel) []BookLevelChange {
+        changes := diffLevels("bid", bs.bids, bids)
+        changes = append(changes, diffLevels("ask", bs.asks, asks)...)
+
+        bs.bids = levelMap(bids)
+        bs.asks = levelMap(asks)
+        if len(changes) > 0 {
+                bs.sequence++
+        }
+
+        return changes
+}
+
+// snapshot returns the stream state as sorted price levels
+func (bs *bookStream) snapshot() ([]normalizer.PriceLevel, []normalizer.PriceLevel) {
+        bids := levelSlice(bs.bids)
+        asks := levelSlice(bs.asks)
+        sort.Slice(bids, func(i, j int) bool { return bids[i].Price > bids[j].Price })
+        sort.Slice(asks, func(i, j int) bool { return asks[i].Price < asks[j].Price })
+        return bids, asks
+}
+
+// diffLevels compares the previous levels of one side with the current ones
+func diffLevels(side string, previous map[float64]float64, current []normalizer.PriceLevel) []BookLevelChange {
+        changes := make([]BookLevelChange, 0)
+        seen := make(map[float64]bool, len(current))
+
+        for _, level := range current {
+                seen[level.Price] = true
+                volume, exists := previous[level.Price]
+                switch {
+                case !exists:
+                        changes = append(changes, BookLevelChange{Side: side, Action: "add", Price: level.Price, Volume: level.Volume})
+                case volume != level.Volume:
+                        changes = append(changes, BookLevelChange{Side: side, Action: "update", Price: level.Price, Volume: level.Volume})
+                }
+        }
+
+        for price := range previous {
+                if !seen[price] {
+                        changes = append(changes, BookLevelChange{Side: side, Action: "remove", Price: price})
+                }
+        }
+
+        return changes
+}
+
+func levelMap(levels []normalizer.PriceLevel) map[float64]float64 {
+        m := make(map[float64]float64, len(levels))
+        for _, level := range levels {
+                m[level.Price] = level.Volume
+        }
+        return m
+}
+
+func levelSlice(levels map[float64]float64) []normalizer.PriceLevel {
+        s := make([]normalizer.PriceLevel, 0, len(levels))
+        for price, volume := range levels {
+                s = append(s, normalizer.PriceLevel{Price: price, Volume: volume})
+        }
+        return s
+}
+
+// getBookStream returns the diff stream for a symbol, creating it if needed
+func (s *WebSocketServer) getBookStream(symbol string) *bookStream {
+        s.streamMu.Lock()
+        defer s.streamMu.Unlock()
+
+        stream, ok := s.bookStreams[symbol]
+        if !ok {
+                stream = newBookStream()
+                s.bookStreams[symbol] = stream
+        }
+        return stream
+}
+
+// lookupBookStream returns the diff stream for a symbol if a client has subscribed to it
+func (s *WebSocketServer) lookupBookStream(symbol string) (*bookStream, bool) {
+        s.streamMu.Lock()
+        defer s.streamMu.Unlock()
+
+        stream, ok := s.bookStreams[symbol]
+        return stream, ok
+}
+
+// subscribeBookDiff subscribes a client to incremental updates and sends it a
+// snapshot. Streams are only created for symbols that have a book, so clients
+// cannot grow the books or streams by naming arbitrary symbols.
+func (s *WebSocketServer) subscribeBookDiff(c *Client, symbol string) {
+        book, ok := s.orderBooks.LookupOrderBook(symbol)
+        if !ok {
+                s.rejectBookDiff(c, symbol)
+                return
+        }
+        stream := s.getBookStream(symbol)
+
+        // Hold the stream lock so no delta can be published between the
+        // snapshot and the subscription taking effect
+        stream.mu.Lock()
+        defer stream.mu.Unlock()
+
+        if stream.sequence == 0 {
+                bids, asks := book.GetDepth(defaultBookDiffDepth)
+                stream.update(bids, asks)
+        }
+
+        bids, asks := stream.snapshot()
+        msg := BookDiffMessage{
+                Channel:   "orderbook_diff",
+                Type:      "snapshot",
+                Symbol:    symbol,
+                Sequence:  stream.sequence,
+                Timestamp: time.Now(),
+                Bids:      bids,
+                Asks:      asks,
+        }
+
+        data, err := json.Marshal(msg)
+        if err != nil {
+                log.Printf("Failed to marshal book snapshot: %v", err)
+                return
+        }
+
+        c.mu.Lock()
+        c.diffSubs[symbol] = true
+        c.mu.Unlock()
+
+        c.sendMessage(data)
+}
+
+// rejectBookDiff tells a client that a symbol has no book to stream
+func (s *WebSocketServer) rejectBookDiff(c *Client, symbol string) {
+        data, err := json.Marshal(BookDiffMessage{
+                Channel:   "orderbook_diff",
+                Type:      "error",
+                Symbol:    symbol,
+                Timestamp: time.Now(),
+                Error:     "unknown symbol",
+        })
+        if err != nil {
+                log.Printf("Failed to marshal book diff error: %v", err)
+                return
+        }
+        c.sendMessage(data)
+}
+
+// PublishOrderBookDiff sends the changes in a symbol's book since the last
+// publish to every client subscribed to incremental updates for it. Symbols
+// nobody has subscribed to have no stream and are skipped.
+func (s *WebSocketServer) PublishOrderBookDiff(symbol string) {
+        stream, ok := s.lookupBookStream(symbol)
+        if !ok {
+                return
+        }
+        book, ok := s.orderBooks.LookupOrderBook(symbol)
+        if !ok {
+                return
+        }
+
+        stream.mu.Lock()
+        defer stream.mu.Unlock()
+
+        // A throttled diff is not lost: its changes are folded into the next one
+        if !s.allowTopic("orderbook_diff") {
+                return
+        }
+
+        bids, asks := book.GetDepth(defaultBookDiffDepth)
+        changes := stream.update(bids, asks)
+        if len(changes) == 0 {
+                return
+        }
+
+        msg := BookDiffMessage{
+                Channel:   "orderbook_diff",
+                Type:      "delta",
+                Symbol:    symbol,
+                Sequence:  stream.sequence,
+                Timestamp: time.Now(),
+                Changes:   changes,
+        }
+
+        data, err := json.Marshal(msg)
+        if err != nil {
+                log.Printf("Failed to marshal book delta: %v", err)
+                return
+        }
+
+        encoded := newEncodedMessage(data)
+        wireBytes := 0
+        s.mu.Lock()
+        for client := range s.clients {
+                client.mu.Lock()
+                subscribed := client.diffSubs[symbol]
+                client.mu.Unlock()
+                if subscribed && client.sendEncoded(encoded) {
+                        wireBytes += len(encoded.forEncoding(client.encoding))
+                }
+        }
+        s.mu.Unlock()
+        s.recordTopic("orderbook_diff", len(data), wireBytes)
+}
+
+// PublishOrderBookDiffs publishes diffs for every symbol with a diff stream
+func (s *WebSocketServer) PublishOrderBookDiffs() {
+        s.streamMu.Lock()
+        symbols := make([]string, 0, len(s.bookStreams))
+        for symbol := range s.bookStreams {
+                symbols = append(symbols, symbol)
+        }
+        s.streamMu.Unlock()
+
+        for _, symbol := range symbols {
+                s.PublishOrderBookDiff(symbol)
+        }
+}
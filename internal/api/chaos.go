@@ -0,0 +1,96 @@
+package api
+
+import (
+        "encoding/json"
+        "fmt"
+        "net/http"
+        "time"
+
+        "velocimex/internal/chaos"
+)
+
+// LatencySettings is the injected latency as exposed on the API, with
+// durations written like "50ms"
+type LatencySettings struct {
+        Enabled      bool   `json:"enabled"`
+        FeedLatency  string `json:"feed_latency"`
+        OrderLatency string `json:"order_latency"`
+        Jitter       string `json:"jitter"`
+}
+
+// latencyUpdate is a partial update, omitted fields keep their current values
+type latencyUpdate struct {
+        Enabled      *bool   `json:"enabled"`
+        FeedLatency  *string `json:"feed_latency"`
+        OrderLatency *string `json:"order_latency"`
+        Jitter       *string `json:"jitter"`
+}
+
+// RegisterChaosHandlers registers the latency injection toggle used for chaos testing
+func RegisterChaosHandlers(router *http.ServeMux, injector *chaos.LatencyInjector) {
+        const apiBase = "/api/v1"
+
+        router.HandleFunc(apiBase+"/chaos/latency", func(w http.ResponseWriter, r *http.Request) {
+                switch r.Method {
+                case http.MethodGet:
+                        writeJSON(w, latencySettings(injector.Config()))
+                case http.MethodPost, http.MethodPut:
+                        var update latencyUpdate
+                        if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+                                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("Invalid latency settings: %v", err))
+                                return
+                        }
+
+                        config, err := update.apply(injector.Config())
+                        if err == nil {
+                                err = injector.Set(config)
+                        }
+                        if err != nil {
+                                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("Invalid latency settings: %v", err))
+                                return
+                        }
+
+                        writeJSON(w, latencySettings(injector.Config()))
+                default:
+                        writeMethodNotAllowed(w)
+                }
+        })
+}
+
+func latencySettings(config chaos.LatencyConfig) LatencySettings {
+        return LatencySettings{
+                Enabled:      config.Enabled,
+                FeedLatency:  config.FeedLatency.String(),
+                OrderLatency: config.OrderLatency.String(),
+                Jitter:       config.Jitter.String(),
+        }
+}
+
+// apply returns config with the fields present in the update replaced
+func (u latencyUpdate) apply(config chaos.LatencyConfig) (chaos.LatencyConfig, error) {
+        if u.Enabled != nil {
+                config.Enabled = *u.Enabled
+        }
+
+        durations := []struct {
+                name  string
+                value *string
+                field *time.Duration
+        }{
+                {"feed_latency", u.FeedLatency, &config.FeedLatency},
+                {"order_latency", u.OrderLatency, &config.OrderLatency},
+                {"jitter", u.Jitter, &config.Jitter},
+        }
+        for _, d := range durations {
+                if d.value == nil {
+                        continue
+                }
+                duration, err := time.ParseDuration(*d.value)
+                if err != nil {
+                        return config, fmt.Errorf("%s: %v", d.name, err)
+                }
+                *d.field = duration
+        }
+
+        return config, nil
+}
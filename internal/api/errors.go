@@ -0,0 +1,98 @@
+package api
+
+import (
+        "encoding/json"
+        "errors"
+        "fmt"
+        "log"
+        "net/http"
+
+        "velocimex/internal/backtesting"
+        "velocimex/internal/orders"
+)
+
+// Error codes identify the kind of failure in an error response. They are
+// stable; clients should match on them rather than on messages.
+const (
+        ErrCodeInvalidRequest   = "invalid_request"   // Malformed body or a request that fails validation
+        ErrCodeInvalidParameter = "invalid_parameter" // A query parameter that cannot be parsed; details name it
+        ErrCodeUnauthorized     = "unauthorized"
+        ErrCodeNotFound         = "not_found"
+        ErrCodeMethodNotAllowed = "method_not_allowed"
+        ErrCodeConflict         = "conflict"
+        ErrCodeInternal         = "internal_error"
+        ErrCodeNotImplemented   = "not_implemented"
+        ErrCodeOrderRejected    = "order_rejected"  // Order failed pre-trade checks such as tick size or a stale book
+        ErrCodeTradingHalted    = "trading_halted"  // Trading is halted globally or for the order's symbol
+        ErrCodeLimitExceeded    = "limit_exceeded"  // Order breaches a size, value or count limit
+)
+
+// ErrorResponse is the JSON body of every REST API error
+type ErrorResponse struct {
+        Code    string      `json:"code"`
+        Message string      `json:"message"`
+        Details interface{} `json:"details,omitempty"`
+}
+
+// writeError writes an error response with the given status and code
+func writeError(w http.ResponseWriter, status int, code, message string) {
+        writeErrorDetails(w, status, code, message, nil)
+}
+
+// writeErrorDetails writes an error response carrying details about the failure
+func writeErrorDetails(w http.ResponseWriter, status int, code, message string, details interface{}) {
+        w.Header().Set("Content-Type", "application/json")
+        w.Header().Set("X-Content-Type-Options", "nosniff")
+        w.WriteHeader(status)
+        if err := json.NewEncoder(w).Encode(ErrorResponse{Code: code, Message: message, Details: details}); err != nil {
+                log.Printf("Error encoding error response: %v", err)
+        }
+}
+
+// writeMethodNotAllowed rejects a request using an unsupported HTTP method
+func writeMethodNotAllowed(w http.ResponseWriter) {
+        writeError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed, "Method not allowed")
+}
+
+// writeInvalidParameter rejects a request with a query parameter that cannot be parsed
+func writeInvalidParameter(w http.ResponseWriter, parameter, message string) {
+        writeErrorDetails(w, http.StatusBadRequest, ErrCodeInvalidParameter, message, map[string]string{"parameter": parameter})
+}
+
+// writeOrderError maps an order manager error to a response, so that orders
+// rejected by pre-trade checks are reported as client errors rather than 500s
+func writeOrderError(w http.ResponseWriter, action string, err error) {
+        message := fmt.Sprintf("Failed to %s: %v", action, err)
+        switch {
+        case errors.Is(err, orders.ErrInvalidOrder), errors.Is(err, orders.ErrInvalidSpread):
+                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, message)
+        case errors.Is(err, orders.ErrOrderNotFound):
+                writeError(w, http.StatusNotFound, ErrCodeNotFound, message)
+        case errors.Is(err, orders.ErrNotCancellable):
+                writeError(w, http.StatusConflict, ErrCodeConflict, message)
+        case errors.Is(err, orders.ErrTradingHalted), errors.Is(err, orders.ErrSymbolHalted):
+                writeError(w, http.StatusConflict, ErrCodeTradingHalted, message)
+        case errors.Is(err, orders.ErrDailyOrderLimit), errors.Is(err, orders.ErrMaxOpenOrders),
+                errors.Is(err, orders.ErrMaxOrderValue), errors.Is(err, orders.ErrBelowMinQuantity),
+                errors.Is(err, orders.ErrAboveMaxQuantity), errors.Is(err, orders.ErrBelowMinNotional),
+                errors.Is(err, orders.ErrAboveMaxNotional):
+                writeError(w, http.StatusUnprocessableEntity, ErrCodeLimitExceeded, message)
+        case errors.Is(err, orders.ErrInvalidTickSize), errors.Is(err, orders.ErrInvalidLotSize),
+                errors.Is(err, orders.ErrStaleOrderBook), errors.Is(err, orders.ErrNoQuotePrice),
+                errors.Is(err, orders.ErrNoNotionalPrice), errors.Is(err, orders.ErrLatencyBudget):
+                writeError(w, http.StatusUnprocessableEntity, ErrCodeOrderRejected, message)
+        default:
+                writeError(w, http.StatusInternalServerError, ErrCodeInternal, message)
+        }
+}
+
+// writeConfigError reports a backtest configuration the engine refused as an
+// invalid parameter; any other failure applying it is internal
+func writeConfigError(w http.ResponseWriter, action string, err error) {
+        message := fmt.Sprintf("%s: %v", action, err)
+        if errors.Is(err, backtesting.ErrInvalidConfig) {
+                writeErrorDetails(w, http.StatusBadRequest, ErrCodeInvalidParameter, message, map[string]string{"parameter": "config"})
+                return
+        }
+        writeError(w, http.StatusInternalServerError, ErrCodeInternal, message)
+}
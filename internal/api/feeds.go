@@ -0,0 +1,44 @@
+package api
+
+import (
+        "net/http"
+        "time"
+
+        "velocimex/internal/feeds"
+)
+
+// FeedHealthProvider reports the health of the market data feeds
+type FeedHealthProvider interface {
+        Health() []feeds.FeedHealth
+}
+
+// FeedHealthReport is the health of every configured feed
+type FeedHealthReport struct {
+        Feeds     []feeds.FeedHealth `json:"feeds"`
+        Total     int                `json:"total"`
+        Healthy   int                `json:"healthy"` // Connected and not stale
+        Timestamp time.Time          `json:"timestamp"`
+}
+
+// RegisterFeedHandlers registers the feed health endpoint
+func RegisterFeedHandlers(router *http.ServeMux, provider FeedHealthProvider) {
+        const apiBase = "/api/v1"
+
+        router.HandleFunc(apiBase+"/feeds/health", func(w http.ResponseWriter, r *http.Request) {
+                if r.Method != http.MethodGet {
+                        writeMethodNotAllowed(w)
+                        return
+                }
+                writeJSON(w, feedHealthReport(provider.Health(), time.Now()))
+        })
+}
+
+func feedHealthReport(health []feeds.FeedHealth, now time.Time) FeedHealthReport {
+        report := FeedHealthReport{Feeds: health, Total: len(health), Timestamp: now}
+        for _, feed := range health {
+                if feed.State == feeds.FeedStateConnected {
+                        report.Healthy++
+                }
+        }
+        return report
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+        "net/http"
+        "time"
+
+        "velocimex/internal/orders"
+)
+
+// TradingController halts and resumes order submission
+type TradingController interface {
+        Halt(reason string)
+        Resume()
+        IsHalted() (bool, string)
+}
+
+// TradingStatus reports whether trading is halted and when the operator was last seen
+type TradingStatus struct {
+        Halted        bool      `json:"halted"`
+        Reason        string    `json:"reason,omitempty"`
+        LastHeartbeat time.Time `json:"last_heartbeat,omitempty"`
+}
+
+// RegisterHeartbeatHandlers registers the operator heartbeat and trading halt endpoints
+func RegisterHeartbeatHandlers(router *http.ServeMux, watchdog *orders.HeartbeatWatchdog, trading TradingController) {
+        const apiBase = "/api/v1"
+
+        router.HandleFunc(apiBase+"/heartbeat", func(w http.ResponseWriter, r *http.Request) {
+                if r.Method != http.MethodPost {
+                        writeMethodNotAllowed(w)
+                        return
+                }
+                if watchdog != nil {
+                        watchdog.Heartbeat()
+                }
+                writeJSON(w, tradingStatus(watchdog, trading))
+        })
+
+        router.HandleFunc(apiBase+"/trading/status", func(w http.ResponseWriter, r *http.Request) {
+                if r.Method != http.MethodGet {
+                        writeMethodNotAllowed(w)
+                        return
+                }
+                writeJSON(w, tradingStatus(watchdog, trading))
+        })
+
+        router.HandleFunc(apiBase+"/trading/resume", func(w http.ResponseWriter, r *http.Request) {
+                if r.Method != http.MethodPost {
+                        writeMethodNotAllowed(w)
+                        return
+                }
+                // Resuming counts as a heartbeat so the watchdog does not trip again immediately
+                if watchdog != nil {
+                        watchdog.Heartbeat()
+                }
+                trading.Resume()
+                writeJSON(w, tradingStatus(watchdog, trading))
+        })
+}
+
+func tradingStatus(watchdog *orders.HeartbeatWatchdog, trading TradingController) TradingStatus {
+        halted, reason := trading.IsHalted()
+        status := TradingStatus{Halted: halted, Reason: reason}
+        if watchdog != nil {
+                status.LastHeartbeat = watchdog.LastHeartbeat()
+        }
+        return status
+}
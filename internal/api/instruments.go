@@ -0,0 +1,47 @@
+package api
+
+import (
+        "errors"
+        "net/http"
+        "strings"
+
+        "velocimex/internal/instruments"
+)
+
+// RegisterInstrumentHandlers registers the instrument metadata endpoints
+func RegisterInstrumentHandlers(router *http.ServeMux, store *instruments.Store) {
+        const apiBase = "/api/v1"
+
+        router.HandleFunc(apiBase+"/instruments", func(w http.ResponseWriter, r *http.Request) {
+                if r.Method != http.MethodGet {
+                        writeMethodNotAllowed(w)
+                        return
+                }
+                writeJSON(w, store.List())
+        })
+
+        router.HandleFunc(apiBase+"/instruments/", func(w http.ResponseWriter, r *http.Request) {
+                if r.Method != http.MethodGet {
+                        writeMethodNotAllowed(w)
+                        return
+                }
+
+                // Canonical symbols such as BTC/USD contain a slash, so take the rest of the path
+                symbol := strings.TrimPrefix(r.URL.Path, apiBase+"/instruments/")
+                if symbol == "" {
+                        writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "Symbol required")
+                        return
+                }
+
+                instrument, err := store.Get(symbol)
+                if err != nil {
+                        if errors.Is(err, instruments.ErrUnknownInstrument) {
+                                writeError(w, http.StatusNotFound, ErrCodeNotFound, err.Error())
+                                return
+                        }
+                        writeError(w, http.StatusInternalServerError, ErrCodeInternal, err.Error())
+                        return
+                }
+                writeJSON(w, instrument)
+        })
+}
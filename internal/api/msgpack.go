@@ -0,0 +1,328 @@
+package api
+
+import (
+        "bytes"
+        "encoding/binary"
+        "encoding/json"
+        "fmt"
+        "math"
+        "sort"
+)
+
+// Minimal MessagePack codec covering the values produced by decoding JSON:
+// nil, bool, integers, floats, strings, arrays and string-keyed maps.
+// Binary data is also decoded so clients may send it, but is never produced.
+
+// jsonToMsgpack transcodes a JSON message to MessagePack
+func jsonToMsgpack(data []byte) ([]byte, error) {
+        decoder := json.NewDecoder(bytes.NewReader(data))
+        decoder.UseNumber()
+
+        var value interface{}
+        if err := decoder.Decode(&value); err != nil {
+                return nil, err
+        }
+
+        var buf bytes.Buffer
+        if err := msgpackEncode(&buf, value); err != nil {
+                return nil, err
+        }
+        return buf.Bytes(), nil
+}
+
+// msgpackToJSON transcodes a MessagePack message to JSON
+func msgpackToJSON(data []byte) ([]byte, error) {
+        value, err := msgpackDecode(data)
+        if err != nil {
+                return nil, err
+        }
+        return json.Marshal(value)
+}
+
+// msgpackDecode decodes a single MessagePack value
+func msgpackDecode(data []byte) (interface{}, error) {
+        d := &msgpackDecoder{data: data}
+        value, err := d.decode()
+        if err != nil {
+                return nil, err
+        }
+        if d.pos != len(data) {
+                return nil, fmt.Errorf("msgpack: %d trailing bytes", len(data)-d.pos)
+        }
+        return value, nil
+}
+
+// msgpackEncode appends the MessagePack encoding of a value to buf
+func msgpackEncode(buf *bytes.Buffer, value interface{}) error {
+        switch v := value.(type) {
+        case nil:
+                buf.WriteByte(0xc0)
+        case bool:
+                if v {
+                        buf.WriteByte(0xc3)
+                } else {
+                        buf.WriteByte(0xc2)
+                }
+        case json.Number:
+                if i, err := v.Int64(); err == nil {
+                        msgpackEncodeInt(buf, i)
+                        return nil
+                }
+                f, err := v.Float64()
+                if err != nil {
+                        return fmt.Errorf("msgpack: invalid number %q", v)
+                }
+                msgpackEncodeFloat(buf, f)
+        case int:
+                msgpackEncodeInt(buf, int64(v))
+        case int64:
+                msgpackEncodeInt(buf, v)
+        case float64:
+                msgpackEncodeFloat(buf, v)
+        case string:
+                msgpackEncodeLength(buf, len(v), 0xa0, 32, 0xd9, 0xda, 0xdb)
+                buf.WriteString(v)
+        case []interface{}:
+                msgpackEncodeLength(buf, len(v), 0x90, 16, 0, 0xdc, 0xdd)
+                for _, item := range v {
+                        if err := msgpackEncode(buf, item); err != nil {
+                                return err
+                        }
+                }
+        case map[string]interface{}:
+                // Keys are sorted so the same message always encodes to the same bytes
+                keys := make([]string, 0, len(v))
+                for key := range v {
+                        keys = append(keys, key)
+                }
+                sort.Strings(keys)
+
+                msgpackEncodeLength(buf, len(v), 0x80, 16, 0, 0xde, 0xdf)
+                for _, key := range keys {
+                        msgpackEncode(buf, key)
+                        if err := msgpackEncode(buf, v[key]); err != nil {
+                                return err
+                        }
+                }
+        default:
+                return fmt.Errorf("msgpack: unsupported type %T", value)
+        }
+        return nil
+}
+
+// msgpackEncodeInt writes an integer in its smallest encoding
+func msgpackEncodeInt(buf *bytes.Buffer, v int64) {
+        switch {
+        case v >= 0 && v < 128:
+                buf.WriteByte(byte(v))
+        case v >= -32 && v < 0:
+                buf.WriteByte(byte(v))
+        case v >= 0 && v <= math.MaxUint8:
+                buf.Write([]byte{0xcc, byte(v)})
+        case v >= 0 && v <= math.MaxUint16:
+                buf.WriteByte(0xcd)
+                binary.Write(buf, binary.BigEndian, uint16(v))
+        case v >= 0 && v <= math.MaxUint32:
+                buf.WriteByte(0xce)
+                binary.Write(buf, binary.BigEndian, uint32(v))
+        case v >= 0:
+                buf.WriteByte(0xcf)
+                binary.Write(buf, binary.BigEndian, uint64(v))
+        case v >= math.MinInt8:
+                buf.Write([]byte{0xd0, byte(v)})
+        case v >= math.MinInt16:
+                buf.WriteByte(0xd1)
+                binary.Write(buf, binary.BigEndian, int16(v))
+        case v >= math.MinInt32:
+                buf.WriteByte(0xd2)
+                binary.Write(buf, binary.BigEndian, int32(v))
+        default:
+                buf.WriteByte(0xd3)
+                binary.Write(buf, binary.BigEndian, v)
+        }
+}
+
+// msgpackEncodeFloat writes a float64
+func msgpackEncodeFloat(buf *bytes.Buffer, v float64) {
+        buf.WriteByte(0xcb)
+        binary.Write(buf, binary.BigEndian, math.Float64bits(v))
+}
+
+// msgpackEncodeLength writes a string, array or map header. The fixed format
+// holds lengths below fixedLimit; short8 is zero for types without an 8-bit form.
+func msgpackEncodeLength(buf *bytes.Buffer, n int, fixed byte, fixedLimit int, short8, short16, short32 byte) {
+        switch {
+        case n < fixedLimit:
+                buf.WriteByte(fixed | byte(n))
+        case short8 != 0 && n <= math.MaxUint8:
+                buf.Write([]byte{short8, byte(n)})
+        case n <= math.MaxUint16:
+                buf.WriteByte(short16)
+                binary.Write(buf, binary.BigEndian, uint16(n))
+        default:
+                buf.WriteByte(short32)
+                binary.Write(buf, binary.BigEndian, uint32(n))
+        }
+}
+
+// msgpackDecoder reads MessagePack values from a buffer
+type msgpackDecoder struct {
+        data []byte
+        pos  int
+}
+
+// next returns the next n bytes
+func (d *msgpackDecoder) next(n int) ([]byte, error) {
+        if n < 0 || d.pos+n > len(d.data) {
+                return nil, fmt.Errorf("msgpack: unexpected end of data")
+        }
+        b := d.data[d.pos : d.pos+n]
+        d.pos += n
+        return b, nil
+}
+
+// uint reads an n-byte big-endian unsigned integer
+func (d *msgpackDecoder) uint(n int) (uint64, error) {
+        b, err := d.next(n)
+        if err != nil {
+                return 0, err
+        }
+        var v uint64
+        for _, c := range b {
+                v = v<<8 | uint64(c)
+        }
+        return v, nil
+}
+
+// decode reads one value
+func (d *msgpackDecoder) decode() (interface{}, error) {
+        b, err := d.next(1)
+        if err != nil {
+                return nil, err
+        }
+        c := b[0]
+
+        switch {
+        case c <= 0x7f:
+                return int64(c), nil
+        case c >= 0xe0:
+                return int64(int8(c)), nil
+        case c&0xe0 == 0xa0:
+                return d.str(int(c & 0x1f))
+        case c&0xf0 == 0x90:
+                return d.array(int(c & 0x0f))
+        case c&0xf0 == 0x80:
+                return d.mapping(int(c & 0x0f))
+        }
+
+        switch c {
+        case 0xc0:
+                return nil, nil
+        case 0xc2:
+                return false, nil
+        case 0xc3:
+                return true, nil
+        case 0xc4, 0xc5, 0xc6:
+                n, err := d.uint(1 << (c - 0xc4))
+                if err != nil {
+                        return nil, err
+                }
+                raw, err := d.next(int(n))
+                if err != nil {
+                        return nil, err
+                }
+                return append([]byte(nil), raw...), nil
+        case 0xca:
+                v, err := d.uint(4)
+                return float64(math.Float32frombits(uint32(v))), err
+        case 0xcb:
+                v, err := d.uint(8)
+                return math.Float64frombits(v), err
+        case 0xcc, 0xcd, 0xce:
+                v, err := d.uint(1 << (c - 0xcc))
+                return int64(v), err
+        case 0xcf:
+                v, err := d.uint(8)
+                if v > math.MaxInt64 {
+                        return v, err
+                }
+                return int64(v), err
+        case 0xd0:
+                v, err := d.uint(1)
+                return int64(int8(v)), err
+        case 0xd1:
+                v, err := d.uint(2)
+                return int64(int16(v)), err
+        case 0xd2:
+                v, err := d.uint(4)
+                return int64(int32(v)), err
+        case 0xd3:
+                v, err := d.uint(8)
+                return int64(v), err
+        case 0xd9, 0xda, 0xdb:
+                n, err := d.uint(1 << (c - 0xd9))
+                if err != nil {
+                        return nil, err
+                }
+                return d.str(int(n))
+        case 0xdc, 0xdd:
+                n, err := d.uint(2 << (c - 0xdc))
+                if err != nil {
+                        return nil, err
+                }
+                return d.array(int(n))
+        case 0xde, 0xdf:
+                n, err := d.uint(2 << (c - 0xde))
+                if err != nil {
+                        return nil, err
+                }
+                return d.mapping(int(n))
+        }
+        return nil, fmt.Errorf("msgpack: unsupported type byte 0x%02x", c)
+}
+
+func (d *msgpackDecoder) str(n int) (interface{}, error) {
+        b, err := d.next(n)
+        if err != nil {
+                return nil, err
+        }
+        return string(b), nil
+}
+
+func (d *msgpackDecoder) array(n int) (interface{}, error) {
+        if n > len(d.data)-d.pos {
+                return nil, fmt.Errorf("msgpack: unexpected end of data")
+        }
+        items := make([]interface{}, n)
+        for i := range items {
+                item, err := d.decode()
+                if err != nil {
+                        return nil, err
+                }
+                items[i] = item
+        }
+        return items, nil
+}
+
+func (d *msgpackDecoder) mapping(n int) (interface{}, error) {
+        if n > len(d.data)-d.pos {
+                return nil, fmt.Errorf("msgpack: unexpected end of data")
+        }
+        m := make(map[string]interface{}, n)
+        for i := 0; i < n; i++ {
+                key, err := d.decode()
+                if err != nil {
+                        return nil, err
+                }
+                name, ok := key.(string)
+                if !ok {
+                        return nil, fmt.Errorf("msgpack: map key is %T, not a string", key)
+                }
+                value, err := d.decode()
+                if err != nil {
+                        return nil, err
+                }
+                m[name] = value
+        }
+        return m, nil
+}
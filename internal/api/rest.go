@@ -1,7 +1,9 @@
 package api
 
 import (
+        "context"
         "encoding/json"
+        "errors"
         "fmt"
         "log"
         "net/http"
@@ -9,6 +11,8 @@ import (
         "strings"
         "time"
 
+        "github.com/shopspring/decimal"
+
         "velocimex/internal/backtesting"
         "velocimex/internal/normalizer"
         "velocimex/internal/orderbook"
@@ -28,21 +32,51 @@ func RegisterRESTHandlers(router *http.ServeMux, bookManager *orderbook.Manager,
                 handleOrderBooks(w, r, bookManager)
         })
 
+        router.HandleFunc(apiBase+"/orderbooks/liquidity", func(w http.ResponseWriter, r *http.Request) {
+                handleOrderBookLiquidity(w, r, bookManager)
+        })
+
+        router.HandleFunc(apiBase+"/orderbooks/depth-chart", func(w http.ResponseWriter, r *http.Request) {
+                handleOrderBookDepthChart(w, r, bookManager)
+        })
+
         // Strategy endpoints
         router.HandleFunc(apiBase+"/strategies", func(w http.ResponseWriter, r *http.Request) {
                 handleStrategies(w, r, strategyEngine)
         })
 
+        router.HandleFunc(apiBase+"/strategies/leaderboard", func(w http.ResponseWriter, r *http.Request) {
+                handleStrategyLeaderboard(w, r, strategyEngine, backtestEngine)
+        })
+
+        router.HandleFunc(apiBase+"/strategies/", func(w http.ResponseWriter, r *http.Request) {
+                if name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, apiBase+"/strategies/"), "/current-signals"); ok {
+                        handleStrategyCurrentSignals(w, r, strategyEngine, name)
+                        return
+                }
+                handleStrategies(w, r, strategyEngine)
+        })
+
         // Arbitrage opportunities endpoint
         router.HandleFunc(apiBase+"/arbitrage", func(w http.ResponseWriter, r *http.Request) {
                 handleArbitrage(w, r, strategyEngine)
         })
 
+        // Arbitrage detection thresholds endpoint
+        router.HandleFunc(apiBase+"/arbitrage/config", func(w http.ResponseWriter, r *http.Request) {
+                handleArbitrageConfig(w, r, strategyEngine)
+        })
+
         // Market summary endpoint
         router.HandleFunc(apiBase+"/markets", func(w http.ResponseWriter, r *http.Request) {
                 handleMarkets(w, r, bookManager)
         })
 
+        // Cross-exchange index price endpoint
+        router.HandleFunc(apiBase+"/markets/index", func(w http.ResponseWriter, r *http.Request) {
+                handleMarketIndex(w, r, bookManager)
+        })
+
         // Order management endpoints
         router.HandleFunc(apiBase+"/orders", func(w http.ResponseWriter, r *http.Request) {
                 handleOrders(w, r, orderManager)
@@ -51,15 +85,32 @@ func RegisterRESTHandlers(router *http.ServeMux, bookManager *orderbook.Manager,
         router.HandleFunc(apiBase+"/orders/", func(w http.ResponseWriter, r *http.Request) {
                 handleOrderByID(w, r, orderManager)
         })
+
+        if canceller, ok := orderManager.(BulkCanceller); ok {
+                router.HandleFunc(apiBase+"/orders/cancel-by-tag", func(w http.ResponseWriter, r *http.Request) {
+                        handleCancelOrdersByTag(w, r, canceller)
+                })
+        }
         
         router.HandleFunc(apiBase+"/positions", func(w http.ResponseWriter, r *http.Request) {
                 handlePositions(w, r, orderManager)
         })
+
+        if closer, ok := orderManager.(PositionCloser); ok {
+                router.HandleFunc(apiBase+"/positions/close-all", func(w http.ResponseWriter, r *http.Request) {
+                        handleCloseAllPositions(w, r, closer)
+                })
+        }
         
         router.HandleFunc(apiBase+"/executions", func(w http.ResponseWriter, r *http.Request) {
                 handleExecutions(w, r, orderManager)
         })
         
+        // Account snapshot endpoint
+        router.HandleFunc(apiBase+"/account/snapshot", func(w http.ResponseWriter, r *http.Request) {
+                handleAccountSnapshot(w, r, orderManager, riskManager)
+        })
+        
         // Risk management endpoints
         router.HandleFunc(apiBase+"/risk/portfolio", func(w http.ResponseWriter, r *http.Request) {
                 handleRiskPortfolio(w, r, riskManager)
@@ -76,6 +127,18 @@ func RegisterRESTHandlers(router *http.ServeMux, bookManager *orderbook.Manager,
         router.HandleFunc(apiBase+"/risk/positions", func(w http.ResponseWriter, r *http.Request) {
                 handleRiskPositions(w, r, riskManager)
         })
+
+        if tester, ok := riskManager.(StressTester); ok {
+                router.HandleFunc(apiBase+"/risk/stress", func(w http.ResponseWriter, r *http.Request) {
+                        handleRiskStress(w, r, tester)
+                })
+        }
+
+        if provider, ok := riskManager.(EquityCurveProvider); ok {
+                router.HandleFunc(apiBase+"/risk/equity-curve", func(w http.ResponseWriter, r *http.Request) {
+                        handleRiskEquityCurve(w, r, provider)
+                })
+        }
         
         // Backtesting endpoints
         router.HandleFunc(apiBase+"/backtesting/run", func(w http.ResponseWriter, r *http.Request) {
@@ -94,6 +157,14 @@ func RegisterRESTHandlers(router *http.ServeMux, bookManager *orderbook.Manager,
                 handleBacktestConfig(w, r, backtestEngine)
         })
         
+        router.HandleFunc(apiBase+"/backtesting/progress", func(w http.ResponseWriter, r *http.Request) {
+                handleBacktestProgress(w, r, backtestEngine)
+        })
+        
+        router.HandleFunc(apiBase+"/backtesting/results/", func(w http.ResponseWriter, r *http.Request) {
+                handleBacktestResults(w, r, backtestEngine)
+        })
+        
         // Plugin management endpoints
         router.HandleFunc(apiBase+"/plugins", func(w http.ResponseWriter, r *http.Request) {
                 handlePlugins(w, r, pluginManager)
@@ -130,7 +201,18 @@ func handleOrderBooks(w http.ResponseWriter, r *http.Request, bookManager *order
                         var err error
                         depth, err = strconv.Atoi(depthStr)
                         if err != nil || depth <= 0 {
-                                http.Error(w, "Invalid depth parameter", http.StatusBadRequest)
+                                writeInvalidParameter(w, "depth", "Invalid depth parameter")
+                                return
+                        }
+                }
+
+                // Optionally coalesce levels into price bands of this width
+                var band float64
+                if bandStr := r.URL.Query().Get("band"); bandStr != "" {
+                        var err error
+                        band, err = strconv.ParseFloat(bandStr, 64)
+                        if err != nil || band <= 0 {
+                                writeInvalidParameter(w, "band", "Invalid band parameter")
                                 return
                         }
                 }
@@ -139,19 +221,33 @@ func handleOrderBooks(w http.ResponseWriter, r *http.Request, bookManager *order
                 if symbol != "" {
                         book := bookManager.GetOrderBook(symbol)
                         if book == nil {
-                                http.Error(w, "Order book not found", http.StatusNotFound)
+                                writeError(w, http.StatusNotFound, ErrCodeNotFound, "Order book not found")
                                 return
                         }
 
-                        bids, asks := book.GetDepth(depth)
+                        var bids, asks []normalizer.PriceLevel
+                        if band > 0 {
+                                var err error
+                                bids, asks, err = book.GetBandedDepth(band, depth)
+                                if err != nil {
+                                        writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
+                                        return
+                                }
+                        } else {
+                                bids, asks = book.GetDepth(depth)
+                        }
                         response := struct {
                                 Symbol    string                   `json:"symbol"`
                                 Timestamp string                   `json:"timestamp"`
+                                Band      float64                  `json:"band,omitempty"`
+                                Stale     bool                     `json:"stale,omitempty"`
                                 Bids      []normalizer.PriceLevel `json:"bids"`
                                 Asks      []normalizer.PriceLevel `json:"asks"`
                         }{
                                 Symbol:    symbol,
                                 Timestamp: book.GetTimestamp().Format("2006-01-02T15:04:05.999999Z07:00"),
+                                Band:      band,
+                                Stale:     book.IsStale(),
                                 Bids:      bids,
                                 Asks:      asks,
                         }
@@ -167,8 +263,78 @@ func handleOrderBooks(w http.ResponseWriter, r *http.Request, bookManager *order
                 })
 
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
+        }
+}
+
+// handleOrderBookDepthChart returns the cumulative bid and ask volume of a
+// symbol, across every exchange unless one is given, for plotting a depth chart
+func handleOrderBookDepthChart(w http.ResponseWriter, r *http.Request, bookManager *orderbook.Manager) {
+        if r.Method != http.MethodGet {
+                writeMethodNotAllowed(w)
+                return
+        }
+
+        symbol := r.URL.Query().Get("symbol")
+        if symbol == "" {
+                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "symbol is required")
+                return
+        }
+
+        // All price levels unless a depth is given
+        var depth int
+        if depthStr := r.URL.Query().Get("depth"); depthStr != "" {
+                var err error
+                depth, err = strconv.Atoi(depthStr)
+                if err != nil || depth <= 0 {
+                        writeInvalidParameter(w, "depth", "Invalid depth parameter")
+                        return
+                }
+        }
+
+        chart, ok := bookManager.GetDepthChart(symbol, r.URL.Query().Get("exchange"), depth)
+        if !ok {
+                writeError(w, http.StatusNotFound, ErrCodeNotFound, "Order book not found")
+                return
+        }
+        writeJSON(w, chart)
+}
+
+// handleOrderBookLiquidity handles requests for the liquidity scores of a
+// symbol's books, most liquid first, for choosing a venue
+func handleOrderBookLiquidity(w http.ResponseWriter, r *http.Request, bookManager *orderbook.Manager) {
+        if r.Method != http.MethodGet {
+                writeMethodNotAllowed(w)
+                return
+        }
+
+        symbol := r.URL.Query().Get("symbol")
+        if symbol == "" {
+                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "symbol is required")
+                return
+        }
+
+        config := orderbook.DefaultLiquidityConfig()
+        if bpsStr := r.URL.Query().Get("bps"); bpsStr != "" {
+                bps, err := strconv.ParseFloat(bpsStr, 64)
+                if err != nil || bps <= 0 {
+                        writeInvalidParameter(w, "bps", "Invalid bps parameter")
+                        return
+                }
+                config.DepthBps = bps
+        }
+
+        scores := bookManager.LiquidityScores(symbol, config)
+        if len(scores) == 0 {
+                writeError(w, http.StatusNotFound, ErrCodeNotFound, "Order book not found")
+                return
         }
+
+        writeJSON(w, map[string]interface{}{
+                "symbol":    symbol,
+                "depth_bps": config.DepthBps,
+                "books":     scores,
+        })
 }
 
 // handleStrategies handles requests for strategy data
@@ -188,7 +354,7 @@ func handleStrategies(w http.ResponseWriter, r *http.Request, strategyEngine *st
                 strategyName := strings.TrimPrefix(path, "/")
                 strategy, exists := strategyEngine.GetStrategy(strategyName)
                 if !exists {
-                        http.Error(w, "Strategy not found", http.StatusNotFound)
+                        writeError(w, http.StatusNotFound, ErrCodeNotFound, "Strategy not found")
                         return
                 }
 
@@ -204,20 +370,20 @@ func handleStrategies(w http.ResponseWriter, r *http.Request, strategyEngine *st
                 }
 
                 if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-                        http.Error(w, "Invalid request", http.StatusBadRequest)
+                        writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request")
                         return
                 }
 
                 strategy, exists := strategyEngine.GetStrategy(request.Name)
                 if !exists {
-                        http.Error(w, "Strategy not found", http.StatusNotFound)
+                        writeError(w, http.StatusNotFound, ErrCodeNotFound, "Strategy not found")
                         return
                 }
 
                 switch request.Action {
                 case "start":
                         if err := strategy.Start(r.Context()); err != nil {
-                                http.Error(w, fmt.Sprintf("Failed to start strategy: %v", err), http.StatusInternalServerError)
+                                writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to start strategy: %v", err))
                                 return
                         }
                         writeJSON(w, map[string]interface{}{
@@ -227,7 +393,7 @@ func handleStrategies(w http.ResponseWriter, r *http.Request, strategyEngine *st
 
                 case "stop":
                         if err := strategy.Stop(); err != nil {
-                                http.Error(w, fmt.Sprintf("Failed to stop strategy: %v", err), http.StatusInternalServerError)
+                                writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to stop strategy: %v", err))
                                 return
                         }
                         writeJSON(w, map[string]interface{}{
@@ -236,12 +402,92 @@ func handleStrategies(w http.ResponseWriter, r *http.Request, strategyEngine *st
                         })
 
                 default:
-                        http.Error(w, "Invalid action", http.StatusBadRequest)
+                        writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid action")
                 }
 
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
+        }
+}
+
+// handleStrategyCurrentSignals handles requests for the signals a strategy
+// would emit from the current books. Nothing is executed.
+func handleStrategyCurrentSignals(w http.ResponseWriter, r *http.Request, strategyEngine *strategy.Engine, name string) {
+        if r.Method != http.MethodGet {
+                writeMethodNotAllowed(w)
+                return
+        }
+
+        signals, exists, err := strategyEngine.CurrentSignals(name)
+        if !exists {
+                writeError(w, http.StatusNotFound, ErrCodeNotFound, "Strategy not found")
+                return
+        }
+        if err != nil {
+                writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to evaluate strategy: %v", err))
+                return
         }
+        if signals == nil {
+                signals = make([]*strategy.Signal, 0)
+        }
+
+        writeJSON(w, map[string]interface{}{
+                "strategy":  name,
+                "timestamp": time.Now(),
+                "signals":   signals,
+        })
+}
+
+// BacktestResultLister is a backtest engine that lists its stored results
+type BacktestResultLister interface {
+        Results() []*backtesting.BacktestResult
+}
+
+// handleStrategyLeaderboard ranks strategies by a metric over a window,
+// pooling live trades with those of stored backtests
+func handleStrategyLeaderboard(w http.ResponseWriter, r *http.Request, strategyEngine *strategy.Engine, backtestEngine backtesting.BacktestEngine) {
+        if r.Method != http.MethodGet {
+                writeMethodNotAllowed(w)
+                return
+        }
+
+        metric := strategy.RankBySharpe
+        if m := r.URL.Query().Get("metric"); m != "" {
+                if !strategy.ValidRankMetric(m) {
+                        writeInvalidParameter(w, "metric", "Invalid metric parameter")
+                        return
+                }
+                metric = m
+        }
+
+        var window time.Duration
+        if windowStr := r.URL.Query().Get("window"); windowStr != "" {
+                parsed, err := time.ParseDuration(windowStr)
+                if err != nil || parsed < 0 {
+                        writeInvalidParameter(w, "window", "Invalid window parameter")
+                        return
+                }
+                window = parsed
+        }
+
+        backtestTrades := make([]strategy.TradeResult, 0)
+        if lister, ok := backtestEngine.(BacktestResultLister); ok {
+                for _, result := range lister.Results() {
+                        backtestTrades = append(backtestTrades, result.TradeResults(window)...)
+                }
+        }
+
+        entries, err := strategyEngine.Leaderboard(metric, window, backtestTrades)
+        if err != nil {
+                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
+                return
+        }
+
+        writeJSON(w, map[string]interface{}{
+                "metric":      metric,
+                "window":      window.String(),
+                "leaderboard": entries,
+        })
 }
 
 // handleArbitrage handles requests for arbitrage opportunities
@@ -265,7 +511,44 @@ func handleArbitrage(w http.ResponseWriter, r *http.Request, strategyEngine *str
                 writeJSON(w, arbOpportunities)
 
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
+        }
+}
+
+// handleArbitrageConfig gets or updates the arbitrage detection thresholds at runtime
+func handleArbitrageConfig(w http.ResponseWriter, r *http.Request, strategyEngine *strategy.Engine) {
+        var arbStrategy *strategy.ArbitrageStrategy
+        for _, s := range strategyEngine.GetAllStrategies() {
+                if a, ok := s.(*strategy.ArbitrageStrategy); ok {
+                        arbStrategy = a
+                        break
+                }
+        }
+
+        if arbStrategy == nil {
+                writeError(w, http.StatusNotFound, ErrCodeNotFound, "Arbitrage strategy not registered")
+                return
+        }
+
+        switch r.Method {
+        case http.MethodGet:
+                writeJSON(w, arbStrategy.GetThresholds())
+        case http.MethodPost, http.MethodPut:
+                // Fields omitted from the request keep their current values
+                thresholds := arbStrategy.GetThresholds()
+                if err := json.NewDecoder(r.Body).Decode(&thresholds); err != nil {
+                        writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("Invalid thresholds: %v", err))
+                        return
+                }
+
+                if err := arbStrategy.SetThresholds(thresholds); err != nil {
+                        writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("Failed to set thresholds: %v", err))
+                        return
+                }
+
+                writeJSON(w, arbStrategy.GetThresholds())
+        default:
+                writeMethodNotAllowed(w)
         }
 }
 
@@ -304,10 +587,32 @@ func handleMarkets(w http.ResponseWriter, r *http.Request, bookManager *orderboo
                 })
 
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
         }
 }
 
+// handleMarketIndex returns a symbol's index price, combined across the
+// exchanges quoting it
+func handleMarketIndex(w http.ResponseWriter, r *http.Request, bookManager *orderbook.Manager) {
+        if r.Method != http.MethodGet {
+                writeMethodNotAllowed(w)
+                return
+        }
+
+        symbol := r.URL.Query().Get("symbol")
+        if symbol == "" {
+                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "symbol is required")
+                return
+        }
+
+        index, ok := bookManager.IndexPrice(symbol)
+        if !ok {
+                writeError(w, http.StatusNotFound, ErrCodeNotFound, "No exchange is quoting the symbol")
+                return
+        }
+        writeJSON(w, index)
+}
+
 // handleSystemStatus handles requests for system status
 func handleSystemStatus(w http.ResponseWriter, r *http.Request) {
         switch r.Method {
@@ -330,7 +635,7 @@ func handleSystemStatus(w http.ResponseWriter, r *http.Request) {
                 writeJSON(w, status)
 
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
         }
 }
 
@@ -349,15 +654,18 @@ func handleOrders(w http.ResponseWriter, r *http.Request, orderManager orders.Or
                 if symbol := r.URL.Query().Get("symbol"); symbol != "" {
                         filters["symbol"] = symbol
                 }
+                if tags := parseTags(r); len(tags) > 0 {
+                        filters["tags"] = tags
+                }
                 
                 orders, err := orderManager.GetOrders(r.Context(), filters)
                 if err != nil {
-                        http.Error(w, fmt.Sprintf("Failed to get orders: %v", err), http.StatusInternalServerError)
+                        writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to get orders: %v", err))
                         return
                 }
                 
                 writeJSON(w, map[string]interface{}{
-                        "orders": orders,
+                        "orders": roundOrders(orders),
                         "count":  len(orders),
                 })
                 
@@ -365,21 +673,60 @@ func handleOrders(w http.ResponseWriter, r *http.Request, orderManager orders.Or
                 // Submit new order
                 var req orders.OrderRequest
                 if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-                        http.Error(w, "Invalid JSON", http.StatusBadRequest)
+                        writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid JSON")
                         return
                 }
                 
                 order, err := orderManager.SubmitOrder(r.Context(), &req)
                 if err != nil {
-                        http.Error(w, fmt.Sprintf("Failed to submit order: %v", err), http.StatusInternalServerError)
+                        writeOrderError(w, "submit order", err)
                         return
                 }
                 
-                writeJSON(w, order)
+                writeJSON(w, roundOrder(order))
                 
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
+        }
+}
+
+// parseTags reads repeated tag query parameters, each key=value or a bare
+// key matching any value
+func parseTags(r *http.Request) map[string]string {
+        tags := make(map[string]string)
+        for _, tag := range r.URL.Query()["tag"] {
+                key, value, _ := strings.Cut(tag, "=")
+                if key != "" {
+                        tags[key] = value
+                }
+        }
+        return tags
+}
+
+// BulkCanceller cancels every working order carrying a set of tags
+type BulkCanceller interface {
+        CancelOrdersByTag(ctx context.Context, tags map[string]string) *orders.BulkCancelResult
+}
+
+// handleCancelOrdersByTag cancels the working orders carrying all the given tags
+func handleCancelOrdersByTag(w http.ResponseWriter, r *http.Request, canceller BulkCanceller) {
+        if r.Method != http.MethodPost {
+                writeMethodNotAllowed(w)
+                return
+        }
+
+        tags := parseTags(r)
+        if len(tags) == 0 {
+                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "At least one tag is required")
+                return
         }
+
+        result := canceller.CancelOrdersByTag(r.Context(), tags)
+        writeJSON(w, map[string]interface{}{
+                "cancelled": result.Cancelled,
+                "count":     len(result.Cancelled),
+                "failed":    result.Failed,
+        })
 }
 
 // handleOrderByID handles requests for specific orders
@@ -387,7 +734,15 @@ func handleOrderByID(w http.ResponseWriter, r *http.Request, orderManager orders
         // Extract order ID from URL path
         path := strings.TrimPrefix(r.URL.Path, "/api/v1/orders/")
         if path == "" {
-                http.Error(w, "Order ID required", http.StatusBadRequest)
+                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "Order ID required")
+                return
+        }
+        if orderID, ok := strings.CutSuffix(path, "/history"); ok {
+                handleOrderHistory(w, r, orderManager, orderID)
+                return
+        }
+        if orderID, ok := strings.CutSuffix(path, "/execution-quality"); ok {
+                handleExecutionQuality(w, r, orderManager, orderID)
                 return
         }
         
@@ -396,27 +751,94 @@ func handleOrderByID(w http.ResponseWriter, r *http.Request, orderManager orders
                 // Get specific order
                 order, err := orderManager.GetOrder(r.Context(), path)
                 if err != nil {
-                        http.Error(w, fmt.Sprintf("Order not found: %v", err), http.StatusNotFound)
+                        writeError(w, http.StatusNotFound, ErrCodeNotFound, fmt.Sprintf("Order not found: %v", err))
                         return
                 }
                 
-                writeJSON(w, order)
+                writeJSON(w, roundOrder(order))
                 
         case http.MethodDelete:
                 // Cancel order
                 err := orderManager.CancelOrder(r.Context(), path)
                 if err != nil {
-                        http.Error(w, fmt.Sprintf("Failed to cancel order: %v", err), http.StatusInternalServerError)
+                        writeOrderError(w, "cancel order", err)
                         return
                 }
                 
                 writeJSON(w, map[string]string{"status": "cancelled"})
                 
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
         }
 }
 
+// OrderHistoryProvider returns the audit trail of an order's lifecycle
+type OrderHistoryProvider interface {
+        GetOrderHistory(ctx context.Context, orderID string) ([]orders.OrderHistoryEvent, error)
+}
+
+// handleOrderHistory returns the state transitions and executions of an
+// order in the sequence they happened
+func handleOrderHistory(w http.ResponseWriter, r *http.Request, orderManager orders.OrderManager, orderID string) {
+        if r.Method != http.MethodGet {
+                writeMethodNotAllowed(w)
+                return
+        }
+        provider, ok := orderManager.(OrderHistoryProvider)
+        if !ok {
+                writeError(w, http.StatusNotImplemented, ErrCodeNotImplemented, "Order history not supported")
+                return
+        }
+
+        history, err := provider.GetOrderHistory(r.Context(), orderID)
+        if err != nil {
+                writeError(w, http.StatusNotFound, ErrCodeNotFound, fmt.Sprintf("Order not found: %v", err))
+                return
+        }
+
+        writeJSON(w, map[string]interface{}{
+                "order_id": orderID,
+                "events":   history,
+                "count":    len(history),
+        })
+}
+
+// ExecutionQualityProvider measures an order's fills against the configured TCA benchmark
+type ExecutionQualityProvider interface {
+        ExecutionQuality(orderID string) (*orders.ExecutionQualityReport, error)
+}
+
+// handleExecutionQuality returns the slippage and fee cost of an order's fills
+func handleExecutionQuality(w http.ResponseWriter, r *http.Request, orderManager orders.OrderManager, orderID string) {
+        if r.Method != http.MethodGet {
+                writeMethodNotAllowed(w)
+                return
+        }
+        provider, ok := orderManager.(ExecutionQualityProvider)
+        if !ok {
+                writeError(w, http.StatusNotImplemented, ErrCodeNotImplemented, "Execution quality not supported")
+                return
+        }
+
+        report, err := provider.ExecutionQuality(orderID)
+        switch {
+        case errors.Is(err, orders.ErrOrderNotFound):
+                writeError(w, http.StatusNotFound, ErrCodeNotFound, err.Error())
+                return
+        case errors.Is(err, orders.ErrNoExecutions), errors.Is(err, orders.ErrMissingBenchmarkPrice):
+                writeError(w, http.StatusConflict, ErrCodeConflict, err.Error())
+                return
+        case err != nil:
+                writeError(w, http.StatusInternalServerError, ErrCodeInternal, err.Error())
+                return
+        }
+
+        places := getRoundingConfig().PlacesFor(report.Symbol)
+        report.SlippageCost = report.SlippageCost.Round(places)
+        report.Fees = report.Fees.Round(places)
+        writeJSON(w, report)
+}
+
 // handlePositions handles position management requests
 func handlePositions(w http.ResponseWriter, r *http.Request, orderManager orders.OrderManager) {
         switch r.Method {
@@ -432,21 +854,62 @@ func handlePositions(w http.ResponseWriter, r *http.Request, orderManager orders
                 
                 positions, err := orderManager.GetPositions(r.Context(), filters)
                 if err != nil {
-                        http.Error(w, fmt.Sprintf("Failed to get positions: %v", err), http.StatusInternalServerError)
+                        writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to get positions: %v", err))
                         return
                 }
                 
                 writeJSON(w, map[string]interface{}{
-                        "positions": positions,
+                        "positions": roundPositions(positions),
                         "count":     len(positions),
                 })
                 
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
+        }
+}
+
+// PositionCloser flattens open positions
+type PositionCloser interface {
+        ClosePositions(ctx context.Context, filters map[string]interface{}) *orders.CloseAllResult
+}
+
+// handleCloseAllPositions submits market orders closing every open position,
+// optionally only those on an exchange or symbol
+func handleCloseAllPositions(w http.ResponseWriter, r *http.Request, closer PositionCloser) {
+        if r.Method != http.MethodPost {
+                writeMethodNotAllowed(w)
+                return
+        }
+
+        filters := make(map[string]interface{})
+        if exchange := r.URL.Query().Get("exchange"); exchange != "" {
+                filters["exchange"] = exchange
         }
+        if symbol := r.URL.Query().Get("symbol"); symbol != "" {
+                filters["symbol"] = symbol
+        }
+
+        result := closer.ClosePositions(r.Context(), filters)
+        writeJSON(w, map[string]interface{}{
+                "orders": roundOrders(result.Orders),
+                "count":  len(result.Orders),
+                "failed": result.Failed,
+        })
 }
 
-// handleExecutions handles execution history requests
+// Page size of execution history requests without a limit, and the largest allowed
+const (
+        defaultExecutionLimit = 100
+        maxExecutionLimit     = 1000
+)
+
+// ExecutionQuerier returns pages of execution history
+type ExecutionQuerier interface {
+        QueryExecutions(ctx context.Context, query orders.ExecutionQuery) (*orders.ExecutionPage, error)
+}
+
+// handleExecutions handles execution history requests, filtered by order_id,
+// exchange, symbol and an RFC 3339 from/to window and paged by limit and offset
 func handleExecutions(w http.ResponseWriter, r *http.Request, orderManager orders.OrderManager) {
         switch r.Method {
         case http.MethodGet:
@@ -462,19 +925,112 @@ func handleExecutions(w http.ResponseWriter, r *http.Request, orderManager order
                         filters["symbol"] = symbol
                 }
                 
-                executions, err := orderManager.GetExecutions(r.Context(), filters)
+                querier, paged := orderManager.(ExecutionQuerier)
+                if !paged {
+                        executions, err := orderManager.GetExecutions(r.Context(), filters)
+                        if err != nil {
+                                writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to get executions: %v", err))
+                                return
+                        }
+                        writeJSON(w, map[string]interface{}{
+                                "executions": roundExecutions(executions),
+                                "count":      len(executions),
+                        })
+                        return
+                }
+                
+                query := orders.ExecutionQuery{Filters: filters, Limit: defaultExecutionLimit}
+                if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+                        limit, err := strconv.Atoi(limitStr)
+                        if err != nil || limit <= 0 || limit > maxExecutionLimit {
+                                writeInvalidParameter(w, "limit", fmt.Sprintf("Invalid limit parameter: must be 1-%d", maxExecutionLimit))
+                                return
+                        }
+                        query.Limit = limit
+                }
+                if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+                        offset, err := strconv.Atoi(offsetStr)
+                        if err != nil || offset < 0 {
+                                writeInvalidParameter(w, "offset", "Invalid offset parameter")
+                                return
+                        }
+                        query.Offset = offset
+                }
+                from, to, ok := parseTimeWindow(w, r)
+                if !ok {
+                        return
+                }
+                query.From, query.To = from, to
+                
+                page, err := querier.QueryExecutions(r.Context(), query)
                 if err != nil {
-                        http.Error(w, fmt.Sprintf("Failed to get executions: %v", err), http.StatusInternalServerError)
+                        writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to get executions: %v", err))
                         return
                 }
                 
                 writeJSON(w, map[string]interface{}{
-                        "executions": executions,
-                        "count":      len(executions),
+                        "executions": roundExecutions(page.Executions),
+                        "count":      len(page.Executions),
+                        "total":      page.Total,
+                        "limit":      query.Limit,
+                        "offset":     query.Offset,
                 })
                 
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
+        }
+}
+
+// AccountSnapshot is a consolidated view of the account for dashboards
+type AccountSnapshot struct {
+        PortfolioValue decimal.Decimal    `json:"portfolio_value"`
+        Cash           decimal.Decimal    `json:"cash"`
+        DailyPNL       decimal.Decimal    `json:"daily_pnl"`
+        OpenOrders     int                `json:"open_orders"`
+        Positions      []*orders.Position `json:"positions"`
+        RiskMetrics    *risk.RiskMetrics  `json:"risk_metrics"`
+        Timestamp      time.Time          `json:"timestamp"`
+}
+
+// handleAccountSnapshot handles consolidated account snapshot requests
+func handleAccountSnapshot(w http.ResponseWriter, r *http.Request, orderManager orders.OrderManager, riskManager risk.RiskManager) {
+        switch r.Method {
+        case http.MethodGet:
+                allOrders, err := orderManager.GetOrders(r.Context(), nil)
+                if err != nil {
+                        writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to get orders: %v", err))
+                        return
+                }
+                
+                positions, err := orderManager.GetPositions(r.Context(), nil)
+                if err != nil {
+                        writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to get positions: %v", err))
+                        return
+                }
+                
+                snapshot := AccountSnapshot{
+                        Positions:   roundPositions(positions),
+                        RiskMetrics: riskManager.GetRiskMetrics(),
+                        Timestamp:   time.Now(),
+                }
+                
+                for _, order := range allOrders {
+                        switch order.Status {
+                        case orders.OrderStatusPending, orders.OrderStatusSubmitted, orders.OrderStatusPartial:
+                                snapshot.OpenOrders++
+                        }
+                }
+                
+                if portfolio := riskManager.GetPortfolio(); portfolio != nil {
+                        snapshot.PortfolioValue = roundAmount(portfolio.TotalValue)
+                        snapshot.Cash = roundAmount(portfolio.CashBalance)
+                        snapshot.DailyPNL = roundAmount(portfolio.DailyPNL)
+                }
+                
+                writeJSON(w, snapshot)
+                
+        default:
+                writeMethodNotAllowed(w)
         }
 }
 
@@ -485,7 +1041,7 @@ func handleRiskPortfolio(w http.ResponseWriter, r *http.Request, riskManager ris
                 portfolio := riskManager.GetPortfolio()
                 writeJSON(w, portfolio)
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
         }
 }
 
@@ -496,7 +1052,7 @@ func handleRiskMetrics(w http.ResponseWriter, r *http.Request, riskManager risk.
                 metrics := riskManager.GetRiskMetrics()
                 writeJSON(w, metrics)
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
         }
 }
 
@@ -518,7 +1074,7 @@ func handleRiskEvents(w http.ResponseWriter, r *http.Request, riskManager risk.R
                 
                 events, err := riskManager.GetRiskEvents(filters)
                 if err != nil {
-                        http.Error(w, fmt.Sprintf("Failed to get risk events: %v", err), http.StatusInternalServerError)
+                        writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to get risk events: %v", err))
                         return
                 }
                 
@@ -527,7 +1083,7 @@ func handleRiskEvents(w http.ResponseWriter, r *http.Request, riskManager risk.R
                         "count":  len(events),
                 })
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
         }
 }
 
@@ -541,10 +1097,95 @@ func handleRiskPositions(w http.ResponseWriter, r *http.Request, riskManager ris
                         "count":     len(positions),
                 })
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
         }
 }
 
+// StressTester projects the portfolio under hypothetical price shocks
+type StressTester interface {
+        StressTest(scenario risk.StressScenario) (*risk.StressResult, error)
+}
+
+// stressResponse is a stress test result with a flag for any limit breach
+type stressResponse struct {
+        *risk.StressResult
+        Breached bool `json:"breached"`
+}
+
+// handleRiskStress applies the price shocks in the request body to the current
+// positions and returns the projected portfolio and the limits it would breach
+func handleRiskStress(w http.ResponseWriter, r *http.Request, tester StressTester) {
+        if r.Method != http.MethodPost {
+                writeMethodNotAllowed(w)
+                return
+        }
+
+        var scenario risk.StressScenario
+        if err := json.NewDecoder(r.Body).Decode(&scenario); err != nil {
+                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("Invalid request body: %v", err))
+                return
+        }
+
+        result, err := tester.StressTest(scenario)
+        if err != nil {
+                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("Invalid scenario: %v", err))
+                return
+        }
+        writeJSON(w, stressResponse{StressResult: result, Breached: result.Breached()})
+}
+
+// EquityCurveProvider returns the recorded history of the portfolio value
+type EquityCurveProvider interface {
+        EquityCurve(from, to time.Time) []risk.EquitySnapshot
+}
+
+// handleRiskEquityCurve returns the equity snapshots between the optional
+// RFC 3339 from and to query parameters
+func handleRiskEquityCurve(w http.ResponseWriter, r *http.Request, provider EquityCurveProvider) {
+        if r.Method != http.MethodGet {
+                writeMethodNotAllowed(w)
+                return
+        }
+
+        from, to, ok := parseTimeWindow(w, r)
+        if !ok {
+                return
+        }
+
+        curve := provider.EquityCurve(from, to)
+        writeJSON(w, map[string]interface{}{
+                "snapshots": curve,
+                "count":     len(curve),
+        })
+}
+
+// parseTimeWindow parses the optional RFC 3339 from and to query parameters,
+// writing an error response if they are invalid
+func parseTimeWindow(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+        var from, to time.Time
+        if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+                parsed, err := time.Parse(time.RFC3339, fromStr)
+                if err != nil {
+                        writeInvalidParameter(w, "from", "Invalid from parameter")
+                        return from, to, false
+                }
+                from = parsed
+        }
+        if toStr := r.URL.Query().Get("to"); toStr != "" {
+                parsed, err := time.Parse(time.RFC3339, toStr)
+                if err != nil {
+                        writeInvalidParameter(w, "to", "Invalid to parameter")
+                        return from, to, false
+                }
+                to = parsed
+        }
+        if !from.IsZero() && !to.IsZero() && to.Before(from) {
+                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "to must not be before from")
+                return from, to, false
+        }
+        return from, to, true
+}
+
 // handleBacktestRun handles backtest execution requests
 func handleBacktestRun(w http.ResponseWriter, r *http.Request, backtestEngine backtesting.BacktestEngine) {
         switch r.Method {
@@ -557,7 +1198,7 @@ func handleBacktestRun(w http.ResponseWriter, r *http.Request, backtestEngine ba
                 }
                 
                 if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-                        http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+                        writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("Invalid request body: %v", err))
                         return
                 }
                 
@@ -575,7 +1216,7 @@ func handleBacktestRun(w http.ResponseWriter, r *http.Request, backtestEngine ba
                 }
                 
                 if err := backtestEngine.SetConfig(config); err != nil {
-                        http.Error(w, fmt.Sprintf("Failed to update config: %v", err), http.StatusInternalServerError)
+                        writeConfigError(w, "Failed to update config", err)
                         return
                 }
                 
@@ -590,13 +1231,13 @@ func handleBacktestRun(w http.ResponseWriter, r *http.Request, backtestEngine ba
                 }
                 
                 if err != nil {
-                        http.Error(w, fmt.Sprintf("Backtest failed: %v", err), http.StatusInternalServerError)
+                        writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Backtest failed: %v", err))
                         return
                 }
                 
                 writeJSON(w, result)
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
         }
 }
 
@@ -619,8 +1260,17 @@ func handleBacktestStrategies(w http.ResponseWriter, r *http.Request, backtestEn
                         "count":      len(strategyList),
                 })
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
+        }
+}
+
+// handleBacktestProgress reports the progress and phase times of the current or last run
+func handleBacktestProgress(w http.ResponseWriter, r *http.Request, backtestEngine backtesting.BacktestEngine) {
+        if r.Method != http.MethodGet {
+                writeMethodNotAllowed(w)
+                return
         }
+        writeJSON(w, backtestEngine.Progress())
 }
 
 // handleBacktestData handles backtest data requests
@@ -633,7 +1283,7 @@ func handleBacktestData(w http.ResponseWriter, r *http.Request, backtestEngine b
                         "symbols":       len(availableData),
                 })
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
         }
 }
 
@@ -646,18 +1296,18 @@ func handleBacktestConfig(w http.ResponseWriter, r *http.Request, backtestEngine
         case http.MethodPost:
                 var config backtesting.BacktestConfig
                 if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
-                        http.Error(w, fmt.Sprintf("Invalid config: %v", err), http.StatusBadRequest)
+                        writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("Invalid config: %v", err))
                         return
                 }
                 
                 if err := backtestEngine.SetConfig(config); err != nil {
-                        http.Error(w, fmt.Sprintf("Failed to set config: %v", err), http.StatusInternalServerError)
+                        writeConfigError(w, "Failed to set config", err)
                         return
                 }
                 
                 writeJSON(w, map[string]string{"status": "success"})
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
         }
 }
 
@@ -683,19 +1333,19 @@ func handlePlugins(w http.ResponseWriter, r *http.Request, pluginManager plugins
                 }
                 
                 if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-                        http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+                        writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("Invalid request body: %v", err))
                         return
                 }
                 
                 plugin, err := pluginManager.LoadPlugin(request.Path)
                 if err != nil {
-                        http.Error(w, fmt.Sprintf("Failed to load plugin: %v", err), http.StatusInternalServerError)
+                        writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to load plugin: %v", err))
                         return
                 }
                 
                 writeJSON(w, plugin)
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
         }
 }
 
@@ -704,7 +1354,7 @@ func handlePluginByID(w http.ResponseWriter, r *http.Request, pluginManager plug
         // Extract plugin ID from URL path
         path := strings.TrimPrefix(r.URL.Path, "/api/v1/plugins/")
         if path == "" {
-                http.Error(w, "Plugin ID required", http.StatusBadRequest)
+                writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "Plugin ID required")
                 return
         }
         
@@ -712,7 +1362,7 @@ func handlePluginByID(w http.ResponseWriter, r *http.Request, pluginManager plug
         case http.MethodGet:
                 plugin, err := pluginManager.GetPlugin(path)
                 if err != nil {
-                        http.Error(w, fmt.Sprintf("Plugin not found: %v", err), http.StatusNotFound)
+                        writeError(w, http.StatusNotFound, ErrCodeNotFound, fmt.Sprintf("Plugin not found: %v", err))
                         return
                 }
                 
@@ -720,7 +1370,7 @@ func handlePluginByID(w http.ResponseWriter, r *http.Request, pluginManager plug
         case http.MethodPost:
                 // Start plugin
                 if err := pluginManager.StartPlugin(path); err != nil {
-                        http.Error(w, fmt.Sprintf("Failed to start plugin: %v", err), http.StatusInternalServerError)
+                        writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to start plugin: %v", err))
                         return
                 }
                 
@@ -728,7 +1378,7 @@ func handlePluginByID(w http.ResponseWriter, r *http.Request, pluginManager plug
         case http.MethodDelete:
                 // Stop plugin
                 if err := pluginManager.StopPlugin(path); err != nil {
-                        http.Error(w, fmt.Sprintf("Failed to stop plugin: %v", err), http.StatusInternalServerError)
+                        writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to stop plugin: %v", err))
                         return
                 }
                 
@@ -737,18 +1387,18 @@ func handlePluginByID(w http.ResponseWriter, r *http.Request, pluginManager plug
                 // Update plugin configuration
                 var config plugins.PluginConfig
                 if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
-                        http.Error(w, fmt.Sprintf("Invalid config: %v", err), http.StatusBadRequest)
+                        writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("Invalid config: %v", err))
                         return
                 }
                 
                 if err := pluginManager.UpdatePluginConfig(path, config); err != nil {
-                        http.Error(w, fmt.Sprintf("Failed to update config: %v", err), http.StatusInternalServerError)
+                        writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to update config: %v", err))
                         return
                 }
                 
                 writeJSON(w, map[string]string{"status": "updated"})
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
         }
 }
 
@@ -763,7 +1413,7 @@ func handlePluginDiscover(w http.ResponseWriter, r *http.Request, pluginManager
                 
                 plugins, err := pluginManager.DiscoverPlugins(directory)
                 if err != nil {
-                        http.Error(w, fmt.Sprintf("Failed to discover plugins: %v", err), http.StatusInternalServerError)
+                        writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to discover plugins: %v", err))
                         return
                 }
                 
@@ -773,7 +1423,7 @@ func handlePluginDiscover(w http.ResponseWriter, r *http.Request, pluginManager
                         "directory": directory,
                 })
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
         }
 }
 
@@ -787,7 +1437,7 @@ func handlePluginHealth(w http.ResponseWriter, r *http.Request, pluginManager pl
                         // Get health for specific plugin
                         plugin, err := pluginManager.GetPlugin(pluginID)
                         if err != nil {
-                                http.Error(w, fmt.Sprintf("Plugin not found: %v", err), http.StatusNotFound)
+                                writeError(w, http.StatusNotFound, ErrCodeNotFound, fmt.Sprintf("Plugin not found: %v", err))
                                 return
                         }
                         
@@ -830,7 +1480,7 @@ func handlePluginHealth(w http.ResponseWriter, r *http.Request, pluginManager pl
                         })
                 }
         default:
-                http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+                writeMethodNotAllowed(w)
         }
 }
 
@@ -839,6 +1489,6 @@ func writeJSON(w http.ResponseWriter, data interface{}) {
         w.Header().Set("Content-Type", "application/json")
         if err := json.NewEncoder(w).Encode(data); err != nil {
                 log.Printf("Error encoding JSON: %v", err)
-                http.Error(w, "Internal server error", http.StatusInternalServerError)
+                writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Internal server error")
         }
 }
\ No newline at end of file
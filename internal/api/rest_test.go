@@ -0,0 +1,1416 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/alerts"
+	"velocimex/internal/backtesting"
+	"velocimex/internal/chaos"
+	"velocimex/internal/feeds"
+	"velocimex/internal/instruments"
+	"velocimex/internal/logger"
+	"velocimex/internal/normalizer"
+	"velocimex/internal/orderbook"
+	"velocimex/internal/orders"
+	"velocimex/internal/plugins"
+	"velocimex/internal/risk"
+	"velocimex/internal/strategy"
+)
+
+// testRouter routes every order to a fixed exchange
+type testRouter struct{}
+
+func (r *testRouter) RouteOrder(ctx context.Context, req *orders.OrderRequest) (*orders.RoutingDecision, error) {
+	return &orders.RoutingDecision{Exchange: "test_exchange", Symbol: req.Symbol, Side: req.Side, Timestamp: time.Now()}, nil
+}
+
+func (r *testRouter) UpdateMarketData(symbol string, data interface{}) {}
+
+func (r *testRouter) GetBestPrice(ctx context.Context, symbol string, side orders.OrderSide, quantity decimal.Decimal) (*orders.RoutingDecision, error) {
+	return &orders.RoutingDecision{Exchange: "test_exchange", Symbol: symbol, Side: side, Timestamp: time.Now()}, nil
+}
+
+// testServer bundles the REST mux with the managers behind it
+type testServer struct {
+	mux            *http.ServeMux
+	bookManager    *orderbook.Manager
+	strategyEngine *strategy.Engine
+	orderManager   *orders.Manager
+	riskManager    *risk.Manager
+	backtestEngine *backtesting.Engine
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	bookManager := orderbook.NewManager()
+	orderManager := orders.NewManager(orders.DefaultManagerConfig(), &testRouter{}, nil)
+	require.NoError(t, orderManager.Start(context.Background()))
+	t.Cleanup(func() { orderManager.Stop(context.Background()) })
+
+	riskManager := risk.NewManager(risk.DefaultRiskConfig(), nil)
+	strategyEngine := strategy.NewEngine(bookManager)
+	backtestEngine := backtesting.NewEngine()
+
+	mux := http.NewServeMux()
+	RegisterRESTHandlers(mux, bookManager, strategyEngine, orderManager, riskManager, backtestEngine, plugins.NewManager())
+
+	return &testServer{
+		mux:            mux,
+		bookManager:    bookManager,
+		strategyEngine: strategyEngine,
+		orderManager:   orderManager,
+		riskManager:    riskManager,
+		backtestEngine: backtestEngine,
+	}
+}
+
+func (s *testServer) do(t *testing.T, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var req *http.Request
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		req = httptest.NewRequest(method, path, bytes.NewReader(data))
+	} else {
+		req = httptest.NewRequest(method, path, nil)
+	}
+
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestOrderBookBanding tests that the band parameter coalesces book levels
+func TestOrderBookBanding(t *testing.T) {
+	s := newTestServer(t)
+	s.bookManager.UpdateOrderBook("binance", "BTCUSDT",
+		[]normalizer.PriceLevel{{Price: 100.75, Volume: 1}, {Price: 100.5, Volume: 2}, {Price: 99.5, Volume: 3}},
+		[]normalizer.PriceLevel{{Price: 101.25, Volume: 1}, {Price: 101.5, Volume: 1}, {Price: 102.5, Volume: 4}},
+	)
+
+	type bookResponse struct {
+		Band float64                 `json:"band"`
+		Bids []normalizer.PriceLevel `json:"bids"`
+		Asks []normalizer.PriceLevel `json:"asks"`
+	}
+	get := func(query string) bookResponse {
+		rec := s.do(t, http.MethodGet, "/api/v1/orderbooks?symbol=binance:BTCUSDT"+query, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var response bookResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+		return response
+	}
+
+	raw := get("")
+	assert.Len(t, raw.Bids, 3)
+	assert.Zero(t, raw.Band)
+
+	banded := get("&band=1")
+	assert.Equal(t, 1.0, banded.Band)
+	assert.Equal(t, []normalizer.PriceLevel{{Price: 100, Volume: 3}, {Price: 99, Volume: 3}}, banded.Bids)
+	assert.Equal(t, []normalizer.PriceLevel{{Price: 102, Volume: 2}, {Price: 103, Volume: 4}}, banded.Asks)
+
+	top := get("&band=1&depth=1")
+	assert.Equal(t, []normalizer.PriceLevel{{Price: 100, Volume: 3}}, top.Bids)
+
+	for _, band := range []string{"0", "-1", "wide"} {
+		rec := s.do(t, http.MethodGet, "/api/v1/orderbooks?symbol=binance:BTCUSDT&band="+band, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, band)
+	}
+}
+
+// TestOrderBookDepthChart tests that the depth chart accumulates the volume
+// of a symbol's books from the best price outwards
+func TestOrderBookDepthChart(t *testing.T) {
+	s := newTestServer(t)
+	s.bookManager.UpdateOrderBook("binance", "BTCUSDT",
+		[]normalizer.PriceLevel{{Price: 100, Volume: 1}, {Price: 99, Volume: 2}},
+		[]normalizer.PriceLevel{{Price: 101, Volume: 1}, {Price: 103, Volume: 4}},
+	)
+	s.bookManager.UpdateOrderBook("kraken", "BTCUSDT",
+		[]normalizer.PriceLevel{{Price: 99.5, Volume: 3}, {Price: 99, Volume: 1}},
+		[]normalizer.PriceLevel{{Price: 102, Volume: 2}},
+	)
+
+	get := func(query string) orderbook.DepthChart {
+		rec := s.do(t, http.MethodGet, "/api/v1/orderbooks/depth-chart?symbol=BTCUSDT"+query, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var chart orderbook.DepthChart
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&chart))
+		return chart
+	}
+
+	chart := get("")
+	assert.Equal(t, "BTCUSDT", chart.Symbol)
+	assert.Equal(t, 2, chart.Books)
+	assert.Equal(t, []orderbook.DepthPoint{
+		{Price: 100, Volume: 1, Cumulative: 1},
+		{Price: 99.5, Volume: 3, Cumulative: 4},
+		{Price: 99, Volume: 3, Cumulative: 7},
+	}, chart.Bids)
+	assert.Equal(t, []orderbook.DepthPoint{
+		{Price: 101, Volume: 1, Cumulative: 1},
+		{Price: 102, Volume: 2, Cumulative: 3},
+		{Price: 103, Volume: 4, Cumulative: 7},
+	}, chart.Asks)
+
+	chart = get("&exchange=kraken&depth=1")
+	assert.Equal(t, "kraken", chart.Exchange)
+	assert.Equal(t, []orderbook.DepthPoint{{Price: 99.5, Volume: 3, Cumulative: 3}}, chart.Bids)
+	assert.Equal(t, []orderbook.DepthPoint{{Price: 102, Volume: 2, Cumulative: 2}}, chart.Asks)
+
+	rec := s.do(t, http.MethodGet, "/api/v1/orderbooks/depth-chart?symbol=ETHUSDT", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = s.do(t, http.MethodGet, "/api/v1/orderbooks/depth-chart", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = s.do(t, http.MethodGet, "/api/v1/orderbooks/depth-chart?symbol=BTCUSDT&depth=0", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+// TestOrderBookLiquidity tests that a symbol's books are ranked by liquidity
+func TestOrderBookLiquidity(t *testing.T) {
+	s := newTestServer(t)
+	s.bookManager.UpdateOrderBook("binance", "BTCUSDT",
+		[]normalizer.PriceLevel{{Price: 99.99, Volume: 500}, {Price: 99.98, Volume: 500}},
+		[]normalizer.PriceLevel{{Price: 100.01, Volume: 500}, {Price: 100.02, Volume: 500}},
+	)
+	s.bookManager.UpdateOrderBook("kraken", "BTCUSDT",
+		[]normalizer.PriceLevel{{Price: 99.5, Volume: 1}},
+		[]normalizer.PriceLevel{{Price: 100.5, Volume: 1}},
+	)
+
+	rec := s.do(t, http.MethodGet, "/api/v1/orderbooks/liquidity?symbol=BTCUSDT", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var response struct {
+		Symbol   string                     `json:"symbol"`
+		DepthBps float64                    `json:"depth_bps"`
+		Books    []orderbook.LiquidityScore `json:"books"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, 10.0, response.DepthBps)
+	require.Len(t, response.Books, 2)
+	assert.Equal(t, "binance:BTCUSDT", response.Books[0].Book)
+	assert.Greater(t, response.Books[0].Score, response.Books[1].Score)
+
+	rec = s.do(t, http.MethodGet, "/api/v1/orderbooks/liquidity?symbol=BTCUSDT&bps=100", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, 100.0, response.DepthBps)
+
+	assert.Equal(t, http.StatusBadRequest, s.do(t, http.MethodGet, "/api/v1/orderbooks/liquidity", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, s.do(t, http.MethodGet, "/api/v1/orderbooks/liquidity?symbol=BTCUSDT&bps=0", nil).Code)
+	assert.Equal(t, http.StatusNotFound, s.do(t, http.MethodGet, "/api/v1/orderbooks/liquidity?symbol=ETHUSDT", nil).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, s.do(t, http.MethodPost, "/api/v1/orderbooks/liquidity?symbol=BTCUSDT", nil).Code)
+}
+
+// TestMarketIndex tests that the index price combines every exchange quoting a symbol
+func TestMarketIndex(t *testing.T) {
+	s := newTestServer(t)
+	s.bookManager.UpdateOrderBook("binance", "BTCUSDT", []normalizer.PriceLevel{{Price: 99, Volume: 3}}, []normalizer.PriceLevel{{Price: 101, Volume: 3}})
+	s.bookManager.UpdateOrderBook("kraken", "BTCUSDT", []normalizer.PriceLevel{{Price: 119, Volume: 1}}, []normalizer.PriceLevel{{Price: 121, Volume: 1}})
+
+	rec := s.do(t, http.MethodGet, "/api/v1/markets/index?symbol=BTCUSDT", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var index orderbook.IndexPrice
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&index))
+	assert.Equal(t, "BTCUSDT", index.Symbol)
+	assert.Equal(t, orderbook.IndexMethodVWAP, index.Method)
+	// Mids of 100 and 120 weighted 6 to 2
+	assert.InDelta(t, 105, index.Price, 1e-9)
+	require.Len(t, index.Components, 2)
+	assert.Equal(t, "binance", index.Components[0].Exchange)
+	assert.InDelta(t, 0.75, index.Components[0].Weight, 1e-9)
+
+	assert.Equal(t, http.StatusBadRequest, s.do(t, http.MethodGet, "/api/v1/markets/index", nil).Code)
+	assert.Equal(t, http.StatusNotFound, s.do(t, http.MethodGet, "/api/v1/markets/index?symbol=ETHUSDT", nil).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, s.do(t, http.MethodPost, "/api/v1/markets/index?symbol=BTCUSDT", nil).Code)
+}
+
+// TestStrategyCurrentSignals tests that a strategy's current signals are
+// evaluated from the live books without being executed
+func TestStrategyCurrentSignals(t *testing.T) {
+	s := newTestServer(t)
+	s.bookManager.UpdateOrderBook("binance", "BTC/USD", []normalizer.PriceLevel{{Price: 100, Volume: 1000}}, []normalizer.PriceLevel{{Price: 100, Volume: 1000}})
+	s.bookManager.UpdateOrderBook("binance", "ETH/USD", []normalizer.PriceLevel{{Price: 10, Volume: 1000}}, []normalizer.PriceLevel{{Price: 10, Volume: 1000}})
+
+	// 600 in BTC and 400 in ETH against a 50/50 target
+	s.strategyEngine.RegisterStrategy(strategy.NewRebalanceStrategy(strategy.RebalanceConfig{
+		Name:            "rebalance",
+		Exchange:        "binance",
+		TargetWeights:   map[string]float64{"BTC/USD": 0.5, "ETH/USD": 0.5},
+		DriftThreshold:  0.05,
+		InitialHoldings: map[string]float64{"BTC/USD": 6, "ETH/USD": 40},
+	}))
+
+	var response struct {
+		Strategy string             `json:"strategy"`
+		Signals  []*strategy.Signal `json:"signals"`
+	}
+	for i := 0; i < 2; i++ {
+		rec := s.do(t, http.MethodGet, "/api/v1/strategies/rebalance/current-signals", nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+		assert.Equal(t, "rebalance", response.Strategy)
+		require.Len(t, response.Signals, 2)
+		assert.Equal(t, "BTC/USD", response.Signals[0].Symbol)
+		assert.Equal(t, "SELL", response.Signals[0].Side)
+		assert.True(t, response.Signals[0].Quantity.Equal(decimal.NewFromInt(1)))
+		assert.Equal(t, "ETH/USD", response.Signals[1].Symbol)
+		assert.Equal(t, "BUY", response.Signals[1].Side)
+		assert.True(t, response.Signals[1].Quantity.Equal(decimal.NewFromInt(10)))
+	}
+
+	// Nothing was sent to the order manager
+	orders, err := s.orderManager.GetOrders(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, orders)
+
+	rec := s.do(t, http.MethodGet, "/api/v1/strategies/rebalance", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, http.StatusNotFound, s.do(t, http.MethodGet, "/api/v1/strategies/missing/current-signals", nil).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, s.do(t, http.MethodPost, "/api/v1/strategies/rebalance/current-signals", nil).Code)
+}
+
+// TestStrategyLeaderboard tests ranking strategies by a chosen metric
+func TestStrategyLeaderboard(t *testing.T) {
+	s := newTestServer(t)
+	for _, name := range []string{"steady", "volatile", "loser"} {
+		s.strategyEngine.RegisterStrategy(strategy.NewRebalanceStrategy(strategy.RebalanceConfig{Name: name}))
+	}
+
+	record := func(name string, pnls ...float64) {
+		for _, pnl := range pnls {
+			s.strategyEngine.RecordTradeResult(strategy.TradeResult{Strategy: name, Symbol: "BTC/USD", PnL: pnl})
+		}
+	}
+	record("steady", 10, 12, 11)
+	record("volatile", 100, -40, 80)
+	record("loser", -5, -1)
+
+	ranking := func(query string) []string {
+		rec := s.do(t, http.MethodGet, "/api/v1/strategies/leaderboard"+query, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var response struct {
+			Metric      string                      `json:"metric"`
+			Leaderboard []strategy.LeaderboardEntry `json:"leaderboard"`
+		}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+		names := make([]string, 0, len(response.Leaderboard))
+		for _, entry := range response.Leaderboard {
+			names = append(names, entry.Strategy)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"steady", "volatile", "loser"}, ranking(""))
+	assert.Equal(t, []string{"volatile", "steady", "loser"}, ranking("?metric=return&window=1h"))
+	assert.Equal(t, []string{"steady", "volatile", "loser"}, ranking("?metric=win_rate"))
+
+	assert.Equal(t, http.StatusBadRequest, s.do(t, http.MethodGet, "/api/v1/strategies/leaderboard?metric=sortino", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, s.do(t, http.MethodGet, "/api/v1/strategies/leaderboard?window=soon", nil).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, s.do(t, http.MethodPost, "/api/v1/strategies/leaderboard", nil).Code)
+}
+
+// TestCancelOrdersByTag tests listing and bulk cancelling orders by tag
+func TestCancelOrdersByTag(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	submit := func(tags map[string]string) *orders.Order {
+		order, err := s.orderManager.SubmitOrder(ctx, &orders.OrderRequest{
+			Symbol:   "BTCUSDT",
+			Side:     orders.OrderSideBuy,
+			Type:     orders.OrderTypeLimit,
+			Quantity: decimal.NewFromInt(1),
+			Price:    decimal.NewFromInt(100),
+			Tags:     tags,
+		})
+		require.NoError(t, err)
+		return order
+	}
+	grid := submit(map[string]string{"strategy": "grid"})
+	other := submit(map[string]string{"strategy": "hedge"})
+
+	type ordersResponse struct {
+		Orders []*orders.Order `json:"orders"`
+		Count  int             `json:"count"`
+	}
+	list := func(query string) ordersResponse {
+		rec := s.do(t, http.MethodGet, "/api/v1/orders"+query, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var response ordersResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+		return response
+	}
+
+	tagged := list("?tag=strategy=grid")
+	require.Equal(t, 1, tagged.Count)
+	assert.Equal(t, grid.ID, tagged.Orders[0].ID)
+	assert.Equal(t, 2, list("?tag=strategy").Count)
+
+	rec := s.do(t, http.MethodPost, "/api/v1/orders/cancel-by-tag?tag=strategy=grid", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response struct {
+		Cancelled []string `json:"cancelled"`
+		Count     int      `json:"count"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, []string{grid.ID}, response.Cancelled)
+
+	// A status filter given as a query string matches the typed status
+	require.Eventually(t, func() bool {
+		cancelled := list("?status=CANCELLED&tag=strategy")
+		return cancelled.Count == 1 && cancelled.Orders[0].ID == grid.ID
+	}, time.Second, 5*time.Millisecond)
+	order, err := s.orderManager.GetOrder(ctx, other.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, orders.OrderStatusCancelled, order.Status)
+
+	assert.Equal(t, http.StatusBadRequest, s.do(t, http.MethodPost, "/api/v1/orders/cancel-by-tag", nil).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, s.do(t, http.MethodGet, "/api/v1/orders/cancel-by-tag?tag=strategy", nil).Code)
+}
+
+// TestExecutionQuality tests that an order's fills are measured against the
+// book's mid price when it was submitted
+func TestExecutionQuality(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	s.orderManager.SetOrderBooks(s.bookManager)
+	s.bookManager.UpdateOrderBook("test_exchange", "BTCUSDT",
+		[]normalizer.PriceLevel{{Price: 99, Volume: 5}},
+		[]normalizer.PriceLevel{{Price: 101, Volume: 5}},
+	)
+
+	order, err := s.orderManager.SubmitOrder(ctx, &orders.OrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     orders.OrderSideBuy,
+		Type:     orders.OrderTypeLimit,
+		Quantity: decimal.NewFromInt(2),
+		Price:    decimal.NewFromInt(101),
+	})
+	require.NoError(t, err)
+
+	path := "/api/v1/orders/" + order.ID + "/execution-quality"
+	rec := s.do(t, http.MethodGet, path, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code, "no fills to measure yet")
+
+	require.NoError(t, s.orderManager.UpdateOrderStatus(ctx, &orders.OrderUpdate{
+		OrderID:     order.ID,
+		Status:      orders.OrderStatusFilled,
+		FilledQty:   decimal.NewFromInt(2),
+		FilledPrice: decimal.NewFromInt(101),
+		Timestamp:   time.Now(),
+		Exchange:    order.Exchange,
+	}))
+	require.Eventually(t, func() bool {
+		return s.do(t, http.MethodGet, path, nil).Code == http.StatusOK
+	}, time.Second, 5*time.Millisecond)
+
+	var report orders.ExecutionQualityReport
+	require.NoError(t, json.NewDecoder(s.do(t, http.MethodGet, path, nil).Body).Decode(&report))
+	assert.Equal(t, orders.BenchmarkArrival, report.Benchmark)
+	assert.True(t, report.BenchmarkPrice.Equal(decimal.NewFromInt(100)))
+	assert.True(t, report.SlippageBps.Equal(decimal.NewFromInt(100)), "slippage %s", report.SlippageBps)
+	assert.True(t, report.SlippageCost.Equal(decimal.NewFromInt(2)))
+
+	assert.Equal(t, http.StatusNotFound, s.do(t, http.MethodGet, "/api/v1/orders/missing/execution-quality", nil).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, s.do(t, http.MethodPost, path, nil).Code)
+}
+
+// TestOrderHistory tests that an order's history replays its fills and
+// cancel in sequence
+func TestOrderHistory(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	order, err := s.orderManager.SubmitOrder(ctx, &orders.OrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     orders.OrderSideBuy,
+		Type:     orders.OrderTypeLimit,
+		Quantity: decimal.NewFromInt(2),
+		Price:    decimal.NewFromInt(100),
+	})
+	require.NoError(t, err)
+
+	type historyResponse struct {
+		OrderID string                     `json:"order_id"`
+		Events  []orders.OrderHistoryEvent `json:"events"`
+		Count   int                        `json:"count"`
+	}
+	history := func() historyResponse {
+		rec := s.do(t, http.MethodGet, "/api/v1/orders/"+order.ID+"/history", nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var response historyResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+		return response
+	}
+	require.Eventually(t, func() bool { return history().Count == 2 }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, s.orderManager.UpdateOrderStatus(ctx, &orders.OrderUpdate{
+		OrderID:     order.ID,
+		Status:      orders.OrderStatusPartial,
+		FilledQty:   decimal.NewFromInt(1),
+		FilledPrice: decimal.NewFromInt(100),
+		Timestamp:   time.Now(),
+		Exchange:    order.Exchange,
+	}))
+	require.Eventually(t, func() bool { return history().Count == 3 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, s.orderManager.CancelOrder(ctx, order.ID))
+	require.Eventually(t, func() bool { return history().Count == 4 }, time.Second, 5*time.Millisecond)
+
+	response := history()
+	assert.Equal(t, order.ID, response.OrderID)
+	types := make([]string, 0, len(response.Events))
+	for _, event := range response.Events {
+		types = append(types, event.Type+":"+string(event.Status))
+	}
+	assert.Equal(t, []string{"created:PENDING", "submitted:SUBMITTED", "updated:PARTIAL", "cancelled:CANCELLED"}, types)
+	require.NotNil(t, response.Events[2].Execution)
+	assert.True(t, response.Events[2].Execution.Quantity.Equal(decimal.NewFromInt(1)))
+
+	assert.Equal(t, http.StatusNotFound, s.do(t, http.MethodGet, "/api/v1/orders/missing/history", nil).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, s.do(t, http.MethodPost, "/api/v1/orders/"+order.ID+"/history", nil).Code)
+}
+
+// TestAccountSnapshot tests that the snapshot reflects orders, positions and portfolio state
+func TestAccountSnapshot(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.riskManager.UpdatePortfolio(&risk.Portfolio{
+		TotalValue:  decimal.NewFromInt(100000),
+		CashBalance: decimal.NewFromInt(40000),
+		DailyPNL:    decimal.NewFromInt(250),
+		Positions:   make(map[string]*risk.Position),
+	}))
+
+	filled, err := s.orderManager.SubmitOrder(ctx, &orders.OrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     orders.OrderSideBuy,
+		Type:     orders.OrderTypeLimit,
+		Quantity: decimal.NewFromInt(1),
+		Price:    decimal.NewFromInt(50000),
+	})
+	require.NoError(t, err)
+
+	_, err = s.orderManager.SubmitOrder(ctx, &orders.OrderRequest{
+		Symbol:   "ETHUSDT",
+		Side:     orders.OrderSideBuy,
+		Type:     orders.OrderTypeLimit,
+		Quantity: decimal.NewFromInt(2),
+		Price:    decimal.NewFromInt(3000),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, s.orderManager.UpdateOrderStatus(ctx, &orders.OrderUpdate{
+		OrderID:     filled.ID,
+		Status:      orders.OrderStatusFilled,
+		FilledQty:   decimal.NewFromInt(1),
+		FilledPrice: decimal.NewFromInt(50000),
+		Timestamp:   time.Now(),
+		Exchange:    "test_exchange",
+	}))
+	time.Sleep(50 * time.Millisecond)
+
+	rec := s.do(t, http.MethodGet, "/api/v1/account/snapshot", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var snapshot AccountSnapshot
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&snapshot))
+	assert.True(t, snapshot.PortfolioValue.Equal(decimal.NewFromInt(100000)))
+	assert.True(t, snapshot.Cash.Equal(decimal.NewFromInt(40000)))
+	assert.True(t, snapshot.DailyPNL.Equal(decimal.NewFromInt(250)))
+	assert.Equal(t, 1, snapshot.OpenOrders)
+	require.Len(t, snapshot.Positions, 1)
+	assert.Equal(t, "BTCUSDT", snapshot.Positions[0].Symbol)
+	assert.NotNil(t, snapshot.RiskMetrics)
+
+	rec = s.do(t, http.MethodPost, "/api/v1/account/snapshot", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+// TestCloseAllPositions tests that close-all submits an opposite market order
+// TestRiskStress tests projecting the portfolio under a uniform price shock
+func TestRiskStress(t *testing.T) {
+	s := newTestServer(t)
+
+	require.NoError(t, s.riskManager.UpdatePortfolio(&risk.Portfolio{
+		TotalValue:  decimal.NewFromInt(100000),
+		CashBalance: decimal.NewFromInt(75000),
+		DailyPNL:    decimal.NewFromInt(-500),
+		Positions: map[string]*risk.Position{
+			"binance:BTCUSDT": {Symbol: "BTCUSDT", Exchange: "binance", Side: "LONG", Quantity: decimal.NewFromFloat(0.4),
+				CurrentPrice: decimal.NewFromInt(50000), MarketValue: decimal.NewFromInt(20000)},
+			"binance:ETHUSDT": {Symbol: "ETHUSDT", Exchange: "binance", Side: "LONG", Quantity: decimal.NewFromInt(2),
+				CurrentPrice: decimal.NewFromInt(2500), MarketValue: decimal.NewFromInt(5000)},
+		},
+	}))
+
+	rec := s.do(t, http.MethodPost, "/api/v1/risk/stress", map[string]interface{}{"uniform": -0.2})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var response struct {
+		ProjectedValue decimal.Decimal       `json:"projected_value"`
+		PnL            decimal.Decimal       `json:"pnl"`
+		Positions      []risk.PositionStress `json:"positions"`
+		Breaches       []risk.LimitBreach    `json:"breaches"`
+		Breached       bool                  `json:"breached"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+
+	assert.True(t, response.PnL.Equal(decimal.NewFromInt(-5000)), "pnl %s", response.PnL)
+	assert.True(t, response.ProjectedValue.Equal(decimal.NewFromInt(95000)), "projected value %s", response.ProjectedValue)
+	require.Len(t, response.Positions, 2)
+	assert.True(t, response.Positions[0].ShockedPrice.Equal(decimal.NewFromInt(40000)))
+
+	// The 5500 day loss breaches the 5000 limit and the 16000 BTC position the
+	// 10000 size limit; the 5% drawdown and concentration stay within limits
+	assert.True(t, response.Breached)
+	breaches := make(map[string]string)
+	for _, breach := range response.Breaches {
+		breaches[breach.Type] = breach.Symbol
+	}
+	assert.Equal(t, map[string]string{"DAILY_LOSS_EXCEEDED": "", "POSITION_SIZE_EXCEEDED": "BTCUSDT"}, breaches)
+
+	rec = s.do(t, http.MethodPost, "/api/v1/risk/stress", map[string]interface{}{"uniform": -2})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = s.do(t, http.MethodGet, "/api/v1/risk/stress", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+// TestRiskEquityCurve checks that the endpoint returns the snapshots recorded
+// while the risk manager runs, windowed by from and to
+func TestRiskEquityCurve(t *testing.T) {
+	s := newTestServer(t)
+
+	config := risk.DefaultRiskConfig()
+	config.UpdateInterval = 5 * time.Millisecond
+	config.EquitySnapshotInterval = 5 * time.Millisecond
+	require.NoError(t, s.riskManager.SetConfig(config))
+	require.NoError(t, s.riskManager.UpdatePortfolio(&risk.Portfolio{
+		CashBalance: decimal.NewFromInt(1000),
+		TotalValue:  decimal.NewFromInt(1000),
+		Positions:   make(map[string]*risk.Position),
+	}))
+	require.NoError(t, s.riskManager.Start())
+	require.Eventually(t, func() bool {
+		return len(s.riskManager.EquityCurve(time.Time{}, time.Time{})) >= 4
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, s.riskManager.Stop())
+	curve := s.riskManager.EquityCurve(time.Time{}, time.Time{})
+
+	var response struct {
+		Snapshots []risk.EquitySnapshot `json:"snapshots"`
+		Count     int                   `json:"count"`
+	}
+	rec := s.do(t, http.MethodGet, "/api/v1/risk/equity-curve", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, len(curve), response.Count)
+
+	from := curve[1].Timestamp.UTC().Format(time.RFC3339Nano)
+	to := curve[2].Timestamp.UTC().Format(time.RFC3339Nano)
+	rec = s.do(t, http.MethodGet, "/api/v1/risk/equity-curve?from="+from+"&to="+to, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	require.Len(t, response.Snapshots, 2)
+	assert.True(t, response.Snapshots[0].Timestamp.Equal(curve[1].Timestamp))
+	assert.True(t, response.Snapshots[1].Timestamp.Equal(curve[2].Timestamp))
+	assert.True(t, response.Snapshots[0].TotalValue.Equal(decimal.NewFromInt(1000)))
+
+	rec = s.do(t, http.MethodGet, "/api/v1/risk/equity-curve?from=yesterday", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = s.do(t, http.MethodGet, "/api/v1/risk/equity-curve?from="+to+"&to="+from, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = s.do(t, http.MethodPost, "/api/v1/risk/equity-curve", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+// for each open long and short position, honouring the symbol filter
+func TestCloseAllPositions(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	fill := func(symbol string, side orders.OrderSide, quantity int64) {
+		order, err := s.orderManager.SubmitOrder(ctx, &orders.OrderRequest{
+			Symbol:   symbol,
+			Side:     side,
+			Type:     orders.OrderTypeLimit,
+			Quantity: decimal.NewFromInt(quantity),
+			Price:    decimal.NewFromInt(100),
+		})
+		require.NoError(t, err)
+		require.NoError(t, s.orderManager.UpdateOrderStatus(ctx, &orders.OrderUpdate{
+			OrderID:     order.ID,
+			Status:      orders.OrderStatusFilled,
+			FilledQty:   decimal.NewFromInt(quantity),
+			FilledPrice: decimal.NewFromInt(100),
+			Timestamp:   time.Now(),
+			Exchange:    order.Exchange,
+		}))
+	}
+	fill("BTCUSDT", orders.OrderSideBuy, 2)
+	fill("ETHUSDT", orders.OrderSideSell, 3)
+	require.Eventually(t, func() bool {
+		positions, err := s.orderManager.GetPositions(ctx, nil)
+		return err == nil && len(positions) == 2
+	}, time.Second, time.Millisecond)
+
+	type closeAllResponse struct {
+		Orders []*orders.Order               `json:"orders"`
+		Count  int                           `json:"count"`
+		Failed []orders.PositionCloseFailure `json:"failed"`
+	}
+	closeAll := func(path string) closeAllResponse {
+		rec := s.do(t, http.MethodPost, path, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var response closeAllResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+		return response
+	}
+
+	short := closeAll("/api/v1/positions/close-all?symbol=ETHUSDT")
+	require.Equal(t, 1, short.Count)
+	assert.Empty(t, short.Failed)
+	assert.Equal(t, "ETHUSDT", short.Orders[0].Symbol)
+	assert.Equal(t, orders.OrderSideBuy, short.Orders[0].Side)
+	assert.Equal(t, orders.OrderTypeMarket, short.Orders[0].Type)
+	assert.True(t, short.Orders[0].Quantity.Equal(decimal.NewFromInt(3)))
+
+	// The short is covered by its working closing order
+	all := closeAll("/api/v1/positions/close-all")
+	require.Equal(t, 1, all.Count)
+	assert.Equal(t, "BTCUSDT", all.Orders[0].Symbol)
+	assert.Equal(t, orders.OrderSideSell, all.Orders[0].Side)
+	assert.True(t, all.Orders[0].Quantity.Equal(decimal.NewFromInt(2)))
+	assert.Equal(t, "test_exchange", all.Orders[0].Exchange)
+
+	rec := s.do(t, http.MethodGet, "/api/v1/positions/close-all", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+// TestRoundingPrecision tests that serialized monetary values follow the per-class precision
+func TestRoundingPrecision(t *testing.T) {
+	SetRoundingConfig(DefaultRoundingConfig())
+	t.Cleanup(func() { SetRoundingConfig(DefaultRoundingConfig()) })
+
+	config := DefaultRoundingConfig()
+	assert.Equal(t, int32(2), config.PlacesFor("BTCUSDT"))
+	assert.Equal(t, int32(2), config.PlacesFor("BTC/USD"))
+	assert.Equal(t, int32(8), config.PlacesFor("ETHBTC"))
+	assert.Equal(t, int32(2), config.PlacesFor("UNKNOWN"))
+
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	for _, symbol := range []string{"BTCUSDT", "ETHBTC"} {
+		order, err := s.orderManager.SubmitOrder(ctx, &orders.OrderRequest{
+			Symbol:   symbol,
+			Side:     orders.OrderSideBuy,
+			Type:     orders.OrderTypeLimit,
+			Quantity: decimal.NewFromInt(1),
+			Price:    decimal.NewFromInt(100),
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, s.orderManager.UpdateOrderStatus(ctx, &orders.OrderUpdate{
+			OrderID:     order.ID,
+			Status:      orders.OrderStatusFilled,
+			FilledQty:   decimal.NewFromInt(1),
+			FilledPrice: decimal.NewFromInt(100),
+			Commission:  decimal.RequireFromString("1.234567891"),
+			Timestamp:   time.Now(),
+			Exchange:    "test_exchange",
+		}))
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	rec := s.do(t, http.MethodGet, "/api/v1/positions", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Positions []struct {
+			Symbol     string `json:"symbol"`
+			Commission string `json:"commission"`
+		} `json:"positions"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	require.Len(t, response.Positions, 2)
+
+	for _, position := range response.Positions {
+		switch position.Symbol {
+		case "BTCUSDT":
+			assert.Equal(t, "1.23", position.Commission)
+		case "ETHBTC":
+			assert.Equal(t, "1.23456789", position.Commission)
+		}
+	}
+
+	// Internal state keeps full precision
+	positions, err := s.orderManager.GetPositions(ctx, map[string]interface{}{"symbol": "BTCUSDT"})
+	require.NoError(t, err)
+	require.Len(t, positions, 1)
+	assert.Equal(t, "1.234567891", positions[0].Commission.String())
+}
+
+// TestArbitrageConfig tests that threshold updates apply to subsequently reported opportunities
+func TestArbitrageConfig(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := s.do(t, http.MethodGet, "/api/v1/arbitrage/config", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	arb := strategy.NewArbitrageStrategy(strategy.ArbitrageConfig{
+		Name:                "arbitrage",
+		Symbols:             []string{"BTC/USD"},
+		Exchanges:           []string{"binance", "coinbase"},
+		UpdateInterval:      10 * time.Millisecond,
+		MinProfitThreshold:  0.5,
+		MaxExecutionLatency: 100,
+	})
+	s.strategyEngine.RegisterStrategy(arb)
+	require.NoError(t, arb.Start(context.Background()))
+	t.Cleanup(func() { arb.Stop() })
+
+	opportunities := func() []strategy.ArbitrageOpportunity {
+		rec := s.do(t, http.MethodGet, "/api/v1/arbitrage", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var opps []strategy.ArbitrageOpportunity
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&opps))
+		return opps
+	}
+
+	// The simulated spread is ~1%, so both directions pass a 0.5% threshold
+	require.Eventually(t, func() bool { return len(opportunities()) == 2 }, time.Second, 10*time.Millisecond)
+
+	rec = s.do(t, http.MethodGet, "/api/v1/arbitrage/config", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var thresholds strategy.ArbitrageThresholds
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&thresholds))
+	assert.Equal(t, 0.5, thresholds.MinProfitThreshold)
+
+	// Raise the threshold above the available profit
+	rec = s.do(t, http.MethodPost, "/api/v1/arbitrage/config", map[string]interface{}{"minProfitThreshold": 2.0})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&thresholds))
+	assert.Equal(t, 2.0, thresholds.MinProfitThreshold)
+
+	require.Eventually(t, func() bool { return len(opportunities()) == 0 }, time.Second, 10*time.Millisecond)
+
+	// Lower it again but only allow one direction
+	rec = s.do(t, http.MethodPut, "/api/v1/arbitrage/config", map[string]interface{}{
+		"minProfitThreshold": 0.5,
+		"allowedPairs":       []map[string]string{{"buy": "binance", "sell": "coinbase"}},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	require.Eventually(t, func() bool {
+		opps := opportunities()
+		return len(opps) == 1 && opps[0].BuyExchange == "binance" && opps[0].ProfitPercent >= 0.5
+	}, time.Second, 10*time.Millisecond)
+
+	// Invalid thresholds are rejected and leave the current ones in place
+	rec = s.do(t, http.MethodPost, "/api/v1/arbitrage/config", map[string]interface{}{"minVolume": -1})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, 0.0, arb.GetThresholds().MinVolume)
+}
+
+// TestHeartbeatHandlers tests the heartbeat, trading status and resume endpoints
+func TestHeartbeatHandlers(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	watchdog := orders.NewHeartbeatWatchdog(orders.HeartbeatConfig{
+		Enabled:       true,
+		Timeout:       50 * time.Millisecond,
+		CheckInterval: 5 * time.Millisecond,
+	}, s.orderManager, nil)
+	RegisterHeartbeatHandlers(s.mux, watchdog, s.orderManager)
+	require.NoError(t, watchdog.Start(ctx))
+	defer watchdog.Stop()
+
+	status := func() TradingStatus {
+		rec := s.do(t, http.MethodGet, "/api/v1/trading/status", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var status TradingStatus
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&status))
+		return status
+	}
+
+	for i := 0; i < 5; i++ {
+		time.Sleep(20 * time.Millisecond)
+		rec := s.do(t, http.MethodPost, "/api/v1/heartbeat", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+	assert.False(t, status().Halted)
+
+	// No heartbeats: trading halts after the window
+	assert.Eventually(t, func() bool { return status().Halted }, time.Second, 10*time.Millisecond)
+	assert.NotEmpty(t, status().Reason)
+
+	_, err := s.orderManager.SubmitOrder(ctx, &orders.OrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     orders.OrderSideBuy,
+		Type:     orders.OrderTypeLimit,
+		Quantity: decimal.NewFromInt(1),
+		Price:    decimal.NewFromInt(50000),
+	})
+	assert.ErrorIs(t, err, orders.ErrTradingHalted)
+
+	rec := s.do(t, http.MethodPost, "/api/v1/trading/resume", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, status().Halted)
+
+	rec = s.do(t, http.MethodGet, "/api/v1/heartbeat", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+// feedHealthFunc adapts a function to FeedHealthProvider
+type feedHealthFunc func() []feeds.FeedHealth
+
+func (f feedHealthFunc) Health() []feeds.FeedHealth { return f() }
+
+// TestFeedHealth tests the feed health report for feeds in each state
+func TestFeedHealth(t *testing.T) {
+	s := newTestServer(t)
+	lastMessage := time.Now().Add(-time.Second).UTC()
+	RegisterFeedHandlers(s.mux, feedHealthFunc(func() []feeds.FeedHealth {
+		return []feeds.FeedHealth{
+			{Name: "binance", State: feeds.FeedStateConnected, Connected: true, LastMessage: &lastMessage, Staleness: 1, MessageRate: 12.5, Messages: 1200, Reconnects: 2, ReadErrors: 3},
+			{Name: "coinbase", State: feeds.FeedStateStale, Connected: true, Endpoint: "backup", Staleness: 90},
+			{Name: "kraken", State: feeds.FeedStateDisconnected},
+		}
+	}))
+
+	rec := s.do(t, http.MethodGet, "/api/v1/feeds/health", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var report struct {
+		Feeds   []map[string]interface{} `json:"feeds"`
+		Total   int                      `json:"total"`
+		Healthy int                      `json:"healthy"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 1, report.Healthy)
+	require.Len(t, report.Feeds, 3)
+
+	live := report.Feeds[0]
+	assert.Equal(t, "binance", live["name"])
+	assert.Equal(t, "connected", live["state"])
+	assert.Equal(t, true, live["connected"])
+	assert.Equal(t, lastMessage.Format(time.RFC3339Nano), live["last_message"])
+	assert.Equal(t, 12.5, live["message_rate"])
+	assert.Equal(t, 1200.0, live["messages"])
+	assert.Equal(t, 2.0, live["reconnects"])
+	assert.Equal(t, 3.0, live["read_errors"])
+	assert.Equal(t, 1.0, live["staleness_seconds"])
+
+	stale := report.Feeds[1]
+	assert.Equal(t, "stale", stale["state"])
+	assert.Equal(t, "backup", stale["endpoint"])
+	assert.Equal(t, 90.0, stale["staleness_seconds"])
+
+	down := report.Feeds[2]
+	assert.Equal(t, "disconnected", down["state"])
+	assert.Equal(t, false, down["connected"])
+	assert.NotContains(t, down, "last_message")
+
+	rec = s.do(t, http.MethodPost, "/api/v1/feeds/health", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+// TestChaosLatencyToggle tests that latency injection can be toggled over the
+// API and delays order submission while enabled
+func TestChaosLatencyToggle(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	injector := chaos.NewLatencyInjector(chaos.DefaultLatencyConfig())
+	s.orderManager.SetLatencyInjector(injector)
+	RegisterChaosHandlers(s.mux, injector)
+
+	settings := func(rec *httptest.ResponseRecorder) LatencySettings {
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var settings LatencySettings
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&settings))
+		return settings
+	}
+
+	assert.False(t, settings(s.do(t, http.MethodGet, "/api/v1/chaos/latency", nil)).Enabled)
+
+	enabled := settings(s.do(t, http.MethodPut, "/api/v1/chaos/latency", map[string]interface{}{
+		"enabled":       true,
+		"order_latency": "100ms",
+	}))
+	assert.True(t, enabled.Enabled)
+	assert.Equal(t, "100ms", enabled.OrderLatency)
+	assert.Equal(t, "0s", enabled.FeedLatency)
+
+	submitted := time.Now()
+	order, err := s.orderManager.SubmitOrder(ctx, &orders.OrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     orders.OrderSideBuy,
+		Type:     orders.OrderTypeLimit,
+		Quantity: decimal.NewFromInt(1),
+		Price:    decimal.NewFromInt(50000),
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		current, err := s.orderManager.GetOrder(ctx, order.ID)
+		return err == nil && current.Status == orders.OrderStatusSubmitted
+	}, 2*time.Second, time.Millisecond)
+	assert.GreaterOrEqual(t, time.Since(submitted), 100*time.Millisecond)
+
+	// Omitted fields keep their values
+	disabled := settings(s.do(t, http.MethodPost, "/api/v1/chaos/latency", map[string]interface{}{"enabled": false}))
+	assert.False(t, disabled.Enabled)
+	assert.Equal(t, "100ms", disabled.OrderLatency)
+	assert.Zero(t, injector.OrderLatency())
+
+	rec := s.do(t, http.MethodPut, "/api/v1/chaos/latency", map[string]interface{}{"jitter": "-5ms"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = s.do(t, http.MethodPut, "/api/v1/chaos/latency", map[string]interface{}{"feed_latency": "soon"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = s.do(t, http.MethodDelete, "/api/v1/chaos/latency", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+// TestInstrumentLookup tests the instrument metadata endpoints
+func TestInstrumentLookup(t *testing.T) {
+	s := newTestServer(t)
+
+	store, err := instruments.NewStore([]instruments.Instrument{
+		{Symbol: "BTC/USD", BaseCurrency: "BTC", QuoteCurrency: "USD", TickSize: decimal.NewFromFloat(0.01), LotSize: decimal.NewFromFloat(0.0001)},
+		{Symbol: "ETHUSDT", BaseCurrency: "ETH", QuoteCurrency: "USDT", MinNotional: decimal.NewFromInt(10)},
+	})
+	require.NoError(t, err)
+	RegisterInstrumentHandlers(s.mux, store)
+
+	rec := s.do(t, http.MethodGet, "/api/v1/instruments/BTC/USD", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var instrument instruments.Instrument
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&instrument))
+	assert.Equal(t, "BTC/USD", instrument.Symbol)
+	assert.Equal(t, "BTC", instrument.BaseCurrency)
+	assert.True(t, instrument.TickSize.Equal(decimal.NewFromFloat(0.01)))
+	assert.True(t, instrument.LotSize.Equal(decimal.NewFromFloat(0.0001)))
+
+	rec = s.do(t, http.MethodGet, "/api/v1/instruments/ETHUSDT", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = s.do(t, http.MethodGet, "/api/v1/instruments/DOGE/USD", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = s.do(t, http.MethodGet, "/api/v1/instruments", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list []instruments.Instrument
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&list))
+	assert.Len(t, list, 2)
+
+	rec = s.do(t, http.MethodPost, "/api/v1/instruments/BTC/USD", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestAlertLifecycle(t *testing.T) {
+	s := newTestServer(t)
+
+	manager := alerts.NewAlertManager(nil)
+	priceRule := &alerts.AlertRule{ID: "price", Name: "Price spike", Type: alerts.AlertTypePrice, Severity: alerts.SeverityHigh, Enabled: true}
+	riskRule := &alerts.AlertRule{ID: "risk", Name: "Risk limit", Type: alerts.AlertTypeRisk, Severity: alerts.SeverityCritical, Enabled: true}
+	require.NoError(t, manager.AddRule(priceRule))
+	require.NoError(t, manager.AddRule(riskRule))
+	require.NoError(t, manager.TriggerAlert(priceRule, nil))
+	require.NoError(t, manager.TriggerAlert(riskRule, nil))
+	RegisterAlertHandlers(s.mux, manager)
+
+	fetch := func(query string) []alerts.Alert {
+		rec := s.do(t, http.MethodGet, "/api/v1/alerts"+query, nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var list []alerts.Alert
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&list))
+		return list
+	}
+
+	all := fetch("")
+	require.Len(t, all, 2)
+	for _, alert := range all {
+		assert.Equal(t, alerts.AlertStatusActive, alert.Status)
+	}
+
+	critical := fetch("?severity=critical")
+	require.Len(t, critical, 1)
+	assert.Equal(t, "risk", critical[0].RuleID)
+	alertID := critical[0].ID
+
+	// Acknowledge moves the alert to acknowledged
+	rec := s.do(t, http.MethodPost, "/api/v1/alerts/"+alertID+"/ack", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var alert alerts.Alert
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&alert))
+	assert.Equal(t, alerts.AlertStatusAcknowledged, alert.Status)
+	assert.True(t, alert.Acknowledged)
+	assert.False(t, alert.Resolved)
+
+	acknowledged := fetch("?acknowledged=true")
+	require.Len(t, acknowledged, 1)
+	assert.Equal(t, alertID, acknowledged[0].ID)
+
+	// Resolve moves it to resolved and stamps the resolution time
+	rec = s.do(t, http.MethodPost, "/api/v1/alerts/"+alertID+"/resolve", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&alert))
+	assert.Equal(t, alerts.AlertStatusResolved, alert.Status)
+	assert.True(t, alert.Resolved)
+	require.NotNil(t, alert.ResolvedAt)
+
+	assert.Len(t, fetch("?resolved=false"), 1)
+	assert.Len(t, fetch("?status=resolved"), 1)
+
+	rec = s.do(t, http.MethodGet, "/api/v1/alerts/"+alertID, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// A resolved alert cannot be acknowledged again
+	rec = s.do(t, http.MethodPost, "/api/v1/alerts/"+alertID+"/ack", nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = s.do(t, http.MethodPost, "/api/v1/alerts/missing/ack", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = s.do(t, http.MethodGet, "/api/v1/alerts?resolved=maybe", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = s.do(t, http.MethodGet, "/api/v1/alerts/"+alertID+"/ack", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+// testAlertChannel records deliveries and optionally fails every send
+type testAlertChannel struct {
+	name string
+	fail bool
+	sent []*alerts.Alert
+}
+
+func (c *testAlertChannel) Send(alert *alerts.Alert) error {
+	if c.fail {
+		return fmt.Errorf("channel %s unavailable", c.name)
+	}
+	c.sent = append(c.sent, alert)
+	return nil
+}
+
+func (c *testAlertChannel) Name() string { return c.name }
+
+func (c *testAlertChannel) Type() string { return "test" }
+
+// TestAlertTest tests that a test alert reaches the requested channel and
+// that a failed delivery is reported
+func TestAlertTest(t *testing.T) {
+	s := newTestServer(t)
+
+	manager := alerts.NewAlertManager(nil)
+	slack := &testAlertChannel{name: "slack"}
+	email := &testAlertChannel{name: "email", fail: true}
+	require.NoError(t, manager.RegisterChannel(slack))
+	require.NoError(t, manager.RegisterChannel(email))
+	RegisterAlertHandlers(s.mux, manager)
+
+	send := func(body interface{}) alertTestResult {
+		rec := s.do(t, http.MethodPost, "/api/v1/alerts/test", body)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var result alertTestResult
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+		return result
+	}
+
+	result := send(map[string]string{"channel": "slack", "message": "wiring check", "severity": "high"})
+	assert.True(t, result.Delivered)
+	assert.Empty(t, result.Error)
+	require.Len(t, slack.sent, 1)
+	assert.Equal(t, "wiring check", slack.sent[0].Message)
+	assert.Equal(t, alerts.SeverityHigh, slack.sent[0].Severity)
+	assert.Equal(t, alerts.AlertTypeTest, slack.sent[0].Type)
+	assert.Equal(t, slack.sent[0].ID, result.Alert.ID)
+
+	result = send(map[string]string{"channel": "email"})
+	assert.False(t, result.Delivered)
+	assert.Contains(t, result.Error, "channel email unavailable")
+	assert.Len(t, slack.sent, 1)
+
+	// The test alerts are not listed with real ones
+	rec := s.do(t, http.MethodGet, "/api/v1/alerts", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, "[]", rec.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, s.do(t, http.MethodPost, "/api/v1/alerts/test", map[string]string{"channel": "pager"}).Code)
+	assert.Equal(t, http.StatusBadRequest, s.do(t, http.MethodPost, "/api/v1/alerts/test", map[string]string{}).Code)
+	assert.Equal(t, http.StatusBadRequest, s.do(t, http.MethodPost, "/api/v1/alerts/test", map[string]string{"channel": "slack", "severity": "urgent"}).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, s.do(t, http.MethodGet, "/api/v1/alerts/test", nil).Code)
+}
+
+func TestAlertMetrics(t *testing.T) {
+	s := newTestServer(t)
+
+	log, err := logger.New(&logger.Config{Level: logger.ERROR, Output: "stderr"})
+	require.NoError(t, err)
+
+	config := alerts.DefaultAlertConfig()
+	config.CooldownPeriod = 0
+	config.CleanupInterval = 0
+	config.EnableMetrics = false
+	engine := alerts.NewAlertEngine(config, log)
+	defer engine.Close()
+
+	engine.RegisterChannel("ok", &testAlertChannel{name: "ok"})
+	engine.RegisterChannel("broken", &testAlertChannel{name: "broken", fail: true})
+
+	always := []alerts.AlertCondition{{Field: "source", Operator: "exists"}}
+	require.NoError(t, engine.AddRule(&alerts.AlertRule{
+		Name: "Price", EventType: "price", Severity: alerts.SeverityHigh,
+		Conditions: always, Channels: []string{"ok"}, Enabled: true,
+	}))
+	require.NoError(t, engine.AddRule(&alerts.AlertRule{
+		Name: "Risk", EventType: "risk", Severity: alerts.SeverityCritical,
+		Conditions: always, Channels: []string{"ok", "broken"}, Enabled: true,
+	}))
+	RegisterAlertMetricsHandler(s.mux, engine)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, engine.ProcessEvent(&alerts.AlertEvent{Type: "price", Source: "test"}))
+	}
+	require.NoError(t, engine.ProcessEvent(&alerts.AlertEvent{Type: "risk", Source: "test"}))
+
+	var metrics alerts.AlertMetrics
+	require.Eventually(t, func() bool {
+		rec := s.do(t, http.MethodGet, "/api/v1/alerts/metrics", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		metrics = alerts.AlertMetrics{}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&metrics))
+		return metrics.ProcessedAlerts+metrics.FailedAlerts == 5
+	}, 2*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 2, metrics.TotalRules)
+	assert.Equal(t, 2, metrics.ActiveRules)
+	assert.Equal(t, 4, metrics.TotalAlerts)
+	assert.Equal(t, 4, metrics.ProcessedAlerts)
+	assert.Equal(t, 1, metrics.FailedAlerts)
+	assert.Equal(t, map[string]int{"price": 3, "risk": 1}, metrics.AlertsByType)
+	assert.Equal(t, map[alerts.AlertSeverity]int{alerts.SeverityHigh: 3, alerts.SeverityCritical: 1}, metrics.AlertsBySeverity)
+	assert.Equal(t, map[string]int{"ok": 4, "broken": 1}, metrics.AlertsByChannel)
+	assert.Equal(t, 0, metrics.QueueSize)
+
+	rec := s.do(t, http.MethodPost, "/api/v1/alerts/metrics", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+// TestBacktestProgress tests that the backtest progress endpoint reports the engine's progress
+func TestBacktestProgress(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := s.do(t, http.MethodGet, "/api/v1/backtesting/progress", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var progress backtesting.BacktestProgress
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&progress))
+	assert.False(t, progress.Running)
+	assert.Zero(t, progress.Ticks)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, s.do(t, http.MethodPost, "/api/v1/backtesting/progress", nil).Code)
+}
+
+// TestErrorResponses tests that failures are reported as a JSON error
+// envelope with a stable code
+func TestErrorResponses(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		body      interface{}
+		status    int
+		code      string
+		parameter string
+	}{
+		{"method not allowed", http.MethodDelete, "/api/v1/risk/equity-curve", nil, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed, ""},
+		{"invalid parameter", http.MethodGet, "/api/v1/risk/equity-curve?from=yesterday", nil, http.StatusBadRequest, ErrCodeInvalidParameter, "from"},
+		{"invalid depth", http.MethodGet, "/api/v1/orderbooks?symbol=BTCUSDT&depth=deep", nil, http.StatusBadRequest, ErrCodeInvalidParameter, "depth"},
+		{"invalid body", http.MethodPost, "/api/v1/orders", "not an order", http.StatusBadRequest, ErrCodeInvalidRequest, ""},
+		{"unknown order", http.MethodGet, "/api/v1/orders/missing/history", nil, http.StatusNotFound, ErrCodeNotFound, ""},
+		{"unknown backtest", http.MethodGet, "/api/v1/backtesting/results/missing", nil, http.StatusNotFound, ErrCodeNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.do(t, tt.method, tt.path, tt.body)
+			require.Equal(t, tt.status, rec.Code, rec.Body.String())
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			var response ErrorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+			assert.Equal(t, tt.code, response.Code)
+			assert.NotEmpty(t, response.Message)
+			if tt.parameter != "" {
+				assert.Equal(t, map[string]interface{}{"parameter": tt.parameter}, response.Details)
+			} else {
+				assert.Nil(t, response.Details)
+			}
+		})
+	}
+}
+
+// TestOrderErrorStatuses tests that rejected orders, cancels and backtest
+// configs are reported as client errors with their own codes
+func TestOrderErrorStatuses(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	assertError := func(rec *httptest.ResponseRecorder, status int, code string) {
+		t.Helper()
+		require.Equal(t, status, rec.Code, rec.Body.String())
+		var response ErrorResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+		assert.Equal(t, code, response.Code)
+	}
+	submit := func(quantity int64) *httptest.ResponseRecorder {
+		return s.do(t, http.MethodPost, "/api/v1/orders", orders.OrderRequest{
+			Symbol:   "BTCUSDT",
+			Side:     orders.OrderSideBuy,
+			Type:     orders.OrderTypeLimit,
+			Quantity: decimal.NewFromInt(quantity),
+			Price:    decimal.NewFromInt(100),
+		})
+	}
+
+	assertError(submit(0), http.StatusBadRequest, ErrCodeInvalidRequest)
+
+	s.orderManager.Halt("maintenance")
+	assertError(submit(1), http.StatusConflict, ErrCodeTradingHalted)
+	s.orderManager.Resume()
+
+	for i := 0; i < orders.DefaultManagerConfig().MaxConcurrentOrders; i++ {
+		require.Equal(t, http.StatusOK, submit(1).Code)
+	}
+	assertError(submit(1), http.StatusUnprocessableEntity, ErrCodeLimitExceeded)
+
+	assertError(s.do(t, http.MethodDelete, "/api/v1/orders/missing", nil), http.StatusNotFound, ErrCodeNotFound)
+
+	working, err := s.orderManager.GetOrders(ctx, nil)
+	require.NoError(t, err)
+	filled := working[0]
+	require.NoError(t, s.orderManager.UpdateOrderStatus(ctx, &orders.OrderUpdate{
+		OrderID:     filled.ID,
+		Status:      orders.OrderStatusFilled,
+		FilledQty:   filled.Quantity,
+		FilledPrice: filled.Price,
+		Timestamp:   time.Now(),
+		Exchange:    filled.Exchange,
+	}))
+	require.Eventually(t, func() bool {
+		order, err := s.orderManager.GetOrder(ctx, filled.ID)
+		return err == nil && order.Status == orders.OrderStatusFilled
+	}, time.Second, time.Millisecond)
+	assertError(s.do(t, http.MethodDelete, "/api/v1/orders/"+filled.ID, nil), http.StatusConflict, ErrCodeConflict)
+
+	config := s.backtestEngine.GetConfig()
+	config.InitialPositions = []backtesting.InitialPosition{{Symbol: "BTCUSDT"}}
+	assertError(s.do(t, http.MethodPost, "/api/v1/backtesting/config", config), http.StatusBadRequest, ErrCodeInvalidParameter)
+}
+
+// TestExecutionsPagination tests that the executions endpoint filters by
+// order and time window and pages the matches with a total count
+func TestExecutionsPagination(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	start := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	orderIDs := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		order, err := s.orderManager.SubmitOrder(ctx, &orders.OrderRequest{
+			Symbol:   "BTCUSDT",
+			Side:     orders.OrderSideBuy,
+			Type:     orders.OrderTypeLimit,
+			Quantity: decimal.NewFromInt(5),
+			Price:    decimal.NewFromInt(100),
+		})
+		require.NoError(t, err)
+		orderIDs = append(orderIDs, order.ID)
+	}
+	// Each order fills in five parts, the first order on even minutes and the second on odd
+	for part := 1; part <= 5; part++ {
+		for i, orderID := range orderIDs {
+			require.NoError(t, s.orderManager.UpdateOrderStatus(ctx, &orders.OrderUpdate{
+				OrderID:     orderID,
+				Status:      orders.OrderStatusPartial,
+				FilledQty:   decimal.NewFromInt(int64(part)),
+				FilledPrice: decimal.NewFromInt(100),
+				Timestamp:   start.Add(time.Duration(2*(part-1)+i) * time.Minute),
+				Exchange:    "test_exchange",
+			}))
+		}
+	}
+	require.Eventually(t, func() bool {
+		executions, err := s.orderManager.GetExecutions(ctx, nil)
+		return err == nil && len(executions) == 10
+	}, time.Second, 5*time.Millisecond)
+
+	type executionsResponse struct {
+		Executions []orders.Execution `json:"executions"`
+		Count      int                `json:"count"`
+		Total      int                `json:"total"`
+		Limit      int                `json:"limit"`
+		Offset     int                `json:"offset"`
+	}
+	get := func(query string) executionsResponse {
+		rec := s.do(t, http.MethodGet, "/api/v1/executions"+query, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var response executionsResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+		return response
+	}
+
+	response := get("")
+	assert.Equal(t, 10, response.Total)
+	assert.Equal(t, 10, response.Count)
+	assert.Equal(t, 100, response.Limit)
+
+	// The first order's fills from minute 2 to minute 8, two at a time
+	window := "?order_id=" + orderIDs[0] +
+		"&from=" + start.Add(2*time.Minute).Format(time.RFC3339) +
+		"&to=" + start.Add(8*time.Minute).Format(time.RFC3339) + "&limit=2"
+	response = get(window)
+	assert.Equal(t, 4, response.Total)
+	require.Len(t, response.Executions, 2)
+	assert.True(t, response.Executions[0].Timestamp.Equal(start.Add(2*time.Minute)))
+	assert.True(t, response.Executions[1].Timestamp.Equal(start.Add(4*time.Minute)))
+
+	response = get(window + "&offset=2")
+	assert.Equal(t, 4, response.Total)
+	assert.Equal(t, 2, response.Offset)
+	require.Len(t, response.Executions, 2)
+	assert.True(t, response.Executions[0].Timestamp.Equal(start.Add(6*time.Minute)))
+	assert.True(t, response.Executions[1].Timestamp.Equal(start.Add(8*time.Minute)))
+	for _, execution := range response.Executions {
+		assert.Equal(t, orderIDs[0], execution.OrderID)
+	}
+
+	for _, query := range []string{"?limit=0", "?limit=5000", "?offset=-1", "?from=yesterday"} {
+		rec := s.do(t, http.MethodGet, "/api/v1/executions"+query, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
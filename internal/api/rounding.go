@@ -0,0 +1,135 @@
+package api
+
+import (
+        "sort"
+        "strings"
+        "sync"
+
+        "github.com/shopspring/decimal"
+        "velocimex/internal/orders"
+)
+
+// RoundingConfig controls the precision of monetary values in API responses.
+// Values are only rounded when serialized; internal state keeps full precision.
+type RoundingConfig struct {
+        DefaultPlaces int32             `yaml:"defaultPlaces" json:"default_places"`
+        Classes       map[string]int32  `yaml:"classes" json:"classes"`             // class name -> decimal places
+        QuoteClasses  map[string]string `yaml:"quoteClasses" json:"quote_classes"`  // quote currency suffix -> class
+        Symbols       map[string]string `yaml:"symbols" json:"symbols"`             // explicit symbol -> class
+}
+
+// DefaultRoundingConfig returns the default rounding configuration
+func DefaultRoundingConfig() RoundingConfig {
+        return RoundingConfig{
+                DefaultPlaces: 2,
+                Classes: map[string]int32{
+                        "fiat":   2,
+                        "crypto": 8,
+                },
+                QuoteClasses: map[string]string{
+                        "USD":  "fiat",
+                        "USDT": "fiat",
+                        "USDC": "fiat",
+                        "EUR":  "fiat",
+                        "INR":  "fiat",
+                        "BTC":  "crypto",
+                        "ETH":  "crypto",
+                },
+                Symbols: make(map[string]string),
+        }
+}
+
+// PlacesFor returns the number of decimal places used for a symbol's monetary values
+func (c RoundingConfig) PlacesFor(symbol string) int32 {
+        if class, ok := c.Symbols[symbol]; ok {
+                if places, ok := c.Classes[class]; ok {
+                        return places
+                }
+        }
+
+        normalized := strings.ToUpper(strings.NewReplacer("/", "", "-", "", "_", "").Replace(symbol))
+
+        // Try the longest quote suffix first so USDT wins over USD
+        quotes := make([]string, 0, len(c.QuoteClasses))
+        for quote := range c.QuoteClasses {
+                quotes = append(quotes, quote)
+        }
+        sort.Slice(quotes, func(i, j int) bool { return len(quotes[i]) > len(quotes[j]) })
+
+        for _, quote := range quotes {
+                if strings.HasSuffix(normalized, quote) {
+                        if places, ok := c.Classes[c.QuoteClasses[quote]]; ok {
+                                return places
+                        }
+                }
+        }
+
+        return c.DefaultPlaces
+}
+
+var (
+        roundingMu     sync.RWMutex
+        roundingConfig = DefaultRoundingConfig()
+)
+
+// SetRoundingConfig sets the rounding configuration used by API responses
+func SetRoundingConfig(config RoundingConfig) {
+        roundingMu.Lock()
+        defer roundingMu.Unlock()
+        roundingConfig = config
+}
+
+// getRoundingConfig returns the active rounding configuration
+func getRoundingConfig() RoundingConfig {
+        roundingMu.RLock()
+        defer roundingMu.RUnlock()
+        return roundingConfig
+}
+
+// roundAmount rounds an amount that is not tied to a symbol
+func roundAmount(value decimal.Decimal) decimal.Decimal {
+        return value.Round(getRoundingConfig().DefaultPlaces)
+}
+
+// roundPositions returns copies of positions with PnL and commission rounded
+func roundPositions(positions []*orders.Position) []*orders.Position {
+        config := getRoundingConfig()
+        rounded := make([]*orders.Position, 0, len(positions))
+        for _, position := range positions {
+                places := config.PlacesFor(position.Symbol)
+                p := *position
+                p.UnrealizedPNL = p.UnrealizedPNL.Round(places)
+                p.RealizedPNL = p.RealizedPNL.Round(places)
+                p.Commission = p.Commission.Round(places)
+                rounded = append(rounded, &p)
+        }
+        return rounded
+}
+
+// roundOrder returns a copy of an order with its commission rounded
+func roundOrder(order *orders.Order) *orders.Order {
+        o := *order
+        o.Commission = o.Commission.Round(getRoundingConfig().PlacesFor(o.Symbol))
+        return &o
+}
+
+// roundOrders returns rounded copies of orders
+func roundOrders(orderList []*orders.Order) []*orders.Order {
+        rounded := make([]*orders.Order, 0, len(orderList))
+        for _, order := range orderList {
+                rounded = append(rounded, roundOrder(order))
+        }
+        return rounded
+}
+
+// roundExecutions returns copies of executions with commission rounded
+func roundExecutions(executions []*orders.Execution) []*orders.Execution {
+        config := getRoundingConfig()
+        rounded := make([]*orders.Execution, 0, len(executions))
+        for _, execution := range executions {
+                e := *execution
+                e.Commission = e.Commission.Round(config.PlacesFor(e.Symbol))
+                rounded = append(rounded, &e)
+        }
+        return rounded
+}
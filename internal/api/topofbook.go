@@ -0,0 +1,33 @@
+package api
+
+import (
+        "encoding/json"
+        "log"
+
+        "velocimex/internal/orderbook"
+)
+
+// topOfBookChannel is the WebSocket channel carrying top-of-book changes
+const topOfBookChannel = "top_of_book"
+
+// PublishTopOfBook broadcasts a change of an exchange book's best bid or ask.
+// It is meant to be subscribed to the order book manager's top-of-book events,
+// so it never blocks the updating feed.
+func (s *WebSocketServer) PublishTopOfBook(event orderbook.TopOfBookEvent) {
+        message := map[string]interface{}{
+                "channel": topOfBookChannel,
+                "data":    event,
+        }
+
+        data, err := json.Marshal(message)
+        if err != nil {
+                log.Printf("Failed to marshal top of book event: %v", err)
+                return
+        }
+
+        select {
+        case s.broadcast <- data:
+        default:
+                log.Printf("WebSocket broadcast queue full, dropping top of book event for %s:%s", event.Exchange, event.Symbol)
+        }
+}
@@ -5,9 +5,11 @@ import (
         "log"
         "net/http"
         "sync"
+        "sync/atomic"
         "time"
 
         "github.com/gorilla/websocket"
+        "velocimex/internal/metrics"
         "velocimex/internal/orderbook"
         "velocimex/internal/orders"
         "velocimex/internal/risk"
@@ -26,16 +28,33 @@ type WebSocketServer struct {
         unregister    chan *Client
         mu            sync.Mutex
         upgrader      websocket.Upgrader
+        streamMu      sync.Mutex
+        bookStreams   map[string]*bookStream
+        heartbeat     *orders.HeartbeatWatchdog
+        auth          Authenticator
+        authTimeout   time.Duration
+        messagePack   bool
+        topicMu       sync.Mutex
+        topics        map[string]*topicState
+        topicLimits   map[string]float64
+        metrics       *metrics.Wrapper
+        maxMessageAge atomic.Int64 // Nanoseconds a book update may wait in a client's queue, zero for no limit
 }
 
 // Client represents a connected WebSocket client
 type Client struct {
         conn      *websocket.Conn
         server    *WebSocketServer
-        send      chan []byte
+        send      chan *outboundMessage
         mu        sync.Mutex
         symbolSubs map[string]bool
         channelSubs map[string]bool
+        diffSubs   map[string]bool
+        user       string // Authenticated username, if auth is required
+        encoding   Encoding
+        bookMu       sync.Mutex
+        bookSequence uint64
+        latestBooks  map[string]uint64 // Sequence of the latest queued update to each book
 }
 
 // NewWebSocketServer creates a new WebSocket server
@@ -49,6 +68,8 @@ func NewWebSocketServer(books *orderbook.Manager, strategies *strategy.Engine, o
                 broadcast:    make(chan []byte, 256),
                 register:     make(chan *Client),
                 unregister:   make(chan *Client),
+                bookStreams:  make(map[string]*bookStream),
+                topics:       make(map[string]*topicState),
                 upgrader: websocket.Upgrader{
                         ReadBufferSize:  1024,
                         WriteBufferSize: 1024,
@@ -61,7 +82,20 @@ func NewWebSocketServer(books *orderbook.Manager, strategies *strategy.Engine, o
 
 // ServeHTTP handles WebSocket connections
 func (s *WebSocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-        conn, err := s.upgrader.Upgrade(w, r, nil)
+        // A token in the upgrade request is checked before upgrading
+        auth, authTimeout := s.authenticator()
+        var user string
+        if token := upgradeToken(r); auth != nil && token != "" {
+                authUser, err := auth.Authenticate(token)
+                if err != nil {
+                        writeError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+                        return
+                }
+                user = authUser.Username
+        }
+
+        encoding, header := s.negotiateEncoding(r)
+        conn, err := s.upgrader.Upgrade(w, r, header)
         if err != nil {
                 log.Printf("Failed to upgrade to WebSocket: %v", err)
                 return
@@ -70,11 +104,25 @@ func (s *WebSocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
         client := &Client{
                 conn:       conn,
                 server:     s,
-                send:       make(chan []byte, 256),
+                send:       make(chan *outboundMessage, 256),
                 symbolSubs: make(map[string]bool),
                 channelSubs: make(map[string]bool),
+                diffSubs:   make(map[string]bool),
+                user:       user,
+                encoding:   encoding,
+        }
+
+        // Without a token in the upgrade the client must authenticate first
+        if auth != nil && user == "" {
+                go client.awaitAuth(auth, authTimeout)
+                return
         }
 
+        s.startClient(client)
+}
+
+// startClient registers an accepted client and starts its pumps
+func (s *WebSocketServer) startClient(client *Client) {
         s.register <- client
 
         // Send initial system status
@@ -91,7 +139,7 @@ func (s *WebSocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
         statusJson, err := json.Marshal(status)
         if err == nil {
-                client.send <- statusJson
+                client.send <- client.outbound(client.encode(statusJson), "")
         }
 
         go client.readPump()
@@ -118,16 +166,31 @@ func (s *WebSocketServer) Run() {
                         log.Printf("WebSocket client disconnected: %s", client.conn.RemoteAddr())
 
                 case message := <-s.broadcast:
+                        topic := messageTopic(message)
+                        if !s.allowTopic(topic) {
+                                continue
+                        }
+
+                        // Serialize once per encoding rather than once per client
+                        encoded := newEncodedMessage(message)
+                        book := encoded.bookKey(s)
+                        wireBytes := 0
                         s.mu.Lock()
                         for client := range s.clients {
+                                data := encoded.forEncoding(client.encoding)
+                                if data == nil {
+                                        continue
+                                }
                                 select {
-                                case client.send <- message:
+                                case client.send <- client.outbound(data, book):
+                                        wireBytes += len(data)
                                 default:
                                         close(client.send)
                                         delete(s.clients, client)
                                 }
                         }
                         s.mu.Unlock()
+                        s.recordTopic(topic, len(message), wireBytes)
                 }
         }
 }
@@ -156,13 +219,18 @@ func (s *WebSocketServer) BroadcastSampleData() {
         strategyData := `{"channel":"strategy","data":{"profitLoss":1250.75,"drawdown":125.5,"recentSignals":[{"symbol":"BTCUSDT","side":"buy","price":70110.22,"volume":0.5,"exchange":"Binance","timestamp":1744648000000},{"symbol":"ETHUSDT","side":"sell","price":3518.75,"volume":2.5,"exchange":"Coinbase","timestamp":1744647900000}]}}`
         
         // Broadcast all sample data to clients
+        messages := []*encodedMessage{
+                newEncodedMessage([]byte(orderBookData)),
+                newEncodedMessage([]byte(arbitrageData)),
+                newEncodedMessage([]byte(symbolsData)),
+                newEncodedMessage([]byte(strategyData)),
+        }
         s.mu.Lock()
         for client := range s.clients {
                 // Always send all data to all clients in demo mode
-                client.sendMessage([]byte(orderBookData))
-                client.sendMessage([]byte(arbitrageData))
-                client.sendMessage([]byte(symbolsData))
-                client.sendMessage([]byte(strategyData))
+                for _, message := range messages {
+                        client.sendEncoded(message)
+                }
         }
         s.mu.Unlock()
         
@@ -192,6 +260,31 @@ func (s *WebSocketServer) BroadcastStatus() {
         s.broadcast <- statusJson
 }
 
+// BroadcastAlert sends a system alert to all connected clients
+func (s *WebSocketServer) BroadcastAlert(severity, message string) {
+        alert := map[string]interface{}{
+                "channel": "system",
+                "type":    "alert",
+                "data": map[string]interface{}{
+                        "severity":  severity,
+                        "message":   message,
+                        "timestamp": time.Now().Unix(),
+                },
+        }
+
+        alertJson, err := json.Marshal(alert)
+        if err != nil {
+                log.Printf("Failed to marshal system alert: %v", err)
+                return
+        }
+
+        select {
+        case s.broadcast <- alertJson:
+        default:
+                log.Printf("WebSocket broadcast queue full, dropping alert: %s", message)
+        }
+}
+
 // readPump processes incoming messages from the client
 func (c *Client) readPump() {
         defer func() {
@@ -207,7 +300,7 @@ func (c *Client) readPump() {
         })
 
         for {
-                _, message, err := c.conn.ReadMessage()
+                messageType, message, err := c.conn.ReadMessage()
                 if err != nil {
                         if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
                                 log.Printf("WebSocket error: %v", err)
@@ -216,7 +309,9 @@ func (c *Client) readPump() {
                 }
 
                 // Handle message
-                c.handleMessage(message)
+                if message = c.decodeMessage(messageType, message); message != nil {
+                        c.handleMessage(message)
+                }
         }
 }
 
@@ -238,8 +333,14 @@ func (c *Client) writePump() {
                                 return
                         }
 
+                        // A book update superseded while it waited in the queue is dropped
+                        if c.expired(message, time.Now()) {
+                                c.server.recordStale(message)
+                                continue
+                        }
+
                         // Send each message individually to avoid JSON parsing errors
-                        if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+                        if err := c.conn.WriteMessage(c.frameType(), message.data); err != nil {
                                 log.Printf("Error writing message: %v", err)
                                 return
                         }
@@ -253,8 +354,51 @@ func (c *Client) writePump() {
         }
 }
 
+// SetHeartbeatWatchdog sets the watchdog fed by client heartbeat messages
+func (s *WebSocketServer) SetHeartbeatWatchdog(watchdog *orders.HeartbeatWatchdog) {
+        s.mu.Lock()
+        defer s.mu.Unlock()
+        s.heartbeat = watchdog
+}
+
+// recordHeartbeat feeds the heartbeat watchdog, if one is configured
+func (s *WebSocketServer) recordHeartbeat() {
+        s.mu.Lock()
+        watchdog := s.heartbeat
+        s.mu.Unlock()
+
+        if watchdog != nil {
+                watchdog.Heartbeat()
+        }
+}
+
+// clientRequest is a control message sent by a client
+type clientRequest struct {
+        Action  string `json:"action"`
+        Channel string `json:"channel"`
+        Symbol  string `json:"symbol"`
+}
+
 // handleMessage processes an incoming message from the client
 func (c *Client) handleMessage(msg []byte) {
+    var req clientRequest
+    err := json.Unmarshal(msg, &req)
+    if err == nil && req.Action == "heartbeat" {
+        c.server.recordHeartbeat()
+        return
+    }
+    if err == nil && req.Channel == "orderbook_diff" && req.Symbol != "" {
+        switch req.Action {
+        case "subscribe":
+            c.server.subscribeBookDiff(c, req.Symbol)
+        case "unsubscribe":
+            c.mu.Lock()
+            delete(c.diffSubs, req.Symbol)
+            c.mu.Unlock()
+        }
+        return
+    }
+    
     // This is a simplified implementation for demo purposes
     // In a real system, we would properly parse JSON and handle various message types
     
@@ -335,15 +479,28 @@ func (c *Client) handleMessage(msg []byte) {
     c.sendMessage([]byte(sampleMarketData))
 }
 
-// sendMessage sends a message to the client
+// sendMessage sends a JSON message to the client in its encoding
 func (c *Client) sendMessage(msg []byte) {
+        c.sendEncoded(newEncodedMessage(msg))
+}
+
+// sendEncoded sends a message shared with other clients in this client's
+// encoding, reporting whether it was queued
+func (c *Client) sendEncoded(msg *encodedMessage) bool {
+        data := msg.forEncoding(c.encoding)
+        if data == nil {
+                return false
+        }
+
         c.mu.Lock()
         defer c.mu.Unlock()
         
         select {
-        case c.send <- msg:
+        case c.send <- c.outbound(data, msg.bookKey(c.server)):
+                return true
         default:
                 c.server.unregister <- c
                 c.conn.Close()
+                return false
         }
 }
\ No newline at end of file
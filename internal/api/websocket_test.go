@@ -0,0 +1,212 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/normalizer"
+	"velocimex/internal/orderbook"
+	"velocimex/internal/orders"
+	"velocimex/internal/strategy"
+)
+
+// newTestWebSocket starts a WebSocket server and connects a client to it
+func newTestWebSocket(t *testing.T, books *orderbook.Manager) (*WebSocketServer, *websocket.Conn) {
+	t.Helper()
+
+	server := NewWebSocketServer(books, strategy.NewEngine(books), nil, nil)
+	go server.Run()
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return server, conn
+}
+
+// readBookDiff reads messages until the next order book diff message
+func readBookDiff(t *testing.T, conn *websocket.Conn) BookDiffMessage {
+	t.Helper()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+
+		var msg BookDiffMessage
+		if err := json.Unmarshal(data, &msg); err == nil && msg.Channel == "orderbook_diff" {
+			return msg
+		}
+	}
+}
+
+// localBook is a client-side replica maintained from diff messages
+type localBook struct {
+	sequence uint64
+	bids     map[float64]float64
+	asks     map[float64]float64
+}
+
+func (b *localBook) apply(t *testing.T, msg BookDiffMessage) {
+	switch msg.Type {
+	case "snapshot":
+		b.bids = levelMap(msg.Bids)
+		b.asks = levelMap(msg.Asks)
+	case "delta":
+		require.Equal(t, b.sequence+1, msg.Sequence, "sequence gap")
+		for _, change := range msg.Changes {
+			side := b.bids
+			if change.Side == "ask" {
+				side = b.asks
+			}
+			if change.Action == "remove" {
+				delete(side, change.Price)
+			} else {
+				side[change.Price] = change.Volume
+			}
+		}
+	}
+	b.sequence = msg.Sequence
+}
+
+// TestOrderBookDiffReconstruction tests that snapshot plus deltas rebuild the server book
+func TestOrderBookDiffReconstruction(t *testing.T) {
+	books := orderbook.NewManager()
+	book := books.GetOrderBook("BTCUSDT")
+	book.Update(
+		[]normalizer.PriceLevel{{Price: 100, Volume: 1}, {Price: 99, Volume: 2}},
+		[]normalizer.PriceLevel{{Price: 101, Volume: 1}, {Price: 102, Volume: 3}},
+	)
+
+	server, conn := newTestWebSocket(t, books)
+	require.NoError(t, conn.WriteJSON(map[string]string{
+		"action":  "subscribe",
+		"channel": "orderbook_diff",
+		"symbol":  "BTCUSDT",
+	}))
+
+	local := &localBook{}
+	snapshot := readBookDiff(t, conn)
+	assert.Equal(t, "snapshot", snapshot.Type)
+	local.apply(t, snapshot)
+
+	updates := []struct {
+		bids []normalizer.PriceLevel
+		asks []normalizer.PriceLevel
+	}{
+		// Update a bid volume and add an ask level
+		{
+			[]normalizer.PriceLevel{{Price: 100, Volume: 1.5}, {Price: 99, Volume: 2}},
+			[]normalizer.PriceLevel{{Price: 101, Volume: 1}, {Price: 102, Volume: 3}, {Price: 103, Volume: 4}},
+		},
+		// Remove the best bid and ask
+		{
+			[]normalizer.PriceLevel{{Price: 99, Volume: 2}},
+			[]normalizer.PriceLevel{{Price: 102, Volume: 3}, {Price: 103, Volume: 4}},
+		},
+		// Replace both sides
+		{
+			[]normalizer.PriceLevel{{Price: 98, Volume: 5}, {Price: 97, Volume: 1}},
+			[]normalizer.PriceLevel{{Price: 100.5, Volume: 2}},
+		},
+	}
+
+	for _, update := range updates {
+		book.Update(update.bids, update.asks)
+		server.PublishOrderBookDiff("BTCUSDT")
+		local.apply(t, readBookDiff(t, conn))
+	}
+
+	// Publishing an unchanged book sends nothing and keeps the sequence
+	server.PublishOrderBookDiff("BTCUSDT")
+	assert.Equal(t, uint64(4), local.sequence)
+
+	bids, asks := book.GetDepth(defaultBookDiffDepth)
+	assert.Equal(t, levelMap(bids), local.bids)
+	assert.Equal(t, levelMap(asks), local.asks)
+}
+
+// TestOrderBookDiffUnknownSymbol tests that subscribing to a symbol without a
+// book is rejected without creating a book or a diff stream for it
+func TestOrderBookDiffUnknownSymbol(t *testing.T) {
+	books := orderbook.NewManager()
+	server, conn := newTestWebSocket(t, books)
+
+	require.NoError(t, conn.WriteJSON(map[string]string{
+		"action":  "subscribe",
+		"channel": "orderbook_diff",
+		"symbol":  "NOPE",
+	}))
+	msg := readBookDiff(t, conn)
+	assert.Equal(t, "error", msg.Type)
+	assert.Equal(t, "NOPE", msg.Symbol)
+	assert.NotEmpty(t, msg.Error)
+
+	server.PublishOrderBookDiff("NOPE")
+	_, exists := books.LookupOrderBook("NOPE")
+	assert.False(t, exists)
+	_, exists = server.lookupBookStream("NOPE")
+	assert.False(t, exists)
+}
+
+// TestWebSocketHeartbeat tests that heartbeat messages feed the watchdog
+func TestWebSocketHeartbeat(t *testing.T) {
+	books := orderbook.NewManager()
+	server, conn := newTestWebSocket(t, books)
+
+	watchdog := orders.NewHeartbeatWatchdog(orders.DefaultHeartbeatConfig(), nil, nil)
+	server.SetHeartbeatWatchdog(watchdog)
+	require.True(t, watchdog.LastHeartbeat().IsZero())
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"action":"heartbeat"}`)))
+	assert.Eventually(t, func() bool {
+		return !watchdog.LastHeartbeat().IsZero()
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+// TestPublishTopOfBook tests that top-of-book changes reach WebSocket clients
+func TestPublishTopOfBook(t *testing.T) {
+	books := orderbook.NewManager()
+	server, conn := newTestWebSocket(t, books)
+	books.SubscribeTopOfBook(server.PublishTopOfBook)
+
+	// Wait for the client to be registered before publishing
+	require.Eventually(t, func() bool {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		return len(server.clients) == 1
+	}, 2*time.Second, 5*time.Millisecond)
+
+	books.UpdateOrderBook("binance", "BTCUSDT",
+		[]normalizer.PriceLevel{{Price: 100, Volume: 1}},
+		[]normalizer.PriceLevel{{Price: 101, Volume: 2}})
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+
+		var msg struct {
+			Channel string                   `json:"channel"`
+			Data    orderbook.TopOfBookEvent `json:"data"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Channel != topOfBookChannel {
+			continue
+		}
+		assert.Equal(t, "BTCUSDT", msg.Data.Symbol)
+		assert.Nil(t, msg.Data.OldBid)
+		require.NotNil(t, msg.Data.NewAsk)
+		assert.Equal(t, 101.0, msg.Data.NewAsk.Price)
+		assert.Equal(t, 2.0, msg.Data.NewAsk.Volume)
+		return
+	}
+}
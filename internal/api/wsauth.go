@@ -0,0 +1,98 @@
+package api
+
+import (
+        "encoding/json"
+        "log"
+        "net/http"
+        "strings"
+        "time"
+
+        "github.com/gorilla/websocket"
+        "velocimex/internal/security"
+)
+
+// defaultAuthTimeout is how long a client has to send its auth message
+const defaultAuthTimeout = 10 * time.Second
+
+// Authenticator validates client tokens, e.g. the security manager
+type Authenticator interface {
+        Authenticate(token string) (*security.User, error)
+}
+
+// authMessage is the first message a client sends when it did not pass a token in the upgrade request
+type authMessage struct {
+        Action string `json:"action"`
+        Token  string `json:"token"`
+}
+
+// SetAuthenticator requires clients to authenticate before they are registered.
+// A token may be passed in the upgrade request as a bearer Authorization header
+// or a "token" query parameter; otherwise the client must send
+// {"action":"auth","token":"..."} within the timeout. Connections that fail
+// to authenticate are closed without receiving any data.
+func (s *WebSocketServer) SetAuthenticator(auth Authenticator, timeout time.Duration) {
+        s.mu.Lock()
+        defer s.mu.Unlock()
+
+        if timeout <= 0 {
+                timeout = defaultAuthTimeout
+        }
+        s.auth = auth
+        s.authTimeout = timeout
+}
+
+// authenticator returns the configured authenticator and auth timeout
+func (s *WebSocketServer) authenticator() (Authenticator, time.Duration) {
+        s.mu.Lock()
+        defer s.mu.Unlock()
+        return s.auth, s.authTimeout
+}
+
+// upgradeToken returns the token passed in the upgrade request, if any
+func upgradeToken(r *http.Request) string {
+        if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+                return strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+        }
+        return r.URL.Query().Get("token")
+}
+
+// awaitAuth waits for the client's auth message and starts the client once it is valid
+func (c *Client) awaitAuth(auth Authenticator, timeout time.Duration) {
+        c.conn.SetReadDeadline(time.Now().Add(timeout))
+        messageType, message, err := c.conn.ReadMessage()
+        if err != nil {
+                log.Printf("WebSocket client %s did not authenticate: %v", c.conn.RemoteAddr(), err)
+                c.rejectAuth("authentication required")
+                return
+        }
+        message = c.decodeMessage(messageType, message)
+
+        var msg authMessage
+        if err := json.Unmarshal(message, &msg); err != nil || msg.Action != "auth" || msg.Token == "" {
+                c.rejectAuth("authentication required")
+                return
+        }
+
+        user, err := auth.Authenticate(msg.Token)
+        if err != nil {
+                log.Printf("WebSocket client %s failed authentication: %v", c.conn.RemoteAddr(), err)
+                c.rejectAuth("invalid token")
+                return
+        }
+
+        c.user = user.Username
+        if ack, err := json.Marshal(map[string]interface{}{
+                "type": "auth",
+                "data": map[string]interface{}{"status": "ok", "user": user.Username},
+        }); err == nil {
+                c.send <- c.outbound(c.encode(ack), "")
+        }
+        c.server.startClient(c)
+}
+
+// rejectAuth closes an unauthenticated connection with a policy violation
+func (c *Client) rejectAuth(reason string) {
+        c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+        c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason))
+        c.conn.Close()
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/orderbook"
+	"velocimex/internal/security"
+	"velocimex/internal/strategy"
+)
+
+// newAuthTestServer starts a WebSocket server that requires authentication and
+// returns its URL along with a valid API key token
+func newAuthTestServer(t *testing.T, timeout time.Duration) (string, string) {
+	t.Helper()
+
+	manager := security.NewManager(security.SecurityConfig{})
+	user, err := manager.CreateUser("trader", "trader@example.com", security.RoleTrader)
+	require.NoError(t, err)
+	apiKey, err := manager.CreateAPIKey(user.ID, "ws", nil)
+	require.NoError(t, err)
+
+	books := orderbook.NewManager()
+	server := NewWebSocketServer(books, strategy.NewEngine(books), nil, nil)
+	server.SetAuthenticator(manager, timeout)
+	go server.Run()
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	return "ws" + strings.TrimPrefix(httpServer.URL, "http"), apiKey.Key
+}
+
+// readMessageType reads the next message and returns its type field
+func readMessageType(t *testing.T, conn *websocket.Conn) string {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+
+	var msg struct {
+		Type string `json:"type"`
+	}
+	require.NoError(t, json.Unmarshal(data, &msg))
+	return msg.Type
+}
+
+// requirePolicyClose asserts the server closes the connection for failing authentication
+func requirePolicyClose(t *testing.T, conn *websocket.Conn) {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	require.Error(t, err)
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), "unexpected error: %v", err)
+}
+
+func TestWebSocketAuthMessageAccepted(t *testing.T) {
+	url, token := newAuthTestServer(t, time.Second)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(map[string]string{"action": "auth", "token": token}))
+	assert.Equal(t, "auth", readMessageType(t, conn))
+	assert.Equal(t, "status", readMessageType(t, conn))
+
+	// Subscriptions are honoured once authenticated
+	require.NoError(t, conn.WriteJSON(map[string]string{"action": "subscribe", "channel": "orderbook"}))
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"channel": "orderbook"`)
+}
+
+func TestWebSocketUpgradeTokenAccepted(t *testing.T) {
+	url, token := newAuthTestServer(t, time.Second)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?token="+token, nil)
+	require.NoError(t, err)
+	conn.Close()
+
+	header := http.Header{"Authorization": []string{"Bearer " + token}}
+	conn, _, err = websocket.DefaultDialer.Dial(url, header)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, "status", readMessageType(t, conn))
+}
+
+func TestWebSocketInvalidUpgradeTokenRejected(t *testing.T) {
+	url, _ := newAuthTestServer(t, time.Second)
+
+	_, resp, err := websocket.DefaultDialer.Dial(url+"?token=bogus", nil)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestWebSocketInvalidAuthMessageClosed(t *testing.T) {
+	url, _ := newAuthTestServer(t, time.Second)
+
+	for _, message := range []string{
+		`{"action":"auth","token":"bogus"}`,
+		`{"action":"subscribe","channel":"orderbook"}`,
+	} {
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(message)))
+		requirePolicyClose(t, conn)
+		conn.Close()
+	}
+}
+
+func TestWebSocketMissingAuthClosed(t *testing.T) {
+	url, _ := newAuthTestServer(t, 100*time.Millisecond)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	requirePolicyClose(t, conn)
+}
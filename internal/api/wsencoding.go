@@ -0,0 +1,114 @@
+package api
+
+import (
+        "log"
+        "net/http"
+        "sync"
+
+        "github.com/gorilla/websocket"
+)
+
+// Encoding is the wire format of a WebSocket client's messages
+type Encoding string
+
+const (
+        // EncodingJSON sends JSON text frames, the default
+        EncodingJSON Encoding = "json"
+        // EncodingMessagePack sends MessagePack binary frames
+        EncodingMessagePack Encoding = "msgpack"
+)
+
+// SetMessagePack allows clients to negotiate MessagePack encoding. Clients ask
+// for it with the "msgpack" WebSocket subprotocol or an encoding=msgpack query
+// parameter; everyone else, and everyone while it is disabled, gets JSON.
+func (s *WebSocketServer) SetMessagePack(enabled bool) {
+        s.mu.Lock()
+        defer s.mu.Unlock()
+        s.messagePack = enabled
+}
+
+// negotiateEncoding picks a client's encoding from its upgrade request and
+// returns the response headers that confirm it
+func (s *WebSocketServer) negotiateEncoding(r *http.Request) (Encoding, http.Header) {
+        s.mu.Lock()
+        messagePack := s.messagePack
+        s.mu.Unlock()
+
+        for _, protocol := range websocket.Subprotocols(r) {
+                switch {
+                case protocol == string(EncodingMessagePack) && messagePack:
+                        return EncodingMessagePack, http.Header{"Sec-Websocket-Protocol": {protocol}}
+                case protocol == string(EncodingJSON):
+                        return EncodingJSON, http.Header{"Sec-Websocket-Protocol": {protocol}}
+                }
+        }
+        if messagePack && r.URL.Query().Get("encoding") == string(EncodingMessagePack) {
+                return EncodingMessagePack, nil
+        }
+        return EncodingJSON, nil
+}
+
+// encodedMessage is a JSON message serialized at most once per client
+// encoding, so a broadcast transcodes once per encoding group
+type encodedMessage struct {
+        mu      sync.Mutex
+        json    []byte
+        encoded map[Encoding][]byte
+
+        bookOnce sync.Once
+        book     string
+}
+
+// newEncodedMessage wraps a JSON message for sending
+func newEncodedMessage(data []byte) *encodedMessage {
+        return &encodedMessage{json: data}
+}
+
+// forEncoding returns the message in the given encoding, or nil if it cannot be encoded
+func (m *encodedMessage) forEncoding(encoding Encoding) []byte {
+        if encoding != EncodingMessagePack {
+                return m.json
+        }
+
+        m.mu.Lock()
+        defer m.mu.Unlock()
+
+        if data, ok := m.encoded[encoding]; ok {
+                return data
+        }
+        data, err := jsonToMsgpack(m.json)
+        if err != nil {
+                log.Printf("Failed to encode WebSocket message as %s: %v", encoding, err)
+        }
+        if m.encoded == nil {
+                m.encoded = make(map[Encoding][]byte)
+        }
+        m.encoded[encoding] = data
+        return data
+}
+
+// encode returns a JSON message in the client's encoding
+func (c *Client) encode(data []byte) []byte {
+        return newEncodedMessage(data).forEncoding(c.encoding)
+}
+
+// frameType returns the WebSocket frame type for the client's encoding
+func (c *Client) frameType() int {
+        if c.encoding == EncodingMessagePack {
+                return websocket.BinaryMessage
+        }
+        return websocket.TextMessage
+}
+
+// decodeMessage converts a message received from the client to JSON
+func (c *Client) decodeMessage(messageType int, data []byte) []byte {
+        if messageType != websocket.BinaryMessage || c.encoding != EncodingMessagePack {
+                return data
+        }
+        decoded, err := msgpackToJSON(data)
+        if err != nil {
+                log.Printf("Failed to decode MessagePack message from %s: %v", c.conn.RemoteAddr(), err)
+                return nil
+        }
+        return decoded
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/orderbook"
+	"velocimex/internal/strategy"
+)
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	long := strings.Repeat("x", 300)
+	items := make([]interface{}, 20)
+	for i := range items {
+		items[i] = i * 1000
+	}
+	itemsJSON, err := json.Marshal(items)
+	require.NoError(t, err)
+
+	message := `{"channel":"system","empty":null,"flag":true,"off":false,` +
+		`"small":7,"negative":-5,"int8":-100,"uint16":40000,"int32":-70000,"big":9007199254740993,` +
+		`"price":70123.45,"short":"` + strings.Repeat("y", 40) + `","long":"` + long + `",` +
+		`"items":` + string(itemsJSON) + `,"nested":{"bids":[{"price":1.5,"volume":2}]}}`
+
+	encoded, err := jsonToMsgpack([]byte(message))
+	require.NoError(t, err)
+	assert.Less(t, len(encoded), len(message))
+
+	decoded, err := msgpackToJSON(encoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, message, string(decoded))
+
+	// Large integers survive exactly rather than as floats
+	value, err := msgpackDecode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, int64(9007199254740993), value.(map[string]interface{})["big"])
+
+	_, err = msgpackDecode(encoded[:len(encoded)-1])
+	assert.Error(t, err)
+}
+
+// newEncodingTestServer starts a WebSocket server and returns its URL
+func newEncodingTestServer(t *testing.T, messagePack bool) (*WebSocketServer, string) {
+	t.Helper()
+
+	books := orderbook.NewManager()
+	server := NewWebSocketServer(books, strategy.NewEngine(books), nil, nil)
+	server.SetMessagePack(messagePack)
+	go server.Run()
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	return server, "ws" + strings.TrimPrefix(httpServer.URL, "http")
+}
+
+// readDecoded reads the next message, checks its frame type and decodes it to a generic JSON value
+func readDecoded(t *testing.T, conn *websocket.Conn, wantType int) map[string]interface{} {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	messageType, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, wantType, messageType)
+
+	if messageType == websocket.BinaryMessage {
+		data, err = msgpackToJSON(data)
+		require.NoError(t, err)
+	}
+	var msg map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &msg))
+	return msg
+}
+
+func TestWebSocketEncodingNegotiation(t *testing.T) {
+	server, url := newEncodingTestServer(t, true)
+
+	jsonConn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer jsonConn.Close()
+
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = []string{"msgpack", "json"}
+	packConn, resp, err := dialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer packConn.Close()
+	assert.Equal(t, "msgpack", resp.Header.Get("Sec-Websocket-Protocol"))
+
+	queryConn, _, err := websocket.DefaultDialer.Dial(url+"?encoding=msgpack", nil)
+	require.NoError(t, err)
+	defer queryConn.Close()
+
+	assert.Equal(t, "status", readDecoded(t, jsonConn, websocket.TextMessage)["type"])
+	assert.Equal(t, "status", readDecoded(t, packConn, websocket.BinaryMessage)["type"])
+	assert.Equal(t, "status", readDecoded(t, queryConn, websocket.BinaryMessage)["type"])
+
+	// Every client must be registered before the broadcast
+	assert.Eventually(t, func() bool {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		return len(server.clients) == 3
+	}, time.Second, 5*time.Millisecond)
+
+	server.BroadcastAlert("critical", "Trading halted on BTC/USD")
+	fromJSON := readDecoded(t, jsonConn, websocket.TextMessage)
+	fromPack := readDecoded(t, packConn, websocket.BinaryMessage)
+	fromQuery := readDecoded(t, queryConn, websocket.BinaryMessage)
+
+	assert.Equal(t, "alert", fromJSON["type"])
+	assert.Equal(t, fromJSON, fromPack)
+	assert.Equal(t, fromJSON, fromQuery)
+}
+
+func TestWebSocketMessagePackRequests(t *testing.T) {
+	_, url := newEncodingTestServer(t, true)
+
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = []string{"msgpack"}
+	conn, _, err := dialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	readDecoded(t, conn, websocket.BinaryMessage)
+
+	request, err := jsonToMsgpack([]byte(`{"action":"subscribe","channel":"orderbook"}`))
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, request))
+	assert.Equal(t, "orderbook", readDecoded(t, conn, websocket.BinaryMessage)["channel"])
+}
+
+func TestWebSocketMessagePackDisabledFallsBackToJSON(t *testing.T) {
+	_, url := newEncodingTestServer(t, false)
+
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = []string{"msgpack"}
+	conn, resp, err := dialer.Dial(url+"?encoding=msgpack", http.Header{})
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Empty(t, resp.Header.Get("Sec-Websocket-Protocol"))
+
+	assert.Equal(t, "status", readDecoded(t, conn, websocket.TextMessage)["type"])
+}
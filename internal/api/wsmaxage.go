@@ -0,0 +1,104 @@
+package api
+
+import (
+        "encoding/json"
+        "strings"
+        "time"
+)
+
+// bookChannels are the channels whose messages each hold the full state of a
+// book, so a later message on the same book supersedes an earlier one
+var bookChannels = map[string]bool{
+        "orderbook":      true,
+        topOfBookChannel: true,
+}
+
+// outboundMessage is a message queued to a client
+type outboundMessage struct {
+        data     []byte
+        book     string // Book the message holds the state of, empty for other messages
+        sequence uint64 // Order of the client's updates to the book
+        queuedAt time.Time
+}
+
+// SetMaxMessageAge drops order book updates that have waited in a client's
+// queue longer than maxAge by the time they would be sent, as long as a later
+// update to the same book is queued behind them, so a slow client skips to
+// the freshest state of each book. Zero sends every update.
+func (s *WebSocketServer) SetMaxMessageAge(maxAge time.Duration) {
+        s.maxMessageAge.Store(int64(maxAge))
+}
+
+// messageBook returns the book a JSON message holds the state of, keyed by
+// channel, exchange and symbol, or "" if it is not a book message
+func messageBook(msg []byte) string {
+        var envelope struct {
+                Channel string `json:"channel"`
+                Data    struct {
+                        Exchange string `json:"exchange"`
+                        Symbol   string `json:"symbol"`
+                } `json:"data"`
+        }
+        if err := json.Unmarshal(msg, &envelope); err != nil {
+                return ""
+        }
+        if !bookChannels[envelope.Channel] || envelope.Data.Symbol == "" {
+                return ""
+        }
+        return envelope.Channel + ":" + envelope.Data.Exchange + ":" + envelope.Data.Symbol
+}
+
+// bookKey returns the book the message holds the state of. It is only looked
+// up while a max message age is set, since nothing else needs it.
+func (m *encodedMessage) bookKey(s *WebSocketServer) string {
+        if s.maxMessageAge.Load() <= 0 {
+                return ""
+        }
+        m.bookOnce.Do(func() {
+                m.book = messageBook(m.json)
+        })
+        return m.book
+}
+
+// outbound wraps data for the client's send queue, recording it as the latest
+// update to its book
+func (c *Client) outbound(data []byte, book string) *outboundMessage {
+        msg := &outboundMessage{data: data, book: book, queuedAt: time.Now()}
+        if book == "" {
+                return msg
+        }
+
+        c.bookMu.Lock()
+        defer c.bookMu.Unlock()
+        if c.latestBooks == nil {
+                c.latestBooks = make(map[string]uint64)
+        }
+        c.bookSequence++
+        msg.sequence = c.bookSequence
+        c.latestBooks[book] = c.bookSequence
+        return msg
+}
+
+// expired reports whether a queued book update is older than the max message
+// age and superseded by a later update to its book. The latest update to a
+// book is always sent, however long it waited.
+func (c *Client) expired(msg *outboundMessage, now time.Time) bool {
+        maxAge := time.Duration(c.server.maxMessageAge.Load())
+        if msg.book == "" || maxAge <= 0 || now.Sub(msg.queuedAt) <= maxAge {
+                return false
+        }
+
+        c.bookMu.Lock()
+        latest := c.latestBooks[msg.book]
+        c.bookMu.Unlock()
+        return msg.sequence < latest
+}
+
+// recordStale counts a book update dropped as stale on its channel's topic
+func (s *WebSocketServer) recordStale(msg *outboundMessage) {
+        topic, _, _ := strings.Cut(msg.book, ":")
+
+        s.topicMu.Lock()
+        defer s.topicMu.Unlock()
+        s.topic(topic).stats.Stale++
+}
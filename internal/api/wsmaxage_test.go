@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/orderbook"
+	"velocimex/internal/strategy"
+)
+
+// bookMessage returns an order book message for a symbol at a version
+func bookMessage(t *testing.T, symbol string, version int) *encodedMessage {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]interface{}{
+		"channel": "orderbook",
+		"data":    map[string]interface{}{"symbol": symbol, "version": version},
+	})
+	require.NoError(t, err)
+	return newEncodedMessage(data)
+}
+
+// drainClient empties a client's send queue as its write pump would,
+// returning the messages that would be written
+func drainClient(c *Client) []string {
+	sent := make([]string, 0)
+	for {
+		select {
+		case msg := <-c.send:
+			if c.expired(msg, time.Now()) {
+				c.server.recordStale(msg)
+				continue
+			}
+			sent = append(sent, string(msg.data))
+		default:
+			return sent
+		}
+	}
+}
+
+func TestMessageBook(t *testing.T) {
+	assert.Equal(t, "orderbook::BTCUSDT", messageBook([]byte(`{"channel":"orderbook","data":{"symbol":"BTCUSDT","bids":[]}}`)))
+	assert.Equal(t, "top_of_book:binance:BTCUSDT", messageBook([]byte(`{"channel":"top_of_book","data":{"exchange":"binance","symbol":"BTCUSDT"}}`)))
+	assert.Equal(t, "", messageBook([]byte(`{"channel":"arbitrage","data":[{"symbol":"BTCUSDT"}]}`)))
+	assert.Equal(t, "", messageBook([]byte(`{"channel":"system","type":"alert","data":{"message":"halted"}}`)))
+	assert.Equal(t, "", messageBook([]byte(`not json`)))
+}
+
+func TestStaleBookUpdatesDropped(t *testing.T) {
+	books := orderbook.NewManager()
+	server := NewWebSocketServer(books, strategy.NewEngine(books), nil, nil)
+	server.SetMaxMessageAge(20 * time.Millisecond)
+	client := &Client{server: server, send: make(chan *outboundMessage, 16), encoding: EncodingJSON}
+
+	alert := newEncodedMessage([]byte(`{"channel":"system","type":"alert","data":{"message":"halted"}}`))
+	require.True(t, client.sendEncoded(bookMessage(t, "BTCUSDT", 1)))
+	require.True(t, client.sendEncoded(bookMessage(t, "BTCUSDT", 2)))
+	require.True(t, client.sendEncoded(bookMessage(t, "ETHUSDT", 1)))
+	require.True(t, client.sendEncoded(alert))
+
+	// The write pump falls behind, then a fresher BTC update is queued
+	time.Sleep(30 * time.Millisecond)
+	require.True(t, client.sendEncoded(bookMessage(t, "BTCUSDT", 3)))
+
+	// Superseded BTC updates are dropped; the stale ETH update is still the
+	// latest for its book and other messages never expire
+	assert.Equal(t, []string{
+		string(bookMessage(t, "ETHUSDT", 1).json),
+		string(alert.json),
+		string(bookMessage(t, "BTCUSDT", 3).json),
+	}, drainClient(client))
+
+	var stale int64
+	for _, stats := range server.TopicStats() {
+		if stats.Topic == "orderbook" {
+			stale = stats.Stale
+		}
+	}
+	assert.Equal(t, int64(2), stale)
+}
+
+func TestMaxMessageAgeDisabled(t *testing.T) {
+	books := orderbook.NewManager()
+	server := NewWebSocketServer(books, strategy.NewEngine(books), nil, nil)
+	client := &Client{server: server, send: make(chan *outboundMessage, 16), encoding: EncodingJSON}
+
+	for version := 1; version <= 3; version++ {
+		require.True(t, client.sendEncoded(bookMessage(t, "BTCUSDT", version)))
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	sent := drainClient(client)
+	require.Len(t, sent, 3)
+	for i, msg := range sent {
+		assert.Equal(t, string(bookMessage(t, "BTCUSDT", i+1).json), msg, fmt.Sprintf("update %d", i+1))
+	}
+}
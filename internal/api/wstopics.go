@@ -0,0 +1,155 @@
+package api
+
+import (
+        "encoding/json"
+        "math"
+        "sort"
+        "time"
+
+        "golang.org/x/time/rate"
+        "velocimex/internal/metrics"
+)
+
+// TopicStats summarises the messages published on one WebSocket topic
+type TopicStats struct {
+        Topic     string  `json:"topic"`
+        Messages  int64   `json:"messages"`   // Messages published to clients
+        Throttled int64   `json:"throttled"`  // Messages dropped by the topic's rate limit
+        Stale     int64   `json:"stale"`      // Book updates dropped for waiting in a client's queue past the max message age
+        RawBytes  int64   `json:"raw_bytes"`  // Size of published messages as JSON
+        WireBytes int64   `json:"wire_bytes"` // Bytes queued to clients in their negotiated encodings
+        Rate      float64 `json:"rate"`       // Messages per second over the last measured second
+}
+
+// topicState tracks one topic's stats and rate limit
+type topicState struct {
+        stats       TopicStats
+        limiter     *rate.Limiter // Nil when the topic is uncapped
+        windowStart time.Time
+        windowCount int64
+}
+
+// SetMetrics sets the metrics that per-topic message counts and bytes are reported to
+func (s *WebSocketServer) SetMetrics(metrics *metrics.Wrapper) {
+        s.topicMu.Lock()
+        defer s.topicMu.Unlock()
+        s.metrics = metrics
+}
+
+// SetTopicRateLimits caps how many messages per second are published on each
+// topic, e.g. {"orderbook": 10}. Messages over the limit are dropped rather
+// than queued, since a later message on the topic supersedes them. Topics
+// without a limit are uncapped.
+func (s *WebSocketServer) SetTopicRateLimits(limits map[string]float64) {
+        s.topicMu.Lock()
+        defer s.topicMu.Unlock()
+
+        s.topicLimits = make(map[string]float64, len(limits))
+        for topic, limit := range limits {
+                if limit > 0 {
+                        s.topicLimits[topic] = limit
+                }
+        }
+        for topic, state := range s.topics {
+                state.limiter = newTopicLimiter(s.topicLimits[topic])
+        }
+}
+
+// newTopicLimiter allows limit messages per second with a burst of one second's worth
+func newTopicLimiter(limit float64) *rate.Limiter {
+        if limit <= 0 {
+                return nil
+        }
+        return rate.NewLimiter(rate.Limit(limit), int(math.Max(1, math.Ceil(limit))))
+}
+
+// TopicStats returns the stats of every topic published so far, sorted by topic
+func (s *WebSocketServer) TopicStats() []TopicStats {
+        s.topicMu.Lock()
+        defer s.topicMu.Unlock()
+
+        stats := make([]TopicStats, 0, len(s.topics))
+        for _, state := range s.topics {
+                stats = append(stats, state.stats)
+        }
+        sort.Slice(stats, func(i, j int) bool { return stats[i].Topic < stats[j].Topic })
+        return stats
+}
+
+// messageTopic returns the topic of a JSON message: its channel, else its type
+func messageTopic(msg []byte) string {
+        var envelope struct {
+                Channel string `json:"channel"`
+                Type    string `json:"type"`
+        }
+        if err := json.Unmarshal(msg, &envelope); err != nil {
+                return "unknown"
+        }
+        if envelope.Channel != "" {
+                return envelope.Channel
+        }
+        if envelope.Type != "" {
+                return envelope.Type
+        }
+        return "unknown"
+}
+
+// topic returns the state of a topic, creating it on first use. The caller holds topicMu.
+func (s *WebSocketServer) topic(name string) *topicState {
+        state, ok := s.topics[name]
+        if !ok {
+                state = &topicState{
+                        stats:   TopicStats{Topic: name},
+                        limiter: newTopicLimiter(s.topicLimits[name]),
+                }
+                s.topics[name] = state
+        }
+        return state
+}
+
+// allowTopic reports whether a message may be published on a topic now,
+// counting it as throttled if not
+func (s *WebSocketServer) allowTopic(name string) bool {
+        s.topicMu.Lock()
+        state := s.topic(name)
+        if state.limiter == nil || state.limiter.Allow() {
+                s.topicMu.Unlock()
+                return true
+        }
+        state.stats.Throttled++
+        metrics := s.metrics
+        s.topicMu.Unlock()
+
+        if metrics != nil {
+                metrics.RecordWebSocketThrottled(name)
+        }
+        return false
+}
+
+// recordTopic records a message published on a topic with its size as JSON
+// and the total bytes queued to clients in their encodings
+func (s *WebSocketServer) recordTopic(name string, rawBytes, wireBytes int) {
+        now := time.Now()
+
+        s.topicMu.Lock()
+        state := s.topic(name)
+        state.stats.Messages++
+        state.stats.RawBytes += int64(rawBytes)
+        state.stats.WireBytes += int64(wireBytes)
+        if elapsed := now.Sub(state.windowStart); elapsed >= time.Second {
+                if !state.windowStart.IsZero() {
+                        state.stats.Rate = float64(state.windowCount) / elapsed.Seconds()
+                }
+                state.windowStart = now
+                state.windowCount = 0
+        }
+        state.windowCount++
+        metrics := s.metrics
+        s.topicMu.Unlock()
+
+        if metrics != nil {
+                metrics.RecordWebSocketMessage(name)
+                metrics.RecordWebSocketBytes(name, "raw", rawBytes)
+                metrics.RecordWebSocketBytes(name, "wire", wireBytes)
+        }
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTopicThrottling tests that a flooded topic is throttled to its rate
+// while an uncapped topic delivers every message
+func TestTopicThrottling(t *testing.T) {
+	server, url := newEncodingTestServer(t, false)
+	server.SetTopicRateLimits(map[string]float64{"orderbook": 10})
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	readDecoded(t, conn, websocket.TextMessage) // Initial status
+
+	for i := 0; i < 50; i++ {
+		server.broadcast <- []byte(fmt.Sprintf(`{"channel":"orderbook","data":{"seq":%d}}`, i))
+		server.broadcast <- []byte(fmt.Sprintf(`{"channel":"trades","data":{"seq":%d}}`, i))
+	}
+
+	received := make(map[string]int)
+	for {
+		conn.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		received[messageTopic(data)]++
+	}
+
+	// The burst is one second's worth; a message or two may refill while flooding
+	assert.GreaterOrEqual(t, received["orderbook"], 10)
+	assert.LessOrEqual(t, received["orderbook"], 12)
+	assert.Equal(t, 50, received["trades"])
+
+	stats := make(map[string]TopicStats)
+	for _, topic := range server.TopicStats() {
+		stats[topic.Topic] = topic
+	}
+	assert.Equal(t, int64(received["orderbook"]), stats["orderbook"].Messages)
+	assert.Equal(t, int64(50-received["orderbook"]), stats["orderbook"].Throttled)
+	assert.Equal(t, int64(50), stats["trades"].Messages)
+	assert.Zero(t, stats["trades"].Throttled)
+	assert.Positive(t, stats["trades"].RawBytes)
+	assert.Equal(t, stats["trades"].RawBytes, stats["trades"].WireBytes) // One JSON client
+}
+
+// TestTopicStatsWireBytes tests that wire bytes reflect each client's encoding
+func TestTopicStatsWireBytes(t *testing.T) {
+	server, url := newEncodingTestServer(t, true)
+
+	for _, suffix := range []string{"", "?encoding=msgpack"} {
+		conn, _, err := websocket.DefaultDialer.Dial(url+suffix, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, _, err = conn.ReadMessage() // Initial status
+		require.NoError(t, err)
+	}
+
+	message := []byte(`{"channel":"arbitrage","data":[{"symbol":"BTCUSDT","buyPrice":70110.22,"sellPrice":70125.78}]}`)
+	server.broadcast <- message
+
+	require.Eventually(t, func() bool {
+		stats := server.TopicStats()
+		return len(stats) == 1 && stats[0].Messages == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	encoded, err := jsonToMsgpack(message)
+	require.NoError(t, err)
+
+	stats := server.TopicStats()[0]
+	assert.Equal(t, "arbitrage", stats.Topic)
+	assert.Equal(t, int64(len(message)), stats.RawBytes)
+	assert.Equal(t, int64(len(message)+len(encoded)), stats.WireBytes)
+}
+
+func TestMessageTopic(t *testing.T) {
+	assert.Equal(t, "orderbook", messageTopic([]byte(`{"channel":"orderbook","type":"snapshot"}`)))
+	assert.Equal(t, "status", messageTopic([]byte(`{"type":"status"}`)))
+	assert.Equal(t, "unknown", messageTopic([]byte(`{}`)))
+	assert.Equal(t, "unknown", messageTopic([]byte(`not json`)))
+}
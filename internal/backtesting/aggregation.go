@@ -0,0 +1,26 @@
+package backtesting
+
+import (
+	"time"
+
+	"velocimex/internal/strategy"
+)
+
+// newSignalAggregator returns an aggregator for the configured signal window,
+// or nil when signals are executed as generated
+func newSignalAggregator(window time.Duration) *strategy.SignalAggregator {
+	if window <= 0 {
+		return nil
+	}
+	return strategy.NewSignalAggregator(window)
+}
+
+// aggregateSignals returns the signals to execute at the current time. With a
+// signal window, signals are netted per exchange and symbol and executed when
+// their window closes; those still pending when the run ends are not executed.
+func (e *Engine) aggregateSignals(signals []*strategy.Signal) []*strategy.Signal {
+	if e.aggregator == nil {
+		return signals
+	}
+	return e.aggregator.Add(e.currentTime, signals...)
+}
@@ -0,0 +1,68 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/orderbook"
+	"velocimex/internal/strategy"
+)
+
+// churnStrategy signals one unit every tick, alternating sides when flip is set
+type churnStrategy struct {
+	testStrategy
+	flip  bool
+	ticks int
+}
+
+func (s *churnStrategy) GenerateSignals(orderBooks map[string]*orderbook.OrderBook) ([]*strategy.Signal, error) {
+	side := "BUY"
+	if s.flip && s.ticks%2 == 1 {
+		side = "SELL"
+	}
+	s.ticks++
+	return []*strategy.Signal{{Symbol: "BTC/USD", Exchange: "test", Side: side, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100)}}, nil
+}
+
+func runChurnBacktest(t *testing.T, flip bool, window time.Duration) *BacktestResult {
+	t.Helper()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 10)
+	config.SignalWindow = window
+
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(config))
+	defer engine.Stop()
+	require.NoError(t, engine.AddHistoricalData(trendingData(start, 10, 100, 0)))
+	s := &churnStrategy{testStrategy: *newTestStrategy(), flip: flip}
+	require.NoError(t, engine.RegisterStrategy(s))
+
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+	return result
+}
+
+// TestSignalWindowNetsOpposingSignals tests that flip-flopping signals within
+// the window cancel out instead of trading
+func TestSignalWindowNetsOpposingSignals(t *testing.T) {
+	assert.Len(t, runChurnBacktest(t, true, 0).Trades, 10)
+	assert.Empty(t, runChurnBacktest(t, true, 2*time.Minute).Trades)
+}
+
+// TestSignalWindowAggregatesSameSide tests that same-side signals within the
+// window trade once for their combined size
+func TestSignalWindowAggregatesSameSide(t *testing.T) {
+	result := runChurnBacktest(t, false, 2*time.Minute)
+
+	// Windows opened at minutes 0, 2, 4 and 6 close within the run; the one
+	// opened at minute 8 is still pending when the data ends
+	require.Len(t, result.Trades, 4)
+	for _, trade := range result.Trades {
+		assert.Equal(t, "BUY", trade.Side)
+		assert.True(t, trade.Quantity.Equal(decimal.NewFromInt(2)), trade.Quantity.String())
+	}
+}
@@ -0,0 +1,192 @@
+package backtesting
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"velocimex/internal/orderbook"
+	"velocimex/internal/strategy"
+)
+
+// BuyAndHoldStrategyID is the ID of the built-in buy-and-hold benchmark strategy
+const BuyAndHoldStrategyID = "buy_and_hold"
+
+// BenchmarkResult is the buy-and-hold run over the same data as a backtest
+type BenchmarkResult struct {
+	StrategyID     string           `json:"strategy_id"`
+	FinalCapital   decimal.Decimal  `json:"final_capital"`
+	TotalReturnPct decimal.Decimal  `json:"total_return_pct"`
+	EquityCurve    []BenchmarkPoint `json:"equity_curve"`
+	// Outperformance is the backtest's return minus the benchmark's, in percentage points
+	Outperformance decimal.Decimal `json:"outperformance"`
+}
+
+// BenchmarkPoint is the benchmark's equity at one tick
+type BenchmarkPoint struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Value     decimal.Decimal `json:"value"`
+}
+
+// BuyAndHoldStrategy splits its capital equally across its markets at the
+// first prices seen and holds to the end. Quantities are net of commission
+// and slippage so the capital is fully invested.
+type BuyAndHoldStrategy struct {
+	capital decimal.Decimal
+	costs   decimal.Decimal // Commission plus slippage, as a fraction of notional
+	markets []string        // exchange:symbol
+	bought  bool
+}
+
+// NewBuyAndHoldStrategy creates a buy-and-hold strategy investing the
+// config's initial capital across the given exchange:symbol markets
+func NewBuyAndHoldStrategy(config BacktestConfig, markets []string) *BuyAndHoldStrategy {
+	return &BuyAndHoldStrategy{
+		capital: config.InitialCapital,
+		costs:   config.Commission.Add(config.Slippage),
+		markets: markets,
+	}
+}
+
+func (s *BuyAndHoldStrategy) GetID() string                   { return BuyAndHoldStrategyID }
+func (s *BuyAndHoldStrategy) GetName() string                 { return "Buy and hold" }
+func (s *BuyAndHoldStrategy) Start(ctx context.Context) error { return nil }
+func (s *BuyAndHoldStrategy) Stop() error                     { return nil }
+func (s *BuyAndHoldStrategy) IsRunning() bool                 { return false }
+
+// GetResults returns the strategy results
+func (s *BuyAndHoldStrategy) GetResults() strategy.StrategyResults {
+	return strategy.StrategyResults{Name: s.GetName()}
+}
+
+// WithParameters returns a fresh copy; buy-and-hold has no parameters
+func (s *BuyAndHoldStrategy) WithParameters(params map[string]interface{}) (strategy.Strategy, error) {
+	if len(params) > 0 {
+		return nil, fmt.Errorf("buy-and-hold strategy has no parameters")
+	}
+	return &BuyAndHoldStrategy{capital: s.capital, costs: s.costs, markets: s.markets}, nil
+}
+
+// GenerateSignals buys every market once all of them have an ask
+func (s *BuyAndHoldStrategy) GenerateSignals(orderBooks map[string]*orderbook.OrderBook) ([]*strategy.Signal, error) {
+	if s.bought || len(s.markets) == 0 {
+		return nil, nil
+	}
+
+	asks := make([]decimal.Decimal, len(s.markets))
+	for i, market := range s.markets {
+		book, ok := orderBooks[market]
+		if !ok {
+			return nil, nil
+		}
+		ask := book.GetBestAsk()
+		if ask == nil || ask.Price <= 0 {
+			return nil, nil
+		}
+		asks[i] = decimal.NewFromFloat(ask.Price)
+	}
+
+	allocation := s.capital.Div(decimal.NewFromInt(int64(len(s.markets))))
+	signals := make([]*strategy.Signal, 0, len(s.markets))
+	for i, market := range s.markets {
+		exchange, symbol, _ := strings.Cut(market, ":")
+		signals = append(signals, &strategy.Signal{
+			Symbol:   symbol,
+			Exchange: exchange,
+			Side:     "BUY",
+			Quantity: allocation.Div(asks[i].Mul(decimal.NewFromInt(1).Add(s.costs))),
+			Price:    asks[i],
+		})
+	}
+	s.bought = true
+	return signals, nil
+}
+
+// benchmarkMarkets returns one exchange:symbol market per symbol with data,
+// using the first exchange alphabetically, restricted to the configured symbols
+func (e *Engine) benchmarkMarkets() []string {
+	allowed := make(map[string]bool, len(e.config.Symbols))
+	for _, symbol := range e.config.Symbols {
+		allowed[symbol] = true
+	}
+
+	var markets []string
+	for symbol, exchanges := range e.historicalData {
+		if len(allowed) > 0 && !allowed[symbol] {
+			continue
+		}
+		names := make([]string, 0, len(exchanges))
+		for exchange := range exchanges {
+			names = append(names, exchange)
+		}
+		if len(names) == 0 {
+			continue
+		}
+		sort.Strings(names)
+		markets = append(markets, fmt.Sprintf("%s:%s", names[0], symbol))
+	}
+	sort.Strings(markets)
+	return markets
+}
+
+// runBenchmark runs buy-and-hold over the same data and period as the result
+// in a separate engine, and compares the two returns
+func (e *Engine) runBenchmark(result *BacktestResult) (*BenchmarkResult, error) {
+	config := e.config
+	config.Benchmark = false
+	config.RiskManagement = true // The portfolio is tracked by the risk manager
+	config.Latency = 0
+	config.WarmupPeriod = 0
+	config.QueueModel = false
+	config.AbortOnDrawdown = false
+	config.DrawdownLimit = decimal.Zero
+	config.CheckpointPath = ""
+
+	benchmark := NewEngine()
+	if err := benchmark.SetConfig(config); err != nil {
+		return nil, err
+	}
+	defer benchmark.Stop()
+
+	// Historical data is only read during a run, so it is shared
+	benchmark.historicalData = e.historicalData
+	if err := benchmark.RegisterStrategy(NewBuyAndHoldStrategy(config, e.benchmarkMarkets())); err != nil {
+		return nil, err
+	}
+
+	run, err := benchmark.RunBacktestWithStrategy(BuyAndHoldStrategyID)
+	if err != nil {
+		return nil, err
+	}
+
+	curve := make([]BenchmarkPoint, 0, len(run.PortfolioHistory))
+	for _, snapshot := range run.PortfolioHistory {
+		curve = append(curve, BenchmarkPoint{Timestamp: snapshot.Timestamp, Value: snapshot.TotalValue})
+	}
+
+	return &BenchmarkResult{
+		StrategyID:     BuyAndHoldStrategyID,
+		FinalCapital:   run.FinalCapital,
+		TotalReturnPct: run.TotalReturnPct,
+		EquityCurve:    curve,
+		Outperformance: result.TotalReturnPct.Sub(run.TotalReturnPct),
+	}, nil
+}
+
+// attachBenchmark runs the buy-and-hold benchmark when configured. A failed
+// benchmark is logged rather than failing the backtest it accompanies.
+func (e *Engine) attachBenchmark(result *BacktestResult) {
+	if !e.config.Benchmark {
+		return
+	}
+	benchmark, err := e.runBenchmark(result)
+	if err != nil {
+		log.Printf("Failed to run buy-and-hold benchmark: %v", err)
+		return
+	}
+	result.Benchmark = benchmark
+}
@@ -0,0 +1,97 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBenchmarkTracksPriceReturn tests that the buy-and-hold curve follows
+// the underlying price and that outperformance compares the two returns
+func TestBenchmarkTracksPriceReturn(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 20)
+	config.Benchmark = true
+
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(config))
+	t.Cleanup(func() { engine.Stop() })
+
+	data := trendingData(start, 20, 100, 2)
+	require.NoError(t, engine.AddHistoricalData(data))
+	require.NoError(t, engine.RegisterStrategy(newTestStrategy()))
+
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+	require.NotNil(t, result.Benchmark)
+
+	benchmark := result.Benchmark
+	assert.Equal(t, BuyAndHoldStrategyID, benchmark.StrategyID)
+	require.Len(t, benchmark.EquityCurve, len(data.DataPoints))
+
+	// Fully invested at the first price, equity scales with the price
+	first := data.DataPoints[0].Close
+	tolerance := decimal.NewFromFloat(1e-6)
+	for i, point := range benchmark.EquityCurve {
+		assert.True(t, point.Timestamp.Equal(data.DataPoints[i].Timestamp))
+		want := config.InitialCapital.Mul(data.DataPoints[i].Close).Div(first)
+		assert.True(t, point.Value.Sub(want).Abs().LessThan(tolerance), "tick %d equity %s, want %s", i, point.Value, want)
+	}
+
+	last := data.DataPoints[len(data.DataPoints)-1].Close
+	wantReturn := last.Sub(first).Div(first).Mul(decimal.NewFromInt(100))
+	assert.True(t, benchmark.TotalReturnPct.Sub(wantReturn).Abs().LessThan(tolerance), "benchmark return %s, want %s", benchmark.TotalReturnPct, wantReturn)
+	assert.True(t, benchmark.Outperformance.Equal(result.TotalReturnPct.Sub(benchmark.TotalReturnPct)))
+
+	// Buying one unit of a rising market underperforms buying with all capital
+	assert.True(t, benchmark.Outperformance.IsNegative())
+
+	report, err := engine.GenerateReport(result)
+	require.NoError(t, err)
+	assert.Same(t, benchmark, report.Benchmark)
+	assert.True(t, report.Summary.BenchmarkReturnPct.Equal(benchmark.TotalReturnPct))
+	assert.True(t, report.Summary.Outperformance.Equal(benchmark.Outperformance))
+}
+
+// TestBenchmarkNetOfCosts tests that buy-and-hold sizes its order so costs do not overdraw cash
+func TestBenchmarkNetOfCosts(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 10)
+	config.Commission = decimal.NewFromFloat(0.001)
+	config.Slippage = decimal.NewFromFloat(0.0005)
+	config.Benchmark = true
+
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(config))
+	t.Cleanup(func() { engine.Stop() })
+	require.NoError(t, engine.AddHistoricalData(trendingData(start, 10, 100, 0)))
+	require.NoError(t, engine.RegisterStrategy(newTestStrategy()))
+
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+	require.NotNil(t, result.Benchmark)
+
+	// On a flat market the only loss is the entry cost
+	curve := result.Benchmark.EquityCurve
+	require.NotEmpty(t, curve)
+	final := curve[len(curve)-1].Value
+	assert.True(t, final.LessThan(config.InitialCapital))
+	assert.True(t, final.GreaterThan(config.InitialCapital.Mul(decimal.NewFromFloat(0.998))), "final equity %s", final)
+}
+
+// TestBenchmarkDisabled tests that no benchmark is run unless configured
+func TestBenchmarkDisabled(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(testConfig(start, 5)))
+	t.Cleanup(func() { engine.Stop() })
+	require.NoError(t, engine.AddHistoricalData(trendingData(start, 5, 100, 1)))
+	require.NoError(t, engine.RegisterStrategy(newTestStrategy()))
+
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+	assert.Nil(t, result.Benchmark)
+}
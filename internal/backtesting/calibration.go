@@ -0,0 +1,299 @@
+package backtesting
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"velocimex/internal/numeric"
+	"velocimex/internal/orders"
+)
+
+// ErrNoPairedFills is returned when no predicted fill has a matching live fill
+var ErrNoPairedFills = errors.New("no predicted fills match a live fill")
+
+// FillRecord is one fill of an order, either predicted by a backtest or
+// recorded live. Predicted and live fills of the same order share an OrderKey.
+type FillRecord struct {
+	OrderKey       string          `json:"order_key"`
+	Symbol         string          `json:"symbol"`
+	Side           string          `json:"side"` // "BUY" or "SELL"
+	Quantity       decimal.Decimal `json:"quantity"`
+	Price          decimal.Decimal `json:"price"`
+	ReferencePrice decimal.Decimal `json:"reference_price"` // Price when the order was placed, slippage is measured from it
+	SubmittedAt    time.Time       `json:"submitted_at"`
+	FilledAt       time.Time       `json:"filled_at"`
+}
+
+// FillDiscrepancy compares the predicted and live fills of one order.
+// Slippage is in basis points of the reference price, positive when the fill
+// is worse than the reference. Errors are live minus predicted.
+type FillDiscrepancy struct {
+	OrderKey             string          `json:"order_key"`
+	Symbol               string          `json:"symbol"`
+	Side                 string          `json:"side"`
+	ReferencePrice       decimal.Decimal `json:"reference_price"`
+	PredictedPrice       decimal.Decimal `json:"predicted_price"`
+	ActualPrice          decimal.Decimal `json:"actual_price"`
+	PredictedQuantity    decimal.Decimal `json:"predicted_quantity"`
+	ActualQuantity       decimal.Decimal `json:"actual_quantity"`
+	PredictedSlippageBps decimal.Decimal `json:"predicted_slippage_bps"`
+	ActualSlippageBps    decimal.Decimal `json:"actual_slippage_bps"`
+	SlippageErrorBps     decimal.Decimal `json:"slippage_error_bps"`
+	PredictedDelay       time.Duration   `json:"predicted_delay"` // Submission to last fill
+	ActualDelay          time.Duration   `json:"actual_delay"`
+	TimingError          time.Duration   `json:"timing_error"`
+}
+
+// FillCalibrationReport summarises how far backtest fills are from live fills
+type FillCalibrationReport struct {
+	Paired             int               `json:"paired"`
+	UnmatchedPredicted []string          `json:"unmatched_predicted"` // Order keys with no live fill
+	UnmatchedActual    []string          `json:"unmatched_actual"`    // Order keys with no predicted fill
+	Discrepancies      []FillDiscrepancy `json:"discrepancies"`
+
+	MeanPredictedSlippageBps decimal.Decimal `json:"mean_predicted_slippage_bps"`
+	MeanActualSlippageBps    decimal.Decimal `json:"mean_actual_slippage_bps"`
+	MeanSlippageErrorBps     decimal.Decimal `json:"mean_slippage_error_bps"` // Positive when the backtest is optimistic
+	MeanAbsSlippageErrorBps  decimal.Decimal `json:"mean_abs_slippage_error_bps"`
+	MeanTimingError          time.Duration   `json:"mean_timing_error"` // Positive when live fills are slower
+	MeanAbsTimingError       time.Duration   `json:"mean_abs_timing_error"`
+
+	// SuggestedSlippage is the BacktestConfig.Slippage fraction matching the
+	// mean live slippage, and SuggestedLatency the matching Latency
+	SuggestedSlippage decimal.Decimal `json:"suggested_slippage"`
+	SuggestedLatency  time.Duration   `json:"suggested_latency"`
+}
+
+// orderFill aggregates the fills of one order
+type orderFill struct {
+	symbol      string
+	side        string
+	quantity    decimal.Decimal
+	notional    decimal.Decimal
+	reference   decimal.Decimal
+	submittedAt time.Time
+	filledAt    time.Time
+}
+
+// price returns the volume-weighted fill price
+func (f *orderFill) price() decimal.Decimal {
+	if !f.quantity.IsPositive() {
+		return decimal.Zero
+	}
+	return f.notional.Div(f.quantity)
+}
+
+// delay returns the time from submission to the last fill
+func (f *orderFill) delay() time.Duration {
+	if f.submittedAt.IsZero() {
+		return 0
+	}
+	return f.filledAt.Sub(f.submittedAt)
+}
+
+// aggregateFills groups fills by order, volume-weighting partial fills
+func aggregateFills(fills []FillRecord) map[string]*orderFill {
+	byOrder := make(map[string]*orderFill)
+	for _, fill := range fills {
+		agg, ok := byOrder[fill.OrderKey]
+		if !ok {
+			agg = &orderFill{symbol: fill.Symbol, side: fill.Side, submittedAt: fill.SubmittedAt}
+			byOrder[fill.OrderKey] = agg
+		}
+		agg.quantity = agg.quantity.Add(fill.Quantity)
+		agg.notional = agg.notional.Add(fill.Quantity.Mul(fill.Price))
+		if agg.reference.IsZero() {
+			agg.reference = fill.ReferencePrice
+		}
+		if agg.submittedAt.IsZero() || (!fill.SubmittedAt.IsZero() && fill.SubmittedAt.Before(agg.submittedAt)) {
+			agg.submittedAt = fill.SubmittedAt
+		}
+		if fill.FilledAt.After(agg.filledAt) {
+			agg.filledAt = fill.FilledAt
+		}
+	}
+	return byOrder
+}
+
+// slippageBps measures a fill price from a reference, positive when worse
+func slippageBps(side string, price, reference decimal.Decimal) decimal.Decimal {
+	diff := price.Sub(reference)
+	if side == "SELL" {
+		diff = diff.Neg()
+	}
+	return diff.Div(reference).Mul(bpsMultiplier)
+}
+
+// CompareFills pairs predicted and live fills by order key and reports the
+// discrepancy in slippage and timing. Slippage of both is measured from the
+// live reference price, falling back to the predicted one, so they share a
+// baseline. Orders present on only one side are listed but not compared.
+func CompareFills(predicted, actual []FillRecord) (*FillCalibrationReport, error) {
+	predictedOrders := aggregateFills(predicted)
+	actualOrders := aggregateFills(actual)
+
+	report := &FillCalibrationReport{
+		UnmatchedPredicted: make([]string, 0),
+		UnmatchedActual:    make([]string, 0),
+		Discrepancies:      make([]FillDiscrepancy, 0),
+	}
+
+	keys := make([]string, 0, len(predictedOrders))
+	for key := range predictedOrders {
+		if _, ok := actualOrders[key]; !ok {
+			report.UnmatchedPredicted = append(report.UnmatchedPredicted, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	for key := range actualOrders {
+		if _, ok := predictedOrders[key]; !ok {
+			report.UnmatchedActual = append(report.UnmatchedActual, key)
+		}
+	}
+	sort.Strings(keys)
+	sort.Strings(report.UnmatchedPredicted)
+	sort.Strings(report.UnmatchedActual)
+
+	if len(keys) == 0 {
+		return nil, ErrNoPairedFills
+	}
+
+	sumPredicted, sumActual := decimal.Zero, decimal.Zero
+	sumError, sumAbsError := decimal.Zero, decimal.Zero
+	var sumActualDelay, sumTiming, sumAbsTiming time.Duration
+	for _, key := range keys {
+		p, a := predictedOrders[key], actualOrders[key]
+		if p.side != a.side {
+			return nil, fmt.Errorf("order %s predicted %s but filled %s live", key, p.side, a.side)
+		}
+		if !p.quantity.IsPositive() || !a.quantity.IsPositive() {
+			return nil, fmt.Errorf("order %s has no filled quantity", key)
+		}
+
+		reference := a.reference
+		if !reference.IsPositive() {
+			reference = p.reference
+		}
+		if !reference.IsPositive() {
+			return nil, fmt.Errorf("order %s has no reference price", key)
+		}
+
+		predictedBps := slippageBps(p.side, p.price(), reference)
+		actualBps := slippageBps(a.side, a.price(), reference)
+		errorBps := actualBps.Sub(predictedBps)
+		timing := a.delay() - p.delay()
+
+		report.Discrepancies = append(report.Discrepancies, FillDiscrepancy{
+			OrderKey:             key,
+			Symbol:               a.symbol,
+			Side:                 a.side,
+			ReferencePrice:       reference,
+			PredictedPrice:       p.price(),
+			ActualPrice:          a.price(),
+			PredictedQuantity:    p.quantity,
+			ActualQuantity:       a.quantity,
+			PredictedSlippageBps: numeric.Round(predictedBps),
+			ActualSlippageBps:    numeric.Round(actualBps),
+			SlippageErrorBps:     numeric.Round(errorBps),
+			PredictedDelay:       p.delay(),
+			ActualDelay:          a.delay(),
+			TimingError:          timing,
+		})
+
+		sumPredicted = sumPredicted.Add(predictedBps)
+		sumActual = sumActual.Add(actualBps)
+		sumError = sumError.Add(errorBps)
+		sumAbsError = sumAbsError.Add(errorBps.Abs())
+		sumActualDelay += a.delay()
+		sumTiming += timing
+		if timing < 0 {
+			timing = -timing
+		}
+		sumAbsTiming += timing
+	}
+
+	n := len(keys)
+	count := decimal.NewFromInt(int64(n))
+	report.Paired = n
+	report.MeanPredictedSlippageBps = numeric.Round(sumPredicted.Div(count))
+	report.MeanActualSlippageBps = numeric.Round(sumActual.Div(count))
+	report.MeanSlippageErrorBps = numeric.Round(sumError.Div(count))
+	report.MeanAbsSlippageErrorBps = numeric.Round(sumAbsError.Div(count))
+	report.MeanTimingError = sumTiming / time.Duration(n)
+	report.MeanAbsTimingError = sumAbsTiming / time.Duration(n)
+
+	// The slippage model only adds cost, so price improvement suggests none
+	if suggested := sumActual.Div(count).Div(bpsMultiplier); suggested.IsPositive() {
+		report.SuggestedSlippage = numeric.Round(suggested)
+	} else {
+		report.SuggestedSlippage = decimal.Zero
+	}
+	if meanDelay := sumActualDelay / time.Duration(n); meanDelay > 0 {
+		report.SuggestedLatency = meanDelay
+	}
+
+	return report, nil
+}
+
+// PredictedFills converts backtest trades into fill records. The order key is
+// the trade's "order_key" metadata when set, otherwise its ID. The reference
+// is the signal price and the fill lands after the configured latency.
+func PredictedFills(trades []*BacktestTrade, config BacktestConfig) []FillRecord {
+	fills := make([]FillRecord, 0, len(trades))
+	for _, trade := range trades {
+		key := trade.ID
+		if metaKey, ok := trade.Metadata["order_key"].(string); ok && metaKey != "" {
+			key = metaKey
+		}
+
+		price := trade.EntryPrice
+		if trade.Quantity.IsPositive() && !trade.Slippage.IsZero() {
+			perUnit := trade.Slippage.Div(trade.Quantity)
+			if trade.Side == "SELL" {
+				perUnit = perUnit.Neg()
+			}
+			price = price.Add(perUnit)
+		}
+
+		fills = append(fills, FillRecord{
+			OrderKey:       key,
+			Symbol:         trade.Symbol,
+			Side:           trade.Side,
+			Quantity:       trade.Quantity,
+			Price:          price,
+			ReferencePrice: trade.EntryPrice,
+			SubmittedAt:    trade.EntryTime,
+			FilledAt:       trade.EntryTime.Add(config.Latency),
+		})
+	}
+	return fills
+}
+
+// LiveFills converts the executions of a live order into fill records keyed
+// by the order's client ID, or its ID when it has none. The reference is the
+// price when the order was placed, e.g. the arrival mid.
+func LiveFills(order *orders.Order, executions []*orders.Execution, referencePrice decimal.Decimal) []FillRecord {
+	key := order.ClientID
+	if key == "" {
+		key = order.ID
+	}
+
+	fills := make([]FillRecord, 0, len(executions))
+	for _, execution := range executions {
+		fills = append(fills, FillRecord{
+			OrderKey:       key,
+			Symbol:         order.Symbol,
+			Side:           string(order.Side),
+			Quantity:       execution.Quantity,
+			Price:          execution.Price,
+			ReferencePrice: referencePrice,
+			SubmittedAt:    order.CreatedAt,
+			FilledAt:       execution.Timestamp,
+		})
+	}
+	return fills
+}
@@ -0,0 +1,137 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/orders"
+)
+
+func dec(v float64) decimal.Decimal { return decimal.NewFromFloat(v) }
+
+// TestCompareFills tests slippage and timing discrepancies of paired fills
+func TestCompareFills(t *testing.T) {
+	submitted := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	predicted := []FillRecord{
+		// Buy predicted 5 bps worse than the reference, filled after 10ms
+		{OrderKey: "a", Symbol: "BTC/USD", Side: "BUY", Quantity: dec(1), Price: dec(100.05), ReferencePrice: dec(100), SubmittedAt: submitted, FilledAt: submitted.Add(10 * time.Millisecond)},
+		// Sell predicted 5 bps worse
+		{OrderKey: "b", Symbol: "BTC/USD", Side: "SELL", Quantity: dec(2), Price: dec(199.90), ReferencePrice: dec(200), SubmittedAt: submitted, FilledAt: submitted.Add(10 * time.Millisecond)},
+		{OrderKey: "only-predicted", Symbol: "BTC/USD", Side: "BUY", Quantity: dec(1), Price: dec(100), ReferencePrice: dec(100)},
+	}
+	actual := []FillRecord{
+		// Buy filled in two parts at a VWAP of 100.15, 15 bps, the last after 50ms
+		{OrderKey: "a", Symbol: "BTC/USD", Side: "BUY", Quantity: dec(0.5), Price: dec(100.10), ReferencePrice: dec(100), SubmittedAt: submitted, FilledAt: submitted.Add(30 * time.Millisecond)},
+		{OrderKey: "a", Symbol: "BTC/USD", Side: "BUY", Quantity: dec(0.5), Price: dec(100.20), ReferencePrice: dec(100), SubmittedAt: submitted, FilledAt: submitted.Add(50 * time.Millisecond)},
+		// Sell filled at 199.90, 5 bps as predicted, after 30ms
+		{OrderKey: "b", Symbol: "BTC/USD", Side: "SELL", Quantity: dec(2), Price: dec(199.90), ReferencePrice: dec(200), SubmittedAt: submitted, FilledAt: submitted.Add(30 * time.Millisecond)},
+		{OrderKey: "only-actual", Symbol: "BTC/USD", Side: "SELL", Quantity: dec(1), Price: dec(100), ReferencePrice: dec(100)},
+	}
+
+	report, err := CompareFills(predicted, actual)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.Paired)
+	assert.Equal(t, []string{"only-predicted"}, report.UnmatchedPredicted)
+	assert.Equal(t, []string{"only-actual"}, report.UnmatchedActual)
+	require.Len(t, report.Discrepancies, 2)
+
+	buy := report.Discrepancies[0]
+	assert.Equal(t, "a", buy.OrderKey)
+	assert.True(t, buy.ActualPrice.Equal(dec(100.15)), "actual price %s", buy.ActualPrice)
+	assert.True(t, buy.ActualQuantity.Equal(dec(1)))
+	assert.True(t, buy.PredictedSlippageBps.Equal(dec(5)), "predicted %s", buy.PredictedSlippageBps)
+	assert.True(t, buy.ActualSlippageBps.Equal(dec(15)), "actual %s", buy.ActualSlippageBps)
+	assert.True(t, buy.SlippageErrorBps.Equal(dec(10)), "error %s", buy.SlippageErrorBps)
+	assert.Equal(t, 50*time.Millisecond, buy.ActualDelay)
+	assert.Equal(t, 40*time.Millisecond, buy.TimingError)
+
+	sell := report.Discrepancies[1]
+	assert.True(t, sell.PredictedSlippageBps.Equal(dec(5)), "predicted %s", sell.PredictedSlippageBps)
+	assert.True(t, sell.SlippageErrorBps.IsZero(), "error %s", sell.SlippageErrorBps)
+	assert.Equal(t, 20*time.Millisecond, sell.TimingError)
+
+	assert.True(t, report.MeanPredictedSlippageBps.Equal(dec(5)))
+	assert.True(t, report.MeanActualSlippageBps.Equal(dec(10)))
+	assert.True(t, report.MeanSlippageErrorBps.Equal(dec(5)))
+	assert.True(t, report.MeanAbsSlippageErrorBps.Equal(dec(5)))
+	assert.Equal(t, 30*time.Millisecond, report.MeanTimingError)
+	assert.Equal(t, 30*time.Millisecond, report.MeanAbsTimingError)
+	assert.True(t, report.SuggestedSlippage.Equal(dec(0.001)), "suggested %s", report.SuggestedSlippage)
+	assert.Equal(t, 40*time.Millisecond, report.SuggestedLatency)
+}
+
+// TestCompareFillsPriceImprovement tests that live price improvement suggests no slippage
+func TestCompareFillsPriceImprovement(t *testing.T) {
+	predicted := []FillRecord{{OrderKey: "a", Side: "BUY", Quantity: dec(1), Price: dec(100.10), ReferencePrice: dec(100)}}
+	actual := []FillRecord{{OrderKey: "a", Side: "BUY", Quantity: dec(1), Price: dec(99.90), ReferencePrice: dec(100)}}
+
+	report, err := CompareFills(predicted, actual)
+	require.NoError(t, err)
+	assert.True(t, report.MeanSlippageErrorBps.Equal(dec(-20)), "error %s", report.MeanSlippageErrorBps)
+	assert.True(t, report.MeanAbsSlippageErrorBps.Equal(dec(20)))
+	assert.True(t, report.SuggestedSlippage.IsZero())
+}
+
+// TestCompareFillsErrors tests inputs that cannot be compared
+func TestCompareFillsErrors(t *testing.T) {
+	_, err := CompareFills(
+		[]FillRecord{{OrderKey: "a", Side: "BUY", Quantity: dec(1), Price: dec(100), ReferencePrice: dec(100)}},
+		[]FillRecord{{OrderKey: "b", Side: "BUY", Quantity: dec(1), Price: dec(100), ReferencePrice: dec(100)}},
+	)
+	assert.ErrorIs(t, err, ErrNoPairedFills)
+
+	_, err = CompareFills(
+		[]FillRecord{{OrderKey: "a", Side: "BUY", Quantity: dec(1), Price: dec(100), ReferencePrice: dec(100)}},
+		[]FillRecord{{OrderKey: "a", Side: "SELL", Quantity: dec(1), Price: dec(100), ReferencePrice: dec(100)}},
+	)
+	assert.Error(t, err)
+
+	_, err = CompareFills(
+		[]FillRecord{{OrderKey: "a", Side: "BUY", Quantity: dec(1), Price: dec(100)}},
+		[]FillRecord{{OrderKey: "a", Side: "BUY", Quantity: dec(1), Price: dec(100)}},
+	)
+	assert.Error(t, err)
+}
+
+// TestCalibrateBacktestAgainstLiveFills tests building fill records from a
+// backtest trade and live executions of the same order
+func TestCalibrateBacktestAgainstLiveFills(t *testing.T) {
+	entry := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	config := DefaultBacktestConfig()
+	config.Latency = 10 * time.Millisecond
+
+	// A buy of 2 at 100 with 0.05% modeled slippage
+	trades := []*BacktestTrade{{
+		ID:         "trade-1",
+		Symbol:     "BTC/USD",
+		Side:       "BUY",
+		Quantity:   dec(2),
+		EntryPrice: dec(100),
+		EntryTime:  entry,
+		Slippage:   dec(100).Mul(dec(2)).Mul(dec(0.0005)),
+		Metadata:   map[string]interface{}{"order_key": "client-1"},
+	}}
+	predicted := PredictedFills(trades, config)
+	require.Len(t, predicted, 1)
+	assert.Equal(t, "client-1", predicted[0].OrderKey)
+	assert.True(t, predicted[0].Price.Equal(dec(100.05)), "predicted price %s", predicted[0].Price)
+	assert.Equal(t, entry.Add(10*time.Millisecond), predicted[0].FilledAt)
+
+	order := &orders.Order{ID: "order-1", ClientID: "client-1", Symbol: "BTC/USD", Side: orders.OrderSideBuy, CreatedAt: entry}
+	executions := []*orders.Execution{
+		{Quantity: dec(1), Price: dec(100.10), Timestamp: entry.Add(20 * time.Millisecond)},
+		{Quantity: dec(1), Price: dec(100.10), Timestamp: entry.Add(25 * time.Millisecond)},
+	}
+	actual := LiveFills(order, executions, dec(100))
+
+	report, err := CompareFills(predicted, actual)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Paired)
+	assert.True(t, report.MeanSlippageErrorBps.Equal(dec(5)), "error %s", report.MeanSlippageErrorBps)
+	assert.Equal(t, 15*time.Millisecond, report.MeanTimingError)
+}
@@ -0,0 +1,245 @@
+package backtesting
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"velocimex/internal/risk"
+	"velocimex/internal/strategy"
+)
+
+// Checkpoint is the engine state saved periodically during a run so a
+// crashed backtest can continue with ResumeBacktest instead of restarting.
+// Strategy state is not saved; it is rebuilt on resume by replaying the
+// data before the checkpoint to the strategy.
+type Checkpoint struct {
+	StrategyID       string                           `json:"strategy_id"`
+	Config           BacktestConfig                   `json:"config"`
+	CurrentTime      time.Time                        `json:"current_time"` // Next tick to run
+	Ticks            int                              `json:"ticks"`        // Ticks completed before the checkpoint
+	WarmupTicks      int                              `json:"warmup_ticks"`
+	Portfolio        *risk.Portfolio                  `json:"portfolio,omitempty"`
+	PortfolioHistory []*PortfolioSnapshot             `json:"portfolio_history"`
+	Trades           []*BacktestTrade                 `json:"trades"`
+	RiskEvents       []*risk.RiskEvent                `json:"risk_events"`
+	TotalCommission  decimal.Decimal                  `json:"total_commission"`
+	TotalSlippage    decimal.Decimal                  `json:"total_slippage"`
+	ExecutionTimes   []time.Duration                  `json:"execution_times"`
+	Latency          latencyCheckpoint                `json:"latency"`
+	Drawdown         drawdownCheckpoint               `json:"drawdown"`
+	RestingOrders    []restingCheckpoint              `json:"resting_orders"`
+	PendingSignals   map[string]strategy.SignalWindow `json:"pending_signals,omitempty"` // Signals aggregating in open windows
+	ClockTime        time.Time                        `json:"clock_time,omitempty"`      // Simulated clock time in fast mode
+	SavedAt          time.Time                        `json:"saved_at"`
+}
+
+// latencyCheckpoint is the saved state of the latency recorder
+type latencyCheckpoint struct {
+	Samples int           `json:"samples"`
+	Total   time.Duration `json:"total"`
+	Max     time.Duration `json:"max"`
+}
+
+// drawdownCheckpoint is the saved state of the drawdown monitor
+type drawdownCheckpoint struct {
+	Peak     decimal.Decimal  `json:"peak"`
+	Below    bool             `json:"below"`
+	Breaches []*DrawdownEvent `json:"breaches"`
+}
+
+// restingCheckpoint is a saved resting limit order; its strategy is the run's strategy
+type restingCheckpoint struct {
+	Signal     *strategy.Signal `json:"signal"`
+	PlacedAt   time.Time        `json:"placed_at"`
+	QueueAhead decimal.Decimal  `json:"queue_ahead"`
+	Traded     decimal.Decimal  `json:"traded"`
+}
+
+// maybeCheckpoint saves a checkpoint every CheckpointInterval ticks. A failed
+// write is logged rather than failing the run.
+func (e *Engine) maybeCheckpoint(strategyID string) {
+	if e.config.CheckpointPath == "" || e.config.CheckpointInterval <= 0 || e.ticks%e.config.CheckpointInterval != 0 {
+		return
+	}
+	if err := e.saveCheckpoint(strategyID); err != nil {
+		log.Printf("Failed to checkpoint backtest for strategy %s: %v", strategyID, err)
+	}
+}
+
+// saveCheckpoint writes the current run state to the checkpoint path. The file
+// is replaced atomically so a crash mid-write leaves the previous checkpoint intact.
+func (e *Engine) saveCheckpoint(strategyID string) error {
+	checkpoint := &Checkpoint{
+		StrategyID:       strategyID,
+		Config:           e.config,
+		CurrentTime:      e.currentTime,
+		Ticks:            e.ticks,
+		WarmupTicks:      e.warmupTicks,
+		PortfolioHistory: e.portfolioHistory,
+		Trades:           e.trades,
+		RiskEvents:       e.riskEvents,
+		TotalCommission:  e.totalCommission,
+		TotalSlippage:    e.totalSlippage,
+		ExecutionTimes:   e.executionTimes,
+		Latency:          latencyCheckpoint{Samples: e.latency.samples, Total: e.latency.total, Max: e.latency.max},
+		Drawdown:         drawdownCheckpoint{Peak: e.drawdown.peak, Below: e.drawdown.below, Breaches: e.drawdown.breaches},
+		SavedAt:          time.Now(),
+	}
+	if e.riskManager != nil {
+		checkpoint.Portfolio = e.riskManager.GetPortfolio()
+	}
+	if e.config.FastMode && e.clock != nil {
+		checkpoint.ClockTime = e.clock.Now()
+	}
+	if e.aggregator != nil {
+		checkpoint.PendingSignals = e.aggregator.Pending()
+	}
+	for _, order := range e.restingOrders {
+		checkpoint.RestingOrders = append(checkpoint.RestingOrders, restingCheckpoint{
+			Signal:     order.signal,
+			PlacedAt:   order.placedAt,
+			QueueAhead: order.queueAhead,
+			Traded:     order.traded,
+		})
+	}
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	tmp := e.config.CheckpointPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, e.config.CheckpointPath); err != nil {
+		return fmt.Errorf("failed to replace checkpoint: %w", err)
+	}
+	return nil
+}
+
+// LoadCheckpoint reads a checkpoint written during a backtest run
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+// ResumeBacktest continues a backtest from the checkpoint at the given path.
+// The strategy must be registered and the historical data loaded as for the
+// original run. The strategy is first replayed over the data before the
+// checkpoint, discarding its signals, so deterministic strategies rebuild
+// the state they had when the checkpoint was written.
+func (e *Engine) ResumeBacktest(checkpointPath string) (*BacktestResult, error) {
+	checkpoint, err := LoadCheckpoint(checkpointPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fresh order and risk managers, as for a new run
+	if err := e.SetConfig(checkpoint.Config); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	strategy, exists := e.strategies[checkpoint.StrategyID]
+	if !exists {
+		return nil, fmt.Errorf("strategy not found: %s", checkpoint.StrategyID)
+	}
+
+	e.progress.begin(checkpoint.StrategyID, e.config, checkpoint.Ticks, checkpoint.CurrentTime)
+	defer e.progress.finish()
+
+	loadStart := time.Now()
+	if err := e.resampleHistoricalData(); err != nil {
+		return nil, err
+	}
+	e.progress.addPhase(PhaseDataLoading, loadStart)
+
+	// Rebuild the strategy's state before restoring the booked state
+	e.running = true
+	e.paused = false
+	e.runWarmup(strategy)
+	e.replayStrategy(strategy, checkpoint.CurrentTime)
+
+	e.currentTime = checkpoint.CurrentTime
+	e.ticks = checkpoint.Ticks
+	e.openingValue = e.openingPortfolio().TotalValue
+	e.warmupTicks = checkpoint.WarmupTicks
+	e.portfolioHistory = checkpoint.PortfolioHistory
+	e.trades = checkpoint.Trades
+	e.riskEvents = checkpoint.RiskEvents
+	e.totalCommission = checkpoint.TotalCommission
+	e.totalSlippage = checkpoint.TotalSlippage
+	e.executionTimes = checkpoint.ExecutionTimes
+	e.latency = latencyRecorder{samples: checkpoint.Latency.Samples, total: checkpoint.Latency.Total, max: checkpoint.Latency.Max}
+	e.drawdown = drawdownMonitor{
+		limit:    e.config.DrawdownLimit,
+		peak:     checkpoint.Drawdown.Peak,
+		below:    checkpoint.Drawdown.Below,
+		breaches: checkpoint.Drawdown.Breaches,
+	}
+	e.restingOrders = nil
+	for _, order := range checkpoint.RestingOrders {
+		e.restingOrders = append(e.restingOrders, &restingOrder{
+			signal:     order.Signal,
+			strategy:   strategy,
+			placedAt:   order.PlacedAt,
+			queueAhead: order.QueueAhead,
+			traded:     order.Traded,
+		})
+	}
+	e.aggregator = newSignalAggregator(e.config.SignalWindow)
+	if e.aggregator != nil {
+		e.aggregator.Restore(checkpoint.PendingSignals)
+	}
+	e.clock = newClock(e.config)
+	if e.config.FastMode && !checkpoint.ClockTime.IsZero() {
+		e.clock = newSimulatedClock(checkpoint.ClockTime)
+	}
+	if e.riskManager != nil && checkpoint.Portfolio != nil {
+		e.riskManager.UpdatePortfolio(checkpoint.Portfolio)
+	}
+
+	startTime := time.Now()
+	log.Printf("Resuming backtest for strategy %s at %s from checkpoint %s", checkpoint.StrategyID, checkpoint.CurrentTime, checkpointPath)
+
+	if err := e.runBacktestLoop(strategy); err != nil {
+		return nil, fmt.Errorf("backtest failed: %v", err)
+	}
+
+	result := e.calculateBacktestResult(checkpoint.StrategyID, time.Since(startTime))
+	result.Phases = e.progress.snapshot().Phases
+	e.attachBenchmark(result)
+	e.storeResult(result)
+	e.recordMetrics(checkpoint.StrategyID, result)
+
+	log.Printf("Resumed backtest completed in %v", time.Since(startTime))
+	return result, nil
+}
+
+// replayStrategy runs the strategy over the ticks from StartDate up to but
+// excluding the given time, discarding its signals and booking nothing
+func (e *Engine) replayStrategy(strategy strategy.Strategy, until time.Time) {
+	for e.currentTime = e.config.StartDate; e.currentTime.Before(until); e.currentTime = e.currentTime.Add(e.config.DataFrequency) {
+		if err := e.updateMarketData(); err != nil {
+			log.Printf("Error updating market data during replay: %v", err)
+		}
+		if _, err := strategy.GenerateSignals(e.currentOrderBooks()); err != nil {
+			log.Printf("Error running strategy during replay: %v", err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package backtesting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/orderbook"
+	"velocimex/internal/strategy"
+)
+
+// crashingStrategy panics on its crashAt-th call to simulate the process dying mid-run
+type crashingStrategy struct {
+	strategy.Strategy
+	calls   int
+	crashAt int
+}
+
+func (s *crashingStrategy) GenerateSignals(orderBooks map[string]*orderbook.OrderBook) ([]*strategy.Signal, error) {
+	s.calls++
+	if s.calls == s.crashAt {
+		panic("simulated crash")
+	}
+	return s.Strategy.GenerateSignals(orderBooks)
+}
+
+// oscillatingData returns data alternating between two prices so rebalancing trades on every tick
+func oscillatingData(start time.Time, ticks int) *HistoricalData {
+	data := trendingData(start, ticks, 100, 0)
+	for i, point := range data.DataPoints {
+		price := decimal.NewFromFloat(100.125)
+		if i%2 == 1 {
+			price = decimal.NewFromFloat(120.375)
+		}
+		point.Open, point.High, point.Low, point.Close = price, price, price, price
+		point.Bid, point.Ask = price, price
+	}
+	return data
+}
+
+// newCheckpointEngine sets up a rebalancing backtest over 30 ticks of oscillating prices
+func newCheckpointEngine(t *testing.T, config BacktestConfig, wrap func(strategy.Strategy) strategy.Strategy) *Engine {
+	t.Helper()
+
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(config))
+	t.Cleanup(func() { engine.Stop() })
+
+	require.NoError(t, engine.AddHistoricalData(oscillatingData(config.StartDate, 30)))
+	var s strategy.Strategy = strategy.NewRebalanceStrategy(strategy.RebalanceConfig{
+		Exchange:       "test",
+		TargetWeights:  map[string]float64{"BTC/USD": 0.5},
+		DriftThreshold: 0.01,
+		InitialCash:    10000,
+	})
+	if wrap != nil {
+		s = wrap(s)
+	}
+	require.NoError(t, engine.RegisterStrategy(s))
+	return engine
+}
+
+// TestResumeBacktestMatchesUninterruptedRun tests that a run crashing partway
+// and resumed from its last checkpoint ends exactly where an uninterrupted run does
+func TestResumeBacktestMatchesUninterruptedRun(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 30)
+
+	expected, err := newCheckpointEngine(t, config, nil).RunBacktestWithStrategy("rebalance")
+	require.NoError(t, err)
+	require.Greater(t, expected.TotalTrades, 2)
+
+	config.CheckpointPath = filepath.Join(t.TempDir(), "backtest.checkpoint")
+	config.CheckpointInterval = 5
+
+	// Crash on tick 18, after the checkpoint written at tick 15
+	crashed := newCheckpointEngine(t, config, func(s strategy.Strategy) strategy.Strategy {
+		return &crashingStrategy{Strategy: s, crashAt: 18}
+	})
+	assert.Panics(t, func() { crashed.RunBacktestWithStrategy("rebalance") })
+
+	checkpoint, err := LoadCheckpoint(config.CheckpointPath)
+	require.NoError(t, err)
+	assert.Equal(t, 15, checkpoint.Ticks)
+	assert.True(t, checkpoint.CurrentTime.Equal(start.Add(15*time.Minute)))
+	assert.NotEmpty(t, checkpoint.Trades)
+
+	// Resume in a fresh engine, as after a restart
+	resumed, err := newCheckpointEngine(t, testConfig(start, 30), nil).ResumeBacktest(config.CheckpointPath)
+	require.NoError(t, err)
+
+	assert.True(t, expected.FinalCapital.Equal(resumed.FinalCapital), "final capital %s, want %s", resumed.FinalCapital, expected.FinalCapital)
+	assert.True(t, expected.TotalReturn.Equal(resumed.TotalReturn))
+	assert.True(t, expected.MaxDrawdown.Equal(resumed.MaxDrawdown))
+	assert.Len(t, resumed.PortfolioHistory, len(expected.PortfolioHistory))
+	require.Equal(t, expected.TotalTrades, resumed.TotalTrades)
+	for i, trade := range expected.Trades {
+		got := resumed.Trades[i]
+		assert.Equal(t, trade.Side, got.Side, "trade %d", i)
+		assert.True(t, trade.Quantity.Equal(got.Quantity), "trade %d quantity %s, want %s", i, got.Quantity, trade.Quantity)
+		assert.True(t, trade.EntryPrice.Equal(got.EntryPrice), "trade %d price %s, want %s", i, got.EntryPrice, trade.EntryPrice)
+		assert.True(t, trade.EntryTime.Equal(got.EntryTime), "trade %d time", i)
+	}
+}
+
+// TestCheckpointingDisabled tests that no checkpoint is written without an interval
+func TestCheckpointingDisabled(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 30)
+	config.CheckpointPath = filepath.Join(t.TempDir(), "backtest.checkpoint")
+
+	_, err := newCheckpointEngine(t, config, nil).RunBacktestWithStrategy("rebalance")
+	require.NoError(t, err)
+
+	_, err = os.Stat(config.CheckpointPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+// TestResumeBacktestErrors tests resuming from a missing checkpoint or for an unregistered strategy
+func TestResumeBacktestErrors(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 10)
+	config.CheckpointPath = filepath.Join(t.TempDir(), "backtest.checkpoint")
+	config.CheckpointInterval = 5
+
+	engine := newCheckpointEngine(t, config, nil)
+	_, err := engine.ResumeBacktest(config.CheckpointPath)
+	assert.Error(t, err)
+
+	_, err = engine.RunBacktestWithStrategy("rebalance")
+	require.NoError(t, err)
+
+	other := NewEngine()
+	t.Cleanup(func() { other.Stop() })
+	_, err = other.ResumeBacktest(config.CheckpointPath)
+	assert.ErrorContains(t, err, "strategy not found")
+}
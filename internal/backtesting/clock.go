@@ -0,0 +1,75 @@
+package backtesting
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock controls how the backtest loop waits for simulated latency
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// realClock waits on the wall clock
+type realClock struct{}
+
+func (realClock) Now() time.Time        { return time.Now() }
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// simulatedClock advances instantly, so latency is modeled without blocking
+type simulatedClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// newSimulatedClock creates a clock starting at the given time
+func newSimulatedClock(start time.Time) *simulatedClock {
+	return &simulatedClock{now: start}
+}
+
+func (c *simulatedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *simulatedClock) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newClock returns the clock for a run: simulated in fast mode, wall clock otherwise
+func newClock(config BacktestConfig) Clock {
+	if config.FastMode {
+		return newSimulatedClock(config.StartDate)
+	}
+	return realClock{}
+}
+
+// latencyRecorder accumulates modeled latency samples
+type latencyRecorder struct {
+	samples int
+	total   time.Duration
+	max     time.Duration
+}
+
+func (r *latencyRecorder) record(d time.Duration) {
+	r.samples++
+	r.total += d
+	if d > r.max {
+		r.max = d
+	}
+}
+
+func (r *latencyRecorder) stats() LatencyStats {
+	stats := LatencyStats{Samples: r.samples, Total: r.total, Max: r.max}
+	if r.samples > 0 {
+		stats.Mean = r.total / time.Duration(r.samples)
+	}
+	return stats
+}
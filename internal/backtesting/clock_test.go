@@ -0,0 +1,60 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runLatencyBacktest(t *testing.T, fast bool) (*BacktestResult, time.Duration) {
+	t.Helper()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 40)
+	config.Latency = 5 * time.Millisecond
+	config.FastMode = fast
+
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(config))
+	defer engine.Stop()
+	require.NoError(t, engine.AddHistoricalData(trendingData(start, 40, 100, 1)))
+	require.NoError(t, engine.RegisterStrategy(newTestStrategy()))
+
+	began := time.Now()
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+	return result, time.Since(began)
+}
+
+// TestFastModeLatency tests that fast mode skips real sleeps but models the same latency
+func TestFastModeLatency(t *testing.T) {
+	slow, slowElapsed := runLatencyBacktest(t, false)
+	fast, fastElapsed := runLatencyBacktest(t, true)
+
+	// 40 ticks at 5ms is at least 200ms of real sleeping
+	assert.GreaterOrEqual(t, slowElapsed, 200*time.Millisecond)
+	assert.Less(t, fastElapsed, 100*time.Millisecond)
+
+	assert.Equal(t, slow.Latency, fast.Latency)
+	assert.Equal(t, 40, fast.Latency.Samples)
+	assert.Equal(t, 200*time.Millisecond, fast.Latency.Total)
+	assert.Equal(t, 5*time.Millisecond, fast.Latency.Mean)
+	assert.Equal(t, 5*time.Millisecond, fast.Latency.Max)
+
+	assert.Equal(t, slow.TotalTrades, fast.TotalTrades)
+	assert.True(t, slow.FinalCapital.Equal(fast.FinalCapital))
+}
+
+// TestSimulatedClock tests that the simulated clock advances without blocking
+func TestSimulatedClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := newSimulatedClock(start)
+
+	began := time.Now()
+	clock.Sleep(time.Hour)
+	clock.Sleep(-time.Second)
+	assert.Less(t, time.Since(began), 50*time.Millisecond)
+	assert.Equal(t, start.Add(time.Hour), clock.Now())
+}
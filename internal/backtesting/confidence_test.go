@@ -0,0 +1,79 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/orderbook"
+	"velocimex/internal/strategy"
+)
+
+// confidenceStrategy signals every tick, cycling through the given confidences
+// and reporting each signal's confidence as its quantity
+type confidenceStrategy struct {
+	testStrategy
+	confidences []float64
+	ticks       int
+}
+
+func (s *confidenceStrategy) GenerateSignals(orderBooks map[string]*orderbook.OrderBook) ([]*strategy.Signal, error) {
+	confidence := s.confidences[s.ticks%len(s.confidences)]
+	s.ticks++
+	return []*strategy.Signal{{
+		Symbol:   "BTC/USD",
+		Exchange: "test",
+		Side:     "BUY",
+		Quantity: decimal.NewFromFloat(confidence),
+		Price:    decimal.NewFromInt(100),
+		Metadata: map[string]interface{}{"confidence": confidence},
+	}}, nil
+}
+
+func runConfidenceBacktest(t *testing.T, gate strategy.ConfidenceGateConfig) *BacktestResult {
+	t.Helper()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 10)
+	config.ConfidenceGate = gate
+
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(config))
+	defer engine.Stop()
+	require.NoError(t, engine.AddHistoricalData(trendingData(start, 10, 100, 0)))
+	s := &confidenceStrategy{testStrategy: *newTestStrategy(), confidences: []float64{0.2, 0.9}}
+	require.NoError(t, engine.RegisterStrategy(s))
+
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+	return result
+}
+
+// TestConfidenceGate tests that only signals meeting the strategy's minimum
+// confidence are executed
+func TestConfidenceGate(t *testing.T) {
+	assert.Len(t, runConfidenceBacktest(t, strategy.ConfidenceGateConfig{}).Trades, 10)
+
+	result := runConfidenceBacktest(t, strategy.ConfidenceGateConfig{MinConfidence: 0.5})
+	require.Len(t, result.Trades, 5)
+	for _, trade := range result.Trades {
+		assert.True(t, trade.Quantity.Equal(decimal.NewFromFloat(0.9)), trade.Quantity.String())
+	}
+
+	// A strategy's own minimum overrides the default
+	result = runConfidenceBacktest(t, strategy.ConfidenceGateConfig{
+		MinConfidence: 0.5,
+		Strategies:    map[string]float64{"test": 0.1},
+	})
+	assert.Len(t, result.Trades, 10)
+	result = runConfidenceBacktest(t, strategy.ConfidenceGateConfig{Strategies: map[string]float64{"test": 0.95}})
+	assert.Empty(t, result.Trades)
+
+	engine := NewEngine()
+	defer engine.Stop()
+	config := testConfig(time.Now(), 10)
+	config.ConfidenceGate.MinConfidence = 1.5
+	assert.Error(t, engine.SetConfig(config))
+}
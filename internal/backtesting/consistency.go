@@ -0,0 +1,203 @@
+package backtesting
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"velocimex/internal/orderbook"
+	"velocimex/internal/strategy"
+)
+
+// SignalDivergence is a step at which the backtest and live paths generated
+// different signals
+type SignalDivergence struct {
+	Step     int                `json:"step"`
+	Time     time.Time          `json:"time"`
+	Backtest []*strategy.Signal `json:"backtest"`
+	Live     []*strategy.Signal `json:"live"`
+}
+
+// ConsistencyReport compares the signals a strategy generates in backtest and
+// live on the same recorded data
+type ConsistencyReport struct {
+	StrategyID      string             `json:"strategy_id"`
+	Steps           int                `json:"steps"`
+	BacktestSignals int                `json:"backtest_signals"`
+	LiveSignals     int                `json:"live_signals"`
+	Divergences     []SignalDivergence `json:"divergences"`
+}
+
+// Consistent reports whether both paths generated the same signals at every step
+func (r *ConsistencyReport) Consistent() bool {
+	return len(r.Divergences) == 0
+}
+
+// recordingStrategy captures the signals a strategy generates at each backtest step
+type recordingStrategy struct {
+	strategy.Strategy
+	steps [][]*strategy.Signal
+}
+
+func (s *recordingStrategy) GenerateSignals(orderBooks map[string]*orderbook.OrderBook) ([]*strategy.Signal, error) {
+	signals, err := s.Strategy.GenerateSignals(orderBooks)
+	s.steps = append(s.steps, signals)
+	return signals, err
+}
+
+// CheckConsistency feeds the same recorded data to a strategy through the
+// backtest engine and through the live path, where each data point updates an
+// order book manager as a feed would and the strategy reads the live books at
+// every step, and diffs the signals generated at each step. newStrategy must
+// return a fresh instance on every call so the paths share no state.
+func CheckConsistency(config BacktestConfig, data []*HistoricalData, newStrategy func() (strategy.Strategy, error)) (*ConsistencyReport, error) {
+	if config.DataFrequency <= 0 {
+		return nil, fmt.Errorf("data frequency must be positive")
+	}
+
+	// Only the signals matter: no warmup, checkpoints, early aborts or sleeping
+	config.WarmupPeriod = 0
+	config.CheckpointPath = ""
+	config.AbortOnDrawdown = false
+	config.Benchmark = false
+	config.FastMode = true
+
+	backtest, err := backtestSignals(config, data, newStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("backtest path: %w", err)
+	}
+	live, err := liveSignals(config, data, newStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("live path: %w", err)
+	}
+
+	s, err := newStrategy()
+	if err != nil {
+		return nil, err
+	}
+	report := &ConsistencyReport{
+		StrategyID:  s.GetID(),
+		Steps:       len(live),
+		Divergences: make([]SignalDivergence, 0),
+	}
+	if len(backtest) > report.Steps {
+		report.Steps = len(backtest)
+	}
+
+	for step := 0; step < report.Steps; step++ {
+		var backtestStep, liveStep []*strategy.Signal
+		if step < len(backtest) {
+			backtestStep = backtest[step]
+		}
+		if step < len(live) {
+			liveStep = live[step]
+		}
+		report.BacktestSignals += len(backtestStep)
+		report.LiveSignals += len(liveStep)
+
+		if !sameSignals(backtestStep, liveStep) {
+			report.Divergences = append(report.Divergences, SignalDivergence{
+				Step:     step,
+				Time:     config.StartDate.Add(time.Duration(step) * config.DataFrequency),
+				Backtest: backtestStep,
+				Live:     liveStep,
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// backtestSignals runs a backtest and returns the signals generated at each step
+func backtestSignals(config BacktestConfig, data []*HistoricalData, newStrategy func() (strategy.Strategy, error)) ([][]*strategy.Signal, error) {
+	s, err := newStrategy()
+	if err != nil {
+		return nil, err
+	}
+	recorder := &recordingStrategy{Strategy: s}
+
+	engine := NewEngine()
+	defer engine.Stop()
+
+	if err := engine.SetConfig(config); err != nil {
+		return nil, err
+	}
+	for _, series := range data {
+		engine.AddHistoricalData(series)
+	}
+	engine.RegisterStrategy(recorder)
+
+	if _, err := engine.RunBacktestWithStrategy(recorder.GetID()); err != nil {
+		return nil, err
+	}
+	return recorder.steps, nil
+}
+
+// liveSignals replays the data into live order books in timestamp order and
+// returns the signals the strategy generates from them at each step
+func liveSignals(config BacktestConfig, data []*HistoricalData, newStrategy func() (strategy.Strategy, error)) ([][]*strategy.Signal, error) {
+	s, err := newStrategy()
+	if err != nil {
+		return nil, err
+	}
+
+	books := orderbook.NewManager()
+	strategy.NewEngine(books).RegisterStrategy(s)
+
+	// Points are delivered as a feed would, once their timestamp has passed
+	type series struct {
+		data   *HistoricalData
+		points []*DataPoint
+		next   int
+	}
+	feeds := make([]*series, 0, len(data))
+	for _, d := range data {
+		points := append([]*DataPoint(nil), d.DataPoints...)
+		sort.SliceStable(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
+		feeds = append(feeds, &series{data: d, points: points})
+	}
+
+	var steps [][]*strategy.Signal
+	for now := config.StartDate; now.Before(config.EndDate); now = now.Add(config.DataFrequency) {
+		for _, feed := range feeds {
+			for feed.next < len(feed.points) && !feed.points[feed.next].Timestamp.After(now) {
+				bids, asks := dataPointLevels(feed.points[feed.next])
+				books.UpdateOrderBook(feed.data.Exchange, feed.data.Symbol, bids, asks)
+				feed.next++
+			}
+		}
+
+		signals, err := s.GenerateSignals(books.GetAllOrderBooks())
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, signals)
+	}
+	return steps, nil
+}
+
+// sameSignals reports whether two steps generated the same signals, in any order
+func sameSignals(a, b []*strategy.Signal) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	a, b = sortedSignals(a), sortedSignals(b)
+	for i := range a {
+		if a[i].Symbol != b[i].Symbol || a[i].Exchange != b[i].Exchange || a[i].Side != b[i].Side ||
+			!a[i].Quantity.Equal(b[i].Quantity) || !a[i].Price.Equal(b[i].Price) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedSignals returns a copy of signals in a canonical order
+func sortedSignals(signals []*strategy.Signal) []*strategy.Signal {
+	sorted := append([]*strategy.Signal(nil), signals...)
+	key := func(s *strategy.Signal) string {
+		return fmt.Sprintf("%s|%s|%s|%s|%s", s.Exchange, s.Symbol, s.Side, s.Price.String(), s.Quantity.String())
+	}
+	sort.Slice(sorted, func(i, j int) bool { return key(sorted[i]) < key(sorted[j]) })
+	return sorted
+}
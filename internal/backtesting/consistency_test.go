@@ -0,0 +1,102 @@
+package backtesting
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/orderbook"
+	"velocimex/internal/strategy"
+)
+
+// tickStrategy buys when the ask rises and sells when it falls, sizing
+// the order from the move, or from a random draw when random is set
+type tickStrategy struct {
+	testStrategy
+	last   decimal.Decimal
+	random *rand.Rand
+}
+
+func (s *tickStrategy) GenerateSignals(orderBooks map[string]*orderbook.OrderBook) ([]*strategy.Signal, error) {
+	book, ok := orderBooks["test:BTC/USD"]
+	if !ok || book.GetBestAsk() == nil {
+		return nil, nil
+	}
+	ask := decimal.NewFromFloat(book.GetBestAsk().Price)
+	last := s.last
+	s.last = ask
+	if last.IsZero() || ask.Equal(last) {
+		return nil, nil
+	}
+
+	side := "BUY"
+	if ask.LessThan(last) {
+		side = "SELL"
+	}
+	quantity := ask.Sub(last).Abs()
+	if s.random != nil {
+		quantity = decimal.NewFromInt(s.random.Int63n(1000) + 1)
+	}
+	return []*strategy.Signal{{Symbol: "BTC/USD", Exchange: "test", Side: side, Quantity: quantity, Price: ask}}, nil
+}
+
+// zigzagData returns data whose price alternates direction with growing swings
+func zigzagData(start time.Time, ticks int) *HistoricalData {
+	data := trendingData(start, ticks, 100, 0)
+	for i, point := range data.DataPoints {
+		price := decimal.NewFromInt(int64(100 + i*(i%3-1)))
+		point.Bid, point.Ask, point.Close = price, price, price
+	}
+	return data
+}
+
+// TestConsistencyDeterministic tests that a deterministic strategy generates
+// the same signals in backtest and live
+func TestConsistencyDeterministic(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	report, err := CheckConsistency(testConfig(start, 20), []*HistoricalData{zigzagData(start, 20)}, func() (strategy.Strategy, error) {
+		return &tickStrategy{testStrategy: *newTestStrategy()}, nil
+	})
+	require.NoError(t, err)
+
+	assert.True(t, report.Consistent(), "divergences: %+v", report.Divergences)
+	assert.Equal(t, "test", report.StrategyID)
+	assert.Equal(t, 20, report.Steps)
+	assert.Greater(t, report.BacktestSignals, 10)
+	assert.Equal(t, report.BacktestSignals, report.LiveSignals)
+}
+
+// TestConsistencyNonDeterministic tests that a strategy whose signals depend
+// on more than the data is flagged at the steps where the paths diverge
+func TestConsistencyNonDeterministic(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	seed := int64(0)
+	report, err := CheckConsistency(testConfig(start, 20), []*HistoricalData{zigzagData(start, 20)}, func() (strategy.Strategy, error) {
+		seed++
+		return &tickStrategy{testStrategy: *newTestStrategy(), random: rand.New(rand.NewSource(seed))}, nil
+	})
+	require.NoError(t, err)
+
+	assert.False(t, report.Consistent())
+	require.NotEmpty(t, report.Divergences)
+	divergence := report.Divergences[0]
+	assert.Equal(t, start.Add(time.Duration(divergence.Step)*time.Minute), divergence.Time)
+	require.Len(t, divergence.Backtest, 1)
+	require.Len(t, divergence.Live, 1)
+	assert.Equal(t, divergence.Backtest[0].Side, divergence.Live[0].Side, "only the size is random")
+	assert.False(t, divergence.Backtest[0].Quantity.Equal(divergence.Live[0].Quantity))
+}
+
+// TestConsistencyRequiresFrequency tests that steps cannot be derived without a data frequency
+func TestConsistencyRequiresFrequency(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 5)
+	config.DataFrequency = 0
+	_, err := CheckConsistency(config, []*HistoricalData{zigzagData(start, 5)}, func() (strategy.Strategy, error) {
+		return newTestStrategy(), nil
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package backtesting
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var bpsMultiplier = decimal.NewFromInt(10000)
+
+// calculateCostBreakdown aggregates trade commission and slippage per symbol and
+// per time bucket, and expresses their drag on returns in basis points of initial capital
+func calculateCostBreakdown(trades []*BacktestTrade, config BacktestConfig) *CostBreakdown {
+	bucketSize := config.CostBucketSize
+	if bucketSize <= 0 {
+		bucketSize = 24 * time.Hour
+	}
+
+	breakdown := &CostBreakdown{
+		BySymbol:   make(map[string]*CostBucket),
+		ByPeriod:   make([]*CostBucket, 0),
+		BucketSize: bucketSize,
+	}
+
+	periods := make(map[time.Time]*CostBucket)
+	totalCommission := decimal.Zero
+	totalSlippage := decimal.Zero
+
+	for _, trade := range trades {
+		symbolBucket, ok := breakdown.BySymbol[trade.Symbol]
+		if !ok {
+			symbolBucket = &CostBucket{Symbol: trade.Symbol}
+			breakdown.BySymbol[trade.Symbol] = symbolBucket
+		}
+		symbolBucket.add(trade)
+
+		start := trade.EntryTime.Truncate(bucketSize)
+		periodBucket, ok := periods[start]
+		if !ok {
+			periodBucket = &CostBucket{Start: start}
+			periods[start] = periodBucket
+			breakdown.ByPeriod = append(breakdown.ByPeriod, periodBucket)
+		}
+		periodBucket.add(trade)
+
+		totalCommission = totalCommission.Add(trade.Commission)
+		totalSlippage = totalSlippage.Add(trade.Slippage)
+	}
+
+	sort.Slice(breakdown.ByPeriod, func(i, j int) bool {
+		return breakdown.ByPeriod[i].Start.Before(breakdown.ByPeriod[j].Start)
+	})
+
+	capital := config.InitialCapital
+	for _, bucket := range breakdown.BySymbol {
+		bucket.DragBps = dragBps(bucket.Commission.Add(bucket.Slippage), capital)
+	}
+	for _, bucket := range breakdown.ByPeriod {
+		bucket.DragBps = dragBps(bucket.Commission.Add(bucket.Slippage), capital)
+	}
+
+	breakdown.CommissionDragBps = dragBps(totalCommission, capital)
+	breakdown.SlippageDragBps = dragBps(totalSlippage, capital)
+	breakdown.TotalDragBps = dragBps(totalCommission.Add(totalSlippage), capital)
+
+	return breakdown
+}
+
+// add accumulates a trade's costs into the bucket
+func (b *CostBucket) add(trade *BacktestTrade) {
+	b.Trades++
+	b.Notional = b.Notional.Add(trade.EntryPrice.Mul(trade.Quantity))
+	b.Commission = b.Commission.Add(trade.Commission)
+	b.Slippage = b.Slippage.Add(trade.Slippage)
+}
+
+// dragBps expresses a cost in basis points of capital
+func dragBps(cost, capital decimal.Decimal) decimal.Decimal {
+	if !capital.IsPositive() {
+		return decimal.Zero
+	}
+	return cost.Div(capital).Mul(bpsMultiplier)
+}
@@ -0,0 +1,96 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCostBreakdown tests that the per-symbol and per-period breakdowns sum to the totals
+func TestCostBreakdown(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := DefaultBacktestConfig()
+	config.InitialCapital = decimal.NewFromInt(100000)
+	config.CostBucketSize = 24 * time.Hour
+
+	trade := func(symbol string, offset time.Duration, commission, slippage float64) *BacktestTrade {
+		return &BacktestTrade{
+			Symbol:     symbol,
+			Quantity:   decimal.NewFromInt(1),
+			EntryPrice: decimal.NewFromInt(1000),
+			EntryTime:  start.Add(offset),
+			Commission: decimal.NewFromFloat(commission),
+			Slippage:   decimal.NewFromFloat(slippage),
+		}
+	}
+
+	trades := []*BacktestTrade{
+		trade("BTC/USD", time.Hour, 1.5, 0.5),
+		trade("ETH/USD", 2*time.Hour, 0.75, 0.25),
+		trade("BTC/USD", 26*time.Hour, 2, 1),
+		trade("SOL/USD", 50*time.Hour, 0.1, 0.05),
+	}
+
+	breakdown := calculateCostBreakdown(trades, config)
+
+	require.Len(t, breakdown.BySymbol, 3)
+	assert.Equal(t, 2, breakdown.BySymbol["BTC/USD"].Trades)
+	assert.True(t, breakdown.BySymbol["BTC/USD"].Commission.Equal(decimal.NewFromFloat(3.5)))
+
+	totalCommission := decimal.NewFromFloat(4.35)
+	totalSlippage := decimal.NewFromFloat(1.8)
+
+	symbolCommission, symbolSlippage := decimal.Zero, decimal.Zero
+	for _, bucket := range breakdown.BySymbol {
+		symbolCommission = symbolCommission.Add(bucket.Commission)
+		symbolSlippage = symbolSlippage.Add(bucket.Slippage)
+	}
+	assert.True(t, symbolCommission.Equal(totalCommission), "per-symbol commission %s", symbolCommission)
+	assert.True(t, symbolSlippage.Equal(totalSlippage), "per-symbol slippage %s", symbolSlippage)
+
+	require.Len(t, breakdown.ByPeriod, 3)
+	periodCommission, periodSlippage := decimal.Zero, decimal.Zero
+	for i, bucket := range breakdown.ByPeriod {
+		assert.Equal(t, start.Add(time.Duration(i)*24*time.Hour), bucket.Start)
+		periodCommission = periodCommission.Add(bucket.Commission)
+		periodSlippage = periodSlippage.Add(bucket.Slippage)
+	}
+	assert.True(t, periodCommission.Equal(totalCommission))
+	assert.True(t, periodSlippage.Equal(totalSlippage))
+
+	// 4.35 / 100000 = 0.435 bps, 1.8 / 100000 = 0.18 bps
+	assert.True(t, breakdown.CommissionDragBps.Equal(decimal.NewFromFloat(0.435)), "commission drag %s", breakdown.CommissionDragBps)
+	assert.True(t, breakdown.SlippageDragBps.Equal(decimal.NewFromFloat(0.18)), "slippage drag %s", breakdown.SlippageDragBps)
+	assert.True(t, breakdown.TotalDragBps.Equal(decimal.NewFromFloat(0.615)))
+}
+
+// TestBacktestCostBreakdown tests that a backtest result's breakdown matches its reported totals
+func TestBacktestCostBreakdown(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 10)
+	config.Commission = decimal.NewFromFloat(0.001)
+	config.Slippage = decimal.NewFromFloat(0.0005)
+
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(config))
+	defer engine.Stop()
+	require.NoError(t, engine.AddHistoricalData(trendingData(start, 10, 100, 1)))
+	require.NoError(t, engine.RegisterStrategy(newTestStrategy()))
+
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+	require.NotNil(t, result.Costs)
+	require.True(t, result.TotalCommission.IsPositive())
+
+	bucket, ok := result.Costs.BySymbol["BTC/USD"]
+	require.True(t, ok)
+	assert.True(t, bucket.Commission.Equal(result.TotalCommission))
+	assert.True(t, bucket.Slippage.Equal(result.TotalSlippage))
+
+	report, err := engine.GenerateReport(result)
+	require.NoError(t, err)
+	assert.Same(t, result.Costs, report.Costs)
+}
@@ -0,0 +1,98 @@
+package backtesting
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"velocimex/internal/numeric"
+)
+
+// drawdownMonitor tracks rolling drawdown from peak equity during a run
+type drawdownMonitor struct {
+	limit       decimal.Decimal
+	peak        decimal.Decimal
+	below       bool // Equity is currently past the limit
+	breaches    []*DrawdownEvent
+	abortReason string
+}
+
+// breached reports whether the limit has been crossed during the run
+func (m *drawdownMonitor) breached() bool {
+	return len(m.breaches) > 0
+}
+
+// abortedReason returns why the run stopped early, or "" if it ran to completion
+func (e *Engine) abortedReason() string {
+	if !e.config.AbortOnDrawdown || !e.drawdown.breached() {
+		return ""
+	}
+	return e.drawdown.abortReason
+}
+
+// observe records an equity sample and returns an event when drawdown crosses the limit.
+// The monitor re-arms once equity recovers inside the limit.
+func (m *drawdownMonitor) observe(event DrawdownEvent) *DrawdownEvent {
+	if !m.limit.IsPositive() {
+		return nil
+	}
+
+	if event.Equity.GreaterThan(m.peak) {
+		m.peak = event.Equity
+	}
+	if !m.peak.IsPositive() {
+		return nil
+	}
+
+	drawdown := numeric.FractionChange(m.peak, event.Equity).Neg()
+	if drawdown.LessThan(m.limit) {
+		m.below = false
+		return nil
+	}
+	if m.below {
+		return nil
+	}
+	m.below = true
+
+	event.PeakEquity = m.peak
+	event.Drawdown = drawdown
+	event.Limit = m.limit
+	m.breaches = append(m.breaches, &event)
+	if m.abortReason == "" {
+		m.abortReason = fmt.Sprintf("drawdown %s%% exceeded limit %s%% at %s",
+			drawdown.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			m.limit.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			event.Timestamp.Format(time.RFC3339))
+	}
+	return &event
+}
+
+// SetDrawdownHandler sets the hook fired when rolling drawdown crosses BacktestConfig.DrawdownLimit.
+// The handler runs synchronously inside the backtest loop and must not call back into the engine.
+func (e *Engine) SetDrawdownHandler(handler DrawdownHandler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.drawdownHandler = handler
+}
+
+// checkDrawdown samples the latest portfolio snapshot and reports whether the limit was just crossed
+func (e *Engine) checkDrawdown(strategyID string) bool {
+	if len(e.portfolioHistory) == 0 {
+		return false
+	}
+
+	snapshot := e.portfolioHistory[len(e.portfolioHistory)-1]
+	event := e.drawdown.observe(DrawdownEvent{
+		StrategyID: strategyID,
+		Timestamp:  snapshot.Timestamp,
+		Equity:     snapshot.TotalValue,
+	})
+	if event == nil {
+		return false
+	}
+
+	if e.drawdownHandler != nil {
+		e.drawdownHandler(event)
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crashingBacktest sets up a leveraged long into a steadily falling market
+func crashingBacktest(t *testing.T, config BacktestConfig) *Engine {
+	t.Helper()
+
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(config))
+	t.Cleanup(func() { engine.Stop() })
+
+	// 900 BTC at 100 is 90% of capital; a 5/tick decline wipes out equity quickly
+	require.NoError(t, engine.AddHistoricalData(trendingData(config.StartDate, 20, 100, -5)))
+	strategy := newTestStrategy()
+	strategy.quantity = decimal.NewFromInt(900)
+	require.NoError(t, engine.RegisterStrategy(strategy))
+	return engine
+}
+
+// TestDrawdownHookFires tests that the hook fires once when drawdown crosses the limit
+func TestDrawdownHookFires(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 20)
+	config.DrawdownLimit = decimal.NewFromFloat(0.2)
+
+	engine := crashingBacktest(t, config)
+
+	var events []*DrawdownEvent
+	engine.SetDrawdownHandler(func(event *DrawdownEvent) {
+		events = append(events, event)
+	})
+
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "test", events[0].StrategyID)
+	assert.True(t, events[0].Drawdown.GreaterThanOrEqual(config.DrawdownLimit))
+	assert.True(t, events[0].Equity.LessThan(events[0].PeakEquity))
+	assert.Equal(t, events, result.DrawdownBreaches)
+
+	// Without abort the run continues to the end of the data
+	assert.False(t, result.Aborted)
+	assert.Empty(t, result.AbortReason)
+	assert.Len(t, result.PortfolioHistory, 20)
+}
+
+// TestDrawdownAbort tests that the run stops at the breach when abort is enabled
+func TestDrawdownAbort(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 20)
+	config.DrawdownLimit = decimal.NewFromFloat(0.2)
+	config.AbortOnDrawdown = true
+
+	engine := crashingBacktest(t, config)
+
+	fired := 0
+	engine.SetDrawdownHandler(func(event *DrawdownEvent) { fired++ })
+
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, fired)
+	assert.True(t, result.Aborted)
+	assert.Contains(t, result.AbortReason, "exceeded limit")
+	require.NotEmpty(t, result.PortfolioHistory)
+	assert.Less(t, len(result.PortfolioHistory), 20)
+
+	last := result.PortfolioHistory[len(result.PortfolioHistory)-1]
+	assert.Equal(t, result.DrawdownBreaches[0].Timestamp, last.Timestamp)
+}
+
+// TestDrawdownMonitorRearms tests that a recovery re-arms the monitor
+func TestDrawdownMonitorRearms(t *testing.T) {
+	monitor := drawdownMonitor{limit: decimal.NewFromFloat(0.1), peak: decimal.NewFromInt(100)}
+	sample := func(equity int64) *DrawdownEvent {
+		return monitor.observe(DrawdownEvent{Equity: decimal.NewFromInt(equity)})
+	}
+
+	assert.Nil(t, sample(95))
+	assert.NotNil(t, sample(89))
+	assert.Nil(t, sample(85))
+	assert.Nil(t, sample(120))
+	assert.NotNil(t, sample(100))
+	assert.Len(t, monitor.breaches, 2)
+
+	disabled := drawdownMonitor{peak: decimal.NewFromInt(100)}
+	assert.Nil(t, disabled.observe(DrawdownEvent{Equity: decimal.NewFromInt(1)}))
+}
+
+// TestBacktestExpectedShortfall tests that the results report VaR and CVaR of the period returns
+func TestBacktestExpectedShortfall(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine := crashingBacktest(t, testConfig(start, 20))
+
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+
+	assert.True(t, result.VaR95.IsPositive())
+	assert.True(t, result.CVaR95.GreaterThanOrEqual(result.VaR95))
+	assert.True(t, result.CVaR99.GreaterThanOrEqual(result.VaR99))
+	assert.True(t, result.CVaR99.GreaterThanOrEqual(result.CVaR95))
+}
@@ -2,6 +2,7 @@ package backtesting
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -12,12 +13,22 @@ import (
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"velocimex/internal/normalizer"
+	"velocimex/internal/numeric"
 	"velocimex/internal/orderbook"
 	"velocimex/internal/orders"
 	"velocimex/internal/risk"
 	"velocimex/internal/strategy"
 )
 
+// maxStoredResults is how many completed backtest results are kept for retrieval
+const maxStoredResults = 50
+
+// ErrResultNotFound is returned when no stored backtest result has the requested ID
+var ErrResultNotFound = errors.New("backtest result not found")
+
+// ErrInvalidConfig is returned when SetConfig is given a configuration that fails validation
+var ErrInvalidConfig = errors.New("invalid backtest config")
+
 // Engine implements the BacktestEngine interface
 type Engine struct {
 	config           BacktestConfig
@@ -45,6 +56,40 @@ type Engine struct {
 	totalCommission  decimal.Decimal
 	totalSlippage    decimal.Decimal
 	executionTimes   []time.Duration
+	clock            Clock
+	latency          latencyRecorder
+	
+	// Drawdown monitoring
+	drawdownHandler  DrawdownHandler
+	drawdown         drawdownMonitor
+	
+	// Limit orders resting under the queue model
+	restingOrders    []*restingOrder
+	
+	// Nets signals over the configured signal window
+	aggregator       *strategy.SignalAggregator
+	
+	// Ticks replayed before StartDate
+	warmupTicks      int
+	
+	// Ticks run since StartDate, for checkpointing
+	ticks            int
+	
+	// Portfolio value the run opened with, cash plus initial positions
+	openingValue     decimal.Decimal
+	
+	// Completed results by ID, oldest first in resultIDs
+	results          map[string]*BacktestResult
+	resultIDs        []string
+	
+	// Publishes each completed run's metrics
+	metricsPusher    MetricsPusher
+	
+	// Records each completed run's duration and phase times
+	metricsRecorder  MetricsRecorder
+	
+	// Ticks run and time spent per phase, readable during a run
+	progress         runProgress
 }
 
 // NewEngine creates a new backtesting engine
@@ -58,6 +103,7 @@ func NewEngine() *Engine {
 		portfolioHistory: make([]*PortfolioSnapshot, 0),
 		trades:           make([]*BacktestTrade, 0),
 		riskEvents:       make([]*risk.RiskEvent, 0),
+		results:          make(map[string]*BacktestResult),
 		ctx:              ctx,
 		cancel:           cancel,
 	}
@@ -65,14 +111,35 @@ func NewEngine() *Engine {
 
 // SetConfig sets the backtesting configuration
 func (e *Engine) SetConfig(config BacktestConfig) error {
+	if err := config.ConfidenceGate.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	if err := validateInitialPositions(config.InitialPositions); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	
 	e.config = config
 	
+	// Replace any order manager from a previous configuration
+	if e.orderManager != nil {
+		e.orderManager.Stop(context.Background())
+	}
+	
 	// Initialize order manager with backtesting config
-	smartRouter := orders.NewSmartRouter(orders.DefaultSmartRouterConfig(), e.orderBookManager)
-	e.orderManager = orders.NewManager(orders.DefaultManagerConfig(), smartRouter, nil)
+	orderManager := orders.NewManager(orders.DefaultManagerConfig(), &directRouter{}, nil)
+	if err := orderManager.Start(e.ctx); err != nil {
+		return fmt.Errorf("failed to start order manager: %v", err)
+	}
+	e.orderManager = orderManager
+	
+	// Replace any risk manager from a previous configuration
+	if e.riskManager != nil {
+		e.riskManager.Stop()
+		e.riskManager = nil
+	}
 	
 	// Initialize risk manager if enabled
 	if config.RiskManagement {
@@ -169,15 +236,19 @@ func (e *Engine) RunBacktest() (*BacktestResult, error) {
 	
 	// Run backtest for each strategy and combine results
 	var combinedResult *BacktestResult
+	var trades []*BacktestTrade
 	
 	for strategyID := range e.strategies {
 		result, err := e.RunBacktestWithStrategy(strategyID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to run backtest for strategy %s: %v", strategyID, err)
 		}
+		trades = append(trades, result.Trades...)
 		
 		if combinedResult == nil {
-			combinedResult = result
+			// Copy so the single-strategy result stays stored as it was
+			combined := *result
+			combinedResult = &combined
 		} else {
 			// Combine results (simplified - in practice you'd want more sophisticated combination)
 			combinedResult.TotalTrades += result.TotalTrades
@@ -187,6 +258,12 @@ func (e *Engine) RunBacktest() (*BacktestResult, error) {
 			combinedResult.TotalSlippage = combinedResult.TotalSlippage.Add(result.TotalSlippage)
 		}
 	}
+	combinedResult.Costs = calculateCostBreakdown(trades, e.config)
+	combinedResult.Trades = trades
+	
+	e.mu.Lock()
+	e.storeResult(combinedResult)
+	e.mu.Unlock()
 	
 	return combinedResult, nil
 }
@@ -201,6 +278,16 @@ func (e *Engine) RunBacktestWithStrategy(strategyID string) (*BacktestResult, er
 		return nil, fmt.Errorf("strategy not found: %s", strategyID)
 	}
 	
+	e.progress.begin(strategyID, e.config, 0, e.config.StartDate)
+	defer e.progress.finish()
+	
+	// Convert the data to the configured bar frequency
+	loadStart := time.Now()
+	if err := e.resampleHistoricalData(); err != nil {
+		return nil, err
+	}
+	e.progress.addPhase(PhaseDataLoading, loadStart)
+	
 	// Initialize backtest state
 	e.running = true
 	e.paused = false
@@ -211,18 +298,17 @@ func (e *Engine) RunBacktestWithStrategy(strategyID string) (*BacktestResult, er
 	e.totalCommission = decimal.Zero
 	e.totalSlippage = decimal.Zero
 	e.executionTimes = make([]time.Duration, 0)
-	
-	// Initialize portfolio
-	portfolio := &risk.Portfolio{
-		TotalValue:    e.config.InitialCapital,
-		CashBalance:   e.config.InitialCapital,
-		InvestedValue: decimal.Zero,
-		UnrealizedPNL: decimal.Zero,
-		RealizedPNL:   decimal.Zero,
-		DailyPNL:      decimal.Zero,
-		Positions:     make(map[string]*risk.Position),
-		LastUpdated:   e.currentTime,
-	}
+	e.clock = newClock(e.config)
+	e.latency = latencyRecorder{}
+	e.restingOrders = nil
+	e.aggregator = newSignalAggregator(e.config.SignalWindow)
+	e.warmupTicks = 0
+	e.ticks = 0
+	
+	// Initialize portfolio, seeded with any initial positions
+	portfolio := e.openingPortfolio()
+	e.openingValue = portfolio.TotalValue
+	e.drawdown = drawdownMonitor{limit: e.config.DrawdownLimit, peak: e.openingValue}
 	
 	if e.riskManager != nil {
 		e.riskManager.UpdatePortfolio(portfolio)
@@ -231,6 +317,9 @@ func (e *Engine) RunBacktestWithStrategy(strategyID string) (*BacktestResult, er
 	startTime := time.Now()
 	log.Printf("Starting backtest for strategy %s from %s to %s", strategyID, e.config.StartDate, e.config.EndDate)
 	
+	// Feed pre-start history to the strategy without booking anything
+	e.warmupTicks = e.runWarmup(strategy)
+	
 	// Run the backtest
 	err := e.runBacktestLoop(strategy)
 	
@@ -243,11 +332,43 @@ func (e *Engine) RunBacktestWithStrategy(strategyID string) (*BacktestResult, er
 	
 	// Calculate final results
 	result := e.calculateBacktestResult(strategyID, duration)
+	result.Phases = e.progress.snapshot().Phases
+	e.attachBenchmark(result)
+	
+	e.storeResult(result)
+	e.pushResult(strategyID, result)
+	e.recordMetrics(strategyID, result)
 	
 	log.Printf("Backtest completed in %v", duration)
 	return result, nil
 }
 
+// storeResult assigns a result its ID and keeps it for later retrieval,
+// dropping the oldest stored result once maxStoredResults is reached.
+// Callers must hold e.mu.
+func (e *Engine) storeResult(result *BacktestResult) {
+	result.ID = uuid.New().String()
+	e.results[result.ID] = result
+	e.resultIDs = append(e.resultIDs, result.ID)
+	
+	if len(e.resultIDs) > maxStoredResults {
+		delete(e.results, e.resultIDs[0])
+		e.resultIDs = e.resultIDs[1:]
+	}
+}
+
+// GetResult returns a stored backtest result by ID
+func (e *Engine) GetResult(id string) (*BacktestResult, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	
+	result, ok := e.results[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrResultNotFound, id)
+	}
+	return result, nil
+}
+
 // runBacktestLoop runs the main backtesting loop
 func (e *Engine) runBacktestLoop(strategy strategy.Strategy) error {
 	for e.currentTime.Before(e.config.EndDate) && e.running {
@@ -257,30 +378,51 @@ func (e *Engine) runBacktestLoop(strategy strategy.Strategy) error {
 		}
 		
 		// Update market data for current time
+		phaseStart := time.Now()
 		if err := e.updateMarketData(); err != nil {
 			log.Printf("Error updating market data: %v", err)
 		}
+		e.progress.addPhase(PhaseDataLoading, phaseStart)
+		
+		// Fill resting limit orders that volume has traded through
+		e.processRestingOrders()
 		
 		// Run strategy
+		phaseStart = time.Now()
 		if err := e.runStrategy(strategy); err != nil {
 			log.Printf("Error running strategy: %v", err)
 		}
+		e.progress.addPhase(PhaseStrategyEval, phaseStart)
 		
 		// Update portfolio and risk metrics
+		phaseStart = time.Now()
 		if err := e.updatePortfolio(); err != nil {
 			log.Printf("Error updating portfolio: %v", err)
 		}
 		
 		// Take portfolio snapshot
 		e.takePortfolioSnapshot()
+		e.progress.addPhase(PhasePortfolioUpdate, phaseStart)
+		
+		// Fire the drawdown hook and stop early if configured to
+		if e.checkDrawdown(strategy.GetID()) && e.config.AbortOnDrawdown {
+			log.Printf("Aborting backtest for strategy %s: %s", strategy.GetID(), e.drawdown.abortReason)
+			break
+		}
 		
 		// Advance time
 		e.currentTime = e.currentTime.Add(e.config.DataFrequency)
 		
-		// Simulate latency
+		// Simulate latency; fast mode advances a simulated clock instead of sleeping
 		if e.config.Latency > 0 {
-			time.Sleep(e.config.Latency)
+			e.clock.Sleep(e.config.Latency)
+			e.latency.record(e.config.Latency)
 		}
+		
+		// Periodically save state so a crashed run can be resumed
+		e.ticks++
+		e.progress.tick(e.ticks, e.currentTime)
+		e.maybeCheckpoint(strategy.GetID())
 	}
 	
 	return nil
@@ -296,22 +438,32 @@ func (e *Engine) updateMarketData() error {
 				continue
 			}
 			
-			// Create normalized price levels
-			bids := []normalizer.PriceLevel{
-				{Price: dataPoint.Bid.InexactFloat64(), Volume: dataPoint.BidSize.InexactFloat64()},
-			}
-			asks := []normalizer.PriceLevel{
-				{Price: dataPoint.Ask.InexactFloat64(), Volume: dataPoint.AskSize.InexactFloat64()},
-			}
-			
-			// Update order book
-			e.orderBookManager.UpdateOrderBook(exchange, symbol, bids, asks)
+			e.applyDataPoint(exchange, symbol, dataPoint)
 		}
 	}
 	
 	return nil
 }
 
+// applyDataPoint updates an exchange's order book from a historical data point
+func (e *Engine) applyDataPoint(exchange, symbol string, dataPoint *DataPoint) {
+	bids, asks := dataPointLevels(dataPoint)
+	
+	// Update order book
+	e.orderBookManager.UpdateOrderBook(exchange, symbol, bids, asks)
+}
+
+// dataPointLevels returns the normalized top-of-book levels of a data point
+func dataPointLevels(dataPoint *DataPoint) (bids, asks []normalizer.PriceLevel) {
+	bids = []normalizer.PriceLevel{
+		{Price: dataPoint.Bid.InexactFloat64(), Volume: dataPoint.BidSize.InexactFloat64()},
+	}
+	asks = []normalizer.PriceLevel{
+		{Price: dataPoint.Ask.InexactFloat64(), Volume: dataPoint.AskSize.InexactFloat64()},
+	}
+	return bids, asks
+}
+
 // findDataPointForTime finds the data point closest to the given time
 func (e *Engine) findDataPointForTime(data *HistoricalData, targetTime time.Time) *DataPoint {
 	var closest *DataPoint
@@ -334,25 +486,15 @@ func (e *Engine) findDataPointForTime(data *HistoricalData, targetTime time.Time
 
 // runStrategy runs the strategy for the current time
 func (e *Engine) runStrategy(strategy strategy.Strategy) error {
-	// Get current order books
-	orderBooks := make(map[string]*orderbook.OrderBook)
-	for symbol := range e.historicalData {
-		for exchange := range e.historicalData[symbol] {
-			key := fmt.Sprintf("%s:%s", exchange, symbol)
-			if book := e.orderBookManager.GetOrderBook(symbol); book != nil {
-				orderBooks[key] = book
-			}
-		}
-	}
-	
 	// Run strategy
-	signals, err := strategy.GenerateSignals(orderBooks)
+	signals, err := strategy.GenerateSignals(e.currentOrderBooks())
 	if err != nil {
 		return err
 	}
+	signals = e.config.ConfidenceGate.Filter(strategy.GetID(), signals)
 	
-	// Execute signals
-	for _, signal := range signals {
+	// Execute signals, netted over the signal window if one is set
+	for _, signal := range e.aggregateSignals(signals) {
 		if err := e.executeSignal(signal, strategy); err != nil {
 			log.Printf("Error executing signal: %v", err)
 		}
@@ -361,8 +503,36 @@ func (e *Engine) runStrategy(strategy strategy.Strategy) error {
 	return nil
 }
 
+// currentOrderBooks returns the books for every loaded symbol and exchange, keyed exchange:symbol
+func (e *Engine) currentOrderBooks() map[string]*orderbook.OrderBook {
+	orderBooks := make(map[string]*orderbook.OrderBook)
+	for symbol := range e.historicalData {
+		for exchange := range e.historicalData[symbol] {
+			key := fmt.Sprintf("%s:%s", exchange, symbol)
+			if book := e.orderBookManager.GetOrderBook(key); book != nil {
+				orderBooks[key] = book
+			}
+		}
+	}
+	return orderBooks
+}
+
 // executeSignal executes a trading signal
 func (e *Engine) executeSignal(signal *strategy.Signal, strategy strategy.Strategy) error {
+	// Under the queue model, limit orders that do not cross the spread join the book
+	if e.config.QueueModel {
+		if dataPoint := e.currentDataPoint(signal.Symbol, signal.Exchange); dataPoint != nil && !isMarketable(signal, dataPoint) {
+			e.restOrder(signal, strategy, dataPoint)
+			return nil
+		}
+	}
+	
+	return e.fillSignal(signal, strategy, false)
+}
+
+// fillSignal books a signal as a fill. Taker fills cross the spread on arrival
+// and pay slippage; maker fills rested in the book and pay the maker commission.
+func (e *Engine) fillSignal(signal *strategy.Signal, strategy strategy.Strategy, maker bool) error {
 	// Create order request
 	orderReq := &orders.OrderRequest{
 		Symbol:       signal.Symbol,
@@ -377,8 +547,12 @@ func (e *Engine) executeSignal(signal *strategy.Signal, strategy strategy.Strate
 	}
 	
 	// Apply slippage
-	if e.config.Slippage.GreaterThan(decimal.Zero) {
-		slippageAmount := signal.Price.Mul(e.config.Slippage)
+	slippage := decimal.Zero
+	if !maker {
+		slippage = e.config.Slippage
+	}
+	if slippage.GreaterThan(decimal.Zero) {
+		slippageAmount := signal.Price.Mul(slippage)
 		if signal.Side == "BUY" {
 			orderReq.Price = orderReq.Price.Add(slippageAmount)
 		} else {
@@ -399,10 +573,13 @@ func (e *Engine) executeSignal(signal *strategy.Signal, strategy strategy.Strate
 	executionTime := time.Since(executionStart)
 	e.executionTimes = append(e.executionTimes, executionTime)
 	
-	// Calculate commission
-	commission := signal.Price.Mul(signal.Quantity).Mul(e.config.Commission)
+	// Calculate commission, negative when a maker rebate is paid
+	commission := signal.Price.Mul(signal.Quantity).Mul(e.commissionRate(maker))
 	e.totalCommission = e.totalCommission.Add(commission)
 	
+	// Apply the fill to the portfolio
+	realizedPnL := e.applyFill(signal.Symbol, signal.Exchange, signal.Side, signal.Quantity, orderReq.Price, commission)
+	
 	// Create backtest trade
 	trade := &BacktestTrade{
 		ID:           uuid.New().String(),
@@ -415,10 +592,11 @@ func (e *Engine) executeSignal(signal *strategy.Signal, strategy strategy.Strate
 		EntryTime:    e.currentTime,
 		ExitTime:     time.Time{}, // Will be set when position is closed
 		Duration:     0,            // Will be calculated when position is closed
-		PnL:         decimal.Zero, // Will be calculated when position is closed
+		PnL:         realizedPnL,
 		PnLPct:      decimal.Zero, // Will be calculated when position is closed
 		Commission:  commission,
-		Slippage:    signal.Price.Mul(signal.Quantity).Mul(e.config.Slippage),
+		Slippage:    signal.Price.Mul(signal.Quantity).Mul(slippage),
+		Maker:       maker,
 		StrategyID:  strategy.GetID(),
 		StrategyName: strategy.GetName(),
 		Metadata:    signal.Metadata,
@@ -429,6 +607,14 @@ func (e *Engine) executeSignal(signal *strategy.Signal, strategy strategy.Strate
 	return nil
 }
 
+// commissionRate returns the commission charged as a fraction of notional
+func (e *Engine) commissionRate(maker bool) decimal.Decimal {
+	if maker && e.config.MakerCommission != nil {
+		return *e.config.MakerCommission
+	}
+	return e.config.Commission
+}
+
 // updatePortfolio updates the portfolio based on current positions
 func (e *Engine) updatePortfolio() error {
 	if e.riskManager == nil {
@@ -480,11 +666,14 @@ func (e *Engine) takePortfolioSnapshot() {
 
 // calculateBacktestResult calculates the final backtest results
 func (e *Engine) calculateBacktestResult(strategyID string, duration time.Duration) *BacktestResult {
-	portfolio := e.riskManager.GetPortfolio()
+	portfolio := &risk.Portfolio{TotalValue: e.openingValue}
+	if e.riskManager != nil {
+		portfolio = e.riskManager.GetPortfolio()
+	}
 	
 	// Calculate basic metrics
-	totalReturn := portfolio.TotalValue.Sub(e.config.InitialCapital)
-	totalReturnPct := totalReturn.Div(e.config.InitialCapital).Mul(decimal.NewFromFloat(100))
+	totalReturn := numeric.Round(portfolio.TotalValue.Sub(e.openingValue))
+	totalReturnPct := numeric.Round(numeric.PercentChange(e.openingValue, portfolio.TotalValue))
 	
 	// Calculate trade metrics
 	winningTrades := 0
@@ -504,7 +693,7 @@ func (e *Engine) calculateBacktestResult(strategyID string, duration time.Durati
 	
 	winRate := decimal.Zero
 	if len(e.trades) > 0 {
-		winRate = decimal.NewFromInt(int64(winningTrades)).Div(decimal.NewFromInt(int64(len(e.trades))))
+		winRate = numeric.Round(decimal.NewFromInt(int64(winningTrades)).Div(decimal.NewFromInt(int64(len(e.trades)))))
 	}
 	
 	// Calculate average execution time
@@ -519,15 +708,14 @@ func (e *Engine) calculateBacktestResult(strategyID string, duration time.Durati
 	
 	// Calculate performance metrics (simplified)
 	sharpeRatio := decimal.Zero
+	var returns []decimal.Decimal
 	if len(e.portfolioHistory) > 1 {
 		// Calculate daily returns
-		var returns []decimal.Decimal
 		for i := 1; i < len(e.portfolioHistory); i++ {
 			prevValue := e.portfolioHistory[i-1].TotalValue
 			currValue := e.portfolioHistory[i].TotalValue
 			if !prevValue.IsZero() {
-				dailyReturn := currValue.Sub(prevValue).Div(prevValue)
-				returns = append(returns, dailyReturn)
+				returns = append(returns, numeric.FractionChange(prevValue, currValue))
 			}
 		}
 		
@@ -554,7 +742,7 @@ func (e *Engine) calculateBacktestResult(strategyID string, duration time.Durati
 			stdDev = decimal.NewFromFloat(stdDevFloat)
 			
 			if !stdDev.IsZero() {
-				sharpeRatio = avgReturn.Div(stdDev)
+				sharpeRatio = numeric.Round(avgReturn.Div(stdDev))
 			}
 		}
 	}
@@ -564,7 +752,7 @@ func (e *Engine) calculateBacktestResult(strategyID string, duration time.Durati
 		StartTime:        e.config.StartDate,
 		EndTime:          e.config.EndDate,
 		Duration:         duration,
-		InitialCapital:   e.config.InitialCapital,
+		InitialCapital:   e.openingValue,
 		FinalCapital:     portfolio.TotalValue,
 		TotalReturn:      totalReturn,
 		TotalReturnPct:   totalReturnPct,
@@ -578,17 +766,26 @@ func (e *Engine) calculateBacktestResult(strategyID string, duration time.Durati
 		MaxDrawdown:      decimal.Zero, // TODO: Implement
 		MaxDrawdownPct:   decimal.Zero, // TODO: Implement
 		Volatility:       decimal.Zero, // TODO: Implement
-		VaR95:            decimal.Zero, // TODO: Implement
-		VaR99:            decimal.Zero, // TODO: Implement
+		VaR95:            numeric.Round(risk.HistoricalVaR(returns, 0.95)),
+		VaR99:            numeric.Round(risk.HistoricalVaR(returns, 0.99)),
+		CVaR95:           numeric.Round(risk.ExpectedShortfall(returns, 0.95)),
+		CVaR99:           numeric.Round(risk.ExpectedShortfall(returns, 0.99)),
 		Beta:             decimal.Zero, // TODO: Implement
 		Alpha:            decimal.Zero, // TODO: Implement
 		TotalCommission:  e.totalCommission,
 		TotalSlippage:    e.totalSlippage,
 		AvgExecutionTime: avgExecutionTime,
+		Latency:          e.latency.stats(),
+		Costs:            calculateCostBreakdown(e.trades, e.config),
 		Trades:           e.trades,
 		PortfolioHistory: e.portfolioHistory,
 		RiskEvents:       e.riskEvents,
 		StrategyMetrics:  make(map[string]interface{}),
+		DrawdownBreaches: e.drawdown.breaches,
+		Aborted:          e.abortedReason() != "",
+		AbortReason:      e.abortedReason(),
+		OpenLimitOrders:  len(e.restingOrders),
+		WarmupTicks:      e.warmupTicks,
 	}
 }
 
@@ -676,14 +873,20 @@ func (e *Engine) GenerateReport(result *BacktestResult) (*BacktestReport, error)
 		ProfitFactor:     decimal.Zero, // TODO: Calculate
 		RiskAdjustedReturn: decimal.Zero, // TODO: Calculate
 	}
+	if result.Benchmark != nil {
+		summary.BenchmarkReturnPct = result.Benchmark.TotalReturnPct
+		summary.Outperformance = result.Benchmark.Outperformance
+	}
 	
 	return &BacktestReport{
 		Summary:         summary,
 		Analysis:        analysis,
 		Charts:          make(map[string]interface{}),
 		Recommendations: make([]string, 0),
+		Costs:           result.Costs,
 		GeneratedAt:     time.Now(),
 		ReportVersion:   "1.0.0",
+		Benchmark:       result.Benchmark,
 	}, nil
 }
 
@@ -706,18 +909,21 @@ func (e *Engine) Stop() error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	
-	if !e.running {
-		return nil
+	if e.running {
+		e.running = false
+		e.cancel()
+		log.Println("Backtesting engine stopped")
 	}
 	
-	e.running = false
-	e.cancel()
-	
+	// SetConfig starts the managers whether or not the engine was started
 	if e.riskManager != nil {
 		e.riskManager.Stop()
 	}
 	
-	log.Println("Backtesting engine stopped")
+	if e.orderManager != nil {
+		e.orderManager.Stop(context.Background())
+	}
+	
 	return nil
 }
 
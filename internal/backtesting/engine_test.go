@@ -0,0 +1,181 @@
+package backtesting
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/orderbook"
+	"velocimex/internal/orders"
+	"velocimex/internal/strategy"
+)
+
+// testStrategy opens a single position on its first tick and holds it
+type testStrategy struct {
+	id        string
+	exchange  string
+	symbol    string
+	side      string
+	quantity  decimal.Decimal
+	price     decimal.Decimal // Limit price; zero uses the best ask
+	signalled bool
+}
+
+func newTestStrategy() *testStrategy {
+	return &testStrategy{
+		id:       "test",
+		exchange: "test",
+		symbol:   "BTC/USD",
+		side:     "BUY",
+		quantity: decimal.NewFromInt(1),
+	}
+}
+
+func (s *testStrategy) GetID() string                   { return s.id }
+func (s *testStrategy) GetName() string                 { return s.id }
+func (s *testStrategy) Start(ctx context.Context) error { return nil }
+func (s *testStrategy) Stop() error                     { return nil }
+func (s *testStrategy) IsRunning() bool                 { return false }
+
+func (s *testStrategy) GetResults() strategy.StrategyResults {
+	return strategy.StrategyResults{Name: s.id}
+}
+
+func (s *testStrategy) GenerateSignals(orderBooks map[string]*orderbook.OrderBook) ([]*strategy.Signal, error) {
+	if s.signalled {
+		return nil, nil
+	}
+
+	book, ok := orderBooks[fmt.Sprintf("%s:%s", s.exchange, s.symbol)]
+	if !ok {
+		return nil, nil
+	}
+	ask := book.GetBestAsk()
+	if ask == nil {
+		return nil, nil
+	}
+
+	price := s.price
+	if price.IsZero() {
+		price = decimal.NewFromFloat(ask.Price)
+	}
+
+	s.signalled = true
+	return []*strategy.Signal{{
+		Symbol:   s.symbol,
+		Exchange: s.exchange,
+		Side:     s.side,
+		Quantity: s.quantity,
+		Price:    price,
+	}}, nil
+}
+
+func (s *testStrategy) WithParameters(params map[string]interface{}) (strategy.Strategy, error) {
+	clone := *s
+	clone.signalled = false
+	if side, ok := params["side"].(string); ok {
+		clone.side = side
+	}
+	if quantity, ok := params["quantity"].(int); ok {
+		clone.quantity = decimal.NewFromInt(int64(quantity))
+	}
+	return &clone, nil
+}
+
+// testConfig returns a fast, frictionless backtest configuration
+func testConfig(start time.Time, ticks int) BacktestConfig {
+	config := DefaultBacktestConfig()
+	config.StartDate = start
+	config.EndDate = start.Add(time.Duration(ticks) * time.Minute)
+	config.DataFrequency = time.Minute
+	config.Latency = 0
+	config.Commission = decimal.Zero
+	config.Slippage = decimal.Zero
+	return config
+}
+
+// trendingData returns data whose price rises by step every minute
+func trendingData(start time.Time, ticks int, base, step float64) *HistoricalData {
+	data := &HistoricalData{
+		Symbol:     "BTC/USD",
+		Exchange:   "test",
+		DataPoints: make([]*DataPoint, 0, ticks),
+		StartTime:  start,
+		EndTime:    start.Add(time.Duration(ticks) * time.Minute),
+		Frequency:  time.Minute,
+	}
+
+	for i := 0; i < ticks; i++ {
+		price := decimal.NewFromFloat(base + step*float64(i))
+		data.DataPoints = append(data.DataPoints, &DataPoint{
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Open:      price,
+			High:      price,
+			Low:       price,
+			Close:     price,
+			Volume:    decimal.NewFromInt(100),
+			Bid:       price,
+			Ask:       price,
+			BidSize:   decimal.NewFromInt(10),
+			AskSize:   decimal.NewFromInt(10),
+		})
+	}
+
+	return data
+}
+
+// TestImbalanceStrategyBacktest tests that the imbalance strategy trades
+// historical books, flipping side as the quoted sizes skew
+func TestImbalanceStrategyBacktest(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 30)
+
+	// Bids outweigh asks for the first ten minutes, then asks do, then neither
+	data := trendingData(start, 30, 100, 0)
+	for i, point := range data.DataPoints {
+		switch {
+		case i < 10:
+			point.BidSize, point.AskSize = decimal.NewFromInt(90), decimal.NewFromInt(10)
+		case i < 20:
+			point.BidSize, point.AskSize = decimal.NewFromInt(10), decimal.NewFromInt(90)
+		}
+	}
+
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(config))
+	defer engine.Stop()
+	require.NoError(t, engine.AddHistoricalData(data))
+	require.NoError(t, engine.RegisterStrategy(strategy.NewImbalanceStrategy(strategy.ImbalanceConfig{
+		Exchange:  "test",
+		Symbols:   []string{"BTC/USD"},
+		Threshold: 0.5,
+		Quantity:  1,
+	})))
+
+	result, err := engine.RunBacktestWithStrategy("imbalance")
+	require.NoError(t, err)
+	require.Len(t, result.Trades, 2)
+	assert.Equal(t, "BUY", result.Trades[0].Side)
+	assert.True(t, result.Trades[0].Quantity.Equal(decimal.NewFromInt(1)))
+	assert.Equal(t, "SELL", result.Trades[1].Side)
+	assert.True(t, result.Trades[1].Quantity.Equal(decimal.NewFromInt(2)))
+}
+
+// TestStopWithoutStart tests that Stop shuts down the managers SetConfig
+// started even when the engine never ran
+func TestStopWithoutStart(t *testing.T) {
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(testConfig(time.Now(), 1)))
+	require.NotNil(t, engine.riskManager)
+	orderManager, ok := engine.orderManager.(*orders.Manager)
+	require.True(t, ok)
+	require.True(t, orderManager.IsRunning())
+
+	require.NoError(t, engine.Stop())
+	assert.False(t, orderManager.IsRunning())
+	assert.False(t, engine.riskManager.IsRunning())
+}
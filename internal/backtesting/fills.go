@@ -0,0 +1,114 @@
+package backtesting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"velocimex/internal/numeric"
+	"velocimex/internal/orders"
+	"velocimex/internal/risk"
+)
+
+// directRouter routes backtest orders straight to the exchange named by the signal
+type directRouter struct{}
+
+// RouteOrder routes the order to its requested exchange
+func (r *directRouter) RouteOrder(ctx context.Context, req *orders.OrderRequest) (*orders.RoutingDecision, error) {
+	return &orders.RoutingDecision{
+		OrderID:    req.ClientID,
+		Exchange:   req.Exchange,
+		Symbol:     req.Symbol,
+		Side:       req.Side,
+		Route:      "direct",
+		Reason:     "backtest",
+		Confidence: 1.0,
+		Timestamp:  time.Now(),
+	}, nil
+}
+
+// UpdateMarketData is a no-op for backtests
+func (r *directRouter) UpdateMarketData(symbol string, data interface{}) {}
+
+// GetBestPrice is not supported for backtests
+func (r *directRouter) GetBestPrice(ctx context.Context, symbol string, side orders.OrderSide, quantity decimal.Decimal) (*orders.RoutingDecision, error) {
+	return nil, fmt.Errorf("best price lookup not supported in backtests")
+}
+
+// applyFill books a fill against the portfolio and returns the realized PnL.
+// Short positions are held as negative quantities so mark-to-market stays consistent.
+func (e *Engine) applyFill(symbol, exchange, side string, quantity, price, commission decimal.Decimal) decimal.Decimal {
+	if e.riskManager == nil {
+		return decimal.Zero
+	}
+
+	signedQty := quantity
+	if side == "SELL" {
+		signedQty = quantity.Neg()
+	}
+
+	portfolio := e.riskManager.GetPortfolio()
+	portfolio.CashBalance = portfolio.CashBalance.Sub(signedQty.Mul(price)).Sub(commission)
+
+	key := fmt.Sprintf("%s:%s", exchange, symbol)
+	realized := decimal.Zero
+	position, exists := portfolio.Positions[key]
+	if !exists {
+		position = &risk.Position{
+			Symbol:     symbol,
+			Exchange:   exchange,
+			Quantity:   decimal.Zero,
+			EntryPrice: price,
+			CreatedAt:  e.currentTime,
+		}
+		portfolio.Positions[key] = position
+	}
+
+	if position.Quantity.IsZero() || position.Quantity.Sign() == signedQty.Sign() {
+		// Opening or adding: weighted average entry
+		newQty := position.Quantity.Add(signedQty)
+		position.EntryPrice = position.Quantity.Mul(position.EntryPrice).Add(signedQty.Mul(price)).Div(newQty)
+		position.Quantity = newQty
+	} else {
+		// Reducing, closing or flipping
+		closeQty := decimal.Min(signedQty.Abs(), position.Quantity.Abs())
+		realized = numeric.Round(price.Sub(position.EntryPrice).Mul(closeQty))
+		if position.Quantity.IsNegative() {
+			realized = realized.Neg()
+		}
+		position.RealizedPNL = position.RealizedPNL.Add(realized)
+		portfolio.RealizedPNL = portfolio.RealizedPNL.Add(realized)
+
+		newQty := position.Quantity.Add(signedQty)
+		if newQty.Sign() != 0 && newQty.Sign() != position.Quantity.Sign() {
+			position.EntryPrice = price
+		}
+		position.Quantity = newQty
+	}
+
+	if position.Quantity.IsZero() {
+		delete(portfolio.Positions, key)
+	} else {
+		position.Side = "LONG"
+		if position.Quantity.IsNegative() {
+			position.Side = "SHORT"
+		}
+		position.CurrentPrice = price
+		position.MarketValue = position.Quantity.Mul(price)
+		position.UnrealizedPNL = position.Quantity.Mul(price.Sub(position.EntryPrice))
+		position.UpdatedAt = e.currentTime
+	}
+
+	portfolio.TotalValue = portfolio.CashBalance
+	portfolio.InvestedValue = decimal.Zero
+	portfolio.UnrealizedPNL = decimal.Zero
+	for _, p := range portfolio.Positions {
+		portfolio.TotalValue = portfolio.TotalValue.Add(p.MarketValue)
+		portfolio.InvestedValue = portfolio.InvestedValue.Add(p.Quantity.Mul(p.EntryPrice).Abs())
+		portfolio.UnrealizedPNL = portfolio.UnrealizedPNL.Add(p.UnrealizedPNL)
+	}
+
+	e.riskManager.UpdatePortfolio(portfolio)
+	return realized
+}
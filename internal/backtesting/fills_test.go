@@ -0,0 +1,75 @@
+package backtesting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/orders"
+	"velocimex/internal/risk"
+)
+
+// TestApplyFill tests that fills open, add to, reduce, flip and close a
+// position, realizing PnL against the average entry price
+func TestApplyFill(t *testing.T) {
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(testConfig(time.Now(), 1)))
+	defer engine.Stop()
+	require.NoError(t, engine.riskManager.UpdatePortfolio(&risk.Portfolio{
+		CashBalance: decimal.NewFromInt(10000),
+		Positions:   make(map[string]*risk.Position),
+	}))
+
+	fills := []struct {
+		name       string
+		side       string
+		quantity   int64
+		price      int64
+		realized   int64
+		position   int64 // Signed quantity afterwards
+		entryPrice int64
+	}{
+		{"open long", "BUY", 2, 100, 0, 2, 100},
+		{"add at a higher price", "BUY", 2, 110, 0, 4, 105},
+		{"reduce at a profit", "SELL", 1, 120, 15, 3, 105},
+		{"flip to short at a loss", "SELL", 5, 100, -15, -2, 100},
+		{"close the short at a profit", "BUY", 2, 90, 20, 0, 0},
+	}
+	for _, fill := range fills {
+		realized := engine.applyFill("BTC/USD", "test", fill.side, decimal.NewFromInt(fill.quantity), decimal.NewFromInt(fill.price), decimal.Zero)
+		assert.True(t, realized.Equal(decimal.NewFromInt(fill.realized)), "%s: realized %s", fill.name, realized)
+
+		position, open := engine.riskManager.GetPortfolio().Positions["test:BTC/USD"]
+		if fill.position == 0 {
+			assert.False(t, open, "%s: closed positions are removed", fill.name)
+			continue
+		}
+		require.True(t, open, fill.name)
+		assert.True(t, position.Quantity.Equal(decimal.NewFromInt(fill.position)), "%s: quantity %s", fill.name, position.Quantity)
+		assert.True(t, position.EntryPrice.Equal(decimal.NewFromInt(fill.entryPrice)), "%s: entry %s", fill.name, position.EntryPrice)
+	}
+
+	portfolio := engine.riskManager.GetPortfolio()
+	assert.True(t, portfolio.CashBalance.Equal(decimal.NewFromInt(10020)), "cash %s", portfolio.CashBalance)
+	assert.True(t, portfolio.RealizedPNL.Equal(decimal.NewFromInt(20)), "realized %s", portfolio.RealizedPNL)
+	assert.True(t, portfolio.TotalValue.Equal(portfolio.CashBalance))
+
+	// Commission comes out of cash
+	engine.applyFill("BTC/USD", "test", "BUY", decimal.NewFromInt(1), decimal.NewFromInt(100), decimal.NewFromInt(1))
+	assert.True(t, engine.riskManager.GetPortfolio().CashBalance.Equal(decimal.NewFromInt(9919)))
+}
+
+// TestDirectRouter tests that backtest orders route to the exchange they name
+func TestDirectRouter(t *testing.T) {
+	decision, err := (&directRouter{}).RouteOrder(context.Background(), &orders.OrderRequest{
+		Exchange: "kraken",
+		Symbol:   "BTC/USD",
+		Side:     orders.OrderSideBuy,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "kraken", decision.Exchange)
+	assert.Equal(t, "BTC/USD", decision.Symbol)
+}
@@ -0,0 +1,54 @@
+package backtesting
+
+import (
+	"time"
+
+	"velocimex/internal/strategy"
+)
+
+// Results returns the stored backtest results, oldest first
+func (e *Engine) Results() []*BacktestResult {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	results := make([]*BacktestResult, 0, len(e.resultIDs))
+	for _, id := range e.resultIDs {
+		results = append(results, e.results[id])
+	}
+	return results
+}
+
+// TradeResults returns a run's realized trades attributed to their strategy
+// by name, as live trades are. With a window, only trades in that final
+// stretch of the backtest period are returned. Trades that opened a
+// position realize nothing and are left out.
+func (r *BacktestResult) TradeResults(window time.Duration) []strategy.TradeResult {
+	cutoff := r.EndTime.Add(-window)
+
+	trades := make([]strategy.TradeResult, 0, len(r.Trades))
+	for _, trade := range r.Trades {
+		if trade.PnL.IsZero() {
+			continue
+		}
+		at := trade.ExitTime
+		if at.IsZero() {
+			at = trade.EntryTime
+		}
+		if window > 0 && !at.After(cutoff) {
+			continue
+		}
+
+		name := trade.StrategyName
+		if name == "" {
+			name = trade.StrategyID
+		}
+		pnl, _ := trade.PnL.Float64()
+		trades = append(trades, strategy.TradeResult{
+			Strategy:  name,
+			Symbol:    trade.Symbol,
+			PnL:       pnl,
+			Timestamp: at,
+		})
+	}
+	return trades
+}
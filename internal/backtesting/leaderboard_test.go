@@ -0,0 +1,34 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResultTradeResults tests that a run's realized trades are attributed by
+// strategy name and limited to the end of the run by the window
+func TestResultTradeResults(t *testing.T) {
+	end := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	result := &BacktestResult{
+		EndTime: end,
+		Trades: []*BacktestTrade{
+			{Symbol: "BTC/USD", EntryTime: end.Add(-3 * time.Hour), PnL: decimal.NewFromInt(5), StrategyID: "id", StrategyName: "trend"},
+			{Symbol: "BTC/USD", EntryTime: end.Add(-2 * time.Hour), PnL: decimal.Zero, StrategyID: "id", StrategyName: "trend"},
+			{Symbol: "BTC/USD", EntryTime: end.Add(-30 * time.Minute), PnL: decimal.NewFromInt(-2), StrategyID: "id"},
+		},
+	}
+
+	trades := result.TradeResults(0)
+	require.Len(t, trades, 2)
+	assert.Equal(t, "trend", trades[0].Strategy)
+	assert.Equal(t, 5.0, trades[0].PnL)
+	assert.Equal(t, "id", trades[1].Strategy)
+
+	trades = result.TradeResults(time.Hour)
+	require.Len(t, trades, 1)
+	assert.Equal(t, -2.0, trades[0].PnL)
+}
@@ -0,0 +1,135 @@
+package backtesting
+
+import (
+	"sync"
+	"time"
+)
+
+// Backtest phases timed during a run
+const (
+	PhaseDataLoading     = "data_loading"     // Resampling the data and applying each tick's data points to the books
+	PhaseStrategyEval    = "strategy_eval"    // Generating and executing the strategy's signals
+	PhasePortfolioUpdate = "portfolio_update" // Marking positions and snapshotting the portfolio
+)
+
+// MetricsRecorder records the duration of each run and of its phases, e.g.
+// into the backtest histograms scraped from this process
+type MetricsRecorder interface {
+	RecordBacktestDuration(strategy string, duration time.Duration)
+	RecordBacktestPhase(strategy, phase string, duration time.Duration)
+}
+
+// BacktestProgress reports how far the current, or else the last, run has got
+type BacktestProgress struct {
+	StrategyID  string                   `json:"strategy_id"`
+	Running     bool                     `json:"running"`
+	Ticks       int                      `json:"ticks"`
+	TotalTicks  int                      `json:"total_ticks"`
+	Percent     float64                  `json:"percent"`
+	CurrentTime time.Time                `json:"current_time"`
+	Phases      map[string]time.Duration `json:"phases"` // Time spent in each phase so far
+}
+
+// runProgress tracks the progress of a run. It has its own lock because a run
+// holds the engine's for its whole duration.
+type runProgress struct {
+	mu          sync.Mutex
+	strategyID  string
+	running     bool
+	ticks       int
+	totalTicks  int
+	currentTime time.Time
+	phases      map[string]time.Duration
+}
+
+// begin resets the progress for a run starting at the given tick
+func (p *runProgress) begin(strategyID string, config BacktestConfig, ticks int, currentTime time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.strategyID = strategyID
+	p.running = true
+	p.ticks = ticks
+	p.totalTicks = 0
+	if config.DataFrequency > 0 && config.EndDate.After(config.StartDate) {
+		span := config.EndDate.Sub(config.StartDate)
+		p.totalTicks = int((span + config.DataFrequency - 1) / config.DataFrequency)
+	}
+	p.currentTime = currentTime
+	p.phases = map[string]time.Duration{
+		PhaseDataLoading:     0,
+		PhaseStrategyEval:    0,
+		PhasePortfolioUpdate: 0,
+	}
+}
+
+// addPhase adds the time since start to a phase
+func (p *runProgress) addPhase(phase string, start time.Time) {
+	elapsed := time.Since(start)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.phases[phase] += elapsed
+}
+
+// tick records a completed tick
+func (p *runProgress) tick(ticks int, currentTime time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ticks = ticks
+	p.currentTime = currentTime
+}
+
+// finish marks the run as no longer running
+func (p *runProgress) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running = false
+}
+
+// snapshot returns a copy of the progress
+func (p *runProgress) snapshot() BacktestProgress {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	progress := BacktestProgress{
+		StrategyID:  p.strategyID,
+		Running:     p.running,
+		Ticks:       p.ticks,
+		TotalTicks:  p.totalTicks,
+		CurrentTime: p.currentTime,
+		Phases:      make(map[string]time.Duration, len(p.phases)),
+	}
+	if p.totalTicks > 0 {
+		progress.Percent = min(100, float64(p.ticks)/float64(p.totalTicks)*100)
+	}
+	for phase, duration := range p.phases {
+		progress.Phases[phase] = duration
+	}
+	return progress
+}
+
+// Progress returns the progress of the current run, or of the last one
+// once it has finished. It can be called while a run is in progress.
+func (e *Engine) Progress() BacktestProgress {
+	return e.progress.snapshot()
+}
+
+// SetMetricsRecorder sets where the duration of each run and its phases is recorded
+func (e *Engine) SetMetricsRecorder(recorder MetricsRecorder) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.metricsRecorder = recorder
+}
+
+// recordMetrics records a completed run's duration and phase times. Callers
+// must hold e.mu.
+func (e *Engine) recordMetrics(strategyID string, result *BacktestResult) {
+	if e.metricsRecorder == nil {
+		return
+	}
+	e.metricsRecorder.RecordBacktestDuration(strategyID, result.Duration)
+	for phase, duration := range result.Phases {
+		e.metricsRecorder.RecordBacktestPhase(strategyID, phase, duration)
+	}
+}
@@ -0,0 +1,75 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingRecorder records the durations reported to it
+type recordingRecorder struct {
+	durations map[string]time.Duration
+	phases    map[string]time.Duration
+}
+
+func newRecordingRecorder() *recordingRecorder {
+	return &recordingRecorder{durations: make(map[string]time.Duration), phases: make(map[string]time.Duration)}
+}
+
+func (r *recordingRecorder) RecordBacktestDuration(strategy string, duration time.Duration) {
+	r.durations[strategy] = duration
+}
+
+func (r *recordingRecorder) RecordBacktestPhase(strategy, phase string, duration time.Duration) {
+	r.phases[phase] = duration
+}
+
+// TestBacktestPhaseMetrics tests that each phase of a run records a positive duration
+func TestBacktestPhaseMetrics(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(testConfig(start, 20)))
+	defer engine.Stop()
+	require.NoError(t, engine.AddHistoricalData(trendingData(start, 20, 100, 1)))
+	require.NoError(t, engine.RegisterStrategy(newTestStrategy()))
+
+	recorder := newRecordingRecorder()
+	engine.SetMetricsRecorder(recorder)
+
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+
+	phases := []string{PhaseDataLoading, PhaseStrategyEval, PhasePortfolioUpdate}
+	for _, phase := range phases {
+		assert.Positive(t, result.Phases[phase], phase)
+		assert.Equal(t, result.Phases[phase], recorder.phases[phase], phase)
+	}
+	assert.Len(t, recorder.phases, len(phases))
+	assert.Equal(t, result.Duration, recorder.durations["test"])
+}
+
+// TestBacktestProgress tests that progress reports every tick once a run finishes
+func TestBacktestProgress(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(testConfig(start, 10)))
+	defer engine.Stop()
+	require.NoError(t, engine.AddHistoricalData(trendingData(start, 10, 100, 1)))
+	require.NoError(t, engine.RegisterStrategy(newTestStrategy()))
+
+	assert.False(t, engine.Progress().Running)
+
+	_, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+
+	progress := engine.Progress()
+	assert.Equal(t, "test", progress.StrategyID)
+	assert.False(t, progress.Running)
+	assert.Equal(t, 10, progress.Ticks)
+	assert.Equal(t, 10, progress.TotalTicks)
+	assert.Equal(t, 100.0, progress.Percent)
+	assert.True(t, progress.CurrentTime.Equal(start.Add(10*time.Minute)), "current time %s", progress.CurrentTime)
+	assert.Positive(t, progress.Phases[PhaseStrategyEval])
+}
@@ -0,0 +1,88 @@
+package backtesting
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"velocimex/internal/risk"
+)
+
+// InitialPosition is an open position a backtest starts with, held alongside
+// the initial capital as cash
+type InitialPosition struct {
+	Symbol     string          `json:"symbol"`
+	Exchange   string          `json:"exchange"`
+	Quantity   decimal.Decimal `json:"quantity"`    // Negative for a short
+	EntryPrice decimal.Decimal `json:"entry_price"` // Price the position was opened at; PnL is measured from it
+}
+
+// validateInitialPositions checks that each seeded position is complete and
+// that no market is seeded twice
+func validateInitialPositions(positions []InitialPosition) error {
+	seen := make(map[string]bool, len(positions))
+	for _, position := range positions {
+		if position.Symbol == "" || position.Exchange == "" {
+			return fmt.Errorf("initial position requires a symbol and exchange")
+		}
+		key := fmt.Sprintf("%s:%s", position.Exchange, position.Symbol)
+		if seen[key] {
+			return fmt.Errorf("duplicate initial position: %s", key)
+		}
+		seen[key] = true
+		if position.Quantity.IsZero() {
+			return fmt.Errorf("initial position %s has zero quantity", key)
+		}
+		if !position.EntryPrice.IsPositive() {
+			return fmt.Errorf("initial position %s entry price must be positive", key)
+		}
+	}
+	return nil
+}
+
+// openingPortfolio returns the portfolio a run starts with: the initial
+// capital as cash plus the seeded positions, marked at the close of their
+// data point at StartDate, or at their entry price without data
+func (e *Engine) openingPortfolio() *risk.Portfolio {
+	portfolio := &risk.Portfolio{
+		TotalValue:    e.config.InitialCapital,
+		CashBalance:   e.config.InitialCapital,
+		InvestedValue: decimal.Zero,
+		UnrealizedPNL: decimal.Zero,
+		RealizedPNL:   decimal.Zero,
+		DailyPNL:      decimal.Zero,
+		Positions:     make(map[string]*risk.Position),
+		LastUpdated:   e.config.StartDate,
+	}
+
+	for _, seed := range e.config.InitialPositions {
+		price := seed.EntryPrice
+		if data := e.historicalData[seed.Symbol][seed.Exchange]; data != nil {
+			if dataPoint := e.findDataPointForTime(data, e.config.StartDate); dataPoint != nil {
+				price = dataPoint.Close
+			}
+		}
+
+		side := "LONG"
+		if seed.Quantity.IsNegative() {
+			side = "SHORT"
+		}
+		position := &risk.Position{
+			Symbol:        seed.Symbol,
+			Exchange:      seed.Exchange,
+			Side:          side,
+			Quantity:      seed.Quantity,
+			EntryPrice:    seed.EntryPrice,
+			CurrentPrice:  price,
+			MarketValue:   seed.Quantity.Mul(price),
+			UnrealizedPNL: seed.Quantity.Mul(price.Sub(seed.EntryPrice)),
+			CreatedAt:     e.config.StartDate,
+			UpdatedAt:     e.config.StartDate,
+		}
+		portfolio.Positions[fmt.Sprintf("%s:%s", seed.Exchange, seed.Symbol)] = position
+
+		portfolio.TotalValue = portfolio.TotalValue.Add(position.MarketValue)
+		portfolio.InvestedValue = portfolio.InvestedValue.Add(seed.Quantity.Mul(seed.EntryPrice).Abs())
+		portfolio.UnrealizedPNL = portfolio.UnrealizedPNL.Add(position.UnrealizedPNL)
+	}
+	return portfolio
+}
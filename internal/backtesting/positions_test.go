@@ -0,0 +1,104 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSeededEngine sets up a backtest starting with 10000 cash and a long of
+// 2 BTC/USD entered at 90
+func newSeededEngine(t *testing.T, data *HistoricalData, s *testStrategy) *Engine {
+	t.Helper()
+
+	config := testConfig(data.StartTime, len(data.DataPoints))
+	config.InitialCapital = decimal.NewFromInt(10000)
+	config.InitialPositions = []InitialPosition{{
+		Symbol:     "BTC/USD",
+		Exchange:   "test",
+		Quantity:   decimal.NewFromInt(2),
+		EntryPrice: decimal.NewFromInt(90),
+	}}
+
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(config))
+	t.Cleanup(func() { engine.Stop() })
+	require.NoError(t, engine.AddHistoricalData(data))
+	require.NoError(t, engine.RegisterStrategy(s))
+	return engine
+}
+
+// TestInitialPositionsOpeningValue tests that seeded positions are valued at
+// the opening price and that only moves after the start count as return
+func TestInitialPositionsOpeningValue(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Rises from 100 to 109; the strategy's only order never matches a market
+	s := newTestStrategy()
+	s.symbol = "ETH/USD"
+	engine := newSeededEngine(t, trendingData(start, 10, 100, 1), s)
+
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+	assert.Empty(t, result.Trades)
+
+	// 10000 cash and 2 BTC at 100, not at the 90 entry
+	assert.True(t, result.InitialCapital.Equal(decimal.NewFromInt(10200)), "opening value %s", result.InitialCapital)
+	opening := result.PortfolioHistory[0]
+	assert.True(t, opening.CashBalance.Equal(decimal.NewFromInt(10000)), "cash %s", opening.CashBalance)
+	assert.Contains(t, opening.Positions, "test:BTC/USD")
+
+	// The position ends marked at 109
+	assert.True(t, result.FinalCapital.Equal(decimal.NewFromInt(10218)), "final value %s", result.FinalCapital)
+	assert.True(t, result.TotalReturn.Equal(decimal.NewFromInt(18)), "return %s", result.TotalReturn)
+}
+
+// TestInitialPositionsRealizedPnL tests that closing a seeded position
+// realizes PnL from its seeded entry price
+func TestInitialPositionsRealizedPnL(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := newTestStrategy()
+	s.side = "SELL"
+	s.quantity = decimal.NewFromInt(2)
+	engine := newSeededEngine(t, trendingData(start, 10, 100, 0), s)
+
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+	require.Len(t, result.Trades, 1)
+
+	// Sold at 100 against the 90 entry
+	assert.True(t, result.Trades[0].PnL.Equal(decimal.NewFromInt(20)), "pnl %s", result.Trades[0].PnL)
+	assert.Equal(t, 1, result.WinningTrades)
+
+	final := result.PortfolioHistory[len(result.PortfolioHistory)-1]
+	assert.Empty(t, final.Positions)
+	assert.True(t, final.CashBalance.Equal(decimal.NewFromInt(10200)), "cash %s", final.CashBalance)
+	assert.True(t, final.RealizedPNL.Equal(decimal.NewFromInt(20)), "realized %s", final.RealizedPNL)
+
+	// The gain was already in the opening value, so the run itself returned nothing
+	assert.True(t, result.TotalReturn.IsZero(), "return %s", result.TotalReturn)
+}
+
+// TestInitialPositionsValidation tests that incomplete seeded positions are rejected
+func TestInitialPositionsValidation(t *testing.T) {
+	valid := InitialPosition{Symbol: "BTC/USD", Exchange: "test", Quantity: decimal.NewFromInt(-1), EntryPrice: decimal.NewFromInt(100)}
+
+	for name, positions := range map[string][]InitialPosition{
+		"no symbol":      {{Exchange: "test", Quantity: valid.Quantity, EntryPrice: valid.EntryPrice}},
+		"zero quantity":  {{Symbol: "BTC/USD", Exchange: "test", EntryPrice: valid.EntryPrice}},
+		"no entry price": {{Symbol: "BTC/USD", Exchange: "test", Quantity: valid.Quantity}},
+		"duplicate":      {valid, valid},
+	} {
+		config := DefaultBacktestConfig()
+		config.InitialPositions = positions
+		assert.Error(t, NewEngine().SetConfig(config), name)
+	}
+
+	config := DefaultBacktestConfig()
+	config.InitialPositions = []InitialPosition{valid}
+	engine := NewEngine()
+	assert.NoError(t, engine.SetConfig(config))
+	engine.Stop()
+}
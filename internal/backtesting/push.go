@@ -0,0 +1,46 @@
+package backtesting
+
+import (
+	"log"
+	"time"
+)
+
+// MetricsPusher publishes the metrics of a completed backtest, e.g. to a
+// Prometheus Pushgateway, since a backtest job may exit before it is scraped
+type MetricsPusher interface {
+	PushBacktest(strategy string, duration time.Duration, results map[string]float64) error
+}
+
+// SetMetricsPusher sets where the metrics of each completed run are pushed
+func (e *Engine) SetMetricsPusher(pusher MetricsPusher) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.metricsPusher = pusher
+}
+
+// pushResult pushes a completed run's metrics. A failed push is logged and
+// does not fail the run. Callers must hold e.mu.
+func (e *Engine) pushResult(strategyID string, result *BacktestResult) {
+	if e.metricsPusher == nil {
+		return
+	}
+	if err := e.metricsPusher.PushBacktest(strategyID, result.Duration, resultMetrics(result)); err != nil {
+		log.Printf("Backtest %s: %v", strategyID, err)
+	}
+}
+
+// resultMetrics returns the headline metrics of a result by name
+func resultMetrics(result *BacktestResult) map[string]float64 {
+	return map[string]float64{
+		"final_capital":    result.FinalCapital.InexactFloat64(),
+		"total_return":     result.TotalReturn.InexactFloat64(),
+		"total_return_pct": result.TotalReturnPct.InexactFloat64(),
+		"total_trades":     float64(result.TotalTrades),
+		"win_rate":         result.WinRate.InexactFloat64(),
+		"sharpe_ratio":     result.SharpeRatio.InexactFloat64(),
+		"sortino_ratio":    result.SortinoRatio.InexactFloat64(),
+		"max_drawdown_pct": result.MaxDrawdownPct.InexactFloat64(),
+		"total_commission": result.TotalCommission.InexactFloat64(),
+		"total_slippage":   result.TotalSlippage.InexactFloat64(),
+	}
+}
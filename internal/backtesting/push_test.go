@@ -0,0 +1,66 @@
+package backtesting
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingPusher records the pushes made to it
+type recordingPusher struct {
+	strategies []string
+	durations  []time.Duration
+	results    []map[string]float64
+	err        error
+}
+
+func (p *recordingPusher) PushBacktest(strategy string, duration time.Duration, results map[string]float64) error {
+	p.strategies = append(p.strategies, strategy)
+	p.durations = append(p.durations, duration)
+	p.results = append(p.results, results)
+	return p.err
+}
+
+// TestBacktestMetricsPushed tests that a completed run pushes its duration and results
+func TestBacktestMetricsPushed(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(testConfig(start, 10)))
+	defer engine.Stop()
+	require.NoError(t, engine.AddHistoricalData(trendingData(start, 10, 100, 1)))
+	require.NoError(t, engine.RegisterStrategy(newTestStrategy()))
+
+	pusher := &recordingPusher{}
+	engine.SetMetricsPusher(pusher)
+
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"test"}, pusher.strategies)
+	assert.Equal(t, result.Duration, pusher.durations[0])
+	assert.Equal(t, result.TotalReturnPct.InexactFloat64(), pusher.results[0]["total_return_pct"])
+	assert.Equal(t, float64(result.TotalTrades), pusher.results[0]["total_trades"])
+	assert.Equal(t, result.FinalCapital.InexactFloat64(), pusher.results[0]["final_capital"])
+	assert.Contains(t, pusher.results[0], "sharpe_ratio")
+	assert.Contains(t, pusher.results[0], "max_drawdown_pct")
+}
+
+// TestBacktestMetricsPushFailure tests that a failed push does not fail the run
+func TestBacktestMetricsPushFailure(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(testConfig(start, 10)))
+	defer engine.Stop()
+	require.NoError(t, engine.AddHistoricalData(trendingData(start, 10, 100, 1)))
+	require.NoError(t, engine.RegisterStrategy(newTestStrategy()))
+
+	pusher := &recordingPusher{err: errors.New("gateway unavailable")}
+	engine.SetMetricsPusher(pusher)
+
+	_, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+	assert.Len(t, pusher.strategies, 1)
+}
@@ -0,0 +1,102 @@
+package backtesting
+
+import (
+	"log"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"velocimex/internal/strategy"
+)
+
+// restingOrder is a limit order waiting in the queue at its price level
+type restingOrder struct {
+	signal     *strategy.Signal
+	strategy   strategy.Strategy
+	placedAt   time.Time
+	queueAhead decimal.Decimal // Displayed size ahead of the order when it was placed
+	traded     decimal.Decimal // Volume traded at the order's price since it was placed
+}
+
+// isMarketable reports whether a signal crosses the spread and fills on arrival
+func isMarketable(signal *strategy.Signal, dataPoint *DataPoint) bool {
+	if signal.Side == "SELL" {
+		return signal.Price.LessThanOrEqual(dataPoint.Bid)
+	}
+	return signal.Price.GreaterThanOrEqual(dataPoint.Ask)
+}
+
+// currentDataPoint returns the data point for a symbol and exchange at the current backtest time
+func (e *Engine) currentDataPoint(symbol, exchange string) *DataPoint {
+	data := e.historicalData[symbol][exchange]
+	if data == nil {
+		return nil
+	}
+	return e.findDataPointForTime(data, e.currentTime)
+}
+
+// restOrder queues a limit order behind the displayed size at its level.
+// Orders that improve on the best price start at the front of a new level.
+func (e *Engine) restOrder(signal *strategy.Signal, strategy strategy.Strategy, dataPoint *DataPoint) {
+	queueAhead := dataPoint.BidSize
+	improves := signal.Price.GreaterThan(dataPoint.Bid)
+	if signal.Side == "SELL" {
+		queueAhead = dataPoint.AskSize
+		improves = signal.Price.LessThan(dataPoint.Ask)
+	}
+	if improves {
+		queueAhead = decimal.Zero
+	}
+
+	e.restingOrders = append(e.restingOrders, &restingOrder{
+		signal:     signal,
+		strategy:   strategy,
+		placedAt:   e.currentTime,
+		queueAhead: queueAhead,
+		traded:     decimal.Zero,
+	})
+}
+
+// processRestingOrders fills resting orders once the market trades through their level,
+// or once enough volume has traded at their level to clear the queue ahead of them
+func (e *Engine) processRestingOrders() {
+	remaining := e.restingOrders[:0]
+	for _, order := range e.restingOrders {
+		if !order.placedAt.Before(e.currentTime) {
+			remaining = append(remaining, order)
+			continue
+		}
+
+		dataPoint := e.currentDataPoint(order.signal.Symbol, order.signal.Exchange)
+		if dataPoint == nil || !order.advance(dataPoint) {
+			remaining = append(remaining, order)
+			continue
+		}
+
+		if err := e.fillSignal(order.signal, order.strategy, true); err != nil {
+			log.Printf("Error filling resting limit order: %v", err)
+		}
+	}
+	e.restingOrders = remaining
+}
+
+// advance applies a bar of trading to the order and reports whether it filled
+func (o *restingOrder) advance(dataPoint *DataPoint) bool {
+	price := o.signal.Price
+
+	var tradedThrough, tradedAt bool
+	if o.signal.Side == "SELL" {
+		tradedThrough = dataPoint.High.GreaterThan(price)
+		tradedAt = dataPoint.High.Equal(price)
+	} else {
+		tradedThrough = dataPoint.Low.LessThan(price)
+		tradedAt = dataPoint.Low.Equal(price)
+	}
+
+	if tradedThrough {
+		return true
+	}
+	if tradedAt {
+		o.traded = o.traded.Add(dataPoint.Volume)
+	}
+	return o.traded.GreaterThanOrEqual(o.queueAhead.Add(o.signal.Quantity))
+}
@@ -0,0 +1,139 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// quotedData returns flat data quoted 100/101 with lows at the bid and the given volume per bar
+func quotedData(start time.Time, ticks int, volume int64) *HistoricalData {
+	data := trendingData(start, ticks, 100, 0)
+	for _, point := range data.DataPoints {
+		point.Ask = decimal.NewFromInt(101)
+		point.High = decimal.NewFromInt(101)
+		point.Volume = decimal.NewFromInt(volume)
+	}
+	return data
+}
+
+func runQueueBacktest(t *testing.T, config BacktestConfig, data *HistoricalData, price decimal.Decimal) *BacktestResult {
+	t.Helper()
+
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(config))
+	defer engine.Stop()
+	require.NoError(t, engine.AddHistoricalData(data))
+
+	strategy := newTestStrategy()
+	strategy.quantity = decimal.NewFromInt(2)
+	strategy.price = price
+	require.NoError(t, engine.RegisterStrategy(strategy))
+
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+	return result
+}
+
+// TestLimitOrderQueuePosition tests that a resting bid fills only after volume clears the queue ahead of it
+func TestLimitOrderQueuePosition(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 10)
+	config.QueueModel = true
+
+	// 10 lots displayed ahead plus our 2 need 12 traded; 4 per bar clears after three bars
+	result := runQueueBacktest(t, config, quotedData(start, 10, 4), decimal.NewFromInt(100))
+
+	require.Len(t, result.Trades, 1)
+	assert.Equal(t, start.Add(3*time.Minute), result.Trades[0].EntryTime)
+	assert.True(t, result.Trades[0].EntryPrice.Equal(decimal.NewFromInt(100)))
+	assert.Equal(t, 0, result.OpenLimitOrders)
+}
+
+// TestLimitOrderQueueInsufficientVolume tests that the order keeps resting while volume is too thin
+func TestLimitOrderQueueInsufficientVolume(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 5)
+	config.QueueModel = true
+
+	// 4 bars of 2 lots is only 8 of the 12 needed
+	result := runQueueBacktest(t, config, quotedData(start, 5, 2), decimal.NewFromInt(100))
+
+	assert.Empty(t, result.Trades)
+	assert.Equal(t, 1, result.OpenLimitOrders)
+}
+
+// TestLimitOrderQueueTradeThrough tests that trading through the level fills regardless of queue
+func TestLimitOrderQueueTradeThrough(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 5)
+	config.QueueModel = true
+
+	data := quotedData(start, 5, 0)
+	data.DataPoints[2].Low = decimal.NewFromInt(99)
+
+	result := runQueueBacktest(t, config, data, decimal.NewFromInt(100))
+
+	require.Len(t, result.Trades, 1)
+	assert.Equal(t, start.Add(2*time.Minute), result.Trades[0].EntryTime)
+}
+
+// TestLimitOrderQueueDisabled tests that without the queue model limit orders fill on arrival
+func TestLimitOrderQueueDisabled(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 5)
+
+	result := runQueueBacktest(t, config, quotedData(start, 5, 0), decimal.NewFromInt(100))
+
+	require.Len(t, result.Trades, 1)
+	assert.Equal(t, start, result.Trades[0].EntryTime)
+}
+
+// TestLimitOrderQueueMarketable tests that a crossing order fills immediately under the queue model
+func TestLimitOrderQueueMarketable(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 5)
+	config.QueueModel = true
+
+	result := runQueueBacktest(t, config, quotedData(start, 5, 0), decimal.NewFromInt(101))
+
+	require.Len(t, result.Trades, 1)
+	assert.Equal(t, start, result.Trades[0].EntryTime)
+}
+
+// TestMakerRebate tests that resting fills pay the maker commission, so a
+// rebate lowers total cost and raises net PnL, while crossing fills still pay
+// the taker commission
+func TestMakerRebate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rebate := decimal.NewFromFloat(-0.0005)
+	config := testConfig(start, 10)
+	config.QueueModel = true
+	config.Commission = decimal.NewFromFloat(0.002)
+
+	// Without a maker commission resting fills pay the taker rate: 2 * 100 * 0.002
+	charged := runQueueBacktest(t, config, quotedData(start, 10, 4), decimal.NewFromInt(100))
+	require.Len(t, charged.Trades, 1)
+	assert.True(t, charged.TotalCommission.Equal(decimal.NewFromFloat(0.4)), "commission %s", charged.TotalCommission)
+
+	// A rebate of 2 * 100 * 0.0005 is paid instead
+	config.MakerCommission = &rebate
+	maker := runQueueBacktest(t, config, quotedData(start, 10, 4), decimal.NewFromInt(100))
+	require.Len(t, maker.Trades, 1)
+	assert.True(t, maker.Trades[0].Maker)
+	assert.True(t, maker.Trades[0].Commission.Equal(decimal.NewFromFloat(-0.1)), "commission %s", maker.Trades[0].Commission)
+	assert.True(t, maker.TotalCommission.Equal(decimal.NewFromFloat(-0.1)))
+	assert.True(t, maker.Costs.CommissionDragBps.IsNegative())
+	assert.True(t, maker.FinalCapital.Sub(charged.FinalCapital).Equal(decimal.NewFromFloat(0.5)),
+		"rebate should add 0.5 to net PnL, got %s", maker.FinalCapital.Sub(charged.FinalCapital))
+
+	// A crossing order is a taker fill: 2 * 101 * 0.002
+	taker := runQueueBacktest(t, config, quotedData(start, 5, 0), decimal.NewFromInt(101))
+	require.Len(t, taker.Trades, 1)
+	assert.False(t, taker.Trades[0].Maker)
+	assert.True(t, taker.TotalCommission.Equal(decimal.NewFromFloat(0.404)), "commission %s", taker.TotalCommission)
+	assert.True(t, taker.Costs.CommissionDragBps.IsPositive())
+}
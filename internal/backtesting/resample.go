@@ -0,0 +1,104 @@
+package backtesting
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Resample aggregates data points into OHLCV bars of the given frequency.
+// Bars are aligned to multiples of the frequency and stamped with their start
+// time; periods without data produce no bar. Each bar opens at its first
+// point and closes at its last, takes the highest high and lowest low and
+// sums the volume. Points without OHLC prices, such as ticks, count at their
+// close. The quote of a bar is that of its last point.
+func Resample(points []*DataPoint, frequency time.Duration) ([]*DataPoint, error) {
+	if frequency <= 0 {
+		return nil, fmt.Errorf("resample frequency must be positive")
+	}
+
+	sorted := append([]*DataPoint(nil), points...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	bars := make([]*DataPoint, 0)
+	var bar *DataPoint
+	for _, point := range sorted {
+		start := point.Timestamp.Truncate(frequency)
+		open, high, low := barPrices(point)
+
+		if bar == nil || !bar.Timestamp.Equal(start) {
+			bar = &DataPoint{
+				Timestamp: start,
+				Open:      open,
+				High:      high,
+				Low:       low,
+				Volume:    decimal.Zero,
+			}
+			bars = append(bars, bar)
+		}
+
+		bar.High = decimal.Max(bar.High, high)
+		bar.Low = decimal.Min(bar.Low, low)
+		bar.Close = point.Close
+		bar.Volume = bar.Volume.Add(point.Volume)
+		bar.Bid = point.Bid
+		bar.Ask = point.Ask
+		bar.BidSize = point.BidSize
+		bar.AskSize = point.AskSize
+	}
+	return bars, nil
+}
+
+// barPrices returns the open, high and low a point contributes to its bar,
+// falling back to the close for prices it does not carry
+func barPrices(point *DataPoint) (decimal.Decimal, decimal.Decimal, decimal.Decimal) {
+	open, high, low := point.Open, point.High, point.Low
+	if open.IsZero() {
+		open = point.Close
+	}
+	if high.IsZero() {
+		high = decimal.Max(open, point.Close)
+	}
+	if low.IsZero() {
+		low = decimal.Min(open, point.Close)
+	}
+	return open, high, low
+}
+
+// Resample returns a copy of the series aggregated into bars of the given frequency
+func (h *HistoricalData) Resample(frequency time.Duration) (*HistoricalData, error) {
+	bars, err := Resample(h.DataPoints, frequency)
+	if err != nil {
+		return nil, err
+	}
+
+	resampled := *h
+	resampled.DataPoints = bars
+	resampled.Frequency = frequency
+	return &resampled, nil
+}
+
+// resampleHistoricalData converts every series to the configured resample
+// frequency. Series already at that frequency are left as they are.
+func (e *Engine) resampleHistoricalData() error {
+	frequency := e.config.ResampleFrequency
+	if frequency <= 0 {
+		return nil
+	}
+
+	for symbol, exchanges := range e.historicalData {
+		for exchange, data := range exchanges {
+			if data.Frequency == frequency {
+				continue
+			}
+			resampled, err := data.Resample(frequency)
+			if err != nil {
+				return fmt.Errorf("failed to resample %s on %s: %w", symbol, exchange, err)
+			}
+			exchanges[exchange] = resampled
+		}
+	}
+	return nil
+}
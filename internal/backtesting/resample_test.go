@@ -0,0 +1,119 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tick(at time.Time, price, volume float64) *DataPoint {
+	return &DataPoint{
+		Timestamp: at,
+		Close:     decimal.NewFromFloat(price),
+		Volume:    decimal.NewFromFloat(volume),
+		Bid:       decimal.NewFromFloat(price - 0.5),
+		Ask:       decimal.NewFromFloat(price + 0.5),
+	}
+}
+
+// TestResampleTicksIntoBars tests that ticks are aggregated into aligned
+// OHLCV bars, skipping periods without data
+func TestResampleTicksIntoBars(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes, seconds int) time.Time {
+		return start.Add(time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second)
+	}
+
+	// Out of order to check the series is sorted first
+	ticks := []*DataPoint{
+		tick(at(0, 10), 100, 1),
+		tick(at(2, 0), 104, 2),
+		tick(at(1, 30), 98, 3),
+		tick(at(4, 59), 101, 4),
+		tick(at(5, 0), 102, 5),
+		tick(at(7, 0), 99, 1),
+		tick(at(16, 0), 110, 2),
+	}
+
+	bars, err := Resample(ticks, 5*time.Minute)
+	require.NoError(t, err)
+	require.Len(t, bars, 3)
+
+	expected := []struct {
+		start                  time.Time
+		open, high, low, close float64
+		volume                 float64
+	}{
+		{at(0, 0), 100, 104, 98, 101, 10},
+		{at(5, 0), 102, 102, 99, 99, 6},
+		{at(15, 0), 110, 110, 110, 110, 2},
+	}
+	for i, want := range expected {
+		bar := bars[i]
+		assert.Equal(t, want.start, bar.Timestamp, "bar %d start", i)
+		assert.True(t, decimal.NewFromFloat(want.open).Equal(bar.Open), "bar %d open %s", i, bar.Open)
+		assert.True(t, decimal.NewFromFloat(want.high).Equal(bar.High), "bar %d high %s", i, bar.High)
+		assert.True(t, decimal.NewFromFloat(want.low).Equal(bar.Low), "bar %d low %s", i, bar.Low)
+		assert.True(t, decimal.NewFromFloat(want.close).Equal(bar.Close), "bar %d close %s", i, bar.Close)
+		assert.True(t, decimal.NewFromFloat(want.volume).Equal(bar.Volume), "bar %d volume %s", i, bar.Volume)
+	}
+
+	// The quote is the last one in the bar
+	assert.True(t, decimal.NewFromFloat(98.5).Equal(bars[1].Bid))
+	assert.True(t, decimal.NewFromFloat(99.5).Equal(bars[1].Ask))
+}
+
+// TestResampleBars tests that bars are merged using their own highs and lows
+func TestResampleBars(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := trendingData(start, 10, 100, 1)
+	data.DataPoints[3].High = decimal.NewFromInt(120)
+	data.DataPoints[6].Low = decimal.NewFromInt(90)
+
+	resampled, err := data.Resample(5 * time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, resampled.Frequency)
+	assert.Len(t, data.DataPoints, 10, "source series is unchanged")
+	require.Len(t, resampled.DataPoints, 2)
+
+	first, second := resampled.DataPoints[0], resampled.DataPoints[1]
+	assert.True(t, decimal.NewFromInt(100).Equal(first.Open))
+	assert.True(t, decimal.NewFromInt(120).Equal(first.High))
+	assert.True(t, decimal.NewFromInt(100).Equal(first.Low))
+	assert.True(t, decimal.NewFromInt(104).Equal(first.Close))
+	assert.True(t, decimal.NewFromInt(500).Equal(first.Volume))
+	assert.True(t, decimal.NewFromInt(105).Equal(second.Open))
+	assert.True(t, decimal.NewFromInt(90).Equal(second.Low))
+	assert.True(t, decimal.NewFromInt(109).Equal(second.Close))
+
+	_, err = Resample(data.DataPoints, 0)
+	assert.Error(t, err)
+}
+
+// TestBacktestResamplesData tests that a run with a resample frequency
+// trades on bars instead of the loaded data
+func TestBacktestResamplesData(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 20)
+	config.DataFrequency = 5 * time.Minute
+	config.ResampleFrequency = 5 * time.Minute
+
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(config))
+	defer engine.Stop()
+	require.NoError(t, engine.AddHistoricalData(trendingData(start, 20, 100, 1)))
+	require.NoError(t, engine.RegisterStrategy(newTestStrategy()))
+
+	_, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+
+	bars := engine.historicalData["BTC/USD"]["test"].DataPoints
+	require.Len(t, bars, 4)
+	for i, bar := range bars {
+		assert.Equal(t, start.Add(time.Duration(i)*5*time.Minute), bar.Timestamp)
+		assert.True(t, decimal.NewFromInt(int64(104+5*i)).Equal(bar.Close), "bar %d close %s", i, bar.Close)
+	}
+}
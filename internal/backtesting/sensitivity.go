@@ -0,0 +1,69 @@
+package backtesting
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"velocimex/internal/numeric"
+)
+
+// DefaultCostMultipliers are the cost assumptions a sensitivity report covers
+// when none are given: half, the configured costs, and one and a half and
+// two times them
+var DefaultCostMultipliers = []decimal.Decimal{
+	decimal.NewFromFloat(0.5),
+	decimal.NewFromInt(1),
+	decimal.NewFromFloat(1.5),
+	decimal.NewFromInt(2),
+}
+
+// CostScenario is a backtest's result with its commission and slippage scaled
+// by a multiplier
+type CostScenario struct {
+	Multiplier   decimal.Decimal `json:"multiplier"`
+	Commission   decimal.Decimal `json:"commission"`
+	Slippage     decimal.Decimal `json:"slippage"`
+	FinalCapital decimal.Decimal `json:"final_capital"`
+	NetReturn    decimal.Decimal `json:"net_return"`
+	NetReturnPct decimal.Decimal `json:"net_return_pct"`
+}
+
+// CostSensitivity re-prices a result under each cost multiplier, in the order
+// given. Rather than re-running the strategy, the difference between the
+// scaled and recorded commission and slippage of every trade is taken off the
+// final capital, so the trades themselves are assumed unchanged. Maker rebates
+// scale with the multiplier like any other commission.
+func (r *BacktestResult) CostSensitivity(multipliers []decimal.Decimal) ([]CostScenario, error) {
+	if len(multipliers) == 0 {
+		multipliers = DefaultCostMultipliers
+	}
+
+	commission := decimal.Zero
+	slippage := decimal.Zero
+	for _, trade := range r.Trades {
+		commission = commission.Add(trade.Commission)
+		slippage = slippage.Add(trade.Slippage)
+	}
+
+	scenarios := make([]CostScenario, 0, len(multipliers))
+	for _, multiplier := range multipliers {
+		if multiplier.IsNegative() {
+			return nil, fmt.Errorf("cost multiplier cannot be negative: %s", multiplier.String())
+		}
+
+		scaledCommission := commission.Mul(multiplier)
+		scaledSlippage := slippage.Mul(multiplier)
+		extraCost := scaledCommission.Sub(commission).Add(scaledSlippage.Sub(slippage))
+		finalCapital := r.FinalCapital.Sub(extraCost)
+
+		scenarios = append(scenarios, CostScenario{
+			Multiplier:   multiplier,
+			Commission:   numeric.Round(scaledCommission),
+			Slippage:     numeric.Round(scaledSlippage),
+			FinalCapital: numeric.Round(finalCapital),
+			NetReturn:    numeric.Round(finalCapital.Sub(r.InitialCapital)),
+			NetReturnPct: numeric.Round(numeric.PercentChange(r.InitialCapital, finalCapital)),
+		})
+	}
+	return scenarios, nil
+}
@@ -0,0 +1,78 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/numeric"
+)
+
+// TestCostSensitivity tests that cost deltas are applied to a result's trades
+func TestCostSensitivity(t *testing.T) {
+	result := &BacktestResult{
+		InitialCapital: decimal.NewFromInt(10000),
+		FinalCapital:   decimal.NewFromInt(10500),
+		Trades: []*BacktestTrade{
+			{Commission: decimal.NewFromInt(20), Slippage: decimal.NewFromInt(10)},
+			{Commission: decimal.NewFromInt(30), Slippage: decimal.NewFromInt(40)},
+		},
+	}
+
+	scenarios, err := result.CostSensitivity(nil)
+	require.NoError(t, err)
+	require.Len(t, scenarios, len(DefaultCostMultipliers))
+
+	// 50 commission and 50 slippage recorded: each half step moves 50
+	expected := []int64{10550, 10500, 10450, 10400}
+	for i, scenario := range scenarios {
+		assert.True(t, scenario.Multiplier.Equal(DefaultCostMultipliers[i]))
+		assert.True(t, scenario.FinalCapital.Equal(decimal.NewFromInt(expected[i])), "%sx final capital %s", scenario.Multiplier, scenario.FinalCapital)
+		assert.True(t, scenario.NetReturn.Equal(decimal.NewFromInt(expected[i]-10000)))
+	}
+	assert.True(t, scenarios[3].Commission.Equal(decimal.NewFromInt(100)))
+	assert.True(t, scenarios[3].Slippage.Equal(decimal.NewFromInt(100)))
+	assert.True(t, scenarios[0].NetReturnPct.Equal(decimal.NewFromFloat(5.5)))
+
+	_, err = result.CostSensitivity([]decimal.Decimal{decimal.NewFromInt(-1)})
+	assert.Error(t, err)
+}
+
+// TestBacktestCostSensitivity tests that higher cost multipliers reduce a
+// backtest's net return monotonically and that 1x reproduces the result
+func TestBacktestCostSensitivity(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 10)
+	config.Commission = decimal.NewFromFloat(0.001)
+	config.Slippage = decimal.NewFromFloat(0.0005)
+
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(config))
+	defer engine.Stop()
+	require.NoError(t, engine.AddHistoricalData(trendingData(start, 10, 100, 1)))
+	require.NoError(t, engine.RegisterStrategy(newTestStrategy()))
+
+	result, err := engine.RunBacktestWithStrategy("test")
+	require.NoError(t, err)
+	require.True(t, result.TotalCommission.IsPositive())
+
+	multipliers := []decimal.Decimal{
+		decimal.Zero,
+		decimal.NewFromFloat(0.5),
+		decimal.NewFromInt(1),
+		decimal.NewFromInt(2),
+		decimal.NewFromInt(4),
+	}
+	scenarios, err := result.CostSensitivity(multipliers)
+	require.NoError(t, err)
+	require.Len(t, scenarios, len(multipliers))
+
+	for i := 1; i < len(scenarios); i++ {
+		assert.True(t, scenarios[i].NetReturn.LessThan(scenarios[i-1].NetReturn),
+			"%sx net return %s not below %sx %s", scenarios[i].Multiplier, scenarios[i].NetReturn, scenarios[i-1].Multiplier, scenarios[i-1].NetReturn)
+	}
+	assert.True(t, scenarios[2].FinalCapital.Equal(numeric.Round(result.FinalCapital)), "1x final capital %s, result %s", scenarios[2].FinalCapital, result.FinalCapital)
+	assert.True(t, scenarios[0].Commission.IsZero())
+}
@@ -0,0 +1,182 @@
+package backtesting
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"velocimex/internal/strategy"
+)
+
+// Sweep objectives
+const (
+	SweepObjectiveSharpe  = "sharpe"
+	SweepObjectiveReturn  = "return"
+	SweepObjectiveWinRate = "win_rate"
+)
+
+// ParameterizedStrategy is a strategy that can be rebuilt with a new set of parameters
+type ParameterizedStrategy interface {
+	strategy.Strategy
+	WithParameters(params map[string]interface{}) (strategy.Strategy, error)
+}
+
+// SweepResult holds the outcome of a single parameter combination
+type SweepResult struct {
+	Parameters map[string]interface{} `json:"parameters"`
+	Objective  float64                `json:"objective"`
+	Result     *BacktestResult        `json:"result,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// RunSweep runs a backtest for every combination in the parameter grid and
+// returns the results sorted by the configured objective, best first
+func (e *Engine) RunSweep(strategyID string, grid map[string][]interface{}) ([]*SweepResult, error) {
+	e.mu.RLock()
+	base, exists := e.strategies[strategyID]
+	config := e.config
+	historicalData := make([]*HistoricalData, 0)
+	for _, exchanges := range e.historicalData {
+		for _, data := range exchanges {
+			historicalData = append(historicalData, data)
+		}
+	}
+	e.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("strategy not found: %s", strategyID)
+	}
+
+	parameterized, ok := base.(ParameterizedStrategy)
+	if !ok {
+		return nil, fmt.Errorf("strategy %s does not support parameter sweeps", strategyID)
+	}
+
+	if len(grid) == 0 {
+		return nil, fmt.Errorf("parameter grid is empty")
+	}
+
+	// Combinations run in parallel and would clash on one checkpoint file
+	config.CheckpointPath = ""
+
+	objective := config.SweepObjective
+	if objective == "" {
+		objective = SweepObjectiveSharpe
+	}
+	if _, err := sweepObjectiveValue(objective, &BacktestResult{}); err != nil {
+		return nil, err
+	}
+
+	combinations, err := expandGrid(grid)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*SweepResult, len(combinations))
+
+	parallelism := config.SweepParallelism
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for i, params := range combinations {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, params map[string]interface{}) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i] = runSweepCombination(parameterized, params, config, historicalData, objective)
+		}(i, params)
+	}
+	wg.Wait()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Objective > results[j].Objective
+	})
+
+	return results, nil
+}
+
+// runSweepCombination runs a single backtest on an isolated engine
+func runSweepCombination(base ParameterizedStrategy, params map[string]interface{}, config BacktestConfig, historicalData []*HistoricalData, objective string) *SweepResult {
+	sweepResult := &SweepResult{
+		Parameters: params,
+		Objective:  math.Inf(-1),
+	}
+
+	s, err := base.WithParameters(params)
+	if err != nil {
+		sweepResult.Error = err.Error()
+		return sweepResult
+	}
+
+	engine := NewEngine()
+	defer engine.Stop()
+
+	if err := engine.SetConfig(config); err != nil {
+		sweepResult.Error = err.Error()
+		return sweepResult
+	}
+	for _, data := range historicalData {
+		engine.AddHistoricalData(data)
+	}
+	engine.RegisterStrategy(s)
+
+	result, err := engine.RunBacktestWithStrategy(s.GetID())
+	if err != nil {
+		sweepResult.Error = err.Error()
+		return sweepResult
+	}
+
+	sweepResult.Result = result
+	sweepResult.Objective, _ = sweepObjectiveValue(objective, result)
+	return sweepResult
+}
+
+// sweepObjectiveValue extracts the objective from a backtest result
+func sweepObjectiveValue(objective string, result *BacktestResult) (float64, error) {
+	switch objective {
+	case SweepObjectiveSharpe:
+		return result.SharpeRatio.InexactFloat64(), nil
+	case SweepObjectiveReturn:
+		return result.TotalReturnPct.InexactFloat64(), nil
+	case SweepObjectiveWinRate:
+		return result.WinRate.InexactFloat64(), nil
+	default:
+		return 0, fmt.Errorf("unknown sweep objective: %s", objective)
+	}
+}
+
+// expandGrid returns the cartesian product of the parameter grid. A parameter
+// with no values would leave no combinations to run, so it is an error.
+func expandGrid(grid map[string][]interface{}) ([]map[string]interface{}, error) {
+	keys := make([]string, 0, len(grid))
+	for key, values := range grid {
+		if len(values) == 0 {
+			return nil, fmt.Errorf("parameter %s has no values", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	combinations := []map[string]interface{}{{}}
+	for _, key := range keys {
+		next := make([]map[string]interface{}, 0, len(combinations)*len(grid[key]))
+		for _, combination := range combinations {
+			for _, value := range grid[key] {
+				params := make(map[string]interface{}, len(combination)+1)
+				for k, v := range combination {
+					params[k] = v
+				}
+				params[key] = value
+				next = append(next, params)
+			}
+		}
+		combinations = next
+	}
+
+	return combinations, nil
+}
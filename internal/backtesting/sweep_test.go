@@ -0,0 +1,71 @@
+package backtesting
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunSweep tests a 2x2 grid produces four ranked results
+func TestRunSweep(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(start, 30)
+	config.SweepObjective = SweepObjectiveReturn
+	config.SweepParallelism = 2
+	config.CheckpointPath = filepath.Join(t.TempDir(), "checkpoint.json")
+	config.CheckpointInterval = 1
+
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(config))
+	defer engine.Stop()
+	require.NoError(t, engine.AddHistoricalData(trendingData(start, 30, 100, 1)))
+	require.NoError(t, engine.RegisterStrategy(newTestStrategy()))
+
+	results, err := engine.RunSweep("test", map[string][]interface{}{
+		"side":     {"BUY", "SELL"},
+		"quantity": {1, 2},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	for _, result := range results {
+		assert.Empty(t, result.Error)
+		assert.NotNil(t, result.Result)
+	}
+
+	// Rising prices reward the largest long position
+	assert.Equal(t, "BUY", results[0].Parameters["side"])
+	assert.Equal(t, 2, results[0].Parameters["quantity"])
+	assert.Greater(t, results[0].Objective, results[1].Objective)
+
+	// And punish the largest short
+	assert.Equal(t, "SELL", results[3].Parameters["side"])
+	assert.Equal(t, 2, results[3].Parameters["quantity"])
+
+	for i := 1; i < len(results); i++ {
+		assert.GreaterOrEqual(t, results[i-1].Objective, results[i].Objective)
+	}
+
+	// Combinations are not checkpointed
+	assert.NoFileExists(t, config.CheckpointPath)
+}
+
+// TestRunSweepErrors tests sweep argument validation
+func TestRunSweepErrors(t *testing.T) {
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(testConfig(time.Now(), 1)))
+	defer engine.Stop()
+	require.NoError(t, engine.RegisterStrategy(newTestStrategy()))
+
+	_, err := engine.RunSweep("missing", map[string][]interface{}{"side": {"BUY"}})
+	assert.Error(t, err)
+
+	_, err = engine.RunSweep("test", nil)
+	assert.Error(t, err)
+
+	_, err = engine.RunSweep("test", map[string][]interface{}{"side": {}})
+	assert.Error(t, err)
+}
@@ -13,7 +13,9 @@ type BacktestConfig struct {
 	StartDate        time.Time     `json:"start_date"`
 	EndDate          time.Time     `json:"end_date"`
 	InitialCapital   decimal.Decimal `json:"initial_capital"`
+	InitialPositions []InitialPosition `json:"initial_positions,omitempty"` // Open positions the run starts with, held alongside InitialCapital as cash
 	Commission       decimal.Decimal `json:"commission"` // Per trade commission
+	MakerCommission  *decimal.Decimal `json:"maker_commission,omitempty"` // Commission on resting limit fills under the queue model, negative for a rebate; unset charges Commission
 	Slippage         decimal.Decimal `json:"slippage"`   // Slippage percentage
 	Latency          time.Duration `json:"latency"`     // Simulated latency
 	DataFrequency    time.Duration `json:"data_frequency"` // Data update frequency
@@ -22,6 +24,20 @@ type BacktestConfig struct {
 	Symbols          []string      `json:"symbols"`
 	Exchanges        []string      `json:"exchanges"`
 	StrategyConfig   map[string]interface{} `json:"strategy_config"`
+	SweepObjective   string        `json:"sweep_objective"`   // Objective used to rank sweep results
+	SweepParallelism int           `json:"sweep_parallelism"` // Max concurrent sweep runs
+	CostBucketSize   time.Duration `json:"cost_bucket_size"`  // Time bucket for the cost breakdown
+	DrawdownLimit    decimal.Decimal `json:"drawdown_limit"`   // Fraction below peak equity that fires the drawdown hook; zero disables
+	AbortOnDrawdown  bool          `json:"abort_on_drawdown"` // Stop the run when the drawdown limit is breached
+	QueueModel       bool          `json:"queue_model"`       // Rest non-marketable limit orders until volume trades through their queue position
+	FastMode         bool          `json:"fast_mode"`         // Model latency on a simulated clock instead of sleeping
+	WarmupPeriod     time.Duration `json:"warmup_period"`     // History before StartDate replayed to strategies but excluded from results
+	CheckpointPath   string        `json:"checkpoint_path"`     // File the run state is checkpointed to; empty disables checkpointing
+	CheckpointInterval int         `json:"checkpoint_interval"` // Ticks between checkpoints; zero disables checkpointing
+	Benchmark        bool          `json:"benchmark"`         // Also run buy-and-hold over the same data for comparison
+	SignalWindow     time.Duration `json:"signal_window"`     // Net signals per exchange and symbol over this window before executing; zero executes each signal
+	ResampleFrequency time.Duration `json:"resample_frequency"` // Aggregate historical data into OHLCV bars of this length before the run; zero uses the data as loaded
+	ConfidenceGate   strategy.ConfidenceGateConfig `json:"confidence_gate"` // Ignore signals below a minimum confidence, per strategy
 }
 
 // DefaultBacktestConfig returns default backtesting configuration
@@ -39,18 +55,22 @@ func DefaultBacktestConfig() BacktestConfig {
 		Symbols:          []string{"BTC/USD", "ETH/USD"},
 		Exchanges:        []string{"binance", "coinbase"},
 		StrategyConfig:   make(map[string]interface{}),
+		SweepObjective:   "sharpe",
+		SweepParallelism: 4,
+		CostBucketSize:   24 * time.Hour,
 	}
 }
 
 // BacktestResult represents the results of a backtest
 type BacktestResult struct {
+	ID               string             `json:"id"`
 	Config           BacktestConfig     `json:"config"`
 	StartTime        time.Time          `json:"start_time"`
 	EndTime          time.Time          `json:"end_time"`
 	Duration         time.Duration      `json:"duration"`
 	
 	// Portfolio metrics
-	InitialCapital   decimal.Decimal    `json:"initial_capital"`
+	InitialCapital   decimal.Decimal    `json:"initial_capital"` // Opening portfolio value, including any initial positions
 	FinalCapital     decimal.Decimal    `json:"final_capital"`
 	TotalReturn      decimal.Decimal    `json:"total_return"`
 	TotalReturnPct   decimal.Decimal    `json:"total_return_pct"`
@@ -69,9 +89,11 @@ type BacktestResult struct {
 	MaxDrawdownPct   decimal.Decimal    `json:"max_drawdown_pct"`
 	Volatility       decimal.Decimal    `json:"volatility"`
 	
-	// Risk metrics
+	// Risk metrics, as loss fractions of portfolio value per data period
 	VaR95            decimal.Decimal    `json:"var_95"`
 	VaR99            decimal.Decimal    `json:"var_99"`
+	CVaR95           decimal.Decimal    `json:"cvar_95"` // Average loss beyond the 95% VaR
+	CVaR99           decimal.Decimal    `json:"cvar_99"` // Average loss beyond the 99% VaR
 	Beta             decimal.Decimal    `json:"beta"`
 	Alpha            decimal.Decimal    `json:"alpha"`
 	
@@ -79,6 +101,8 @@ type BacktestResult struct {
 	TotalCommission  decimal.Decimal    `json:"total_commission"`
 	TotalSlippage    decimal.Decimal    `json:"total_slippage"`
 	AvgExecutionTime time.Duration      `json:"avg_execution_time"`
+	Latency          LatencyStats       `json:"latency"` // Modeled latency, identical in fast and real-time modes
+	Costs            *CostBreakdown     `json:"costs"`
 	
 	// Detailed data
 	Trades           []*BacktestTrade   `json:"trades"`
@@ -87,8 +111,46 @@ type BacktestResult struct {
 	
 	// Strategy-specific metrics
 	StrategyMetrics  map[string]interface{} `json:"strategy_metrics"`
+	
+	// Drawdown monitoring
+	DrawdownBreaches []*DrawdownEvent   `json:"drawdown_breaches,omitempty"`
+	Aborted          bool               `json:"aborted"`
+	AbortReason      string             `json:"abort_reason,omitempty"`
+	
+	// Limit orders still resting in the queue model when the run ended
+	OpenLimitOrders  int                `json:"open_limit_orders"`
+	
+	// Ticks of pre-start history replayed to the strategy during warmup
+	WarmupTicks      int                `json:"warmup_ticks"`
+	
+	// Buy-and-hold over the same data, when Benchmark is configured
+	Benchmark        *BenchmarkResult   `json:"benchmark,omitempty"`
+	
+	// Time spent in each phase of the run, to find slow phases
+	Phases           map[string]time.Duration `json:"phases"`
+}
+
+// LatencyStats summarises the latency modeled during a run
+type LatencyStats struct {
+	Samples int           `json:"samples"`
+	Total   time.Duration `json:"total"`
+	Mean    time.Duration `json:"mean"`
+	Max     time.Duration `json:"max"`
+}
+
+// DrawdownEvent describes equity falling through the configured drawdown limit
+type DrawdownEvent struct {
+	StrategyID string          `json:"strategy_id"`
+	Timestamp  time.Time       `json:"timestamp"`
+	PeakEquity decimal.Decimal `json:"peak_equity"`
+	Equity     decimal.Decimal `json:"equity"`
+	Drawdown   decimal.Decimal `json:"drawdown"` // Fraction below peak equity
+	Limit      decimal.Decimal `json:"limit"`
 }
 
+// DrawdownHandler is called during a run each time drawdown crosses the configured limit
+type DrawdownHandler func(event *DrawdownEvent)
+
 // BacktestTrade represents a trade executed during backtesting
 type BacktestTrade struct {
 	ID              string          `json:"id"`
@@ -103,13 +165,35 @@ type BacktestTrade struct {
 	Duration        time.Duration   `json:"duration"`
 	PnL             decimal.Decimal `json:"pnl"`
 	PnLPct          decimal.Decimal `json:"pnl_pct"`
-	Commission      decimal.Decimal `json:"commission"`
+	Commission      decimal.Decimal `json:"commission"` // Negative for a maker rebate
 	Slippage        decimal.Decimal `json:"slippage"`
+	Maker           bool            `json:"maker"` // Filled resting in the book rather than on arrival
 	StrategyID      string          `json:"strategy_id"`
 	StrategyName    string          `json:"strategy_name"`
 	Metadata        map[string]interface{} `json:"metadata"`
 }
 
+// CostBreakdown distributes execution costs across symbols and over time
+type CostBreakdown struct {
+	BySymbol          map[string]*CostBucket `json:"by_symbol"`
+	ByPeriod          []*CostBucket          `json:"by_period"`
+	BucketSize        time.Duration          `json:"bucket_size"`
+	CommissionDragBps decimal.Decimal        `json:"commission_drag_bps"` // Commission as bps of initial capital
+	SlippageDragBps   decimal.Decimal        `json:"slippage_drag_bps"`   // Slippage as bps of initial capital
+	TotalDragBps      decimal.Decimal        `json:"total_drag_bps"`
+}
+
+// CostBucket aggregates execution costs for a symbol or time period
+type CostBucket struct {
+	Symbol     string          `json:"symbol,omitempty"`
+	Start      time.Time       `json:"start,omitempty"`
+	Trades     int             `json:"trades"`
+	Notional   decimal.Decimal `json:"notional"`
+	Commission decimal.Decimal `json:"commission"`
+	Slippage   decimal.Decimal `json:"slippage"`
+	DragBps    decimal.Decimal `json:"drag_bps"`
+}
+
 // PortfolioSnapshot represents a snapshot of the portfolio at a point in time
 type PortfolioSnapshot struct {
 	Timestamp       time.Time       `json:"timestamp"`
@@ -167,6 +251,13 @@ type BacktestEngine interface {
 	// Execution
 	RunBacktest() (*BacktestResult, error)
 	RunBacktestWithStrategy(strategyID string) (*BacktestResult, error)
+	ResumeBacktest(checkpointPath string) (*BacktestResult, error)
+	
+	// Stored results
+	GetResult(id string) (*BacktestResult, error)
+	
+	// Progress of the current or last run
+	Progress() BacktestProgress
 	
 	// Analysis
 	AnalyzeResult(result *BacktestResult) (*BacktestAnalysis, error)
@@ -274,8 +365,10 @@ type BacktestReport struct {
 	Analysis            *BacktestAnalysis `json:"analysis"`
 	Charts              map[string]interface{} `json:"charts"`
 	Recommendations     []string         `json:"recommendations"`
+	Costs               *CostBreakdown   `json:"costs,omitempty"`
 	GeneratedAt         time.Time        `json:"generated_at"`
 	ReportVersion       string           `json:"report_version"`
+	Benchmark           *BenchmarkResult `json:"benchmark,omitempty"`
 }
 
 // BacktestSummary represents a summary of backtest results
@@ -292,4 +385,6 @@ type BacktestSummary struct {
 	WinRate             decimal.Decimal `json:"win_rate"`
 	ProfitFactor        decimal.Decimal `json:"profit_factor"`
 	RiskAdjustedReturn  decimal.Decimal `json:"risk_adjusted_return"`
+	BenchmarkReturnPct  decimal.Decimal `json:"benchmark_return_pct"` // Buy-and-hold return, when benchmarked
+	Outperformance      decimal.Decimal `json:"outperformance"`       // Return minus the benchmark's, in percentage points
 }
@@ -0,0 +1,57 @@
+package backtesting
+
+import (
+	"log"
+	"sort"
+
+	"velocimex/internal/strategy"
+)
+
+// warmupUpdate is a single historical data point replayed during warmup
+type warmupUpdate struct {
+	symbol   string
+	exchange string
+	point    *DataPoint
+}
+
+// runWarmup replays the data in the WarmupPeriod before StartDate so a strategy
+// can build indicator state before the run starts. Signals generated during
+// warmup are discarded and nothing is booked. It returns the number of ticks replayed.
+func (e *Engine) runWarmup(strategy strategy.Strategy) int {
+	if e.config.WarmupPeriod <= 0 {
+		return 0
+	}
+	from := e.config.StartDate.Add(-e.config.WarmupPeriod)
+
+	var updates []warmupUpdate
+	for symbol, exchanges := range e.historicalData {
+		for exchange, data := range exchanges {
+			for _, point := range data.DataPoints {
+				if !point.Timestamp.Before(from) && point.Timestamp.Before(e.config.StartDate) {
+					updates = append(updates, warmupUpdate{symbol: symbol, exchange: exchange, point: point})
+				}
+			}
+		}
+	}
+	sort.SliceStable(updates, func(i, j int) bool {
+		return updates[i].point.Timestamp.Before(updates[j].point.Timestamp)
+	})
+
+	// Points sharing a timestamp update every book before the strategy sees them
+	ticks := 0
+	for i := 0; i < len(updates); {
+		timestamp := updates[i].point.Timestamp
+		for ; i < len(updates) && updates[i].point.Timestamp.Equal(timestamp); i++ {
+			e.applyDataPoint(updates[i].exchange, updates[i].symbol, updates[i].point)
+		}
+
+		e.currentTime = timestamp
+		if _, err := strategy.GenerateSignals(e.currentOrderBooks()); err != nil {
+			log.Printf("Error running strategy during warmup: %v", err)
+		}
+		ticks++
+	}
+
+	e.currentTime = e.config.StartDate
+	return ticks
+}
@@ -0,0 +1,128 @@
+package backtesting
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/orderbook"
+	"velocimex/internal/strategy"
+)
+
+// momentumStrategy buys once the price is above its moving average, which
+// needs lookback observations before it can signal
+type momentumStrategy struct {
+	lookback  int
+	prices    []float64
+	signalled bool
+}
+
+func (s *momentumStrategy) GetID() string                   { return "momentum" }
+func (s *momentumStrategy) GetName() string                 { return "momentum" }
+func (s *momentumStrategy) Start(ctx context.Context) error { return nil }
+func (s *momentumStrategy) Stop() error                     { return nil }
+func (s *momentumStrategy) IsRunning() bool                 { return false }
+
+func (s *momentumStrategy) GetResults() strategy.StrategyResults {
+	return strategy.StrategyResults{Name: "momentum"}
+}
+
+func (s *momentumStrategy) WithParameters(params map[string]interface{}) (strategy.Strategy, error) {
+	return &momentumStrategy{lookback: s.lookback}, nil
+}
+
+func (s *momentumStrategy) GenerateSignals(orderBooks map[string]*orderbook.OrderBook) ([]*strategy.Signal, error) {
+	book, ok := orderBooks[fmt.Sprintf("%s:%s", "test", "BTC/USD")]
+	if !ok {
+		return nil, nil
+	}
+	ask := book.GetBestAsk()
+	if ask == nil {
+		return nil, nil
+	}
+
+	ready := len(s.prices) >= s.lookback
+	var mean float64
+	if ready {
+		for _, price := range s.prices[len(s.prices)-s.lookback:] {
+			mean += price
+		}
+		mean /= float64(s.lookback)
+	}
+	s.prices = append(s.prices, ask.Price)
+
+	if s.signalled || !ready || ask.Price <= mean {
+		return nil, nil
+	}
+	s.signalled = true
+	return []*strategy.Signal{{
+		Symbol:   "BTC/USD",
+		Exchange: "test",
+		Side:     "BUY",
+		Quantity: decimal.NewFromInt(1),
+		Price:    decimal.NewFromFloat(ask.Price),
+	}}, nil
+}
+
+// runMomentum runs the momentum strategy over 10 ticks of history before start and 15 after
+func runMomentum(t *testing.T, warmup time.Duration) *BacktestResult {
+	t.Helper()
+
+	start := time.Date(2024, 1, 1, 0, 10, 0, 0, time.UTC)
+	config := testConfig(start, 15)
+	config.WarmupPeriod = warmup
+
+	engine := NewEngine()
+	require.NoError(t, engine.SetConfig(config))
+	t.Cleanup(func() { engine.Stop() })
+
+	require.NoError(t, engine.AddHistoricalData(trendingData(start.Add(-10*time.Minute), 25, 100, 1)))
+	require.NoError(t, engine.RegisterStrategy(&momentumStrategy{lookback: 10}))
+
+	result, err := engine.RunBacktestWithStrategy("momentum")
+	require.NoError(t, err)
+	return result
+}
+
+// TestWarmupPreloadsHistory tests that signals at StartDate reflect preloaded history
+func TestWarmupPreloadsHistory(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 10, 0, 0, time.UTC)
+
+	result := runMomentum(t, 10*time.Minute)
+
+	assert.Equal(t, 10, result.WarmupTicks)
+	require.Len(t, result.Trades, 1)
+	assert.Equal(t, start, result.Trades[0].EntryTime)
+	assert.True(t, result.Trades[0].EntryPrice.Equal(decimal.NewFromInt(110)))
+
+	// Warmup ticks are not part of the results
+	require.Len(t, result.PortfolioHistory, 15)
+	assert.Equal(t, start, result.PortfolioHistory[0].Timestamp)
+}
+
+// TestWarmupWindowLimitsHistory tests that only data inside the warmup window is replayed
+func TestWarmupWindowLimitsHistory(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 10, 0, 0, time.UTC)
+
+	// Three ticks of history are not enough for a ten tick lookback
+	result := runMomentum(t, 3*time.Minute)
+
+	assert.Equal(t, 3, result.WarmupTicks)
+	require.Len(t, result.Trades, 1)
+	assert.Equal(t, start.Add(7*time.Minute), result.Trades[0].EntryTime)
+}
+
+// TestWithoutWarmupStartsCold tests that without warmup the strategy builds its history inside the run
+func TestWithoutWarmupStartsCold(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 10, 0, 0, time.UTC)
+
+	result := runMomentum(t, 0)
+
+	assert.Zero(t, result.WarmupTicks)
+	require.Len(t, result.Trades, 1)
+	assert.Equal(t, start.Add(10*time.Minute), result.Trades[0].EntryTime)
+}
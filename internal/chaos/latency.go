@@ -0,0 +1,96 @@
+package chaos
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// LatencyConfig configures artificial latency added to live feed and order
+// processing, to test how strategies behave when markets or venues are slow.
+// It is independent of the backtest latency model.
+type LatencyConfig struct {
+	Enabled      bool          `json:"enabled" yaml:"enabled"`
+	FeedLatency  time.Duration `json:"feed_latency" yaml:"feedLatency"`   // Added before each order book update from a feed
+	OrderLatency time.Duration `json:"order_latency" yaml:"orderLatency"` // Added before each order is submitted
+	Jitter       time.Duration `json:"jitter" yaml:"jitter"`              // Up to this much is added at random on top
+	AllowAPI     bool          `json:"allow_api" yaml:"allowApi"`         // Expose the toggle on the REST API
+}
+
+// DefaultLatencyConfig returns default latency injection configuration
+func DefaultLatencyConfig() LatencyConfig {
+	return LatencyConfig{
+		Enabled: false,
+	}
+}
+
+// Validate checks that no latency is negative
+func (c LatencyConfig) Validate() error {
+	if c.FeedLatency < 0 || c.OrderLatency < 0 || c.Jitter < 0 {
+		return fmt.Errorf("injected latency cannot be negative")
+	}
+	return nil
+}
+
+// LatencyInjector adds configurable latency to the feed and order paths.
+// It can be reconfigured while running, e.g. from a chaos test.
+type LatencyInjector struct {
+	config LatencyConfig
+	rand   *rand.Rand
+	mu     sync.Mutex
+}
+
+// NewLatencyInjector creates an injector with the given configuration
+func NewLatencyInjector(config LatencyConfig) *LatencyInjector {
+	return &LatencyInjector{
+		config: config,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Config returns the current configuration
+func (l *LatencyInjector) Config() LatencyConfig {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.config
+}
+
+// Set replaces the configuration. AllowAPI is kept, so the API cannot revoke
+// or grant its own access.
+func (l *LatencyInjector) Set(config LatencyConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	config.AllowAPI = l.config.AllowAPI
+	l.config = config
+	return nil
+}
+
+// FeedLatency returns the latency to add to the next feed update, zero when disabled
+func (l *LatencyInjector) FeedLatency() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.latency(l.config.FeedLatency)
+}
+
+// OrderLatency returns the latency to add to the next order, zero when disabled
+func (l *LatencyInjector) OrderLatency() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.latency(l.config.OrderLatency)
+}
+
+// latency adds jitter to a base latency. The caller holds l.mu.
+func (l *LatencyInjector) latency(base time.Duration) time.Duration {
+	if !l.config.Enabled {
+		return 0
+	}
+	if l.config.Jitter > 0 {
+		base += time.Duration(l.rand.Int63n(int64(l.config.Jitter) + 1))
+	}
+	return base
+}
@@ -0,0 +1,42 @@
+package chaos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLatencyInjector tests that latency is only injected while enabled and
+// that jitter stays within its bound
+func TestLatencyInjector(t *testing.T) {
+	injector := NewLatencyInjector(LatencyConfig{
+		FeedLatency:  20 * time.Millisecond,
+		OrderLatency: 50 * time.Millisecond,
+		AllowAPI:     true,
+	})
+	assert.Zero(t, injector.FeedLatency())
+	assert.Zero(t, injector.OrderLatency())
+
+	require.NoError(t, injector.Set(LatencyConfig{
+		Enabled:      true,
+		FeedLatency:  20 * time.Millisecond,
+		OrderLatency: 50 * time.Millisecond,
+		Jitter:       10 * time.Millisecond,
+	}))
+	assert.True(t, injector.Config().AllowAPI, "Set must not change API access")
+
+	for i := 0; i < 100; i++ {
+		feed := injector.FeedLatency()
+		assert.GreaterOrEqual(t, feed, 20*time.Millisecond)
+		assert.LessOrEqual(t, feed, 30*time.Millisecond)
+
+		order := injector.OrderLatency()
+		assert.GreaterOrEqual(t, order, 50*time.Millisecond)
+		assert.LessOrEqual(t, order, 60*time.Millisecond)
+	}
+
+	assert.Error(t, injector.Set(LatencyConfig{Enabled: true, OrderLatency: -time.Millisecond}))
+	assert.Equal(t, 20*time.Millisecond, injector.Config().FeedLatency, "invalid config must not be applied")
+}
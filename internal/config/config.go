@@ -1,15 +1,27 @@
 package config
 
 import (
+	"fmt"
 	"io/ioutil"
+	"net/url"
 	"time"
 
 	"gopkg.in/yaml.v2"
 	
+	"velocimex/internal/alerts"
 	"velocimex/internal/backtesting"
+	"velocimex/internal/chaos"
 	"velocimex/internal/fix"
+	"velocimex/internal/instruments"
+	"velocimex/internal/metrics"
+	"velocimex/internal/normalizer"
+	"velocimex/internal/numeric"
+	"velocimex/internal/orderbook"
+	"velocimex/internal/orders"
 	"velocimex/internal/plugins"
+	"velocimex/internal/reports"
 	"velocimex/internal/risk"
+	"velocimex/internal/security"
 	"velocimex/internal/strategy"
 )
 
@@ -17,6 +29,7 @@ import (
 type Config struct {
 	Server      ServerConfig           `yaml:"server"`
 	Feeds       []FeedConfig           `yaml:"feeds"`
+	FeedHealth  FeedHealthConfig       `yaml:"feedHealth"`
 	FIX         fix.Config             `yaml:"fix"`
 	Risk        risk.RiskConfig        `yaml:"risk"`
 	Backtesting backtesting.BacktestConfig `yaml:"backtesting"`
@@ -24,6 +37,74 @@ type Config struct {
 	Metrics     MetricsConfig          `yaml:"metrics"`
 	Strategies  StrategiesConfig       `yaml:"strategies"`
 	Simulation  SimulationConfig       `yaml:"simulation"`
+	API         APIConfig              `yaml:"api"`
+	FeedValidation *normalizer.ValidationConfig `yaml:"feedValidation"` // Nil when absent, keeping the normalizer defaults
+	Heartbeat   orders.HeartbeatConfig `yaml:"heartbeat"`
+	TCA         orders.TCAConfig       `yaml:"tca"`
+	StaleBook   orders.StaleBookConfig `yaml:"staleBook"`
+	CircuitBreaker orders.CircuitBreakerConfig `yaml:"circuitBreaker"`
+	// DailyOrderLimit caps the orders submitted per trading day, globally and per strategy
+	DailyOrderLimit orders.DailyOrderLimitConfig `yaml:"dailyOrderLimit"`
+	// SymbolLimits bounds the quantity and notional of orders, keyed by canonical symbol
+	SymbolLimits map[string]orders.SymbolLimits `yaml:"symbolLimits"`
+	// MaxSlippageBps rejects market order fills this far past the expected price, unless the order sets its own
+	MaxSlippageBps float64 `yaml:"maxSlippageBps"`
+	// MaxOrderValue rejects any single order whose notional exceeds it, whatever other limits allow
+	MaxOrderValue float64 `yaml:"maxOrderValue"`
+	// AckTimeout cancels orders the exchange has not acknowledged this long after submission
+	AckTimeout time.Duration `yaml:"ackTimeout"`
+	// CancelRemainderOnTimeout keeps the fills of partially filled orders that expire and cancels the rest
+	CancelRemainderOnTimeout bool `yaml:"cancelRemainderOnTimeout"`
+	// ExpirySweepInterval is how often working orders are checked for expiry and ack timeouts missed by their timers
+	ExpirySweepInterval time.Duration `yaml:"expirySweepInterval"`
+	Reports     reports.Config         `yaml:"reports"`
+	// Alerts configures how triggered alerts are delivered
+	Alerts AlertsConfig `yaml:"alerts"`
+	Security    security.SecurityConfig `yaml:"security"`
+	// Decimal sets division precision and the rounding of PnL and metrics
+	Decimal     numeric.PrecisionConfig `yaml:"decimal"`
+	// TopOfBook configures best bid/ask change events
+	TopOfBook orderbook.TopOfBookConfig `yaml:"topOfBook"`
+	// FeedPriority ranks exchanges quoting the same symbol in the consolidated book and routing
+	FeedPriority orderbook.FeedPriorityConfig `yaml:"feedPriority"`
+	// OrderBookSnapshot periodically saves books to disk and restores them on startup
+	OrderBookSnapshot orderbook.SnapshotConfig `yaml:"orderBookSnapshot"`
+	// BookCoalescing batches bursts of book updates to save CPU
+	BookCoalescing orderbook.CoalesceConfig `yaml:"bookCoalescing"`
+	// IndexPrice combines each symbol's price across exchanges
+	IndexPrice orderbook.IndexConfig `yaml:"indexPrice"`
+	// LatencyInjection adds artificial feed and order latency for resilience testing
+	LatencyInjection chaos.LatencyConfig `yaml:"latencyInjection"`
+	// Instruments holds contract specifications keyed by canonical symbol
+	Instruments []instruments.Instrument `yaml:"instruments"`
+	// SymbolMappings maps exchange -> native symbol -> canonical symbol
+	SymbolMappings map[string]map[string]string `yaml:"symbolMappings"`
+}
+
+// APIConfig contains REST and WebSocket API configuration
+type APIConfig struct {
+	Rounding      RoundingConfig      `yaml:"rounding"`
+	WebSocketAuth WebSocketAuthConfig `yaml:"websocketAuth"`
+	// WebSocketMessagePack lets WebSocket clients negotiate MessagePack instead of JSON
+	WebSocketMessagePack bool `yaml:"websocketMessagePack"`
+	// WebSocketTopicRates caps messages per second published on each topic, e.g. orderbook: 10
+	WebSocketTopicRates map[string]float64 `yaml:"websocketTopicRates"`
+	// WebSocketMaxMessageAge drops book updates queued to a client for longer than this once a later one is queued; zero disables it
+	WebSocketMaxMessageAge time.Duration `yaml:"websocketMaxMessageAge"`
+}
+
+// WebSocketAuthConfig requires WebSocket clients to authenticate with the security manager
+type WebSocketAuthConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Timeout time.Duration `yaml:"timeout"` // How long a client has to send its auth message
+}
+
+// RoundingConfig contains display precision for monetary values in API responses
+type RoundingConfig struct {
+	DefaultPlaces int32             `yaml:"defaultPlaces"`
+	Classes       map[string]int32  `yaml:"classes"`
+	QuoteClasses  map[string]string `yaml:"quoteClasses"`
+	Symbols       map[string]string `yaml:"symbols"`
 }
 
 // MetricsConfig contains metrics server configuration
@@ -34,6 +115,10 @@ type MetricsConfig struct {
 	Path        string        `yaml:"path"`
 	Timeout     time.Duration `yaml:"timeout"`
 	EnablePprof bool          `yaml:"enable_pprof"`
+	// QueueInterval is how often internal queue depths are sampled, default 5s
+	QueueInterval time.Duration `yaml:"queueInterval"`
+	// Pushgateway receives the metrics of completed backtests
+	Pushgateway metrics.PushgatewayConfig `yaml:"pushgateway"`
 }
 
 // ServerConfig contains HTTP server configuration
@@ -57,11 +142,36 @@ type FeedConfig struct {
 	Symbols       []string `yaml:"symbols"`
 	APIKey        string   `yaml:"apiKey,omitempty"`
 	APISecret     string   `yaml:"apiSecret,omitempty"`
+	// BackupURL is an optional backup endpoint the feed fails over to when the primary drops
+	BackupURL        string        `yaml:"backupUrl,omitempty"`
+	FailoverInterval time.Duration `yaml:"failoverInterval,omitempty"` // How often connections are checked, default 5s
+}
+
+// FeedHealthConfig configures the feed health report
+type FeedHealthConfig struct {
+	// StaleAfter reports a connected feed stale once no message arrived for this long; zero disables
+	StaleAfter time.Duration `yaml:"staleAfter"`
+	// RateWindow is the window message rates are averaged over, at most a minute; default 10s
+	RateWindow time.Duration `yaml:"rateWindow"`
+	// MaxClockSkew warns once an exchange's clock is further than this from ours, either way; zero disables
+	MaxClockSkew time.Duration `yaml:"maxClockSkew"`
+}
+
+// AlertsConfig configures the delivery of triggered alerts
+type AlertsConfig struct {
+	// QuietHours holds sub-critical alerts during the windows, sending a digest or dropping them
+	QuietHours alerts.QuietHoursConfig `yaml:"quietHours"`
+	// ChannelBreaker skips a failing channel for a cooldown; nil keeps the default breaker
+	ChannelBreaker *alerts.ChannelBreakerConfig `yaml:"channelBreaker"`
 }
 
 // StrategiesConfig contains all strategy configurations
 type StrategiesConfig struct {
-	Arbitrage strategy.ArbitrageConfig `yaml:"arbitrage"`
+	Arbitrage  strategy.ArbitrageConfig  `yaml:"arbitrage"`
+	Rebalance  strategy.RebalanceConfig  `yaml:"rebalance"`
+	Imbalance  strategy.ImbalanceConfig  `yaml:"imbalance"`
+	KillSwitch strategy.KillSwitchConfig `yaml:"killSwitch"`
+	Session    strategy.SessionConfig    `yaml:"session"`
 }
 
 // SimulationConfig contains configuration for simulation and backtesting
@@ -79,6 +189,8 @@ type PaperTradingConfig struct {
 	SlippageModel     string             `yaml:"slippageModel"`
 	FixedSlippage     float64            `yaml:"fixedSlippage"`
 	ExchangeFees      map[string]float64 `yaml:"exchangeFees"`
+	// LimitFills controls when paper limit orders fill against the live books
+	LimitFills orders.PaperFillConfig `yaml:"limitFills"`
 }
 
 // Load loads configuration from a file
@@ -95,4 +207,166 @@ func Load(path string) (*Config, error) {
 	}
 
 	return &config, nil
-}
\ No newline at end of file
+}
+
+// Validate checks the configuration for values that would prevent startup
+func (c *Config) Validate() error {
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		return fmt.Errorf("server port %d out of range", c.Server.Port)
+	}
+
+	if c.Metrics.Enabled && (c.Metrics.Port <= 0 || c.Metrics.Port > 65535) {
+		return fmt.Errorf("metrics port %d out of range", c.Metrics.Port)
+	}
+
+	names := make(map[string]bool, len(c.Feeds))
+	for i, feed := range c.Feeds {
+		if feed.Name == "" {
+			return fmt.Errorf("feed %d has no name", i)
+		}
+		if names[feed.Name] {
+			return fmt.Errorf("duplicate feed name: %s", feed.Name)
+		}
+		names[feed.Name] = true
+
+		if feed.URL == "" {
+			return fmt.Errorf("feed %s has no url", feed.Name)
+		}
+		if _, err := url.Parse(feed.URL); err != nil {
+			return fmt.Errorf("feed %s has invalid url: %w", feed.Name, err)
+		}
+		if feed.BackupURL != "" {
+			if _, err := url.Parse(feed.BackupURL); err != nil {
+				return fmt.Errorf("feed %s has invalid backup url: %w", feed.Name, err)
+			}
+			if feed.BackupURL == feed.URL {
+				return fmt.Errorf("feed %s backup url is the same as its url", feed.Name)
+			}
+		}
+		if feed.FailoverInterval < 0 {
+			return fmt.Errorf("feed %s failover interval must not be negative", feed.Name)
+		}
+	}
+
+	switch c.TCA.Benchmark {
+	case "", orders.BenchmarkArrival, orders.BenchmarkVWAP, orders.BenchmarkClose:
+	default:
+		return fmt.Errorf("unknown tca benchmark: %s", c.TCA.Benchmark)
+	}
+
+	switch c.StaleBook.Action {
+	case "", orders.StaleBookReject, orders.StaleBookWarn:
+	default:
+		return fmt.Errorf("unknown stale book action: %s", c.StaleBook.Action)
+	}
+	if c.StaleBook.MaxAge < 0 {
+		return fmt.Errorf("stale book max age cannot be negative")
+	}
+	if err := c.DailyOrderLimit.Validate(); err != nil {
+		return err
+	}
+	if c.FeedHealth.StaleAfter < 0 || c.FeedHealth.RateWindow < 0 || c.FeedHealth.MaxClockSkew < 0 {
+		return fmt.Errorf("feed health durations cannot be negative")
+	}
+	if c.FeedHealth.RateWindow > time.Minute {
+		return fmt.Errorf("feed health rate window cannot exceed a minute")
+	}
+	if err := c.Alerts.QuietHours.Validate(); err != nil {
+		return err
+	}
+	if breaker := c.Alerts.ChannelBreaker; breaker != nil {
+		if err := breaker.Validate(); err != nil {
+			return err
+		}
+	}
+	for symbol, limits := range c.SymbolLimits {
+		if limits.MinQuantity.IsNegative() || limits.MaxQuantity.IsNegative() || limits.MinNotional.IsNegative() || limits.MaxNotional.IsNegative() {
+			return fmt.Errorf("symbol limits for %s cannot be negative", symbol)
+		}
+		if limits.MaxQuantity.IsPositive() && limits.MinQuantity.GreaterThan(limits.MaxQuantity) {
+			return fmt.Errorf("symbol limits for %s: min quantity above max quantity", symbol)
+		}
+		if limits.MaxNotional.IsPositive() && limits.MinNotional.GreaterThan(limits.MaxNotional) {
+			return fmt.Errorf("symbol limits for %s: min notional above max notional", symbol)
+		}
+	}
+	if c.MaxSlippageBps < 0 {
+		return fmt.Errorf("max slippage cannot be negative")
+	}
+	if c.MaxOrderValue < 0 {
+		return fmt.Errorf("max order value cannot be negative")
+	}
+	if c.AckTimeout < 0 {
+		return fmt.Errorf("ack timeout cannot be negative")
+	}
+	if c.ExpirySweepInterval < 0 {
+		return fmt.Errorf("expiry sweep interval cannot be negative")
+	}
+	for topic, limit := range c.API.WebSocketTopicRates {
+		if limit < 0 {
+			return fmt.Errorf("websocket topic rate for %s cannot be negative", topic)
+		}
+	}
+	if c.API.WebSocketMaxMessageAge < 0 {
+		return fmt.Errorf("websocket max message age cannot be negative")
+	}
+	if err := c.LatencyInjection.Validate(); err != nil {
+		return err
+	}
+	if err := c.Metrics.Pushgateway.Validate(); err != nil {
+		return err
+	}
+	if err := c.BookCoalescing.Validate(); err != nil {
+		return err
+	}
+	if err := c.IndexPrice.Validate(); err != nil {
+		return err
+	}
+	if err := c.OrderBookSnapshot.Validate(); err != nil {
+		return err
+	}
+	if c.Decimal.DivisionPrecision < 0 || c.Decimal.RoundingPlaces < 0 {
+		return fmt.Errorf("decimal precision cannot be negative")
+	}
+	if breaker := c.CircuitBreaker; breaker.Enabled && breaker.MaxMovePercent < 0 {
+		return fmt.Errorf("circuit breaker max move percent cannot be negative")
+	}
+
+	if rebalance := c.Strategies.Rebalance; rebalance.Enabled {
+		if rebalance.Exchange == "" {
+			return fmt.Errorf("rebalance strategy requires an exchange")
+		}
+		total := 0.0
+		for symbol, weight := range rebalance.TargetWeights {
+			if weight < 0 {
+				return fmt.Errorf("rebalance target weight for %s cannot be negative", symbol)
+			}
+			total += weight
+		}
+		if total > 1 {
+			return fmt.Errorf("rebalance target weights sum to %v, more than 1", total)
+		}
+	}
+	if imbalance := c.Strategies.Imbalance; imbalance.Enabled {
+		if err := imbalance.Validate(); err != nil {
+			return err
+		}
+	}
+	if session := c.Strategies.Session; session.Enabled {
+		if err := session.Validate(); err != nil {
+			return err
+		}
+	}
+
+	fills := c.Simulation.PaperTrading.LimitFills
+	switch fills.Model {
+	case "", orders.PaperFillImmediate, orders.PaperFillMarket:
+	default:
+		return fmt.Errorf("unknown paper fill model: %s", fills.Model)
+	}
+	if fills.TouchProbability < 0 || fills.TouchProbability > 1 {
+		return fmt.Errorf("paper fill touch probability %v out of range [0, 1]", fills.TouchProbability)
+	}
+
+	return nil
+}
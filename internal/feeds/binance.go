@@ -11,6 +11,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/shopspring/decimal"
 	"velocimex/internal/config"
+	"velocimex/internal/metrics"
 	"velocimex/internal/normalizer"
 )
 
@@ -21,8 +22,10 @@ type BinanceWebSocketFeed struct {
 	conn       *websocket.Conn
 	isConnected bool
 	mu         sync.Mutex
-	done       chan struct{}
+	done       chan struct{} // Closed by Disconnect to stop reading and reconnecting
 	orderBookManager OrderBookManager
+	reader     *readSupervisor
+	clock      *ClockSkewMonitor
 }
 
 // BinanceDepthUpdate represents Binance depth update message
@@ -50,6 +53,7 @@ func NewBinanceWebSocketFeed(config config.FeedConfig, norm *normalizer.Normaliz
 		config:     config,
 		normalizer: norm,
 		done:       make(chan struct{}),
+		reader:     newReadSupervisor(config.Name),
 	}, nil
 }
 
@@ -58,11 +62,46 @@ func (f *BinanceWebSocketFeed) SetOrderBookManager(manager OrderBookManager) {
 	f.orderBookManager = manager
 }
 
+// SetClockSkewMonitor sets where the timestamps on messages are reported
+func (f *BinanceWebSocketFeed) SetClockSkewMonitor(clock *ClockSkewMonitor) {
+	f.clock = clock
+}
+
+// SetMetrics sets where read errors and reconnects are recorded
+func (f *BinanceWebSocketFeed) SetMetrics(metrics *metrics.Wrapper) {
+	f.reader.setMetrics(metrics)
+}
+
+// stats returns the message flow the feed has seen
+func (f *BinanceWebSocketFeed) stats(now time.Time, rateWindow time.Duration) FeedStats {
+	return f.reader.stats.snapshot(now, rateWindow)
+}
+
 // Connect establishes a connection to Binance WebSocket
 func (f *BinanceWebSocketFeed) Connect() error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
+	if f.done == nil {
+		f.done = make(chan struct{})
+	}
+	return f.connect(f.done)
+}
+
+// reconnect re-establishes a connection lost to a read error, unless the feed
+// was disconnected while waiting
+func (f *BinanceWebSocketFeed) reconnect(done chan struct{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.done != done {
+		return errFeedDisconnected
+	}
+	return f.connect(done)
+}
+
+// connect dials the WebSocket and starts reading. The caller holds f.mu.
+func (f *BinanceWebSocketFeed) connect(done chan struct{}) error {
 	if f.isConnected {
 		return nil
 	}
@@ -88,7 +127,9 @@ func (f *BinanceWebSocketFeed) Connect() error {
 	f.isConnected = true
 
 	// Start message processing
-	go f.processMessages()
+	f.reader.start(conn, done, f.handleMessage, f.handleDisconnection, func() error {
+		return f.reconnect(done)
+	})
 
 	log.Printf("Connected to Binance WebSocket feed: %s", f.config.Name)
 	return nil
@@ -97,13 +138,14 @@ func (f *BinanceWebSocketFeed) Connect() error {
 // Disconnect closes the WebSocket connection
 func (f *BinanceWebSocketFeed) Disconnect() error {
 	f.mu.Lock()
-	defer f.mu.Unlock()
-
-	if !f.isConnected {
+	if f.done == nil {
+		f.mu.Unlock()
 		return nil
 	}
 
+	// Closing done also stops a pending reconnect
 	close(f.done)
+	f.done = nil
 
 	if f.conn != nil {
 		f.conn.Close()
@@ -111,6 +153,10 @@ func (f *BinanceWebSocketFeed) Disconnect() error {
 	}
 
 	f.isConnected = false
+	f.mu.Unlock()
+
+	// Wait for the read loop to exit; it needs the lock to drop its connection
+	f.reader.wait()
 	log.Printf("Disconnected from Binance WebSocket feed: %s", f.config.Name)
 	return nil
 }
@@ -136,31 +182,6 @@ func (f *BinanceWebSocketFeed) IsConnected() bool {
 	return f.isConnected
 }
 
-// processMessages processes incoming WebSocket messages
-func (f *BinanceWebSocketFeed) processMessages() {
-	defer func() {
-		if r := recover(); r != nil {
-			log.Printf("Binance WebSocket panic recovered: %v", r)
-		}
-	}()
-
-	for {
-		select {
-		case <-f.done:
-			return
-		default:
-			_, message, err := f.conn.ReadMessage()
-			if err != nil {
-				log.Printf("Binance WebSocket read error: %v", err)
-				f.handleDisconnection()
-				return
-			}
-
-			f.handleMessage(message)
-		}
-	}
-}
-
 // handleMessage processes a single WebSocket message
 func (f *BinanceWebSocketFeed) handleMessage(message []byte) {
 	var update BinanceDepthUpdate
@@ -168,6 +189,9 @@ func (f *BinanceWebSocketFeed) handleMessage(message []byte) {
 		log.Printf("Failed to unmarshal Binance message: %v", err)
 		return
 	}
+	if f.clock != nil && update.Data.EventTime > 0 {
+		f.clock.Observe("binance", time.UnixMilli(update.Data.EventTime), time.Now())
+	}
 
 	// Convert Binance data to normalized format
 	bids := f.convertPriceLevels(update.Data.Bids)
@@ -176,12 +200,6 @@ func (f *BinanceWebSocketFeed) handleMessage(message []byte) {
 	// Normalize symbol
 	normalizedSymbol := f.normalizer.NormalizeSymbol("binance", update.Data.Symbol)
 
-	// Update order book if manager is available
-	if f.orderBookManager != nil {
-		f.orderBookManager.UpdateOrderBook("binance", normalizedSymbol, bids, asks)
-	}
-
-	// Process through normalizer
 	orderBookUpdate := &normalizer.OrderBookUpdate{
 		Exchange:  "binance",
 		Symbol:    normalizedSymbol,
@@ -189,8 +207,21 @@ func (f *BinanceWebSocketFeed) handleMessage(message []byte) {
 		Asks:      asks,
 		Timestamp: time.Unix(0, update.Data.EventTime*int64(time.Millisecond)),
 		Snapshot:  false,
+		Sequence:  update.Data.FinalUpdateID,
+	}
+
+	// Drop malformed or stale updates before they reach the book
+	if err := f.normalizer.ValidateOrderBookUpdate(orderBookUpdate); err != nil {
+		log.Printf("Rejected Binance order book update: %v", err)
+		return
+	}
+
+	// Update order book if manager is available
+	if f.orderBookManager != nil {
+		f.orderBookManager.UpdateOrderBook("binance", normalizedSymbol, orderBookUpdate.Bids, orderBookUpdate.Asks)
 	}
 
+	// Process through normalizer
 	f.normalizer.ProcessOrderBookUpdate(orderBookUpdate)
 }
 
@@ -229,22 +260,17 @@ func (f *BinanceWebSocketFeed) convertPriceLevels(levels [][]string) []normalize
 	return result
 }
 
-// handleDisconnection handles WebSocket disconnection
-func (f *BinanceWebSocketFeed) handleDisconnection() {
+// handleDisconnection drops a connection that failed to read. Reconnecting
+// is left to the read supervisor.
+func (f *BinanceWebSocketFeed) handleDisconnection(conn *websocket.Conn) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	f.isConnected = false
-	if f.conn != nil {
-		f.conn.Close()
-		f.conn = nil
+	conn.Close()
+	if f.conn != conn {
+		// Already replaced or disconnected
+		return
 	}
-
-	// Attempt to reconnect after a delay
-	go func() {
-		time.Sleep(5 * time.Second)
-		if err := f.Connect(); err != nil {
-			log.Printf("Failed to reconnect to Binance: %v", err)
-		}
-	}()
+	f.conn = nil
+	f.isConnected = false
 }
@@ -0,0 +1,23 @@
+package feeds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBinanceDuplicateUpdates tests that a depth update the exchange resends
+// is applied to the book once
+func TestBinanceDuplicateUpdates(t *testing.T) {
+	books := &countingBooks{}
+	feed := newTestBinanceFeed(t, "ws://localhost:0", books)
+
+	first, second := depthMessage(), depthMessage()
+	feed.handleMessage(first)
+	feed.handleMessage(first)
+	feed.handleMessage(second)
+	feed.handleMessage(first)
+
+	assert.Equal(t, int32(2), books.updates.Load())
+	assert.Equal(t, uint64(2), feed.normalizer.Rejects()["duplicate"])
+}
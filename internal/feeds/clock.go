@@ -0,0 +1,190 @@
+package feeds
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"velocimex/internal/metrics"
+)
+
+// clockSkewSamples is how many of an exchange's latest messages its skew is
+// the median of, so a single delayed message does not move it
+const clockSkewSamples = 50
+
+// ClockSkew is how far the local clock is ahead of an exchange's, measured as
+// the time a message was received less the exchange's timestamp on it. It
+// includes the network latency, so a well synchronized clock still shows a
+// small positive skew; a negative one means the local clock is behind.
+type ClockSkew struct {
+	Exchange   string        `json:"exchange"`
+	Skew       time.Duration `json:"skew"` // Median over the latest messages
+	Samples    int           `json:"samples"`
+	Exceeded   bool          `json:"exceeded"` // Whether the skew is past the warning threshold
+	LastSample time.Time     `json:"last_sample"`
+}
+
+// ClockSkewEvent reports an exchange's skew going past the warning threshold,
+// or coming back within it
+type ClockSkewEvent struct {
+	Exchange  string
+	Skew      time.Duration
+	Threshold time.Duration
+	Exceeded  bool
+}
+
+// exchangeClock holds the latest skew samples of an exchange
+type exchangeClock struct {
+	samples  [clockSkewSamples]time.Duration
+	count    int // Samples held, up to clockSkewSamples
+	next     int // Where the next sample goes
+	skew     time.Duration
+	exceeded bool
+	last     time.Time
+}
+
+// add records a sample and returns the median of those held
+func (c *exchangeClock) add(skew time.Duration) time.Duration {
+	c.samples[c.next] = skew
+	c.next = (c.next + 1) % clockSkewSamples
+	if c.count < clockSkewSamples {
+		c.count++
+	}
+
+	sorted := make([]time.Duration, c.count)
+	copy(sorted, c.samples[:c.count])
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	if c.count%2 == 1 {
+		return sorted[c.count/2]
+	}
+	return (sorted[c.count/2-1] + sorted[c.count/2]) / 2
+}
+
+// ClockSkewMonitor measures the clock skew against each exchange from the
+// timestamps on its messages, records it as a metric and warns when it goes
+// past a threshold
+type ClockSkewMonitor struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	exchanges map[string]*exchangeClock
+	metrics   *metrics.Wrapper
+	onWarning func(event ClockSkewEvent)
+}
+
+// NewClockSkewMonitor creates a monitor warning when the skew against an
+// exchange is further than threshold either way; zero disables warnings
+func NewClockSkewMonitor(threshold time.Duration) *ClockSkewMonitor {
+	return &ClockSkewMonitor{
+		threshold: threshold,
+		exchanges: make(map[string]*exchangeClock),
+	}
+}
+
+// SetThreshold sets how far off an exchange's clock may be before warning
+func (c *ClockSkewMonitor) SetThreshold(threshold time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.threshold = threshold
+}
+
+// SetMetrics sets where the skew of each exchange is recorded
+func (c *ClockSkewMonitor) SetMetrics(metrics *metrics.Wrapper) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metrics = metrics
+}
+
+// SetWarningHandler sets a callback invoked when an exchange's skew goes past
+// the threshold and when it comes back within it
+func (c *ClockSkewMonitor) SetWarningHandler(handler func(event ClockSkewEvent)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onWarning = handler
+}
+
+// Observe records a message from exchange stamped at exchangeTime and
+// received at received. Messages without a timestamp are ignored.
+func (c *ClockSkewMonitor) Observe(exchange string, exchangeTime, received time.Time) {
+	if exchangeTime.IsZero() {
+		return
+	}
+
+	c.mu.Lock()
+	clock, ok := c.exchanges[exchange]
+	if !ok {
+		clock = &exchangeClock{}
+		c.exchanges[exchange] = clock
+	}
+	clock.skew = clock.add(received.Sub(exchangeTime))
+	clock.last = received
+
+	exceeded := c.threshold > 0 && (clock.skew > c.threshold || clock.skew < -c.threshold)
+	var event *ClockSkewEvent
+	if exceeded != clock.exceeded {
+		clock.exceeded = exceeded
+		event = &ClockSkewEvent{Exchange: exchange, Skew: clock.skew, Threshold: c.threshold, Exceeded: exceeded}
+	}
+	skew := clock.skew
+	metrics := c.metrics
+	handler := c.onWarning
+	c.mu.Unlock()
+
+	if metrics != nil {
+		metrics.RecordFeedClockSkew(exchange, skew)
+	}
+	if event == nil {
+		return
+	}
+	if event.Exceeded {
+		log.Printf("Warning: clock skew against %s is %s, beyond %s", exchange, event.Skew, event.Threshold)
+	} else {
+		log.Printf("Clock skew against %s is back to %s, within %s", exchange, event.Skew, event.Threshold)
+	}
+	if handler != nil {
+		handler(*event)
+	}
+}
+
+// Skew returns the measured skew against an exchange, and false if none of
+// its messages carried a timestamp yet
+func (c *ClockSkewMonitor) Skew(exchange string) (ClockSkew, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clock, ok := c.exchanges[exchange]
+	if !ok {
+		return ClockSkew{}, false
+	}
+	return ClockSkew{
+		Exchange:   exchange,
+		Skew:       clock.skew,
+		Samples:    clock.count,
+		Exceeded:   clock.exceeded,
+		LastSample: clock.last,
+	}, true
+}
+
+// Skews returns the measured skew against every exchange, sorted by exchange
+func (c *ClockSkewMonitor) Skews() []ClockSkew {
+	c.mu.Lock()
+	exchanges := make([]string, 0, len(c.exchanges))
+	for exchange := range c.exchanges {
+		exchanges = append(exchanges, exchange)
+	}
+	c.mu.Unlock()
+
+	sort.Strings(exchanges)
+	skews := make([]ClockSkew, 0, len(exchanges))
+	for _, exchange := range exchanges {
+		if skew, ok := c.Skew(exchange); ok {
+			skews = append(skews, skew)
+		}
+	}
+	return skews
+}
+
+// clockObserver is a feed that reports the timestamps on its messages
+type clockObserver interface {
+	SetClockSkewMonitor(clock *ClockSkewMonitor)
+}
@@ -0,0 +1,112 @@
+package feeds
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/config"
+)
+
+// TestClockSkewMeasured tests that the skew is the median of the latest
+// messages, so a single delayed one does not move it
+func TestClockSkewMeasured(t *testing.T) {
+	clock := NewClockSkewMonitor(0)
+	received := time.Date(2024, 4, 15, 14, 30, 0, 0, time.UTC)
+
+	_, ok := clock.Skew("binance")
+	assert.False(t, ok)
+
+	for i, skew := range []time.Duration{120, 80, 100, 2000, 100} {
+		at := received.Add(time.Duration(i) * time.Second)
+		clock.Observe("binance", at.Add(-skew*time.Millisecond), at)
+	}
+	clock.Observe("coinbase", received.Add(250*time.Millisecond), received)
+	clock.Observe("coinbase", time.Time{}, received)
+
+	skew, ok := clock.Skew("binance")
+	require.True(t, ok)
+	assert.Equal(t, 100*time.Millisecond, skew.Skew)
+	assert.Equal(t, 5, skew.Samples)
+	assert.False(t, skew.Exceeded)
+	assert.Equal(t, received.Add(4*time.Second), skew.LastSample)
+
+	// The local clock behind the exchange's shows as a negative skew
+	skews := clock.Skews()
+	require.Len(t, skews, 2)
+	assert.Equal(t, "binance", skews[0].Exchange)
+	assert.Equal(t, "coinbase", skews[1].Exchange)
+	assert.Equal(t, -250*time.Millisecond, skews[1].Skew)
+	assert.Equal(t, 1, skews[1].Samples)
+}
+
+// TestClockSkewWarning tests that a skew beyond the threshold, either way,
+// warns once, and again when it comes back within
+func TestClockSkewWarning(t *testing.T) {
+	clock := NewClockSkewMonitor(500 * time.Millisecond)
+	var events []ClockSkewEvent
+	clock.SetWarningHandler(func(event ClockSkewEvent) {
+		events = append(events, event)
+	})
+
+	now := time.Now()
+	clock.Observe("binance", now.Add(-100*time.Millisecond), now)
+	assert.Empty(t, events)
+
+	// The median moves past the threshold on the second skewed message
+	for i := 0; i < 3; i++ {
+		clock.Observe("binance", now.Add(-2*time.Second), now)
+	}
+	require.Len(t, events, 1)
+	assert.Equal(t, ClockSkewEvent{Exchange: "binance", Skew: 1050 * time.Millisecond, Threshold: 500 * time.Millisecond, Exceeded: true}, events[0])
+	skew, _ := clock.Skew("binance")
+	assert.True(t, skew.Exceeded)
+	assert.Equal(t, 2*time.Second, skew.Skew)
+
+	for i := 0; i < 5; i++ {
+		clock.Observe("binance", now.Add(-50*time.Millisecond), now)
+	}
+	require.Len(t, events, 2)
+	assert.False(t, events[1].Exceeded)
+	assert.Equal(t, 100*time.Millisecond, events[1].Skew)
+
+	// A local clock that is behind warns too
+	clock.Observe("coinbase", now.Add(time.Second), now)
+	require.Len(t, events, 3)
+	assert.Equal(t, "coinbase", events[2].Exchange)
+	assert.Equal(t, -time.Second, events[2].Skew)
+}
+
+// TestBinanceClockSkew tests that Binance depth updates stamped in the past
+// are measured as a skew, reported in the feed health
+func TestBinanceClockSkew(t *testing.T) {
+	m := NewManager(nil, []config.FeedConfig{{Name: "binance"}})
+	m.SetHealthConfig(config.FeedHealthConfig{MaxClockSkew: time.Second})
+	var warned []ClockSkewEvent
+	m.SetClockSkewHandler(func(event ClockSkewEvent) {
+		warned = append(warned, event)
+	})
+
+	feed := newTestBinanceFeed(t, "ws://localhost:0", &countingBooks{})
+	feed.SetClockSkewMonitor(m.clock)
+	m.named["binance"] = feed
+
+	for i := int64(1); i <= 3; i++ {
+		stamped := time.Now().Add(-3 * time.Second).UnixMilli()
+		feed.handleMessage([]byte(fmt.Sprintf(`{"stream":"btcusdt@depth","data":{"e":"depthUpdate","E":%d,"s":"BTCUSDT","U":%d,"u":%d,"b":[["100.0","1.0"]],"a":[["101.0","2.0"]]}}`, stamped, i, i)))
+	}
+
+	skews := m.ClockSkews()
+	require.Len(t, skews, 1)
+	assert.Equal(t, 3, skews[0].Samples)
+	assert.InDelta(t, 3*time.Second, skews[0].Skew, float64(200*time.Millisecond))
+	require.Len(t, warned, 1)
+	assert.True(t, warned[0].Exceeded)
+
+	health := m.Health()
+	require.Len(t, health, 1)
+	require.NotNil(t, health[0].ClockSkew)
+	assert.InDelta(t, 3.0, *health[0].ClockSkew, 0.2)
+}
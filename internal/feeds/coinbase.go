@@ -10,6 +10,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/shopspring/decimal"
 	"velocimex/internal/config"
+	"velocimex/internal/metrics"
 	"velocimex/internal/normalizer"
 )
 
@@ -20,8 +21,10 @@ type CoinbaseWebSocketFeed struct {
 	conn       *websocket.Conn
 	isConnected bool
 	mu         sync.Mutex
-	done       chan struct{}
+	done       chan struct{} // Closed by Disconnect to stop reading and reconnecting
 	orderBookManager OrderBookManager
+	reader     *readSupervisor
+	clock      *ClockSkewMonitor
 }
 
 // CoinbaseMessage represents a Coinbase WebSocket message
@@ -40,6 +43,7 @@ func NewCoinbaseWebSocketFeed(config config.FeedConfig, norm *normalizer.Normali
 		config:     config,
 		normalizer: norm,
 		done:       make(chan struct{}),
+		reader:     newReadSupervisor(config.Name),
 	}, nil
 }
 
@@ -48,11 +52,46 @@ func (f *CoinbaseWebSocketFeed) SetOrderBookManager(manager OrderBookManager) {
 	f.orderBookManager = manager
 }
 
+// SetClockSkewMonitor sets where the timestamps on messages are reported
+func (f *CoinbaseWebSocketFeed) SetClockSkewMonitor(clock *ClockSkewMonitor) {
+	f.clock = clock
+}
+
+// SetMetrics sets where read errors and reconnects are recorded
+func (f *CoinbaseWebSocketFeed) SetMetrics(metrics *metrics.Wrapper) {
+	f.reader.setMetrics(metrics)
+}
+
+// stats returns the message flow the feed has seen
+func (f *CoinbaseWebSocketFeed) stats(now time.Time, rateWindow time.Duration) FeedStats {
+	return f.reader.stats.snapshot(now, rateWindow)
+}
+
 // Connect establishes a connection to Coinbase WebSocket
 func (f *CoinbaseWebSocketFeed) Connect() error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
+	if f.done == nil {
+		f.done = make(chan struct{})
+	}
+	return f.connect(f.done)
+}
+
+// reconnect re-establishes a connection lost to a read error, unless the feed
+// was disconnected while waiting
+func (f *CoinbaseWebSocketFeed) reconnect(done chan struct{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.done != done {
+		return errFeedDisconnected
+	}
+	return f.connect(done)
+}
+
+// connect dials the WebSocket and starts reading. The caller holds f.mu.
+func (f *CoinbaseWebSocketFeed) connect(done chan struct{}) error {
 	if f.isConnected {
 		return nil
 	}
@@ -75,7 +114,9 @@ func (f *CoinbaseWebSocketFeed) Connect() error {
 	}
 
 	// Start message processing
-	go f.processMessages()
+	f.reader.start(conn, done, f.handleMessage, f.handleDisconnection, func() error {
+		return f.reconnect(done)
+	})
 
 	log.Printf("Connected to Coinbase WebSocket feed: %s", f.config.Name)
 	return nil
@@ -84,13 +125,14 @@ func (f *CoinbaseWebSocketFeed) Connect() error {
 // Disconnect closes the WebSocket connection
 func (f *CoinbaseWebSocketFeed) Disconnect() error {
 	f.mu.Lock()
-	defer f.mu.Unlock()
-
-	if !f.isConnected {
+	if f.done == nil {
+		f.mu.Unlock()
 		return nil
 	}
 
+	// Closing done also stops a pending reconnect
 	close(f.done)
+	f.done = nil
 
 	if f.conn != nil {
 		f.conn.Close()
@@ -98,6 +140,10 @@ func (f *CoinbaseWebSocketFeed) Disconnect() error {
 	}
 
 	f.isConnected = false
+	f.mu.Unlock()
+
+	// Wait for the read loop to exit; it needs the lock to drop its connection
+	f.reader.wait()
 	log.Printf("Disconnected from Coinbase WebSocket feed: %s", f.config.Name)
 	return nil
 }
@@ -145,31 +191,6 @@ func (f *CoinbaseWebSocketFeed) subscribeToChannels() error {
 	return f.conn.WriteJSON(subscription)
 }
 
-// processMessages processes incoming WebSocket messages
-func (f *CoinbaseWebSocketFeed) processMessages() {
-	defer func() {
-		if r := recover(); r != nil {
-			log.Printf("Coinbase WebSocket panic recovered: %v", r)
-		}
-	}()
-
-	for {
-		select {
-		case <-f.done:
-			return
-		default:
-			_, message, err := f.conn.ReadMessage()
-			if err != nil {
-				log.Printf("Coinbase WebSocket read error: %v", err)
-				f.handleDisconnection()
-				return
-			}
-
-			f.handleMessage(message)
-		}
-	}
-}
-
 // handleMessage processes a single WebSocket message
 func (f *CoinbaseWebSocketFeed) handleMessage(message []byte) {
 	var msg CoinbaseMessage
@@ -182,6 +203,9 @@ func (f *CoinbaseWebSocketFeed) handleMessage(message []byte) {
 	if msg.Type != "l2update" && msg.Type != "snapshot" {
 		return
 	}
+	if f.clock != nil && msg.Time != "" {
+		f.clock.Observe("coinbase", f.parseTime(msg.Time), time.Now())
+	}
 
 	// Convert Coinbase data to normalized format
 	bids := f.convertPriceLevels(msg.Bids)
@@ -190,12 +214,6 @@ func (f *CoinbaseWebSocketFeed) handleMessage(message []byte) {
 	// Normalize symbol
 	normalizedSymbol := f.normalizer.NormalizeSymbol("coinbase", msg.ProductID)
 
-	// Update order book if manager is available
-	if f.orderBookManager != nil {
-		f.orderBookManager.UpdateOrderBook("coinbase", normalizedSymbol, bids, asks)
-	}
-
-	// Process through normalizer
 	orderBookUpdate := &normalizer.OrderBookUpdate{
 		Exchange:  "coinbase",
 		Symbol:    normalizedSymbol,
@@ -203,8 +221,21 @@ func (f *CoinbaseWebSocketFeed) handleMessage(message []byte) {
 		Asks:      asks,
 		Timestamp: f.parseTime(msg.Time),
 		Snapshot:  msg.Type == "snapshot",
+		Sequence:  msg.Sequence,
+	}
+
+	// Drop malformed or stale updates before they reach the book
+	if err := f.normalizer.ValidateOrderBookUpdate(orderBookUpdate); err != nil {
+		log.Printf("Rejected Coinbase order book update: %v", err)
+		return
+	}
+
+	// Update order book if manager is available
+	if f.orderBookManager != nil {
+		f.orderBookManager.UpdateOrderBook("coinbase", normalizedSymbol, orderBookUpdate.Bids, orderBookUpdate.Asks)
 	}
 
+	// Process through normalizer
 	f.normalizer.ProcessOrderBookUpdate(orderBookUpdate)
 }
 
@@ -259,22 +290,17 @@ func (f *CoinbaseWebSocketFeed) parseTime(timeStr string) time.Time {
 	return t
 }
 
-// handleDisconnection handles WebSocket disconnection
-func (f *CoinbaseWebSocketFeed) handleDisconnection() {
+// handleDisconnection drops a connection that failed to read. Reconnecting
+// is left to the read supervisor.
+func (f *CoinbaseWebSocketFeed) handleDisconnection(conn *websocket.Conn) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	f.isConnected = false
-	if f.conn != nil {
-		f.conn.Close()
-		f.conn = nil
+	conn.Close()
+	if f.conn != conn {
+		// Already replaced or disconnected
+		return
 	}
-
-	// Attempt to reconnect after a delay
-	go func() {
-		time.Sleep(5 * time.Second)
-		if err := f.Connect(); err != nil {
-			log.Printf("Failed to reconnect to Coinbase: %v", err)
-		}
-	}()
+	f.conn = nil
+	f.isConnected = false
 }
@@ -0,0 +1,250 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// defaultFailoverInterval is how often a failover feed checks its connections
+const defaultFailoverInterval = 5 * time.Second
+
+// Failover endpoints
+const (
+	EndpointPrimary = "primary"
+	EndpointBackup  = "backup"
+)
+
+// FailoverEvent reports a feed switching between its primary and backup endpoints
+type FailoverEvent struct {
+	Feed      string    `json:"feed"`
+	Active    string    `json:"active"` // Endpoint now serving data
+	Reason    string    `json:"reason"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// FailoverFeed serves an exchange's data from its primary connection and
+// fails over to a backup endpoint when the primary drops. While on the backup
+// it keeps trying the primary and fails back once it reconnects. The backup is
+// a cold standby: it is only connected while it is serving data, and a fresh
+// connection is created for every failover.
+type FailoverFeed struct {
+	name      string
+	primary   Feed
+	newBackup func() (Feed, error)
+	interval  time.Duration
+
+	mu      sync.Mutex
+	backup  Feed // Nil while the primary is active
+	symbols []string
+	onEvent func(FailoverEvent)
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewFailoverFeed creates a feed that fails over from primary to backups
+// created by newBackup, checking the connections every interval
+func NewFailoverFeed(name string, primary Feed, newBackup func() (Feed, error), interval time.Duration) *FailoverFeed {
+	if interval <= 0 {
+		interval = defaultFailoverInterval
+	}
+	return &FailoverFeed{
+		name:      name,
+		primary:   primary,
+		newBackup: newBackup,
+		interval:  interval,
+	}
+}
+
+// SetEventHandler sets a callback invoked on failover and failback, e.g. to raise an alert
+func (f *FailoverFeed) SetEventHandler(handler func(event FailoverEvent)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.onEvent = handler
+}
+
+// Active returns the endpoint currently serving data
+func (f *FailoverFeed) Active() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.backup != nil {
+		return EndpointBackup
+	}
+	return EndpointPrimary
+}
+
+// Connect connects the primary, or the backup if the primary is unreachable,
+// and starts monitoring the connections
+func (f *FailoverFeed) Connect() error {
+	f.mu.Lock()
+	if f.cancel != nil {
+		f.mu.Unlock()
+		return nil
+	}
+
+	if err := f.primary.Connect(); err != nil {
+		log.Printf("Primary endpoint of feed %s unreachable: %v", f.name, err)
+		if err := f.connectBackup(); err != nil {
+			f.mu.Unlock()
+			return fmt.Errorf("primary and backup endpoints of feed %s unreachable: %w", f.name, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancel = cancel
+	f.mu.Unlock()
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				f.check()
+			}
+		}
+	}()
+	return nil
+}
+
+// Disconnect stops monitoring and disconnects both endpoints
+func (f *FailoverFeed) Disconnect() error {
+	f.mu.Lock()
+	cancel := f.cancel
+	f.cancel = nil
+	f.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		f.wg.Wait()
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.backup != nil {
+		if err := f.backup.Disconnect(); err != nil {
+			log.Printf("Error disconnecting backup endpoint of feed %s: %v", f.name, err)
+		}
+		f.backup = nil
+	}
+	return f.primary.Disconnect()
+}
+
+// Subscribe subscribes the active connection and remembers the symbol for the next switch
+func (f *FailoverFeed) Subscribe(symbol string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.symbols = append(f.symbols, symbol)
+	return f.activeFeed().Subscribe(symbol)
+}
+
+// Unsubscribe unsubscribes the active connection
+func (f *FailoverFeed) Unsubscribe(symbol string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, s := range f.symbols {
+		if s == symbol {
+			f.symbols = append(f.symbols[:i], f.symbols[i+1:]...)
+			break
+		}
+	}
+	return f.activeFeed().Unsubscribe(symbol)
+}
+
+// IsConnected reports whether the active connection is up
+func (f *FailoverFeed) IsConnected() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.activeFeed().IsConnected()
+}
+
+// stats returns the message flow of the active connection, if it tracks one
+func (f *FailoverFeed) stats(now time.Time, rateWindow time.Duration) FeedStats {
+	f.mu.Lock()
+	active := f.activeFeed()
+	f.mu.Unlock()
+
+	if reporter, ok := active.(statsReporter); ok {
+		return reporter.stats(now, rateWindow)
+	}
+	return FeedStats{}
+}
+
+// activeFeed returns the connection serving data. The caller holds f.mu.
+func (f *FailoverFeed) activeFeed() Feed {
+	if f.backup != nil {
+		return f.backup
+	}
+	return f.primary
+}
+
+// connectBackup creates, connects and subscribes a backup connection. The caller holds f.mu.
+func (f *FailoverFeed) connectBackup() error {
+	backup, err := f.newBackup()
+	if err != nil {
+		return err
+	}
+	if err := backup.Connect(); err != nil {
+		return err
+	}
+	subscribe(f.name, backup, f.symbols)
+	f.backup = backup
+	return nil
+}
+
+// check fails over when the primary has dropped and fails back once it has recovered
+func (f *FailoverFeed) check() {
+	f.mu.Lock()
+	var event *FailoverEvent
+	switch {
+	case f.backup == nil && !f.primary.IsConnected():
+		if err := f.connectBackup(); err != nil {
+			log.Printf("Failover of feed %s to backup endpoint failed: %v", f.name, err)
+			break
+		}
+		event = &FailoverEvent{Feed: f.name, Active: EndpointBackup, Reason: "primary endpoint disconnected"}
+
+	case f.backup != nil:
+		// Retry the primary; Connect is a no-op if it has already reconnected
+		if err := f.primary.Connect(); err != nil || !f.primary.IsConnected() {
+			break
+		}
+		subscribe(f.name, f.primary, f.symbols)
+		if err := f.backup.Disconnect(); err != nil {
+			log.Printf("Error disconnecting backup endpoint of feed %s: %v", f.name, err)
+		}
+		f.backup = nil
+		event = &FailoverEvent{Feed: f.name, Active: EndpointPrimary, Reason: "primary endpoint recovered"}
+	}
+	onEvent := f.onEvent
+	f.mu.Unlock()
+
+	if event == nil {
+		return
+	}
+	event.Timestamp = time.Now()
+	log.Printf("Feed %s switched to %s endpoint: %s", event.Feed, event.Active, event.Reason)
+	if onEvent != nil {
+		onEvent(*event)
+	}
+}
+
+// subscribe subscribes a connection to every symbol, logging failures
+func subscribe(name string, feed Feed, symbols []string) {
+	for _, symbol := range symbols {
+		if err := feed.Subscribe(symbol); err != nil {
+			log.Printf("Failed to subscribe to %s on %s: %v", symbol, name, err)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package feeds
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFeed streams updates tagged with its endpoint while connected. Failing
+// it drops the connection and makes reconnects fail until it is restored.
+type fakeFeed struct {
+	endpoint string
+	updates  chan<- string
+
+	mu         sync.Mutex
+	connected  bool
+	failing    bool
+	subscribed []string
+	done       chan struct{}
+}
+
+func newFakeFeed(endpoint string, updates chan<- string) *fakeFeed {
+	return &fakeFeed{endpoint: endpoint, updates: updates}
+}
+
+func (f *fakeFeed) Connect() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failing {
+		return fmt.Errorf("%s unreachable", f.endpoint)
+	}
+	if f.connected {
+		return nil
+	}
+	f.connected = true
+	f.done = make(chan struct{})
+	go f.stream(f.done)
+	return nil
+}
+
+func (f *fakeFeed) stream(done chan struct{}) {
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			select {
+			case f.updates <- f.endpoint:
+			default:
+			}
+		}
+	}
+}
+
+func (f *fakeFeed) Disconnect() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.drop()
+	return nil
+}
+
+// drop closes the connection. The caller holds f.mu.
+func (f *fakeFeed) drop() {
+	if f.connected {
+		f.connected = false
+		close(f.done)
+	}
+}
+
+func (f *fakeFeed) fail() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failing = true
+	f.drop()
+}
+
+func (f *fakeFeed) restore() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failing = false
+}
+
+func (f *fakeFeed) Subscribe(symbol string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subscribed = append(f.subscribed, symbol)
+	return nil
+}
+
+func (f *fakeFeed) Unsubscribe(symbol string) error { return nil }
+
+func (f *fakeFeed) IsConnected() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.connected
+}
+
+func (f *fakeFeed) symbols() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.subscribed...)
+}
+
+// awaitUpdate waits for an update from the given endpoint
+func awaitUpdate(t *testing.T, updates <-chan string, endpoint string) {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case got := <-updates:
+			if got == endpoint {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no update from %s endpoint", endpoint)
+		}
+	}
+}
+
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []FailoverEvent
+}
+
+func (r *eventRecorder) record(event FailoverEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) actives() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	actives := make([]string, len(r.events))
+	for i, event := range r.events {
+		actives[i] = event.Active
+	}
+	return actives
+}
+
+// TestFailoverToBackupAndBack tests that data keeps flowing from the backup
+// while the primary is down and that the feed fails back once it recovers
+func TestFailoverToBackupAndBack(t *testing.T) {
+	updates := make(chan string, 64)
+	primary := newFakeFeed(EndpointPrimary, updates)
+	var backups []*fakeFeed
+	var backupsMu sync.Mutex
+	newBackup := func() (Feed, error) {
+		backupsMu.Lock()
+		defer backupsMu.Unlock()
+		backup := newFakeFeed(EndpointBackup, updates)
+		backups = append(backups, backup)
+		return backup, nil
+	}
+
+	feed := NewFailoverFeed("binance", primary, newBackup, 5*time.Millisecond)
+	recorder := &eventRecorder{}
+	feed.SetEventHandler(recorder.record)
+
+	require.NoError(t, feed.Connect())
+	t.Cleanup(func() { feed.Disconnect() })
+	require.NoError(t, feed.Subscribe("BTCUSDT"))
+	awaitUpdate(t, updates, EndpointPrimary)
+	assert.Equal(t, EndpointPrimary, feed.Active())
+
+	// Primary drops: the backup takes over with the same subscriptions
+	primary.fail()
+	require.Eventually(t, func() bool { return feed.Active() == EndpointBackup }, 2*time.Second, time.Millisecond)
+	awaitUpdate(t, updates, EndpointBackup)
+	assert.True(t, feed.IsConnected())
+
+	backupsMu.Lock()
+	require.Len(t, backups, 1)
+	backup := backups[0]
+	backupsMu.Unlock()
+	assert.Equal(t, []string{"BTCUSDT"}, backup.symbols())
+
+	// Primary recovers: data comes from the primary again and the backup is released
+	primary.restore()
+	require.Eventually(t, func() bool { return feed.Active() == EndpointPrimary }, 2*time.Second, time.Millisecond)
+	awaitUpdate(t, updates, EndpointPrimary)
+	assert.False(t, backup.IsConnected())
+	assert.Equal(t, []string{"BTCUSDT", "BTCUSDT"}, primary.symbols())
+
+	assert.Equal(t, []string{EndpointBackup, EndpointPrimary}, recorder.actives())
+
+	require.NoError(t, feed.Disconnect())
+	assert.False(t, primary.IsConnected())
+}
+
+// TestFailoverConnectsBackupWhenPrimaryUnreachable tests that a feed starts
+// on the backup when the primary is down at startup
+func TestFailoverConnectsBackupWhenPrimaryUnreachable(t *testing.T) {
+	updates := make(chan string, 64)
+	primary := newFakeFeed(EndpointPrimary, updates)
+	primary.fail()
+	backup := newFakeFeed(EndpointBackup, updates)
+
+	feed := NewFailoverFeed("binance", primary, func() (Feed, error) { return backup, nil }, time.Hour)
+	require.NoError(t, feed.Connect())
+	t.Cleanup(func() { feed.Disconnect() })
+
+	assert.Equal(t, EndpointBackup, feed.Active())
+	awaitUpdate(t, updates, EndpointBackup)
+}
+
+// TestFailoverBothEndpointsDown tests that Connect fails when neither endpoint is reachable
+func TestFailoverBothEndpointsDown(t *testing.T) {
+	primary := newFakeFeed(EndpointPrimary, make(chan string, 1))
+	primary.fail()
+
+	feed := NewFailoverFeed("binance", primary, func() (Feed, error) {
+		return nil, fmt.Errorf("backup unreachable")
+	}, time.Hour)
+	assert.Error(t, feed.Connect())
+	assert.False(t, feed.IsConnected())
+}
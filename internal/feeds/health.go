@@ -0,0 +1,210 @@
+package feeds
+
+import (
+	"sync"
+	"time"
+
+	"velocimex/internal/config"
+)
+
+// Feed health states
+const (
+	FeedStateConnected    = "connected"
+	FeedStateStale        = "stale"
+	FeedStateDisconnected = "disconnected"
+)
+
+// defaultRateWindow is the window message rates are averaged over when the
+// health config sets none
+const defaultRateWindow = 10 * time.Second
+
+// rateBuckets bounds the rate window: messages are counted per second over
+// the last rateBuckets seconds
+const rateBuckets = 60
+
+// FeedHealth is the health of a configured market data feed
+type FeedHealth struct {
+	Name        string     `json:"name"`
+	State       string     `json:"state"`
+	Connected   bool       `json:"connected"`
+	Endpoint    string     `json:"endpoint,omitempty"` // Primary or backup, for feeds with a backup endpoint
+	LastMessage *time.Time `json:"last_message,omitempty"`
+	Staleness   float64    `json:"staleness_seconds"` // Seconds since the last message, or since connecting if none arrived
+	MessageRate float64    `json:"message_rate"`      // Messages per second over the rate window
+	Messages    int64      `json:"messages"`
+	Reconnects  int        `json:"reconnects"`
+	ReadErrors  int        `json:"read_errors"`
+	ClockSkew   *float64   `json:"clock_skew_seconds,omitempty"` // Local clock less the exchange's, for feeds whose messages carry timestamps
+}
+
+// FeedStats is the message flow a feed has seen
+type FeedStats struct {
+	Messages       int64
+	LastMessage    time.Time
+	ConnectedSince time.Time
+	Reconnects     int
+	ReadErrors     int
+	MessageRate    float64
+}
+
+// statsReporter is a feed that tracks its message flow
+type statsReporter interface {
+	stats(now time.Time, rateWindow time.Duration) FeedStats
+}
+
+// feedStats counts the messages, reconnects and read errors of a feed
+type feedStats struct {
+	mu             sync.Mutex
+	messages       int64
+	lastMessage    time.Time
+	connectedSince time.Time
+	reconnects     int
+	readErrors     int
+	counts         [rateBuckets]int64
+	seconds        [rateBuckets]int64 // Unix second each count is for
+}
+
+func (s *feedStats) recordConnect(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connectedSince = now
+}
+
+func (s *feedStats) recordMessage(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages++
+	s.lastMessage = now
+	second := now.Unix()
+	bucket := second % rateBuckets
+	if s.seconds[bucket] != second {
+		s.seconds[bucket] = second
+		s.counts[bucket] = 0
+	}
+	s.counts[bucket]++
+}
+
+func (s *feedStats) recordReconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconnects++
+}
+
+func (s *feedStats) recordReadError() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readErrors++
+}
+
+// snapshot returns the stats, averaging the message rate over the last
+// window, rounded to whole seconds and capped at rateBuckets seconds
+func (s *feedStats) snapshot(now time.Time, window time.Duration) FeedStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seconds := int64(window / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	if seconds > rateBuckets {
+		seconds = rateBuckets
+	}
+	var count int64
+	for second := now.Unix() - seconds + 1; second <= now.Unix(); second++ {
+		if bucket := second % rateBuckets; s.seconds[bucket] == second {
+			count += s.counts[bucket]
+		}
+	}
+
+	return FeedStats{
+		Messages:       s.messages,
+		LastMessage:    s.lastMessage,
+		ConnectedSince: s.connectedSince,
+		Reconnects:     s.reconnects,
+		ReadErrors:     s.readErrors,
+		MessageRate:    float64(count) / float64(seconds),
+	}
+}
+
+// SetHealthConfig sets when feeds are reported stale and the window their
+// message rate is averaged over
+func (m *Manager) SetHealthConfig(health config.FeedHealthConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.health = health
+	m.clock.SetThreshold(health.MaxClockSkew)
+}
+
+// Health returns the health of every configured feed, in config order. Feeds
+// that failed to connect are reported disconnected.
+func (m *Manager) Health() []FeedHealth {
+	m.mu.Lock()
+	configs := m.configs
+	named := make(map[string]Feed, len(m.named))
+	for name, feed := range m.named {
+		named[name] = feed
+	}
+	health := m.health
+	m.mu.Unlock()
+
+	now := time.Now()
+	report := make([]FeedHealth, 0, len(configs))
+	for _, config := range configs {
+		feed := feedHealth(config.Name, named[config.Name], health, now)
+		if skew, ok := m.clock.Skew(config.Name); ok {
+			seconds := skew.Skew.Seconds()
+			feed.ClockSkew = &seconds
+		}
+		report = append(report, feed)
+	}
+	return report
+}
+
+// feedHealth reports the health of a feed, which is nil if it never connected
+func feedHealth(name string, feed Feed, health config.FeedHealthConfig, now time.Time) FeedHealth {
+	report := FeedHealth{Name: name, State: FeedStateDisconnected}
+	if feed == nil {
+		return report
+	}
+	report.Connected = feed.IsConnected()
+	if failoverFeed, ok := feed.(*FailoverFeed); ok {
+		report.Endpoint = failoverFeed.Active()
+	}
+
+	reporter, ok := feed.(statsReporter)
+	if !ok {
+		if report.Connected {
+			report.State = FeedStateConnected
+		}
+		return report
+	}
+
+	window := health.RateWindow
+	if window <= 0 {
+		window = defaultRateWindow
+	}
+	stats := reporter.stats(now, window)
+	report.Messages = stats.Messages
+	report.MessageRate = stats.MessageRate
+	report.Reconnects = stats.Reconnects
+	report.ReadErrors = stats.ReadErrors
+
+	since := stats.ConnectedSince
+	if !stats.LastMessage.IsZero() {
+		lastMessage := stats.LastMessage
+		report.LastMessage = &lastMessage
+		since = lastMessage
+	}
+	if !since.IsZero() {
+		report.Staleness = now.Sub(since).Seconds()
+	}
+
+	if report.Connected {
+		report.State = FeedStateConnected
+		if health.StaleAfter > 0 && !since.IsZero() && now.Sub(since) > health.StaleAfter {
+			report.State = FeedStateStale
+		}
+	}
+	return report
+}
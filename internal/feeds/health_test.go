@@ -0,0 +1,135 @@
+package feeds
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/config"
+)
+
+// statsFeed is a feed reporting fixed connection state and stats
+type statsFeed struct {
+	connected bool
+	flow      FeedStats
+}
+
+func (f *statsFeed) Connect() error                  { return nil }
+func (f *statsFeed) Disconnect() error               { return nil }
+func (f *statsFeed) Subscribe(symbol string) error   { return nil }
+func (f *statsFeed) Unsubscribe(symbol string) error { return nil }
+func (f *statsFeed) IsConnected() bool               { return f.connected }
+
+func (f *statsFeed) stats(now time.Time, rateWindow time.Duration) FeedStats {
+	return f.flow
+}
+
+// TestManagerHealth tests the health reported for feeds in each state
+func TestManagerHealth(t *testing.T) {
+	now := time.Now()
+	configs := []config.FeedConfig{{Name: "binance"}, {Name: "coinbase"}, {Name: "kraken"}, {Name: "nasdaq"}, {Name: "fix"}}
+	m := NewManager(nil, configs)
+	m.SetHealthConfig(config.FeedHealthConfig{StaleAfter: 30 * time.Second})
+	m.named["binance"] = &statsFeed{connected: true, flow: FeedStats{
+		Messages: 1200, LastMessage: now.Add(-time.Second), ConnectedSince: now.Add(-time.Hour), Reconnects: 2, ReadErrors: 3, MessageRate: 12.5,
+	}}
+	m.named["coinbase"] = &statsFeed{connected: true, flow: FeedStats{
+		Messages: 10, LastMessage: now.Add(-time.Minute), ConnectedSince: now.Add(-time.Hour),
+	}}
+	m.named["kraken"] = &statsFeed{connected: false, flow: FeedStats{
+		Messages: 50, LastMessage: now.Add(-10 * time.Second), ConnectedSince: now.Add(-time.Hour), Reconnects: 4, ReadErrors: 5,
+	}}
+	m.named["nasdaq"] = &fakeFeed{connected: true}
+
+	health := m.Health()
+	require.Len(t, health, 5)
+	byName := make(map[string]FeedHealth)
+	for _, feed := range health {
+		byName[feed.Name] = feed
+	}
+
+	live := byName["binance"]
+	assert.Equal(t, FeedStateConnected, live.State)
+	assert.True(t, live.Connected)
+	assert.Equal(t, int64(1200), live.Messages)
+	assert.Equal(t, 12.5, live.MessageRate)
+	assert.Equal(t, 2, live.Reconnects)
+	assert.Equal(t, 3, live.ReadErrors)
+	require.NotNil(t, live.LastMessage)
+	assert.InDelta(t, 1.0, live.Staleness, 0.5)
+
+	quiet := byName["coinbase"]
+	assert.Equal(t, FeedStateStale, quiet.State)
+	assert.True(t, quiet.Connected)
+	assert.InDelta(t, 60.0, quiet.Staleness, 0.5)
+
+	dropped := byName["kraken"]
+	assert.Equal(t, FeedStateDisconnected, dropped.State)
+	assert.False(t, dropped.Connected)
+	assert.Equal(t, 4, dropped.Reconnects)
+
+	// Feeds that do not track messages report their connection only
+	untracked := byName["nasdaq"]
+	assert.Equal(t, FeedStateConnected, untracked.State)
+	assert.Nil(t, untracked.LastMessage)
+
+	// Configured feeds that never connected are disconnected
+	assert.Equal(t, FeedHealth{Name: "fix", State: FeedStateDisconnected}, byName["fix"])
+}
+
+// TestManagerHealthNoMessages tests that a feed silent since connecting goes stale
+func TestManagerHealthNoMessages(t *testing.T) {
+	now := time.Now()
+	m := NewManager(nil, []config.FeedConfig{{Name: "fresh"}, {Name: "silent"}})
+	m.SetHealthConfig(config.FeedHealthConfig{StaleAfter: 30 * time.Second})
+	m.named["fresh"] = &statsFeed{connected: true, flow: FeedStats{ConnectedSince: now.Add(-time.Second)}}
+	m.named["silent"] = &statsFeed{connected: true, flow: FeedStats{ConnectedSince: now.Add(-time.Minute)}}
+
+	health := m.Health()
+	assert.Equal(t, FeedStateConnected, health[0].State)
+	assert.Equal(t, FeedStateStale, health[1].State)
+	assert.Nil(t, health[1].LastMessage)
+}
+
+// TestFeedStatsRate tests that message rates cover only the rate window
+func TestFeedStatsRate(t *testing.T) {
+	var stats feedStats
+	start := time.Unix(1700000000, 0)
+	for second := 0; second < 20; second++ {
+		for i := 0; i < 5; i++ {
+			stats.recordMessage(start.Add(time.Duration(second) * time.Second))
+		}
+	}
+	now := start.Add(19 * time.Second)
+
+	snapshot := stats.snapshot(now, 10*time.Second)
+	assert.Equal(t, int64(100), snapshot.Messages)
+	assert.Equal(t, 5.0, snapshot.MessageRate)
+	assert.Equal(t, now, snapshot.LastMessage)
+
+	// Idle seconds lower the rate until the window has passed
+	assert.Equal(t, 2.5, stats.snapshot(now.Add(5*time.Second), 10*time.Second).MessageRate)
+	assert.Zero(t, stats.snapshot(now.Add(time.Minute), 10*time.Second).MessageRate)
+}
+
+// TestFeedHealthTracksReconnects tests that a WebSocket feed counts the
+// messages, read errors and reconnects of its connections
+func TestFeedHealthTracksReconnects(t *testing.T) {
+	exchange := newFlakyExchange(t)
+	feed := newTestBinanceFeed(t, exchange.url(), &countingBooks{})
+
+	require.NoError(t, feed.Connect())
+	defer disconnectPromptly(t, feed)
+
+	// Every connection is dropped after one update
+	require.Eventually(t, func() bool {
+		return feed.stats(time.Now(), time.Minute).Reconnects >= 2
+	}, 2*time.Second, time.Millisecond)
+
+	stats := feed.stats(time.Now(), time.Minute)
+	assert.GreaterOrEqual(t, stats.Messages, int64(2))
+	assert.GreaterOrEqual(t, stats.ReadErrors, 2)
+	assert.False(t, stats.LastMessage.IsZero())
+	assert.Positive(t, stats.MessageRate)
+}
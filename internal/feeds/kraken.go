@@ -10,6 +10,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/shopspring/decimal"
 	"velocimex/internal/config"
+	"velocimex/internal/metrics"
 	"velocimex/internal/normalizer"
 )
 
@@ -20,8 +21,9 @@ type KrakenWebSocketFeed struct {
 	conn       *websocket.Conn
 	isConnected bool
 	mu         sync.Mutex
-	done       chan struct{}
+	done       chan struct{} // Closed by Disconnect to stop reading and reconnecting
 	orderBookManager OrderBookManager
+	reader     *readSupervisor
 }
 
 // KrakenMessage represents a Kraken WebSocket message
@@ -46,6 +48,7 @@ func NewKrakenWebSocketFeed(config config.FeedConfig, norm *normalizer.Normalize
 		config:     config,
 		normalizer: norm,
 		done:       make(chan struct{}),
+		reader:     newReadSupervisor(config.Name),
 	}, nil
 }
 
@@ -54,11 +57,41 @@ func (f *KrakenWebSocketFeed) SetOrderBookManager(manager OrderBookManager) {
 	f.orderBookManager = manager
 }
 
+// SetMetrics sets where read errors and reconnects are recorded
+func (f *KrakenWebSocketFeed) SetMetrics(metrics *metrics.Wrapper) {
+	f.reader.setMetrics(metrics)
+}
+
+// stats returns the message flow the feed has seen
+func (f *KrakenWebSocketFeed) stats(now time.Time, rateWindow time.Duration) FeedStats {
+	return f.reader.stats.snapshot(now, rateWindow)
+}
+
 // Connect establishes a connection to Kraken WebSocket
 func (f *KrakenWebSocketFeed) Connect() error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
+	if f.done == nil {
+		f.done = make(chan struct{})
+	}
+	return f.connect(f.done)
+}
+
+// reconnect re-establishes a connection lost to a read error, unless the feed
+// was disconnected while waiting
+func (f *KrakenWebSocketFeed) reconnect(done chan struct{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.done != done {
+		return errFeedDisconnected
+	}
+	return f.connect(done)
+}
+
+// connect dials the WebSocket and starts reading. The caller holds f.mu.
+func (f *KrakenWebSocketFeed) connect(done chan struct{}) error {
 	if f.isConnected {
 		return nil
 	}
@@ -81,7 +114,9 @@ func (f *KrakenWebSocketFeed) Connect() error {
 	}
 
 	// Start message processing
-	go f.processMessages()
+	f.reader.start(conn, done, f.handleMessage, f.handleDisconnection, func() error {
+		return f.reconnect(done)
+	})
 
 	log.Printf("Connected to Kraken WebSocket feed: %s", f.config.Name)
 	return nil
@@ -90,13 +125,14 @@ func (f *KrakenWebSocketFeed) Connect() error {
 // Disconnect closes the WebSocket connection
 func (f *KrakenWebSocketFeed) Disconnect() error {
 	f.mu.Lock()
-	defer f.mu.Unlock()
-
-	if !f.isConnected {
+	if f.done == nil {
+		f.mu.Unlock()
 		return nil
 	}
 
+	// Closing done also stops a pending reconnect
 	close(f.done)
+	f.done = nil
 
 	if f.conn != nil {
 		f.conn.Close()
@@ -104,6 +140,10 @@ func (f *KrakenWebSocketFeed) Disconnect() error {
 	}
 
 	f.isConnected = false
+	f.mu.Unlock()
+
+	// Wait for the read loop to exit; it needs the lock to drop its connection
+	f.reader.wait()
 	log.Printf("Disconnected from Kraken WebSocket feed: %s", f.config.Name)
 	return nil
 }
@@ -162,31 +202,6 @@ func (f *KrakenWebSocketFeed) subscribeToChannels() error {
 	return f.conn.WriteJSON(subscription)
 }
 
-// processMessages processes incoming WebSocket messages
-func (f *KrakenWebSocketFeed) processMessages() {
-	defer func() {
-		if r := recover(); r != nil {
-			log.Printf("Kraken WebSocket panic recovered: %v", r)
-		}
-	}()
-
-	for {
-		select {
-		case <-f.done:
-			return
-		default:
-			_, message, err := f.conn.ReadMessage()
-			if err != nil {
-				log.Printf("Kraken WebSocket read error: %v", err)
-				f.handleDisconnection()
-				return
-			}
-
-			f.handleMessage(message)
-		}
-	}
-}
-
 // handleMessage processes a single WebSocket message
 func (f *KrakenWebSocketFeed) handleMessage(message []byte) {
 	var msg KrakenMessage
@@ -258,12 +273,6 @@ func (f *KrakenWebSocketFeed) handleOrderBookData(msg KrakenMessage) {
 	// Normalize symbol
 	normalizedSymbol := f.normalizer.NormalizeSymbol("kraken", symbol)
 
-	// Update order book if manager is available
-	if f.orderBookManager != nil {
-		f.orderBookManager.UpdateOrderBook("kraken", normalizedSymbol, bids, asks)
-	}
-
-	// Process through normalizer
 	orderBookUpdate := &normalizer.OrderBookUpdate{
 		Exchange:  "kraken",
 		Symbol:    normalizedSymbol,
@@ -273,6 +282,18 @@ func (f *KrakenWebSocketFeed) handleOrderBookData(msg KrakenMessage) {
 		Snapshot:  false,
 	}
 
+	// Drop malformed or stale updates before they reach the book
+	if err := f.normalizer.ValidateOrderBookUpdate(orderBookUpdate); err != nil {
+		log.Printf("Rejected Kraken order book update: %v", err)
+		return
+	}
+
+	// Update order book if manager is available
+	if f.orderBookManager != nil {
+		f.orderBookManager.UpdateOrderBook("kraken", normalizedSymbol, orderBookUpdate.Bids, orderBookUpdate.Asks)
+	}
+
+	// Process through normalizer
 	f.normalizer.ProcessOrderBookUpdate(orderBookUpdate)
 }
 
@@ -311,22 +332,17 @@ func (f *KrakenWebSocketFeed) convertPriceLevels(levels [][]string) []normalizer
 	return result
 }
 
-// handleDisconnection handles WebSocket disconnection
-func (f *KrakenWebSocketFeed) handleDisconnection() {
+// handleDisconnection drops a connection that failed to read. Reconnecting
+// is left to the read supervisor.
+func (f *KrakenWebSocketFeed) handleDisconnection(conn *websocket.Conn) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	f.isConnected = false
-	if f.conn != nil {
-		f.conn.Close()
-		f.conn = nil
+	conn.Close()
+	if f.conn != conn {
+		// Already replaced or disconnected
+		return
 	}
-
-	// Attempt to reconnect after a delay
-	go func() {
-		time.Sleep(5 * time.Second)
-		if err := f.Connect(); err != nil {
-			log.Printf("Failed to reconnect to Kraken: %v", err)
-		}
-	}()
+	f.conn = nil
+	f.isConnected = false
 }
@@ -0,0 +1,26 @@
+package feeds
+
+import (
+	"time"
+
+	"velocimex/internal/normalizer"
+)
+
+// LatencySource supplies artificial latency to add to feed updates
+type LatencySource interface {
+	FeedLatency() time.Duration
+}
+
+// delayedOrderBooks delays every order book update by the injected latency,
+// as if the feed were slow. Updates from a feed stay in order.
+type delayedOrderBooks struct {
+	books   OrderBookManager
+	latency LatencySource
+}
+
+func (d *delayedOrderBooks) UpdateOrderBook(exchange, symbol string, bids, asks []normalizer.PriceLevel) {
+	if latency := d.latency.FeedLatency(); latency > 0 {
+		time.Sleep(latency)
+	}
+	d.books.UpdateOrderBook(exchange, symbol, bids, asks)
+}
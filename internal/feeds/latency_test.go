@@ -0,0 +1,39 @@
+package feeds
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"velocimex/internal/chaos"
+	"velocimex/internal/config"
+	"velocimex/internal/normalizer"
+)
+
+// TestInjectedFeedLatency tests that feeds created after a latency injector is
+// set delay every order book update by the injected latency
+func TestInjectedFeedLatency(t *testing.T) {
+	books := &countingBooks{}
+	injector := chaos.NewLatencyInjector(chaos.LatencyConfig{Enabled: true, FeedLatency: 50 * time.Millisecond})
+
+	manager := NewManager(normalizer.New(), nil)
+	manager.SetOrderBookManager(books)
+	manager.SetLatencyInjector(injector)
+
+	feed, err := manager.newFeed(config.FeedConfig{Name: "binance", URL: "ws://localhost:0", Symbols: []string{"BTCUSDT"}})
+	assert.NoError(t, err)
+	binance := feed.(*BinanceWebSocketFeed)
+
+	levels := []normalizer.PriceLevel{{Price: 100, Volume: 1}}
+	start := time.Now()
+	binance.orderBookManager.UpdateOrderBook("binance", "BTCUSDT", levels, levels)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, int32(1), books.updates.Load())
+
+	// Disabling the injector takes effect on the running feed
+	assert.NoError(t, injector.Set(chaos.LatencyConfig{}))
+	start = time.Now()
+	binance.orderBookManager.UpdateOrderBook("binance", "BTCUSDT", levels, levels)
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, int32(2), books.updates.Load())
+}
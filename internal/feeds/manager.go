@@ -6,6 +6,7 @@ import (
         "sync"
 
         "velocimex/internal/config"
+        "velocimex/internal/metrics"
         "velocimex/internal/normalizer"
 )
 
@@ -22,8 +23,14 @@ type Feed interface {
 type Manager struct {
         normalizer *normalizer.Normalizer
         feeds      []Feed
+        named      map[string]Feed // Connected feeds by config name, for health reports
+        health     config.FeedHealthConfig
         configs    []config.FeedConfig
         orderBookManager OrderBookManager
+        onFailover func(event FailoverEvent)
+        metrics    *metrics.Wrapper
+        latency    LatencySource
+        clock      *ClockSkewMonitor
         mu         sync.Mutex
 }
 
@@ -33,6 +40,8 @@ func NewManager(normalizer *normalizer.Normalizer, configs []config.FeedConfig)
                 normalizer: normalizer,
                 configs:    configs,
                 feeds:      make([]Feed, 0, len(configs)),
+                named:      make(map[string]Feed, len(configs)),
+                clock:      NewClockSkewMonitor(0),
         }
 }
 
@@ -41,53 +50,72 @@ func (m *Manager) SetOrderBookManager(manager OrderBookManager) {
         m.orderBookManager = manager
 }
 
+// SetMetrics sets where feeds record read errors and reconnects
+func (m *Manager) SetMetrics(metrics *metrics.Wrapper) {
+        m.mu.Lock()
+        defer m.mu.Unlock()
+        m.metrics = metrics
+        m.clock.SetMetrics(metrics)
+}
+
+// SetLatencyInjector sets artificial latency added to every order book update
+// from the feeds connected afterwards, for resilience testing
+func (m *Manager) SetLatencyInjector(latency LatencySource) {
+        m.mu.Lock()
+        defer m.mu.Unlock()
+        m.latency = latency
+}
+
+// metricsRecorder is a feed that records metrics
+type metricsRecorder interface {
+        SetMetrics(metrics *metrics.Wrapper)
+}
+
+// SetClockSkewHandler sets a callback invoked when the clock skew against an
+// exchange goes past the feed health threshold and when it comes back
+func (m *Manager) SetClockSkewHandler(handler func(event ClockSkewEvent)) {
+        m.clock.SetWarningHandler(handler)
+}
+
+// ClockSkews returns the clock skew measured against each exchange
+func (m *Manager) ClockSkews() []ClockSkew {
+        return m.clock.Skews()
+}
+
+// SetFailoverHandler sets a callback invoked when a feed fails over to its
+// backup endpoint or fails back to its primary
+func (m *Manager) SetFailoverHandler(handler func(event FailoverEvent)) {
+        m.mu.Lock()
+        defer m.mu.Unlock()
+
+        m.onFailover = handler
+        for _, feed := range m.feeds {
+                if failoverFeed, ok := feed.(*FailoverFeed); ok {
+                        failoverFeed.SetEventHandler(handler)
+                }
+        }
+}
+
 // Connect connects to all configured feeds
 func (m *Manager) Connect() error {
         m.mu.Lock()
         defer m.mu.Unlock()
 
         for _, config := range m.configs {
-                var feed Feed
-                var err error
-
-                // Create the appropriate feed based on the name and type
-                switch config.Name {
-                case "binance":
-                        feed, err = NewBinanceWebSocketFeed(config, m.normalizer)
-                case "coinbase":
-                        feed, err = NewCoinbaseWebSocketFeed(config, m.normalizer)
-                case "kraken":
-                        feed, err = NewKrakenWebSocketFeed(config, m.normalizer)
-                case "nasdaq", "nyse", "nse", "bse", "sp500", "dow":
-                        feed, err = NewStockMarketFeed(config, m.normalizer)
-                default:
-                        switch config.Type {
-                        case "websocket":
-                                feed, err = NewWebSocketFeed(config, m.normalizer)
-                        case "fix":
-                                feed, err = NewFIXFeed(config, m.normalizer)
-                        case "stock":
-                                feed, err = NewStockMarketFeed(config, m.normalizer)
-                        default:
-                                return fmt.Errorf("unsupported feed type: %s", config.Type)
-                        }
-                }
-
+                feed, err := m.newFeed(config)
                 if err != nil {
                         return fmt.Errorf("failed to create feed %s: %v", config.Name, err)
                 }
 
-                // Set order book manager if available
-                if m.orderBookManager != nil {
-                        if binanceFeed, ok := feed.(*BinanceWebSocketFeed); ok {
-                                binanceFeed.SetOrderBookManager(m.orderBookManager)
-                        } else if coinbaseFeed, ok := feed.(*CoinbaseWebSocketFeed); ok {
-                                coinbaseFeed.SetOrderBookManager(m.orderBookManager)
-                        } else if krakenFeed, ok := feed.(*KrakenWebSocketFeed); ok {
-                                krakenFeed.SetOrderBookManager(m.orderBookManager)
-                        } else if stockFeed, ok := feed.(*StockMarketFeed); ok {
-                                stockFeed.SetOrderBookManager(m.orderBookManager)
-                        }
+                // Wrap the feed so it fails over to its backup endpoint
+                if config.BackupURL != "" {
+                        backupConfig := config
+                        backupConfig.URL = config.BackupURL
+                        failoverFeed := NewFailoverFeed(config.Name, feed, func() (Feed, error) {
+                                return m.newFeed(backupConfig)
+                        }, config.FailoverInterval)
+                        failoverFeed.SetEventHandler(m.onFailover)
+                        feed = failoverFeed
                 }
 
                 // Connect to the feed
@@ -105,22 +133,83 @@ func (m *Manager) Connect() error {
                 }
 
                 m.feeds = append(m.feeds, feed)
+                m.named[config.Name] = feed
                 log.Printf("Connected to feed: %s", config.Name)
         }
 
         return nil
 }
 
+// newFeed creates the feed for a config and attaches the order book manager
+func (m *Manager) newFeed(config config.FeedConfig) (Feed, error) {
+        var feed Feed
+        var err error
+
+        // Create the appropriate feed based on the name and type
+        switch config.Name {
+        case "binance":
+                feed, err = NewBinanceWebSocketFeed(config, m.normalizer)
+        case "coinbase":
+                feed, err = NewCoinbaseWebSocketFeed(config, m.normalizer)
+        case "kraken":
+                feed, err = NewKrakenWebSocketFeed(config, m.normalizer)
+        case "nasdaq", "nyse", "nse", "bse", "sp500", "dow":
+                feed, err = NewStockMarketFeed(config, m.normalizer)
+        default:
+                switch config.Type {
+                case "websocket":
+                        feed, err = NewWebSocketFeed(config, m.normalizer)
+                case "fix":
+                        feed, err = NewFIXFeed(config, m.normalizer)
+                case "stock":
+                        feed, err = NewStockMarketFeed(config, m.normalizer)
+                default:
+                        return nil, fmt.Errorf("unsupported feed type: %s", config.Type)
+                }
+        }
+        if err != nil {
+                return nil, err
+        }
+
+        // Set order book manager if available
+        if m.orderBookManager != nil {
+                books := m.orderBookManager
+                if m.latency != nil {
+                        books = &delayedOrderBooks{books: books, latency: m.latency}
+                }
+
+                if binanceFeed, ok := feed.(*BinanceWebSocketFeed); ok {
+                        binanceFeed.SetOrderBookManager(books)
+                } else if coinbaseFeed, ok := feed.(*CoinbaseWebSocketFeed); ok {
+                        coinbaseFeed.SetOrderBookManager(books)
+                } else if krakenFeed, ok := feed.(*KrakenWebSocketFeed); ok {
+                        krakenFeed.SetOrderBookManager(books)
+                } else if stockFeed, ok := feed.(*StockMarketFeed); ok {
+                        stockFeed.SetOrderBookManager(books)
+                }
+        }
+
+        if m.metrics != nil {
+                if recorder, ok := feed.(metricsRecorder); ok {
+                        recorder.SetMetrics(m.metrics)
+                }
+        }
+        if observer, ok := feed.(clockObserver); ok {
+                observer.SetClockSkewMonitor(m.clock)
+        }
+
+        return feed, nil
+}
+
 // Disconnect disconnects from all feeds
 func (m *Manager) Disconnect() {
         m.mu.Lock()
         defer m.mu.Unlock()
 
+        // Feeds are disconnected even when down, to stop reconnecting and failover monitoring
         for _, feed := range m.feeds {
-                if feed.IsConnected() {
-                        if err := feed.Disconnect(); err != nil {
-                                log.Printf("Error disconnecting from feed: %v", err)
-                        }
+                if err := feed.Disconnect(); err != nil {
+                        log.Printf("Error disconnecting from feed: %v", err)
                 }
         }
 }
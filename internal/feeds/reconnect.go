@@ -0,0 +1,146 @@
+package feeds
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"velocimex/internal/metrics"
+)
+
+// Delay bounds between reconnection attempts after a read error
+const (
+	defaultReconnectMinDelay = time.Second
+	defaultReconnectMaxDelay = time.Minute
+)
+
+// errFeedDisconnected is returned by a reconnect attempt when the feed was
+// deliberately disconnected while it was waiting
+var errFeedDisconnected = errors.New("feed disconnected")
+
+// readSupervisor runs a WebSocket feed's read loops and recovers from read
+// errors: it logs them, records a metric, drops the failed connection and
+// reconnects with exponential backoff until the feed is disconnected. Every
+// read loop and reconnect attempt is tracked so none outlives the feed.
+type readSupervisor struct {
+	feed     string
+	metrics  *metrics.Wrapper
+	minDelay time.Duration
+	maxDelay time.Duration
+	stats    feedStats
+	wg       sync.WaitGroup
+}
+
+func newReadSupervisor(feed string) *readSupervisor {
+	return &readSupervisor{
+		feed:     feed,
+		minDelay: defaultReconnectMinDelay,
+		maxDelay: defaultReconnectMaxDelay,
+	}
+}
+
+// setMetrics sets where read errors and reconnects are recorded
+func (s *readSupervisor) setMetrics(metrics *metrics.Wrapper) {
+	s.metrics = metrics
+}
+
+// start runs a read loop for conn. Each message is passed to handle; when
+// reading fails drop is called with the failed connection and reconnect is
+// retried until it succeeds or done is closed. reconnect must start a new
+// read loop through start on success.
+func (s *readSupervisor) start(conn *websocket.Conn, done <-chan struct{}, handle func([]byte), drop func(*websocket.Conn), reconnect func() error) {
+	s.stats.recordConnect(time.Now())
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		err := readLoop(conn, done, func(message []byte) {
+			s.stats.recordMessage(time.Now())
+			handle(message)
+		})
+		drop(conn)
+
+		// A read failing because the feed was disconnected is not an error
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		log.Printf("%s WebSocket read error: %v", s.feed, err)
+		s.stats.recordReadError()
+		if s.metrics != nil {
+			s.metrics.RecordFeedReadError(s.feed)
+		}
+		s.reconnect(done, reconnect)
+	}()
+}
+
+// reconnect retries with exponential backoff until a reconnect succeeds or done is closed
+func (s *readSupervisor) reconnect(done <-chan struct{}, reconnect func() error) {
+	delay := s.minDelay
+	for attempt := 1; ; attempt++ {
+		timer := time.NewTimer(delay)
+		select {
+		case <-done:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		err := reconnect()
+		if errors.Is(err, errFeedDisconnected) {
+			return
+		}
+		if err == nil {
+			log.Printf("Reconnected to %s after %d attempt(s)", s.feed, attempt)
+			s.stats.recordReconnect()
+			if s.metrics != nil {
+				s.metrics.RecordFeedReconnect(s.feed, "success")
+			}
+			return
+		}
+
+		log.Printf("Failed to reconnect to %s (attempt %d): %v", s.feed, attempt, err)
+		if s.metrics != nil {
+			s.metrics.RecordFeedReconnect(s.feed, "failure")
+		}
+		delay *= 2
+		if delay > s.maxDelay {
+			delay = s.maxDelay
+		}
+	}
+}
+
+// wait blocks until every read loop and reconnect attempt has exited
+func (s *readSupervisor) wait() {
+	s.wg.Wait()
+}
+
+// readLoop reads messages until reading fails or done is closed. A panic while
+// handling a message ends the loop with an error rather than killing the
+// goroutine silently, so the connection is replaced.
+func readLoop(conn *websocket.Conn, done <-chan struct{}, handle func([]byte)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic handling message: %v", r)
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			return nil
+		default:
+		}
+
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		handle(message)
+	}
+}
@@ -0,0 +1,188 @@
+package feeds
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/config"
+	"velocimex/internal/metrics"
+	"velocimex/internal/normalizer"
+)
+
+// depthSequence numbers depth updates so none is taken for a duplicate
+var depthSequence atomic.Int64
+
+// depthMessage returns a fresh Binance depth update
+func depthMessage() []byte {
+	last := depthSequence.Add(2)
+	return []byte(fmt.Sprintf(`{"stream":"btcusdt@depth","data":{"e":"depthUpdate","E":%d,"s":"BTCUSDT","U":%d,"u":%d,"b":[["100.0","1.0"]],"a":[["101.0","2.0"]]}}`, time.Now().UnixMilli(), last-1, last))
+}
+
+// flakyExchange is a WebSocket server that sends one depth update on every
+// connection and then either drops it, injecting a read error, or holds it open
+type flakyExchange struct {
+	server      *httptest.Server
+	connections atomic.Int32
+	drop        atomic.Bool
+}
+
+func newFlakyExchange(t *testing.T) *flakyExchange {
+	t.Helper()
+
+	exchange := &flakyExchange{}
+	exchange.drop.Store(true)
+	upgrader := websocket.Upgrader{}
+	exchange.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		exchange.connections.Add(1)
+
+		if err := conn.WriteMessage(websocket.TextMessage, depthMessage()); err != nil {
+			return
+		}
+		if exchange.drop.Load() {
+			return
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(exchange.server.Close)
+	return exchange
+}
+
+func (e *flakyExchange) url() string {
+	return "ws" + strings.TrimPrefix(e.server.URL, "http")
+}
+
+// countingBooks counts order book updates, optionally panicking on the first
+type countingBooks struct {
+	updates    atomic.Int32
+	panicFirst atomic.Bool
+}
+
+func (b *countingBooks) UpdateOrderBook(exchange, symbol string, bids, asks []normalizer.PriceLevel) {
+	if b.updates.Add(1) == 1 && b.panicFirst.Load() {
+		panic("malformed update")
+	}
+}
+
+func newTestBinanceFeed(t *testing.T, url string, books OrderBookManager) *BinanceWebSocketFeed {
+	t.Helper()
+
+	feed, err := NewBinanceWebSocketFeed(config.FeedConfig{Name: "binance", URL: url, Symbols: []string{"BTCUSDT"}}, normalizer.New())
+	require.NoError(t, err)
+	feed.SetOrderBookManager(books)
+	feed.reader.minDelay = 5 * time.Millisecond
+	feed.reader.maxDelay = 20 * time.Millisecond
+	return feed
+}
+
+// disconnectPromptly asserts Disconnect returns, which waits for every read
+// loop and reconnect attempt to exit
+func disconnectPromptly(t *testing.T, feed Feed) {
+	t.Helper()
+
+	done := make(chan struct{})
+	go func() {
+		feed.Disconnect()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Disconnect did not return; read loop or reconnect goroutine leaked")
+	}
+}
+
+// TestFeedReconnectsAfterReadError tests that a dropped connection is replaced
+// and that no reading or reconnecting continues after Disconnect
+func TestFeedReconnectsAfterReadError(t *testing.T) {
+	exchange := newFlakyExchange(t)
+	books := &countingBooks{}
+	m := metrics.New()
+	feed := newTestBinanceFeed(t, exchange.url(), books)
+	feed.SetMetrics(metrics.NewWrapper(m, true))
+
+	require.NoError(t, feed.Connect())
+
+	// Every connection is dropped after one update, so each new connection is a reconnect
+	require.Eventually(t, func() bool { return exchange.connections.Load() >= 3 }, 2*time.Second, time.Millisecond)
+	require.Eventually(t, func() bool { return books.updates.Load() >= 3 }, 2*time.Second, time.Millisecond)
+	assert.GreaterOrEqual(t, testutil.ToFloat64(m.FeedReadErrors.WithLabelValues("binance")), 2.0)
+	assert.GreaterOrEqual(t, testutil.ToFloat64(m.FeedReconnects.WithLabelValues("binance", "success")), 2.0)
+
+	disconnectPromptly(t, feed)
+	assert.False(t, feed.IsConnected())
+
+	connections := exchange.connections.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, connections, exchange.connections.Load(), "reconnected after Disconnect")
+}
+
+// TestFeedRecoversFromHandlerPanic tests that a panic handling a message
+// replaces the connection instead of killing the read loop silently
+func TestFeedRecoversFromHandlerPanic(t *testing.T) {
+	exchange := newFlakyExchange(t)
+	exchange.drop.Store(false)
+	books := &countingBooks{}
+	books.panicFirst.Store(true)
+	feed := newTestBinanceFeed(t, exchange.url(), books)
+
+	require.NoError(t, feed.Connect())
+	require.Eventually(t, func() bool { return exchange.connections.Load() == 2 }, 2*time.Second, time.Millisecond)
+	require.Eventually(t, func() bool { return books.updates.Load() == 2 && feed.IsConnected() }, 2*time.Second, time.Millisecond)
+
+	disconnectPromptly(t, feed)
+}
+
+// TestFeedRetriesWhileExchangeDown tests that failed reconnects are retried
+// with backoff and abandoned once the feed is disconnected
+func TestFeedRetriesWhileExchangeDown(t *testing.T) {
+	exchange := newFlakyExchange(t)
+	m := metrics.New()
+	feed := newTestBinanceFeed(t, exchange.url(), &countingBooks{})
+	feed.SetMetrics(metrics.NewWrapper(m, true))
+
+	require.NoError(t, feed.Connect())
+	exchange.server.Close()
+
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(m.FeedReconnects.WithLabelValues("binance", "failure")) >= 2
+	}, 2*time.Second, time.Millisecond)
+	assert.False(t, feed.IsConnected())
+
+	disconnectPromptly(t, feed)
+}
+
+// TestFeedConnectAfterDisconnect tests that a disconnected feed can connect and read again
+func TestFeedConnectAfterDisconnect(t *testing.T) {
+	exchange := newFlakyExchange(t)
+	exchange.drop.Store(false)
+	books := &countingBooks{}
+	feed := newTestBinanceFeed(t, exchange.url(), books)
+
+	for i := 1; i <= 2; i++ {
+		require.NoError(t, feed.Connect())
+		want := int32(i)
+		require.Eventually(t, func() bool { return books.updates.Load() == want }, 2*time.Second, time.Millisecond)
+		disconnectPromptly(t, feed)
+	}
+
+	// Disconnecting twice is safe
+	assert.NoError(t, feed.Disconnect())
+}
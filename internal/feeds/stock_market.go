@@ -337,12 +337,6 @@ func (f *StockMarketFeed) processStockQuote(quote StockQuote) {
 	// Normalize symbol
 	normalizedSymbol := f.normalizer.NormalizeSymbol(f.config.Name, quote.Symbol)
 
-	// Update order book if manager is available
-	if f.orderBookManager != nil {
-		f.orderBookManager.UpdateOrderBook(f.config.Name, normalizedSymbol, bids, asks)
-	}
-
-	// Process through normalizer
 	orderBookUpdate := &normalizer.OrderBookUpdate{
 		Exchange:  f.config.Name,
 		Symbol:    normalizedSymbol,
@@ -352,6 +346,18 @@ func (f *StockMarketFeed) processStockQuote(quote StockQuote) {
 		Snapshot:  true,
 	}
 
+	// Drop malformed or stale updates before they reach the book
+	if err := f.normalizer.ValidateOrderBookUpdate(orderBookUpdate); err != nil {
+		log.Printf("Rejected %s order book update: %v", f.config.Name, err)
+		return
+	}
+
+	// Update order book if manager is available
+	if f.orderBookManager != nil {
+		f.orderBookManager.UpdateOrderBook(f.config.Name, normalizedSymbol, orderBookUpdate.Bids, orderBookUpdate.Asks)
+	}
+
+	// Process through normalizer
 	f.normalizer.ProcessOrderBookUpdate(orderBookUpdate)
 
 	log.Printf("Updated %s %s: Price=%.2f, Volume=%d", f.config.Name, quote.Symbol, quote.Price, quote.Volume)
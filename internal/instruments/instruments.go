@@ -0,0 +1,112 @@
+package instruments
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrUnknownInstrument is returned when no metadata exists for a symbol
+var ErrUnknownInstrument = errors.New("unknown instrument")
+
+// Instrument holds the contract specification for a canonical symbol
+type Instrument struct {
+	Symbol             string          `json:"symbol" yaml:"symbol"`
+	BaseCurrency       string          `json:"base_currency" yaml:"baseCurrency"`
+	QuoteCurrency      string          `json:"quote_currency" yaml:"quoteCurrency"`
+	TickSize           decimal.Decimal `json:"tick_size" yaml:"tickSize"`                     // Minimum price increment; zero disables the check
+	LotSize            decimal.Decimal `json:"lot_size" yaml:"lotSize"`                       // Minimum quantity increment; zero disables the check
+	MinNotional        decimal.Decimal `json:"min_notional" yaml:"minNotional"`               // Minimum price * quantity * multiplier
+	ContractMultiplier decimal.Decimal `json:"contract_multiplier" yaml:"contractMultiplier"` // Units of base per contract; zero means 1
+}
+
+// Multiplier returns the contract multiplier, defaulting to 1
+func (i *Instrument) Multiplier() decimal.Decimal {
+	if i.ContractMultiplier.IsPositive() {
+		return i.ContractMultiplier
+	}
+	return decimal.NewFromInt(1)
+}
+
+// Notional returns the notional value of a quantity at a price
+func (i *Instrument) Notional(price, quantity decimal.Decimal) decimal.Decimal {
+	return price.Mul(quantity).Mul(i.Multiplier())
+}
+
+// ValidPrice reports whether price is a multiple of the tick size
+func (i *Instrument) ValidPrice(price decimal.Decimal) bool {
+	return !i.TickSize.IsPositive() || price.Mod(i.TickSize).IsZero()
+}
+
+// ValidQuantity reports whether quantity is a multiple of the lot size
+func (i *Instrument) ValidQuantity(quantity decimal.Decimal) bool {
+	return !i.LotSize.IsPositive() || quantity.Mod(i.LotSize).IsZero()
+}
+
+// Validate checks the specification for obviously invalid values
+func (i *Instrument) Validate() error {
+	if i.Symbol == "" {
+		return fmt.Errorf("instrument symbol is required")
+	}
+	if i.TickSize.IsNegative() || i.LotSize.IsNegative() || i.MinNotional.IsNegative() || i.ContractMultiplier.IsNegative() {
+		return fmt.Errorf("instrument %s has negative contract specs", i.Symbol)
+	}
+	return nil
+}
+
+// Store is a thread-safe registry of instrument metadata keyed by canonical symbol
+type Store struct {
+	mu          sync.RWMutex
+	instruments map[string]Instrument
+}
+
+// NewStore creates a store preloaded with the given instruments
+func NewStore(instruments []Instrument) (*Store, error) {
+	s := &Store{instruments: make(map[string]Instrument)}
+	for _, instrument := range instruments {
+		if err := s.Upsert(instrument); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Upsert adds or replaces an instrument's metadata
+func (s *Store) Upsert(instrument Instrument) error {
+	if err := instrument.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.instruments[instrument.Symbol] = instrument
+	return nil
+}
+
+// Get returns a copy of the metadata for a symbol
+func (s *Store) Get(symbol string) (*Instrument, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	instrument, ok := s.instruments[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownInstrument, symbol)
+	}
+	return &instrument, nil
+}
+
+// List returns all instruments sorted by symbol
+func (s *Store) List() []Instrument {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]Instrument, 0, len(s.instruments))
+	for _, instrument := range s.instruments {
+		list = append(list, instrument)
+	}
+	sort.Slice(list, func(a, b int) bool { return list[a].Symbol < list[b].Symbol })
+	return list
+}
@@ -0,0 +1,86 @@
+package instruments
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func btcUSD() Instrument {
+	return Instrument{
+		Symbol:        "BTC/USD",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USD",
+		TickSize:      decimal.RequireFromString("0.5"),
+		LotSize:       decimal.RequireFromString("0.001"),
+		MinNotional:   decimal.NewFromInt(10),
+	}
+}
+
+func TestStoreLookup(t *testing.T) {
+	store, err := NewStore([]Instrument{btcUSD()})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	instrument, err := store.Get("BTC/USD")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if instrument.BaseCurrency != "BTC" || instrument.QuoteCurrency != "USD" {
+		t.Errorf("currencies = %s/%s, want BTC/USD", instrument.BaseCurrency, instrument.QuoteCurrency)
+	}
+	if !instrument.Multiplier().Equal(decimal.NewFromInt(1)) {
+		t.Errorf("default multiplier = %s, want 1", instrument.Multiplier())
+	}
+
+	// Returned metadata is a copy
+	instrument.TickSize = decimal.NewFromInt(100)
+	if again, _ := store.Get("BTC/USD"); !again.TickSize.Equal(decimal.RequireFromString("0.5")) {
+		t.Errorf("store was mutated through returned instrument")
+	}
+
+	if _, err := store.Get("DOGE/USD"); !errors.Is(err, ErrUnknownInstrument) {
+		t.Errorf("Get unknown = %v, want ErrUnknownInstrument", err)
+	}
+
+	if err := store.Upsert(Instrument{Symbol: "ETH/USD"}); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	list := store.List()
+	if len(list) != 2 || list[0].Symbol != "BTC/USD" || list[1].Symbol != "ETH/USD" {
+		t.Errorf("List = %+v, want BTC/USD then ETH/USD", list)
+	}
+}
+
+func TestInstrumentIncrements(t *testing.T) {
+	instrument := btcUSD()
+
+	if !instrument.ValidPrice(decimal.RequireFromString("100.5")) {
+		t.Errorf("100.5 should be on a 0.5 tick")
+	}
+	if instrument.ValidPrice(decimal.RequireFromString("100.25")) {
+		t.Errorf("100.25 should be off a 0.5 tick")
+	}
+	if !instrument.ValidQuantity(decimal.RequireFromString("0.123")) {
+		t.Errorf("0.123 should be on a 0.001 lot")
+	}
+	if instrument.ValidQuantity(decimal.RequireFromString("0.1234")) {
+		t.Errorf("0.1234 should be off a 0.001 lot")
+	}
+
+	instrument.ContractMultiplier = decimal.NewFromInt(10)
+	if notional := instrument.Notional(decimal.NewFromInt(5), decimal.NewFromInt(2)); !notional.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("notional = %s, want 100", notional)
+	}
+}
+
+func TestInstrumentValidate(t *testing.T) {
+	if _, err := NewStore([]Instrument{{}}); err == nil {
+		t.Errorf("expected error for missing symbol")
+	}
+	if _, err := NewStore([]Instrument{{Symbol: "BTC/USD", TickSize: decimal.NewFromInt(-1)}}); err == nil {
+		t.Errorf("expected error for negative tick size")
+	}
+}
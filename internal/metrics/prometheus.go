@@ -19,6 +19,10 @@ type Metrics struct {
 	MarketDataMessages *prometheus.CounterVec
 	MarketDataLatency  prometheus.Histogram
 	FeedConnections    *prometheus.GaugeVec
+	FeedRejects        *prometheus.CounterVec
+	FeedReadErrors     *prometheus.CounterVec
+	FeedReconnects     *prometheus.CounterVec
+	FeedClockSkew      *prometheus.GaugeVec
 	
 	// Order book metrics
 	OrderBookDepth      *prometheus.GaugeVec
@@ -50,6 +54,8 @@ type Metrics struct {
 	// WebSocket metrics
 	WebSocketConnections prometheus.Gauge
 	WebSocketMessages    *prometheus.CounterVec
+	WebSocketBytes       *prometheus.CounterVec
+	WebSocketThrottled   *prometheus.CounterVec
 	
 	// Plugin metrics
 	PluginCount          prometheus.Gauge
@@ -63,6 +69,10 @@ type Metrics struct {
 	BacktestDuration     *prometheus.HistogramVec
 	BacktestResults      *prometheus.GaugeVec
 	
+	// Internal queue metrics
+	QueueDepth           *prometheus.GaugeVec
+	QueueCapacity        *prometheus.GaugeVec
+	
 	// FIX protocol metrics
 	FIXMessages          *prometheus.CounterVec
 	FIXLatency           prometheus.Histogram
@@ -116,6 +126,34 @@ func New() *Metrics {
 			},
 			[]string{"exchange", "status"},
 		),
+		FeedRejects: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "velocimex_feed_messages_rejected_total",
+				Help: "Total number of malformed market data messages rejected",
+			},
+			[]string{"exchange", "reason"},
+		),
+		FeedReadErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "velocimex_feed_read_errors_total",
+				Help: "Total number of feed connections lost to read errors",
+			},
+			[]string{"feed"},
+		),
+		FeedReconnects: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "velocimex_feed_reconnects_total",
+				Help: "Total number of feed reconnection attempts by result",
+			},
+			[]string{"feed", "result"},
+		),
+		FeedClockSkew: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "velocimex_feed_clock_skew_seconds",
+				Help: "Local clock minus exchange message timestamps, including network latency",
+			},
+			[]string{"exchange"},
+		),
 		
 		// Order book metrics
 		OrderBookDepth: prometheus.NewGaugeVec(
@@ -257,6 +295,20 @@ func New() *Metrics {
 			},
 			[]string{"type"},
 		),
+		WebSocketBytes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "velocimex_websocket_bytes_total",
+				Help: "Total bytes of WebSocket messages by topic, as JSON (raw) and as sent to clients (wire)",
+			},
+			[]string{"topic", "kind"},
+		),
+		WebSocketThrottled: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "velocimex_websocket_throttled_total",
+				Help: "Total number of WebSocket messages dropped by topic rate limits",
+			},
+			[]string{"topic"},
+		),
 		
 		// Plugin metrics
 		PluginCount: prometheus.NewGauge(
@@ -305,10 +357,10 @@ func New() *Metrics {
 		BacktestDuration: prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:    "velocimex_backtest_duration_seconds",
-				Help:    "Backtest execution duration in seconds",
-				Buckets: []float64{1, 5, 10, 30, 60, 300, 600, 1800, 3600},
+				Help:    "Backtest execution duration in seconds, in total and by phase",
+				Buckets: []float64{0.001, 0.01, 0.1, 1, 5, 10, 30, 60, 300, 600, 1800, 3600},
 			},
-			[]string{"strategy"},
+			[]string{"strategy", "phase"},
 		),
 		BacktestResults: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
@@ -318,6 +370,22 @@ func New() *Metrics {
 			[]string{"strategy", "metric"},
 		),
 		
+		// Internal queue metrics
+		QueueDepth: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "velocimex_queue_depth",
+				Help: "Current number of enqueued but unprocessed items in internal queues",
+			},
+			[]string{"queue"},
+		),
+		QueueCapacity: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "velocimex_queue_capacity",
+				Help: "Capacity of internal queues",
+			},
+			[]string{"queue"},
+		),
+		
 		// FIX protocol metrics
 		FIXMessages: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
@@ -349,6 +417,10 @@ func New() *Metrics {
 		m.MarketDataMessages,
 		m.MarketDataLatency,
 		m.FeedConnections,
+		m.FeedRejects,
+		m.FeedReadErrors,
+		m.FeedReconnects,
+		m.FeedClockSkew,
 		m.OrderBookDepth,
 		m.OrderBookUpdates,
 		m.OrderBookLatency,
@@ -368,6 +440,8 @@ func New() *Metrics {
 		m.APIErrors,
 		m.WebSocketConnections,
 		m.WebSocketMessages,
+		m.WebSocketBytes,
+		m.WebSocketThrottled,
 		m.PluginCount,
 		m.PluginEvents,
 		m.PluginExecutionTime,
@@ -376,6 +450,8 @@ func New() *Metrics {
 		m.BacktestRuns,
 		m.BacktestDuration,
 		m.BacktestResults,
+		m.QueueDepth,
+		m.QueueCapacity,
 		m.FIXMessages,
 		m.FIXLatency,
 		m.FIXConnections,
@@ -426,6 +502,26 @@ func (m *Metrics) RecordFeedConnection(exchange, status string) {
 	m.FeedConnections.WithLabelValues(exchange, status).Set(1)
 }
 
+// RecordFeedReject records a rejected market data message
+func (m *Metrics) RecordFeedReject(exchange, reason string) {
+	m.FeedRejects.WithLabelValues(exchange, reason).Inc()
+}
+
+// RecordFeedReadError records a feed connection lost to a read error
+func (m *Metrics) RecordFeedReadError(feed string) {
+	m.FeedReadErrors.WithLabelValues(feed).Inc()
+}
+
+// RecordFeedReconnect records a feed reconnection attempt
+func (m *Metrics) RecordFeedReconnect(feed, result string) {
+	m.FeedReconnects.WithLabelValues(feed, result).Inc()
+}
+
+// RecordFeedClockSkew records the measured clock skew against an exchange
+func (m *Metrics) RecordFeedClockSkew(exchange string, skew time.Duration) {
+	m.FeedClockSkew.WithLabelValues(exchange).Set(skew.Seconds())
+}
+
 // RecordOrderBookUpdate records an order book update
 func (m *Metrics) RecordOrderBookUpdate(exchange, symbol string) {
 	m.OrderBookUpdates.WithLabelValues(exchange, symbol).Inc()
@@ -531,6 +627,22 @@ func (m *Metrics) RecordWebSocketMessage(msgType string) {
 	m.WebSocketMessages.WithLabelValues(msgType).Inc()
 }
 
+// RecordWebSocketBytes records bytes of WebSocket messages on a topic
+func (m *Metrics) RecordWebSocketBytes(topic, kind string, bytes int) {
+	m.WebSocketBytes.WithLabelValues(topic, kind).Add(float64(bytes))
+}
+
+// RecordWebSocketThrottled records a WebSocket message dropped by a topic rate limit
+func (m *Metrics) RecordWebSocketThrottled(topic string) {
+	m.WebSocketThrottled.WithLabelValues(topic).Inc()
+}
+
+// RecordQueueDepth records the depth and capacity of an internal queue
+func (m *Metrics) RecordQueueDepth(queue string, depth, capacity int) {
+	m.QueueDepth.WithLabelValues(queue).Set(float64(depth))
+	m.QueueCapacity.WithLabelValues(queue).Set(float64(capacity))
+}
+
 // UpdateUptime updates the uptime metric
 func (m *Metrics) UpdateUptime() {
 	m.UpTime.SetToCurrentTime()
@@ -566,9 +678,14 @@ func (m *Metrics) RecordBacktestRun() {
 	m.BacktestRuns.Inc()
 }
 
-// RecordBacktestDuration records backtest execution duration
+// RecordBacktestDuration records backtest execution duration, as phase "total"
 func (m *Metrics) RecordBacktestDuration(strategy string, duration time.Duration) {
-	m.BacktestDuration.WithLabelValues(strategy).Observe(duration.Seconds())
+	m.BacktestDuration.WithLabelValues(strategy, "total").Observe(duration.Seconds())
+}
+
+// RecordBacktestPhase records the time a backtest run spent in one phase
+func (m *Metrics) RecordBacktestPhase(strategy, phase string, duration time.Duration) {
+	m.BacktestDuration.WithLabelValues(strategy, phase).Observe(duration.Seconds())
 }
 
 // RecordBacktestResult records backtest result metrics
@@ -30,6 +30,8 @@ func TestMetricsMethods(t *testing.T) {
 	m.RecordMarketDataMessage("binance", "BTCUSDT", "trade")
 	m.RecordMarketDataLatency(time.Millisecond)
 	m.RecordFeedConnection("binance", "connected")
+	m.RecordFeedReject("binance", "stale")
+	m.RecordFeedClockSkew("binance", 120*time.Millisecond)
 	
 	// Test order book metrics
 	m.RecordOrderBookUpdate("binance", "BTCUSDT")
@@ -56,6 +58,12 @@ func TestMetricsMethods(t *testing.T) {
 	// Test WebSocket metrics
 	m.RecordWebSocketConnection(10)
 	m.RecordWebSocketMessage("order_update")
+	m.RecordWebSocketBytes("orderbook", "wire", 512)
+	m.RecordWebSocketThrottled("orderbook")
+	
+	// Test backtest metrics
+	m.RecordBacktestDuration("arbitrage", time.Second)
+	m.RecordBacktestPhase("arbitrage", "strategy_eval", time.Millisecond)
 	
 	// Verify metrics are collected
 	assert.NotPanics(t, func() {
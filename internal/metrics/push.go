@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// PushgatewayConfig configures pushing metrics to a Prometheus Pushgateway,
+// for jobs such as backtests that finish before they can be scraped
+type PushgatewayConfig struct {
+	Enabled bool          `json:"enabled" yaml:"enabled"`
+	URL     string        `json:"url" yaml:"url"`         // Pushgateway base URL, e.g. http://localhost:9091
+	Job     string        `json:"job" yaml:"job"`         // Job label the metrics are grouped under, default velocimex_backtest
+	Timeout time.Duration `json:"timeout" yaml:"timeout"` // Timeout for each push, default 10s
+}
+
+// DefaultPushgatewayConfig returns default Pushgateway configuration
+func DefaultPushgatewayConfig() PushgatewayConfig {
+	return PushgatewayConfig{
+		Enabled: false,
+		Job:     "velocimex_backtest",
+		Timeout: 10 * time.Second,
+	}
+}
+
+// Validate checks that an enabled Pushgateway has somewhere to push to
+func (c PushgatewayConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.URL == "" {
+		return fmt.Errorf("pushgateway url is required")
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("pushgateway timeout cannot be negative")
+	}
+	return nil
+}
+
+// BacktestPusher pushes the metrics of completed backtests to a Pushgateway.
+// Each strategy has its own group, which every push replaces, so the gateway
+// holds the latest run of each strategy.
+type BacktestPusher struct {
+	config PushgatewayConfig
+	client *http.Client
+}
+
+// NewBacktestPusher creates a pusher for the given gateway. An unset job or
+// timeout takes its default.
+func NewBacktestPusher(config PushgatewayConfig) *BacktestPusher {
+	defaults := DefaultPushgatewayConfig()
+	if config.Job == "" {
+		config.Job = defaults.Job
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+
+	return &BacktestPusher{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// PushBacktest pushes a run's duration and result metrics, keyed by metric name
+func (p *BacktestPusher) PushBacktest(strategy string, duration time.Duration, results map[string]float64) error {
+	registry := prometheus.NewRegistry()
+
+	durationGauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "velocimex_backtest_duration_seconds",
+		Help: "Duration of the last backtest run in seconds",
+	})
+	completion := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "velocimex_backtest_last_completion_timestamp_seconds",
+		Help: "Unix time the last backtest run completed",
+	})
+	resultGauges := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "velocimex_backtest_results",
+		Help: "Backtest results metrics",
+	}, []string{"metric"})
+	registry.MustRegister(durationGauge, completion, resultGauges)
+
+	durationGauge.Set(duration.Seconds())
+	completion.SetToCurrentTime()
+	for metric, value := range results {
+		resultGauges.WithLabelValues(metric).Set(value)
+	}
+
+	err := push.New(p.config.URL, p.config.Job).
+		Grouping("strategy", strategy).
+		Gatherer(registry).
+		Client(p.client).
+		Push()
+	if err != nil {
+		return fmt.Errorf("failed to push backtest metrics: %w", err)
+	}
+	return nil
+}
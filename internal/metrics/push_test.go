@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockGateway records the pushes made to it
+type mockGateway struct {
+	mu       sync.Mutex
+	paths    []string
+	families map[string]*dto.MetricFamily
+}
+
+func (g *mockGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	decoder := expfmt.NewDecoder(r.Body, expfmt.ResponseFormat(r.Header))
+	families := make(map[string]*dto.MetricFamily)
+	for {
+		family := &dto.MetricFamily{}
+		if err := decoder.Decode(family); err != nil {
+			if err != io.EOF {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			break
+		}
+		families[family.GetName()] = family
+	}
+
+	g.mu.Lock()
+	g.paths = append(g.paths, r.Method+" "+r.URL.Path)
+	g.families = families
+	g.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestPushBacktest(t *testing.T) {
+	gateway := &mockGateway{}
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	pusher := NewBacktestPusher(PushgatewayConfig{Enabled: true, URL: server.URL})
+	err := pusher.PushBacktest("arbitrage", 1500*time.Millisecond, map[string]float64{
+		"total_return_pct": 12.5,
+		"total_trades":     42,
+	})
+	require.NoError(t, err)
+
+	gateway.mu.Lock()
+	defer gateway.mu.Unlock()
+
+	// Each strategy's group is replaced under the default job
+	assert.Equal(t, []string{"PUT /metrics/job/velocimex_backtest/strategy/arbitrage"}, gateway.paths)
+
+	duration := gateway.families["velocimex_backtest_duration_seconds"]
+	require.NotNil(t, duration)
+	assert.Equal(t, 1.5, duration.GetMetric()[0].GetGauge().GetValue())
+
+	completion := gateway.families["velocimex_backtest_last_completion_timestamp_seconds"]
+	require.NotNil(t, completion)
+	assert.InDelta(t, float64(time.Now().Unix()), completion.GetMetric()[0].GetGauge().GetValue(), 5)
+
+	results := gateway.families["velocimex_backtest_results"]
+	require.NotNil(t, results)
+	values := make(map[string]float64)
+	for _, metric := range results.GetMetric() {
+		for _, label := range metric.GetLabel() {
+			if label.GetName() == "metric" {
+				values[label.GetValue()] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+	assert.Equal(t, map[string]float64{"total_return_pct": 12.5, "total_trades": 42}, values)
+}
+
+func TestPushBacktestGatewayError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	pusher := NewBacktestPusher(PushgatewayConfig{Enabled: true, URL: server.URL, Job: "nightly"})
+	assert.Error(t, pusher.PushBacktest("arbitrage", time.Second, nil))
+}
+
+func TestPushgatewayConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultPushgatewayConfig().Validate())
+	assert.Error(t, PushgatewayConfig{Enabled: true}.Validate())
+	assert.NoError(t, PushgatewayConfig{Enabled: true, URL: "http://localhost:9091"}.Validate())
+}
@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultQueueInterval is how often queue depths are sampled when no interval is configured
+const defaultQueueInterval = 5 * time.Second
+
+// QueueDepth is the current depth of an internal queue
+type QueueDepth struct {
+	Queue    string `json:"queue"`
+	Depth    int    `json:"depth"` // Enqueued but not yet processed
+	Capacity int    `json:"capacity"`
+}
+
+// QueueSource reports the depths of the internal queues it owns
+type QueueSource interface {
+	QueueDepths() []QueueDepth
+}
+
+// QueueMonitor periodically records the depths of internal queues as gauges
+// so saturated channels show up before they cause backpressure
+type QueueMonitor struct {
+	metrics  *Wrapper
+	interval time.Duration
+	sources  []QueueSource
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewQueueMonitor creates a monitor sampling queue depths every interval
+func NewQueueMonitor(metrics *Wrapper, interval time.Duration) *QueueMonitor {
+	if interval <= 0 {
+		interval = defaultQueueInterval
+	}
+	return &QueueMonitor{
+		metrics:  metrics,
+		interval: interval,
+	}
+}
+
+// Register adds a source whose queues are sampled
+func (q *QueueMonitor) Register(source QueueSource) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.sources = append(q.sources, source)
+}
+
+// Sample records the current depth of every registered queue and returns them
+func (q *QueueMonitor) Sample() []QueueDepth {
+	q.mu.Lock()
+	sources := append([]QueueSource(nil), q.sources...)
+	q.mu.Unlock()
+
+	var depths []QueueDepth
+	for _, source := range sources {
+		for _, depth := range source.QueueDepths() {
+			if q.metrics != nil {
+				q.metrics.RecordQueueDepth(depth.Queue, depth.Depth, depth.Capacity)
+			}
+			depths = append(depths, depth)
+		}
+	}
+	return depths
+}
+
+// Start begins sampling queue depths in the background
+func (q *QueueMonitor) Start(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.cancel != nil {
+		return fmt.Errorf("queue monitor already running")
+	}
+
+	ctx, q.cancel = context.WithCancel(ctx)
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+
+		ticker := time.NewTicker(q.interval)
+		defer ticker.Stop()
+
+		q.Sample()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				q.Sample()
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops sampling and waits for the monitor to exit
+func (q *QueueMonitor) Stop() {
+	q.mu.Lock()
+	cancel := q.cancel
+	q.cancel = nil
+	q.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		q.wg.Wait()
+	}
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chanSource reports the depth of a buffered channel
+type chanSource struct {
+	name  string
+	queue chan int
+}
+
+func (s *chanSource) QueueDepths() []QueueDepth {
+	return []QueueDepth{{Queue: s.name, Depth: len(s.queue), Capacity: cap(s.queue)}}
+}
+
+func TestQueueMonitorSample(t *testing.T) {
+	m := New()
+	source := &chanSource{name: "orders", queue: make(chan int, 10)}
+	for i := 0; i < 3; i++ {
+		source.queue <- i
+	}
+
+	monitor := NewQueueMonitor(NewWrapper(m, true), time.Hour)
+	monitor.Register(source)
+
+	depths := monitor.Sample()
+	assert.Equal(t, []QueueDepth{{Queue: "orders", Depth: 3, Capacity: 10}}, depths)
+	assert.Equal(t, 3.0, testutil.ToFloat64(m.QueueDepth.WithLabelValues("orders")))
+	assert.Equal(t, 10.0, testutil.ToFloat64(m.QueueCapacity.WithLabelValues("orders")))
+
+	// Draining the queue is reflected on the next sample
+	<-source.queue
+	monitor.Sample()
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.QueueDepth.WithLabelValues("orders")))
+}
+
+func TestQueueMonitorStart(t *testing.T) {
+	m := New()
+	source := &chanSource{name: "updates", queue: make(chan int, 5)}
+	source.queue <- 1
+
+	monitor := NewQueueMonitor(NewWrapper(m, true), 5*time.Millisecond)
+	monitor.Register(source)
+	require.NoError(t, monitor.Start(context.Background()))
+	assert.Error(t, monitor.Start(context.Background()))
+	defer monitor.Stop()
+
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(m.QueueDepth.WithLabelValues("updates")) == 1
+	}, time.Second, time.Millisecond)
+
+	source.queue <- 2
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(m.QueueDepth.WithLabelValues("updates")) == 2
+	}, time.Second, time.Millisecond)
+
+	monitor.Stop()
+	monitor.Stop()
+}
+
+func TestQueueMonitorConcurrentRegister(t *testing.T) {
+	monitor := NewQueueMonitor(NewWrapper(New(), false), time.Millisecond)
+	require.NoError(t, monitor.Start(context.Background()))
+	defer monitor.Stop()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			monitor.Register(&chanSource{name: "q", queue: make(chan int, 1)})
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, monitor.Sample(), 10)
+}
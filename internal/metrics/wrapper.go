@@ -137,6 +137,20 @@ func (w *Wrapper) RecordWebSocketMessage(msgType string) {
 	}
 }
 
+// RecordWebSocketBytes records bytes of WebSocket messages on a topic if metrics are enabled
+func (w *Wrapper) RecordWebSocketBytes(topic, kind string, bytes int) {
+	if w.enabled {
+		w.metrics.RecordWebSocketBytes(topic, kind, bytes)
+	}
+}
+
+// RecordWebSocketThrottled records a throttled WebSocket message if metrics are enabled
+func (w *Wrapper) RecordWebSocketThrottled(topic string) {
+	if w.enabled {
+		w.metrics.RecordWebSocketThrottled(topic)
+	}
+}
+
 // RecordOrderEvent records an order event if metrics are enabled
 func (w *Wrapper) RecordOrderEvent(eventType, status string) {
 	if w.enabled {
@@ -165,6 +179,34 @@ func (w *Wrapper) RecordFeedConnection(feedName, status string) {
 	}
 }
 
+// RecordFeedReject records a rejected feed message if metrics are enabled
+func (w *Wrapper) RecordFeedReject(exchange, reason string) {
+	if w.enabled {
+		w.metrics.RecordFeedReject(exchange, reason)
+	}
+}
+
+// RecordFeedReadError records a feed read error if metrics are enabled
+func (w *Wrapper) RecordFeedReadError(feed string) {
+	if w.enabled {
+		w.metrics.RecordFeedReadError(feed)
+	}
+}
+
+// RecordFeedReconnect records a feed reconnection attempt if metrics are enabled
+func (w *Wrapper) RecordFeedReconnect(feed, result string) {
+	if w.enabled {
+		w.metrics.RecordFeedReconnect(feed, result)
+	}
+}
+
+// RecordFeedClockSkew records the clock skew against an exchange if metrics are enabled
+func (w *Wrapper) RecordFeedClockSkew(exchange string, skew time.Duration) {
+	if w.enabled {
+		w.metrics.RecordFeedClockSkew(exchange, skew)
+	}
+}
+
 // RecordPositionValue records position value if metrics are enabled
 func (w *Wrapper) RecordPositionValue(value float64) {
 	if w.enabled {
@@ -179,6 +221,27 @@ func (w *Wrapper) RecordPositionPNL(pnl float64) {
 	}
 }
 
+// RecordQueueDepth records the depth and capacity of an internal queue if metrics are enabled
+func (w *Wrapper) RecordQueueDepth(queue string, depth, capacity int) {
+	if w.enabled {
+		w.metrics.RecordQueueDepth(queue, depth, capacity)
+	}
+}
+
+// RecordBacktestDuration records a backtest run's duration if metrics are enabled
+func (w *Wrapper) RecordBacktestDuration(strategy string, duration time.Duration) {
+	if w.enabled {
+		w.metrics.RecordBacktestDuration(strategy, duration)
+	}
+}
+
+// RecordBacktestPhase records the time a backtest run spent in a phase if metrics are enabled
+func (w *Wrapper) RecordBacktestPhase(strategy, phase string, duration time.Duration) {
+	if w.enabled {
+		w.metrics.RecordBacktestPhase(strategy, phase, duration)
+	}
+}
+
 // UpdateUptime updates uptime metric if metrics are enabled
 func (w *Wrapper) UpdateUptime() {
 	if w.enabled {
@@ -0,0 +1,118 @@
+package normalizer
+
+import (
+        "fmt"
+        "time"
+)
+
+// lastUpdate is the last order book update accepted for a book
+type lastUpdate struct {
+        sequence    int64
+        timestamp   time.Time
+        fingerprint string
+}
+
+// recentTrades remembers the keys of the last trades accepted for a symbol
+type recentTrades struct {
+        seen  map[string]struct{}
+        order []string
+}
+
+// dedupKey identifies the book or tape a message belongs to
+func dedupKey(exchange, symbol string) string {
+        return exchange + ":" + symbol
+}
+
+// duplicateUpdate reports whether an order book update repeats one already
+// accepted, and otherwise records it. Sequenced updates are duplicates when
+// they do not advance the sequence; a snapshot resets it, so a resync after
+// a reconnect is accepted unless it repeats the last sequence. Unsequenced
+// updates are duplicates when they repeat the last update's timestamp and
+// levels exactly.
+func (v *validator) duplicateUpdate(update *OrderBookUpdate) bool {
+        v.mu.RLock()
+        enabled := v.config.Dedup
+        v.mu.RUnlock()
+        if !enabled {
+                return false
+        }
+
+        fingerprint := ""
+        if update.Sequence == 0 {
+                fingerprint = fmt.Sprint(update.Snapshot, update.Bids, update.Asks)
+        }
+
+        v.dedupMu.Lock()
+        defer v.dedupMu.Unlock()
+
+        key := dedupKey(update.Exchange, update.Symbol)
+        last := v.books[key]
+        if last != nil {
+                switch {
+                case update.Sequence > 0 && update.Snapshot:
+                        if update.Sequence == last.sequence {
+                                return true
+                        }
+                case update.Sequence > 0:
+                        if update.Sequence <= last.sequence {
+                                return true
+                        }
+                case !update.Timestamp.IsZero():
+                        if update.Timestamp.Equal(last.timestamp) && fingerprint == last.fingerprint {
+                                return true
+                        }
+                }
+        } else {
+                last = &lastUpdate{}
+                v.books[key] = last
+        }
+
+        if update.Sequence > 0 {
+                last.sequence = update.Sequence
+        }
+        last.timestamp = update.Timestamp
+        last.fingerprint = fingerprint
+        return false
+}
+
+// duplicateTrade reports whether a trade is among the recent trades already
+// accepted for its symbol, and otherwise records it. Trades are identified
+// by their ID, or without one by their timestamp, price, volume and side;
+// trades with neither an ID nor a timestamp are never treated as duplicates.
+func (v *validator) duplicateTrade(trade *Trade) bool {
+        v.mu.RLock()
+        enabled, window := v.config.Dedup, v.config.DedupWindow
+        v.mu.RUnlock()
+        if !enabled || window <= 0 {
+                return false
+        }
+
+        id := trade.ID
+        if id == "" {
+                if trade.Timestamp.IsZero() {
+                        return false
+                }
+                id = fmt.Sprintf("%d|%v|%v|%s", trade.Timestamp.UnixNano(), trade.Price, trade.Volume, trade.Side)
+        }
+
+        v.dedupMu.Lock()
+        defer v.dedupMu.Unlock()
+
+        key := dedupKey(trade.Exchange, trade.Symbol)
+        recent := v.trades[key]
+        if recent == nil {
+                recent = &recentTrades{seen: make(map[string]struct{})}
+                v.trades[key] = recent
+        }
+        if _, ok := recent.seen[id]; ok {
+                return true
+        }
+
+        recent.seen[id] = struct{}{}
+        recent.order = append(recent.order, id)
+        for len(recent.order) > window {
+                delete(recent.seen, recent.order[0])
+                recent.order = recent.order[1:]
+        }
+        return false
+}
@@ -21,6 +21,7 @@ type Trade struct {
         Side      string    `json:"side"` // "buy" or "sell"
         Timestamp time.Time `json:"timestamp"`
         ID        string    `json:"id"`
+        Sequence  int64     `json:"sequence,omitempty"` // Exchange sequence number, zero if the exchange has none
 }
 
 // OrderBookUpdate represents a normalized order book update
@@ -31,16 +32,31 @@ type OrderBookUpdate struct {
         Asks      []PriceLevel `json:"asks"`
         Timestamp time.Time    `json:"timestamp"`
         Snapshot  bool         `json:"snapshot"`
+        Sequence  int64        `json:"sequence,omitempty"` // Exchange sequence number, zero if the exchange has none
 }
 
 // Normalizer normalizes market data from different exchanges
 type Normalizer struct {
-        // You might add exchange-specific mappings or configuration here
+        symbols   *SymbolMapper
+        validator *validator
 }
 
 // New creates a new normalizer
 func New() *Normalizer {
-        return &Normalizer{}
+        return &Normalizer{
+                symbols:   NewSymbolMapper(nil),
+                validator: newValidator(DefaultValidationConfig()),
+        }
+}
+
+// SetSymbolMappings replaces the exchange -> native -> canonical symbol mappings
+func (n *Normalizer) SetSymbolMappings(mappings map[string]map[string]string) {
+        n.symbols = NewSymbolMapper(mappings)
+}
+
+// Symbols returns the normalizer's symbol mapper
+func (n *Normalizer) Symbols() *SymbolMapper {
+        return n.symbols
 }
 
 // NormalizeTrade normalizes a trade from an exchange
@@ -48,11 +64,11 @@ func (n *Normalizer) NormalizeTrade(exchange, symbol string, data map[string]int
         // This is a simplified implementation
         // In a real system, this would parse exchange-specific trade data
         // and convert it to a standard format
-        
+
         // For now, return a placeholder trade
         return &Trade{
                 Exchange:  exchange,
-                Symbol:    symbol,
+                Symbol:    n.NormalizeSymbol(exchange, symbol),
                 Price:     0,
                 Volume:    0,
                 Side:      "buy",
@@ -66,11 +82,11 @@ func (n *Normalizer) NormalizeOrderBook(exchange, symbol string, data map[string
         // This is a simplified implementation
         // In a real system, this would parse exchange-specific order book data
         // and convert it to a standard format
-        
+
         // For now, return a placeholder order book update
         return &OrderBookUpdate{
                 Exchange:  exchange,
-                Symbol:    symbol,
+                Symbol:    n.NormalizeSymbol(exchange, symbol),
                 Bids:      make([]PriceLevel, 0),
                 Asks:      make([]PriceLevel, 0),
                 Timestamp: time.Now(),
@@ -80,6 +96,11 @@ func (n *Normalizer) NormalizeOrderBook(exchange, symbol string, data map[string
 
 // NormalizeSymbol normalizes a symbol from exchange-specific to standard format
 func (n *Normalizer) NormalizeSymbol(exchange, symbol string) string {
+        // Configured mappings take precedence over the built-in rules
+        if canonical, ok := n.symbols.ToCanonical(exchange, symbol); ok {
+                return canonical
+        }
+
         // Convert exchange-specific symbols to standard format
         switch exchange {
         case "binance":
@@ -121,7 +142,7 @@ func (n *Normalizer) ProcessOrderBookUpdate(update *OrderBookUpdate) {
         // 1. Update the internal order book state
         // 2. Notify subscribers
         // 3. Trigger strategies to evaluate signals
-        
+
         // For now, we'll just log that we received an update
         log.Printf("Received order book update for %s on %s: %d bids, %d asks", 
                 update.Symbol, update.Exchange, len(update.Bids), len(update.Asks))
@@ -0,0 +1,275 @@
+package normalizer
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func testMappings() map[string]map[string]string {
+	return map[string]map[string]string{
+		"binance": {
+			"BTCUSDT": "BTC/USD",
+			"ETHUSDT": "ETH/USD",
+		},
+		"coinbase": {
+			"BTC-USD": "BTC/USD",
+		},
+		"kraken": {
+			"XBTUSD": "BTC/USD",
+		},
+	}
+}
+
+// TestSymbolMapping tests that exchange-native symbols map to a canonical form and back
+func TestSymbolMapping(t *testing.T) {
+	n := New()
+	n.SetSymbolMappings(testMappings())
+
+	tests := []struct {
+		exchange string
+		native   string
+		want     string
+	}{
+		{"binance", "BTCUSDT", "BTC/USD"},
+		{"binance", "btcusdt", "BTC/USD"},
+		{"coinbase", "BTC-USD", "BTC/USD"},
+		{"kraken", "XBTUSD", "BTC/USD"},
+		{"Kraken", "xbtusd", "BTC/USD"},
+		{"binance", "ETHUSDT", "ETH/USD"},
+	}
+
+	for _, tt := range tests {
+		if got := n.NormalizeSymbol(tt.exchange, tt.native); got != tt.want {
+			t.Errorf("NormalizeSymbol(%q, %q) = %q, want %q", tt.exchange, tt.native, got, tt.want)
+		}
+	}
+
+	// Unmapped symbols fall back to the built-in rules
+	if got := n.NormalizeSymbol("coinbase", "ETH-USD"); got != "ETHUSD" {
+		t.Errorf("NormalizeSymbol fallback = %q, want %q", got, "ETHUSD")
+	}
+
+	update := n.NormalizeOrderBook("kraken", "XBTUSD", nil)
+	if update.Symbol != "BTC/USD" {
+		t.Errorf("order book symbol = %q, want %q", update.Symbol, "BTC/USD")
+	}
+
+	natives := map[string]string{
+		"binance":  "BTCUSDT",
+		"coinbase": "BTC-USD",
+		"kraken":   "XBTUSD",
+	}
+	for exchange, want := range natives {
+		got, err := n.Symbols().ToNative(exchange, "BTC/USD")
+		if err != nil {
+			t.Errorf("ToNative(%q) returned error: %v", exchange, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ToNative(%q) = %q, want %q", exchange, got, want)
+		}
+	}
+
+	if _, err := n.Symbols().ToNative("coinbase", "ETH/USD"); err == nil {
+		t.Error("expected error for unmapped canonical symbol")
+	}
+}
+
+// TestValidateOrderBookUpdate tests that malformed and stale updates are rejected or sanitized
+func TestValidateOrderBookUpdate(t *testing.T) {
+	n := New()
+	n.SetValidationConfig(ValidationConfig{MaxMessageAge: time.Second})
+
+	tests := []struct {
+		name   string
+		update *OrderBookUpdate
+		want   error
+	}{
+		{
+			name: "negative price",
+			update: &OrderBookUpdate{Exchange: "binance", Symbol: "BTCUSDT", Timestamp: time.Now(),
+				Bids: []PriceLevel{{Price: -100, Volume: 1}}},
+			want: ErrNoValidLevels,
+		},
+		{
+			name: "NaN and zero sizes",
+			update: &OrderBookUpdate{Exchange: "binance", Symbol: "BTCUSDT", Timestamp: time.Now(),
+				Bids: []PriceLevel{{Price: 100, Volume: math.NaN()}},
+				Asks: []PriceLevel{{Price: 101, Volume: 0}}},
+			want: ErrNoValidLevels,
+		},
+		{
+			name: "stale timestamp",
+			update: &OrderBookUpdate{Exchange: "binance", Symbol: "BTCUSDT", Timestamp: time.Now().Add(-time.Minute),
+				Bids: []PriceLevel{{Price: 100, Volume: 1}}},
+			want: ErrStaleMessage,
+		},
+		{
+			name: "missing symbol",
+			update: &OrderBookUpdate{Exchange: "binance", Timestamp: time.Now(),
+				Bids: []PriceLevel{{Price: 100, Volume: 1}}},
+			want: ErrMissingSymbol,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := n.ValidateOrderBookUpdate(tt.update); !errors.Is(err, tt.want) {
+				t.Errorf("ValidateOrderBookUpdate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	rejects := n.Rejects()
+	if rejects["invalid_levels"] != 2 || rejects["stale"] != 1 || rejects["missing_symbol"] != 1 {
+		t.Errorf("unexpected reject counts: %v", rejects)
+	}
+
+	// Valid levels pass through; malformed ones are dropped
+	update := &OrderBookUpdate{
+		Exchange:  "coinbase",
+		Symbol:    "BTC-USD",
+		Timestamp: time.Now(),
+		Bids:      []PriceLevel{{Price: 100, Volume: 1}, {Price: math.Inf(1), Volume: 1}},
+		Asks:      []PriceLevel{{Price: 101, Volume: 2}, {Price: 102, Volume: -1}},
+	}
+	if err := n.ValidateOrderBookUpdate(update); err != nil {
+		t.Fatalf("valid update rejected: %v", err)
+	}
+	if len(update.Bids) != 1 || update.Bids[0].Price != 100 {
+		t.Errorf("unexpected bids after sanitizing: %v", update.Bids)
+	}
+	if len(update.Asks) != 1 || update.Asks[0].Price != 101 {
+		t.Errorf("unexpected asks after sanitizing: %v", update.Asks)
+	}
+
+	// Disabling the cutoff lets old messages through
+	n.SetValidationConfig(ValidationConfig{})
+	old := &OrderBookUpdate{Exchange: "binance", Symbol: "BTCUSDT", Timestamp: time.Now().Add(-time.Hour),
+		Bids: []PriceLevel{{Price: 100, Volume: 1}}}
+	if err := n.ValidateOrderBookUpdate(old); err != nil {
+		t.Errorf("expected stale check to be disabled, got %v", err)
+	}
+}
+
+// TestValidateTrade tests trade validation
+func TestValidateTrade(t *testing.T) {
+	n := New()
+
+	valid := &Trade{Exchange: "binance", Symbol: "BTCUSDT", Price: 50000, Volume: 0.5, Side: "buy", Timestamp: time.Now()}
+	if err := n.ValidateTrade(valid); err != nil {
+		t.Errorf("valid trade rejected: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		trade Trade
+		want  error
+	}{
+		{"zero price", Trade{Price: 0, Volume: 1, Side: "buy"}, ErrInvalidPrice},
+		{"NaN volume", Trade{Price: 100, Volume: math.NaN(), Side: "sell"}, ErrInvalidVolume},
+		{"bad side", Trade{Price: 100, Volume: 1, Side: "hold"}, ErrInvalidSide},
+		{"stale", Trade{Price: 100, Volume: 1, Side: "buy", Timestamp: time.Now().Add(-time.Hour)}, ErrStaleMessage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trade := tt.trade
+			trade.Exchange = "binance"
+			trade.Symbol = "BTCUSDT"
+			if err := n.ValidateTrade(&trade); !errors.Is(err, tt.want) {
+				t.Errorf("ValidateTrade() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestOrderBookUpdateDedup tests that resent order book updates are rejected
+// so each one reaches the book once
+func TestOrderBookUpdateDedup(t *testing.T) {
+	n := New()
+	now := time.Now()
+	levels := []PriceLevel{{Price: 100, Volume: 1}}
+
+	validate := func(update OrderBookUpdate) error {
+		update.Symbol = "BTCUSDT"
+		update.Bids = append([]PriceLevel(nil), levels...)
+		return n.ValidateOrderBookUpdate(&update)
+	}
+
+	tests := []struct {
+		name   string
+		update OrderBookUpdate
+		want   error
+	}{
+		{"first", OrderBookUpdate{Exchange: "binance", Sequence: 10, Timestamp: now}, nil},
+		{"resent", OrderBookUpdate{Exchange: "binance", Sequence: 10, Timestamp: now}, ErrDuplicateMessage},
+		{"older", OrderBookUpdate{Exchange: "binance", Sequence: 9, Timestamp: now}, ErrDuplicateMessage},
+		{"next", OrderBookUpdate{Exchange: "binance", Sequence: 11, Timestamp: now}, nil},
+		{"other exchange", OrderBookUpdate{Exchange: "coinbase", Sequence: 11, Timestamp: now}, nil},
+		// A resync after reconnect may restart the sequence
+		{"resync snapshot", OrderBookUpdate{Exchange: "binance", Sequence: 3, Snapshot: true, Timestamp: now}, nil},
+		{"resent snapshot", OrderBookUpdate{Exchange: "binance", Sequence: 3, Snapshot: true, Timestamp: now}, ErrDuplicateMessage},
+		{"after resync", OrderBookUpdate{Exchange: "binance", Sequence: 4, Timestamp: now}, nil},
+		// Without sequence numbers, an identical timestamp and levels is a resend
+		{"unsequenced", OrderBookUpdate{Exchange: "kraken", Timestamp: now}, nil},
+		{"unsequenced resent", OrderBookUpdate{Exchange: "kraken", Timestamp: now}, ErrDuplicateMessage},
+		{"unsequenced later", OrderBookUpdate{Exchange: "kraken", Timestamp: now.Add(time.Millisecond)}, nil},
+	}
+
+	applied := 0
+	for _, tt := range tests {
+		err := validate(tt.update)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: ValidateOrderBookUpdate() error = %v, want %v", tt.name, err, tt.want)
+		}
+		if err == nil {
+			applied++
+		}
+	}
+	if applied != 7 {
+		t.Errorf("applied %d updates, want 7", applied)
+	}
+	if got := n.Rejects()["duplicate"]; got != 4 {
+		t.Errorf("duplicate rejects = %d, want 4", got)
+	}
+
+	// With dedup disabled every update is applied
+	n.SetValidationConfig(ValidationConfig{})
+	if err := validate(OrderBookUpdate{Exchange: "binance", Sequence: 4, Timestamp: now}); err != nil {
+		t.Errorf("expected dedup to be disabled, got %v", err)
+	}
+}
+
+// TestTradeDedup tests that resent trades reach the tape once
+func TestTradeDedup(t *testing.T) {
+	n := New()
+	n.SetValidationConfig(ValidationConfig{Dedup: true, DedupWindow: 2})
+	now := time.Now()
+
+	trade := func(id string, at time.Time) *Trade {
+		return &Trade{Exchange: "binance", Symbol: "BTCUSDT", Price: 100, Volume: 1, Side: "buy", Timestamp: at, ID: id}
+	}
+
+	tape := 0
+	for _, tr := range []*Trade{
+		trade("1", now), trade("2", now), trade("1", now), trade("2", now),
+		trade("", now), trade("", now), trade("", now.Add(time.Millisecond)),
+	} {
+		if err := n.ValidateTrade(tr); err == nil {
+			tape++
+		} else if !errors.Is(err, ErrDuplicateMessage) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if tape != 4 {
+		t.Errorf("tape has %d trades, want 4", tape)
+	}
+
+	// Only the most recent trades are remembered
+	if err := n.ValidateTrade(trade("1", now)); err != nil {
+		t.Errorf("expected trade 1 to have left the dedup window, got %v", err)
+	}
+}
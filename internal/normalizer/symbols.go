@@ -0,0 +1,66 @@
+package normalizer
+
+import (
+        "fmt"
+        "strings"
+        "sync"
+)
+
+// SymbolMapper translates exchange-native symbols to canonical internal symbols and back
+type SymbolMapper struct {
+        mu          sync.RWMutex
+        toCanonical map[string]map[string]string // exchange -> native -> canonical
+        toNative    map[string]map[string]string // exchange -> canonical -> native
+}
+
+// NewSymbolMapper creates a symbol mapper from exchange -> native -> canonical mappings
+func NewSymbolMapper(mappings map[string]map[string]string) *SymbolMapper {
+        m := &SymbolMapper{
+                toCanonical: make(map[string]map[string]string),
+                toNative:    make(map[string]map[string]string),
+        }
+
+        for exchange, symbols := range mappings {
+                for native, canonical := range symbols {
+                        m.AddMapping(exchange, native, canonical)
+                }
+        }
+
+        return m
+}
+
+// AddMapping registers a native symbol for an exchange and its canonical symbol
+func (m *SymbolMapper) AddMapping(exchange, native, canonical string) {
+        m.mu.Lock()
+        defer m.mu.Unlock()
+
+        exchange = strings.ToLower(exchange)
+        if m.toCanonical[exchange] == nil {
+                m.toCanonical[exchange] = make(map[string]string)
+                m.toNative[exchange] = make(map[string]string)
+        }
+
+        m.toCanonical[exchange][strings.ToUpper(native)] = canonical
+        m.toNative[exchange][canonical] = native
+}
+
+// ToCanonical returns the canonical symbol for an exchange-native symbol
+func (m *SymbolMapper) ToCanonical(exchange, native string) (string, bool) {
+        m.mu.RLock()
+        defer m.mu.RUnlock()
+
+        canonical, ok := m.toCanonical[strings.ToLower(exchange)][strings.ToUpper(native)]
+        return canonical, ok
+}
+
+// ToNative returns the exchange-native symbol for a canonical symbol
+func (m *SymbolMapper) ToNative(exchange, canonical string) (string, error) {
+        m.mu.RLock()
+        defer m.mu.RUnlock()
+
+        native, ok := m.toNative[strings.ToLower(exchange)][canonical]
+        if !ok {
+                return "", fmt.Errorf("no %s symbol mapped for %s", exchange, canonical)
+        }
+        return native, nil
+}
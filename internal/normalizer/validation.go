@@ -0,0 +1,189 @@
+package normalizer
+
+import (
+        "errors"
+        "fmt"
+        "math"
+        "sync"
+        "time"
+
+        "velocimex/internal/metrics"
+)
+
+// Feed message validation errors
+var (
+        ErrMissingSymbol    = errors.New("message has no symbol")
+        ErrStaleMessage     = errors.New("message timestamp is stale")
+        ErrInvalidPrice     = errors.New("invalid price")
+        ErrInvalidVolume    = errors.New("invalid volume")
+        ErrInvalidSide      = errors.New("invalid trade side")
+        ErrNoValidLevels    = errors.New("no valid price levels")
+        ErrDuplicateMessage = errors.New("duplicate message")
+)
+
+// ValidationConfig controls how feed messages are validated
+type ValidationConfig struct {
+        // MaxMessageAge rejects messages whose timestamp is older than this; zero disables the check
+        MaxMessageAge time.Duration `yaml:"maxMessageAge"`
+        // Dedup drops messages an exchange resends, such as after a reconnect
+        Dedup bool `yaml:"dedup"`
+        // DedupWindow is the number of recent trades per symbol remembered for dedup
+        DedupWindow int `yaml:"dedupWindow"`
+}
+
+// DefaultValidationConfig returns the default validation configuration
+func DefaultValidationConfig() ValidationConfig {
+        return ValidationConfig{
+                MaxMessageAge: 5 * time.Second,
+                Dedup:         true,
+                DedupWindow:   1000,
+        }
+}
+
+// validator validates and sanitizes normalized feed messages
+type validator struct {
+        mu      sync.RWMutex
+        config  ValidationConfig
+        metrics *metrics.Wrapper
+        rejects map[string]uint64
+
+        // Dedup state, keyed by exchange and symbol
+        dedupMu sync.Mutex
+        books   map[string]*lastUpdate
+        trades  map[string]*recentTrades
+}
+
+func newValidator(config ValidationConfig) *validator {
+        return &validator{
+                config:  config,
+                rejects: make(map[string]uint64),
+                books:   make(map[string]*lastUpdate),
+                trades:  make(map[string]*recentTrades),
+        }
+}
+
+// SetValidationConfig replaces the feed validation configuration
+func (n *Normalizer) SetValidationConfig(config ValidationConfig) {
+        n.validator.mu.Lock()
+        defer n.validator.mu.Unlock()
+        n.validator.config = config
+}
+
+// SetMetrics sets the metrics wrapper used to record rejected messages
+func (n *Normalizer) SetMetrics(metrics *metrics.Wrapper) {
+        n.validator.mu.Lock()
+        defer n.validator.mu.Unlock()
+        n.validator.metrics = metrics
+}
+
+// Rejects returns the number of rejected messages by reason
+func (n *Normalizer) Rejects() map[string]uint64 {
+        n.validator.mu.RLock()
+        defer n.validator.mu.RUnlock()
+
+        rejects := make(map[string]uint64, len(n.validator.rejects))
+        for reason, count := range n.validator.rejects {
+                rejects[reason] = count
+        }
+        return rejects
+}
+
+// ValidateOrderBookUpdate checks an order book update before it reaches the book.
+// Individual malformed levels are dropped; the whole update is rejected when it is
+// stale, has no symbol, contains no usable levels or, with dedup, repeats an update
+// already accepted.
+func (n *Normalizer) ValidateOrderBookUpdate(update *OrderBookUpdate) error {
+        if update.Symbol == "" {
+                return n.validator.reject(update.Exchange, "missing_symbol", ErrMissingSymbol)
+        }
+        if err := n.validator.checkTimestamp(update.Exchange, update.Timestamp); err != nil {
+                return err
+        }
+
+        received := len(update.Bids) + len(update.Asks)
+        update.Bids = sanitizeLevels(update.Bids)
+        update.Asks = sanitizeLevels(update.Asks)
+
+        if received > 0 && len(update.Bids)+len(update.Asks) == 0 {
+                return n.validator.reject(update.Exchange, "invalid_levels", fmt.Errorf("%w for %s", ErrNoValidLevels, update.Symbol))
+        }
+
+        if n.validator.duplicateUpdate(update) {
+                return n.validator.reject(update.Exchange, "duplicate", fmt.Errorf("%w: order book update for %s", ErrDuplicateMessage, update.Symbol))
+        }
+
+        return nil
+}
+
+// ValidateTrade checks a trade before it is processed. With dedup, a trade
+// already accepted is rejected so it reaches the tape once.
+func (n *Normalizer) ValidateTrade(trade *Trade) error {
+        if trade.Symbol == "" {
+                return n.validator.reject(trade.Exchange, "missing_symbol", ErrMissingSymbol)
+        }
+        if !validPositive(trade.Price) {
+                return n.validator.reject(trade.Exchange, "invalid_price", fmt.Errorf("%w: %v for %s", ErrInvalidPrice, trade.Price, trade.Symbol))
+        }
+        if !validPositive(trade.Volume) {
+                return n.validator.reject(trade.Exchange, "invalid_volume", fmt.Errorf("%w: %v for %s", ErrInvalidVolume, trade.Volume, trade.Symbol))
+        }
+        if trade.Side != "buy" && trade.Side != "sell" {
+                return n.validator.reject(trade.Exchange, "invalid_side", fmt.Errorf("%w: %q for %s", ErrInvalidSide, trade.Side, trade.Symbol))
+        }
+        if err := n.validator.checkTimestamp(trade.Exchange, trade.Timestamp); err != nil {
+                return err
+        }
+
+        if n.validator.duplicateTrade(trade) {
+                return n.validator.reject(trade.Exchange, "duplicate", fmt.Errorf("%w: trade %s for %s", ErrDuplicateMessage, trade.ID, trade.Symbol))
+        }
+
+        return nil
+}
+
+// checkTimestamp rejects messages older than the staleness cutoff
+func (v *validator) checkTimestamp(exchange string, timestamp time.Time) error {
+        v.mu.RLock()
+        maxAge := v.config.MaxMessageAge
+        v.mu.RUnlock()
+
+        if maxAge <= 0 || timestamp.IsZero() {
+                return nil
+        }
+
+        if age := time.Since(timestamp); age > maxAge {
+                return v.reject(exchange, "stale", fmt.Errorf("%w: %s old", ErrStaleMessage, age.Truncate(time.Millisecond)))
+        }
+
+        return nil
+}
+
+// reject counts a rejected message and returns the error
+func (v *validator) reject(exchange, reason string, err error) error {
+        v.mu.Lock()
+        v.rejects[reason]++
+        metrics := v.metrics
+        v.mu.Unlock()
+
+        if metrics != nil {
+                metrics.RecordFeedReject(exchange, reason)
+        }
+
+        return err
+}
+
+// sanitizeLevels drops levels with non-positive or non-finite prices and volumes
+func sanitizeLevels(levels []PriceLevel) []PriceLevel {
+        result := make([]PriceLevel, 0, len(levels))
+        for _, level := range levels {
+                if validPositive(level.Price) && validPositive(level.Volume) {
+                        result = append(result, level)
+                }
+        }
+        return result
+}
+
+// validPositive reports whether v is a finite, strictly positive number
+func validPositive(v float64) bool {
+        return !math.IsNaN(v) && !math.IsInf(v, 0) && v > 0
+}
@@ -0,0 +1,26 @@
+// Package numeric holds small decimal helpers shared across packages.
+//
+// Convention: values named *Pct or *Percent are percentages (5 means 5%),
+// while risk limits and drawdowns are fractions (0.05 means 5%).
+package numeric
+
+import "github.com/shopspring/decimal"
+
+var hundred = decimal.NewFromInt(100)
+
+// FractionChange returns the relative change from one value to another as a
+// fraction, e.g. 100 -> 105 is 0.05. A zero base yields zero rather than
+// dividing by zero.
+func FractionChange(from, to decimal.Decimal) decimal.Decimal {
+	if from.IsZero() {
+		return decimal.Zero
+	}
+	return to.Sub(from).Div(from.Abs())
+}
+
+// PercentChange returns the relative change from one value to another in
+// percent, e.g. 100 -> 105 is 5. A zero base yields zero rather than
+// dividing by zero.
+func PercentChange(from, to decimal.Decimal) decimal.Decimal {
+	return FractionChange(from, to).Mul(hundred)
+}
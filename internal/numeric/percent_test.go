@@ -0,0 +1,38 @@
+package numeric
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPercentChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		percent  string
+		fraction string
+	}{
+		{name: "gain", from: "100", to: "105", percent: "5", fraction: "0.05"},
+		{name: "loss", from: "200", to: "150", percent: "-25", fraction: "-0.25"},
+		{name: "unchanged", from: "42.5", to: "42.5", percent: "0", fraction: "0"},
+		{name: "zero base", from: "0", to: "10", percent: "0", fraction: "0"},
+		{name: "negative base gain", from: "-50", to: "-25", percent: "50", fraction: "0.5"},
+		{name: "negative base loss", from: "-50", to: "-100", percent: "-100", fraction: "-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := decimal.RequireFromString(tt.from)
+			to := decimal.RequireFromString(tt.to)
+
+			if got := PercentChange(from, to); !got.Equal(decimal.RequireFromString(tt.percent)) {
+				t.Errorf("PercentChange(%s, %s) = %s, want %s", tt.from, tt.to, got, tt.percent)
+			}
+			if got := FractionChange(from, to); !got.Equal(decimal.RequireFromString(tt.fraction)) {
+				t.Errorf("FractionChange(%s, %s) = %s, want %s", tt.from, tt.to, got, tt.fraction)
+			}
+		})
+	}
+}
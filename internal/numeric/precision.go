@@ -0,0 +1,63 @@
+package numeric
+
+import (
+	"sync/atomic"
+
+	"github.com/shopspring/decimal"
+)
+
+// Default precision settings
+const (
+	DefaultDivisionPrecision = 16 // shopspring/decimal's own default
+	DefaultRoundingPlaces    = 8
+)
+
+// PrecisionConfig sets process-wide decimal precision
+type PrecisionConfig struct {
+	// DivisionPrecision is the number of decimal places kept by Div when the quotient does not terminate
+	DivisionPrecision int32 `yaml:"divisionPrecision"`
+	// RoundingPlaces is the number of decimal places PnL and metrics are rounded to by Round
+	RoundingPlaces int32 `yaml:"roundingPlaces"`
+}
+
+// DefaultPrecisionConfig returns default decimal precision configuration
+func DefaultPrecisionConfig() PrecisionConfig {
+	return PrecisionConfig{
+		DivisionPrecision: DefaultDivisionPrecision,
+		RoundingPlaces:    DefaultRoundingPlaces,
+	}
+}
+
+var roundingPlaces atomic.Int32
+
+func init() {
+	roundingPlaces.Store(DefaultRoundingPlaces)
+}
+
+// ApplyPrecision sets the division precision and rounding places used across
+// all packages; zero values keep the defaults. decimal.DivisionPrecision is a
+// plain package variable, so this must be called at startup before any
+// decimal arithmetic runs concurrently.
+func ApplyPrecision(config PrecisionConfig) {
+	if config.DivisionPrecision <= 0 {
+		config.DivisionPrecision = DefaultDivisionPrecision
+	}
+	if config.RoundingPlaces <= 0 {
+		config.RoundingPlaces = DefaultRoundingPlaces
+	}
+
+	decimal.DivisionPrecision = int(config.DivisionPrecision)
+	roundingPlaces.Store(config.RoundingPlaces)
+}
+
+// RoundingPlaces returns the number of decimal places Round rounds to
+func RoundingPlaces() int32 {
+	return roundingPlaces.Load()
+}
+
+// Round rounds a value to the configured places, half away from zero. It is
+// applied where PnL and metrics are calculated so results are consistent
+// whatever chain of divisions produced them.
+func Round(value decimal.Decimal) decimal.Decimal {
+	return value.Round(roundingPlaces.Load())
+}
@@ -0,0 +1,58 @@
+package numeric
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestApplyPrecision(t *testing.T) {
+	t.Cleanup(func() { ApplyPrecision(DefaultPrecisionConfig()) })
+
+	ApplyPrecision(PrecisionConfig{DivisionPrecision: 4, RoundingPlaces: 2})
+
+	third := decimal.NewFromInt(1).Div(decimal.NewFromInt(3))
+	if want := decimal.RequireFromString("0.3333"); !third.Equal(want) {
+		t.Errorf("1/3 = %s, want %s", third, want)
+	}
+	twoThirds := decimal.NewFromInt(2).Div(decimal.NewFromInt(3))
+	if want := decimal.RequireFromString("0.6667"); !twoThirds.Equal(want) {
+		t.Errorf("2/3 = %s, want %s", twoThirds, want)
+	}
+	if got := RoundingPlaces(); got != 2 {
+		t.Errorf("RoundingPlaces() = %d, want 2", got)
+	}
+	if got, want := Round(twoThirds), decimal.RequireFromString("0.67"); !got.Equal(want) {
+		t.Errorf("Round(%s) = %s, want %s", twoThirds, got, want)
+	}
+
+	// Zero values restore the defaults
+	ApplyPrecision(PrecisionConfig{})
+	if decimal.DivisionPrecision != DefaultDivisionPrecision {
+		t.Errorf("DivisionPrecision = %d, want %d", decimal.DivisionPrecision, DefaultDivisionPrecision)
+	}
+	if got := RoundingPlaces(); got != DefaultRoundingPlaces {
+		t.Errorf("RoundingPlaces() = %d, want %d", got, DefaultRoundingPlaces)
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "long quotient", value: "0.333333333333333333", want: "0.33333333"},
+		{name: "half away from zero", value: "1.000000005", want: "1.00000001"},
+		{name: "negative", value: "-1.000000005", want: "-1.00000001"},
+		{name: "already rounded", value: "42.5", want: "42.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Round(decimal.RequireFromString(tt.value)); !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("Round(%s) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package orderbook
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"velocimex/internal/normalizer"
+)
+
+// CoalesceLevels merges price levels into bands of the given width, summing
+// the volume within each band. Bids are banded down and asks up to the band
+// edge, so a band never shows a better price than any level in it. Levels
+// must be sorted best first; bands are returned in the same order.
+func CoalesceLevels(levels []normalizer.PriceLevel, width float64, bids bool) ([]normalizer.PriceLevel, error) {
+	if width <= 0 {
+		return nil, fmt.Errorf("band width must be positive")
+	}
+
+	// Band edges are computed in decimal so e.g. 100.3 lands in the 100.3 band of width 0.1
+	band := decimal.NewFromFloat(width)
+	banded := make([]normalizer.PriceLevel, 0, len(levels))
+	for _, level := range levels {
+		bands := decimal.NewFromFloat(level.Price).Div(band)
+		if bids {
+			bands = bands.Floor()
+		} else {
+			bands = bands.Ceil()
+		}
+		price := bands.Mul(band).InexactFloat64()
+
+		if last := len(banded) - 1; last >= 0 && banded[last].Price == price {
+			banded[last].Volume += level.Volume
+			continue
+		}
+		banded = append(banded, normalizer.PriceLevel{Price: price, Volume: level.Volume})
+	}
+	return banded, nil
+}
+
+// GetBandedDepth returns the top n bands of each side of the book, with
+// levels coalesced into bands of the given width
+func (b *OrderBook) GetBandedDepth(width float64, n int) ([]normalizer.PriceLevel, []normalizer.PriceLevel, error) {
+	bidLevels, askLevels := b.levels()
+
+	bids, err := CoalesceLevels(bidLevels, width, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	asks, err := CoalesceLevels(askLevels, width, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(bids) > n {
+		bids = bids[:n]
+	}
+	if len(asks) > n {
+		asks = asks[:n]
+	}
+	return bids, asks, nil
+}
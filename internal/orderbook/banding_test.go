@@ -0,0 +1,66 @@
+package orderbook
+
+import (
+	"reflect"
+	"testing"
+
+	"velocimex/internal/normalizer"
+)
+
+func TestCoalesceLevels(t *testing.T) {
+	bids := []normalizer.PriceLevel{{Price: 100.9, Volume: 1}, {Price: 100.3, Volume: 2}, {Price: 100, Volume: 0.5}, {Price: 98.7, Volume: 4}}
+	asks := []normalizer.PriceLevel{{Price: 101.1, Volume: 1}, {Price: 101.9, Volume: 3}, {Price: 102, Volume: 1}, {Price: 103.4, Volume: 2}}
+
+	// Bids band down and asks band up, so 100.9, 100.3 and 100 share the 100 bid band
+	gotBids, err := CoalesceLevels(bids, 1, true)
+	if err != nil {
+		t.Fatalf("CoalesceLevels bids: %v", err)
+	}
+	wantBids := []normalizer.PriceLevel{{Price: 100, Volume: 3.5}, {Price: 98, Volume: 4}}
+	if !reflect.DeepEqual(gotBids, wantBids) {
+		t.Errorf("bids = %v, want %v", gotBids, wantBids)
+	}
+
+	gotAsks, err := CoalesceLevels(asks, 1, false)
+	if err != nil {
+		t.Fatalf("CoalesceLevels asks: %v", err)
+	}
+	wantAsks := []normalizer.PriceLevel{{Price: 102, Volume: 5}, {Price: 104, Volume: 2}}
+	if !reflect.DeepEqual(gotAsks, wantAsks) {
+		t.Errorf("asks = %v, want %v", gotAsks, wantAsks)
+	}
+
+	// Fractional widths land on exact band edges
+	gotBids, err = CoalesceLevels([]normalizer.PriceLevel{{Price: 100.3, Volume: 1}, {Price: 100.27, Volume: 1}, {Price: 100.2, Volume: 1}}, 0.1, true)
+	if err != nil {
+		t.Fatalf("CoalesceLevels fractional: %v", err)
+	}
+	wantBids = []normalizer.PriceLevel{{Price: 100.3, Volume: 1}, {Price: 100.2, Volume: 2}}
+	if !reflect.DeepEqual(gotBids, wantBids) {
+		t.Errorf("fractional bids = %v, want %v", gotBids, wantBids)
+	}
+
+	if _, err := CoalesceLevels(bids, 0, true); err == nil {
+		t.Error("expected an error for a zero band width")
+	}
+}
+
+func TestGetBandedDepth(t *testing.T) {
+	book := newTestBook(
+		[]normalizer.PriceLevel{{Price: 99.5, Volume: 1}, {Price: 99, Volume: 1}, {Price: 98.5, Volume: 1}, {Price: 97, Volume: 1}},
+		[]normalizer.PriceLevel{{Price: 100.5, Volume: 2}, {Price: 101, Volume: 2}, {Price: 103, Volume: 2}},
+	)
+
+	bids, asks, err := book.GetBandedDepth(1, 2)
+	if err != nil {
+		t.Fatalf("GetBandedDepth: %v", err)
+	}
+	wantBids := []normalizer.PriceLevel{{Price: 99, Volume: 2}, {Price: 98, Volume: 1}}
+	wantAsks := []normalizer.PriceLevel{{Price: 101, Volume: 4}, {Price: 103, Volume: 2}}
+	if !reflect.DeepEqual(bids, wantBids) {
+		t.Errorf("bids = %v, want %v", bids, wantBids)
+	}
+	if !reflect.DeepEqual(asks, wantAsks) {
+		t.Errorf("asks = %v, want %v", asks, wantAsks)
+	}
+}
@@ -14,6 +14,9 @@ type OrderBook struct {
 	Timestamp time.Time
 	Bids      []normalizer.PriceLevel
 	Asks      []normalizer.PriceLevel
+	stale     bool // Restored from a snapshot and not yet updated by a feed
+	updates   []time.Time // Times of the most recent updates, oldest first
+	stats     BookStats   // Derived from the levels when they are replaced
 	mu        sync.RWMutex
 }
 
@@ -29,10 +32,18 @@ func NewOrderBook(symbol string) *OrderBook {
 
 // Update updates the order book with new data
 func (b *OrderBook) Update(bids, asks []normalizer.PriceLevel) {
+	b.update(bids, asks)
+}
+
+// update replaces the book's levels and returns the top of book before and after
+func (b *OrderBook) update(bids, asks []normalizer.PriceLevel) (topOfBook, topOfBook) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	
+	before := b.top()
 	b.Timestamp = time.Now()
+	b.stale = false
+	b.recordUpdate(b.Timestamp)
 	
 	// Sort bids (highest first)
 	sort.Slice(bids, func(i, j int) bool {
@@ -46,6 +57,8 @@ func (b *OrderBook) Update(bids, asks []normalizer.PriceLevel) {
 	
 	b.Bids = bids
 	b.Asks = asks
+	b.stats = deriveStats(bids, asks)
+	return before, b.top()
 }
 
 // GetDepth returns the top N levels of the order book
@@ -95,6 +108,15 @@ func (b *OrderBook) GetTimestamp() time.Time {
 	return b.Timestamp
 }
 
+// IsStale reports whether the book was restored from a snapshot and has not
+// been updated since
+func (b *OrderBook) IsStale() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	
+	return b.stale
+}
+
 // GetSpread returns the spread of the order book
 func (b *OrderBook) GetSpread() float64 {
 	b.mu.RLock()
@@ -0,0 +1,99 @@
+package orderbook
+
+import (
+	"fmt"
+	"time"
+
+	"velocimex/internal/normalizer"
+)
+
+// CoalesceConfig batches bursts of order book updates. Each update replaces
+// a book's levels, so within a window only the latest update of each book is
+// applied: the book is sorted, its stats derived and top-of-book handlers
+// notified once per window rather than once per update.
+type CoalesceConfig struct {
+	// Window holds a book's updates for this long after the first; zero applies every update at once
+	Window time.Duration `json:"window" yaml:"window"`
+}
+
+// Validate checks the coalescing window
+func (c CoalesceConfig) Validate() error {
+	if c.Window < 0 {
+		return fmt.Errorf("order book coalescing window cannot be negative")
+	}
+	return nil
+}
+
+// pendingUpdate is the latest update of a book waiting for its window to end
+type pendingUpdate struct {
+	exchange  string
+	symbol    string
+	bids      []normalizer.PriceLevel
+	asks      []normalizer.PriceLevel
+	coalesced int // Updates replaced by a later one in the window
+	timer     *time.Timer
+}
+
+// SetCoalesceConfig sets how order book updates are coalesced. Updates
+// already held are applied when their windows end.
+func (m *Manager) SetCoalesceConfig(config CoalesceConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.coalesce = config
+}
+
+// CoalescedUpdates returns how many updates were dropped for a later update
+// of the same book in its window
+func (m *Manager) CoalescedUpdates() int64 {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+	return m.coalesced
+}
+
+// holdUpdate keeps an update as its book's latest in the current window,
+// opening a window if none is open
+func (m *Manager) holdUpdate(exchange, symbol string, bids, asks []normalizer.PriceLevel, window time.Duration) {
+	key := fmt.Sprintf("%s:%s", exchange, symbol)
+
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+
+	pending, ok := m.pending[key]
+	if !ok {
+		pending = &pendingUpdate{exchange: exchange, symbol: symbol}
+		m.pending[key] = pending
+		pending.timer = time.AfterFunc(window, func() { m.flushUpdate(key) })
+	} else {
+		pending.coalesced++
+		m.coalesced++
+	}
+	pending.bids, pending.asks = bids, asks
+}
+
+// flushUpdate applies the held update of a book, if any
+func (m *Manager) flushUpdate(key string) {
+	m.pendingMu.Lock()
+	pending, ok := m.pending[key]
+	delete(m.pending, key)
+	m.pendingMu.Unlock()
+
+	if ok {
+		// Stop the window's timer when flushed early, so it cannot cut a later window short
+		pending.timer.Stop()
+		m.applyUpdate(pending.exchange, pending.symbol, pending.bids, pending.asks)
+	}
+}
+
+// Flush applies every held update without waiting for its window to end
+func (m *Manager) Flush() {
+	m.pendingMu.Lock()
+	keys := make([]string, 0, len(m.pending))
+	for key := range m.pending {
+		keys = append(keys, key)
+	}
+	m.pendingMu.Unlock()
+
+	for _, key := range keys {
+		m.flushUpdate(key)
+	}
+}
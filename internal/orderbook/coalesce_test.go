@@ -0,0 +1,172 @@
+package orderbook
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"velocimex/internal/normalizer"
+)
+
+func TestBookStats(t *testing.T) {
+	book := NewOrderBook("binance:BTCUSDT")
+	book.Update(priceLevels(99, 2, 100, 3), priceLevels(101, 1, 102, 4))
+
+	stats := book.Stats()
+	if stats.MidPrice != 100.5 || stats.Spread != 1 {
+		t.Errorf("Expected mid 100.5 and spread 1, got %+v", stats)
+	}
+	// Bids 5, asks 5
+	if stats.Imbalance != 0 {
+		t.Errorf("Expected a balanced book, got imbalance %v", stats.Imbalance)
+	}
+
+	book.Update(priceLevels(100, 9), priceLevels(101, 1))
+	if got := book.Stats().Imbalance; math.Abs(got-0.8) > 1e-9 {
+		t.Errorf("Expected imbalance 0.8, got %v", got)
+	}
+
+	book.Update(nil, priceLevels(101, 1))
+	if stats := book.Stats(); stats.Imbalance != -1 || stats.MidPrice != 0 {
+		t.Errorf("Expected a one-sided book to have imbalance -1 and no mid, got %+v", stats)
+	}
+}
+
+func TestImbalanceDepth(t *testing.T) {
+	bids := priceLevels(100, 1, 99, 1, 98, 10)
+	asks := priceLevels(101, 1, 102, 1)
+
+	if got := Imbalance(bids, asks, 2); got != 0 {
+		t.Errorf("Expected the top 2 levels to balance, got %v", got)
+	}
+	if got := Imbalance(bids, asks, 0); math.Abs(got-10.0/14) > 1e-9 {
+		t.Errorf("Expected every level to count without a depth, got %v", got)
+	}
+	if got := Imbalance(nil, nil, 5); got != 0 {
+		t.Errorf("Expected an empty book to be balanced, got %v", got)
+	}
+}
+
+func TestCoalescedUpdates(t *testing.T) {
+	m := NewManager()
+	m.SetCoalesceConfig(CoalesceConfig{Window: time.Hour})
+	events := recordTopOfBook(m)
+
+	// A burst of updates walking the book up; only the last should be applied
+	for i := 0; i < 100; i++ {
+		price := 100 + float64(i)
+		m.UpdateOrderBook("binance", "BTCUSDT", priceLevels(price-1, 1, price-2, 3), priceLevels(price+1, 2, price+2, 2))
+	}
+	m.UpdateOrderBook("kraken", "BTCUSDT", priceLevels(50, 1), priceLevels(51, 1))
+
+	if _, _, ok := m.BestQuote("binance", "BTCUSDT"); ok {
+		t.Fatal("Expected the burst to be held until the window ends")
+	}
+	if got := m.CoalescedUpdates(); got != 99 {
+		t.Errorf("Expected 99 coalesced updates, got %d", got)
+	}
+
+	m.Flush()
+
+	book := m.GetOrderBook("binance:BTCUSDT")
+	stats := book.Stats()
+	if stats.MidPrice != 199 || stats.Spread != 2 {
+		t.Errorf("Expected the last update's mid 199 and spread 2, got %+v", stats)
+	}
+	// Bids 4, asks 4
+	if stats.Imbalance != 0 {
+		t.Errorf("Expected imbalance 0, got %v", stats.Imbalance)
+	}
+	if book.GetMidPrice() != stats.MidPrice || book.GetSpread() != stats.Spread {
+		t.Errorf("Expected stats to match the book getters, got %+v", stats)
+	}
+	bid, ask, _ := m.BestQuote("kraken", "BTCUSDT")
+	if bid != 50 || ask != 51 {
+		t.Errorf("Expected every held book to be flushed, got kraken %v/%v", bid, ask)
+	}
+
+	// One top-of-book event per book rather than per update
+	if len(*events) != 2 {
+		t.Fatalf("Expected 2 top-of-book events, got %d", len(*events))
+	}
+
+	// Flushing again has nothing to apply
+	m.Flush()
+	if len(*events) != 2 {
+		t.Errorf("Expected no events from an empty flush, got %d", len(*events))
+	}
+}
+
+func TestCoalescingWindowFlushes(t *testing.T) {
+	m := NewManager()
+	m.SetCoalesceConfig(CoalesceConfig{Window: 10 * time.Millisecond})
+
+	m.UpdateOrderBook("binance", "BTCUSDT", priceLevels(99, 1), priceLevels(101, 1))
+	m.UpdateOrderBook("binance", "BTCUSDT", priceLevels(100, 3), priceLevels(102, 1))
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if bid, ask, ok := m.BestQuote("binance", "BTCUSDT"); ok {
+			if bid != 100 || ask != 102 {
+				t.Errorf("Expected the latest update to be applied, got %v/%v", bid, ask)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the held update to be applied when the window ended")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := m.GetOrderBook("binance:BTCUSDT").Stats().Imbalance; got != 0.5 {
+		t.Errorf("Expected imbalance 0.5, got %v", got)
+	}
+
+	// Disabling coalescing applies updates at once again
+	m.SetCoalesceConfig(CoalesceConfig{})
+	m.UpdateOrderBook("binance", "BTCUSDT", priceLevels(98, 1), priceLevels(99, 1))
+	if bid, _, _ := m.BestQuote("binance", "BTCUSDT"); bid != 98 {
+		t.Errorf("Expected the update to be applied at once, got bid %v", bid)
+	}
+
+	if err := (CoalesceConfig{Window: -time.Second}).Validate(); err == nil {
+		t.Error("Expected a negative window to be invalid")
+	}
+}
+
+// benchmarkLevels returns n levels per side around a mid price, unsorted as
+// they may arrive from a feed
+func benchmarkLevels(mid float64, n int) ([]normalizer.PriceLevel, []normalizer.PriceLevel) {
+	bids := make([]normalizer.PriceLevel, n)
+	asks := make([]normalizer.PriceLevel, n)
+	for i := 0; i < n; i++ {
+		offset := float64((i*7)%n + 1)
+		bids[i] = normalizer.PriceLevel{Price: mid - offset, Volume: offset}
+		asks[i] = normalizer.PriceLevel{Price: mid + offset, Volume: offset}
+	}
+	return bids, asks
+}
+
+// BenchmarkUpdateOrderBook compares applying every update of a burst to
+// coalescing the burst into one update
+func BenchmarkUpdateOrderBook(b *testing.B) {
+	for _, window := range []time.Duration{0, time.Hour} {
+		b.Run(fmt.Sprintf("window=%s", window), func(b *testing.B) {
+			m := NewManager()
+			m.SetCoalesceConfig(CoalesceConfig{Window: window})
+			m.SubscribeTopOfBook(func(event TopOfBookEvent) {})
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				bids, asks := benchmarkLevels(100+float64(i%10), 100)
+				m.UpdateOrderBook("binance", "BTCUSDT", bids, asks)
+				// Flush once per burst of 100 updates
+				if i%100 == 99 {
+					m.Flush()
+				}
+			}
+			m.Flush()
+		})
+	}
+}
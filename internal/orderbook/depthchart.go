@@ -0,0 +1,89 @@
+package orderbook
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"velocimex/internal/normalizer"
+)
+
+// DepthPoint is a point of a depth chart: the volume quoted at a price and
+// the cumulative volume at that price or better
+type DepthPoint struct {
+	Price      float64 `json:"price"`
+	Volume     float64 `json:"volume"`
+	Cumulative float64 `json:"cumulative"`
+}
+
+// DepthChart is the cumulative bid and ask volume of a symbol, best price
+// first, for plotting a depth chart
+type DepthChart struct {
+	Symbol    string       `json:"symbol"`
+	Exchange  string       `json:"exchange,omitempty"`
+	Books     int          `json:"books"`
+	Bids      []DepthPoint `json:"bids"`
+	Asks      []DepthPoint `json:"asks"`
+	Timestamp time.Time    `json:"timestamp"`
+}
+
+// DepthSeries accumulates levels sorted best first into a depth series.
+// Levels at the same price are merged.
+func DepthSeries(levels []normalizer.PriceLevel) []DepthPoint {
+	series := make([]DepthPoint, 0, len(levels))
+	cumulative := 0.0
+	for _, level := range levels {
+		cumulative += level.Volume
+		if last := len(series) - 1; last >= 0 && series[last].Price == level.Price {
+			series[last].Volume += level.Volume
+			series[last].Cumulative = cumulative
+			continue
+		}
+		series = append(series, DepthPoint{Price: level.Price, Volume: level.Volume, Cumulative: cumulative})
+	}
+	return series
+}
+
+// GetDepthChart returns the depth chart of a symbol on one exchange, or
+// across every exchange quoting it if exchange is empty. Each side is cut to
+// depth prices if depth is positive. It reports false if no book quotes the
+// symbol.
+func (m *Manager) GetDepthChart(symbol, exchange string, depth int) (*DepthChart, bool) {
+	m.mu.RLock()
+	books := make([]*OrderBook, 0)
+	for key, book := range m.books {
+		if exchange != "" {
+			if key == exchange+":"+symbol {
+				books = append(books, book)
+			}
+		} else if key == symbol || strings.HasSuffix(key, ":"+symbol) {
+			books = append(books, book)
+		}
+	}
+	m.mu.RUnlock()
+
+	if len(books) == 0 {
+		return nil, false
+	}
+
+	chart := &DepthChart{Symbol: symbol, Exchange: exchange, Books: len(books)}
+	var bids, asks []normalizer.PriceLevel
+	for _, book := range books {
+		bookBids, bookAsks := book.levels()
+		bids = append(bids, bookBids...)
+		asks = append(asks, bookAsks...)
+		if timestamp := book.GetTimestamp(); timestamp.After(chart.Timestamp) {
+			chart.Timestamp = timestamp
+		}
+	}
+	sort.SliceStable(bids, func(i, j int) bool { return bids[i].Price > bids[j].Price })
+	sort.SliceStable(asks, func(i, j int) bool { return asks[i].Price < asks[j].Price })
+
+	chart.Bids = DepthSeries(bids)
+	chart.Asks = DepthSeries(asks)
+	if depth > 0 {
+		chart.Bids = chart.Bids[:min(depth, len(chart.Bids))]
+		chart.Asks = chart.Asks[:min(depth, len(chart.Asks))]
+	}
+	return chart, true
+}
@@ -0,0 +1,88 @@
+package orderbook
+
+import (
+	"math"
+	"testing"
+)
+
+// checkDepthSeries checks that a side's cumulative volume only grows as
+// prices get worse and that each point adds its own volume
+func checkDepthSeries(t *testing.T, side string, series []DepthPoint, bids bool) {
+	t.Helper()
+	previous := DepthPoint{}
+	for i, point := range series {
+		if i > 0 {
+			if bids && point.Price >= previous.Price || !bids && point.Price <= previous.Price {
+				t.Errorf("%s: price %v at %d is not worse than %v", side, point.Price, i, previous.Price)
+			}
+		}
+		if point.Cumulative < previous.Cumulative {
+			t.Errorf("%s: cumulative volume fell from %v to %v at %d", side, previous.Cumulative, point.Cumulative, i)
+		}
+		if math.Abs(point.Cumulative-previous.Cumulative-point.Volume) > 1e-9 {
+			t.Errorf("%s: cumulative %v at %d does not add volume %v to %v", side, point.Cumulative, i, point.Volume, previous.Cumulative)
+		}
+		previous = point
+	}
+}
+
+func TestDepthChart(t *testing.T) {
+	m := NewManager()
+	m.UpdateOrderBook("binance", "BTCUSDT", priceLevels(100, 1, 99, 2, 98, 3), priceLevels(101, 1.5, 102, 2.5))
+	m.UpdateOrderBook("coinbase", "BTCUSDT", priceLevels(100, 0.5, 98.5, 1), priceLevels(101.5, 1, 102, 0.5))
+	m.UpdateOrderBook("binance", "ETHUSDT", priceLevels(10, 100), priceLevels(11, 100))
+
+	chart, ok := m.GetDepthChart("BTCUSDT", "", 0)
+	if !ok {
+		t.Fatal("Expected a depth chart for BTCUSDT")
+	}
+	if chart.Books != 2 {
+		t.Errorf("Expected 2 books, got %d", chart.Books)
+	}
+	checkDepthSeries(t, "bids", chart.Bids, true)
+	checkDepthSeries(t, "asks", chart.Asks, false)
+
+	// Equal prices across exchanges are merged
+	wantBids := []DepthPoint{{100, 1.5, 1.5}, {99, 2, 3.5}, {98.5, 1, 4.5}, {98, 3, 7.5}}
+	if len(chart.Bids) != len(wantBids) {
+		t.Fatalf("Expected %d bid points, got %v", len(wantBids), chart.Bids)
+	}
+	for i, want := range wantBids {
+		if chart.Bids[i] != want {
+			t.Errorf("Bid point %d: expected %v, got %v", i, want, chart.Bids[i])
+		}
+	}
+	wantAsks := []DepthPoint{{101, 1.5, 1.5}, {101.5, 1, 2.5}, {102, 3, 5.5}}
+	if len(chart.Asks) != len(wantAsks) {
+		t.Fatalf("Expected %d ask points, got %v", len(wantAsks), chart.Asks)
+	}
+	for i, want := range wantAsks {
+		if chart.Asks[i] != want {
+			t.Errorf("Ask point %d: expected %v, got %v", i, want, chart.Asks[i])
+		}
+	}
+
+	// The last point of each side is the total volume of its levels
+	if total := chart.Bids[len(chart.Bids)-1].Cumulative; total != 1+2+3+0.5+1 {
+		t.Errorf("Expected total bid volume 7.5, got %v", total)
+	}
+
+	// One exchange, cut to a depth
+	chart, ok = m.GetDepthChart("BTCUSDT", "coinbase", 1)
+	if !ok {
+		t.Fatal("Expected a depth chart for coinbase BTCUSDT")
+	}
+	if chart.Books != 1 || len(chart.Bids) != 1 || len(chart.Asks) != 1 {
+		t.Fatalf("Expected one point a side from one book, got %+v", chart)
+	}
+	if chart.Bids[0] != (DepthPoint{100, 0.5, 0.5}) || chart.Asks[0] != (DepthPoint{101.5, 1, 1}) {
+		t.Errorf("Unexpected coinbase points %v %v", chart.Bids[0], chart.Asks[0])
+	}
+
+	if _, ok := m.GetDepthChart("SOLUSDT", "", 0); ok {
+		t.Error("Expected no depth chart for an unquoted symbol")
+	}
+	if _, ok := m.GetDepthChart("BTCUSDT", "kraken", 0); ok {
+		t.Error("Expected no depth chart for an exchange without the book")
+	}
+}
@@ -0,0 +1,102 @@
+package orderbook
+
+import (
+	"math"
+	"sort"
+
+	"velocimex/internal/normalizer"
+)
+
+// sizeTolerance is the largest size difference treated as equal, absorbing
+// float error accumulated while applying incremental updates
+const sizeTolerance = 1e-9
+
+// LevelDiff is a price level that differs between two books
+type LevelDiff struct {
+	Side  string  `json:"side"` // "bid" or "ask"
+	Price float64 `json:"price"`
+	SizeA float64 `json:"size_a"` // Zero when the level is only in b
+	SizeB float64 `json:"size_b"` // Zero when the level is only in a
+}
+
+// BookDiff reports how book b differs from book a, e.g. an exchange snapshot
+// from our reconstructed book. Bids are listed best first, then asks best first.
+type BookDiff struct {
+	Added   []LevelDiff `json:"added"`   // Levels only in b
+	Removed []LevelDiff `json:"removed"` // Levels only in a
+	Resized []LevelDiff `json:"resized"` // Levels in both with different sizes
+}
+
+// Empty reports whether the books have identical levels
+func (d *BookDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Resized) == 0
+}
+
+// DiffBook compares every price level of two books. A nil book is treated as empty.
+func DiffBook(a, b *OrderBook) *BookDiff {
+	aBids, aAsks := a.levels()
+	bBids, bAsks := b.levels()
+
+	diff := &BookDiff{}
+	diff.compare("bid", aBids, bBids, true)
+	diff.compare("ask", aAsks, bAsks, false)
+	return diff
+}
+
+// levels returns copies of the book's bids and asks
+func (b *OrderBook) levels() ([]normalizer.PriceLevel, []normalizer.PriceLevel) {
+	if b == nil {
+		return nil, nil
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]normalizer.PriceLevel(nil), b.Bids...), append([]normalizer.PriceLevel(nil), b.Asks...)
+}
+
+// compare adds the differences between one side of each book
+func (d *BookDiff) compare(side string, a, b []normalizer.PriceLevel, descending bool) {
+	sizesA := levelSizes(a)
+	sizesB := levelSizes(b)
+
+	prices := make([]float64, 0, len(sizesA)+len(sizesB))
+	for price := range sizesA {
+		prices = append(prices, price)
+	}
+	for price := range sizesB {
+		if _, ok := sizesA[price]; !ok {
+			prices = append(prices, price)
+		}
+	}
+	sort.Float64s(prices)
+	if descending {
+		sort.Sort(sort.Reverse(sort.Float64Slice(prices)))
+	}
+
+	for _, price := range prices {
+		sizeA, inA := sizesA[price]
+		sizeB, inB := sizesB[price]
+		level := LevelDiff{Side: side, Price: price, SizeA: sizeA, SizeB: sizeB}
+
+		switch {
+		case !inA:
+			d.Added = append(d.Added, level)
+		case !inB:
+			d.Removed = append(d.Removed, level)
+		case math.Abs(sizeA-sizeB) > sizeTolerance:
+			d.Resized = append(d.Resized, level)
+		}
+	}
+}
+
+// levelSizes maps price -> size, summing duplicate prices and ignoring empty levels
+func levelSizes(levels []normalizer.PriceLevel) map[float64]float64 {
+	sizes := make(map[float64]float64, len(levels))
+	for _, level := range levels {
+		if level.Volume <= 0 {
+			continue
+		}
+		sizes[level.Price] += level.Volume
+	}
+	return sizes
+}
@@ -0,0 +1,88 @@
+package orderbook
+
+import (
+	"reflect"
+	"testing"
+
+	"velocimex/internal/normalizer"
+)
+
+func newTestBook(bids, asks []normalizer.PriceLevel) *OrderBook {
+	book := NewOrderBook("BTC/USD")
+	book.Update(bids, asks)
+	return book
+}
+
+func TestDiffBook(t *testing.T) {
+	ours := newTestBook(
+		[]normalizer.PriceLevel{{Price: 100, Volume: 1}, {Price: 99, Volume: 2}, {Price: 98, Volume: 3}},
+		[]normalizer.PriceLevel{{Price: 101, Volume: 1}, {Price: 102, Volume: 2}},
+	)
+	exchange := newTestBook(
+		[]normalizer.PriceLevel{{Price: 100, Volume: 1}, {Price: 99, Volume: 2.5}, {Price: 97, Volume: 4}},
+		[]normalizer.PriceLevel{{Price: 101, Volume: 1}, {Price: 101.5, Volume: 0.5}, {Price: 103, Volume: 1}},
+	)
+
+	diff := DiffBook(ours, exchange)
+
+	wantAdded := []LevelDiff{
+		{Side: "bid", Price: 97, SizeB: 4},
+		{Side: "ask", Price: 101.5, SizeB: 0.5},
+		{Side: "ask", Price: 103, SizeB: 1},
+	}
+	wantRemoved := []LevelDiff{
+		{Side: "bid", Price: 98, SizeA: 3},
+		{Side: "ask", Price: 102, SizeA: 2},
+	}
+	wantResized := []LevelDiff{
+		{Side: "bid", Price: 99, SizeA: 2, SizeB: 2.5},
+	}
+
+	if !reflect.DeepEqual(diff.Added, wantAdded) {
+		t.Errorf("Added = %+v, want %+v", diff.Added, wantAdded)
+	}
+	if !reflect.DeepEqual(diff.Removed, wantRemoved) {
+		t.Errorf("Removed = %+v, want %+v", diff.Removed, wantRemoved)
+	}
+	if !reflect.DeepEqual(diff.Resized, wantResized) {
+		t.Errorf("Resized = %+v, want %+v", diff.Resized, wantResized)
+	}
+	if diff.Empty() {
+		t.Error("Empty() = true for differing books")
+	}
+}
+
+func TestDiffBookIdentical(t *testing.T) {
+	levels := func() ([]normalizer.PriceLevel, []normalizer.PriceLevel) {
+		return []normalizer.PriceLevel{{Price: 100, Volume: 1}, {Price: 99, Volume: 0.1 + 0.2}},
+			[]normalizer.PriceLevel{{Price: 101, Volume: 1}}
+	}
+	a := newTestBook(levels())
+	bids, asks := levels()
+	bids[1].Volume = 0.3 // Within float tolerance of 0.1 + 0.2
+	b := newTestBook(bids, asks)
+
+	if diff := DiffBook(a, b); !diff.Empty() {
+		t.Errorf("DiffBook of identical books = %+v, want empty", diff)
+	}
+	if diff := DiffBook(a, a); !diff.Empty() {
+		t.Errorf("DiffBook of a book with itself = %+v, want empty", diff)
+	}
+}
+
+func TestDiffBookNil(t *testing.T) {
+	book := newTestBook(
+		[]normalizer.PriceLevel{{Price: 100, Volume: 1}},
+		[]normalizer.PriceLevel{{Price: 101, Volume: 2}},
+	)
+
+	diff := DiffBook(nil, book)
+	if len(diff.Added) != 2 || len(diff.Removed) != 0 || len(diff.Resized) != 0 {
+		t.Errorf("DiffBook(nil, book) = %+v, want both levels added", diff)
+	}
+
+	diff = DiffBook(book, nil)
+	if len(diff.Removed) != 2 || len(diff.Added) != 0 || len(diff.Resized) != 0 {
+		t.Errorf("DiffBook(book, nil) = %+v, want both levels removed", diff)
+	}
+}
@@ -0,0 +1,166 @@
+package orderbook
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Index price methods
+const (
+	IndexMethodVWAP   = "vwap"   // Mid prices weighted by the volume quoted near the top of each book
+	IndexMethodMedian = "median" // Median of the mid prices, ignoring volume
+)
+
+// IndexConfig configures the index price, a reference price per symbol
+// combined across exchanges so one venue's anomalies are smoothed out
+type IndexConfig struct {
+	// Method is vwap or median; default vwap
+	Method string `json:"method" yaml:"method"`
+	// Depth is the number of levels per side whose volume weights a venue; default ImbalanceDepth
+	Depth int `json:"depth" yaml:"depth"`
+	// MaxAge leaves out books not updated for this long; zero includes every book
+	MaxAge time.Duration `json:"max_age" yaml:"maxAge"`
+	// MarkToMarket values open positions at the index price instead of their last fill
+	MarkToMarket bool `json:"mark_to_market" yaml:"markToMarket"`
+}
+
+// Validate checks the index method, depth and age limit
+func (c IndexConfig) Validate() error {
+	switch c.Method {
+	case "", IndexMethodVWAP, IndexMethodMedian:
+	default:
+		return fmt.Errorf("unknown index price method: %s", c.Method)
+	}
+	if c.Depth < 0 {
+		return fmt.Errorf("index price depth cannot be negative")
+	}
+	if c.MaxAge < 0 {
+		return fmt.Errorf("index price max age cannot be negative")
+	}
+	return nil
+}
+
+// IndexPrice is a symbol's price combined across exchanges
+type IndexPrice struct {
+	Symbol     string           `json:"symbol"`
+	Price      float64          `json:"price"`
+	Method     string           `json:"method"`
+	Components []IndexComponent `json:"components"`
+	Timestamp  time.Time        `json:"timestamp"`
+}
+
+// IndexComponent is one exchange's contribution to an index price
+type IndexComponent struct {
+	Exchange string  `json:"exchange"`
+	Price    float64 `json:"price"`  // Mid price
+	Volume   float64 `json:"volume"` // Bid and ask volume over the index depth
+	Weight   float64 `json:"weight"` // Share of a vwap index; zero for a median
+}
+
+// SetIndexConfig sets how index prices are combined
+func (m *Manager) SetIndexConfig(config IndexConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.index = config
+}
+
+// IndexPrice combines the mid prices of a symbol's books across exchanges.
+// Books restored from a snapshot and not yet updated, books older than the
+// max age and books missing a side are left out; ok is false when none
+// remain. Unlike GetOrderBook it does not create missing books.
+func (m *Manager) IndexPrice(symbol string) (IndexPrice, bool) {
+	m.mu.RLock()
+	config := m.index
+	books := make(map[string]*OrderBook)
+	for key, book := range m.books {
+		if exchange, bookSymbol, ok := strings.Cut(key, ":"); ok && bookSymbol == symbol {
+			books[exchange] = book
+		}
+	}
+	m.mu.RUnlock()
+
+	method := config.Method
+	if method == "" {
+		method = IndexMethodVWAP
+	}
+	depth := config.Depth
+	if depth <= 0 {
+		depth = ImbalanceDepth
+	}
+
+	now := time.Now()
+	components := make([]IndexComponent, 0, len(books))
+	for exchange, book := range books {
+		if book.IsStale() || (config.MaxAge > 0 && now.Sub(book.GetTimestamp()) > config.MaxAge) {
+			continue
+		}
+		bids, asks := book.GetDepth(depth)
+		if len(bids) == 0 || len(asks) == 0 {
+			continue
+		}
+		components = append(components, IndexComponent{
+			Exchange: exchange,
+			Price:    (bids[0].Price + asks[0].Price) / 2,
+			Volume:   sideVolume(bids, depth) + sideVolume(asks, depth),
+		})
+	}
+	if len(components) == 0 {
+		return IndexPrice{}, false
+	}
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].Exchange < components[j].Exchange
+	})
+
+	index := IndexPrice{Symbol: symbol, Method: method, Components: components, Timestamp: now}
+	if method == IndexMethodMedian {
+		index.Price = medianPrice(components)
+	} else {
+		index.Price = weightComponents(components)
+	}
+	return index, true
+}
+
+// MarkPrice returns a symbol's index price, for marking positions to market
+func (m *Manager) MarkPrice(symbol string) (float64, bool) {
+	index, ok := m.IndexPrice(symbol)
+	return index.Price, ok
+}
+
+// weightComponents sets each component's share of the total volume and
+// returns the volume-weighted price. Without any volume every component
+// weighs the same.
+func weightComponents(components []IndexComponent) float64 {
+	total := 0.0
+	for _, component := range components {
+		total += component.Volume
+	}
+
+	price := 0.0
+	for i := range components {
+		weight := 1 / float64(len(components))
+		if total > 0 {
+			weight = components[i].Volume / total
+		}
+		components[i].Weight = weight
+		price += components[i].Price * weight
+	}
+	return price
+}
+
+// medianPrice returns the median of the component prices, averaging the
+// middle two of an even count
+func medianPrice(components []IndexComponent) float64 {
+	prices := make([]float64, len(components))
+	for i, component := range components {
+		prices[i] = component.Price
+	}
+	sort.Float64s(prices)
+
+	middle := len(prices) / 2
+	if len(prices)%2 == 0 {
+		return (prices[middle-1] + prices[middle]) / 2
+	}
+	return prices[middle]
+}
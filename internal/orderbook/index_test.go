@@ -0,0 +1,132 @@
+package orderbook
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// divergentBooks quotes BTC/USD on three exchanges, kraken well away from the others
+func divergentBooks() *Manager {
+	m := NewManager()
+	m.UpdateOrderBook("binance", "BTC/USD", priceLevels(99, 20), priceLevels(101, 10)) // mid 100, volume 30
+	m.UpdateOrderBook("coinbase", "BTC/USD", priceLevels(101, 5), priceLevels(103, 5)) // mid 102, volume 10
+	m.UpdateOrderBook("kraken", "BTC/USD", priceLevels(119, 1), priceLevels(121, 1))   // mid 120, volume 2
+	m.UpdateOrderBook("binance", "ETH/USD", priceLevels(9, 100), priceLevels(11, 100))
+	return m
+}
+
+func TestIndexPriceVWAP(t *testing.T) {
+	m := divergentBooks()
+
+	index, ok := m.IndexPrice("BTC/USD")
+	if !ok {
+		t.Fatal("Expected an index price")
+	}
+	want := (100.0*30 + 102*10 + 120*2) / 42
+	if math.Abs(index.Price-want) > 1e-9 {
+		t.Errorf("Expected index price %v, got %v", want, index.Price)
+	}
+	if index.Method != IndexMethodVWAP {
+		t.Errorf("Expected method %s, got %s", IndexMethodVWAP, index.Method)
+	}
+	if len(index.Components) != 3 {
+		t.Fatalf("Expected 3 components, got %d", len(index.Components))
+	}
+	if c := index.Components[0]; c.Exchange != "binance" || c.Price != 100 || c.Volume != 30 || math.Abs(c.Weight-30.0/42) > 1e-9 {
+		t.Errorf("Unexpected binance component %+v", c)
+	}
+	if c := index.Components[2]; c.Exchange != "kraken" || math.Abs(c.Weight-2.0/42) > 1e-9 {
+		t.Errorf("Unexpected kraken component %+v", c)
+	}
+}
+
+func TestIndexPriceMedian(t *testing.T) {
+	m := divergentBooks()
+	m.SetIndexConfig(IndexConfig{Method: IndexMethodMedian})
+
+	// The kraken outlier does not move the median
+	index, _ := m.IndexPrice("BTC/USD")
+	if index.Price != 102 {
+		t.Errorf("Expected median 102, got %v", index.Price)
+	}
+
+	// An even count averages the middle two
+	m.UpdateOrderBook("bitstamp", "BTC/USD", priceLevels(103, 1), priceLevels(105, 1))
+	index, _ = m.IndexPrice("BTC/USD")
+	if index.Price != 103 {
+		t.Errorf("Expected median 103, got %v", index.Price)
+	}
+	for _, component := range index.Components {
+		if component.Weight != 0 {
+			t.Errorf("Expected no weights for a median, got %+v", component)
+		}
+	}
+}
+
+func TestIndexPriceDepth(t *testing.T) {
+	m := NewManager()
+	m.UpdateOrderBook("binance", "BTC/USD", priceLevels(99, 1, 98, 100), priceLevels(101, 1))
+	m.UpdateOrderBook("kraken", "BTC/USD", priceLevels(109, 1), priceLevels(111, 1))
+
+	// At depth 1 the venues weigh the same
+	m.SetIndexConfig(IndexConfig{Depth: 1})
+	if index, _ := m.IndexPrice("BTC/USD"); index.Price != 105 {
+		t.Errorf("Expected index price 105 at depth 1, got %v", index.Price)
+	}
+
+	// Deeper, binance's second bid level dominates
+	m.SetIndexConfig(IndexConfig{Depth: 2})
+	want := (100.0*102 + 110*2) / 104
+	if index, _ := m.IndexPrice("BTC/USD"); math.Abs(index.Price-want) > 1e-9 {
+		t.Errorf("Expected index price %v at depth 2, got %v", want, index.Price)
+	}
+}
+
+func TestIndexPriceExcludedBooks(t *testing.T) {
+	m := NewManager()
+	if _, ok := m.IndexPrice("BTC/USD"); ok {
+		t.Error("Expected no index price without books")
+	}
+
+	// One-sided and stale books are left out
+	m.UpdateOrderBook("binance", "BTC/USD", priceLevels(99, 1), priceLevels(101, 1))
+	m.UpdateOrderBook("coinbase", "BTC/USD", priceLevels(150, 1), nil)
+	restored := NewOrderBook("kraken:BTC/USD")
+	restored.Update(priceLevels(199, 1), priceLevels(201, 1))
+	restored.stale = true
+	m.books["kraken:BTC/USD"] = restored
+
+	index, ok := m.IndexPrice("BTC/USD")
+	if !ok || index.Price != 100 || len(index.Components) != 1 {
+		t.Errorf("Expected only binance at 100, got %+v", index)
+	}
+
+	// Books older than the max age are left out
+	m.SetIndexConfig(IndexConfig{MaxAge: time.Minute})
+	m.books["binance:BTC/USD"].Timestamp = time.Now().Add(-2 * time.Minute)
+	if _, ok := m.IndexPrice("BTC/USD"); ok {
+		t.Error("Expected no index price once every book is too old")
+	}
+
+	// Unlike GetOrderBook, no book is created
+	if _, ok := m.IndexPrice("DOGE/USD"); ok || len(m.books) != 3 {
+		t.Errorf("Expected no index and no new book, got %d books", len(m.books))
+	}
+}
+
+func TestIndexConfigValidate(t *testing.T) {
+	valid := []IndexConfig{{}, {Method: IndexMethodVWAP, Depth: 5, MaxAge: time.Second}, {Method: IndexMethodMedian}}
+	for _, config := range valid {
+		if err := config.Validate(); err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", config, err)
+		}
+	}
+
+	invalid := []IndexConfig{{Method: "mean"}, {Depth: -1}, {MaxAge: -time.Second}}
+	for _, config := range invalid {
+		if err := config.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", config)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package orderbook
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"velocimex/internal/normalizer"
+)
+
+// maxTrackedUpdates bounds the update times a book keeps for its update rate
+const maxTrackedUpdates = 256
+
+// LiquidityConfig configures how books are scored for liquidity. Each
+// component is scored between 0 and 1 against its reference value, which
+// scores 0.5, and the components are combined by weight.
+type LiquidityConfig struct {
+	DepthBps       float64       // Depth is measured within this many bps of the mid price
+	Window         time.Duration // Window the update rate is measured over
+	SpreadRefBps   float64       // Spread that scores 0.5
+	DepthRef       float64       // Notional depth that scores 0.5
+	UpdateRateRef  float64       // Updates per second that score 0.5
+	SpreadWeight   float64
+	DepthWeight    float64
+	ActivityWeight float64
+}
+
+// DefaultLiquidityConfig returns default liquidity scoring configuration
+func DefaultLiquidityConfig() LiquidityConfig {
+	return LiquidityConfig{
+		DepthBps:       10,
+		Window:         time.Minute,
+		SpreadRefBps:   5,
+		DepthRef:       100000,
+		UpdateRateRef:  1,
+		SpreadWeight:   0.4,
+		DepthWeight:    0.4,
+		ActivityWeight: 0.2,
+	}
+}
+
+// Validate checks that the scoring has positive references and weights
+func (c LiquidityConfig) Validate() error {
+	if c.DepthBps <= 0 {
+		return fmt.Errorf("depth bps must be positive")
+	}
+	if c.Window <= 0 {
+		return fmt.Errorf("update rate window must be positive")
+	}
+	if c.SpreadRefBps <= 0 || c.DepthRef <= 0 || c.UpdateRateRef <= 0 {
+		return fmt.Errorf("liquidity reference values must be positive")
+	}
+	if c.SpreadWeight < 0 || c.DepthWeight < 0 || c.ActivityWeight < 0 {
+		return fmt.Errorf("liquidity weights cannot be negative")
+	}
+	if c.SpreadWeight+c.DepthWeight+c.ActivityWeight == 0 {
+		return fmt.Errorf("at least one liquidity weight must be positive")
+	}
+	return nil
+}
+
+// LiquidityScore is a book's composite liquidity score, from 0 to 1, and the
+// components it was computed from
+type LiquidityScore struct {
+	Book          string  `json:"book"`
+	Score         float64 `json:"score"`
+	SpreadBps     float64 `json:"spread_bps"`
+	BidDepth      float64 `json:"bid_depth"` // Notional within DepthBps of mid
+	AskDepth      float64 `json:"ask_depth"`
+	UpdateRate    float64 `json:"update_rate"` // Updates per second over the window
+	SpreadScore   float64 `json:"spread_score"`
+	DepthScore    float64 `json:"depth_score"`
+	ActivityScore float64 `json:"activity_score"`
+}
+
+// recordUpdate notes the time of an update. The caller holds b.mu.
+func (b *OrderBook) recordUpdate(at time.Time) {
+	if len(b.updates) == maxTrackedUpdates {
+		copy(b.updates, b.updates[1:])
+		b.updates = b.updates[:maxTrackedUpdates-1]
+	}
+	b.updates = append(b.updates, at)
+}
+
+// Liquidity scores the book's liquidity at now. A book without both sides
+// has no spread or depth and scores on its update rate alone.
+func (b *OrderBook) Liquidity(config LiquidityConfig, now time.Time) LiquidityScore {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	score := LiquidityScore{Book: b.Symbol}
+
+	since := now.Add(-config.Window)
+	updates := 0
+	for _, at := range b.updates {
+		if at.After(since) && !at.After(now) {
+			updates++
+		}
+	}
+	score.UpdateRate = float64(updates) / config.Window.Seconds()
+	score.ActivityScore = saturate(score.UpdateRate, config.UpdateRateRef)
+
+	if len(b.Bids) > 0 && len(b.Asks) > 0 {
+		mid := (b.Bids[0].Price + b.Asks[0].Price) / 2
+		if mid > 0 {
+			score.SpreadBps = (b.Asks[0].Price - b.Bids[0].Price) / mid * 10000
+			// A tighter spread scores higher; a crossed book counts as zero spread
+			score.SpreadScore = 1 - saturate(max(score.SpreadBps, 0), config.SpreadRefBps)
+
+			band := mid * config.DepthBps / 10000
+			score.BidDepth = notionalWithin(b.Bids, mid-band, mid)
+			score.AskDepth = notionalWithin(b.Asks, mid, mid+band)
+			// The thinner side limits how much can trade either way
+			score.DepthScore = saturate(min(score.BidDepth, score.AskDepth), config.DepthRef)
+		}
+	}
+
+	total := config.SpreadWeight + config.DepthWeight + config.ActivityWeight
+	if total > 0 {
+		score.Score = (score.SpreadScore*config.SpreadWeight +
+			score.DepthScore*config.DepthWeight +
+			score.ActivityScore*config.ActivityWeight) / total
+	}
+	return score
+}
+
+// LiquidityScores scores every exchange's book for a symbol, most liquid
+// first. Unlike GetOrderBook it does not create missing books.
+func (m *Manager) LiquidityScores(symbol string, config LiquidityConfig) []LiquidityScore {
+	m.mu.RLock()
+	books := make([]*OrderBook, 0)
+	for key, book := range m.books {
+		if key == symbol || strings.HasSuffix(key, ":"+symbol) {
+			books = append(books, book)
+		}
+	}
+	m.mu.RUnlock()
+
+	now := time.Now()
+	scores := make([]LiquidityScore, 0, len(books))
+	for _, book := range books {
+		scores = append(scores, book.Liquidity(config, now))
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].Book < scores[j].Book
+	})
+	return scores
+}
+
+// notionalWithin sums the notional of the levels priced within [low, high]
+func notionalWithin(levels []normalizer.PriceLevel, low, high float64) float64 {
+	notional := 0.0
+	for _, level := range levels {
+		if level.Price >= low && level.Price <= high {
+			notional += level.Price * level.Volume
+		}
+	}
+	return notional
+}
+
+// saturate maps a non-negative value onto [0, 1), scoring ref as 0.5
+func saturate(value, ref float64) float64 {
+	if value <= 0 {
+		return 0
+	}
+	return value / (value + ref)
+}
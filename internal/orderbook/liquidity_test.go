@@ -0,0 +1,117 @@
+package orderbook
+
+import (
+	"testing"
+	"time"
+
+	"velocimex/internal/normalizer"
+)
+
+// ladder builds levels stepping away from best by step, each of volume size
+func ladder(best, step, size float64, n int) []normalizer.PriceLevel {
+	levels := make([]normalizer.PriceLevel, 0, n)
+	for i := 0; i < n; i++ {
+		levels = append(levels, normalizer.PriceLevel{Price: best + step*float64(i), Volume: size})
+	}
+	return levels
+}
+
+func TestLiquidityScoreOrdering(t *testing.T) {
+	config := DefaultLiquidityConfig()
+
+	book := func(spread, size float64, updates int) *OrderBook {
+		b := NewOrderBook("test")
+		for i := 0; i < updates; i++ {
+			b.update(ladder(100-spread/2, -0.01, size, 10), ladder(100+spread/2, 0.01, size, 10))
+		}
+		return b
+	}
+
+	deepBook, thinBook, wideBook, busyBook := book(0.01, 100, 1), book(0.01, 1, 1), book(0.5, 100, 1), book(0.01, 100, 30)
+	now := time.Now()
+	deep := deepBook.Liquidity(config, now)
+	thin := thinBook.Liquidity(config, now)
+	wide := wideBook.Liquidity(config, now)
+	busy := busyBook.Liquidity(config, now)
+
+	if deep.Score <= thin.Score {
+		t.Errorf("deep book scored %f, not above thin book %f", deep.Score, thin.Score)
+	}
+	if deep.Score <= wide.Score {
+		t.Errorf("tight book scored %f, not above wide book %f", deep.Score, wide.Score)
+	}
+	if busy.Score <= deep.Score {
+		t.Errorf("frequently updated book scored %f, not above quiet book %f", busy.Score, deep.Score)
+	}
+	if deep.SpreadBps <= 0 || wide.SpreadBps <= deep.SpreadBps {
+		t.Errorf("spreads = %f and %f bps, want wide above tight", wide.SpreadBps, deep.SpreadBps)
+	}
+
+	// Only levels within DepthBps of mid count: 10 bps of 100 is 0.1, so
+	// half a tight spread plus levels 0.01 apart leaves 10 levels a side
+	if want := 100.0 * 100 * 10; deep.BidDepth < want*0.99 || deep.BidDepth > want*1.01 {
+		t.Errorf("bid depth = %f, want about %f", deep.BidDepth, want)
+	}
+	// The wide book's best levels sit 25 bps out, outside the band
+	if wide.BidDepth != 0 || wide.AskDepth != 0 {
+		t.Errorf("wide book depth = %f/%f, want none within the band", wide.BidDepth, wide.AskDepth)
+	}
+
+	for _, score := range []LiquidityScore{deep, thin, wide, busy} {
+		if score.Score < 0 || score.Score > 1 {
+			t.Errorf("score %f out of [0, 1]", score.Score)
+		}
+	}
+
+	// Updates age out of the window
+	if aged := busyBook.Liquidity(config, now.Add(2*config.Window)); busy.UpdateRate == 0 || aged.UpdateRate != 0 {
+		t.Errorf("update rate = %f then %f, want positive then zero", busy.UpdateRate, aged.UpdateRate)
+	}
+}
+
+func TestLiquidityScoreEmptyBook(t *testing.T) {
+	score := NewOrderBook("empty").Liquidity(DefaultLiquidityConfig(), time.Now())
+	if score.Score != 0 || score.SpreadBps != 0 || score.BidDepth != 0 {
+		t.Errorf("empty book = %+v, want zero score", score)
+	}
+}
+
+func TestLiquidityScoresBySymbol(t *testing.T) {
+	m := NewManager()
+	m.UpdateOrderBook("binance", "BTC/USD", ladder(99.99, -0.01, 100, 10), ladder(100.01, 0.01, 100, 10))
+	m.UpdateOrderBook("kraken", "BTC/USD", ladder(99.9, -0.01, 1, 10), ladder(100.1, 0.01, 1, 10))
+	m.UpdateOrderBook("binance", "ETH/USD", ladder(9.99, -0.01, 1, 10), ladder(10.01, 0.01, 1, 10))
+
+	scores := m.LiquidityScores("BTC/USD", DefaultLiquidityConfig())
+	if len(scores) != 2 {
+		t.Fatalf("got %d scores, want 2", len(scores))
+	}
+	if scores[0].Book != "binance:BTC/USD" || scores[1].Book != "kraken:BTC/USD" {
+		t.Errorf("order = %s, %s, want binance then kraken", scores[0].Book, scores[1].Book)
+	}
+
+	if scores := m.LiquidityScores("SOL/USD", DefaultLiquidityConfig()); len(scores) != 0 {
+		t.Errorf("got %d scores for an unknown symbol", len(scores))
+	}
+	if len(m.GetSymbols()) != 3 {
+		t.Errorf("scoring created books: %v", m.GetSymbols())
+	}
+}
+
+func TestLiquidityConfigValidate(t *testing.T) {
+	if err := DefaultLiquidityConfig().Validate(); err != nil {
+		t.Errorf("default config invalid: %v", err)
+	}
+
+	config := DefaultLiquidityConfig()
+	config.DepthBps = 0
+	if err := config.Validate(); err == nil {
+		t.Error("zero depth bps accepted")
+	}
+
+	config = DefaultLiquidityConfig()
+	config.SpreadWeight, config.DepthWeight, config.ActivityWeight = 0, 0, 0
+	if err := config.Validate(); err == nil {
+		t.Error("all-zero weights accepted")
+	}
+}
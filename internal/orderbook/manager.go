@@ -3,20 +3,32 @@ package orderbook
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"velocimex/internal/normalizer"
 )
 
 // Manager manages multiple order books
 type Manager struct {
-	books map[string]*OrderBook
-	mu    sync.RWMutex
+	books       map[string]*OrderBook
+	topConfig   TopOfBookConfig
+	topHandlers []func(event TopOfBookEvent)
+	priority    FeedPriorityConfig
+	coalesce    CoalesceConfig
+	index       IndexConfig
+	mu          sync.RWMutex
+
+	// Updates held for coalescing, by book key
+	pending     map[string]*pendingUpdate
+	coalesced   int64
+	pendingMu   sync.Mutex
 }
 
 // NewManager creates a new order book manager
 func NewManager() *Manager {
 	return &Manager{
-		books: make(map[string]*OrderBook),
+		books:   make(map[string]*OrderBook),
+		pending: make(map[string]*pendingUpdate),
 	}
 }
 
@@ -44,6 +56,16 @@ func (m *Manager) GetOrderBook(symbol string) *OrderBook {
 	return book
 }
 
+// LookupOrderBook returns the order book for a symbol if it exists.
+// Unlike GetOrderBook it does not create missing books.
+func (m *Manager) LookupOrderBook(symbol string) (*OrderBook, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	
+	book, ok := m.books[symbol]
+	return book, ok
+}
+
 // GetSymbols returns all symbols with order books
 func (m *Manager) GetSymbols() []string {
 	m.mu.RLock()
@@ -71,11 +93,76 @@ func (m *Manager) GetAllOrderBooks() map[string]*OrderBook {
 	return books
 }
 
-// UpdateOrderBook updates an order book with new data from an exchange
+// BookAge returns how long ago an exchange's book for a symbol was updated.
+// Unlike GetOrderBook it does not create missing books.
+func (m *Manager) BookAge(exchange, symbol string) (time.Duration, bool) {
+	m.mu.RLock()
+	book, ok := m.books[fmt.Sprintf("%s:%s", exchange, symbol)]
+	m.mu.RUnlock()
+	
+	if !ok {
+		return 0, false
+	}
+	return time.Since(book.GetTimestamp()), true
+}
+
+// BestQuote returns the best bid and ask of an exchange's book for a symbol.
+// Unlike GetOrderBook it does not create missing books.
+func (m *Manager) BestQuote(exchange, symbol string) (float64, float64, bool) {
+	m.mu.RLock()
+	book, ok := m.books[fmt.Sprintf("%s:%s", exchange, symbol)]
+	m.mu.RUnlock()
+	
+	if !ok {
+		return 0, 0, false
+	}
+	bid, ask := book.GetBestBid(), book.GetBestAsk()
+	if bid == nil || ask == nil {
+		return 0, 0, false
+	}
+	return bid.Price, ask.Price, true
+}
+
+// UpdateOrderBook updates an order book with new data from an exchange. With
+// coalescing enabled the update is held until the book's window ends.
 func (m *Manager) UpdateOrderBook(exchange, symbol string, bids, asks []normalizer.PriceLevel) {
+	m.mu.RLock()
+	window := m.coalesce.Window
+	m.mu.RUnlock()
+
+	if window > 0 {
+		m.holdUpdate(exchange, symbol, bids, asks, window)
+		return
+	}
+	m.applyUpdate(exchange, symbol, bids, asks)
+}
+
+// applyUpdate replaces a book's levels and notifies top-of-book handlers
+func (m *Manager) applyUpdate(exchange, symbol string, bids, asks []normalizer.PriceLevel) {
 	// Create a composite key for exchange-specific order books
 	key := fmt.Sprintf("%s:%s", exchange, symbol)
 	
 	book := m.GetOrderBook(key)
-	book.Update(bids, asks)
+	before, after := book.update(bids, asks)
+	
+	m.mu.RLock()
+	handlers := m.topHandlers
+	priceOnly := m.topConfig.PriceOnly
+	m.mu.RUnlock()
+	
+	if len(handlers) == 0 || !after.changed(before, priceOnly) {
+		return
+	}
+	event := TopOfBookEvent{
+		Exchange:  exchange,
+		Symbol:    symbol,
+		OldBid:    before.bid,
+		OldAsk:    before.ask,
+		NewBid:    after.bid,
+		NewAsk:    after.ask,
+		Timestamp: book.GetTimestamp(),
+	}
+	for _, handler := range handlers {
+		handler(event)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,138 @@
+package orderbook
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// FeedPriorityConfig ranks the exchanges quoting the same symbol. Where prices
+// tie, the consolidated book and the router prefer the higher priority.
+type FeedPriorityConfig struct {
+	// Priorities maps exchange to priority, higher first; unlisted exchanges have priority 0
+	Priorities map[string]int `yaml:"priorities"`
+	// StaleAfter ranks books not updated for this long below every fresh book; zero disables it
+	StaleAfter time.Duration `yaml:"staleAfter"`
+}
+
+// Priority returns an exchange's configured priority
+func (c FeedPriorityConfig) Priority(exchange string) int {
+	return c.Priorities[exchange]
+}
+
+// ConsolidatedLevel is a price level of one exchange in a consolidated book
+type ConsolidatedLevel struct {
+	Exchange string  `json:"exchange"`
+	Price    float64 `json:"price"`
+	Volume   float64 `json:"volume"`
+	Priority int     `json:"priority"`
+	Stale    bool    `json:"stale"`
+}
+
+// ConsolidatedBook merges the books of every exchange quoting a symbol
+type ConsolidatedBook struct {
+	Symbol    string              `json:"symbol"`
+	Bids      []ConsolidatedLevel `json:"bids"`
+	Asks      []ConsolidatedLevel `json:"asks"`
+	Timestamp time.Time           `json:"timestamp"`
+}
+
+// SetFeedPriority sets how exchanges quoting the same symbol are ranked
+func (m *Manager) SetFeedPriority(config FeedPriorityConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.priority = config
+}
+
+// FeedPriority returns how exchanges quoting the same symbol are ranked
+func (m *Manager) FeedPriority() FeedPriorityConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.priority
+}
+
+// IsBookStale reports whether an exchange's book for a symbol is downranked,
+// because it is missing, was restored from a snapshot and not yet updated, or
+// has not been updated within the configured StaleAfter
+func (m *Manager) IsBookStale(exchange, symbol string) bool {
+	m.mu.RLock()
+	book, ok := m.books[exchange+":"+symbol]
+	staleAfter := m.priority.StaleAfter
+	m.mu.RUnlock()
+
+	if !ok {
+		return true
+	}
+	return bookStale(book, staleAfter, time.Now())
+}
+
+func bookStale(book *OrderBook, staleAfter time.Duration, now time.Time) bool {
+	if book.IsStale() {
+		return true
+	}
+	return staleAfter > 0 && now.Sub(book.GetTimestamp()) > staleAfter
+}
+
+// GetConsolidatedBook merges every exchange's book for a symbol into one,
+// keeping each level's exchange. Levels from fresh books come first, best
+// price first; equal prices are ordered by exchange priority, then name.
+// Levels from stale books follow in the same order. Each side is cut to
+// depth levels if depth is positive.
+func (m *Manager) GetConsolidatedBook(symbol string, depth int) *ConsolidatedBook {
+	m.mu.RLock()
+	config := m.priority
+	books := make(map[string]*OrderBook)
+	for key, book := range m.books {
+		if exchange, bookSymbol, ok := strings.Cut(key, ":"); ok && bookSymbol == symbol {
+			books[exchange] = book
+		}
+	}
+	m.mu.RUnlock()
+
+	now := time.Now()
+	consolidated := &ConsolidatedBook{
+		Symbol:    symbol,
+		Bids:      make([]ConsolidatedLevel, 0),
+		Asks:      make([]ConsolidatedLevel, 0),
+		Timestamp: now,
+	}
+	for exchange, book := range books {
+		stale := bookStale(book, config.StaleAfter, now)
+		priority := config.Priority(exchange)
+		bids, asks := book.levels()
+		for _, level := range bids {
+			consolidated.Bids = append(consolidated.Bids, ConsolidatedLevel{exchange, level.Price, level.Volume, priority, stale})
+		}
+		for _, level := range asks {
+			consolidated.Asks = append(consolidated.Asks, ConsolidatedLevel{exchange, level.Price, level.Volume, priority, stale})
+		}
+	}
+
+	sortConsolidated(consolidated.Bids, true)
+	sortConsolidated(consolidated.Asks, false)
+	if depth > 0 {
+		consolidated.Bids = consolidated.Bids[:min(depth, len(consolidated.Bids))]
+		consolidated.Asks = consolidated.Asks[:min(depth, len(consolidated.Asks))]
+	}
+	return consolidated
+}
+
+// sortConsolidated orders one side of a consolidated book
+func sortConsolidated(levels []ConsolidatedLevel, bids bool) {
+	sort.Slice(levels, func(i, j int) bool {
+		a, b := levels[i], levels[j]
+		if a.Stale != b.Stale {
+			return !a.Stale
+		}
+		if a.Price != b.Price {
+			if bids {
+				return a.Price > b.Price
+			}
+			return a.Price < b.Price
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		return a.Exchange < b.Exchange
+	})
+}
@@ -0,0 +1,108 @@
+package orderbook
+
+import (
+	"testing"
+	"time"
+)
+
+func consolidatedExchanges(levels []ConsolidatedLevel) []string {
+	exchanges := make([]string, 0, len(levels))
+	for _, level := range levels {
+		exchanges = append(exchanges, level.Exchange)
+	}
+	return exchanges
+}
+
+func sameExchanges(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestConsolidatedBookPriorityTieBreak(t *testing.T) {
+	m := NewManager()
+	m.SetFeedPriority(FeedPriorityConfig{Priorities: map[string]int{"coinbase": 2, "kraken": 1}})
+
+	// All three venues quote the same best bid and ask
+	m.UpdateOrderBook("binance", "BTCUSDT", priceLevels(100, 1, 99, 1), priceLevels(101, 1))
+	m.UpdateOrderBook("coinbase", "BTCUSDT", priceLevels(100, 2), priceLevels(101, 2))
+	m.UpdateOrderBook("kraken", "BTCUSDT", priceLevels(100, 3), priceLevels(101, 3, 102, 1))
+	m.UpdateOrderBook("kraken", "ETHUSDT", priceLevels(10, 1), priceLevels(11, 1))
+
+	book := m.GetConsolidatedBook("BTCUSDT", 0)
+	if want := []string{"coinbase", "kraken", "binance", "binance"}; !sameExchanges(consolidatedExchanges(book.Bids), want) {
+		t.Errorf("Expected bids from %v, got %v", want, consolidatedExchanges(book.Bids))
+	}
+	if book.Bids[3].Price != 99 {
+		t.Errorf("Expected the worse bid last, got %v", book.Bids[3])
+	}
+	if want := []string{"coinbase", "kraken", "binance", "kraken"}; !sameExchanges(consolidatedExchanges(book.Asks), want) {
+		t.Errorf("Expected asks from %v, got %v", want, consolidatedExchanges(book.Asks))
+	}
+	if book.Asks[0].Priority != 2 || book.Asks[0].Volume != 2 {
+		t.Errorf("Expected coinbase's level with priority 2, got %v", book.Asks[0])
+	}
+
+	// Depth cuts each side
+	book = m.GetConsolidatedBook("BTCUSDT", 2)
+	if len(book.Bids) != 2 || len(book.Asks) != 2 {
+		t.Errorf("Expected 2 levels a side, got %d bids and %d asks", len(book.Bids), len(book.Asks))
+	}
+
+	// Unknown symbols have an empty book
+	book = m.GetConsolidatedBook("SOLUSDT", 0)
+	if len(book.Bids) != 0 || len(book.Asks) != 0 {
+		t.Errorf("Expected an empty book, got %v", book)
+	}
+}
+
+func TestConsolidatedBookStaleDownrank(t *testing.T) {
+	m := NewManager()
+	m.SetFeedPriority(FeedPriorityConfig{
+		Priorities: map[string]int{"binance": 5},
+		StaleAfter: time.Second,
+	})
+
+	// Binance quotes the best prices at the highest priority, but stopped updating
+	m.UpdateOrderBook("binance", "BTCUSDT", priceLevels(100.5, 1), priceLevels(100.6, 1))
+	m.UpdateOrderBook("coinbase", "BTCUSDT", priceLevels(100, 1), priceLevels(101, 1))
+	m.GetOrderBook("binance:BTCUSDT").Timestamp = time.Now().Add(-time.Minute)
+
+	if !m.IsBookStale("binance", "BTCUSDT") || m.IsBookStale("coinbase", "BTCUSDT") {
+		t.Errorf("Expected only binance to be stale")
+	}
+	if !m.IsBookStale("kraken", "BTCUSDT") {
+		t.Errorf("Expected a missing book to be stale")
+	}
+
+	book := m.GetConsolidatedBook("BTCUSDT", 0)
+	if want := []string{"coinbase", "binance"}; !sameExchanges(consolidatedExchanges(book.Bids), want) {
+		t.Errorf("Expected bids from %v, got %v", want, consolidatedExchanges(book.Bids))
+	}
+	if want := []string{"coinbase", "binance"}; !sameExchanges(consolidatedExchanges(book.Asks), want) {
+		t.Errorf("Expected asks from %v, got %v", want, consolidatedExchanges(book.Asks))
+	}
+	if book.Bids[0].Stale || !book.Bids[1].Stale {
+		t.Errorf("Expected only binance's levels flagged stale, got %v", book.Bids)
+	}
+
+	// A fresh update restores its rank
+	m.UpdateOrderBook("binance", "BTCUSDT", priceLevels(100.5, 1), priceLevels(100.6, 1))
+	book = m.GetConsolidatedBook("BTCUSDT", 0)
+	if book.Bids[0].Exchange != "binance" || book.Asks[0].Exchange != "binance" {
+		t.Errorf("Expected binance first once fresh, got %v %v", book.Bids[0], book.Asks[0])
+	}
+
+	// Without StaleAfter only books restored from a snapshot are downranked
+	m.SetFeedPriority(FeedPriorityConfig{})
+	m.GetOrderBook("binance:BTCUSDT").Timestamp = time.Now().Add(-time.Hour)
+	if m.IsBookStale("binance", "BTCUSDT") {
+		t.Errorf("Expected no staleness downranking without StaleAfter")
+	}
+}
@@ -0,0 +1,207 @@
+package orderbook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"velocimex/internal/normalizer"
+)
+
+// SnapshotConfig configures periodic persistence of order books, so that
+// after a restart books are available before feeds repopulate them
+type SnapshotConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Path     string        `yaml:"path"`     // File the books are written to
+	Interval time.Duration `yaml:"interval"` // How often books are saved, default 30s
+}
+
+// DefaultSnapshotConfig returns default snapshot configuration
+func DefaultSnapshotConfig() SnapshotConfig {
+	return SnapshotConfig{
+		Enabled:  false,
+		Path:     "data/orderbooks.json",
+		Interval: 30 * time.Second,
+	}
+}
+
+// Validate checks that an enabled snapshot has a path and a usable interval
+func (c SnapshotConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Path == "" {
+		return fmt.Errorf("order book snapshot path is required")
+	}
+	if c.Interval < 0 {
+		return fmt.Errorf("order book snapshot interval cannot be negative")
+	}
+	return nil
+}
+
+// bookSnapshot is a saved order book, keyed as in the manager
+type bookSnapshot struct {
+	Key       string                  `json:"key"`
+	Timestamp time.Time               `json:"timestamp"`
+	Bids      []normalizer.PriceLevel `json:"bids"`
+	Asks      []normalizer.PriceLevel `json:"asks"`
+}
+
+// snapshotFile is the on-disk format of a snapshot
+type snapshotFile struct {
+	SavedAt time.Time      `json:"saved_at"`
+	Books   []bookSnapshot `json:"books"`
+}
+
+// SaveSnapshot writes every non-empty book to path. The file is replaced
+// atomically so a crash mid-write leaves the previous snapshot intact.
+func (m *Manager) SaveSnapshot(path string) error {
+	snapshot := snapshotFile{SavedAt: time.Now(), Books: make([]bookSnapshot, 0)}
+	for key, book := range m.GetAllOrderBooks() {
+		book.mu.RLock()
+		if len(book.Bids) > 0 || len(book.Asks) > 0 {
+			snapshot.Books = append(snapshot.Books, bookSnapshot{
+				Key:       key,
+				Timestamp: book.Timestamp,
+				Bids:      append([]normalizer.PriceLevel(nil), book.Bids...),
+				Asks:      append([]normalizer.PriceLevel(nil), book.Asks...),
+			})
+		}
+		book.mu.RUnlock()
+	}
+	sort.Slice(snapshot.Books, func(i, j int) bool { return snapshot.Books[i].Key < snapshot.Books[j].Key })
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode order book snapshot: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create snapshot directory: %w", err)
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write order book snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace order book snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot restores the books saved at path and returns how many were
+// restored. Restored books keep their saved timestamp and are flagged stale
+// until a feed updates them. Books that already exist are left alone, and a
+// missing snapshot file is not an error.
+func (m *Manager) LoadSnapshot(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read order book snapshot: %w", err)
+	}
+
+	var snapshot snapshotFile
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return 0, fmt.Errorf("failed to decode order book snapshot: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	restored := 0
+	for _, saved := range snapshot.Books {
+		if _, exists := m.books[saved.Key]; exists {
+			continue
+		}
+		book := NewOrderBook(saved.Key)
+		book.Timestamp = saved.Timestamp
+		book.Bids = saved.Bids
+		book.Asks = saved.Asks
+		book.stats = deriveStats(book.Bids, book.Asks)
+		book.stale = true
+		m.books[saved.Key] = book
+		restored++
+	}
+	return restored, nil
+}
+
+// Snapshotter periodically saves a manager's books to disk
+type Snapshotter struct {
+	manager *Manager
+	config  SnapshotConfig
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewSnapshotter creates a snapshotter saving the manager's books to config.Path
+func NewSnapshotter(manager *Manager, config SnapshotConfig) *Snapshotter {
+	if config.Interval <= 0 {
+		config.Interval = DefaultSnapshotConfig().Interval
+	}
+	return &Snapshotter{
+		manager: manager,
+		config:  config,
+	}
+}
+
+// Start begins saving snapshots in the background
+func (s *Snapshotter) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return fmt.Errorf("order book snapshotter already running")
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.save()
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the snapshotter and saves a final snapshot
+func (s *Snapshotter) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		s.wg.Wait()
+		s.save()
+	}
+}
+
+// save writes a snapshot, logging rather than returning a failure
+func (s *Snapshotter) save() {
+	if err := s.manager.SaveSnapshot(s.config.Path); err != nil {
+		log.Printf("Failed to snapshot order books: %v", err)
+	}
+}
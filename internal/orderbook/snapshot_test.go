@@ -0,0 +1,126 @@
+package orderbook
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"velocimex/internal/normalizer"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "books", "orderbooks.json")
+
+	bids := []normalizer.PriceLevel{{Price: 100, Volume: 1}, {Price: 99, Volume: 2}}
+	asks := []normalizer.PriceLevel{{Price: 101, Volume: 3}}
+	before := NewManager()
+	before.UpdateOrderBook("binance", "BTCUSDT", bids, asks)
+	before.GetOrderBook("empty") // Empty books are not saved
+	savedAt := before.GetOrderBook("binance:BTCUSDT").GetTimestamp()
+
+	if err := before.SaveSnapshot(path); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+
+	// A new manager stands in for the restarted process
+	after := NewManager()
+	restored, err := after.LoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("LoadSnapshot: %v", err)
+	}
+	if restored != 1 {
+		t.Fatalf("restored %d books, want 1", restored)
+	}
+
+	if _, ok := after.BookAge("binance", "BTCUSDT"); !ok {
+		t.Fatal("restored book not found")
+	}
+
+	book := after.GetOrderBook("binance:BTCUSDT")
+	gotBids, gotAsks := book.GetDepth(10)
+	if !reflect.DeepEqual(gotBids, bids) || !reflect.DeepEqual(gotAsks, asks) {
+		t.Errorf("restored levels = %v / %v, want %v / %v", gotBids, gotAsks, bids, asks)
+	}
+	if !book.GetTimestamp().Equal(savedAt) {
+		t.Errorf("restored timestamp = %v, want %v", book.GetTimestamp(), savedAt)
+	}
+	if !book.IsStale() {
+		t.Error("restored book should be stale")
+	}
+
+	// The first feed update makes the book fresh
+	after.UpdateOrderBook("binance", "BTCUSDT", []normalizer.PriceLevel{{Price: 100.5, Volume: 1}}, asks)
+	if book.IsStale() {
+		t.Error("book should not be stale after an update")
+	}
+	if bid := book.GetBestBid(); bid == nil || bid.Price != 100.5 {
+		t.Errorf("best bid = %v, want 100.5", bid)
+	}
+}
+
+func TestSnapshotKeepsLiveBooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orderbooks.json")
+
+	saved := NewManager()
+	saved.UpdateOrderBook("binance", "BTCUSDT", []normalizer.PriceLevel{{Price: 100, Volume: 1}}, nil)
+	if err := saved.SaveSnapshot(path); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+
+	// A book a feed has already updated is newer than the snapshot
+	live := NewManager()
+	live.UpdateOrderBook("binance", "BTCUSDT", []normalizer.PriceLevel{{Price: 105, Volume: 1}}, nil)
+	restored, err := live.LoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("LoadSnapshot: %v", err)
+	}
+	if restored != 0 {
+		t.Errorf("restored %d books, want 0", restored)
+	}
+	book := live.GetOrderBook("binance:BTCUSDT")
+	if book.IsStale() || book.GetBestBid().Price != 105 {
+		t.Errorf("live book was replaced by the snapshot")
+	}
+}
+
+func TestLoadSnapshotErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	// No snapshot yet on first start
+	restored, err := NewManager().LoadSnapshot(filepath.Join(dir, "missing.json"))
+	if err != nil || restored != 0 {
+		t.Errorf("missing snapshot = %d, %v; want 0, nil", restored, err)
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewManager().LoadSnapshot(corrupt); err == nil {
+		t.Error("expected error for a corrupt snapshot")
+	}
+}
+
+func TestSnapshotterSavesOnStop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orderbooks.json")
+
+	manager := NewManager()
+	snapshotter := NewSnapshotter(manager, SnapshotConfig{Enabled: true, Path: path, Interval: time.Hour})
+	if err := snapshotter.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := snapshotter.Start(context.Background()); err == nil {
+		t.Error("expected error starting twice")
+	}
+
+	manager.UpdateOrderBook("binance", "ETHUSDT", []normalizer.PriceLevel{{Price: 3000, Volume: 1}}, nil)
+	snapshotter.Stop()
+
+	restored, err := NewManager().LoadSnapshot(path)
+	if err != nil || restored != 1 {
+		t.Errorf("snapshot after stop = %d, %v; want 1, nil", restored, err)
+	}
+}
@@ -0,0 +1,56 @@
+package orderbook
+
+import "velocimex/internal/normalizer"
+
+// ImbalanceDepth is the number of levels per side BookStats.Imbalance is
+// measured over
+const ImbalanceDepth = 5
+
+// BookStats are values derived from a book's levels, recomputed whenever the
+// levels are replaced
+type BookStats struct {
+	MidPrice  float64 `json:"mid_price"`
+	Spread    float64 `json:"spread"`
+	Imbalance float64 `json:"imbalance"` // Bid minus ask volume over their sum, top ImbalanceDepth levels, from -1 to 1
+}
+
+// deriveStats computes the stats of levels sorted best first
+func deriveStats(bids, asks []normalizer.PriceLevel) BookStats {
+	stats := BookStats{Imbalance: Imbalance(bids, asks, ImbalanceDepth)}
+	if len(bids) > 0 && len(asks) > 0 {
+		stats.MidPrice = (bids[0].Price + asks[0].Price) / 2
+		stats.Spread = asks[0].Price - bids[0].Price
+	}
+	return stats
+}
+
+// Imbalance returns the bid volume minus the ask volume over their sum, for
+// the top depth levels of each side sorted best first: 1 when only bids are
+// quoted, -1 when only asks are, 0 for an empty book. A depth of zero or less
+// uses every level.
+func Imbalance(bids, asks []normalizer.PriceLevel, depth int) float64 {
+	bidVolume, askVolume := sideVolume(bids, depth), sideVolume(asks, depth)
+	if bidVolume+askVolume == 0 {
+		return 0
+	}
+	return (bidVolume - askVolume) / (bidVolume + askVolume)
+}
+
+func sideVolume(levels []normalizer.PriceLevel, depth int) float64 {
+	if depth > 0 && depth < len(levels) {
+		levels = levels[:depth]
+	}
+	volume := 0.0
+	for _, level := range levels {
+		volume += level.Volume
+	}
+	return volume
+}
+
+// Stats returns the values derived from the book's current levels
+func (b *OrderBook) Stats() BookStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.stats
+}
@@ -0,0 +1,78 @@
+package orderbook
+
+import (
+	"time"
+
+	"velocimex/internal/normalizer"
+)
+
+// TopOfBookConfig configures top-of-book change events
+type TopOfBookConfig struct {
+	// Enabled publishes the events to WebSocket clients; strategies receive them regardless
+	Enabled bool `yaml:"enabled"`
+	// PriceOnly ignores size changes at an unchanged best price
+	PriceOnly bool `yaml:"priceOnly"`
+}
+
+// TopOfBookEvent reports a change of an exchange book's best bid or ask.
+// A nil level means that side of the book was, or became, empty.
+type TopOfBookEvent struct {
+	Exchange  string                 `json:"exchange"`
+	Symbol    string                 `json:"symbol"`
+	OldBid    *normalizer.PriceLevel `json:"old_bid"`
+	OldAsk    *normalizer.PriceLevel `json:"old_ask"`
+	NewBid    *normalizer.PriceLevel `json:"new_bid"`
+	NewAsk    *normalizer.PriceLevel `json:"new_ask"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// topOfBook is the best bid and ask of a book
+type topOfBook struct {
+	bid *normalizer.PriceLevel
+	ask *normalizer.PriceLevel
+}
+
+// top returns copies of the best levels. The caller holds b.mu.
+func (b *OrderBook) top() topOfBook {
+	var top topOfBook
+	if len(b.Bids) > 0 {
+		bid := b.Bids[0]
+		top.bid = &bid
+	}
+	if len(b.Asks) > 0 {
+		ask := b.Asks[0]
+		top.ask = &ask
+	}
+	return top
+}
+
+// changed reports whether the top of book differs from before
+func (t topOfBook) changed(before topOfBook, priceOnly bool) bool {
+	return levelChanged(before.bid, t.bid, priceOnly) || levelChanged(before.ask, t.ask, priceOnly)
+}
+
+func levelChanged(before, after *normalizer.PriceLevel, priceOnly bool) bool {
+	if before == nil || after == nil {
+		return before != after
+	}
+	if before.Price != after.Price {
+		return true
+	}
+	return !priceOnly && before.Volume != after.Volume
+}
+
+// SetTopOfBookConfig sets how top-of-book changes are detected
+func (m *Manager) SetTopOfBookConfig(config TopOfBookConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.topConfig = config
+}
+
+// SubscribeTopOfBook registers a handler called whenever an exchange book's
+// best bid or ask changes. Handlers run on the updating feed's goroutine and
+// must not block.
+func (m *Manager) SubscribeTopOfBook(handler func(event TopOfBookEvent)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.topHandlers = append(m.topHandlers, handler)
+}
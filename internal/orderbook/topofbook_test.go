@@ -0,0 +1,109 @@
+package orderbook
+
+import (
+	"testing"
+
+	"velocimex/internal/normalizer"
+)
+
+func priceLevels(prices ...float64) []normalizer.PriceLevel {
+	out := make([]normalizer.PriceLevel, 0, len(prices)/2)
+	for i := 0; i+1 < len(prices); i += 2 {
+		out = append(out, normalizer.PriceLevel{Price: prices[i], Volume: prices[i+1]})
+	}
+	return out
+}
+
+func recordTopOfBook(m *Manager) *[]TopOfBookEvent {
+	events := &[]TopOfBookEvent{}
+	m.SubscribeTopOfBook(func(event TopOfBookEvent) {
+		*events = append(*events, event)
+	})
+	return events
+}
+
+func TestTopOfBookEvents(t *testing.T) {
+	m := NewManager()
+	events := recordTopOfBook(m)
+
+	// First update populates an empty book
+	m.UpdateOrderBook("binance", "BTCUSDT", priceLevels(100, 1, 99, 2), priceLevels(101, 1, 102, 2))
+	if len(*events) != 1 {
+		t.Fatalf("Expected 1 event for the first update, got %d", len(*events))
+	}
+	first := (*events)[0]
+	if first.Exchange != "binance" || first.Symbol != "BTCUSDT" {
+		t.Errorf("Unexpected event market %s:%s", first.Exchange, first.Symbol)
+	}
+	if first.OldBid != nil || first.OldAsk != nil {
+		t.Errorf("Expected no old levels for an empty book, got %v %v", first.OldBid, first.OldAsk)
+	}
+	if first.NewBid == nil || first.NewBid.Price != 100 || first.NewAsk == nil || first.NewAsk.Price != 101 {
+		t.Errorf("Unexpected new top of book %v %v", first.NewBid, first.NewAsk)
+	}
+
+	// Deeper levels change, the top does not
+	m.UpdateOrderBook("binance", "BTCUSDT", priceLevels(100, 1, 98, 5), priceLevels(101, 1, 103, 4))
+	if len(*events) != 1 {
+		t.Fatalf("Expected no event for a deeper-level-only update, got %d events", len(*events))
+	}
+
+	// Best bid moves up
+	m.UpdateOrderBook("binance", "BTCUSDT", priceLevels(100.5, 0.5, 100, 1), priceLevels(101, 1, 103, 4))
+	if len(*events) != 2 {
+		t.Fatalf("Expected an event for a best bid change, got %d events", len(*events))
+	}
+	moved := (*events)[1]
+	if moved.OldBid.Price != 100 || moved.OldBid.Volume != 1 {
+		t.Errorf("Expected old bid 100 x 1, got %v", moved.OldBid)
+	}
+	if moved.NewBid.Price != 100.5 || moved.NewBid.Volume != 0.5 {
+		t.Errorf("Expected new bid 100.5 x 0.5, got %v", moved.NewBid)
+	}
+	if moved.OldAsk.Price != 101 || moved.NewAsk.Price != 101 {
+		t.Errorf("Expected unchanged ask 101, got %v -> %v", moved.OldAsk, moved.NewAsk)
+	}
+
+	// Size change at the best ask
+	m.UpdateOrderBook("binance", "BTCUSDT", priceLevels(100.5, 0.5, 100, 1), priceLevels(101, 3, 103, 4))
+	if len(*events) != 3 {
+		t.Fatalf("Expected an event for a best ask size change, got %d events", len(*events))
+	}
+
+	// Ask side empties
+	m.UpdateOrderBook("binance", "BTCUSDT", priceLevels(100.5, 0.5, 100, 1), nil)
+	if len(*events) != 4 || (*events)[3].NewAsk != nil {
+		t.Fatalf("Expected an event with no new ask when the side empties, got %v", *events)
+	}
+}
+
+func TestTopOfBookPriceOnly(t *testing.T) {
+	m := NewManager()
+	m.SetTopOfBookConfig(TopOfBookConfig{PriceOnly: true})
+	events := recordTopOfBook(m)
+
+	m.UpdateOrderBook("kraken", "ETHUSD", priceLevels(10, 1), priceLevels(11, 1))
+	m.UpdateOrderBook("kraken", "ETHUSD", priceLevels(10, 7), priceLevels(11, 2))
+	if len(*events) != 1 {
+		t.Fatalf("Expected size changes to be ignored, got %d events", len(*events))
+	}
+
+	m.UpdateOrderBook("kraken", "ETHUSD", priceLevels(10, 7), priceLevels(10.5, 2))
+	if len(*events) != 2 {
+		t.Fatalf("Expected an event for a best ask price change, got %d events", len(*events))
+	}
+}
+
+func TestTopOfBookPerMarket(t *testing.T) {
+	m := NewManager()
+	events := recordTopOfBook(m)
+
+	m.UpdateOrderBook("binance", "BTCUSDT", priceLevels(100, 1), priceLevels(101, 1))
+	m.UpdateOrderBook("coinbase", "BTCUSDT", priceLevels(100, 1), priceLevels(101, 1))
+	if len(*events) != 2 {
+		t.Fatalf("Expected each exchange book to report its own top of book, got %d events", len(*events))
+	}
+	if (*events)[1].Exchange != "coinbase" {
+		t.Errorf("Expected the second event from coinbase, got %s", (*events)[1].Exchange)
+	}
+}
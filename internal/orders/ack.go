@@ -0,0 +1,62 @@
+package orders
+
+import (
+	"log"
+	"time"
+)
+
+// CancelReasonAckTimeout is the cancel reason of orders the exchange did not
+// acknowledge within the ack timeout
+const CancelReasonAckTimeout = "ack_timeout"
+
+// scheduleAckTimeout arms a timer that cancels the order if the exchange has
+// not acknowledged it by the timeout. Must be called with m.mu held.
+func (m *Manager) scheduleAckTimeout(orderID string, timeout time.Duration) {
+	m.ackTimers[orderID] = time.AfterFunc(timeout, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		delete(m.ackTimers, orderID)
+		if order, exists := m.orders[orderID]; exists && order.AcknowledgedAt == nil {
+			m.cancelUnacknowledged(order, time.Now())
+		}
+	})
+}
+
+// acknowledge records the first update received for an order and disarms
+// its ack timeout. Must be called with m.mu held.
+func (m *Manager) acknowledge(order *Order, at time.Time) {
+	if order.AcknowledgedAt != nil {
+		return
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	order.AcknowledgedAt = &at
+
+	if timer, exists := m.ackTimers[order.ID]; exists {
+		timer.Stop()
+		delete(m.ackTimers, order.ID)
+	}
+}
+
+// cancelUnacknowledged cancels an order still waiting for the exchange's
+// acknowledgment and flags it with the ack timeout reason. Must be called
+// with m.mu held.
+func (m *Manager) cancelUnacknowledged(order *Order, now time.Time) {
+	if order.Status != OrderStatusPending && order.Status != OrderStatusSubmitted {
+		return
+	}
+
+	order.Status = OrderStatusCancelled
+	order.CancelReason = CancelReasonAckTimeout
+	order.UpdatedAt = now
+	m.finishOrder(order)
+	m.recordHistory(order, OrderEventCancelled, CancelReasonAckTimeout, nil, now)
+	m.refreshSpreadForOrder(order.ID)
+
+	log.Printf("Order %s not acknowledged within %s, cancelled", order.ID, m.config.AckTimeout)
+	if m.metrics != nil {
+		m.metrics.RecordOrderEvent("order_ack_timeout", "warning")
+	}
+}
@@ -0,0 +1,277 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"velocimex/internal/metrics"
+	"velocimex/internal/orderbook"
+)
+
+// CircuitBreakerConfig configures the per-symbol circuit breakers
+type CircuitBreakerConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// MaxMovePercent is the largest price move, in percent, tolerated within the window
+	MaxMovePercent float64       `json:"max_move_percent" yaml:"maxMovePercent"`
+	Window         time.Duration `json:"window" yaml:"window"`
+	Cooldown       time.Duration `json:"cooldown" yaml:"cooldown"`            // How long a tripped symbol stays halted
+	CheckInterval  time.Duration `json:"check_interval" yaml:"checkInterval"` // How often book prices are sampled
+}
+
+// DefaultCircuitBreakerConfig returns default circuit breaker configuration
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		Enabled:        false,
+		MaxMovePercent: 10,
+		Window:         time.Minute,
+		Cooldown:       5 * time.Minute,
+		CheckInterval:  time.Second,
+	}
+}
+
+// CircuitBreakerEvent reports a symbol being halted or resumed
+type CircuitBreakerEvent struct {
+	Symbol      string    `json:"symbol"`
+	Exchange    string    `json:"exchange,omitempty"`
+	Halted      bool      `json:"halted"`
+	MovePercent float64   `json:"move_percent,omitempty"`
+	FromPrice   float64   `json:"from_price,omitempty"`
+	ToPrice     float64   `json:"to_price,omitempty"`
+	ResumeAt    time.Time `json:"resume_at,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// priceSample is one observed price of a book
+type priceSample struct {
+	price float64
+	at    time.Time
+}
+
+// symbolHalt is an active circuit breaker halt
+type symbolHalt struct {
+	reason   string
+	resumeAt time.Time
+}
+
+// CircuitBreaker halts trading on a symbol when its price moves more than the
+// configured percent within the window, and resumes it once the cooldown has
+// passed. Prices are tracked per exchange book so that differences between
+// venues are not mistaken for moves; a move on any venue halts the symbol.
+type CircuitBreaker struct {
+	config  CircuitBreakerConfig
+	books   *orderbook.Manager
+	metrics *metrics.Wrapper
+	onEvent func(CircuitBreakerEvent)
+
+	mu      sync.RWMutex
+	history map[string][]priceSample // exchange:symbol -> samples within the window
+	halts   map[string]symbolHalt    // symbol -> halt
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewCircuitBreaker creates circuit breakers fed from the given order books
+func NewCircuitBreaker(config CircuitBreakerConfig, books *orderbook.Manager, metrics *metrics.Wrapper) *CircuitBreaker {
+	defaults := DefaultCircuitBreakerConfig()
+	if config.MaxMovePercent <= 0 {
+		config.MaxMovePercent = defaults.MaxMovePercent
+	}
+	if config.Window <= 0 {
+		config.Window = defaults.Window
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = defaults.Cooldown
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = defaults.CheckInterval
+	}
+
+	return &CircuitBreaker{
+		config:  config,
+		books:   books,
+		metrics: metrics,
+		history: make(map[string][]priceSample),
+		halts:   make(map[string]symbolHalt),
+	}
+}
+
+// SetEventHandler sets a callback invoked when a symbol is halted or resumed, e.g. to raise an alert
+func (b *CircuitBreaker) SetEventHandler(handler func(event CircuitBreakerEvent)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onEvent = handler
+}
+
+// Start begins sampling book prices
+func (b *CircuitBreaker) Start(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.cancel != nil {
+		return fmt.Errorf("circuit breaker already running")
+	}
+	if b.books == nil {
+		return fmt.Errorf("circuit breaker has no order books")
+	}
+
+	ctx, b.cancel = context.WithCancel(ctx)
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+
+		ticker := time.NewTicker(b.config.CheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				b.sample(now)
+			}
+		}
+	}()
+
+	log.Printf("Circuit breakers started: %.2f%% move within %s halts for %s",
+		b.config.MaxMovePercent, b.config.Window, b.config.Cooldown)
+	return nil
+}
+
+// Stop stops sampling book prices
+func (b *CircuitBreaker) Stop() {
+	b.mu.Lock()
+	cancel := b.cancel
+	b.cancel = nil
+	b.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		b.wg.Wait()
+	}
+}
+
+// SymbolHalted reports whether a symbol is halted by its circuit breaker and why
+func (b *CircuitBreaker) SymbolHalted(symbol string) (bool, string) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	halt, ok := b.halts[symbol]
+	if !ok {
+		return false, ""
+	}
+	return true, halt.reason
+}
+
+// sample records the mid price of every book and resumes expired halts
+func (b *CircuitBreaker) sample(now time.Time) {
+	for key, book := range b.books.GetAllOrderBooks() {
+		exchange, symbol, ok := strings.Cut(key, ":")
+		if !ok {
+			continue
+		}
+		bid, ask := book.GetBestBid(), book.GetBestAsk()
+		if bid == nil || ask == nil || bid.Price <= 0 || ask.Price <= 0 {
+			continue
+		}
+		b.RecordPrice(exchange, symbol, (bid.Price+ask.Price)/2, now)
+	}
+	b.resumeExpired(now)
+}
+
+// RecordPrice adds a price observation for an exchange's book and trips the
+// symbol's breaker if the price has moved too far within the window
+func (b *CircuitBreaker) RecordPrice(exchange, symbol string, price float64, at time.Time) {
+	if price <= 0 {
+		return
+	}
+	key := fmt.Sprintf("%s:%s", exchange, symbol)
+
+	b.mu.Lock()
+	samples := b.history[key]
+	cutoff := at.Add(-b.config.Window)
+	start := 0
+	for start < len(samples) && samples[start].at.Before(cutoff) {
+		start++
+	}
+	samples = append(samples[start:], priceSample{price: price, at: at})
+	b.history[key] = samples
+
+	if _, halted := b.halts[symbol]; halted {
+		b.mu.Unlock()
+		return
+	}
+
+	// The largest move is from the window's extreme furthest from the current price
+	from := price
+	for _, sample := range samples {
+		if math.Abs(price-sample.price)/sample.price > math.Abs(price-from)/from {
+			from = sample.price
+		}
+	}
+	move := (price - from) / from * 100
+	if math.Abs(move) <= b.config.MaxMovePercent {
+		b.mu.Unlock()
+		return
+	}
+
+	event := CircuitBreakerEvent{
+		Symbol:      symbol,
+		Exchange:    exchange,
+		Halted:      true,
+		MovePercent: move,
+		FromPrice:   from,
+		ToPrice:     price,
+		ResumeAt:    at.Add(b.config.Cooldown),
+		Timestamp:   at,
+	}
+	reason := fmt.Sprintf("%s moved %.2f%% on %s within %s (%.8g -> %.8g)",
+		symbol, move, exchange, b.config.Window, from, price)
+	b.halts[symbol] = symbolHalt{reason: reason, resumeAt: event.ResumeAt}
+	onEvent := b.onEvent
+	b.mu.Unlock()
+
+	log.Printf("Circuit breaker tripped: %s; halted until %s", reason, event.ResumeAt.Format(time.RFC3339))
+	if b.metrics != nil {
+		b.metrics.RecordRiskEvent("circuit_breaker_tripped", "critical")
+	}
+	if onEvent != nil {
+		onEvent(event)
+	}
+}
+
+// resumeExpired lifts halts whose cooldown has passed. The symbol's price
+// history is cleared so the pre-halt prices cannot immediately trip it again.
+func (b *CircuitBreaker) resumeExpired(now time.Time) {
+	b.mu.Lock()
+	var resumed []CircuitBreakerEvent
+	for symbol, halt := range b.halts {
+		if now.Before(halt.resumeAt) {
+			continue
+		}
+		delete(b.halts, symbol)
+		for key := range b.history {
+			if _, s, _ := strings.Cut(key, ":"); s == symbol {
+				delete(b.history, key)
+			}
+		}
+		resumed = append(resumed, CircuitBreakerEvent{Symbol: symbol, Timestamp: now})
+	}
+	onEvent := b.onEvent
+	b.mu.Unlock()
+
+	for _, event := range resumed {
+		log.Printf("Circuit breaker reset: trading resumed on %s", event.Symbol)
+		if b.metrics != nil {
+			b.metrics.RecordRiskEvent("circuit_breaker_reset", "info")
+		}
+		if onEvent != nil {
+			onEvent(event)
+		}
+	}
+}
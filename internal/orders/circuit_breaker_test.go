@@ -0,0 +1,163 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/normalizer"
+	"velocimex/internal/orderbook"
+)
+
+// recordedBreakerEvents collects circuit breaker events from the handler goroutine
+type recordedBreakerEvents struct {
+	mu     sync.Mutex
+	events []CircuitBreakerEvent
+}
+
+func (r *recordedBreakerEvents) record(event CircuitBreakerEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recordedBreakerEvents) get() []CircuitBreakerEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]CircuitBreakerEvent(nil), r.events...)
+}
+
+// TestCircuitBreakerHaltsAndResumes tests that a large move halts the symbol and the cooldown resumes it
+func TestCircuitBreakerHaltsAndResumes(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{
+		Enabled:        true,
+		MaxMovePercent: 5,
+		Window:         time.Minute,
+		Cooldown:       5 * time.Minute,
+	}, nil, nil)
+	var events recordedBreakerEvents
+	breaker.SetEventHandler(events.record)
+	manager.SetSymbolHalts(breaker)
+
+	start := time.Now()
+	breaker.RecordPrice("binance", "BTC/USD", 50000, start)
+	breaker.RecordPrice("binance", "BTC/USD", 51000, start.Add(10*time.Second))
+	halted, _ := breaker.SymbolHalted("BTC/USD")
+	assert.False(t, halted)
+
+	// A 10% drop within the window trips the breaker
+	breaker.RecordPrice("binance", "BTC/USD", 45900, start.Add(20*time.Second))
+	halted, reason := breaker.SymbolHalted("BTC/USD")
+	require.True(t, halted)
+	assert.Contains(t, reason, "BTC/USD")
+
+	recorded := events.get()
+	require.Len(t, recorded, 1)
+	assert.True(t, recorded[0].Halted)
+	assert.Equal(t, "binance", recorded[0].Exchange)
+	assert.InDelta(t, -10.0, recorded[0].MovePercent, 1e-9)
+	assert.Equal(t, 51000.0, recorded[0].FromPrice)
+
+	_, err := manager.SubmitOrder(ctx, heartbeatTestOrder())
+	assert.True(t, errors.Is(err, ErrSymbolHalted))
+
+	// Other symbols keep trading
+	other := heartbeatTestOrder()
+	other.Symbol = "ETH/USD"
+	_, err = manager.SubmitOrder(ctx, other)
+	assert.NoError(t, err)
+
+	// The halt holds until the cooldown has passed
+	breaker.resumeExpired(start.Add(4 * time.Minute))
+	halted, _ = breaker.SymbolHalted("BTC/USD")
+	assert.True(t, halted)
+
+	breaker.resumeExpired(start.Add(20*time.Second + 5*time.Minute))
+	halted, _ = breaker.SymbolHalted("BTC/USD")
+	assert.False(t, halted)
+
+	recorded = events.get()
+	require.Len(t, recorded, 2)
+	assert.False(t, recorded[1].Halted)
+	assert.Equal(t, "BTC/USD", recorded[1].Symbol)
+
+	_, err = manager.SubmitOrder(ctx, heartbeatTestOrder())
+	assert.NoError(t, err)
+
+	// The pre-halt prices are forgotten so trading at the new level does not re-trip
+	breaker.RecordPrice("binance", "BTC/USD", 46000, start.Add(6*time.Minute))
+	halted, _ = breaker.SymbolHalted("BTC/USD")
+	assert.False(t, halted)
+}
+
+// TestCircuitBreakerIgnoresMovesOutsideWindow tests that gradual moves and venue differences do not trip
+func TestCircuitBreakerIgnoresMovesOutsideWindow(t *testing.T) {
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{
+		Enabled:        true,
+		MaxMovePercent: 5,
+		Window:         time.Minute,
+		Cooldown:       time.Minute,
+	}, nil, nil)
+
+	start := time.Now()
+	price := 100.0
+	for i := 0; i < 10; i++ {
+		breaker.RecordPrice("binance", "BTC/USD", price, start.Add(time.Duration(i)*time.Minute))
+		price *= 1.04
+	}
+	halted, _ := breaker.SymbolHalted("BTC/USD")
+	assert.False(t, halted)
+
+	// A venue trading at a different level is tracked separately
+	breaker.RecordPrice("kraken", "BTC/USD", 80, start.Add(10*time.Minute))
+	halted, _ = breaker.SymbolHalted("BTC/USD")
+	assert.False(t, halted)
+}
+
+// TestCircuitBreakerSamplesOrderBooks tests that book updates drive the breaker end to end
+func TestCircuitBreakerSamplesOrderBooks(t *testing.T) {
+	books := orderbook.NewManager()
+	books.UpdateOrderBook("binance", "BTC/USD",
+		[]normalizer.PriceLevel{{Price: 49990, Volume: 1}},
+		[]normalizer.PriceLevel{{Price: 50010, Volume: 1}})
+
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{
+		Enabled:        true,
+		MaxMovePercent: 5,
+		Window:         time.Minute,
+		Cooldown:       100 * time.Millisecond,
+		CheckInterval:  5 * time.Millisecond,
+	}, books, nil)
+	var events recordedBreakerEvents
+	breaker.SetEventHandler(events.record)
+	require.NoError(t, breaker.Start(context.Background()))
+	defer breaker.Stop()
+
+	time.Sleep(20 * time.Millisecond)
+	books.UpdateOrderBook("binance", "BTC/USD",
+		[]normalizer.PriceLevel{{Price: 39990, Volume: 1}},
+		[]normalizer.PriceLevel{{Price: 40010, Volume: 1}})
+
+	assert.Eventually(t, func() bool {
+		halted, _ := breaker.SymbolHalted("BTC/USD")
+		return halted
+	}, time.Second, 5*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		halted, _ := breaker.SymbolHalted("BTC/USD")
+		return !halted
+	}, time.Second, 5*time.Millisecond)
+
+	recorded := events.get()
+	require.GreaterOrEqual(t, len(recorded), 2)
+	assert.True(t, recorded[0].Halted)
+	assert.False(t, recorded[1].Halted)
+}
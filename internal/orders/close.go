@@ -0,0 +1,137 @@
+package orders
+
+import (
+	"context"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// PositionCloseFailure is a position whose closing order could not be submitted
+type PositionCloseFailure struct {
+	Exchange string          `json:"exchange"`
+	Symbol   string          `json:"symbol"`
+	Side     OrderSide       `json:"side"`
+	Quantity decimal.Decimal `json:"quantity"`
+	Error    string          `json:"error"`
+}
+
+// CloseAllResult reports the orders submitted to flatten positions
+type CloseAllResult struct {
+	Orders []*Order               `json:"orders"`
+	Failed []PositionCloseFailure `json:"failed"`
+}
+
+// ClosePositions submits a market order against every open position matching
+// the filters, so that it is flattened on the exchange where it is held.
+// The orders are reduce-only: trading halts and the guards against new risk
+// do not block them, and a position is only closed for the quantity not
+// already covered by working closing orders. Positions whose order is
+// rejected are reported and the rest still closed.
+func (m *Manager) ClosePositions(ctx context.Context, filters map[string]interface{}) *CloseAllResult {
+	m.mu.RLock()
+	closing := m.workingCloseQuantities()
+	open := make([]Position, 0, len(m.positions))
+	for _, position := range m.positions {
+		if !position.Quantity.IsPositive() || !m.matchesPositionFilters(position, filters) {
+			continue
+		}
+		uncovered := *position
+		uncovered.Quantity = position.Quantity.Sub(closing[closeKey(position.Exchange, position.Symbol, closingSide(position.Side))])
+		if uncovered.Quantity.IsPositive() {
+			open = append(open, uncovered)
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(open, func(i, j int) bool {
+		if open[i].Exchange != open[j].Exchange {
+			return open[i].Exchange < open[j].Exchange
+		}
+		if open[i].Symbol != open[j].Symbol {
+			return open[i].Symbol < open[j].Symbol
+		}
+		return open[i].Side < open[j].Side
+	})
+
+	result := &CloseAllResult{
+		Orders: make([]*Order, 0, len(open)),
+		Failed: make([]PositionCloseFailure, 0),
+	}
+	for _, position := range open {
+		order, err := m.submitOrder(ctx, closingRequest(position), true)
+		if err != nil {
+			result.Failed = append(result.Failed, PositionCloseFailure{
+				Exchange: position.Exchange,
+				Symbol:   position.Symbol,
+				Side:     position.Side,
+				Quantity: position.Quantity,
+				Error:    err.Error(),
+			})
+			continue
+		}
+		result.Orders = append(result.Orders, order)
+	}
+
+	if m.metrics != nil {
+		m.metrics.RecordOrderEvent("positions_closed", "info")
+	}
+
+	return result
+}
+
+// closingRequest builds the market order that flattens a position. It is
+// tagged as closing so hedging mode reduces the position instead of opening
+// the opposite side.
+func closingRequest(position Position) *OrderRequest {
+	side := closingSide(position.Side)
+
+	// Market orders carry a reference price for order value and paper fills
+	price := position.CurrentPrice
+	if !price.IsPositive() {
+		price = position.EntryPrice
+	}
+
+	return &OrderRequest{
+		Exchange:   position.Exchange,
+		Symbol:     position.Symbol,
+		Side:       side,
+		Type:       OrderTypeMarket,
+		Quantity:   position.Quantity,
+		Price:      price,
+		StrategyID: position.StrategyID,
+		Tags:       map[string]string{PositionEffectTag: "close"},
+	}
+}
+
+// closingSide returns the order side that reduces a position on the given side
+func closingSide(side OrderSide) OrderSide {
+	if side == OrderSideSell {
+		return OrderSideBuy
+	}
+	return OrderSideSell
+}
+
+// closeKey identifies the closing orders working against one position
+func closeKey(exchange, symbol string, side OrderSide) string {
+	return exchange + ":" + symbol + ":" + string(side)
+}
+
+// workingCloseQuantities sums the unfilled quantity of the closing orders
+// still working, by exchange, symbol and side. The caller must hold m.mu.
+func (m *Manager) workingCloseQuantities() map[string]decimal.Decimal {
+	quantities := make(map[string]decimal.Decimal)
+	for _, order := range m.orders {
+		if order.Tags[PositionEffectTag] != "close" {
+			continue
+		}
+		switch order.Status {
+		case OrderStatusPending, OrderStatusSubmitted, OrderStatusPartial:
+		default:
+			continue
+		}
+		key := closeKey(order.Exchange, order.Symbol, order.Side)
+		quantities[key] = quantities[key].Add(order.Quantity.Sub(order.FilledQty))
+	}
+	return quantities
+}
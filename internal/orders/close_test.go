@@ -0,0 +1,245 @@
+package orders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exchangeRouter routes each symbol to a fixed exchange
+type exchangeRouter map[string]string
+
+func (r exchangeRouter) RouteOrder(ctx context.Context, req *OrderRequest) (*RoutingDecision, error) {
+	return &RoutingDecision{Exchange: r[req.Symbol], Symbol: req.Symbol, Side: req.Side, Timestamp: time.Now()}, nil
+}
+
+func (r exchangeRouter) UpdateMarketData(symbol string, data interface{}) {}
+
+func (r exchangeRouter) GetBestPrice(ctx context.Context, symbol string, side OrderSide, quantity decimal.Decimal) (*RoutingDecision, error) {
+	return r.RouteOrder(ctx, &OrderRequest{Symbol: symbol, Side: side})
+}
+
+// TestClosePositions tests that every open long and short position gets an
+// opposite market order of its size on its own exchange, and that filters apply
+func TestClosePositions(t *testing.T) {
+	router := exchangeRouter{"BTC/USD": "binance", "ETH/USD": "kraken", "SOL/USD": "binance"}
+	manager := NewManager(DefaultManagerConfig(), router, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	open := func(symbol string, side OrderSide, quantity, price float64) {
+		fillOrder(t, manager, &OrderRequest{
+			Symbol:   symbol,
+			Side:     side,
+			Type:     OrderTypeLimit,
+			Quantity: decimal.NewFromFloat(quantity),
+			Price:    decimal.NewFromFloat(price),
+		})
+	}
+	open("BTC/USD", OrderSideBuy, 1.5, 50000)
+	open("ETH/USD", OrderSideSell, 4, 3000)
+	open("SOL/USD", OrderSideBuy, 10, 100)
+	open("SOL/USD", OrderSideSell, 10, 110) // Flat, nothing to close
+	require.Eventually(t, func() bool {
+		positions, err := manager.GetPositions(ctx, nil)
+		return err == nil && len(positions) == 3
+	}, time.Second, time.Millisecond)
+
+	// Filtered to one exchange
+	result := manager.ClosePositions(ctx, map[string]interface{}{"exchange": "kraken"})
+	require.Len(t, result.Orders, 1)
+	assert.Empty(t, result.Failed)
+	closing := result.Orders[0]
+	assert.Equal(t, "kraken", closing.Exchange)
+	assert.Equal(t, "ETH/USD", closing.Symbol)
+	assert.Equal(t, OrderSideBuy, closing.Side)
+	assert.Equal(t, OrderTypeMarket, closing.Type)
+	assert.True(t, closing.Quantity.Equal(decimal.NewFromInt(4)))
+	assert.Equal(t, "close", closing.Tags[PositionEffectTag])
+
+	// Everything, routed to where each position is held rather than by the
+	// router; ETH/USD is already covered by the working closing order
+	router["BTC/USD"] = "coinbase"
+	result = manager.ClosePositions(ctx, nil)
+	assert.Empty(t, result.Failed)
+	require.Len(t, result.Orders, 1, "the flat SOL/USD and covered ETH/USD positions must not be closed")
+	assert.Equal(t, "binance", result.Orders[0].Exchange)
+	assert.Equal(t, "BTC/USD", result.Orders[0].Symbol)
+	assert.Equal(t, OrderSideSell, result.Orders[0].Side)
+	assert.True(t, result.Orders[0].Quantity.Equal(decimal.NewFromFloat(1.5)))
+	assert.True(t, result.Orders[0].Price.Equal(decimal.NewFromInt(50000)))
+
+	// Cancelling the working closing order uncovers the position again
+	require.NoError(t, manager.CancelOrder(ctx, closing.ID))
+	require.Eventually(t, func() bool {
+		order, err := manager.GetOrder(ctx, closing.ID)
+		return err == nil && order.Status == OrderStatusCancelled
+	}, time.Second, time.Millisecond)
+	result = manager.ClosePositions(ctx, map[string]interface{}{"exchange": "kraken"})
+	require.Len(t, result.Orders, 1)
+	assert.True(t, result.Orders[0].Quantity.Equal(decimal.NewFromInt(4)))
+}
+
+// TestClosingFillCappedAtPosition tests that a closing execution larger than
+// the position flattens it in netting mode instead of opening the other side
+func TestClosingFillCappedAtPosition(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig(), exchangeRouter{"BTC/USD": "binance"}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	fillOrder(t, manager, &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromInt(2),
+		Price:    decimal.NewFromInt(100),
+	})
+	fillOrder(t, manager, &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideSell,
+		Type:     OrderTypeMarket,
+		Quantity: decimal.NewFromInt(5),
+		Price:    decimal.NewFromInt(110),
+		Tags:     map[string]string{PositionEffectTag: "close"},
+	})
+
+	require.Eventually(t, func() bool {
+		positions, err := manager.GetPositions(ctx, nil)
+		return err == nil && len(positions) == 1 && positions[0].Quantity.IsZero()
+	}, time.Second, time.Millisecond)
+	positions, err := manager.GetPositions(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, OrderSideBuy, positions[0].Side)
+	assert.True(t, positions[0].RealizedPNL.Equal(decimal.NewFromInt(20)))
+
+	// A further closing fill on the flat position is not applied
+	fillOrder(t, manager, &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideSell,
+		Type:     OrderTypeMarket,
+		Quantity: decimal.NewFromInt(1),
+		Price:    decimal.NewFromInt(110),
+		Tags:     map[string]string{PositionEffectTag: "close"},
+	})
+	positions, err = manager.GetPositions(ctx, nil)
+	require.NoError(t, err)
+	assert.True(t, positions[0].Quantity.IsZero())
+}
+
+// TestClosePositionsHedging tests that both sides of a hedged symbol are
+// closed and that the closing fills flatten them
+func TestClosePositionsHedging(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.PositionMode = PositionModeHedging
+	manager := NewManager(config, &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	fillOrder(t, manager, &OrderRequest{Symbol: "BTC/USD", Side: OrderSideBuy, Type: OrderTypeLimit, Quantity: decimal.NewFromInt(2), Price: decimal.NewFromInt(50000)})
+	fillOrder(t, manager, &OrderRequest{Symbol: "BTC/USD", Side: OrderSideSell, Type: OrderTypeLimit, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(51000)})
+	require.Eventually(t, func() bool {
+		positions, err := manager.GetPositions(ctx, nil)
+		return err == nil && len(positions) == 2
+	}, time.Second, time.Millisecond)
+
+	result := manager.ClosePositions(ctx, map[string]interface{}{"symbol": "BTC/USD"})
+	require.Len(t, result.Orders, 2)
+	sides := map[OrderSide]decimal.Decimal{}
+	for _, order := range result.Orders {
+		sides[order.Side] = order.Quantity
+		require.NoError(t, manager.UpdateOrderStatus(ctx, &OrderUpdate{
+			OrderID:     order.ID,
+			Status:      OrderStatusFilled,
+			FilledQty:   order.Quantity,
+			FilledPrice: order.Price,
+			Timestamp:   time.Now(),
+			Exchange:    order.Exchange,
+		}))
+	}
+	assert.True(t, sides[OrderSideSell].Equal(decimal.NewFromInt(2)), "closes the long")
+	assert.True(t, sides[OrderSideBuy].Equal(decimal.NewFromInt(1)), "closes the short")
+
+	require.Eventually(t, func() bool {
+		positions, err := manager.GetPositions(ctx, nil)
+		if err != nil || len(positions) != 2 {
+			return false
+		}
+		for _, position := range positions {
+			if !position.Quantity.IsZero() {
+				return false
+			}
+		}
+		return true
+	}, time.Second, time.Millisecond)
+}
+
+// haltedSymbols halts trading in every symbol it holds, with the mapped reason
+type haltedSymbols map[string]string
+
+func (h haltedSymbols) SymbolHalted(symbol string) (bool, string) {
+	reason, halted := h[symbol]
+	return halted, reason
+}
+
+// TestClosePositionsWhileHalted tests that positions are flattened despite the
+// halts and guards that stop new orders, and that rejected closes are reported
+func TestClosePositionsWhileHalted(t *testing.T) {
+	router := exchangeRouter{"BTC/USD": "binance", "ETH/USD": "kraken"}
+	config := DefaultManagerConfig()
+	config.MaxConcurrentOrders = 2
+	config.DailyOrderLimit = DailyOrderLimitConfig{MaxOrders: 3}
+	manager := NewManager(config, router, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	fillOrder(t, manager, &OrderRequest{Symbol: "BTC/USD", Side: OrderSideBuy, Type: OrderTypeLimit, Quantity: decimal.NewFromInt(2), Price: decimal.NewFromInt(50000)})
+	fillOrder(t, manager, &OrderRequest{Symbol: "ETH/USD", Side: OrderSideBuy, Type: OrderTypeLimit, Quantity: decimal.NewFromInt(5), Price: decimal.NewFromInt(3000)})
+	require.Eventually(t, func() bool {
+		positions, err := manager.GetPositions(ctx, nil)
+		return err == nil && len(positions) == 2
+	}, time.Second, time.Millisecond)
+
+	// A resting order uses up the daily cap and one of the two open order slots
+	_, err := manager.SubmitOrder(ctx, &OrderRequest{Symbol: "ETH/USD", Side: OrderSideBuy, Type: OrderTypeLimit, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(2000)})
+	require.NoError(t, err)
+
+	manager.Halt("maintenance")
+	manager.SetSymbolHalts(haltedSymbols{"BTC/USD": "price moved 20%"})
+	manager.config.MaxOrderValue = decimal.NewFromInt(1)
+
+	_, err = manager.SubmitOrder(ctx, &OrderRequest{Symbol: "BTC/USD", Side: OrderSideSell, Type: OrderTypeMarket, Quantity: decimal.NewFromInt(2), Price: decimal.NewFromInt(50000)})
+	require.ErrorIs(t, err, ErrTradingHalted)
+
+	result := manager.ClosePositions(ctx, nil)
+	require.Len(t, result.Orders, 1)
+	closing := result.Orders[0]
+	assert.Equal(t, "BTC/USD", closing.Symbol)
+	assert.Equal(t, OrderSideSell, closing.Side)
+	assert.True(t, closing.Quantity.Equal(decimal.NewFromInt(2)))
+
+	// The open order limit still applies, and the rejected close is reported
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "ETH/USD", result.Failed[0].Symbol)
+	assert.Contains(t, result.Failed[0].Error, ErrMaxOpenOrders.Error())
+
+	require.NoError(t, manager.UpdateOrderStatus(ctx, &OrderUpdate{
+		OrderID:     closing.ID,
+		Status:      OrderStatusFilled,
+		FilledQty:   closing.Quantity,
+		FilledPrice: closing.Price,
+		Timestamp:   time.Now(),
+		Exchange:    closing.Exchange,
+	}))
+	require.Eventually(t, func() bool {
+		positions, err := manager.GetPositions(ctx, map[string]interface{}{"symbol": "BTC/USD"})
+		return err == nil && len(positions) == 1 && positions[0].Quantity.IsZero()
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,116 @@
+package orders
+
+import (
+	"fmt"
+	"time"
+)
+
+// DailyOrderLimitConfig caps the orders submitted in a trading day, to stop a
+// runaway strategy. Counts reset at the rollover time each day.
+type DailyOrderLimitConfig struct {
+	// MaxOrders caps orders across all strategies; zero disables it
+	MaxOrders int `json:"max_orders" yaml:"maxOrders"`
+	// MaxOrdersPerStrategy caps each strategy's orders; zero disables it
+	MaxOrdersPerStrategy int `json:"max_orders_per_strategy" yaml:"maxOrdersPerStrategy"`
+	// StrategyLimits overrides MaxOrdersPerStrategy for individual strategies
+	StrategyLimits map[string]int `json:"strategy_limits,omitempty" yaml:"strategyLimits"`
+	// Rollover is the "15:04" clock time the trading day starts, default 00:00
+	Rollover string `json:"rollover" yaml:"rollover"`
+	// Timezone is the IANA name the rollover is in, default UTC
+	Timezone string `json:"timezone" yaml:"timezone"`
+}
+
+// Validate checks the limits, rollover time and timezone
+func (c DailyOrderLimitConfig) Validate() error {
+	if c.MaxOrders < 0 || c.MaxOrdersPerStrategy < 0 {
+		return fmt.Errorf("daily order limits cannot be negative")
+	}
+	for strategy, limit := range c.StrategyLimits {
+		if limit < 0 {
+			return fmt.Errorf("daily order limit for strategy %s cannot be negative", strategy)
+		}
+	}
+	if c.Rollover != "" {
+		if _, err := time.Parse("15:04", c.Rollover); err != nil {
+			return fmt.Errorf("invalid daily order rollover %q: want HH:MM", c.Rollover)
+		}
+	}
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		return fmt.Errorf("invalid daily order timezone %q: %w", c.Timezone, err)
+	}
+	return nil
+}
+
+// strategyLimit returns the cap on a strategy's orders, zero if uncapped
+func (c DailyOrderLimitConfig) strategyLimit(strategyID string) int {
+	if limit, ok := c.StrategyLimits[strategyID]; ok {
+		return limit
+	}
+	return c.MaxOrdersPerStrategy
+}
+
+// TradingDay returns the start of the trading day containing t
+func (c DailyOrderLimitConfig) TradingDay(t time.Time) time.Time {
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	rollover, err := time.Parse("15:04", c.Rollover)
+	if err != nil {
+		rollover = time.Time{}
+	}
+
+	t = t.In(location)
+	start := time.Date(t.Year(), t.Month(), t.Day(), rollover.Hour(), rollover.Minute(), 0, 0, location)
+	if t.Before(start) {
+		start = start.AddDate(0, 0, -1)
+	}
+	return start
+}
+
+// dailyOrderCounts counts the orders submitted in the current trading day
+type dailyOrderCounts struct {
+	day        time.Time
+	total      int
+	byStrategy map[string]int
+}
+
+// reserveDailyOrder counts an order against the daily limits, or returns
+// ErrDailyOrderLimit if it would exceed one. The caller holds m.mu.
+func (m *Manager) reserveDailyOrder(strategyID string, now time.Time) error {
+	cfg := m.config.DailyOrderLimit
+	strategyLimit := cfg.strategyLimit(strategyID)
+	if cfg.MaxOrders <= 0 && strategyLimit <= 0 {
+		return nil
+	}
+
+	if day := cfg.TradingDay(now); !day.Equal(m.dailyOrders.day) {
+		m.dailyOrders = dailyOrderCounts{day: day, byStrategy: make(map[string]int)}
+	}
+
+	if cfg.MaxOrders > 0 && m.dailyOrders.total >= cfg.MaxOrders {
+		return fmt.Errorf("%w: %d orders today", ErrDailyOrderLimit, cfg.MaxOrders)
+	}
+	if strategyLimit > 0 && m.dailyOrders.byStrategy[strategyID] >= strategyLimit {
+		return fmt.Errorf("%w: %d orders today for strategy %s", ErrDailyOrderLimit, strategyLimit, strategyID)
+	}
+
+	m.dailyOrders.total++
+	m.dailyOrders.byStrategy[strategyID]++
+	return nil
+}
+
+// releaseDailyOrder gives back a reservation made by reserveDailyOrder for an
+// order that was not stored after all. The caller holds m.mu.
+func (m *Manager) releaseDailyOrder(strategyID string, now time.Time) {
+	cfg := m.config.DailyOrderLimit
+	if cfg.MaxOrders <= 0 && cfg.strategyLimit(strategyID) <= 0 {
+		return
+	}
+	if day := cfg.TradingDay(now); !day.Equal(m.dailyOrders.day) {
+		return
+	}
+
+	m.dailyOrders.total--
+	m.dailyOrders.byStrategy[strategyID]--
+}
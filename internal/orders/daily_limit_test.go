@@ -0,0 +1,120 @@
+package orders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyOrderLimit(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.DailyOrderLimit = DailyOrderLimitConfig{
+		MaxOrders:            5,
+		MaxOrdersPerStrategy: 2,
+		StrategyLimits:       map[string]int{"market-maker": 3},
+	}
+	manager := NewManager(config, &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	submit := func(strategyID string) error {
+		_, err := manager.SubmitOrder(ctx, &OrderRequest{
+			Symbol:     "BTC/USD",
+			Side:       OrderSideBuy,
+			Type:       OrderTypeLimit,
+			Quantity:   decimal.NewFromFloat(1.0),
+			Price:      decimal.NewFromFloat(50000.0),
+			StrategyID: strategyID,
+		})
+		return err
+	}
+
+	// Each strategy is capped at its own limit
+	for i := 0; i < 2; i++ {
+		require.NoError(t, submit("arbitrage"))
+	}
+	assert.ErrorIs(t, submit("arbitrage"), ErrDailyOrderLimit)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, submit("market-maker"))
+	}
+	assert.ErrorIs(t, submit("market-maker"), ErrDailyOrderLimit)
+
+	// Five orders have been accepted, which is the global cap
+	err := submit("momentum")
+	assert.ErrorIs(t, err, ErrDailyOrderLimit)
+	assert.Contains(t, err.Error(), "5 orders today")
+
+	// The counts reset once the trading day rolls over
+	manager.mu.Lock()
+	manager.dailyOrders.day = manager.dailyOrders.day.AddDate(0, 0, -1)
+	manager.mu.Unlock()
+
+	require.NoError(t, submit("momentum"))
+	require.NoError(t, submit("arbitrage"))
+	require.NoError(t, submit("arbitrage"))
+	assert.ErrorIs(t, submit("arbitrage"), ErrDailyOrderLimit)
+}
+
+func TestDailyOrderLimitDisabled(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	for i := 0; i < 10; i++ {
+		_, err := manager.SubmitOrder(ctx, &OrderRequest{
+			Symbol:   "BTC/USD",
+			Side:     OrderSideBuy,
+			Type:     OrderTypeLimit,
+			Quantity: decimal.NewFromFloat(1.0),
+			Price:    decimal.NewFromFloat(50000.0),
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestDailyOrderTradingDay(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		config DailyOrderLimitConfig
+		at     time.Time
+		want   time.Time
+	}{
+		{
+			name: "midnight utc",
+			at:   time.Date(2024, 3, 5, 23, 59, 0, 0, time.UTC),
+			want: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "before rollover belongs to the previous day",
+			config: DailyOrderLimitConfig{Rollover: "17:00", Timezone: "America/New_York"},
+			at:     time.Date(2024, 3, 5, 16, 59, 0, 0, newYork),
+			want:   time.Date(2024, 3, 4, 17, 0, 0, 0, newYork),
+		},
+		{
+			name:   "at rollover starts a new day",
+			config: DailyOrderLimitConfig{Rollover: "17:00", Timezone: "America/New_York"},
+			at:     time.Date(2024, 3, 5, 22, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 3, 5, 17, 0, 0, 0, newYork),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.want.Equal(tt.config.TradingDay(tt.at)), "got %s", tt.config.TradingDay(tt.at))
+		})
+	}
+
+	assert.Error(t, DailyOrderLimitConfig{Rollover: "25:00"}.Validate())
+	assert.Error(t, DailyOrderLimitConfig{Timezone: "Mars/Olympus"}.Validate())
+	assert.Error(t, DailyOrderLimitConfig{MaxOrders: -1}.Validate())
+	assert.NoError(t, DailyOrderLimitConfig{MaxOrders: 10, Rollover: "17:00", Timezone: "America/New_York"}.Validate())
+}
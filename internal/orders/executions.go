@@ -0,0 +1,73 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ExecutionQuery selects a page of executions. Filters take the same keys as
+// GetExecutions. A zero From or To leaves that end of the time window open,
+// and a zero Limit returns every match after Offset.
+type ExecutionQuery struct {
+	Filters map[string]interface{}
+	From    time.Time
+	To      time.Time
+	Limit   int
+	Offset  int
+}
+
+// ExecutionPage is a page of executions, oldest first, and the number of
+// executions matching the query across all pages
+type ExecutionPage struct {
+	Executions []*Execution `json:"executions"`
+	Total      int          `json:"total"`
+}
+
+// QueryExecutions returns the page of executions matching a query
+func (m *Manager) QueryExecutions(ctx context.Context, query ExecutionQuery) (*ExecutionPage, error) {
+	if query.Limit < 0 || query.Offset < 0 {
+		return nil, fmt.Errorf("limit and offset cannot be negative")
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		return nil, fmt.Errorf("to must not be before from")
+	}
+
+	m.mu.RLock()
+	matches := make([]*Execution, 0)
+	for _, execList := range m.executions {
+		for _, execution := range execList {
+			if !query.From.IsZero() && execution.Timestamp.Before(query.From) {
+				continue
+			}
+			if !query.To.IsZero() && execution.Timestamp.After(query.To) {
+				continue
+			}
+			if m.matchesExecutionFilters(execution, query.Filters) {
+				matches = append(matches, execution)
+			}
+		}
+	}
+	m.mu.RUnlock()
+
+	// A stable order keeps pages from overlapping
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].Timestamp.Equal(matches[j].Timestamp) {
+			return matches[i].Timestamp.Before(matches[j].Timestamp)
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	page := &ExecutionPage{Total: len(matches)}
+	if query.Offset >= len(matches) {
+		page.Executions = matches[:0]
+		return page, nil
+	}
+	matches = matches[query.Offset:]
+	if query.Limit > 0 && query.Limit < len(matches) {
+		matches = matches[:query.Limit]
+	}
+	page.Executions = matches
+	return page, nil
+}
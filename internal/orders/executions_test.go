@@ -0,0 +1,91 @@
+package orders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQueryExecutions tests time-window and order filtering of paged executions
+func TestQueryExecutions(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	// Two orders fill in three parts each, a minute apart and interleaved
+	start := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	orderIDs := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		order, err := manager.SubmitOrder(ctx, &OrderRequest{
+			Symbol:   "BTC/USD",
+			Side:     OrderSideBuy,
+			Type:     OrderTypeLimit,
+			Quantity: decimal.NewFromInt(3),
+			Price:    decimal.NewFromInt(50000),
+		})
+		require.NoError(t, err)
+		orderIDs = append(orderIDs, order.ID)
+	}
+	for part := 1; part <= 3; part++ {
+		for i, orderID := range orderIDs {
+			status := OrderStatusPartial
+			if part == 3 {
+				status = OrderStatusFilled
+			}
+			require.NoError(t, manager.UpdateOrderStatus(ctx, &OrderUpdate{
+				OrderID:     orderID,
+				Status:      status,
+				FilledQty:   decimal.NewFromInt(int64(part)),
+				FilledPrice: decimal.NewFromInt(50000),
+				Timestamp:   start.Add(time.Duration(2*(part-1)+i) * time.Minute),
+				Exchange:    "mock_exchange",
+			}))
+		}
+	}
+	require.Eventually(t, func() bool {
+		page, err := manager.QueryExecutions(ctx, ExecutionQuery{})
+		return err == nil && page.Total == 6
+	}, time.Second, 5*time.Millisecond)
+
+	// Pages are oldest first and do not overlap
+	first, err := manager.QueryExecutions(ctx, ExecutionQuery{Limit: 4})
+	require.NoError(t, err)
+	assert.Equal(t, 6, first.Total)
+	require.Len(t, first.Executions, 4)
+	second, err := manager.QueryExecutions(ctx, ExecutionQuery{Limit: 4, Offset: 4})
+	require.NoError(t, err)
+	require.Len(t, second.Executions, 2)
+	all := append(first.Executions, second.Executions...)
+	for i, execution := range all {
+		assert.True(t, execution.Timestamp.Equal(start.Add(time.Duration(i)*time.Minute)), "execution %d at %s", i, execution.Timestamp)
+	}
+
+	// Filtering by order and time window, then paging the matches
+	page, err := manager.QueryExecutions(ctx, ExecutionQuery{
+		Filters: map[string]interface{}{"order_id": orderIDs[0]},
+		From:    start.Add(time.Minute),
+		To:      start.Add(5 * time.Minute),
+		Limit:   1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, page.Total)
+	require.Len(t, page.Executions, 1)
+	assert.Equal(t, orderIDs[0], page.Executions[0].OrderID)
+	assert.True(t, page.Executions[0].Timestamp.Equal(start.Add(2*time.Minute)))
+
+	// An offset past the end is an empty page
+	page, err = manager.QueryExecutions(ctx, ExecutionQuery{Offset: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 6, page.Total)
+	assert.Empty(t, page.Executions)
+
+	_, err = manager.QueryExecutions(ctx, ExecutionQuery{Limit: -1})
+	assert.Error(t, err)
+	_, err = manager.QueryExecutions(ctx, ExecutionQuery{From: start, To: start.Add(-time.Minute)})
+	assert.Error(t, err)
+}
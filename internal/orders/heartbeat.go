@@ -0,0 +1,166 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"velocimex/internal/metrics"
+)
+
+// HeartbeatConfig configures the operator heartbeat dead-man's switch
+type HeartbeatConfig struct {
+	Enabled       bool          `json:"enabled" yaml:"enabled"`
+	Timeout       time.Duration `json:"timeout" yaml:"timeout"`              // Halt after this long without a heartbeat
+	CheckInterval time.Duration `json:"check_interval" yaml:"checkInterval"` // How often the watchdog checks
+}
+
+// DefaultHeartbeatConfig returns default heartbeat configuration
+func DefaultHeartbeatConfig() HeartbeatConfig {
+	return HeartbeatConfig{
+		Enabled:       false,
+		Timeout:       30 * time.Second,
+		CheckInterval: time.Second,
+	}
+}
+
+// Halter can halt order submission
+type Halter interface {
+	Halt(reason string)
+}
+
+// HeartbeatWatchdog halts trading when the operator stops sending heartbeats.
+// A heartbeat after a trip re-arms the watchdog but does not resume trading;
+// that requires an explicit Resume on the order manager.
+type HeartbeatWatchdog struct {
+	config    HeartbeatConfig
+	halter    Halter
+	metrics   *metrics.Wrapper
+	onTimeout func(lastHeartbeat time.Time)
+
+	mu            sync.RWMutex
+	lastHeartbeat time.Time
+	tripped       bool
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+// NewHeartbeatWatchdog creates a watchdog that halts the given halter on timeout
+func NewHeartbeatWatchdog(config HeartbeatConfig, halter Halter, metrics *metrics.Wrapper) *HeartbeatWatchdog {
+	return &HeartbeatWatchdog{
+		config:  config,
+		halter:  halter,
+		metrics: metrics,
+	}
+}
+
+// SetTimeoutHandler sets a callback invoked when the watchdog trips, e.g. to raise an alert
+func (w *HeartbeatWatchdog) SetTimeoutHandler(handler func(lastHeartbeat time.Time)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onTimeout = handler
+}
+
+// Start begins watching for heartbeats. The timeout window starts now.
+func (w *HeartbeatWatchdog) Start(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cancel != nil {
+		return fmt.Errorf("heartbeat watchdog already running")
+	}
+	if w.config.Timeout <= 0 {
+		return fmt.Errorf("heartbeat timeout must be positive")
+	}
+
+	interval := w.config.CheckInterval
+	if interval <= 0 || interval > w.config.Timeout {
+		interval = w.config.Timeout / 2
+	}
+
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.lastHeartbeat = time.Now()
+	w.tripped = false
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				w.check(now)
+			}
+		}
+	}()
+
+	log.Printf("Heartbeat watchdog started with %s timeout", w.config.Timeout)
+	return nil
+}
+
+// Stop stops the watchdog
+func (w *HeartbeatWatchdog) Stop() {
+	w.mu.Lock()
+	cancel := w.cancel
+	w.cancel = nil
+	w.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		w.wg.Wait()
+	}
+}
+
+// Heartbeat records an operator heartbeat
+func (w *HeartbeatWatchdog) Heartbeat() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lastHeartbeat = time.Now()
+	w.tripped = false
+}
+
+// LastHeartbeat returns the time of the most recent heartbeat
+func (w *HeartbeatWatchdog) LastHeartbeat() time.Time {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.lastHeartbeat
+}
+
+// Tripped reports whether the watchdog has halted trading since the last heartbeat
+func (w *HeartbeatWatchdog) Tripped() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.tripped
+}
+
+// check halts trading if the heartbeat window has elapsed
+func (w *HeartbeatWatchdog) check(now time.Time) {
+	w.mu.Lock()
+	if w.tripped || now.Sub(w.lastHeartbeat) <= w.config.Timeout {
+		w.mu.Unlock()
+		return
+	}
+	w.tripped = true
+	lastHeartbeat := w.lastHeartbeat
+	onTimeout := w.onTimeout
+	w.mu.Unlock()
+
+	reason := fmt.Sprintf("no operator heartbeat since %s", lastHeartbeat.Format(time.RFC3339))
+	log.Printf("Heartbeat watchdog tripped: %s", reason)
+
+	w.halter.Halt(reason)
+	if w.metrics != nil {
+		w.metrics.RecordRiskEvent("heartbeat_timeout", "critical")
+	}
+	if onTimeout != nil {
+		onTimeout(lastHeartbeat)
+	}
+}
@@ -0,0 +1,96 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func heartbeatTestOrder() *OrderRequest {
+	return &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(1.0),
+		Price:    decimal.NewFromFloat(50000.0),
+	}
+}
+
+// TestHeartbeatWatchdogHaltsTrading tests that trading halts once heartbeats stop for the configured window
+func TestHeartbeatWatchdogHaltsTrading(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	var timeouts int32
+	watchdog := NewHeartbeatWatchdog(HeartbeatConfig{
+		Enabled:       true,
+		Timeout:       50 * time.Millisecond,
+		CheckInterval: 5 * time.Millisecond,
+	}, manager, nil)
+	watchdog.SetTimeoutHandler(func(lastHeartbeat time.Time) {
+		atomic.AddInt32(&timeouts, 1)
+	})
+	require.NoError(t, watchdog.Start(ctx))
+	defer watchdog.Stop()
+
+	// Heartbeats inside the window keep trading alive
+	for i := 0; i < 6; i++ {
+		time.Sleep(20 * time.Millisecond)
+		watchdog.Heartbeat()
+	}
+	halted, _ := manager.IsHalted()
+	assert.False(t, halted)
+	assert.False(t, watchdog.Tripped())
+
+	_, err := manager.SubmitOrder(ctx, heartbeatTestOrder())
+	require.NoError(t, err)
+
+	// Stop sending heartbeats and wait for the window to elapse
+	assert.Eventually(t, func() bool {
+		halted, _ := manager.IsHalted()
+		return halted
+	}, time.Second, 5*time.Millisecond)
+	assert.True(t, watchdog.Tripped())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&timeouts))
+
+	_, err = manager.SubmitOrder(ctx, heartbeatTestOrder())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrTradingHalted))
+
+	// The watchdog fires once per trip
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&timeouts))
+
+	// A late heartbeat re-arms the watchdog but trading stays halted until resumed
+	watchdog.Heartbeat()
+	assert.False(t, watchdog.Tripped())
+	halted, _ = manager.IsHalted()
+	assert.True(t, halted)
+
+	manager.Resume()
+	_, err = manager.SubmitOrder(ctx, heartbeatTestOrder())
+	require.NoError(t, err)
+}
+
+// TestHeartbeatWatchdogStart tests start-up validation
+func TestHeartbeatWatchdogStart(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	ctx := context.Background()
+
+	watchdog := NewHeartbeatWatchdog(HeartbeatConfig{Enabled: true}, manager, nil)
+	assert.Error(t, watchdog.Start(ctx))
+
+	watchdog = NewHeartbeatWatchdog(DefaultHeartbeatConfig(), manager, nil)
+	require.NoError(t, watchdog.Start(ctx))
+	assert.Error(t, watchdog.Start(ctx))
+	watchdog.Stop()
+	watchdog.Stop()
+}
@@ -0,0 +1,81 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Order history event types
+const (
+	OrderEventCreated            = "created"
+	OrderEventSubmitted          = "submitted"
+	OrderEventUpdated            = "updated" // Status reported by the exchange, with any execution
+	OrderEventCancelled          = "cancelled"
+	OrderEventExpired            = "expired"
+	OrderEventRemainderCancelled = "remainder_cancelled"
+	OrderEventRejected           = "rejected"
+)
+
+// maxOrderHistory bounds the events kept for a single order
+const maxOrderHistory = 1000
+
+// OrderHistoryEvent is one step of an order's lifecycle: a state transition,
+// with the execution that caused it if any
+type OrderHistoryEvent struct {
+	Sequence  int             `json:"sequence"`
+	Type      string          `json:"type"`
+	Status    OrderStatus     `json:"status"`
+	FilledQty decimal.Decimal `json:"filled_qty"`
+	Reason    string          `json:"reason,omitempty"`
+	Execution *Execution      `json:"execution,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// recordHistory appends an event to an order's audit trail, capturing the
+// order's status and filled quantity after the transition. Must be called
+// with m.mu held.
+func (m *Manager) recordHistory(order *Order, eventType, reason string, execution *Execution, at time.Time) {
+	history := m.history[order.ID]
+	sequence := 1
+	if len(history) > 0 {
+		sequence = history[len(history)-1].Sequence + 1
+	}
+
+	var executionCopy *Execution
+	if execution != nil {
+		copied := *execution
+		executionCopy = &copied
+	}
+
+	history = append(history, OrderHistoryEvent{
+		Sequence:  sequence,
+		Type:      eventType,
+		Status:    order.Status,
+		FilledQty: order.FilledQty,
+		Reason:    reason,
+		Execution: executionCopy,
+		Timestamp: at,
+	})
+	if len(history) > maxOrderHistory {
+		history = history[len(history)-maxOrderHistory:]
+	}
+	m.history[order.ID] = history
+}
+
+// GetOrderHistory returns the lifecycle of an order from its audit trail:
+// every state transition and execution, in the order they happened
+func (m *Manager) GetOrderHistory(ctx context.Context, orderID string) ([]OrderHistoryEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, exists := m.orders[orderID]; !exists {
+		return nil, fmt.Errorf("order not found: %s", orderID)
+	}
+
+	history := make([]OrderHistoryEvent, len(m.history[orderID]))
+	copy(history, m.history[orderID])
+	return history, nil
+}
@@ -0,0 +1,117 @@
+package orders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOrderHistory tests that the audit trail reconstructs an order's
+// lifecycle through partial fills and a cancel, and through a full fill
+func TestOrderHistory(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig(), nil, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	status := func(orderID string) OrderStatus {
+		order, err := manager.GetOrder(ctx, orderID)
+		require.NoError(t, err)
+		manager.mu.RLock()
+		defer manager.mu.RUnlock()
+		return order.Status
+	}
+	update := func(order *Order, status OrderStatus, filled float64) {
+		require.NoError(t, manager.UpdateOrderStatus(ctx, &OrderUpdate{
+			OrderID:     order.ID,
+			Status:      status,
+			FilledQty:   decimal.NewFromFloat(filled),
+			FilledPrice: decimal.NewFromInt(50000),
+			Timestamp:   time.Now(),
+			Exchange:    order.Exchange,
+		}))
+	}
+	request := func() *OrderRequest {
+		return &OrderRequest{
+			Exchange: "binance",
+			Symbol:   "BTC/USD",
+			Side:     OrderSideBuy,
+			Type:     OrderTypeLimit,
+			Quantity: decimal.NewFromInt(1),
+			Price:    decimal.NewFromInt(50000),
+		}
+	}
+
+	// Partially filled twice, then cancelled
+	cancelled, err := manager.SubmitOrder(ctx, request())
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return status(cancelled.ID) == OrderStatusSubmitted }, time.Second, time.Millisecond)
+	update(cancelled, OrderStatusPartial, 0.4)
+	require.Eventually(t, func() bool {
+		history, err := manager.GetOrderHistory(ctx, cancelled.ID)
+		return err == nil && len(history) == 3
+	}, time.Second, time.Millisecond)
+	update(cancelled, OrderStatusPartial, 0.7)
+	require.Eventually(t, func() bool {
+		history, err := manager.GetOrderHistory(ctx, cancelled.ID)
+		return err == nil && len(history) == 4
+	}, time.Second, time.Millisecond)
+	require.NoError(t, manager.CancelOrder(ctx, cancelled.ID))
+	require.Eventually(t, func() bool { return status(cancelled.ID) == OrderStatusCancelled }, time.Second, time.Millisecond)
+
+	history, err := manager.GetOrderHistory(ctx, cancelled.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 5)
+
+	types := make([]string, len(history))
+	statuses := make([]OrderStatus, len(history))
+	for i, event := range history {
+		assert.Equal(t, i+1, event.Sequence)
+		if i > 0 {
+			assert.False(t, event.Timestamp.Before(history[i-1].Timestamp), "event %d out of order", i+1)
+		}
+		types[i] = event.Type
+		statuses[i] = event.Status
+	}
+	assert.Equal(t, []string{OrderEventCreated, OrderEventSubmitted, OrderEventUpdated, OrderEventUpdated, OrderEventCancelled}, types)
+	assert.Equal(t, []OrderStatus{OrderStatusPending, OrderStatusSubmitted, OrderStatusPartial, OrderStatusPartial, OrderStatusCancelled}, statuses)
+
+	assert.Nil(t, history[1].Execution)
+	require.NotNil(t, history[2].Execution)
+	require.NotNil(t, history[3].Execution)
+	assert.True(t, history[2].FilledQty.Equal(decimal.NewFromFloat(0.4)))
+	assert.True(t, history[3].FilledQty.Equal(decimal.NewFromFloat(0.7)))
+	assert.True(t, history[4].FilledQty.Equal(decimal.NewFromFloat(0.7)), "cancel keeps the filled quantity")
+
+	// Executions in the history are the ones recorded for the order
+	executions, err := manager.GetExecutions(ctx, map[string]interface{}{"order_id": cancelled.ID})
+	require.NoError(t, err)
+	ids := map[string]bool{}
+	for _, execution := range executions {
+		ids[execution.ID] = true
+	}
+	assert.True(t, ids[history[2].Execution.ID])
+	assert.True(t, ids[history[3].Execution.ID])
+
+	// Filled outright
+	filled, err := manager.SubmitOrder(ctx, request())
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return status(filled.ID) == OrderStatusSubmitted }, time.Second, time.Millisecond)
+	update(filled, OrderStatusFilled, 1)
+	require.Eventually(t, func() bool { return status(filled.ID) == OrderStatusFilled }, time.Second, time.Millisecond)
+
+	history, err = manager.GetOrderHistory(ctx, filled.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, OrderEventUpdated, history[2].Type)
+	assert.Equal(t, OrderStatusFilled, history[2].Status)
+	require.NotNil(t, history[2].Execution)
+	assert.True(t, history[2].Execution.Quantity.Equal(decimal.NewFromInt(1)))
+
+	_, err = manager.GetOrderHistory(ctx, "missing")
+	assert.Error(t, err)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"velocimex/internal/metrics"
+	"velocimex/internal/numeric"
 )
 
 // ManagerConfig holds configuration for the order manager
@@ -21,6 +22,18 @@ type ManagerConfig struct {
 	RetryDelay          time.Duration `json:"retry_delay"`
 	EnablePaperTrading  bool          `json:"enable_paper_trading"`
 	DefaultSlippage     decimal.Decimal `json:"default_slippage"`
+	SymbolLimits        map[string]SymbolLimits `json:"symbol_limits"`
+	PositionMode        PositionMode  `json:"position_mode"`
+	ExpirySweepInterval time.Duration `json:"expiry_sweep_interval"`
+	TCA                 TCAConfig     `json:"tca"`
+	CancelSpreadOnLegFailure bool `json:"cancel_spread_on_leg_failure"` // Cancel remaining spread legs when one cannot fill
+	StaleBook           StaleBookConfig `json:"stale_book"`
+	PaperFill           PaperFillConfig `json:"paper_fill"`
+	CancelRemainderOnTimeout bool `json:"cancel_remainder_on_timeout"` // Partially filled orders that time out keep their fills and cancel the rest
+	AckTimeout          time.Duration `json:"ack_timeout"` // Orders the exchange has not acknowledged this long after submission are cancelled; zero disables
+	DailyOrderLimit     DailyOrderLimitConfig `json:"daily_order_limit"`
+	MaxSlippageBps      float64       `json:"max_slippage_bps"` // Default slippage tolerance of market orders; zero disables
+	MaxOrderValue       decimal.Decimal `json:"max_order_value"` // Notional no single order may exceed, whatever other limits allow; zero disables
 }
 
 // DefaultManagerConfig returns default configuration
@@ -32,6 +45,13 @@ func DefaultManagerConfig() ManagerConfig {
 		RetryDelay:          1 * time.Second,
 		EnablePaperTrading:  false,
 		DefaultSlippage:     decimal.NewFromFloat(0.001),
+		SymbolLimits:        make(map[string]SymbolLimits),
+		PositionMode:        PositionModeNetting,
+		ExpirySweepInterval: 30 * time.Second,
+		TCA:                 DefaultTCAConfig(),
+		CancelSpreadOnLegFailure: true,
+		StaleBook:           DefaultStaleBookConfig(),
+		PaperFill:           DefaultPaperFillConfig(),
 	}
 }
 
@@ -41,17 +61,33 @@ type Manager struct {
 	orders        map[string]*Order
 	positions     map[string]*Position
 	executions    map[string][]*Execution
+	history       map[string][]OrderHistoryEvent // Audit trail of each order's lifecycle
 	smartRouter   SmartRouter
+	symbols       SymbolTranslator
+	instruments   InstrumentProvider
+	books         OrderBookProvider
+	symbolHalts   SymbolHaltProvider
+	marks         MarkPriceProvider
+	latency       LatencySource
+	onRealized    func(RealizedTrade)
 	metrics       *metrics.Wrapper
 	orderChan     chan *OrderRequest
 	updateChan    chan *OrderUpdate
 	cancelChan    chan string
+	expiryTimers  map[string]*time.Timer
+	ackTimers     map[string]*time.Timer
+	spreads       map[string]*spreadState
+	spreadLegs    map[string]string // leg order ID -> spread ID
+	simulated     map[string]bool   // Orders whose paper execution has started
 	mu            sync.RWMutex
 	ctx           context.Context
 	cancel        context.CancelFunc
 	wg            sync.WaitGroup
 	running       bool
+	halted        bool
+	haltReason    string
 	lastOrderID   int64
+	dailyOrders   dailyOrderCounts
 }
 
 // NewManager creates a new order manager instance
@@ -63,16 +99,74 @@ func NewManager(config ManagerConfig, smartRouter SmartRouter, metrics *metrics.
 		orders:      make(map[string]*Order),
 		positions:   make(map[string]*Position),
 		executions:  make(map[string][]*Execution),
+		history:     make(map[string][]OrderHistoryEvent),
 		smartRouter: smartRouter,
 		metrics:     metrics,
 		orderChan:   make(chan *OrderRequest, 1000),
 		updateChan:  make(chan *OrderUpdate, 1000),
 		cancelChan:  make(chan string, 100),
+		expiryTimers: make(map[string]*time.Timer),
+		ackTimers:    make(map[string]*time.Timer),
+		spreads:     make(map[string]*spreadState),
+		spreadLegs:  make(map[string]string),
+		simulated:   make(map[string]bool),
 		ctx:         ctx,
 		cancel:      cancel,
 	}
 }
 
+// SetSymbolMapper sets the translator used to resolve exchange-native symbols
+func (m *Manager) SetSymbolMapper(symbols SymbolTranslator) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.symbols = symbols
+}
+
+// SetInstruments sets the contract specifications used to validate orders
+func (m *Manager) SetInstruments(instruments InstrumentProvider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.instruments = instruments
+}
+
+// SetOrderBooks sets the order books checked by the staleness guard and paper fills
+func (m *Manager) SetOrderBooks(books OrderBookProvider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.books = books
+}
+
+// SetSymbolHalts sets the per-symbol halts, e.g. circuit breakers, checked before orders are accepted
+func (m *Manager) SetSymbolHalts(halts SymbolHaltProvider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.symbolHalts = halts
+}
+
+// SetMarkPrices sets the prices open positions are marked to market at; without
+// them positions keep the price of their last fill
+func (m *Manager) SetMarkPrices(marks MarkPriceProvider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.marks = marks
+}
+
+// SetLatencyInjector sets artificial latency added before each order is
+// submitted, for resilience testing
+func (m *Manager) SetLatencyInjector(latency LatencySource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latency = latency
+}
+
+// SetRealizedTradeHandler sets a callback invoked whenever an execution realizes PnL,
+// attributed to the strategy that placed the order
+func (m *Manager) SetRealizedTradeHandler(handler func(trade RealizedTrade)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onRealized = handler
+}
+
 // Start starts the order manager
 func (m *Manager) Start(ctx context.Context) error {
 	m.mu.Lock()
@@ -86,11 +180,12 @@ func (m *Manager) Start(ctx context.Context) error {
 	m.ctx, m.cancel = context.WithCancel(ctx)
 
 	// Start worker goroutines
-	m.wg.Add(4)
+	m.wg.Add(5)
 	go m.orderProcessor()
 	go m.updateProcessor()
 	go m.positionManager()
 	go m.cleanupWorker()
+	go m.contextWatcher()
 
 	if m.metrics != nil {
 		m.metrics.RecordOrderEvent("manager_start", "info")
@@ -112,8 +207,19 @@ func (m *Manager) Stop(ctx context.Context) error {
 	m.running = false
 	m.cancel()
 
-	// Wait for all goroutines to finish
+	for orderID, timer := range m.expiryTimers {
+		timer.Stop()
+		delete(m.expiryTimers, orderID)
+	}
+	for orderID, timer := range m.ackTimers {
+		timer.Stop()
+		delete(m.ackTimers, orderID)
+	}
+
+	// Release the lock while workers drain, they may need it to finish
+	m.mu.Unlock()
 	m.wg.Wait()
+	m.mu.Lock()
 
 	close(m.orderChan)
 	close(m.updateChan)
@@ -127,14 +233,126 @@ func (m *Manager) Stop(ctx context.Context) error {
 	return nil
 }
 
-// SubmitOrder submits a new order
+// IsRunning reports whether the order manager is running
+func (m *Manager) IsRunning() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.running
+}
+
+// Halt stops new order submission until Resume is called. Working orders are left in place.
+func (m *Manager) Halt(reason string) {
+	m.mu.Lock()
+	alreadyHalted := m.halted
+	m.halted = true
+	m.haltReason = reason
+	m.mu.Unlock()
+
+	if alreadyHalted {
+		return
+	}
+
+	log.Printf("Trading halted: %s", reason)
+	if m.metrics != nil {
+		m.metrics.RecordOrderEvent("trading_halted", "critical")
+	}
+}
+
+// Resume re-enables order submission after a halt
+func (m *Manager) Resume() {
+	m.mu.Lock()
+	wasHalted := m.halted
+	m.halted = false
+	m.haltReason = ""
+	m.mu.Unlock()
+
+	if wasHalted {
+		log.Println("Trading resumed")
+		if m.metrics != nil {
+			m.metrics.RecordOrderEvent("trading_resumed", "info")
+		}
+	}
+}
+
+// IsHalted reports whether order submission is halted and why
+func (m *Manager) IsHalted() (bool, string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.halted, m.haltReason
+}
+
+// SubmitOrder submits a new order and returns a copy of it as stored
 func (m *Manager) SubmitOrder(ctx context.Context, req *OrderRequest) (*Order, error) {
+	return m.submitOrder(ctx, req, false)
+}
+
+// submitOrder validates, routes, stores and queues an order. Reduce-only
+// orders, which flatten existing positions, skip the trading halts, the daily
+// order cap, the fat-finger guard and the staleness guard, so that positions
+// can still be closed when those have stopped new risk from being taken.
+func (m *Manager) submitOrder(ctx context.Context, req *OrderRequest, reduceOnly bool) (*Order, error) {
+	order, err := m.prepareOrder(ctx, req, reduceOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := m.storeOrders([]*Order{order}, reduceOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.queueOrder(ctx, req, stored[0]); err != nil {
+		return nil, err
+	}
+	return stored[0], nil
+}
+
+// prepareOrder runs the pre-trade checks on a request and routes it, returning
+// the order to store. Nothing is reserved or recorded until it is stored.
+func (m *Manager) prepareOrder(ctx context.Context, req *OrderRequest, reduceOnly bool) (*Order, error) {
 	if req == nil {
-		return nil, fmt.Errorf("order request cannot be nil")
+		return nil, fmt.Errorf("%w: order request cannot be nil", ErrInvalidOrder)
+	}
+
+	// Orders sized in quote currency get their base quantity from the book
+	quotedExchange, err := m.resolveQuoteQuantity(ctx, req)
+	if err != nil {
+		if m.metrics != nil {
+			m.metrics.RecordOrderEvent("order_rejected", "quote_quantity")
+		}
+		return nil, err
 	}
 
 	if req.Quantity.IsZero() || req.Quantity.IsNegative() {
-		return nil, fmt.Errorf("invalid quantity")
+		return nil, fmt.Errorf("%w: invalid quantity", ErrInvalidOrder)
+	}
+
+	if req.MaxLatency < 0 {
+		return nil, fmt.Errorf("%w: invalid latency budget", ErrInvalidOrder)
+	}
+
+	if req.MaxSlippageBps < 0 {
+		return nil, fmt.Errorf("%w: invalid slippage tolerance", ErrInvalidOrder)
+	}
+
+	m.mu.RLock()
+	halted, haltReason := m.halted, m.haltReason
+	instrumentSpecs := m.instruments
+	symbolHalts := m.symbolHalts
+	m.mu.RUnlock()
+	if halted && !reduceOnly {
+		if m.metrics != nil {
+			m.metrics.RecordOrderEvent("order_rejected", "halted")
+		}
+		return nil, fmt.Errorf("%w: %s", ErrTradingHalted, haltReason)
+	}
+	if symbolHalts != nil && !reduceOnly {
+		if halted, reason := symbolHalts.SymbolHalted(req.Symbol); halted {
+			if m.metrics != nil {
+				m.metrics.RecordOrderEvent("order_rejected", "symbol_halted")
+			}
+			return nil, fmt.Errorf("%w: %s", ErrSymbolHalted, reason)
+		}
 	}
 
 	// Generate order ID
@@ -143,18 +361,77 @@ func (m *Manager) SubmitOrder(ctx context.Context, req *OrderRequest) (*Order, e
 		req.ClientID = orderID
 	}
 
-	// Route the order using smart router
-	routingDecision, err := m.smartRouter.RouteOrder(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to route order: %w", err)
+	// Route the order using smart router, unless it names its exchange, e.g.
+	// to close a position held there
+	exchange := req.Exchange
+	if exchange == "" {
+		exchange = quotedExchange
+	}
+	if exchange == "" {
+		routingDecision, err := m.smartRouter.RouteOrder(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to route order: %w", err)
+		}
+		exchange = routingDecision.Exchange
+	}
+
+	// Notional limits value orders without a price at the quote they would
+	// take on the routed exchange
+	fillPrice := m.expectedFillPrice(req, exchange)
+	if limits, ok := m.config.SymbolLimits[req.Symbol]; ok {
+		if err := limits.Validate(req, fillPrice); err != nil {
+			if m.metrics != nil {
+				m.metrics.RecordOrderEvent("order_rejected", "symbol_limits")
+			}
+			return nil, err
+		}
+	}
+
+	// Symbols without metadata are not checked
+	if instrumentSpecs != nil {
+		if instrument, err := instrumentSpecs.Get(req.Symbol); err == nil {
+			if err := validateInstrument(instrument, req, fillPrice); err != nil {
+				if m.metrics != nil {
+					m.metrics.RecordOrderEvent("order_rejected", "instrument")
+				}
+				return nil, err
+			}
+		}
+	}
+
+	if !reduceOnly {
+		// Fat-finger guard: the last check before an order is accepted
+		if err := m.checkOrderValue(req, exchange); err != nil {
+			if m.metrics != nil {
+				m.metrics.RecordOrderEvent("order_rejected", "max_order_value")
+			}
+			return nil, err
+		}
+
+		// Don't trade on prices from a book that has stopped updating
+		if err := m.checkBookFreshness(req, exchange); err != nil {
+			return nil, err
+		}
+	}
+
+	// Orders use canonical symbols internally; resolve the routed exchange's native symbol
+	nativeSymbol := req.Symbol
+	m.mu.RLock()
+	symbols := m.symbols
+	m.mu.RUnlock()
+	if symbols != nil {
+		if native, err := symbols.ToNative(exchange, req.Symbol); err == nil {
+			nativeSymbol = native
+		}
 	}
 
 	// Create order
 	order := &Order{
 		ID:           orderID,
 		ClientID:     req.ClientID,
-		Exchange:     routingDecision.Exchange,
+		Exchange:     exchange,
 		Symbol:       req.Symbol,
+		NativeSymbol: nativeSymbol,
 		Side:         req.Side,
 		Type:         req.Type,
 		Quantity:     req.Quantity,
@@ -173,17 +450,65 @@ func (m *Manager) SubmitOrder(ctx context.Context, req *OrderRequest) (*Order, e
 		Tags:         req.Tags,
 		Metadata:     req.Metadata,
 	}
+	order.ArrivalPrice = m.midPrice(exchange, req.Symbol)
+	if tolerance := m.slippageTolerance(req); tolerance > 0 {
+		order.MaxSlippageBps = tolerance
+		order.ExpectedPrice = fillPrice
+	}
+
+	return order, nil
+}
 
-	// Store order
+// storeOrders records prepared orders, enforcing the open order limit and
+// reserving them against the daily order cap atomically with the insert: either
+// every order is stored or, if one would breach a limit, none is. It returns
+// copies of the stored orders, since the processor updates the originals.
+func (m *Manager) storeOrders(orders []*Order, reduceOnly bool) ([]*Order, error) {
 	m.mu.Lock()
-	m.orders[orderID] = order
-	m.mu.Unlock()
+	defer m.mu.Unlock()
+
+	if m.config.MaxConcurrentOrders > 0 && m.activeOrderCount()+len(orders) > m.config.MaxConcurrentOrders {
+		if m.metrics != nil {
+			m.metrics.RecordOrderEvent("order_rejected", "max_open_orders")
+		}
+		return nil, fmt.Errorf("%w: limit %d", ErrMaxOpenOrders, m.config.MaxConcurrentOrders)
+	}
+	if !reduceOnly {
+		for i, order := range orders {
+			if err := m.reserveDailyOrder(order.StrategyID, order.CreatedAt); err != nil {
+				for _, reserved := range orders[:i] {
+					m.releaseDailyOrder(reserved.StrategyID, reserved.CreatedAt)
+				}
+				if m.metrics != nil {
+					m.metrics.RecordOrderEvent("order_rejected", "daily_order_limit")
+				}
+				return nil, err
+			}
+		}
+	}
 
-	// Send to order processor
+	stored := make([]*Order, len(orders))
+	for i, order := range orders {
+		m.orders[order.ID] = order
+		m.recordHistory(order, OrderEventCreated, "", nil, order.CreatedAt)
+		if order.ExpiresAt != nil {
+			m.scheduleExpiry(order.ID, *order.ExpiresAt)
+		}
+		if m.config.AckTimeout > 0 {
+			m.scheduleAckTimeout(order.ID, m.config.AckTimeout)
+		}
+		copied := *order
+		stored[i] = &copied
+	}
+	return stored, nil
+}
+
+// queueOrder hands a stored order's request to the order processor
+func (m *Manager) queueOrder(ctx context.Context, req *OrderRequest, order *Order) error {
 	select {
 	case m.orderChan <- req:
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return ctx.Err()
 	}
 
 	// Record metrics
@@ -192,22 +517,25 @@ func (m *Manager) SubmitOrder(ctx context.Context, req *OrderRequest) (*Order, e
 		orderValue, _ := order.Quantity.Mul(order.Price).Float64()
 		m.metrics.RecordOrderValue(orderValue)
 	}
-
-	return order, nil
+	return nil
 }
 
 // CancelOrder cancels an existing order
 func (m *Manager) CancelOrder(ctx context.Context, orderID string) error {
 	m.mu.RLock()
 	order, exists := m.orders[orderID]
+	var status OrderStatus
+	if exists {
+		status = order.Status
+	}
 	m.mu.RUnlock()
 
 	if !exists {
-		return fmt.Errorf("order not found: %s", orderID)
+		return fmt.Errorf("%w: %s", ErrOrderNotFound, orderID)
 	}
 
-	if order.Status == OrderStatusFilled || order.Status == OrderStatusCancelled {
-		return fmt.Errorf("cannot cancel order with status: %s", order.Status)
+	if status == OrderStatusFilled || status == OrderStatusCancelled || status == OrderStatusPartialCancelled {
+		return fmt.Errorf("%w: status %s", ErrNotCancellable, status)
 	}
 
 	// Send to cancel channel
@@ -224,7 +552,7 @@ func (m *Manager) CancelOrder(ctx context.Context, orderID string) error {
 	return nil
 }
 
-// GetOrder retrieves an order by ID
+// GetOrder retrieves a copy of an order by ID
 func (m *Manager) GetOrder(ctx context.Context, orderID string) (*Order, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -234,10 +562,11 @@ func (m *Manager) GetOrder(ctx context.Context, orderID string) (*Order, error)
 		return nil, fmt.Errorf("order not found: %s", orderID)
 	}
 
-	return order, nil
+	copied := *order
+	return &copied, nil
 }
 
-// GetOrders retrieves orders with optional filters
+// GetOrders retrieves copies of orders with optional filters
 func (m *Manager) GetOrders(ctx context.Context, filters map[string]interface{}) ([]*Order, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -245,14 +574,15 @@ func (m *Manager) GetOrders(ctx context.Context, filters map[string]interface{})
 	orders := make([]*Order, 0, len(m.orders))
 	for _, order := range m.orders {
 		if m.matchesFilters(order, filters) {
-			orders = append(orders, order)
+			copied := *order
+			orders = append(orders, &copied)
 		}
 	}
 
 	return orders, nil
 }
 
-// GetPositions retrieves positions with optional filters
+// GetPositions retrieves copies of positions with optional filters
 func (m *Manager) GetPositions(ctx context.Context, filters map[string]interface{}) ([]*Position, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -260,7 +590,8 @@ func (m *Manager) GetPositions(ctx context.Context, filters map[string]interface
 	positions := make([]*Position, 0, len(m.positions))
 	for _, position := range m.positions {
 		if m.matchesPositionFilters(position, filters) {
-			positions = append(positions, position)
+			copied := *position
+			positions = append(positions, &copied)
 		}
 	}
 
@@ -299,6 +630,26 @@ func (m *Manager) UpdateOrderStatus(ctx context.Context, update *OrderUpdate) er
 	return nil
 }
 
+// QueueDepths reports how many requests, updates and cancels are waiting to be processed
+func (m *Manager) QueueDepths() []metrics.QueueDepth {
+	return []metrics.QueueDepth{
+		{Queue: "order_requests", Depth: len(m.orderChan), Capacity: cap(m.orderChan)},
+		{Queue: "order_updates", Depth: len(m.updateChan), Capacity: cap(m.updateChan)},
+		{Queue: "order_cancels", Depth: len(m.cancelChan), Capacity: cap(m.cancelChan)},
+	}
+}
+
+// contextWatcher marks the manager stopped once its context is cancelled,
+// whether by Stop or by the parent context
+func (m *Manager) contextWatcher() {
+	defer m.wg.Done()
+
+	<-m.ctx.Done()
+	m.mu.Lock()
+	m.running = false
+	m.mu.Unlock()
+}
+
 // orderProcessor processes incoming orders
 func (m *Manager) orderProcessor() {
 	defer m.wg.Done()
@@ -309,6 +660,9 @@ func (m *Manager) orderProcessor() {
 			if req == nil {
 				return
 			}
+			if !m.injectLatency() {
+				return
+			}
 			m.processOrder(req)
 		case <-m.ctx.Done():
 			return
@@ -316,6 +670,31 @@ func (m *Manager) orderProcessor() {
 	}
 }
 
+// injectLatency waits for any injected order latency, returning false if the
+// manager stops first
+func (m *Manager) injectLatency() bool {
+	m.mu.RLock()
+	latency := m.latency
+	m.mu.RUnlock()
+
+	if latency == nil {
+		return true
+	}
+	delay := latency.OrderLatency()
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-m.ctx.Done():
+		return false
+	}
+}
+
 // updateProcessor processes order updates
 func (m *Manager) updateProcessor() {
 	defer m.wg.Done()
@@ -354,7 +733,11 @@ func (m *Manager) positionManager() {
 func (m *Manager) cleanupWorker() {
 	defer m.wg.Done()
 
-	ticker := time.NewTicker(30 * time.Second)
+	interval := m.config.ExpirySweepInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
@@ -388,10 +771,15 @@ func (m *Manager) processOrder(req *OrderRequest) {
 		return
 	}
 
-	// Simulate order submission
+	// Simulate order submission, unless the order was cancelled in the meantime
 	m.mu.Lock()
+	if order.Status != OrderStatusPending {
+		m.mu.Unlock()
+		return
+	}
 	order.Status = OrderStatusSubmitted
 	order.UpdatedAt = time.Now()
+	m.recordHistory(order, OrderEventSubmitted, "", nil, order.UpdatedAt)
 	m.mu.Unlock()
 
 	// Simulate execution for paper trading
@@ -406,6 +794,15 @@ func (m *Manager) processOrder(req *OrderRequest) {
 
 // processUpdate processes an order update
 func (m *Manager) processUpdate(update *OrderUpdate) {
+	// Realized trades are reported after the lock is released
+	var realizedTrade *RealizedTrade
+	var onRealized func(RealizedTrade)
+	defer func() {
+		if realizedTrade != nil && onRealized != nil {
+			onRealized(*realizedTrade)
+		}
+	}()
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -414,16 +811,28 @@ func (m *Manager) processUpdate(update *OrderUpdate) {
 		return
 	}
 
+	// Any update from the exchange acknowledges the order
+	m.acknowledge(order, update.Timestamp)
+
+	// Market orders do not fill at prices past their slippage tolerance
+	if m.rejectSlippedFill(order, update) {
+		return
+	}
+
 	// Update order status
 	order.Status = update.Status
 	order.FilledQty = update.FilledQty
 	order.FilledPrice = update.FilledPrice
 	order.Commission = update.Commission
 	order.UpdatedAt = update.Timestamp
+	if order.Status.terminal() {
+		m.finishOrder(order)
+	}
 
 	// Create execution record
+	var execution *Execution
 	if update.FilledQty.GreaterThan(decimal.Zero) {
-		execution := &Execution{
+		execution = &Execution{
 			ID:        uuid.New().String(),
 			OrderID:   update.OrderID,
 			ClientID:  update.ClientID,
@@ -435,13 +844,28 @@ func (m *Manager) processUpdate(update *OrderUpdate) {
 			Commission: update.Commission,
 			Timestamp: update.Timestamp,
 			TradeID:   update.Exchange + "_" + uuid.New().String(),
+			MidPrice:  bookMid(m.books, order.Exchange, order.Symbol),
 		}
 
 		m.executions[update.OrderID] = append(m.executions[update.OrderID], execution)
 
 		// Update position
-		m.updatePositionFromExecution(execution)
+		closing := order.Tags[PositionEffectTag] == "close"
+		if realized, reduced := m.updatePositionFromExecution(execution, closing); reduced {
+			realizedTrade = &RealizedTrade{
+				OrderID:      order.ID,
+				StrategyID:   order.StrategyID,
+				StrategyName: order.StrategyName,
+				Symbol:       order.Symbol,
+				Exchange:     execution.Exchange,
+				PnL:          realized,
+				Timestamp:    execution.Timestamp,
+			}
+			onRealized = m.onRealized
+		}
 	}
+	m.recordHistory(order, OrderEventUpdated, update.Reason, execution, update.Timestamp)
+	m.refreshSpreadForOrder(order.ID)
 
 	if m.metrics != nil {
 		m.metrics.RecordOrderEvent("order_updated", string(update.Status))
@@ -462,18 +886,33 @@ func (m *Manager) processCancel(orderID string) {
 		return
 	}
 
-	if order.Status == OrderStatusFilled || order.Status == OrderStatusCancelled {
+	if order.Status == OrderStatusFilled || order.Status == OrderStatusCancelled || order.Status == OrderStatusPartialCancelled {
 		return
 	}
 
 	order.Status = OrderStatusCancelled
 	order.UpdatedAt = time.Now()
+	m.finishOrder(order)
+	m.recordHistory(order, OrderEventCancelled, order.CancelReason, nil, order.UpdatedAt)
+	m.refreshSpreadForOrder(orderID)
 
 	if m.metrics != nil {
 		m.metrics.RecordOrderEvent("order_cancelled", "info")
 	}
 }
 
+// activeOrderCount returns the number of orders still working. Callers must hold m.mu.
+func (m *Manager) activeOrderCount() int {
+	count := 0
+	for _, order := range m.orders {
+		switch order.Status {
+		case OrderStatusPending, OrderStatusSubmitted, OrderStatusPartial:
+			count++
+		}
+	}
+	return count
+}
+
 // updatePositions updates all positions
 func (m *Manager) updatePositions() {
 	m.mu.Lock()
@@ -481,8 +920,11 @@ func (m *Manager) updatePositions() {
 
 	// Update unrealized P&L for all positions
 	for _, position := range m.positions {
-		// In a real implementation, this would fetch current prices
-		// For now, we'll use the last known price
+		if m.marks != nil && position.Quantity.IsPositive() {
+			if price, ok := m.marks.MarkPrice(position.Symbol); ok {
+				markPosition(position, decimal.NewFromFloat(price))
+			}
+		}
 		position.UpdatedAt = time.Now()
 	}
 
@@ -491,11 +933,53 @@ func (m *Manager) updatePositions() {
 	}
 }
 
-// updatePositionFromExecution updates a position based on an execution
-func (m *Manager) updatePositionFromExecution(execution *Execution) {
-	positionKey := fmt.Sprintf("%s:%s", execution.Exchange, execution.Symbol)
+// markPosition values a position at the given price
+func markPosition(position *Position, price decimal.Decimal) {
+	position.CurrentPrice = price
+	position.UnrealizedPNL = numeric.Round(price.Sub(position.EntryPrice).Mul(position.Quantity))
+	if position.Side == OrderSideSell {
+		position.UnrealizedPNL = position.UnrealizedPNL.Neg()
+	}
+}
+
+// positionKey returns the key of the position an execution applies to.
+// In hedging mode each side has its own position; closing executions
+// apply to the opposite side.
+func (m *Manager) positionKey(execution *Execution, closing bool) string {
+	if m.config.PositionMode != PositionModeHedging {
+		return fmt.Sprintf("%s:%s", execution.Exchange, execution.Symbol)
+	}
+
+	side := execution.Side
+	if closing {
+		side = OrderSideBuy
+		if execution.Side == OrderSideBuy {
+			side = OrderSideSell
+		}
+	}
+	return fmt.Sprintf("%s:%s:%s", execution.Exchange, execution.Symbol, side)
+}
+
+// updatePositionFromExecution updates a position based on an execution and
+// returns the PnL realized by it, if the execution reduced a position
+func (m *Manager) updatePositionFromExecution(execution *Execution, closing bool) (decimal.Decimal, bool) {
+	positionKey := m.positionKey(execution, closing)
+	realized := decimal.Zero
+	reduced := false
+	
+	// Nothing to apply, and a zero quantity would leave the average entry price undefined
+	if !execution.Quantity.IsPositive() {
+		log.Printf("Ignoring execution %s with non-positive quantity %s", execution.ID, execution.Quantity.String())
+		return realized, false
+	}
 	
+	// Closing executions only ever reduce a position; with nothing left on
+	// the opposite side they are not applied
 	position, exists := m.positions[positionKey]
+	if closing && (!exists || !position.Quantity.IsPositive() || position.Side == execution.Side) {
+		log.Printf("No %s position to close for execution %s", positionKey, execution.ID)
+		return realized, false
+	}
 	if !exists {
 		// Create new position
 		position = &Position{
@@ -514,7 +998,13 @@ func (m *Manager) updatePositionFromExecution(execution *Execution) {
 		m.positions[positionKey] = position
 	} else {
 		// Update existing position
-		if position.Side == execution.Side {
+		if !position.Quantity.IsPositive() {
+			// A flat position reopens at the execution price on whichever
+			// side the execution is, keeping its realized PnL
+			position.Side = execution.Side
+			position.Quantity = execution.Quantity
+			position.EntryPrice = execution.Price
+		} else if position.Side == execution.Side {
 			// Adding to position
 			newQuantity := position.Quantity.Add(execution.Quantity)
 			newEntryPrice := ((position.Quantity.Mul(position.EntryPrice)).Add(execution.Quantity.Mul(execution.Price))).Div(newQuantity)
@@ -522,25 +1012,24 @@ func (m *Manager) updatePositionFromExecution(execution *Execution) {
 			position.Quantity = newQuantity
 			position.EntryPrice = newEntryPrice
 		} else {
-			// Reducing position (closing)
-			if execution.Quantity.GreaterThanOrEqual(position.Quantity) {
-				// Position fully closed
-				realizedPNL := (execution.Price.Sub(position.EntryPrice)).Mul(position.Quantity)
-				if position.Side == OrderSideSell {
-					realizedPNL = realizedPNL.Neg()
-				}
-				
-				position.RealizedPNL = position.RealizedPNL.Add(realizedPNL)
-				position.Quantity = decimal.Zero
-			} else {
-				// Partial close
-				realizedPNL := (execution.Price.Sub(position.EntryPrice)).Mul(execution.Quantity)
-				if position.Side == OrderSideSell {
-					realizedPNL = realizedPNL.Neg()
-				}
-				
-				position.RealizedPNL = position.RealizedPNL.Add(realizedPNL)
-				position.Quantity = position.Quantity.Sub(execution.Quantity)
+			// Reducing position (closing), up to its full quantity
+			closed := decimal.Min(execution.Quantity, position.Quantity)
+			realizedPNL := numeric.Round(execution.Price.Sub(position.EntryPrice).Mul(closed))
+			if position.Side == OrderSideSell {
+				realizedPNL = realizedPNL.Neg()
+			}
+			
+			position.RealizedPNL = position.RealizedPNL.Add(realizedPNL)
+			position.Quantity = position.Quantity.Sub(closed)
+			realized, reduced = realizedPNL, true
+			
+			// The rest of an execution larger than the position opens the
+			// opposite side, unless the execution is closing, which is
+			// capped at the position
+			if excess := execution.Quantity.Sub(closed); excess.IsPositive() && !closing {
+				position.Side = execution.Side
+				position.Quantity = excess
+				position.EntryPrice = execution.Price
 			}
 		}
 		
@@ -554,12 +1043,32 @@ func (m *Manager) updatePositionFromExecution(execution *Execution) {
 		realizedPNL, _ := position.RealizedPNL.Float64()
 		m.metrics.RecordPositionPNL(realizedPNL)
 	}
+
+	return realized, reduced
 }
 
 // simulateExecution simulates order execution for paper trading
 func (m *Manager) simulateExecution(order *Order) {
+	// An order is simulated once, however often it is retried
+	if !m.claimSimulation(order.ID) {
+		return
+	}
+
 	time.Sleep(100 * time.Millisecond) // Simulate network delay
 
+	// Orders cancelled or expired during the delay do not fill
+	m.mu.RLock()
+	working := order.Status == OrderStatusSubmitted
+	m.mu.RUnlock()
+	if !working {
+		return
+	}
+
+	// Limit orders may rest until the market reaches their price
+	if !m.awaitLimitFill(order) {
+		return
+	}
+
 	// Simulate partial or full fill
 	fillRatio := decimal.NewFromFloat(0.8 + 0.2*rand.Float64()) // 80-100% fill
 	filledQty := order.Quantity.Mul(fillRatio)
@@ -596,18 +1105,84 @@ func (m *Manager) cleanupExpiredOrders() {
 	defer m.mu.Unlock()
 
 	now := time.Now()
-	for orderID, order := range m.orders {
-		if order.ExpiresAt != nil && now.After(*order.ExpiresAt) {
-			if order.Status == OrderStatusPending || order.Status == OrderStatusSubmitted {
-				order.Status = OrderStatusExpired
-				order.UpdatedAt = now
+	for _, order := range m.orders {
+		if order.ExpiresAt != nil && !now.Before(*order.ExpiresAt) {
+			m.expireOrder(order, now)
+		}
+		if m.config.AckTimeout > 0 && order.AcknowledgedAt == nil && now.Sub(order.CreatedAt) >= m.config.AckTimeout {
+			m.cancelUnacknowledged(order, now)
+		}
+	}
+}
 
-				log.Printf("Order %s expired", orderID)
-				if m.metrics != nil {
-					m.metrics.RecordOrderEvent("order_expired", "info")
-				}
-			}
+// scheduleExpiry arms a timer that expires the order at its deadline, so
+// expiry does not wait for the next sweep. Must be called with m.mu held.
+func (m *Manager) scheduleExpiry(orderID string, expiresAt time.Time) {
+	m.expiryTimers[orderID] = time.AfterFunc(time.Until(expiresAt), func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		delete(m.expiryTimers, orderID)
+		if order, exists := m.orders[orderID]; exists {
+			m.expireOrder(order, time.Now())
 		}
+	})
+}
+
+// finishOrder disarms the expiry and ack timers of an order that has reached
+// a terminal state, so they do not outlive it, and records the book's mid as
+// its close benchmark. Must be called with m.mu held.
+func (m *Manager) finishOrder(order *Order) {
+	order.ClosePrice = bookMid(m.books, order.Exchange, order.Symbol)
+	if timer, exists := m.expiryTimers[order.ID]; exists {
+		timer.Stop()
+		delete(m.expiryTimers, order.ID)
+	}
+	if timer, exists := m.ackTimers[order.ID]; exists {
+		timer.Stop()
+		delete(m.ackTimers, order.ID)
+	}
+}
+
+// expireOrder marks a working order as expired. With CancelRemainderOnTimeout
+// set, a partially filled order instead keeps its fills and only the unfilled
+// remainder is cancelled. Must be called with m.mu held.
+func (m *Manager) expireOrder(order *Order, now time.Time) {
+	switch order.Status {
+	case OrderStatusPending, OrderStatusSubmitted:
+	case OrderStatusPartial:
+		if m.config.CancelRemainderOnTimeout {
+			m.cancelRemainder(order, now)
+			return
+		}
+	default:
+		return
+	}
+
+	order.Status = OrderStatusExpired
+	order.UpdatedAt = now
+	m.finishOrder(order)
+	m.recordHistory(order, OrderEventExpired, "", nil, now)
+	m.refreshSpreadForOrder(order.ID)
+
+	log.Printf("Order %s expired", order.ID)
+	if m.metrics != nil {
+		m.metrics.RecordOrderEvent("order_expired", "info")
+	}
+}
+
+// cancelRemainder cancels the unfilled part of a partially filled order. The
+// filled quantity and its executions are kept. Must be called with m.mu held.
+func (m *Manager) cancelRemainder(order *Order, now time.Time) {
+	order.Status = OrderStatusPartialCancelled
+	order.UpdatedAt = now
+	m.finishOrder(order)
+	m.recordHistory(order, OrderEventRemainderCancelled, "timeout", nil, now)
+	m.refreshSpreadForOrder(order.ID)
+
+	log.Printf("Order %s timed out with %s of %s filled, remainder cancelled", order.ID, order.FilledQty.String(), order.Quantity.String())
+	if m.metrics != nil {
+		m.metrics.RecordOrderEvent("order_remainder_cancelled", "info")
 	}
 }
 
@@ -620,15 +1195,16 @@ func (m *Manager) matchesFilters(order *Order, filters map[string]interface{}) b
 				return false
 			}
 		case "status":
-			if order.Status != value.(OrderStatus) {
+			// Accept the typed value or its string, as query parameters arrive
+			if string(order.Status) != fmt.Sprint(value) {
 				return false
 			}
 		case "side":
-			if order.Side != value.(OrderSide) {
+			if string(order.Side) != fmt.Sprint(value) {
 				return false
 			}
 		case "type":
-			if order.Type != value.(OrderType) {
+			if string(order.Type) != fmt.Sprint(value) {
 				return false
 			}
 		case "exchange":
@@ -639,6 +1215,10 @@ func (m *Manager) matchesFilters(order *Order, filters map[string]interface{}) b
 			if order.StrategyID != value.(string) {
 				return false
 			}
+		case "tags":
+			if !matchesTags(order, value.(map[string]string)) {
+				return false
+			}
 		}
 	}
 	return true
@@ -698,6 +1278,7 @@ func (m *Manager) GetStatistics() map[string]interface{} {
 		"cancelled_orders": 0,
 		"total_positions":  len(m.positions),
 		"total_executions": 0,
+		"halted":           m.halted,
 	}
 
 	for _, order := range m.orders {
@@ -706,7 +1287,7 @@ func (m *Manager) GetStatistics() map[string]interface{} {
 			stats["active_orders"] = stats["active_orders"].(int) + 1
 		case OrderStatusFilled:
 			stats["filled_orders"] = stats["filled_orders"].(int) + 1
-		case OrderStatusCancelled:
+		case OrderStatusCancelled, OrderStatusPartialCancelled:
 			stats["cancelled_orders"] = stats["cancelled_orders"].(int) + 1
 		}
 	}
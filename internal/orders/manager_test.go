@@ -10,7 +10,10 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"velocimex/internal/instruments"
 	"velocimex/internal/metrics"
+	"velocimex/internal/normalizer"
+	"velocimex/internal/orderbook"
 )
 
 // MockSmartRouter is a mock implementation of SmartRouter for testing
@@ -425,6 +428,313 @@ func TestOrderTimeout(t *testing.T) {
 	assert.Equal(t, OrderStatusExpired, updatedOrder.Status)
 }
 
+// TestOrderExpiryPrecision tests that orders expire close to their deadline rather than on the next sweep
+func TestOrderExpiryPrecision(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.ExpirySweepInterval = time.Hour
+	mockRouter := &MockSmartRouter{}
+
+	manager := NewManager(config, mockRouter, nil)
+	ctx := context.Background()
+
+	err := manager.Start(ctx)
+	require.NoError(t, err)
+	defer manager.Stop(ctx)
+
+	expiresAt := time.Now().Add(50 * time.Millisecond)
+	order, err := manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:    "BTC/USD",
+		Side:      OrderSideBuy,
+		Type:      OrderTypeLimit,
+		Quantity:  decimal.NewFromFloat(1.0),
+		Price:     decimal.NewFromFloat(50000.0),
+		ExpiresAt: &expiresAt,
+	})
+	require.NoError(t, err)
+
+	// Not expired before the deadline
+	status, _ := orderState(manager, order.ID)
+	assert.NotEqual(t, OrderStatusExpired, status)
+
+	assert.Eventually(t, func() bool {
+		status, _ := orderState(manager, order.ID)
+		return status == OrderStatusExpired
+	}, time.Second, 5*time.Millisecond)
+
+	_, updatedAt := orderState(manager, order.ID)
+	assert.WithinDuration(t, expiresAt, updatedAt, 100*time.Millisecond)
+	assert.False(t, updatedAt.Before(expiresAt))
+}
+
+// TestExpiryTimerDisarmed tests that an order's expiry timer is stopped and
+// dropped once the order fills or is cancelled before its deadline
+func TestExpiryTimerDisarmed(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	request := func() *OrderRequest {
+		expiresAt := time.Now().Add(time.Hour)
+		return &OrderRequest{
+			Symbol:    "BTC/USD",
+			Side:      OrderSideBuy,
+			Type:      OrderTypeLimit,
+			Quantity:  decimal.NewFromFloat(1.0),
+			Price:     decimal.NewFromFloat(50000.0),
+			ExpiresAt: &expiresAt,
+		}
+	}
+	timers := func() int {
+		manager.mu.RLock()
+		defer manager.mu.RUnlock()
+		return len(manager.expiryTimers)
+	}
+
+	fillOrder(t, manager, request())
+	assert.Eventually(t, func() bool { return timers() == 0 }, time.Second, 5*time.Millisecond)
+
+	cancelled, err := manager.SubmitOrder(ctx, request())
+	require.NoError(t, err)
+	assert.Equal(t, 1, timers())
+	require.NoError(t, manager.CancelOrder(ctx, cancelled.ID))
+	assert.Eventually(t, func() bool { return timers() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+// TestExpirySweepInterval tests that the periodic sweep honours the configured interval
+func TestExpirySweepInterval(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.ExpirySweepInterval = 20 * time.Millisecond
+	mockRouter := &MockSmartRouter{}
+
+	manager := NewManager(config, mockRouter, nil)
+	ctx := context.Background()
+
+	err := manager.Start(ctx)
+	require.NoError(t, err)
+	defer manager.Stop(ctx)
+
+	order, err := manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(1.0),
+		Price:    decimal.NewFromFloat(50000.0),
+	})
+	require.NoError(t, err)
+
+	// Set an expiry after submission so only the sweep can catch it
+	manager.mu.Lock()
+	past := time.Now().Add(-time.Second)
+	manager.orders[order.ID].ExpiresAt = &past
+	manager.mu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		status, _ := orderState(manager, order.ID)
+		return status == OrderStatusExpired
+	}, time.Second, 5*time.Millisecond)
+}
+
+// TestPartialCancelOnTimeout tests that a partially filled order timing out
+// keeps its fills and, when configured, cancels only the remainder
+func TestPartialCancelOnTimeout(t *testing.T) {
+	tests := []struct {
+		name            string
+		cancelRemainder bool
+		want            OrderStatus
+	}{
+		{"expired wholesale", false, OrderStatusExpired},
+		{"remainder cancelled", true, OrderStatusPartialCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultManagerConfig()
+			config.ExpirySweepInterval = time.Hour
+			config.CancelRemainderOnTimeout = tt.cancelRemainder
+
+			manager := NewManager(config, &MockSmartRouter{}, nil)
+			ctx := context.Background()
+			require.NoError(t, manager.Start(ctx))
+			defer manager.Stop(ctx)
+
+			expiresAt := time.Now().Add(100 * time.Millisecond)
+			order, err := manager.SubmitOrder(ctx, &OrderRequest{
+				Symbol:    "BTC/USD",
+				Side:      OrderSideBuy,
+				Type:      OrderTypeLimit,
+				Quantity:  decimal.NewFromInt(1),
+				Price:     decimal.NewFromInt(50000),
+				ExpiresAt: &expiresAt,
+			})
+			require.NoError(t, err)
+
+			require.NoError(t, manager.UpdateOrderStatus(ctx, &OrderUpdate{
+				OrderID:     order.ID,
+				Status:      OrderStatusPartial,
+				FilledQty:   decimal.NewFromFloat(0.4),
+				FilledPrice: decimal.NewFromInt(50000),
+				Timestamp:   time.Now(),
+				Exchange:    order.Exchange,
+			}))
+			require.Eventually(t, func() bool {
+				status, _ := orderState(manager, order.ID)
+				return status == OrderStatusPartial
+			}, time.Second, 5*time.Millisecond)
+
+			require.Eventually(t, func() bool {
+				status, _ := orderState(manager, order.ID)
+				return status == tt.want
+			}, time.Second, 5*time.Millisecond)
+
+			// The filled portion is retained either way
+			updated, err := manager.GetOrder(ctx, order.ID)
+			require.NoError(t, err)
+			assert.True(t, updated.FilledQty.Equal(decimal.NewFromFloat(0.4)))
+
+			executions, err := manager.GetExecutions(ctx, map[string]interface{}{"order_id": order.ID})
+			require.NoError(t, err)
+			require.Len(t, executions, 1)
+			assert.True(t, executions[0].Quantity.Equal(decimal.NewFromFloat(0.4)))
+
+			positions, err := manager.GetPositions(ctx, nil)
+			require.NoError(t, err)
+			require.Len(t, positions, 1)
+			assert.True(t, positions[0].Quantity.Equal(decimal.NewFromFloat(0.4)))
+
+			// A timed out order can no longer be cancelled
+			if tt.cancelRemainder {
+				assert.Error(t, manager.CancelOrder(ctx, order.ID))
+			}
+		})
+	}
+}
+
+// TestAckTimeout tests that orders the exchange never acknowledges are
+// cancelled once the ack timeout passes, and acknowledged orders are not
+func TestAckTimeout(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.ExpirySweepInterval = time.Hour
+	config.AckTimeout = 100 * time.Millisecond
+
+	manager := NewManager(config, &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	request := func() *OrderRequest {
+		return &OrderRequest{
+			Symbol:   "BTC/USD",
+			Side:     OrderSideBuy,
+			Type:     OrderTypeLimit,
+			Quantity: decimal.NewFromInt(1),
+			Price:    decimal.NewFromInt(50000),
+		}
+	}
+
+	silent, err := manager.SubmitOrder(ctx, request())
+	require.NoError(t, err)
+	acked, err := manager.SubmitOrder(ctx, request())
+	require.NoError(t, err)
+
+	require.NoError(t, manager.UpdateOrderStatus(ctx, &OrderUpdate{
+		OrderID:   acked.ID,
+		Status:    OrderStatusSubmitted,
+		Timestamp: time.Now(),
+		Exchange:  acked.Exchange,
+	}))
+
+	require.Eventually(t, func() bool {
+		status, _ := orderState(manager, silent.ID)
+		return status == OrderStatusCancelled
+	}, time.Second, 5*time.Millisecond)
+
+	manager.mu.RLock()
+	assert.Equal(t, CancelReasonAckTimeout, manager.orders[silent.ID].CancelReason)
+	assert.Nil(t, manager.orders[silent.ID].AcknowledgedAt)
+	assert.NotNil(t, manager.orders[acked.ID].AcknowledgedAt)
+	assert.Empty(t, manager.ackTimers)
+	manager.mu.RUnlock()
+
+	// Well past the timeout the acknowledged order is still working
+	time.Sleep(2 * config.AckTimeout)
+	status, _ := orderState(manager, acked.ID)
+	assert.Equal(t, OrderStatusSubmitted, status)
+}
+
+// TestOrderTags tests filtering and bulk cancelling orders by tag
+func TestOrderTags(t *testing.T) {
+	config := DefaultManagerConfig()
+	manager := NewManager(config, &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	submit := func(tags map[string]string) *Order {
+		order, err := manager.SubmitOrder(ctx, &OrderRequest{
+			Symbol:   "BTC/USD",
+			Side:     OrderSideBuy,
+			Type:     OrderTypeLimit,
+			Quantity: decimal.NewFromInt(1),
+			Price:    decimal.NewFromInt(50000),
+			Tags:     tags,
+		})
+		require.NoError(t, err)
+		return order
+	}
+	gridA := submit(map[string]string{"strategy": "grid", "leg": "a"})
+	gridB := submit(map[string]string{"strategy": "grid", "leg": "b"})
+	hedge := submit(map[string]string{"strategy": "hedge"})
+	untagged := submit(nil)
+
+	ids := func(orders []*Order) []string {
+		result := make([]string, 0, len(orders))
+		for _, order := range orders {
+			result = append(result, order.ID)
+		}
+		return result
+	}
+
+	tagged, err := manager.GetOrders(ctx, map[string]interface{}{"tags": map[string]string{"strategy": "grid"}})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{gridA.ID, gridB.ID}, ids(tagged))
+
+	// Every tag must match, and a bare key matches any value
+	tagged, err = manager.GetOrders(ctx, map[string]interface{}{"tags": map[string]string{"strategy": "grid", "leg": "b"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{gridB.ID}, ids(tagged))
+	tagged, err = manager.GetOrders(ctx, map[string]interface{}{"tags": map[string]string{"strategy": ""}})
+	require.NoError(t, err)
+	assert.Len(t, tagged, 3)
+
+	result := manager.CancelOrdersByTag(ctx, map[string]string{"strategy": "grid"})
+	assert.ElementsMatch(t, []string{gridA.ID, gridB.ID}, result.Cancelled)
+	assert.Empty(t, result.Failed)
+
+	require.Eventually(t, func() bool {
+		a, _ := orderState(manager, gridA.ID)
+		b, _ := orderState(manager, gridB.ID)
+		return a == OrderStatusCancelled && b == OrderStatusCancelled
+	}, time.Second, 5*time.Millisecond)
+	for _, order := range []*Order{hedge, untagged} {
+		status, _ := orderState(manager, order.ID)
+		assert.NotEqual(t, OrderStatusCancelled, status)
+	}
+
+	// Cancelled orders are not cancelled again, and no tags cancel nothing
+	assert.Empty(t, manager.CancelOrdersByTag(ctx, map[string]string{"strategy": "grid"}).Cancelled)
+	assert.Empty(t, manager.CancelOrdersByTag(ctx, nil).Cancelled)
+}
+
+// orderState reads an order's status and update time under the manager lock
+func orderState(manager *Manager, orderID string) (OrderStatus, time.Time) {
+	manager.mu.RLock()
+	defer manager.mu.RUnlock()
+
+	order := manager.orders[orderID]
+	return order.Status, order.UpdatedAt
+}
+
 // TestStatistics tests statistics collection
 func TestStatistics(t *testing.T) {
 	config := DefaultManagerConfig()
@@ -525,7 +835,7 @@ func TestContextCancellation(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 
 	// Verify manager is stopped
-	assert.False(t, manager.running)
+	assert.False(t, manager.IsRunning())
 }
 
 // TestErrorHandling tests error handling in various scenarios
@@ -620,4 +930,912 @@ func TestConcurrentAccess(t *testing.T) {
 	}
 
 	wg.Wait()
-}
\ No newline at end of file
+}
+// TestSymbolLimits tests per-symbol quantity and notional validation
+func TestSymbolLimits(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.SymbolLimits["BTC/USD"] = SymbolLimits{
+		MinQuantity: decimal.NewFromFloat(0.001),
+		MaxQuantity: decimal.NewFromFloat(10.0),
+		MinNotional: decimal.NewFromFloat(100.0),
+		MaxNotional: decimal.NewFromFloat(1000000.0),
+	}
+	mockRouter := &MockSmartRouter{}
+	metricsInstance := metrics.New()
+	metricsWrapper := metrics.NewWrapper(metricsInstance, false)
+
+	manager := NewManager(config, mockRouter, metricsWrapper)
+	ctx := context.Background()
+
+	err := manager.Start(ctx)
+	require.NoError(t, err)
+	defer manager.Stop(ctx)
+
+	// Below minimum notional
+	_, err = manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(0.001),
+		Price:    decimal.NewFromFloat(50000.0),
+	})
+	assert.ErrorIs(t, err, ErrBelowMinNotional)
+
+	// Above maximum quantity
+	_, err = manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(11.0),
+		Price:    decimal.NewFromFloat(50000.0),
+	})
+	assert.ErrorIs(t, err, ErrAboveMaxQuantity)
+
+	// Valid order
+	order, err := manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(1.0),
+		Price:    decimal.NewFromFloat(50000.0),
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, order)
+
+	// Market orders are valued at the quote they would take, and rejected
+	// when there is none
+	market := &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeMarket,
+		Quantity: decimal.NewFromFloat(0.001),
+	}
+	_, err = manager.SubmitOrder(ctx, market)
+	assert.ErrorIs(t, err, ErrNoNotionalPrice)
+	books := orderbook.NewManager()
+	books.UpdateOrderBook("mock_exchange", "BTC/USD",
+		[]normalizer.PriceLevel{{Price: 49990, Volume: 10}},
+		[]normalizer.PriceLevel{{Price: 50000, Volume: 10}},
+	)
+	manager.SetOrderBooks(books)
+	_, err = manager.SubmitOrder(ctx, market)
+	assert.ErrorIs(t, err, ErrBelowMinNotional)
+	market.Quantity = decimal.NewFromInt(1)
+	_, err = manager.SubmitOrder(ctx, market)
+	assert.NoError(t, err)
+
+	// Symbols without limits are unaffected
+	_, err = manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "ETH/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(100.0),
+		Price:    decimal.NewFromFloat(1.0),
+	})
+	assert.NoError(t, err)
+}
+
+// TestMaxOrderValue tests that the fat-finger guard rejects any order over the
+// ceiling, even one its symbol limits allow
+func TestMaxOrderValue(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.MaxOrderValue = decimal.NewFromInt(100000)
+	config.SymbolLimits["BTC/USD"] = SymbolLimits{MaxNotional: decimal.NewFromInt(1000000)}
+	manager := NewManager(config, &MockSmartRouter{}, nil)
+	books := orderbook.NewManager()
+	books.UpdateOrderBook("mock_exchange", "BTC/USD",
+		[]normalizer.PriceLevel{{Price: 49990, Volume: 10}},
+		[]normalizer.PriceLevel{{Price: 50000, Volume: 10}},
+	)
+	manager.SetOrderBooks(books)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	submit := func(orderType OrderType, quantity, price float64) (*Order, error) {
+		return manager.SubmitOrder(ctx, &OrderRequest{
+			Symbol:   "BTC/USD",
+			Side:     OrderSideBuy,
+			Type:     orderType,
+			Quantity: decimal.NewFromFloat(quantity),
+			Price:    decimal.NewFromFloat(price),
+		})
+	}
+
+	// 150000 is within the symbol's limits but over the ceiling
+	order, err := submit(OrderTypeLimit, 3, 50000)
+	assert.ErrorIs(t, err, ErrMaxOrderValue)
+	assert.Nil(t, order)
+
+	// 50000 is below it
+	order, err = submit(OrderTypeLimit, 1, 50000)
+	require.NoError(t, err)
+	assert.NotNil(t, order)
+
+	// Market orders are valued at the ask they would take
+	_, err = submit(OrderTypeMarket, 3, 0)
+	assert.ErrorIs(t, err, ErrMaxOrderValue)
+	_, err = submit(OrderTypeMarket, 1, 0)
+	assert.NoError(t, err)
+
+	// An order that cannot be valued is rejected rather than let through
+	_, err = manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "ETH/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeMarket,
+		Quantity: decimal.NewFromInt(1),
+	})
+	assert.ErrorIs(t, err, ErrMaxOrderValue)
+
+	// No rejected order is recorded
+	list, err := manager.GetOrders(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+}
+
+// TestInstrumentValidation tests that orders are checked against instrument contract specs
+func TestInstrumentValidation(t *testing.T) {
+	store, err := instruments.NewStore([]instruments.Instrument{{
+		Symbol:             "BTC/USD",
+		TickSize:           decimal.NewFromFloat(0.5),
+		LotSize:            decimal.NewFromFloat(0.001),
+		MinNotional:        decimal.NewFromFloat(100.0),
+		ContractMultiplier: decimal.NewFromInt(1),
+	}})
+	require.NoError(t, err)
+
+	manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	manager.SetInstruments(store)
+	ctx := context.Background()
+
+	err = manager.Start(ctx)
+	require.NoError(t, err)
+	defer manager.Stop(ctx)
+
+	request := func(quantity, price float64) *OrderRequest {
+		return &OrderRequest{
+			Symbol:   "BTC/USD",
+			Side:     OrderSideBuy,
+			Type:     OrderTypeLimit,
+			Quantity: decimal.NewFromFloat(quantity),
+			Price:    decimal.NewFromFloat(price),
+		}
+	}
+
+	// Off-tick price
+	_, err = manager.SubmitOrder(ctx, request(1.0, 50000.25))
+	assert.ErrorIs(t, err, ErrInvalidTickSize)
+
+	// Off-lot quantity
+	_, err = manager.SubmitOrder(ctx, request(0.0015, 50000.0))
+	assert.ErrorIs(t, err, ErrInvalidLotSize)
+
+	// Below minimum notional
+	_, err = manager.SubmitOrder(ctx, request(0.001, 50000.0))
+	assert.ErrorIs(t, err, ErrBelowMinNotional)
+
+	// Valid order
+	_, err = manager.SubmitOrder(ctx, request(0.01, 50000.5))
+	assert.NoError(t, err)
+
+	// A market order that cannot be priced cannot be checked against the minimum notional
+	market := request(0.001, 0)
+	market.Type = OrderTypeMarket
+	_, err = manager.SubmitOrder(ctx, market)
+	assert.ErrorIs(t, err, ErrNoNotionalPrice)
+
+	// Symbols without metadata are unaffected
+	_, err = manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "ETH/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(0.12345),
+		Price:    decimal.NewFromFloat(3000.123),
+	})
+	assert.NoError(t, err)
+}
+
+// TestMaxOpenOrders tests that submissions are rejected once the open order limit is reached
+func TestMaxOpenOrders(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.MaxConcurrentOrders = 3
+	mockRouter := &MockSmartRouter{}
+	metricsInstance := metrics.New()
+	metricsWrapper := metrics.NewWrapper(metricsInstance, false)
+
+	manager := NewManager(config, mockRouter, metricsWrapper)
+	ctx := context.Background()
+
+	err := manager.Start(ctx)
+	require.NoError(t, err)
+	defer manager.Stop(ctx)
+
+	newRequest := func() *OrderRequest {
+		return &OrderRequest{
+			Symbol:   "BTC/USD",
+			Side:     OrderSideBuy,
+			Type:     OrderTypeLimit,
+			Quantity: decimal.NewFromFloat(1.0),
+			Price:    decimal.NewFromFloat(50000.0),
+		}
+	}
+
+	var first *Order
+	for i := 0; i < config.MaxConcurrentOrders; i++ {
+		order, err := manager.SubmitOrder(ctx, newRequest())
+		require.NoError(t, err)
+		if first == nil {
+			first = order
+		}
+	}
+
+	_, err = manager.SubmitOrder(ctx, newRequest())
+	assert.ErrorIs(t, err, ErrMaxOpenOrders)
+
+	// Cancelling an order frees a slot
+	require.NoError(t, manager.CancelOrder(ctx, first.ID))
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = manager.SubmitOrder(ctx, newRequest())
+	assert.NoError(t, err)
+}
+
+// fillOrder submits an order and reports it fully filled at its price
+func fillOrder(t *testing.T, manager *Manager, req *OrderRequest) *Order {
+	t.Helper()
+	ctx := context.Background()
+
+	order, err := manager.SubmitOrder(ctx, req)
+	require.NoError(t, err)
+
+	err = manager.UpdateOrderStatus(ctx, &OrderUpdate{
+		OrderID:     order.ID,
+		Status:      OrderStatusFilled,
+		FilledQty:   req.Quantity,
+		FilledPrice: req.Price,
+		Timestamp:   time.Now(),
+		Exchange:    order.Exchange,
+	})
+	require.NoError(t, err)
+
+	return order
+}
+
+// TestPositionModes tests netting versus hedging position handling
+func TestPositionModes(t *testing.T) {
+	buy := func() *OrderRequest {
+		return &OrderRequest{
+			Symbol:   "BTC/USD",
+			Side:     OrderSideBuy,
+			Type:     OrderTypeLimit,
+			Quantity: decimal.NewFromFloat(1.0),
+			Price:    decimal.NewFromFloat(50000.0),
+		}
+	}
+	sell := func(tags map[string]string) *OrderRequest {
+		return &OrderRequest{
+			Symbol:   "BTC/USD",
+			Side:     OrderSideSell,
+			Type:     OrderTypeLimit,
+			Quantity: decimal.NewFromFloat(0.4),
+			Price:    decimal.NewFromFloat(51000.0),
+			Tags:     tags,
+		}
+	}
+
+	t.Run("netting", func(t *testing.T) {
+		config := DefaultManagerConfig()
+		manager := NewManager(config, &MockSmartRouter{}, metrics.NewWrapper(metrics.New(), false))
+		ctx := context.Background()
+		require.NoError(t, manager.Start(ctx))
+		defer manager.Stop(ctx)
+
+		fillOrder(t, manager, buy())
+		fillOrder(t, manager, sell(nil))
+		time.Sleep(50 * time.Millisecond)
+
+		positions, err := manager.GetPositions(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, positions, 1)
+		assert.Equal(t, OrderSideBuy, positions[0].Side)
+		assert.True(t, positions[0].Quantity.Equal(decimal.NewFromFloat(0.6)))
+	})
+
+	t.Run("hedging", func(t *testing.T) {
+		config := DefaultManagerConfig()
+		config.PositionMode = PositionModeHedging
+		manager := NewManager(config, &MockSmartRouter{}, metrics.NewWrapper(metrics.New(), false))
+		ctx := context.Background()
+		require.NoError(t, manager.Start(ctx))
+		defer manager.Stop(ctx)
+
+		fillOrder(t, manager, buy())
+		fillOrder(t, manager, sell(nil))
+		time.Sleep(50 * time.Millisecond)
+
+		positions, err := manager.GetPositions(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, positions, 2)
+
+		bySide := make(map[OrderSide]*Position)
+		for _, position := range positions {
+			bySide[position.Side] = position
+		}
+		require.Contains(t, bySide, OrderSideBuy)
+		require.Contains(t, bySide, OrderSideSell)
+		assert.True(t, bySide[OrderSideBuy].Quantity.Equal(decimal.NewFromFloat(1.0)))
+		assert.True(t, bySide[OrderSideSell].Quantity.Equal(decimal.NewFromFloat(0.4)))
+
+		// A closing sell reduces the long instead of adding to the short
+		fillOrder(t, manager, sell(map[string]string{PositionEffectTag: "close"}))
+		time.Sleep(50 * time.Millisecond)
+
+		positions, err = manager.GetPositions(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, positions, 2)
+		for _, position := range positions {
+			bySide[position.Side] = position
+		}
+		assert.True(t, bySide[OrderSideBuy].Quantity.Equal(decimal.NewFromFloat(0.6)))
+		assert.True(t, bySide[OrderSideSell].Quantity.Equal(decimal.NewFromFloat(0.4)))
+		assert.True(t, bySide[OrderSideBuy].RealizedPNL.Equal(decimal.NewFromFloat(400.0)))
+	})
+}
+
+// TestNativeSymbolRouting tests that orders keep the canonical symbol and carry the routed exchange's native symbol
+func TestNativeSymbolRouting(t *testing.T) {
+	mockRouter := &MockSmartRouter{}
+	manager := NewManager(DefaultManagerConfig(), mockRouter, nil)
+	manager.SetSymbolMapper(normalizer.NewSymbolMapper(map[string]map[string]string{
+		"mock_exchange": {"XBTUSD": "BTC/USD"},
+	}))
+	ctx := context.Background()
+
+	err := manager.Start(ctx)
+	require.NoError(t, err)
+	defer manager.Stop(ctx)
+
+	order, err := manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(1.0),
+		Price:    decimal.NewFromFloat(50000.0),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "BTC/USD", order.Symbol)
+	assert.Equal(t, "XBTUSD", order.NativeSymbol)
+
+	// Unmapped symbols are sent as-is
+	order, err = manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "ETH/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(1.0),
+		Price:    decimal.NewFromFloat(3000.0),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ETH/USD", order.NativeSymbol)
+}
+
+// TestRealizedTradeAttribution tests that closing executions report realized PnL for the order's strategy
+func TestRealizedTradeAttribution(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	trades := make(chan RealizedTrade, 4)
+	manager.SetRealizedTradeHandler(func(trade RealizedTrade) { trades <- trade })
+
+	fill := func(side OrderSide, price float64) {
+		order, err := manager.SubmitOrder(ctx, &OrderRequest{
+			Symbol:       "BTC/USD",
+			Side:         side,
+			Type:         OrderTypeLimit,
+			Quantity:     decimal.NewFromInt(1),
+			Price:        decimal.NewFromFloat(price),
+			StrategyName: "pairs",
+		})
+		require.NoError(t, err)
+		require.NoError(t, manager.UpdateOrderStatus(ctx, &OrderUpdate{
+			OrderID:     order.ID,
+			Status:      OrderStatusFilled,
+			FilledQty:   decimal.NewFromInt(1),
+			FilledPrice: decimal.NewFromFloat(price),
+			Timestamp:   time.Now(),
+			Exchange:    "test_exchange",
+		}))
+	}
+
+	// Opening a position realizes nothing
+	fill(OrderSideBuy, 50000)
+	select {
+	case trade := <-trades:
+		t.Fatalf("unexpected realized trade %+v", trade)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	fill(OrderSideSell, 49000)
+	select {
+	case trade := <-trades:
+		assert.Equal(t, "pairs", trade.StrategyName)
+		assert.Equal(t, "BTC/USD", trade.Symbol)
+		assert.True(t, trade.PnL.Equal(decimal.NewFromInt(-1000)), "pnl %s", trade.PnL)
+	case <-time.After(time.Second):
+		t.Fatal("no realized trade reported")
+	}
+}
+
+func TestStaleBookGuard(t *testing.T) {
+	books := orderbook.NewManager()
+	books.UpdateOrderBook("mock_exchange", "BTC/USD",
+		[]normalizer.PriceLevel{{Price: 49990, Volume: 1}},
+		[]normalizer.PriceLevel{{Price: 50010, Volume: 1}},
+	)
+
+	request := func(symbol string) *OrderRequest {
+		return &OrderRequest{
+			Symbol:   symbol,
+			Side:     OrderSideBuy,
+			Type:     OrderTypeLimit,
+			Quantity: decimal.NewFromFloat(0.1),
+			Price:    decimal.NewFromFloat(50000),
+		}
+	}
+
+	newManager := func(t *testing.T, action StaleBookAction) *Manager {
+		config := DefaultManagerConfig()
+		config.StaleBook = StaleBookConfig{MaxAge: time.Second, Action: action}
+		manager := NewManager(config, &MockSmartRouter{}, nil)
+		manager.SetOrderBooks(books)
+		require.NoError(t, manager.Start(context.Background()))
+		t.Cleanup(func() { manager.Stop(context.Background()) })
+		return manager
+	}
+
+	ctx := context.Background()
+
+	t.Run("fresh book allows submission", func(t *testing.T) {
+		manager := newManager(t, StaleBookReject)
+		order, err := manager.SubmitOrder(ctx, request("BTC/USD"))
+		require.NoError(t, err)
+		assert.Equal(t, "mock_exchange", order.Exchange)
+	})
+
+	t.Run("stale book rejects", func(t *testing.T) {
+		manager := newManager(t, StaleBookReject)
+		book := books.GetOrderBook("mock_exchange:BTC/USD")
+		book.Timestamp = time.Now().Add(-time.Minute)
+		defer book.Update(book.Bids, book.Asks)
+
+		_, err := manager.SubmitOrder(ctx, request("BTC/USD"))
+		assert.ErrorIs(t, err, ErrStaleOrderBook)
+		orders, err := manager.GetOrders(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, orders)
+	})
+
+	t.Run("missing book rejects", func(t *testing.T) {
+		manager := newManager(t, StaleBookReject)
+		_, err := manager.SubmitOrder(ctx, request("ETH/USD"))
+		assert.ErrorIs(t, err, ErrStaleOrderBook)
+	})
+
+	t.Run("warn action submits anyway", func(t *testing.T) {
+		manager := newManager(t, StaleBookWarn)
+		_, err := manager.SubmitOrder(ctx, request("ETH/USD"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("disabled guard ignores age", func(t *testing.T) {
+		manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+		manager.SetOrderBooks(books)
+		require.NoError(t, manager.Start(ctx))
+		defer manager.Stop(ctx)
+
+		_, err := manager.SubmitOrder(ctx, request("ETH/USD"))
+		assert.NoError(t, err)
+	})
+}
+
+// TestPaperLimitFillModel tests that paper limit orders rest until the market reaches their price
+func TestPaperLimitFillModel(t *testing.T) {
+	books := orderbook.NewManager()
+	setBook := func(bid, ask float64) {
+		books.UpdateOrderBook("mock_exchange", "BTC/USD",
+			[]normalizer.PriceLevel{{Price: bid, Volume: 1}},
+			[]normalizer.PriceLevel{{Price: ask, Volume: 1}},
+		)
+	}
+	setBook(49990, 50010)
+
+	config := DefaultManagerConfig()
+	config.EnablePaperTrading = true
+	config.PaperFill = PaperFillConfig{Model: PaperFillMarket, TouchProbability: 1, CheckInterval: 10 * time.Millisecond}
+	manager := NewManager(config, &MockSmartRouter{}, nil)
+	manager.SetOrderBooks(books)
+
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	status := func(id string) OrderStatus {
+		order, err := manager.GetOrder(ctx, id)
+		require.NoError(t, err)
+		return order.Status
+	}
+
+	buy, err := manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(0.1),
+		Price:    decimal.NewFromFloat(49900),
+	})
+	require.NoError(t, err)
+	sell, err := manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideSell,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(0.1),
+		Price:    decimal.NewFromFloat(50100),
+	})
+	require.NoError(t, err)
+
+	// Both orders are away from the market and stay open
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, OrderStatusSubmitted, status(buy.ID))
+	assert.Equal(t, OrderStatusSubmitted, status(sell.ID))
+
+	// The ask trading down to the buy price fills the buy at its limit
+	setBook(49800, 49900)
+	require.Eventually(t, func() bool { return status(buy.ID) == OrderStatusFilled }, time.Second, 10*time.Millisecond)
+	order, err := manager.GetOrder(ctx, buy.ID)
+	require.NoError(t, err)
+	assert.True(t, order.FilledPrice.Equal(decimal.NewFromFloat(49900)))
+	assert.Equal(t, OrderStatusSubmitted, status(sell.ID))
+
+	// The bid trading through the sell price fills the sell
+	setBook(50200, 50210)
+	require.Eventually(t, func() bool { return status(sell.ID) == OrderStatusFilled }, time.Second, 10*time.Millisecond)
+
+	// Market orders still fill immediately
+	market, err := manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeMarket,
+		Quantity: decimal.NewFromFloat(0.1),
+		Price:    decimal.NewFromFloat(50210),
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return status(market.ID) == OrderStatusFilled }, time.Second, 10*time.Millisecond)
+}
+
+// TestPaperLimitCancelWhileResting tests that a cancelled resting limit order never fills
+func TestPaperLimitCancelWhileResting(t *testing.T) {
+	books := orderbook.NewManager()
+	books.UpdateOrderBook("mock_exchange", "BTC/USD",
+		[]normalizer.PriceLevel{{Price: 49990, Volume: 1}},
+		[]normalizer.PriceLevel{{Price: 50010, Volume: 1}},
+	)
+
+	config := DefaultManagerConfig()
+	config.EnablePaperTrading = true
+	config.PaperFill = PaperFillConfig{Model: PaperFillMarket, TouchProbability: 1, CheckInterval: 10 * time.Millisecond}
+	manager := NewManager(config, &MockSmartRouter{}, nil)
+	manager.SetOrderBooks(books)
+
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	order, err := manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(0.1),
+		Price:    decimal.NewFromFloat(49900),
+	})
+	require.NoError(t, err)
+	time.Sleep(200 * time.Millisecond)
+	require.NoError(t, manager.CancelOrder(ctx, order.ID))
+
+	books.UpdateOrderBook("mock_exchange", "BTC/USD",
+		[]normalizer.PriceLevel{{Price: 49800, Volume: 1}},
+		[]normalizer.PriceLevel{{Price: 49850, Volume: 1}},
+	)
+	time.Sleep(100 * time.Millisecond)
+
+	updated, err := manager.GetOrder(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, OrderStatusCancelled, updated.Status)
+	assert.True(t, updated.FilledQty.IsZero())
+}
+
+// TestPaperSimulationIdempotent tests that a paper order simulated more than once fills once
+func TestPaperSimulationIdempotent(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.EnablePaperTrading = true
+	manager := NewManager(config, &MockSmartRouter{}, nil)
+	ctx := context.Background()
+
+	// Not started yet, so simulated fills queue up rather than being applied
+	order, err := manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeMarket,
+		Quantity: decimal.NewFromFloat(1.0),
+		Price:    decimal.NewFromFloat(50000.0),
+	})
+	require.NoError(t, err)
+
+	manager.mu.Lock()
+	managed := manager.orders[order.ID]
+	managed.Status = OrderStatusSubmitted
+	manager.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			manager.simulateExecution(managed)
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, manager.updateChan, 1)
+
+	// A later retry is a no-op too
+	manager.simulateExecution(managed)
+	assert.Len(t, manager.updateChan, 1)
+
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+	time.Sleep(100 * time.Millisecond)
+
+	updated, err := manager.GetOrder(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, OrderStatusFilled, updated.Status)
+
+	executions, err := manager.GetExecutions(ctx, map[string]interface{}{"order_id": order.ID})
+	require.NoError(t, err)
+	assert.Len(t, executions, 1)
+}
+
+// TestQueueDepths tests that queue depths count requests not yet picked up by the processors
+func TestQueueDepths(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	ctx := context.Background()
+
+	// Not started, so nothing drains the queues
+	var last *Order
+	for i := 0; i < 3; i++ {
+		order, err := manager.SubmitOrder(ctx, &OrderRequest{
+			Symbol:   "BTC/USD",
+			Side:     OrderSideBuy,
+			Type:     OrderTypeLimit,
+			Quantity: decimal.NewFromFloat(1.0),
+			Price:    decimal.NewFromFloat(50000.0),
+		})
+		require.NoError(t, err)
+		last = order
+	}
+	require.NoError(t, manager.CancelOrder(ctx, last.ID))
+
+	depths := make(map[string]metrics.QueueDepth)
+	for _, depth := range manager.QueueDepths() {
+		depths[depth.Queue] = depth
+	}
+	assert.Equal(t, metrics.QueueDepth{Queue: "order_requests", Depth: 3, Capacity: 1000}, depths["order_requests"])
+	assert.Equal(t, 0, depths["order_updates"].Depth)
+	assert.Equal(t, 1, depths["order_cancels"].Depth)
+
+	// Once started the processors drain the queues
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+	require.Eventually(t, func() bool {
+		for _, depth := range manager.QueueDepths() {
+			if depth.Depth != 0 {
+				return false
+			}
+		}
+		return true
+	}, time.Second, time.Millisecond)
+}
+
+// fixedLatency injects the same latency before every order
+type fixedLatency time.Duration
+
+func (l fixedLatency) OrderLatency() time.Duration {
+	return time.Duration(l)
+}
+
+// TestInjectedOrderLatency tests that injected latency delays order submission
+// and does not hold up stopping the manager
+func TestInjectedOrderLatency(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	manager.SetLatencyInjector(fixedLatency(80 * time.Millisecond))
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+
+	start := time.Now()
+	order, err := manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(1.0),
+		Price:    decimal.NewFromFloat(50000.0),
+	})
+	require.NoError(t, err)
+
+	status := func() OrderStatus {
+		manager.mu.RLock()
+		defer manager.mu.RUnlock()
+		return manager.orders[order.ID].Status
+	}
+	require.Eventually(t, func() bool { return status() == OrderStatusSubmitted }, time.Second, time.Millisecond)
+	assert.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+
+	// An order waiting out its latency is abandoned when the manager stops
+	manager.SetLatencyInjector(fixedLatency(time.Hour))
+	_, err = manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(1.0),
+		Price:    decimal.NewFromFloat(50000.0),
+	})
+	require.NoError(t, err)
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- manager.Stop(ctx) }()
+	select {
+	case err := <-stopped:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Stop waited out the injected latency")
+	}
+}
+
+// TestPositionReopenAndFlip tests that position math survives closing a
+// position fully, reopening it and flipping it through zero
+func TestPositionReopenAndFlip(t *testing.T) {
+	execution := func(side OrderSide, quantity, price float64) *Execution {
+		return &Execution{
+			ID:        "exec",
+			Exchange:  "mock_exchange",
+			Symbol:    "BTC/USD",
+			Side:      side,
+			Quantity:  decimal.NewFromFloat(quantity),
+			Price:     decimal.NewFromFloat(price),
+			Timestamp: time.Now(),
+		}
+	}
+	position := func(manager *Manager) *Position {
+		return manager.positions["mock_exchange:BTC/USD"]
+	}
+
+	t.Run("close and reopen", func(t *testing.T) {
+		manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+
+		manager.updatePositionFromExecution(execution(OrderSideBuy, 1, 50000), false)
+		realized, reduced := manager.updatePositionFromExecution(execution(OrderSideSell, 1, 51000), false)
+		assert.True(t, reduced)
+		assert.True(t, realized.Equal(decimal.NewFromInt(1000)))
+		require.True(t, position(manager).Quantity.IsZero())
+
+		// Reopening on the same side starts from the new price, not the old entry
+		require.NotPanics(t, func() {
+			manager.updatePositionFromExecution(execution(OrderSideBuy, 2, 52000), false)
+		})
+		assert.Equal(t, OrderSideBuy, position(manager).Side)
+		assert.True(t, position(manager).Quantity.Equal(decimal.NewFromInt(2)))
+		assert.True(t, position(manager).EntryPrice.Equal(decimal.NewFromInt(52000)))
+		assert.True(t, position(manager).RealizedPNL.Equal(decimal.NewFromInt(1000)))
+
+		// Adding afterwards averages from the reopened entry
+		manager.updatePositionFromExecution(execution(OrderSideBuy, 2, 54000), false)
+		assert.True(t, position(manager).EntryPrice.Equal(decimal.NewFromInt(53000)))
+	})
+
+	t.Run("reopen on the other side", func(t *testing.T) {
+		manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+
+		manager.updatePositionFromExecution(execution(OrderSideBuy, 1, 50000), false)
+		manager.updatePositionFromExecution(execution(OrderSideSell, 1, 51000), false)
+
+		// A sell against a flat long opens a short rather than closing nothing
+		realized, reduced := manager.updatePositionFromExecution(execution(OrderSideSell, 0.5, 49000), false)
+		assert.False(t, reduced)
+		assert.True(t, realized.IsZero())
+		assert.Equal(t, OrderSideSell, position(manager).Side)
+		assert.True(t, position(manager).Quantity.Equal(decimal.NewFromFloat(0.5)))
+		assert.True(t, position(manager).EntryPrice.Equal(decimal.NewFromInt(49000)))
+	})
+
+	t.Run("flip through zero", func(t *testing.T) {
+		manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+
+		manager.updatePositionFromExecution(execution(OrderSideBuy, 1, 50000), false)
+		realized, reduced := manager.updatePositionFromExecution(execution(OrderSideSell, 3, 50500), false)
+		assert.True(t, reduced)
+		assert.True(t, realized.Equal(decimal.NewFromInt(500)))
+		assert.Equal(t, OrderSideSell, position(manager).Side)
+		assert.True(t, position(manager).Quantity.Equal(decimal.NewFromInt(2)))
+		assert.True(t, position(manager).EntryPrice.Equal(decimal.NewFromInt(50500)))
+
+		// Covering the short realizes against the flipped entry
+		realized, _ = manager.updatePositionFromExecution(execution(OrderSideBuy, 2, 50000), false)
+		assert.True(t, realized.Equal(decimal.NewFromInt(1000)))
+		assert.True(t, position(manager).Quantity.IsZero())
+		assert.True(t, position(manager).RealizedPNL.Equal(decimal.NewFromInt(1500)))
+	})
+
+	t.Run("hedging close does not flip", func(t *testing.T) {
+		config := DefaultManagerConfig()
+		config.PositionMode = PositionModeHedging
+		manager := NewManager(config, &MockSmartRouter{}, nil)
+
+		manager.updatePositionFromExecution(execution(OrderSideBuy, 1, 50000), false)
+		manager.updatePositionFromExecution(execution(OrderSideSell, 3, 51000), true)
+		long := manager.positions["mock_exchange:BTC/USD:BUY"]
+		require.NotNil(t, long)
+		assert.Equal(t, OrderSideBuy, long.Side)
+		assert.True(t, long.Quantity.IsZero())
+		assert.NotContains(t, manager.positions, "mock_exchange:BTC/USD:SELL")
+
+		// A further close against the flat long changes nothing
+		require.NotPanics(t, func() {
+			manager.updatePositionFromExecution(execution(OrderSideSell, 1, 51000), true)
+		})
+		assert.Equal(t, OrderSideBuy, long.Side)
+		assert.True(t, long.Quantity.IsZero())
+	})
+
+	t.Run("zero quantity", func(t *testing.T) {
+		manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+
+		manager.updatePositionFromExecution(execution(OrderSideBuy, 1, 50000), false)
+		manager.updatePositionFromExecution(execution(OrderSideSell, 1, 50000), false)
+		require.NotPanics(t, func() {
+			manager.updatePositionFromExecution(execution(OrderSideBuy, 0, 50000), false)
+		})
+		assert.True(t, position(manager).Quantity.IsZero())
+		assert.True(t, position(manager).EntryPrice.Equal(decimal.NewFromInt(50000)))
+	})
+}
+
+// TestMarkToMarket tests that positions are valued at the mark price when one is set
+func TestMarkToMarket(t *testing.T) {
+	books := orderbook.NewManager()
+	books.UpdateOrderBook("mock_exchange", "BTC/USD", []normalizer.PriceLevel{{Price: 50990, Volume: 1}}, []normalizer.PriceLevel{{Price: 51010, Volume: 1}})
+	books.UpdateOrderBook("other_exchange", "BTC/USD", []normalizer.PriceLevel{{Price: 51990, Volume: 1}}, []normalizer.PriceLevel{{Price: 52010, Volume: 1}})
+
+	manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	for exchange, side := range map[string]OrderSide{"mock_exchange": OrderSideBuy, "other_exchange": OrderSideSell} {
+		manager.updatePositionFromExecution(&Execution{
+			ID:        "exec",
+			Exchange:  exchange,
+			Symbol:    "BTC/USD",
+			Side:      side,
+			Quantity:  decimal.NewFromInt(2),
+			Price:     decimal.NewFromInt(50000),
+			Timestamp: time.Now(),
+		}, false)
+	}
+
+	// Without mark prices positions keep their fill price
+	manager.updatePositions()
+	long := manager.positions["mock_exchange:BTC/USD"]
+	assert.True(t, long.CurrentPrice.Equal(decimal.NewFromInt(50000)))
+	assert.True(t, long.UnrealizedPNL.IsZero())
+
+	// Both venues are marked at the 51500 index, not their own book
+	manager.SetMarkPrices(books)
+	manager.updatePositions()
+	assert.True(t, long.CurrentPrice.Equal(decimal.NewFromInt(51500)), "mark %s", long.CurrentPrice)
+	assert.True(t, long.UnrealizedPNL.Equal(decimal.NewFromInt(3000)), "unrealized %s", long.UnrealizedPNL)
+	short := manager.positions["other_exchange:BTC/USD"]
+	assert.True(t, short.UnrealizedPNL.Equal(decimal.NewFromInt(-3000)), "unrealized %s", short.UnrealizedPNL)
+}
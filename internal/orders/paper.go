@@ -0,0 +1,124 @@
+package orders
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PaperFillModel selects how paper trading decides whether an order fills
+type PaperFillModel string
+
+const (
+	// PaperFillImmediate fills every order after the simulated network delay
+	PaperFillImmediate PaperFillModel = "immediate"
+	// PaperFillMarket rests limit orders until the book trades at or through their price
+	PaperFillMarket PaperFillModel = "market"
+)
+
+// PaperFillConfig configures the paper trading fill model
+type PaperFillConfig struct {
+	Model PaperFillModel `json:"model" yaml:"model"`
+	// TouchProbability is the chance per check that a limit order fills when the
+	// book only touches its price; trading through the price always fills
+	TouchProbability float64       `json:"touch_probability" yaml:"touchProbability"`
+	CheckInterval    time.Duration `json:"check_interval" yaml:"checkInterval"`
+}
+
+// DefaultPaperFillConfig returns default paper fill configuration
+func DefaultPaperFillConfig() PaperFillConfig {
+	return PaperFillConfig{
+		Model:            PaperFillImmediate,
+		TouchProbability: 1,
+		CheckInterval:    100 * time.Millisecond,
+	}
+}
+
+// claimSimulation reserves an order's paper execution. It reports false if
+// the order has already been simulated, so a retried or resubmitted order
+// cannot fill twice.
+func (m *Manager) claimSimulation(orderID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.simulated[orderID] {
+		return false
+	}
+	m.simulated[orderID] = true
+	return true
+}
+
+// awaitLimitFill blocks until the market reaches a resting limit order and
+// reports whether it should fill. It returns false if the order stops working
+// or the manager shuts down first.
+func (m *Manager) awaitLimitFill(order *Order) bool {
+	cfg := m.config.PaperFill
+	if cfg.Model != PaperFillMarket || order.Type != OrderTypeLimit {
+		return true
+	}
+
+	m.mu.RLock()
+	books := m.books
+	m.mu.RUnlock()
+	// Without market data there is nothing to rest against
+	if books == nil {
+		return true
+	}
+
+	interval := cfg.CheckInterval
+	if interval <= 0 {
+		interval = DefaultPaperFillConfig().CheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.mu.RLock()
+		working := order.Status == OrderStatusSubmitted
+		m.mu.RUnlock()
+		if !working {
+			return false
+		}
+
+		if m.limitReached(books, order) {
+			return true
+		}
+
+		select {
+		case <-ticker.C:
+		case <-m.ctx.Done():
+			return false
+		}
+	}
+}
+
+// limitReached reports whether the book trades at or through a limit order's price
+func (m *Manager) limitReached(books QuoteProvider, order *Order) bool {
+	bid, ask, ok := books.BestQuote(order.Exchange, order.Symbol)
+	if !ok {
+		return false
+	}
+
+	// The opposite side of the book is what a resting order trades against
+	market := decimal.NewFromFloat(ask)
+	if order.Side == OrderSideSell {
+		market = decimal.NewFromFloat(bid)
+	}
+	if !market.IsPositive() {
+		return false
+	}
+
+	cmp := market.Cmp(order.Price)
+	if order.Side == OrderSideSell {
+		cmp = -cmp
+	}
+	switch {
+	case cmp < 0:
+		return true
+	case cmp == 0:
+		return rand.Float64() < m.config.PaperFill.TouchProbability
+	default:
+		return false
+	}
+}
@@ -0,0 +1,68 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// resolveQuoteQuantity sizes an order given in quote currency, setting its
+// base Quantity from the best price of the book it would trade against: the
+// ask for buys, the bid for sells. Orders without an exchange are routed to
+// pick that book, and the routed exchange is returned so the order goes where
+// it was priced. Requests with a zero QuoteQuantity are left unchanged.
+func (m *Manager) resolveQuoteQuantity(ctx context.Context, req *OrderRequest) (string, error) {
+	if req.QuoteQuantity.IsZero() {
+		return "", nil
+	}
+	if req.QuoteQuantity.IsNegative() {
+		return "", fmt.Errorf("%w: invalid quote quantity", ErrInvalidOrder)
+	}
+	if !req.Quantity.IsZero() {
+		return "", fmt.Errorf("%w: quantity and quote quantity are mutually exclusive", ErrInvalidOrder)
+	}
+
+	m.mu.RLock()
+	books := m.books
+	instrumentSpecs := m.instruments
+	m.mu.RUnlock()
+	if books == nil {
+		return "", fmt.Errorf("%w: no order books", ErrNoQuotePrice)
+	}
+
+	exchange, routed := req.Exchange, ""
+	if exchange == "" {
+		decision, err := m.smartRouter.RouteOrder(ctx, req)
+		if err != nil {
+			return "", fmt.Errorf("failed to route order: %w", err)
+		}
+		exchange, routed = decision.Exchange, decision.Exchange
+	}
+
+	bid, ask, ok := books.BestQuote(exchange, req.Symbol)
+	price := ask
+	if req.Side == OrderSideSell {
+		price = bid
+	}
+	if !ok || price <= 0 {
+		return "", fmt.Errorf("%w: %s on %s", ErrNoQuotePrice, req.Symbol, exchange)
+	}
+
+	quantity := req.QuoteQuantity.Div(decimal.NewFromFloat(price))
+	// Contracts may carry more than one unit of base, and quantities must be whole lots
+	if instrumentSpecs != nil {
+		if instrument, err := instrumentSpecs.Get(req.Symbol); err == nil {
+			quantity = quantity.Div(instrument.Multiplier())
+			if instrument.LotSize.IsPositive() {
+				quantity = quantity.Div(instrument.LotSize).Floor().Mul(instrument.LotSize)
+			}
+		}
+	}
+	if !quantity.IsPositive() {
+		return "", fmt.Errorf("%w: quote quantity %s buys less than one lot of %s", ErrInvalidOrder, req.QuoteQuantity, req.Symbol)
+	}
+
+	req.Quantity = quantity
+	return routed, nil
+}
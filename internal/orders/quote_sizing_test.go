@@ -0,0 +1,121 @@
+package orders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/instruments"
+	"velocimex/internal/normalizer"
+	"velocimex/internal/orderbook"
+)
+
+func quoteSizingManager(t *testing.T) *Manager {
+	t.Helper()
+
+	books := orderbook.NewManager()
+	books.UpdateOrderBook("mock_exchange", "BTC/USD",
+		[]normalizer.PriceLevel{{Price: 40000, Volume: 1}},
+		[]normalizer.PriceLevel{{Price: 50000, Volume: 1}},
+	)
+	manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	manager.SetOrderBooks(books)
+	return manager
+}
+
+func TestQuoteQuantitySizing(t *testing.T) {
+	manager := quoteSizingManager(t)
+	ctx := context.Background()
+
+	// Buys are sized at the ask, sells at the bid
+	buy, err := manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:        "BTC/USD",
+		Side:          OrderSideBuy,
+		Type:          OrderTypeMarket,
+		QuoteQuantity: decimal.NewFromInt(1000),
+	})
+	require.NoError(t, err)
+	assert.True(t, buy.Quantity.Equal(decimal.RequireFromString("0.02")), "got %s", buy.Quantity)
+	assert.Equal(t, "mock_exchange", buy.Exchange)
+
+	sell, err := manager.SubmitOrder(ctx, &OrderRequest{
+		Exchange:      "mock_exchange",
+		Symbol:        "BTC/USD",
+		Side:          OrderSideSell,
+		Type:          OrderTypeMarket,
+		QuoteQuantity: decimal.NewFromInt(1000),
+	})
+	require.NoError(t, err)
+	assert.True(t, sell.Quantity.Equal(decimal.RequireFromString("0.025")), "got %s", sell.Quantity)
+}
+
+func TestQuoteQuantityLotSize(t *testing.T) {
+	manager := quoteSizingManager(t)
+	store, err := instruments.NewStore([]instruments.Instrument{{
+		Symbol:  "BTC/USD",
+		LotSize: decimal.RequireFromString("0.001"),
+	}})
+	require.NoError(t, err)
+	manager.SetInstruments(store)
+	ctx := context.Background()
+
+	// 1234 / 50000 = 0.02468, rounded down to whole lots
+	order, err := manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:        "BTC/USD",
+		Side:          OrderSideBuy,
+		Type:          OrderTypeMarket,
+		QuoteQuantity: decimal.NewFromInt(1234),
+	})
+	require.NoError(t, err)
+	assert.True(t, order.Quantity.Equal(decimal.RequireFromString("0.024")), "got %s", order.Quantity)
+
+	_, err = manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:        "BTC/USD",
+		Side:          OrderSideBuy,
+		Type:          OrderTypeMarket,
+		QuoteQuantity: decimal.NewFromInt(10),
+	})
+	assert.Error(t, err)
+}
+
+func TestQuoteQuantityRejected(t *testing.T) {
+	manager := quoteSizingManager(t)
+	ctx := context.Background()
+
+	_, err := manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:        "BTC/USD",
+		Side:          OrderSideBuy,
+		Type:          OrderTypeMarket,
+		Quantity:      decimal.NewFromInt(1),
+		QuoteQuantity: decimal.NewFromInt(1000),
+	})
+	assert.Error(t, err, "quantity and quote quantity together")
+
+	_, err = manager.SubmitOrder(ctx, &OrderRequest{
+		Symbol:        "BTC/USD",
+		Side:          OrderSideBuy,
+		Type:          OrderTypeMarket,
+		QuoteQuantity: decimal.NewFromInt(-1000),
+	})
+	assert.Error(t, err, "negative quote quantity")
+
+	_, err = manager.SubmitOrder(ctx, &OrderRequest{
+		Exchange:      "other_exchange",
+		Symbol:        "BTC/USD",
+		Side:          OrderSideBuy,
+		Type:          OrderTypeMarket,
+		QuoteQuantity: decimal.NewFromInt(1000),
+	})
+	assert.ErrorIs(t, err, ErrNoQuotePrice)
+
+	unpriced := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	_, err = unpriced.SubmitOrder(ctx, &OrderRequest{
+		Symbol:        "BTC/USD",
+		Side:          OrderSideBuy,
+		Type:          OrderTypeMarket,
+		QuoteQuantity: decimal.NewFromInt(1000),
+	})
+	assert.ErrorIs(t, err, ErrNoQuotePrice)
+}
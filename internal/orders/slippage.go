@@ -0,0 +1,97 @@
+package orders
+
+import (
+	"log"
+
+	"github.com/shopspring/decimal"
+)
+
+// CancelReasonMaxSlippage is the cancel reason of market orders rejected for
+// filling at a price past their slippage tolerance
+const CancelReasonMaxSlippage = "max_slippage"
+
+// slippageTolerance returns the slippage tolerance of a market order request
+// in basis points, zero if it has none
+func (m *Manager) slippageTolerance(req *OrderRequest) float64 {
+	if req.Type != OrderTypeMarket {
+		return 0
+	}
+	if req.MaxSlippageBps > 0 {
+		return req.MaxSlippageBps
+	}
+	return m.config.MaxSlippageBps
+}
+
+// expectedFillPrice returns the price a market order is expected to fill at:
+// its own price if set, otherwise the opposite side of the exchange's book. It
+// returns zero if neither is known.
+func (m *Manager) expectedFillPrice(req *OrderRequest, exchange string) decimal.Decimal {
+	if req.Price.IsPositive() {
+		return req.Price
+	}
+
+	m.mu.RLock()
+	books := m.books
+	m.mu.RUnlock()
+	if books == nil {
+		return decimal.Zero
+	}
+
+	bid, ask, ok := books.BestQuote(exchange, req.Symbol)
+	if !ok {
+		return decimal.Zero
+	}
+	if req.Side == OrderSideSell {
+		return decimal.NewFromFloat(bid)
+	}
+	return decimal.NewFromFloat(ask)
+}
+
+// slippageBps returns how much worse than expected a fill price is for an
+// order side, in basis points. Fills better than expected are negative.
+func slippageBps(side OrderSide, expected, filled decimal.Decimal) float64 {
+	if !expected.IsPositive() {
+		return 0
+	}
+	slippage := filled.Sub(expected)
+	if side == OrderSideSell {
+		slippage = slippage.Neg()
+	}
+	bps, _ := slippage.Div(expected).Mul(decimal.NewFromInt(10000)).Float64()
+	return bps
+}
+
+// rejectSlippedFill rejects a market order instead of applying a fill whose
+// price is past the order's slippage tolerance, reporting whether it did. A
+// partially filled order keeps its earlier fills. Must be called with m.mu
+// held.
+func (m *Manager) rejectSlippedFill(order *Order, update *OrderUpdate) bool {
+	if order.MaxSlippageBps <= 0 || !order.ExpectedPrice.IsPositive() {
+		return false
+	}
+	if !update.FilledQty.GreaterThan(order.FilledQty) || !update.FilledPrice.IsPositive() {
+		return false
+	}
+
+	slippage := slippageBps(order.Side, order.ExpectedPrice, update.FilledPrice)
+	if slippage <= order.MaxSlippageBps {
+		return false
+	}
+
+	order.Status = OrderStatusRejected
+	if order.FilledQty.IsPositive() {
+		order.Status = OrderStatusPartialCancelled
+	}
+	order.CancelReason = CancelReasonMaxSlippage
+	order.UpdatedAt = update.Timestamp
+	m.finishOrder(order)
+	m.recordHistory(order, OrderEventRejected, CancelReasonMaxSlippage, nil, update.Timestamp)
+	m.refreshSpreadForOrder(order.ID)
+
+	log.Printf("Order %s rejected: fill at %s slipped %.1f bps from %s (max %.1f bps)",
+		order.ID, update.FilledPrice.String(), slippage, order.ExpectedPrice.String(), order.MaxSlippageBps)
+	if m.metrics != nil {
+		m.metrics.RecordOrderEvent("order_rejected", "max_slippage")
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package orders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/normalizer"
+	"velocimex/internal/orderbook"
+)
+
+func TestMaxSlippage(t *testing.T) {
+	books := orderbook.NewManager()
+	books.UpdateOrderBook("mock_exchange", "BTC/USD",
+		[]normalizer.PriceLevel{{Price: 49990, Volume: 1}},
+		[]normalizer.PriceLevel{{Price: 50000, Volume: 1}},
+	)
+
+	config := DefaultManagerConfig()
+	config.MaxSlippageBps = 50
+	manager := NewManager(config, &MockSmartRouter{}, nil)
+	manager.SetOrderBooks(books)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	// Market orders without a price expect to fill at the opposite side of the book
+	submit := func(side OrderSide, maxSlippageBps float64) *Order {
+		order, err := manager.SubmitOrder(ctx, &OrderRequest{
+			Symbol:         "BTC/USD",
+			Side:           side,
+			Type:           OrderTypeMarket,
+			Quantity:       decimal.NewFromInt(1),
+			MaxSlippageBps: maxSlippageBps,
+		})
+		require.NoError(t, err)
+		return order
+	}
+	fill := func(order *Order, price float64) OrderStatus {
+		require.NoError(t, manager.UpdateOrderStatus(ctx, &OrderUpdate{
+			OrderID:     order.ID,
+			Status:      OrderStatusFilled,
+			FilledQty:   order.Quantity,
+			FilledPrice: decimal.NewFromFloat(price),
+			Timestamp:   time.Now(),
+			Exchange:    order.Exchange,
+		}))
+		var status OrderStatus
+		require.Eventually(t, func() bool {
+			status, _ = orderState(manager, order.ID)
+			return status == OrderStatusFilled || status == OrderStatusRejected
+		}, time.Second, 5*time.Millisecond)
+		return status
+	}
+	executions := func(order *Order) int {
+		list, err := manager.GetExecutions(ctx, map[string]interface{}{"order_id": order.ID})
+		require.NoError(t, err)
+		return len(list)
+	}
+
+	// 10 bps worse than the 50000 ask, within the order's 20 bps
+	within := submit(OrderSideBuy, 20)
+	assert.True(t, within.ExpectedPrice.Equal(decimal.NewFromInt(50000)))
+	assert.Equal(t, OrderStatusFilled, fill(within, 50050))
+	assert.Equal(t, 1, executions(within))
+
+	// 30 bps worse, past the order's 20 bps
+	past := submit(OrderSideBuy, 20)
+	assert.Equal(t, OrderStatusRejected, fill(past, 50150))
+	assert.Equal(t, 0, executions(past))
+	manager.mu.RLock()
+	assert.Equal(t, CancelReasonMaxSlippage, manager.orders[past.ID].CancelReason)
+	assert.True(t, manager.orders[past.ID].FilledQty.IsZero())
+	manager.mu.RUnlock()
+
+	// Sells slip downwards from the bid; 60 bps is past the 50 bps default
+	sell := submit(OrderSideSell, 0)
+	assert.Equal(t, 50.0, sell.MaxSlippageBps)
+	assert.Equal(t, OrderStatusRejected, fill(sell, 49690))
+
+	// Fills better than expected are never slippage
+	better := submit(OrderSideSell, 0)
+	assert.Equal(t, OrderStatusFilled, fill(better, 50500))
+}
+
+func TestMaxSlippageLimitOrdersUnchecked(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.MaxSlippageBps = 10
+	manager := NewManager(config, &MockSmartRouter{}, nil)
+
+	order, err := manager.SubmitOrder(context.Background(), &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromInt(1),
+		Price:    decimal.NewFromInt(50000),
+	})
+	require.NoError(t, err)
+	assert.Zero(t, order.MaxSlippageBps)
+
+	_, err = manager.SubmitOrder(context.Background(), &OrderRequest{
+		Symbol:         "BTC/USD",
+		Side:           OrderSideBuy,
+		Type:           OrderTypeMarket,
+		Quantity:       decimal.NewFromInt(1),
+		MaxSlippageBps: -1,
+	})
+	assert.Error(t, err)
+}
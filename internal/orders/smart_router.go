@@ -2,6 +2,7 @@ package orders
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"sync"
@@ -12,6 +13,9 @@ import (
 	"velocimex/internal/orderbook"
 )
 
+// ErrLatencyBudget is returned when no venue is expected to be fast enough for an order
+var ErrLatencyBudget = errors.New("no route within latency budget")
+
 // SmartRouterConfig holds configuration for the smart router
 type SmartRouterConfig struct {
 	MaxSlippage     decimal.Decimal `json:"max_slippage"`
@@ -108,12 +112,28 @@ func (sr *SmartRouterImpl) RouteOrder(ctx context.Context, order *OrderRequest)
 		return nil, fmt.Errorf("no valid routes found")
 	}
 
-	// Sort by score (highest first)
+	// Sort by score (highest first), stale venues last and ties by venue priority
 	sort.Slice(scoredRoutes, func(i, j int) bool {
-		return scoredRoutes[i].Score > scoredRoutes[j].Score
+		a, b := scoredRoutes[i], scoredRoutes[j]
+		if a.Stale != b.Stale {
+			return !a.Stale
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		return a.Exchange < b.Exchange
 	})
 
 	bestRoute := scoredRoutes[0]
+	if order.MaxLatency > 0 {
+		var err error
+		if bestRoute, err = withinLatencyBudget(scoredRoutes, order.MaxLatency); err != nil {
+			return nil, err
+		}
+	}
 	if bestRoute.Score < sr.config.MinConfidence {
 		return nil, fmt.Errorf("no route meets minimum confidence threshold")
 	}
@@ -143,6 +163,8 @@ func (sr *SmartRouterImpl) GetBestPrice(ctx context.Context, symbol string, side
 
 	var bestPrice decimal.Decimal
 	var bestExchange string
+	var bestStale bool
+	var bestPriority int
 
 	for exchange, data := range sr.marketData[symbol] {
 		if data == nil {
@@ -165,10 +187,26 @@ func (sr *SmartRouterImpl) GetBestPrice(ctx context.Context, symbol string, side
 			continue // Skip if not enough volume
 		}
 
-		if bestPrice.IsZero() || (side == OrderSideBuy && price.LessThan(bestPrice)) ||
-			(side == OrderSideSell && price.GreaterThan(bestPrice)) {
+		// Fresh venues beat stale ones, then the better price, then the higher priority
+		stale, priority := sr.venueRank(exchange, data)
+		better := bestExchange == ""
+		switch {
+		case better:
+		case stale != bestStale:
+			better = !stale
+		case !price.Equal(bestPrice):
+			better = (side == OrderSideBuy && price.LessThan(bestPrice)) ||
+				(side == OrderSideSell && price.GreaterThan(bestPrice))
+		case priority != bestPriority:
+			better = priority > bestPriority
+		default:
+			better = exchange < bestExchange
+		}
+		if better {
 			bestPrice = price
 			bestExchange = exchange
+			bestStale = stale
+			bestPriority = priority
 		}
 	}
 
@@ -209,6 +247,46 @@ type ScoredRoute struct {
 	Reason          string
 	ExpectedSlippage decimal.Decimal
 	ExpectedFee      decimal.Decimal
+	Latency          time.Duration
+	Stale            bool
+	Priority         int
+}
+
+// venueRank returns whether a venue's market data is older than the order
+// book manager's feed StaleAfter, and the venue's feed priority
+func (sr *SmartRouterImpl) venueRank(exchange string, data *MarketData) (bool, int) {
+	if sr.orderBookMgr == nil {
+		return false, 0
+	}
+	priority := sr.orderBookMgr.FeedPriority()
+	stale := priority.StaleAfter > 0 && !data.Timestamp.IsZero() && time.Since(data.Timestamp) > priority.StaleAfter
+	return stale, priority.Priority(exchange)
+}
+
+// withinLatencyBudget returns the best scored route whose expected latency is
+// within the budget. Routes must be sorted best first.
+func withinLatencyBudget(routes []*ScoredRoute, budget time.Duration) (*ScoredRoute, error) {
+	for i, route := range routes {
+		if route.Latency > budget {
+			continue
+		}
+		if i > 0 {
+			route.Reason = "latency_budget"
+		}
+		return route, nil
+	}
+	return nil, fmt.Errorf("%w: fastest venue %s, budget %s", ErrLatencyBudget, fastestLatency(routes), budget)
+}
+
+// fastestLatency returns the lowest expected latency among the routes
+func fastestLatency(routes []*ScoredRoute) time.Duration {
+	fastest := routes[0].Latency
+	for _, route := range routes[1:] {
+		if route.Latency < fastest {
+			fastest = route.Latency
+		}
+	}
+	return fastest
 }
 
 // scoreRoute calculates a score for a route based on various factors
@@ -242,6 +320,7 @@ func (sr *SmartRouterImpl) scoreRoute(order *OrderRequest, route ExchangeRoute)
 		latencyScore*sr.config.LatencyWeight +
 		feeScore*sr.config.FeeWeight)
 
+	stale, priority := sr.venueRank(route.Exchange, marketData)
 	return &ScoredRoute{
 		Exchange:         route.Exchange,
 		Route:            route.Route,
@@ -249,6 +328,9 @@ func (sr *SmartRouterImpl) scoreRoute(order *OrderRequest, route ExchangeRoute)
 		Reason:           "optimal_combination",
 		ExpectedSlippage: priceImpact,
 		ExpectedFee:      expectedFee,
+		Latency:          marketData.Latency,
+		Stale:            stale,
+		Priority:         priority,
 	}, nil
 }
 
@@ -291,7 +373,7 @@ func (sr *SmartRouterImpl) calculatePriceImpact(order *OrderRequest, marketData
 		remainingQty = remainingQty.Sub(levelVolume)
 	}
 
-	if volume.IsZero() {
+	if volume.IsZero() || !targetPrice.IsPositive() {
 		return decimal.NewFromFloat(0.001)
 	}
 
@@ -327,7 +409,7 @@ func (sr *SmartRouterImpl) calculateVolumeScore(order *OrderRequest, marketData
 		availableVolume = marketData.BidVolume
 	}
 
-	if availableVolume.IsZero() {
+	if availableVolume.IsZero() || !order.Quantity.IsPositive() {
 		return 0.0
 	}
 
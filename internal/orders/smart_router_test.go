@@ -0,0 +1,147 @@
+package orders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/orderbook"
+)
+
+// TestRouteOrderLatencyBudget tests that an order with a latency budget is
+// routed to the best venue expected to be fast enough for it
+func TestRouteOrderLatencyBudget(t *testing.T) {
+	// Venues rank by fee alone, and the cheapest are the slowest
+	config := DefaultSmartRouterConfig()
+	config.LatencyWeight = 0
+	config.FeeWeight = 1
+	config.MinConfidence = 0
+	router := NewSmartRouter(config, nil)
+
+	venues := []struct {
+		exchange string
+		fee      float64
+		latency  time.Duration
+	}{
+		{"binance", 0, 300 * time.Millisecond},
+		{"coinbase", 0.0005, 80 * time.Millisecond},
+		{"kraken", 0.001, 20 * time.Millisecond},
+	}
+	for _, venue := range venues {
+		router.UpdateMarketData(venue.exchange, &MarketData{
+			Exchange:  venue.exchange,
+			Symbol:    "BTC/USD",
+			BidPrice:  decimal.NewFromInt(100),
+			AskPrice:  decimal.NewFromInt(101),
+			BidVolume: decimal.NewFromInt(10),
+			AskVolume: decimal.NewFromInt(10),
+			FeeRate:   decimal.NewFromFloat(venue.fee),
+			Latency:   venue.latency,
+		})
+	}
+
+	route := func(budget time.Duration) (*RoutingDecision, error) {
+		return router.RouteOrder(context.Background(), &OrderRequest{
+			Symbol:     "BTC/USD",
+			Side:       OrderSideBuy,
+			Type:       OrderTypeLimit,
+			Quantity:   decimal.NewFromFloat(0.1),
+			Price:      decimal.NewFromInt(101),
+			MaxLatency: budget,
+		})
+	}
+
+	tests := []struct {
+		name     string
+		budget   time.Duration
+		exchange string
+		reason   string
+	}{
+		{"no budget", 0, "binance", "optimal_combination"},
+		{"best within budget", 500 * time.Millisecond, "binance", "optimal_combination"},
+		{"next best", 100 * time.Millisecond, "coinbase", "latency_budget"},
+		{"fastest", 50 * time.Millisecond, "kraken", "latency_budget"},
+		{"exact budget", 20 * time.Millisecond, "kraken", "latency_budget"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := route(tt.budget)
+			require.NoError(t, err)
+			assert.Equal(t, tt.exchange, decision.Exchange)
+			assert.Equal(t, tt.reason, decision.Reason)
+		})
+	}
+
+	_, err := route(10 * time.Millisecond)
+	assert.ErrorIs(t, err, ErrLatencyBudget)
+}
+
+// TestRouterFeedPriority tests that venue priority breaks price ties and that
+// venues with stale market data are only chosen when nothing fresher is left
+func TestRouterFeedPriority(t *testing.T) {
+	books := orderbook.NewManager()
+	books.SetFeedPriority(orderbook.FeedPriorityConfig{
+		Priorities: map[string]int{"kraken": 2, "coinbase": 1},
+		StaleAfter: time.Second,
+	})
+
+	config := DefaultSmartRouterConfig()
+	config.MinConfidence = 0
+	router := NewSmartRouter(config, books)
+	quote := func(exchange string, ask float64, age time.Duration) {
+		router.UpdateMarketData(exchange, &MarketData{
+			Exchange:  exchange,
+			Symbol:    "BTC/USD",
+			BidPrice:  decimal.NewFromInt(100),
+			AskPrice:  decimal.NewFromFloat(ask),
+			BidVolume: decimal.NewFromInt(10),
+			AskVolume: decimal.NewFromInt(10),
+			Timestamp: time.Now().Add(-age),
+		})
+	}
+	bestAsk := func() string {
+		decision, err := router.GetBestPrice(context.Background(), "BTC/USD", OrderSideBuy, decimal.NewFromInt(1))
+		require.NoError(t, err)
+		return decision.Exchange
+	}
+
+	// Equal prices go to the highest priority
+	quote("binance", 101, 0)
+	quote("coinbase", 101, 0)
+	quote("kraken", 101, 0)
+	assert.Equal(t, "kraken", bestAsk())
+
+	// A better price still wins over priority
+	quote("binance", 100.5, 0)
+	assert.Equal(t, "binance", bestAsk())
+
+	// Stale venues rank below fresh ones whatever their price
+	quote("binance", 100.5, time.Minute)
+	quote("kraken", 101, time.Minute)
+	assert.Equal(t, "coinbase", bestAsk())
+
+	// Route scoring ties are broken the same way
+	decision, err := router.RouteOrder(context.Background(), &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(0.1),
+		Price:    decimal.NewFromInt(101),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "coinbase", decision.Exchange)
+
+	quote("kraken", 101, 0)
+	decision, err = router.RouteOrder(context.Background(), &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromFloat(0.1),
+		Price:    decimal.NewFromInt(101),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "kraken", decision.Exchange)
+}
@@ -0,0 +1,318 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// SpreadTag is the order tag carrying the ID of the spread an order is a leg of
+const SpreadTag = "spread_id"
+
+// Spread order errors
+var (
+	ErrInvalidSpread       = errors.New("invalid spread order")
+	ErrSpreadRatioMismatch = errors.New("leg quantities do not match spread ratio")
+	ErrSpreadLegFailed     = errors.New("spread leg failed")
+	ErrSpreadNotFound      = errors.New("spread not found")
+)
+
+// SpreadStatus represents the combined status of a multi-leg order
+type SpreadStatus string
+
+const (
+	SpreadStatusWorking   SpreadStatus = "WORKING"
+	SpreadStatusFilled    SpreadStatus = "FILLED"
+	SpreadStatusFailed    SpreadStatus = "FAILED" // A leg could not fill
+	SpreadStatusCancelled SpreadStatus = "CANCELLED"
+)
+
+// SpreadOrder is a snapshot of a multi-leg order and its legs
+type SpreadOrder struct {
+	ID          string            `json:"id"`
+	Legs        []*Order          `json:"legs"`
+	Ratios      []decimal.Decimal `json:"ratios"`
+	Status      SpreadStatus      `json:"status"`
+	FilledUnits decimal.Decimal   `json:"filled_units"` // Complete spread units filled across all legs
+	Reason      string            `json:"reason,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// spreadState is the manager's record of a spread; leg orders live in m.orders
+type spreadState struct {
+	id        string
+	legCount  int
+	legIDs    []string
+	ratios    []decimal.Decimal
+	status    SpreadStatus
+	reason    string
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// SubmitSpread submits the legs of a spread together. Leg quantities must be in
+// proportion to ratio; a nil ratio uses the leg quantities themselves. Every
+// leg passes the pre-trade checks and is reserved against the order limits
+// before any is queued, so a leg rejected up front leaves no other leg working.
+// If a leg is later rejected, cancelled or expires, the spread fails and, when
+// CancelSpreadOnLegFailure is set, the remaining legs are cancelled.
+func (m *Manager) SubmitSpread(ctx context.Context, legs []*OrderRequest, ratio []decimal.Decimal) (*SpreadOrder, error) {
+	ratios, err := spreadRatios(legs, ratio)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	state := &spreadState{
+		id:        uuid.New().String(),
+		legCount:  len(legs),
+		legIDs:    make([]string, 0, len(legs)),
+		ratios:    ratios,
+		status:    SpreadStatusWorking,
+		createdAt: now,
+		updatedAt: now,
+	}
+
+	m.mu.Lock()
+	m.spreads[state.id] = state
+	m.mu.Unlock()
+
+	requests := make([]*OrderRequest, len(legs))
+	prepared := make([]*Order, len(legs))
+	for i, leg := range legs {
+		// Submit a copy so the caller's request and tags are left untouched
+		req := *leg
+		req.Tags = make(map[string]string, len(leg.Tags)+1)
+		for k, v := range leg.Tags {
+			req.Tags[k] = v
+		}
+		req.Tags[SpreadTag] = state.id
+
+		order, err := m.prepareOrder(ctx, &req, false)
+		if err != nil {
+			return m.rejectSpread(state, fmt.Sprintf("leg %d rejected: %v", i, err), fmt.Errorf("%w: leg %d: %w", ErrSpreadLegFailed, i, err))
+		}
+		requests[i] = &req
+		prepared[i] = order
+	}
+
+	stored, err := m.storeOrders(prepared, false)
+	if err != nil {
+		return m.rejectSpread(state, fmt.Sprintf("legs rejected: %v", err), fmt.Errorf("%w: %w", ErrSpreadLegFailed, err))
+	}
+
+	m.mu.Lock()
+	for _, order := range stored {
+		state.legIDs = append(state.legIDs, order.ID)
+		m.spreadLegs[order.ID] = state.id
+	}
+	m.refreshSpread(state)
+	m.mu.Unlock()
+
+	for i, order := range stored {
+		if err := m.queueOrder(ctx, requests[i], order); err != nil {
+			return nil, err
+		}
+	}
+
+	if m.metrics != nil {
+		m.metrics.RecordOrderEvent("spread_submitted", "info")
+	}
+
+	return m.GetSpread(state.id)
+}
+
+// rejectSpread fails a spread none of whose legs were stored
+func (m *Manager) rejectSpread(state *spreadState, reason string, err error) (*SpreadOrder, error) {
+	m.mu.Lock()
+	m.failSpread(state, reason)
+	m.mu.Unlock()
+	spread, _ := m.GetSpread(state.id)
+	return spread, err
+}
+
+// spreadRatios validates the legs against the requested ratio
+func spreadRatios(legs []*OrderRequest, ratio []decimal.Decimal) ([]decimal.Decimal, error) {
+	if len(legs) < 2 {
+		return nil, fmt.Errorf("%w: at least two legs required", ErrInvalidSpread)
+	}
+	for i, leg := range legs {
+		if leg == nil {
+			return nil, fmt.Errorf("%w: leg %d is nil", ErrInvalidSpread, i)
+		}
+		if !leg.Quantity.IsPositive() {
+			return nil, fmt.Errorf("%w: leg %d has invalid quantity", ErrInvalidSpread, i)
+		}
+	}
+
+	if ratio == nil {
+		ratios := make([]decimal.Decimal, len(legs))
+		for i, leg := range legs {
+			ratios[i] = leg.Quantity
+		}
+		return ratios, nil
+	}
+
+	if len(ratio) != len(legs) {
+		return nil, fmt.Errorf("%w: %d ratios for %d legs", ErrInvalidSpread, len(ratio), len(legs))
+	}
+	for i, r := range ratio {
+		if !r.IsPositive() {
+			return nil, fmt.Errorf("%w: ratio %d must be positive", ErrInvalidSpread, i)
+		}
+	}
+
+	units := legs[0].Quantity.Div(ratio[0])
+	for i := 1; i < len(legs); i++ {
+		if !legs[i].Quantity.Div(ratio[i]).Equal(units) {
+			return nil, fmt.Errorf("%w: leg %d quantity %s, ratio %s", ErrSpreadRatioMismatch, i, legs[i].Quantity, ratio[i])
+		}
+	}
+	return append([]decimal.Decimal(nil), ratio...), nil
+}
+
+// GetSpread returns a snapshot of a spread and copies of its legs
+func (m *Manager) GetSpread(spreadID string) (*SpreadOrder, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	state, exists := m.spreads[spreadID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrSpreadNotFound, spreadID)
+	}
+
+	spread := &SpreadOrder{
+		ID:        state.id,
+		Legs:      make([]*Order, 0, len(state.legIDs)),
+		Ratios:    append([]decimal.Decimal(nil), state.ratios...),
+		Status:    state.status,
+		Reason:    state.reason,
+		CreatedAt: state.createdAt,
+		UpdatedAt: state.updatedAt,
+	}
+
+	filledUnits := decimal.Zero
+	for i, legID := range state.legIDs {
+		order, exists := m.orders[legID]
+		if !exists {
+			continue
+		}
+		leg := *order
+		spread.Legs = append(spread.Legs, &leg)
+
+		units := leg.FilledQty.Div(state.ratios[i])
+		if i == 0 || units.LessThan(filledUnits) {
+			filledUnits = units
+		}
+	}
+	if len(state.legIDs) < state.legCount {
+		filledUnits = decimal.Zero
+	}
+	spread.FilledUnits = filledUnits
+
+	return spread, nil
+}
+
+// CancelSpread cancels all working legs of a spread
+func (m *Manager) CancelSpread(ctx context.Context, spreadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, exists := m.spreads[spreadID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrSpreadNotFound, spreadID)
+	}
+	if state.status != SpreadStatusWorking {
+		return fmt.Errorf("cannot cancel spread with status: %s", state.status)
+	}
+
+	m.cancelSpreadLegs(state)
+	state.status = SpreadStatusCancelled
+	state.updatedAt = time.Now()
+	return nil
+}
+
+// refreshSpreadForOrder re-evaluates the spread an order belongs to. Must be called with m.mu held.
+func (m *Manager) refreshSpreadForOrder(orderID string) {
+	spreadID, ok := m.spreadLegs[orderID]
+	if !ok {
+		return
+	}
+	if state, exists := m.spreads[spreadID]; exists {
+		m.refreshSpread(state)
+	}
+}
+
+// refreshSpread derives the spread status from its legs. Must be called with m.mu held.
+func (m *Manager) refreshSpread(state *spreadState) {
+	if state.status != SpreadStatusWorking {
+		return
+	}
+
+	filled := 0
+	for i, legID := range state.legIDs {
+		order, exists := m.orders[legID]
+		if !exists {
+			continue
+		}
+		switch order.Status {
+		case OrderStatusFilled:
+			filled++
+		case OrderStatusRejected, OrderStatusCancelled, OrderStatusExpired, OrderStatusPartialCancelled:
+			m.failSpread(state, fmt.Sprintf("leg %d %s", i, order.Status))
+			return
+		}
+	}
+
+	if filled == state.legCount {
+		state.status = SpreadStatusFilled
+		state.updatedAt = time.Now()
+		if m.metrics != nil {
+			m.metrics.RecordOrderEvent("spread_filled", "info")
+		}
+	}
+}
+
+// failSpread marks a spread failed and, if configured, cancels its working legs.
+// Must be called with m.mu held.
+func (m *Manager) failSpread(state *spreadState, reason string) {
+	if state.status != SpreadStatusWorking {
+		return
+	}
+
+	state.status = SpreadStatusFailed
+	state.reason = reason
+	state.updatedAt = time.Now()
+	log.Printf("Spread %s failed: %s", state.id, reason)
+
+	if m.config.CancelSpreadOnLegFailure {
+		m.cancelSpreadLegs(state)
+	}
+	if m.metrics != nil {
+		m.metrics.RecordOrderEvent("spread_failed", "warning")
+	}
+}
+
+// cancelSpreadLegs cancels every working leg of a spread. Must be called with m.mu held.
+func (m *Manager) cancelSpreadLegs(state *spreadState) {
+	now := time.Now()
+	for _, legID := range state.legIDs {
+		order, exists := m.orders[legID]
+		if !exists {
+			continue
+		}
+		switch order.Status {
+		case OrderStatusPending, OrderStatusSubmitted, OrderStatusPartial:
+			order.Status = OrderStatusCancelled
+			order.UpdatedAt = now
+			m.finishOrder(order)
+			m.recordHistory(order, OrderEventCancelled, "spread", nil, now)
+		}
+	}
+}
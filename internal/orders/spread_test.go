@@ -0,0 +1,220 @@
+package orders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func spreadLegs(btcQty, ethQty float64) []*OrderRequest {
+	return []*OrderRequest{
+		{
+			Symbol:   "BTC/USD",
+			Side:     OrderSideBuy,
+			Type:     OrderTypeLimit,
+			Quantity: decimal.NewFromFloat(btcQty),
+			Price:    decimal.NewFromFloat(50000.0),
+		},
+		{
+			Symbol:   "ETH/USD",
+			Side:     OrderSideSell,
+			Type:     OrderTypeLimit,
+			Quantity: decimal.NewFromFloat(ethQty),
+			Price:    decimal.NewFromFloat(3000.0),
+		},
+	}
+}
+
+func spreadStatus(t *testing.T, manager *Manager, spreadID string) SpreadStatus {
+	spread, err := manager.GetSpread(spreadID)
+	require.NoError(t, err)
+	return spread.Status
+}
+
+// TestSpreadPaperFill tests that both legs of a spread fill in paper mode
+func TestSpreadPaperFill(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.EnablePaperTrading = true
+	manager := NewManager(config, &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	legs := spreadLegs(1, 15)
+	spread, err := manager.SubmitSpread(ctx, legs, []decimal.Decimal{decimal.NewFromInt(1), decimal.NewFromInt(15)})
+	require.NoError(t, err)
+	require.Len(t, spread.Legs, 2)
+	assert.Equal(t, SpreadStatusWorking, spread.Status)
+	assert.Equal(t, spread.ID, spread.Legs[0].Tags[SpreadTag])
+	assert.Nil(t, legs[0].Tags, "caller's request should not be modified")
+
+	assert.Eventually(t, func() bool {
+		return spreadStatus(t, manager, spread.ID) == SpreadStatusFilled
+	}, 2*time.Second, 10*time.Millisecond)
+
+	spread, err = manager.GetSpread(spread.ID)
+	require.NoError(t, err)
+	for _, leg := range spread.Legs {
+		assert.Equal(t, OrderStatusFilled, leg.Status)
+	}
+	assert.True(t, spread.FilledUnits.IsPositive())
+	assert.True(t, spread.FilledUnits.LessThanOrEqual(decimal.NewFromInt(1)))
+}
+
+// TestSpreadLegRejectedQueuesNone tests that a leg rejected by the pre-trade
+// checks fails the spread before any leg is stored or queued
+func TestSpreadLegRejectedQueuesNone(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.EnablePaperTrading = true
+	config.SymbolLimits["ETH/USD"] = SymbolLimits{MaxQuantity: decimal.NewFromInt(10)}
+	manager := NewManager(config, &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	spread, err := manager.SubmitSpread(ctx, spreadLegs(1, 15), nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrSpreadLegFailed)
+	assert.ErrorIs(t, err, ErrAboveMaxQuantity)
+	require.NotNil(t, spread)
+	assert.Equal(t, SpreadStatusFailed, spread.Status)
+	assert.Empty(t, spread.Legs)
+
+	// The first leg passed its own checks but was never stored or filled
+	time.Sleep(200 * time.Millisecond)
+	list, err := manager.GetOrders(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	positions, err := manager.GetPositions(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, positions)
+}
+
+// TestSpreadLegsReservedTogether tests that legs which together exceed the
+// open order limit are all rejected, even though the first would fit
+func TestSpreadLegsReservedTogether(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.MaxConcurrentOrders = 1
+	manager := NewManager(config, &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	spread, err := manager.SubmitSpread(ctx, spreadLegs(1, 15), nil)
+	assert.ErrorIs(t, err, ErrSpreadLegFailed)
+	assert.ErrorIs(t, err, ErrMaxOpenOrders)
+	require.NotNil(t, spread)
+	assert.Equal(t, SpreadStatusFailed, spread.Status)
+
+	list, err := manager.GetOrders(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	// A leg over the daily cap gives back the reservations of the legs before it
+	config = DefaultManagerConfig()
+	config.DailyOrderLimit.MaxOrders = 1
+	manager = NewManager(config, &MockSmartRouter{}, nil)
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	_, err = manager.SubmitSpread(ctx, spreadLegs(1, 15), nil)
+	assert.ErrorIs(t, err, ErrDailyOrderLimit)
+	_, err = manager.SubmitOrder(ctx, spreadLegs(1, 15)[0])
+	assert.NoError(t, err)
+}
+
+// TestSpreadLegFailureAfterSubmit tests that a leg rejected by the exchange cancels the other legs
+func TestSpreadLegFailureAfterSubmit(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	spread, err := manager.SubmitSpread(ctx, spreadLegs(1, 15), nil)
+	require.NoError(t, err)
+	require.Len(t, spread.Legs, 2)
+
+	require.NoError(t, manager.UpdateOrderStatus(ctx, &OrderUpdate{
+		OrderID:   spread.Legs[1].ID,
+		Status:    OrderStatusRejected,
+		Timestamp: time.Now(),
+		Reason:    "insufficient liquidity",
+	}))
+
+	assert.Eventually(t, func() bool {
+		return spreadStatus(t, manager, spread.ID) == SpreadStatusFailed
+	}, time.Second, 5*time.Millisecond)
+
+	status, _ := orderState(manager, spread.Legs[0].ID)
+	assert.Equal(t, OrderStatusCancelled, status)
+}
+
+// TestSpreadLegFailureWithoutCancel tests that other legs keep working when cancellation is disabled
+func TestSpreadLegFailureWithoutCancel(t *testing.T) {
+	config := DefaultManagerConfig()
+	config.CancelSpreadOnLegFailure = false
+	manager := NewManager(config, &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	spread, err := manager.SubmitSpread(ctx, spreadLegs(1, 15), nil)
+	require.NoError(t, err)
+
+	require.NoError(t, manager.CancelOrder(ctx, spread.Legs[1].ID))
+	assert.Eventually(t, func() bool {
+		return spreadStatus(t, manager, spread.ID) == SpreadStatusFailed
+	}, time.Second, 5*time.Millisecond)
+
+	status, _ := orderState(manager, spread.Legs[0].ID)
+	assert.NotEqual(t, OrderStatusCancelled, status)
+}
+
+// TestSpreadValidation tests leg and ratio validation
+func TestSpreadValidation(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	_, err := manager.SubmitSpread(ctx, spreadLegs(1, 15)[:1], nil)
+	assert.ErrorIs(t, err, ErrInvalidSpread)
+
+	_, err = manager.SubmitSpread(ctx, spreadLegs(1, 15), []decimal.Decimal{decimal.NewFromInt(1)})
+	assert.ErrorIs(t, err, ErrInvalidSpread)
+
+	_, err = manager.SubmitSpread(ctx, spreadLegs(1, 15), []decimal.Decimal{decimal.NewFromInt(1), decimal.NewFromInt(10)})
+	assert.ErrorIs(t, err, ErrSpreadRatioMismatch)
+
+	// Ratio 2:30 is the same proportion as 1:15
+	_, err = manager.SubmitSpread(ctx, spreadLegs(1, 15), []decimal.Decimal{decimal.NewFromInt(2), decimal.NewFromInt(30)})
+	assert.NoError(t, err)
+
+	_, err = manager.GetSpread("missing")
+	assert.ErrorIs(t, err, ErrSpreadNotFound)
+}
+
+// TestCancelSpread tests cancelling every leg of a working spread
+func TestCancelSpread(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig(), &MockSmartRouter{}, nil)
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	spread, err := manager.SubmitSpread(ctx, spreadLegs(1, 15), nil)
+	require.NoError(t, err)
+
+	require.NoError(t, manager.CancelSpread(ctx, spread.ID))
+	spread, err = manager.GetSpread(spread.ID)
+	require.NoError(t, err)
+	assert.Equal(t, SpreadStatusCancelled, spread.Status)
+	for _, leg := range spread.Legs {
+		assert.Equal(t, OrderStatusCancelled, leg.Status)
+	}
+
+	assert.Error(t, manager.CancelSpread(ctx, spread.ID))
+}
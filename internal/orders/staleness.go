@@ -0,0 +1,71 @@
+package orders
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// StaleBookAction selects what happens when an order routes to a stale book
+type StaleBookAction string
+
+const (
+	// StaleBookReject rejects the order
+	StaleBookReject StaleBookAction = "reject"
+	// StaleBookWarn logs a warning and submits the order anyway
+	StaleBookWarn StaleBookAction = "warn"
+)
+
+// StaleBookConfig configures the order book staleness guard
+type StaleBookConfig struct {
+	// MaxAge is the oldest book an order may be routed against; zero disables the guard
+	MaxAge time.Duration   `json:"max_age" yaml:"maxAge"`
+	Action StaleBookAction `json:"action" yaml:"action"`
+}
+
+// DefaultStaleBookConfig returns default staleness guard configuration
+func DefaultStaleBookConfig() StaleBookConfig {
+	return StaleBookConfig{
+		MaxAge: 0,
+		Action: StaleBookReject,
+	}
+}
+
+// checkBookFreshness applies the staleness guard to the book an order was routed to
+func (m *Manager) checkBookFreshness(req *OrderRequest, exchange string) error {
+	cfg := m.config.StaleBook
+	if cfg.MaxAge <= 0 {
+		return nil
+	}
+
+	m.mu.RLock()
+	books := m.books
+	m.mu.RUnlock()
+	if books == nil {
+		return nil
+	}
+
+	var reason string
+	age, ok := books.BookAge(exchange, req.Symbol)
+	switch {
+	case !ok:
+		reason = fmt.Sprintf("no order book for %s on %s", req.Symbol, exchange)
+	case age > cfg.MaxAge:
+		reason = fmt.Sprintf("%s book on %s is %s old (max %s)", req.Symbol, exchange, age.Round(time.Millisecond), cfg.MaxAge)
+	default:
+		return nil
+	}
+
+	if cfg.Action == StaleBookWarn {
+		log.Printf("Warning: submitting order against stale book: %s", reason)
+		if m.metrics != nil {
+			m.metrics.RecordOrderEvent("stale_book", "warning")
+		}
+		return nil
+	}
+
+	if m.metrics != nil {
+		m.metrics.RecordOrderEvent("order_rejected", "stale_book")
+	}
+	return fmt.Errorf("%w: %s", ErrStaleOrderBook, reason)
+}
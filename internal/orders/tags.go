@@ -0,0 +1,64 @@
+package orders
+
+import (
+	"context"
+	"sort"
+)
+
+// OrderCancelFailure is an order a bulk cancel could not cancel
+type OrderCancelFailure struct {
+	OrderID string `json:"order_id"`
+	Error   string `json:"error"`
+}
+
+// BulkCancelResult reports the orders a bulk cancel requested cancellation of
+type BulkCancelResult struct {
+	Cancelled []string             `json:"cancelled"`
+	Failed    []OrderCancelFailure `json:"failed"`
+}
+
+// matchesTags reports whether an order carries every tag. A tag with an
+// empty value matches any value.
+func matchesTags(order *Order, tags map[string]string) bool {
+	for key, value := range tags {
+		tag, ok := order.Tags[key]
+		if !ok || (value != "" && tag != value) {
+			return false
+		}
+	}
+	return true
+}
+
+// CancelOrdersByTag cancels every working order carrying all of the tags.
+// An empty tag set matches nothing rather than every order.
+func (m *Manager) CancelOrdersByTag(ctx context.Context, tags map[string]string) *BulkCancelResult {
+	result := &BulkCancelResult{
+		Cancelled: make([]string, 0),
+		Failed:    make([]OrderCancelFailure, 0),
+	}
+	if len(tags) == 0 {
+		return result
+	}
+
+	m.mu.RLock()
+	ids := make([]string, 0)
+	for id, order := range m.orders {
+		switch order.Status {
+		case OrderStatusPending, OrderStatusSubmitted, OrderStatusPartial:
+			if matchesTags(order, tags) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	m.mu.RUnlock()
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if err := m.CancelOrder(ctx, id); err != nil {
+			result.Failed = append(result.Failed, OrderCancelFailure{OrderID: id, Error: err.Error()})
+			continue
+		}
+		result.Cancelled = append(result.Cancelled, id)
+	}
+	return result
+}
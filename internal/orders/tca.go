@@ -0,0 +1,222 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Benchmark selects the reference price slippage is measured against
+type Benchmark string
+
+const (
+	// BenchmarkArrival measures against the market price when the order was submitted
+	BenchmarkArrival Benchmark = "arrival"
+	// BenchmarkVWAP measures against the market VWAP over the order's lifetime
+	BenchmarkVWAP Benchmark = "vwap"
+	// BenchmarkClose measures against the closing price of the period
+	BenchmarkClose Benchmark = "close"
+)
+
+// Execution quality errors
+var (
+	ErrUnknownBenchmark      = errors.New("unknown TCA benchmark")
+	ErrMissingBenchmarkPrice = errors.New("benchmark price not available")
+	ErrNoExecutions          = errors.New("no executions to analyze")
+	ErrOrderNotFound         = errors.New("order not found")
+)
+
+// TCAConfig holds the assumptions used by execution quality reports
+type TCAConfig struct {
+	Benchmark Benchmark `json:"benchmark" yaml:"benchmark"`
+	// AssumedFeeBps is charged when executions carry no reported commission
+	AssumedFeeBps decimal.Decimal `json:"assumed_fee_bps" yaml:"assumedFeeBps"`
+}
+
+// DefaultTCAConfig returns default execution quality assumptions
+func DefaultTCAConfig() TCAConfig {
+	return TCAConfig{
+		Benchmark:     BenchmarkArrival,
+		AssumedFeeBps: decimal.Zero,
+	}
+}
+
+// BenchmarkPrices holds the candidate reference prices for an order
+type BenchmarkPrices struct {
+	Arrival decimal.Decimal `json:"arrival"`
+	VWAP    decimal.Decimal `json:"vwap"`
+	Close   decimal.Decimal `json:"close"`
+}
+
+// Price returns the reference price for the given benchmark
+func (p BenchmarkPrices) Price(benchmark Benchmark) (decimal.Decimal, error) {
+	var price decimal.Decimal
+	switch benchmark {
+	case BenchmarkArrival:
+		price = p.Arrival
+	case BenchmarkVWAP:
+		price = p.VWAP
+	case BenchmarkClose:
+		price = p.Close
+	default:
+		return decimal.Zero, fmt.Errorf("%w: %q", ErrUnknownBenchmark, benchmark)
+	}
+
+	if !price.IsPositive() {
+		return decimal.Zero, fmt.Errorf("%w: %s", ErrMissingBenchmarkPrice, benchmark)
+	}
+	return price, nil
+}
+
+// ExecutionQualityReport summarises the cost of an order's fills against a benchmark.
+// Positive slippage is a cost: paying above the benchmark on a buy or receiving
+// below it on a sell.
+type ExecutionQualityReport struct {
+	OrderID        string          `json:"order_id"`
+	Symbol         string          `json:"symbol"`
+	Side           OrderSide       `json:"side"`
+	Benchmark      Benchmark       `json:"benchmark"`
+	BenchmarkPrice decimal.Decimal `json:"benchmark_price"`
+	FilledQty      decimal.Decimal `json:"filled_qty"`
+	AvgFillPrice   decimal.Decimal `json:"avg_fill_price"`
+	Notional       decimal.Decimal `json:"notional"`
+	SlippageBps    decimal.Decimal `json:"slippage_bps"`
+	SlippageCost   decimal.Decimal `json:"slippage_cost"`
+	Fees           decimal.Decimal `json:"fees"`
+	FeeBps         decimal.Decimal `json:"fee_bps"`
+	FeesAssumed    bool            `json:"fees_assumed"`
+	TotalCostBps   decimal.Decimal `json:"total_cost_bps"`
+}
+
+var bpsMultiplier = decimal.NewFromInt(10000)
+
+// AnalyzeExecutionQuality measures the fills of an order against the configured benchmark
+func AnalyzeExecutionQuality(order *Order, executions []*Execution, prices BenchmarkPrices, config TCAConfig) (*ExecutionQualityReport, error) {
+	if order == nil {
+		return nil, fmt.Errorf("order cannot be nil")
+	}
+
+	benchmarkPrice, err := prices.Price(config.Benchmark)
+	if err != nil {
+		return nil, err
+	}
+
+	filledQty := decimal.Zero
+	notional := decimal.Zero
+	fees := decimal.Zero
+	for _, execution := range executions {
+		filledQty = filledQty.Add(execution.Quantity)
+		notional = notional.Add(execution.Quantity.Mul(execution.Price))
+		fees = fees.Add(execution.Commission)
+	}
+	if !filledQty.IsPositive() {
+		return nil, fmt.Errorf("%w: order %s", ErrNoExecutions, order.ID)
+	}
+
+	avgFillPrice := notional.Div(filledQty)
+
+	// Signed so that worse-than-benchmark fills are a positive cost
+	diff := avgFillPrice.Sub(benchmarkPrice)
+	if order.Side == OrderSideSell {
+		diff = diff.Neg()
+	}
+	slippageBps := diff.Div(benchmarkPrice).Mul(bpsMultiplier)
+
+	feesAssumed := false
+	if fees.IsZero() && config.AssumedFeeBps.IsPositive() {
+		fees = notional.Mul(config.AssumedFeeBps).Div(bpsMultiplier)
+		feesAssumed = true
+	}
+	feeBps := decimal.Zero
+	if notional.IsPositive() {
+		feeBps = fees.Div(notional).Mul(bpsMultiplier)
+	}
+
+	return &ExecutionQualityReport{
+		OrderID:        order.ID,
+		Symbol:         order.Symbol,
+		Side:           order.Side,
+		Benchmark:      config.Benchmark,
+		BenchmarkPrice: benchmarkPrice,
+		FilledQty:      filledQty,
+		AvgFillPrice:   avgFillPrice,
+		Notional:       notional,
+		SlippageBps:    slippageBps,
+		SlippageCost:   diff.Mul(filledQty),
+		Fees:           fees,
+		FeeBps:         feeBps,
+		FeesAssumed:    feesAssumed,
+		TotalCostBps:   slippageBps.Add(feeBps),
+	}, nil
+}
+
+// ExecutionQuality reports the execution quality of an order using the
+// manager's TCA assumptions. The arrival benchmark is the book's mid price
+// when the order was submitted and the close benchmark its mid price when the
+// order reached a terminal state, or now for a working order. Live feeds carry
+// no trade prices, so the VWAP benchmark weights the book mid sampled at each
+// of the order's fills by the fill's quantity.
+func (m *Manager) ExecutionQuality(orderID string) (*ExecutionQualityReport, error) {
+	m.mu.RLock()
+	order, exists := m.orders[orderID]
+	if !exists {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, orderID)
+	}
+	orderCopy := *order
+	executions := append([]*Execution(nil), m.executions[orderID]...)
+	config := m.config.TCA
+	m.mu.RUnlock()
+
+	prices := BenchmarkPrices{
+		Arrival: orderCopy.ArrivalPrice,
+		VWAP:    sampledVWAP(executions),
+		Close:   orderCopy.ClosePrice,
+	}
+	if !orderCopy.Status.terminal() {
+		prices.Close = m.midPrice(orderCopy.Exchange, orderCopy.Symbol)
+	}
+	return AnalyzeExecutionQuality(&orderCopy, executions, prices, config)
+}
+
+// sampledVWAP returns the book mid sampled at each execution weighted by its
+// quantity, or zero when no execution has a sample
+func sampledVWAP(executions []*Execution) decimal.Decimal {
+	quantity := decimal.Zero
+	notional := decimal.Zero
+	for _, execution := range executions {
+		if !execution.MidPrice.IsPositive() {
+			continue
+		}
+		quantity = quantity.Add(execution.Quantity)
+		notional = notional.Add(execution.Quantity.Mul(execution.MidPrice))
+	}
+	if !quantity.IsPositive() {
+		return decimal.Zero
+	}
+	return notional.Div(quantity)
+}
+
+// midPrice returns the mid price of an exchange's book for a symbol, or zero
+// without a two-sided book
+func (m *Manager) midPrice(exchange, symbol string) decimal.Decimal {
+	m.mu.RLock()
+	books := m.books
+	m.mu.RUnlock()
+	return bookMid(books, exchange, symbol)
+}
+
+// bookMid returns the mid price of an exchange's book for a symbol, or zero
+// without a two-sided book. Callers holding m.mu pass m.books directly.
+func bookMid(books OrderBookProvider, exchange, symbol string) decimal.Decimal {
+	if books == nil {
+		return decimal.Zero
+	}
+
+	bid, ask, ok := books.BestQuote(exchange, symbol)
+	if !ok || bid <= 0 || ask <= 0 {
+		return decimal.Zero
+	}
+	return decimal.NewFromFloat((bid + ask) / 2)
+}
@@ -0,0 +1,167 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/normalizer"
+	"velocimex/internal/orderbook"
+)
+
+func tcaFills() []*Execution {
+	return []*Execution{
+		{OrderID: "order-1", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100)},
+		{OrderID: "order-1", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(102)},
+	}
+}
+
+// TestExecutionQualityBenchmarks tests that the same fills yield different slippage per benchmark
+func TestExecutionQualityBenchmarks(t *testing.T) {
+	order := &Order{ID: "order-1", Symbol: "BTC/USD", Side: OrderSideBuy}
+	prices := BenchmarkPrices{
+		Arrival: decimal.NewFromInt(100),
+		VWAP:    decimal.NewFromInt(101),
+		Close:   decimal.NewFromInt(102),
+	}
+
+	arrival, err := AnalyzeExecutionQuality(order, tcaFills(), prices, TCAConfig{Benchmark: BenchmarkArrival})
+	require.NoError(t, err)
+	vwap, err := AnalyzeExecutionQuality(order, tcaFills(), prices, TCAConfig{Benchmark: BenchmarkVWAP})
+	require.NoError(t, err)
+
+	assert.True(t, arrival.AvgFillPrice.Equal(decimal.NewFromInt(101)))
+	assert.True(t, vwap.AvgFillPrice.Equal(arrival.AvgFillPrice))
+
+	// Average fill 101 vs arrival 100 is 100 bps of cost; vs VWAP 101 it is flat
+	assert.True(t, arrival.SlippageBps.Equal(decimal.NewFromInt(100)), "arrival slippage %s", arrival.SlippageBps)
+	assert.True(t, vwap.SlippageBps.IsZero(), "vwap slippage %s", vwap.SlippageBps)
+	assert.False(t, arrival.SlippageBps.Equal(vwap.SlippageBps))
+	assert.True(t, arrival.SlippageCost.Equal(decimal.NewFromInt(2)))
+	assert.Equal(t, BenchmarkArrival, arrival.Benchmark)
+	assert.Equal(t, BenchmarkVWAP, vwap.Benchmark)
+
+	// A sell filled below the close benchmark is also a cost
+	sell := &Order{ID: "order-1", Symbol: "BTC/USD", Side: OrderSideSell}
+	closing, err := AnalyzeExecutionQuality(sell, tcaFills(), prices, TCAConfig{Benchmark: BenchmarkClose})
+	require.NoError(t, err)
+	assert.True(t, closing.SlippageBps.GreaterThan(decimal.Zero))
+}
+
+// TestExecutionQualityFees tests reported and assumed fees
+func TestExecutionQualityFees(t *testing.T) {
+	order := &Order{ID: "order-1", Side: OrderSideBuy}
+	prices := BenchmarkPrices{Arrival: decimal.NewFromInt(101)}
+	config := TCAConfig{Benchmark: BenchmarkArrival, AssumedFeeBps: decimal.NewFromInt(10)}
+
+	assumed, err := AnalyzeExecutionQuality(order, tcaFills(), prices, config)
+	require.NoError(t, err)
+	assert.True(t, assumed.FeesAssumed)
+	assert.True(t, assumed.FeeBps.Equal(decimal.NewFromInt(10)))
+	assert.True(t, assumed.TotalCostBps.Equal(decimal.NewFromInt(10)))
+
+	fills := tcaFills()
+	fills[0].Commission = decimal.NewFromFloat(0.202)
+	reported, err := AnalyzeExecutionQuality(order, fills, prices, config)
+	require.NoError(t, err)
+	assert.False(t, reported.FeesAssumed)
+	assert.True(t, reported.FeeBps.Equal(decimal.NewFromInt(10)), "fee bps %s", reported.FeeBps)
+}
+
+// TestExecutionQualityErrors tests missing benchmarks and fills
+func TestExecutionQualityErrors(t *testing.T) {
+	order := &Order{ID: "order-1", Side: OrderSideBuy}
+	prices := BenchmarkPrices{Arrival: decimal.NewFromInt(100)}
+
+	_, err := AnalyzeExecutionQuality(order, tcaFills(), prices, TCAConfig{Benchmark: BenchmarkVWAP})
+	assert.True(t, errors.Is(err, ErrMissingBenchmarkPrice))
+
+	_, err = AnalyzeExecutionQuality(order, tcaFills(), prices, TCAConfig{Benchmark: "twap"})
+	assert.True(t, errors.Is(err, ErrUnknownBenchmark))
+
+	_, err = AnalyzeExecutionQuality(order, nil, prices, TCAConfig{Benchmark: BenchmarkArrival})
+	assert.True(t, errors.Is(err, ErrNoExecutions))
+}
+
+// TestManagerExecutionQuality tests that the manager measures recorded fills
+// against the book's mid price at submission, at each fill and when the order
+// finished
+func TestManagerExecutionQuality(t *testing.T) {
+	books := orderbook.NewManager()
+	books.UpdateOrderBook("mock_exchange", "BTC/USD",
+		[]normalizer.PriceLevel{{Price: 49990, Volume: 10}},
+		[]normalizer.PriceLevel{{Price: 50010, Volume: 10}},
+	)
+	newManager := func(benchmark Benchmark) *Manager {
+		config := DefaultManagerConfig()
+		config.TCA = TCAConfig{Benchmark: benchmark}
+		manager := NewManager(config, &MockSmartRouter{}, nil)
+		manager.SetOrderBooks(books)
+		require.NoError(t, manager.Start(context.Background()))
+		t.Cleanup(func() { manager.Stop(context.Background()) })
+		return manager
+	}
+	buy := &OrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: decimal.NewFromInt(1),
+		Price:    decimal.NewFromInt(50100),
+	}
+
+	arrivalManager := newManager(BenchmarkArrival)
+	order := fillOrder(t, arrivalManager, buy)
+	assert.True(t, order.ArrivalPrice.Equal(decimal.NewFromInt(50000)))
+
+	var report *ExecutionQualityReport
+	var err error
+	require.Eventually(t, func() bool {
+		report, err = arrivalManager.ExecutionQuality(order.ID)
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, BenchmarkArrival, report.Benchmark)
+	assert.True(t, report.BenchmarkPrice.Equal(decimal.NewFromInt(50000)))
+	assert.True(t, report.SlippageBps.Equal(decimal.NewFromInt(20)), "slippage %s", report.SlippageBps)
+
+	// The market has since moved up to the fill price
+	books.UpdateOrderBook("mock_exchange", "BTC/USD",
+		[]normalizer.PriceLevel{{Price: 50090, Volume: 10}},
+		[]normalizer.PriceLevel{{Price: 50110, Volume: 10}},
+	)
+	closeManager := newManager(BenchmarkClose)
+	order = fillOrder(t, closeManager, buy)
+	require.Eventually(t, func() bool {
+		report, err = closeManager.ExecutionQuality(order.ID)
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+	assert.True(t, report.BenchmarkPrice.Equal(decimal.NewFromInt(50100)))
+	assert.True(t, report.SlippageBps.IsZero(), "slippage %s", report.SlippageBps)
+
+	// The VWAP benchmark is the mid sampled at the fill
+	vwapManager := newManager(BenchmarkVWAP)
+	vwapOrder := fillOrder(t, vwapManager, buy)
+	require.Eventually(t, func() bool {
+		report, err = vwapManager.ExecutionQuality(vwapOrder.ID)
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+	assert.True(t, report.BenchmarkPrice.Equal(decimal.NewFromInt(50100)))
+
+	// Once the orders are done, later book moves change neither benchmark
+	books.UpdateOrderBook("mock_exchange", "BTC/USD",
+		[]normalizer.PriceLevel{{Price: 51990, Volume: 10}},
+		[]normalizer.PriceLevel{{Price: 52010, Volume: 10}},
+	)
+	report, err = closeManager.ExecutionQuality(order.ID)
+	require.NoError(t, err)
+	assert.True(t, report.BenchmarkPrice.Equal(decimal.NewFromInt(50100)))
+	report, err = vwapManager.ExecutionQuality(vwapOrder.ID)
+	require.NoError(t, err)
+	assert.True(t, report.BenchmarkPrice.Equal(decimal.NewFromInt(50100)))
+
+	_, err = closeManager.ExecutionQuality("missing")
+	assert.True(t, errors.Is(err, ErrOrderNotFound))
+}
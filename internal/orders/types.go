@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/shopspring/decimal"
+	"velocimex/internal/instruments"
 )
 
 // OrderStatus represents the current status of an order
@@ -18,8 +19,20 @@ const (
 	OrderStatusCancelled  OrderStatus = "CANCELLED"
 	OrderStatusRejected   OrderStatus = "REJECTED"
 	OrderStatusExpired    OrderStatus = "EXPIRED"
+	// OrderStatusPartialCancelled is a partially filled order whose unfilled
+	// remainder was cancelled; the filled part stands
+	OrderStatusPartialCancelled OrderStatus = "PARTIALLY_FILLED_CANCELLED"
 )
 
+// terminal reports whether an order in this status is done and can change no further
+func (s OrderStatus) terminal() bool {
+	switch s {
+	case OrderStatusFilled, OrderStatusCancelled, OrderStatusRejected, OrderStatusExpired, OrderStatusPartialCancelled:
+		return true
+	}
+	return false
+}
+
 // OrderSide represents the side of an order
 type OrderSide string
 
@@ -51,12 +64,27 @@ const (
 	TimeInForceGTX TimeInForce = "GTX" // Good Till Crossing
 )
 
+// PositionMode controls how executions on opposite sides are combined
+type PositionMode string
+
+const (
+	// PositionModeNetting keeps one position per symbol; opposite fills reduce it
+	PositionModeNetting PositionMode = "NETTING"
+	// PositionModeHedging keeps separate long and short positions per symbol
+	PositionModeHedging PositionMode = "HEDGING"
+)
+
+// PositionEffectTag is the order tag used in hedging mode to mark an order as
+// closing the opposite-side position instead of opening a new one
+const PositionEffectTag = "position_effect"
+
 // Order represents a trading order
 type Order struct {
 	ID           string          `json:"id"`
 	ClientID     string          `json:"client_id"`
 	Exchange     string          `json:"exchange"`
 	Symbol       string          `json:"symbol"`
+	NativeSymbol string          `json:"native_symbol,omitempty"`
 	Side         OrderSide       `json:"side"`
 	Type         OrderType       `json:"type"`
 	Quantity     decimal.Decimal `json:"quantity"`
@@ -70,6 +98,12 @@ type Order struct {
 	CreatedAt    time.Time       `json:"created_at"`
 	UpdatedAt    time.Time       `json:"updated_at"`
 	ExpiresAt    *time.Time      `json:"expires_at,omitempty"`
+	AcknowledgedAt *time.Time    `json:"acknowledged_at,omitempty"` // First update received from the exchange
+	CancelReason string          `json:"cancel_reason,omitempty"`   // Why the manager cancelled the order itself
+	ExpectedPrice  decimal.Decimal `json:"expected_price"`             // Price a market order was expected to fill at, for the slippage check
+	ArrivalPrice   decimal.Decimal `json:"arrival_price"`              // Mid price of the routed book at submission, the arrival benchmark of execution quality reports
+	ClosePrice     decimal.Decimal `json:"close_price"`                // Mid price of the book when the order reached a terminal state, the close benchmark
+	MaxSlippageBps float64       `json:"max_slippage_bps,omitempty"` // Slippage tolerance of a market order, in basis points
 	StrategyID   string          `json:"strategy_id,omitempty"`
 	StrategyName string          `json:"strategy_name,omitempty"`
 	Tags         map[string]string `json:"tags,omitempty"`
@@ -102,6 +136,18 @@ type Execution struct {
 	Commission decimal.Decimal `json:"commission"`
 	Timestamp time.Time       `json:"timestamp"`
 	TradeID   string          `json:"trade_id"`
+	MidPrice  decimal.Decimal `json:"mid_price"` // Book mid when the execution was recorded, sampled for the VWAP benchmark; zero without a book
+}
+
+// RealizedTrade is PnL realized by an execution, attributed to the order's strategy
+type RealizedTrade struct {
+	OrderID      string          `json:"order_id"`
+	StrategyID   string          `json:"strategy_id,omitempty"`
+	StrategyName string          `json:"strategy_name,omitempty"`
+	Symbol       string          `json:"symbol"`
+	Exchange     string          `json:"exchange"`
+	PnL          decimal.Decimal `json:"pnl"`
+	Timestamp    time.Time       `json:"timestamp"`
 }
 
 // Position represents a trading position
@@ -130,6 +176,7 @@ type OrderRequest struct {
 	Side        OrderSide              `json:"side"`
 	Type        OrderType              `json:"type"`
 	Quantity    decimal.Decimal        `json:"quantity"`
+	QuoteQuantity decimal.Decimal      `json:"quote_quantity,omitempty"` // Size in quote currency, converted to Quantity from the book at submission; set instead of Quantity
 	Price       decimal.Decimal        `json:"price,omitempty"`
 	StopPrice   decimal.Decimal        `json:"stop_price,omitempty"`
 	TimeInForce TimeInForce            `json:"time_in_force,omitempty"`
@@ -138,6 +185,8 @@ type OrderRequest struct {
 	StrategyName string                 `json:"strategy_name,omitempty"`
 	Tags         map[string]string      `json:"tags,omitempty"`
 	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	MaxLatency   time.Duration          `json:"max_latency,omitempty"` // Latency budget for smart routing; zero accepts any venue
+	MaxSlippageBps float64              `json:"max_slippage_bps,omitempty"` // Market orders are rejected rather than filled this far past the expected price; zero uses the manager default
 }
 
 // RoutingDecision represents a routing decision made by the smart router
@@ -161,6 +210,47 @@ type SmartRouter interface {
 	GetBestPrice(ctx context.Context, symbol string, side OrderSide, quantity decimal.Decimal) (*RoutingDecision, error)
 }
 
+// SymbolTranslator maps canonical symbols to exchange-native symbols
+type SymbolTranslator interface {
+	ToNative(exchange, canonical string) (string, error)
+}
+
+// InstrumentProvider looks up contract specifications by canonical symbol
+type InstrumentProvider interface {
+	Get(symbol string) (*instruments.Instrument, error)
+}
+
+// BookAgeProvider reports how long ago an exchange's book for a canonical symbol was updated
+type BookAgeProvider interface {
+	BookAge(exchange, symbol string) (time.Duration, bool)
+}
+
+// QuoteProvider reports the best bid and ask of an exchange's book for a canonical symbol
+type QuoteProvider interface {
+	BestQuote(exchange, symbol string) (bid, ask float64, ok bool)
+}
+
+// OrderBookProvider exposes the order book state orders are checked against
+type OrderBookProvider interface {
+	BookAgeProvider
+	QuoteProvider
+}
+
+// MarkPriceProvider reports the price a canonical symbol's positions are marked to market at
+type MarkPriceProvider interface {
+	MarkPrice(symbol string) (float64, bool)
+}
+
+// SymbolHaltProvider reports whether trading on a canonical symbol is halted, e.g. by a circuit breaker
+type SymbolHaltProvider interface {
+	SymbolHalted(symbol string) (bool, string)
+}
+
+// LatencySource supplies artificial latency to add before orders are submitted
+type LatencySource interface {
+	OrderLatency() time.Duration
+}
+
 // OrderManager defines the interface for order management
 type OrderManager interface {
 	SubmitOrder(ctx context.Context, req *OrderRequest) (*Order, error)
@@ -0,0 +1,125 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"velocimex/internal/instruments"
+)
+
+// Order limit validation errors
+var (
+	ErrBelowMinQuantity = errors.New("quantity below symbol minimum")
+	ErrAboveMaxQuantity = errors.New("quantity above symbol maximum")
+	ErrBelowMinNotional = errors.New("notional below symbol minimum")
+	ErrAboveMaxNotional = errors.New("notional above symbol maximum")
+	ErrMaxOpenOrders    = errors.New("maximum open orders reached")
+	ErrTradingHalted    = errors.New("trading halted")
+	ErrSymbolHalted     = errors.New("symbol trading halted")
+	ErrInvalidTickSize  = errors.New("price not a multiple of tick size")
+	ErrInvalidLotSize   = errors.New("quantity not a multiple of lot size")
+	ErrStaleOrderBook   = errors.New("order book is stale")
+	ErrDailyOrderLimit  = errors.New("daily order limit reached")
+	ErrNoQuotePrice     = errors.New("no book price to size quote quantity")
+	ErrMaxOrderValue    = errors.New("order value above maximum")
+	ErrNoNotionalPrice  = errors.New("no price to check order notional")
+	ErrInvalidOrder     = errors.New("invalid order request")
+	ErrNotCancellable   = errors.New("order cannot be cancelled")
+)
+
+// SymbolLimits holds per-symbol order size bounds. A zero value disables the bound.
+type SymbolLimits struct {
+	MinQuantity decimal.Decimal `json:"min_quantity" yaml:"minQuantity"`
+	MaxQuantity decimal.Decimal `json:"max_quantity" yaml:"maxQuantity"`
+	MinNotional decimal.Decimal `json:"min_notional" yaml:"minNotional"`
+	MaxNotional decimal.Decimal `json:"max_notional" yaml:"maxNotional"`
+}
+
+// Validate checks an order request against the limits. Notional bounds use
+// price, the price the order is expected to fill at; an order that cannot be
+// priced is rejected when the symbol has one.
+func (l SymbolLimits) Validate(req *OrderRequest, price decimal.Decimal) error {
+	if l.MinQuantity.IsPositive() && req.Quantity.LessThan(l.MinQuantity) {
+		return fmt.Errorf("%w: %s < %s for %s", ErrBelowMinQuantity, req.Quantity, l.MinQuantity, req.Symbol)
+	}
+	if l.MaxQuantity.IsPositive() && req.Quantity.GreaterThan(l.MaxQuantity) {
+		return fmt.Errorf("%w: %s > %s for %s", ErrAboveMaxQuantity, req.Quantity, l.MaxQuantity, req.Symbol)
+	}
+
+	if !l.MinNotional.IsPositive() && !l.MaxNotional.IsPositive() {
+		return nil
+	}
+	if !price.IsPositive() {
+		return fmt.Errorf("%w: %s %s", ErrNoNotionalPrice, req.Side, req.Symbol)
+	}
+
+	notional := req.Quantity.Mul(price)
+	if l.MinNotional.IsPositive() && notional.LessThan(l.MinNotional) {
+		return fmt.Errorf("%w: %s < %s for %s", ErrBelowMinNotional, notional, l.MinNotional, req.Symbol)
+	}
+	if l.MaxNotional.IsPositive() && notional.GreaterThan(l.MaxNotional) {
+		return fmt.Errorf("%w: %s > %s for %s", ErrAboveMaxNotional, notional, l.MaxNotional, req.Symbol)
+	}
+
+	return nil
+}
+
+// validateInstrument checks an order request against the instrument's
+// contract specs. The tick size applies to the order's own price, if it has
+// one; the minimum notional uses price, the expected fill price, and rejects
+// an order that cannot be priced.
+func validateInstrument(instrument *instruments.Instrument, req *OrderRequest, price decimal.Decimal) error {
+	if !instrument.ValidQuantity(req.Quantity) {
+		return fmt.Errorf("%w: %s for %s (lot %s)", ErrInvalidLotSize, req.Quantity, req.Symbol, instrument.LotSize)
+	}
+
+	if req.Price.IsPositive() && !instrument.ValidPrice(req.Price) {
+		return fmt.Errorf("%w: %s for %s (tick %s)", ErrInvalidTickSize, req.Price, req.Symbol, instrument.TickSize)
+	}
+
+	if !instrument.MinNotional.IsPositive() {
+		return nil
+	}
+	if !price.IsPositive() {
+		return fmt.Errorf("%w: %s %s", ErrNoNotionalPrice, req.Side, req.Symbol)
+	}
+	notional := instrument.Notional(price, req.Quantity)
+	if notional.LessThan(instrument.MinNotional) {
+		return fmt.Errorf("%w: %s < %s for %s", ErrBelowMinNotional, notional, instrument.MinNotional, req.Symbol)
+	}
+
+	return nil
+}
+
+// checkOrderValue rejects an order whose notional exceeds the configured
+// maximum order value, whatever the symbol's own limits. Orders without a
+// price are valued at the best opposite quote on the exchange they are routed
+// to, and rejected when there is none, so the guard never lets an order
+// through unchecked.
+func (m *Manager) checkOrderValue(req *OrderRequest, exchange string) error {
+	ceiling := m.config.MaxOrderValue
+	if !ceiling.IsPositive() {
+		return nil
+	}
+
+	price := m.expectedFillPrice(req, exchange)
+	if !price.IsPositive() {
+		return fmt.Errorf("%w: cannot value %s %s on %s without a price", ErrMaxOrderValue, req.Side, req.Symbol, exchange)
+	}
+
+	notional := req.Quantity.Mul(price)
+	m.mu.RLock()
+	instrumentSpecs := m.instruments
+	m.mu.RUnlock()
+	if instrumentSpecs != nil {
+		if instrument, err := instrumentSpecs.Get(req.Symbol); err == nil {
+			notional = instrument.Notional(price, req.Quantity)
+		}
+	}
+
+	if notional.GreaterThan(ceiling) {
+		return fmt.Errorf("%w: %s > %s for %s", ErrMaxOrderValue, notional, ceiling, req.Symbol)
+	}
+	return nil
+}
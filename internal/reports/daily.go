@@ -0,0 +1,398 @@
+// Package reports builds and sends scheduled trading summaries.
+package reports
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"velocimex/internal/alerts"
+	"velocimex/internal/orders"
+	"velocimex/internal/risk"
+)
+
+// Config configures the daily summary report
+type Config struct {
+	Enabled   bool        `yaml:"enabled"`
+	Time      string      `yaml:"time"`      // Time of day to send the report, "HH:MM"
+	Timezone  string      `yaml:"timezone"`  // IANA zone for Time; empty uses UTC
+	TopAlerts int         `yaml:"topAlerts"` // Unresolved alerts to include, most severe first
+	Channel   string      `yaml:"channel"`   // "email" or "console"
+	Email     EmailConfig `yaml:"email"`
+}
+
+// EmailConfig holds the SMTP settings of the report email channel
+type EmailConfig struct {
+	SMTPHost string   `yaml:"smtpHost"`
+	SMTPPort int      `yaml:"smtpPort"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// DefaultConfig returns default report configuration
+func DefaultConfig() Config {
+	return Config{
+		Enabled:   false,
+		Time:      "18:00",
+		TopAlerts: 5,
+		Channel:   "console",
+	}
+}
+
+// NewChannel creates the alert channel the report is sent through
+func NewChannel(config Config) (alerts.AlertChannel, error) {
+	switch config.Channel {
+	case "", "console":
+		return alerts.NewConsoleChannel("daily-report"), nil
+	case "email":
+		email := config.Email
+		if email.SMTPHost == "" || len(email.To) == 0 {
+			return nil, fmt.Errorf("report email channel requires an SMTP host and recipients")
+		}
+		return alerts.NewEmailChannel("daily-report", email.SMTPHost, email.SMTPPort, email.Username, email.Password, email.From, email.To), nil
+	default:
+		return nil, fmt.Errorf("unknown report channel: %s", config.Channel)
+	}
+}
+
+// PositionSource lists the positions currently held
+type PositionSource interface {
+	GetPositions(ctx context.Context, filters map[string]interface{}) ([]*orders.Position, error)
+}
+
+// RiskSource provides the current risk metrics
+type RiskSource interface {
+	GetRiskMetrics() *risk.RiskMetrics
+}
+
+// AlertSource lists alerts
+type AlertSource interface {
+	GetAlerts(filters map[string]interface{}) ([]*alerts.Alert, error)
+}
+
+// DailyReport is a summary of one trading day
+type DailyReport struct {
+	Date           string                     `json:"date"`
+	GeneratedAt    time.Time                  `json:"generated_at"`
+	RealizedPnL    decimal.Decimal            `json:"realized_pnl"`
+	RealizedTrades int                        `json:"realized_trades"`
+	PnLByStrategy  map[string]decimal.Decimal `json:"pnl_by_strategy"`
+	OpenPositions  []*orders.Position         `json:"open_positions"`
+	Risk           *risk.RiskMetrics          `json:"risk,omitempty"`
+	TopAlerts      []*alerts.Alert            `json:"top_alerts"`
+}
+
+// String renders the report as plain text, one section per heading
+func (r *DailyReport) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Daily report for %s\n", r.Date)
+
+	fmt.Fprintf(&b, "\nRealized PnL\n")
+	fmt.Fprintf(&b, "  Total: %s over %d trades\n", r.RealizedPnL.StringFixed(2), r.RealizedTrades)
+	strategies := make([]string, 0, len(r.PnLByStrategy))
+	for name := range r.PnLByStrategy {
+		strategies = append(strategies, name)
+	}
+	sort.Strings(strategies)
+	for _, name := range strategies {
+		fmt.Fprintf(&b, "  %s: %s\n", name, r.PnLByStrategy[name].StringFixed(2))
+	}
+
+	fmt.Fprintf(&b, "\nOpen Positions\n")
+	if len(r.OpenPositions) == 0 {
+		fmt.Fprintf(&b, "  None\n")
+	}
+	for _, position := range r.OpenPositions {
+		fmt.Fprintf(&b, "  %s %s %s %s @ %s (unrealized %s)\n", position.Exchange, position.Symbol, position.Side,
+			position.Quantity, position.EntryPrice, position.UnrealizedPNL.StringFixed(2))
+	}
+
+	fmt.Fprintf(&b, "\nRisk Metrics\n")
+	if r.Risk == nil {
+		fmt.Fprintf(&b, "  Unavailable\n")
+	} else {
+		fmt.Fprintf(&b, "  Portfolio value: %s\n", r.Risk.PortfolioValue.StringFixed(2))
+		fmt.Fprintf(&b, "  Exposure: %s\n", r.Risk.TotalExposure.StringFixed(2))
+		fmt.Fprintf(&b, "  Leverage: %s\n", r.Risk.Leverage.StringFixed(2))
+		fmt.Fprintf(&b, "  VaR 95%%: %s, CVaR 95%%: %s\n", r.Risk.VaR95.StringFixed(2), r.Risk.CVaR95.StringFixed(2))
+	}
+
+	fmt.Fprintf(&b, "\nTop Alerts\n")
+	if len(r.TopAlerts) == 0 {
+		fmt.Fprintf(&b, "  None\n")
+	}
+	for _, alert := range r.TopAlerts {
+		fmt.Fprintf(&b, "  [%s] %s - %s\n", alert.Severity, alert.Title, alert.Message)
+	}
+
+	return b.String()
+}
+
+// Scheduler sends a daily report at the configured time of day. Realized PnL
+// is accumulated from recorded trades and reset after every report.
+type Scheduler struct {
+	config   Config
+	location *time.Location
+	hour     int
+	minute   int
+	channel  alerts.AlertChannel
+
+	// Overridden in tests to drive the schedule
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+
+	mu             sync.Mutex
+	positions      PositionSource
+	risk           RiskSource
+	alerts         AlertSource
+	realizedPnL    decimal.Decimal
+	realizedTrades int
+	pnlByStrategy  map[string]decimal.Decimal
+	cancel         context.CancelFunc
+	wg             sync.WaitGroup
+}
+
+// NewScheduler creates a report scheduler that sends through the given channel
+func NewScheduler(config Config, channel alerts.AlertChannel) (*Scheduler, error) {
+	if channel == nil {
+		return nil, fmt.Errorf("report scheduler requires a channel")
+	}
+
+	var hour, minute int
+	if _, err := fmt.Sscanf(config.Time, "%d:%d", &hour, &minute); err != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return nil, fmt.Errorf("invalid report time %q, want HH:MM", config.Time)
+	}
+
+	location := time.UTC
+	if config.Timezone != "" {
+		loc, err := time.LoadLocation(config.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid report timezone: %w", err)
+		}
+		location = loc
+	}
+
+	return &Scheduler{
+		config:        config,
+		location:      location,
+		hour:          hour,
+		minute:        minute,
+		channel:       channel,
+		now:           time.Now,
+		after:         time.After,
+		pnlByStrategy: make(map[string]decimal.Decimal),
+	}, nil
+}
+
+// SetSources sets where the report reads positions, risk metrics and alerts from; any may be nil
+func (s *Scheduler) SetSources(positions PositionSource, risk RiskSource, alerts AlertSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.positions = positions
+	s.risk = risk
+	s.alerts = alerts
+}
+
+// RecordRealizedTrade adds a realized trade to the current day's PnL
+func (s *Scheduler) RecordRealizedTrade(trade orders.RealizedTrade) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.realizedPnL = s.realizedPnL.Add(trade.PnL)
+	s.realizedTrades++
+	name := trade.StrategyName
+	if name == "" {
+		name = "manual"
+	}
+	s.pnlByStrategy[name] = s.pnlByStrategy[name].Add(trade.PnL)
+}
+
+// Start begins sending a report every day at the configured time
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return fmt.Errorf("report scheduler already running")
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.wg.Add(1)
+	go s.run(ctx)
+
+	log.Printf("Daily report scheduled at %02d:%02d %s", s.hour, s.minute, s.location)
+	return nil
+}
+
+// Stop stops the scheduler
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		s.wg.Wait()
+	}
+}
+
+// run waits for each scheduled time and sends the report
+func (s *Scheduler) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	for {
+		now := s.now()
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.after(s.nextRun(now).Sub(now)):
+			if err := s.Dispatch(); err != nil {
+				log.Printf("Failed to send daily report: %v", err)
+			}
+		}
+	}
+}
+
+// nextRun returns the first scheduled time strictly after now
+func (s *Scheduler) nextRun(now time.Time) time.Time {
+	local := now.In(s.location)
+	next := time.Date(local.Year(), local.Month(), local.Day(), s.hour, s.minute, 0, 0, s.location)
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// Generate builds a report of the current day without resetting it
+func (s *Scheduler) Generate() *DailyReport {
+	now := s.now()
+
+	s.mu.Lock()
+	report := &DailyReport{
+		Date:           now.In(s.location).Format("2006-01-02"),
+		GeneratedAt:    now,
+		RealizedPnL:    s.realizedPnL,
+		RealizedTrades: s.realizedTrades,
+		PnLByStrategy:  make(map[string]decimal.Decimal, len(s.pnlByStrategy)),
+		OpenPositions:  make([]*orders.Position, 0),
+		TopAlerts:      make([]*alerts.Alert, 0),
+	}
+	for name, pnl := range s.pnlByStrategy {
+		report.PnLByStrategy[name] = pnl
+	}
+	positionSource, riskSource, alertSource := s.positions, s.risk, s.alerts
+	s.mu.Unlock()
+
+	if positionSource != nil {
+		positions, err := positionSource.GetPositions(context.Background(), nil)
+		if err != nil {
+			log.Printf("Daily report: failed to list positions: %v", err)
+		}
+		for _, position := range positions {
+			if !position.Quantity.IsZero() {
+				report.OpenPositions = append(report.OpenPositions, position)
+			}
+		}
+		sort.Slice(report.OpenPositions, func(i, j int) bool {
+			a, b := report.OpenPositions[i], report.OpenPositions[j]
+			if a.Exchange != b.Exchange {
+				return a.Exchange < b.Exchange
+			}
+			return a.Symbol < b.Symbol
+		})
+	}
+
+	if riskSource != nil {
+		if metrics := riskSource.GetRiskMetrics(); metrics != nil {
+			snapshot := *metrics
+			report.Risk = &snapshot
+		}
+	}
+
+	if alertSource != nil {
+		report.TopAlerts = s.topAlerts(alertSource)
+	}
+
+	return report
+}
+
+// topAlerts returns the most severe unresolved alerts, newest first within a severity
+func (s *Scheduler) topAlerts(source AlertSource) []*alerts.Alert {
+	open, err := source.GetAlerts(map[string]interface{}{"resolved": false})
+	if err != nil {
+		log.Printf("Daily report: failed to list alerts: %v", err)
+		return make([]*alerts.Alert, 0)
+	}
+
+	sort.SliceStable(open, func(i, j int) bool {
+		if severityRank(open[i].Severity) != severityRank(open[j].Severity) {
+			return severityRank(open[i].Severity) > severityRank(open[j].Severity)
+		}
+		return open[i].Timestamp.After(open[j].Timestamp)
+	})
+	if s.config.TopAlerts >= 0 && len(open) > s.config.TopAlerts {
+		open = open[:s.config.TopAlerts]
+	}
+	return open
+}
+
+// severityRank orders alert severities from least to most severe
+func severityRank(severity alerts.AlertSeverity) int {
+	switch severity {
+	case alerts.SeverityCritical:
+		return 4
+	case alerts.SeverityHigh:
+		return 3
+	case alerts.SeverityMedium:
+		return 2
+	case alerts.SeverityLow:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Dispatch generates the report, sends it and starts a new reporting day
+func (s *Scheduler) Dispatch() error {
+	report := s.Generate()
+
+	alert := &alerts.Alert{
+		ID:        uuid.New().String(),
+		Type:      alerts.AlertTypeReport,
+		Severity:  alerts.SeverityLow,
+		Title:     fmt.Sprintf("Daily report %s", report.Date),
+		Message:   report.String(),
+		Data:      report,
+		Channels:  []string{s.channel.Name()},
+		Timestamp: report.GeneratedAt,
+		CreatedAt: report.GeneratedAt,
+		Status:    alerts.AlertStatusActive,
+	}
+	if err := s.channel.Send(alert); err != nil {
+		return fmt.Errorf("send daily report via %s: %w", s.channel.Name(), err)
+	}
+
+	// Trades recorded while the report was being built carry over to the next day
+	s.mu.Lock()
+	s.realizedPnL = s.realizedPnL.Sub(report.RealizedPnL)
+	s.realizedTrades -= report.RealizedTrades
+	for name, pnl := range report.PnLByStrategy {
+		s.pnlByStrategy[name] = s.pnlByStrategy[name].Sub(pnl)
+		if s.pnlByStrategy[name].IsZero() {
+			delete(s.pnlByStrategy, name)
+		}
+	}
+	s.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,223 @@
+package reports
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/alerts"
+	"velocimex/internal/orders"
+	"velocimex/internal/risk"
+)
+
+// recordingChannel collects the reports sent through it
+type recordingChannel struct {
+	sent chan *alerts.Alert
+}
+
+func newRecordingChannel() *recordingChannel {
+	return &recordingChannel{sent: make(chan *alerts.Alert, 10)}
+}
+
+func (c *recordingChannel) Send(alert *alerts.Alert) error {
+	c.sent <- alert
+	return nil
+}
+
+func (c *recordingChannel) Name() string { return "recording" }
+func (c *recordingChannel) Type() string { return "test" }
+
+type staticPositions []*orders.Position
+
+func (p staticPositions) GetPositions(ctx context.Context, filters map[string]interface{}) ([]*orders.Position, error) {
+	return p, nil
+}
+
+type staticRisk risk.RiskMetrics
+
+func (r *staticRisk) GetRiskMetrics() *risk.RiskMetrics {
+	metrics := risk.RiskMetrics(*r)
+	return &metrics
+}
+
+type staticAlerts []*alerts.Alert
+
+func (a staticAlerts) GetAlerts(filters map[string]interface{}) ([]*alerts.Alert, error) {
+	var matched []*alerts.Alert
+	for _, alert := range a {
+		if resolved, ok := filters["resolved"]; ok && alert.Resolved != resolved.(bool) {
+			continue
+		}
+		matched = append(matched, alert)
+	}
+	return matched, nil
+}
+
+func newTestScheduler(t *testing.T, now time.Time) (*Scheduler, *recordingChannel) {
+	t.Helper()
+
+	config := DefaultConfig()
+	config.TopAlerts = 2
+	channel := newRecordingChannel()
+	scheduler, err := NewScheduler(config, channel)
+	require.NoError(t, err)
+	scheduler.now = func() time.Time { return now }
+
+	base := now.Add(-time.Hour)
+	scheduler.SetSources(
+		staticPositions{
+			{Exchange: "binance", Symbol: "BTC/USD", Side: orders.OrderSideBuy, Quantity: decimal.NewFromInt(2), EntryPrice: decimal.NewFromInt(50000)},
+			{Exchange: "binance", Symbol: "ETH/USD", Side: orders.OrderSideBuy, Quantity: decimal.Zero, EntryPrice: decimal.NewFromInt(3000)},
+		},
+		&staticRisk{PortfolioValue: decimal.NewFromInt(120000), VaR95: decimal.NewFromInt(2400)},
+		staticAlerts{
+			{Title: "Feed lag", Severity: alerts.SeverityMedium, Timestamp: base},
+			{Title: "Drawdown", Severity: alerts.SeverityCritical, Timestamp: base},
+			{Title: "Spread wide", Severity: alerts.SeverityLow, Timestamp: base.Add(time.Minute)},
+			{Title: "Old outage", Severity: alerts.SeverityCritical, Timestamp: base, Resolved: true},
+		},
+	)
+	return scheduler, channel
+}
+
+// TestDailyReportSections tests that the report covers PnL, positions, risk and alerts
+func TestDailyReportSections(t *testing.T) {
+	now := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+	scheduler, _ := newTestScheduler(t, now)
+	scheduler.RecordRealizedTrade(orders.RealizedTrade{StrategyName: "arb", PnL: decimal.NewFromInt(150)})
+	scheduler.RecordRealizedTrade(orders.RealizedTrade{StrategyName: "arb", PnL: decimal.NewFromInt(-50)})
+	scheduler.RecordRealizedTrade(orders.RealizedTrade{StrategyName: "rebalance", PnL: decimal.NewFromInt(25)})
+
+	report := scheduler.Generate()
+
+	assert.Equal(t, "2024-03-01", report.Date)
+	assert.True(t, report.RealizedPnL.Equal(decimal.NewFromInt(125)))
+	assert.Equal(t, 3, report.RealizedTrades)
+	assert.True(t, report.PnLByStrategy["arb"].Equal(decimal.NewFromInt(100)))
+
+	// Flat positions are not open
+	require.Len(t, report.OpenPositions, 1)
+	assert.Equal(t, "BTC/USD", report.OpenPositions[0].Symbol)
+
+	require.NotNil(t, report.Risk)
+	assert.True(t, report.Risk.VaR95.Equal(decimal.NewFromInt(2400)))
+
+	// Unresolved alerts, most severe first, limited to TopAlerts
+	require.Len(t, report.TopAlerts, 2)
+	assert.Equal(t, "Drawdown", report.TopAlerts[0].Title)
+	assert.Equal(t, "Feed lag", report.TopAlerts[1].Title)
+
+	text := report.String()
+	for _, section := range []string{"Realized PnL", "Open Positions", "Risk Metrics", "Top Alerts"} {
+		assert.Contains(t, text, section)
+	}
+	assert.Contains(t, text, "Total: 125.00 over 3 trades")
+	assert.Contains(t, text, "arb: 100.00")
+	assert.Contains(t, text, "binance BTC/USD BUY 2 @ 50000")
+	assert.Contains(t, text, "VaR 95%: 2400.00")
+	assert.Contains(t, text, "[critical] Drawdown")
+	assert.NotContains(t, text, "Old outage")
+}
+
+// TestDailyReportDispatchedOncePerTick tests that every schedule tick sends exactly one report
+func TestDailyReportDispatchedOncePerTick(t *testing.T) {
+	now := time.Date(2024, 3, 1, 17, 0, 0, 0, time.UTC)
+	scheduler, channel := newTestScheduler(t, now)
+
+	var mu sync.Mutex
+	var waits []time.Duration
+	ticks := make(chan time.Time)
+	scheduler.after = func(d time.Duration) <-chan time.Time {
+		mu.Lock()
+		waits = append(waits, d)
+		mu.Unlock()
+		return ticks
+	}
+
+	scheduler.RecordRealizedTrade(orders.RealizedTrade{StrategyName: "arb", PnL: decimal.NewFromInt(40)})
+	require.NoError(t, scheduler.Start(context.Background()))
+	defer scheduler.Stop()
+
+	ticks <- now
+	select {
+	case alert := <-channel.sent:
+		assert.Equal(t, alerts.AlertTypeReport, alert.Type)
+		assert.Equal(t, "Daily report 2024-03-01", alert.Title)
+		report, ok := alert.Data.(*DailyReport)
+		require.True(t, ok)
+		assert.True(t, report.RealizedPnL.Equal(decimal.NewFromInt(40)))
+	case <-time.After(time.Second):
+		t.Fatal("no report sent on schedule tick")
+	}
+
+	// The next day starts from zero realized PnL
+	ticks <- now
+	select {
+	case alert := <-channel.sent:
+		assert.True(t, alert.Data.(*DailyReport).RealizedPnL.IsZero())
+	case <-time.After(time.Second):
+		t.Fatal("no report sent on second schedule tick")
+	}
+
+	// No report without a tick
+	select {
+	case <-channel.sent:
+		t.Fatal("report sent without a schedule tick")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, waits)
+	assert.Equal(t, time.Hour, waits[0])
+}
+
+func TestNextRun(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		timezone string
+		now      time.Time
+		want     time.Time
+	}{
+		{"later today", "", time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC), time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)},
+		{"exactly at the time rolls to tomorrow", "", time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC), time.Date(2024, 3, 2, 18, 0, 0, 0, time.UTC)},
+		{"past the time", "", time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC), time.Date(2024, 3, 2, 18, 0, 0, 0, time.UTC)},
+		{"configured timezone", "America/New_York", time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 18, 0, 0, 0, newYork)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.Timezone = tt.timezone
+			scheduler, err := NewScheduler(config, newRecordingChannel())
+			require.NoError(t, err)
+			assert.True(t, scheduler.nextRun(tt.now).Equal(tt.want), "got %s, want %s", scheduler.nextRun(tt.now), tt.want)
+		})
+	}
+}
+
+func TestNewSchedulerValidation(t *testing.T) {
+	for _, at := range []string{"", "25:00", "18:60", "six"} {
+		config := DefaultConfig()
+		config.Time = at
+		_, err := NewScheduler(config, newRecordingChannel())
+		assert.Error(t, err, "time %q", at)
+	}
+
+	config := DefaultConfig()
+	config.Timezone = "Mars/Olympus"
+	_, err := NewScheduler(config, newRecordingChannel())
+	assert.Error(t, err)
+
+	_, err = NewChannel(Config{Channel: "email"})
+	assert.Error(t, err)
+	_, err = NewChannel(Config{Channel: "pager"})
+	assert.Error(t, err)
+}
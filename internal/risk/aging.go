@@ -0,0 +1,94 @@
+package risk
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Age returns how long the position has been held
+func (p *Position) Age(now time.Time) time.Duration {
+	if p.CreatedAt.IsZero() {
+		return 0
+	}
+	return now.Sub(p.CreatedAt)
+}
+
+// CheckPositionAges returns a POSITION_AGE_EXCEEDED event for each open
+// position held longer than the max position age, which may be a position
+// left open by mistake
+func (rm *Manager) CheckPositionAges(now time.Time) []*RiskEvent {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	events := make([]*RiskEvent, 0)
+	for _, key := range rm.agedPositionKeys(now) {
+		events = append(events, rm.positionAgeEvent(rm.portfolio.Positions[key], now))
+	}
+	return events
+}
+
+// checkPositionAges raises the age event of each aged position once, until
+// the position is closed or reopened
+func (rm *Manager) checkPositionAges(now time.Time) {
+	rm.mu.Lock()
+	events := make([]*RiskEvent, 0)
+	aged := make(map[string]time.Time)
+	for _, key := range rm.agedPositionKeys(now) {
+		position := rm.portfolio.Positions[key]
+		aged[key] = position.CreatedAt
+		if alerted, ok := rm.agedPositions[key]; ok && alerted.Equal(position.CreatedAt) {
+			continue
+		}
+		events = append(events, rm.positionAgeEvent(position, now))
+	}
+	rm.agedPositions = aged
+	rm.mu.Unlock()
+
+	for _, event := range events {
+		rm.addRiskEvent(event)
+	}
+}
+
+// agedPositionKeys returns the keys of open positions older than the max
+// position age, sorted. Must be called with rm.mu held.
+func (rm *Manager) agedPositionKeys(now time.Time) []string {
+	maxAge := rm.config.AlertThresholds.MaxPositionAge
+	if maxAge <= 0 {
+		return nil
+	}
+
+	keys := make([]string, 0)
+	for key, position := range rm.portfolio.Positions {
+		if position.Quantity.IsZero() || position.Age(now) <= maxAge {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// positionAgeEvent builds the age event of a position, valued in hours
+func (rm *Manager) positionAgeEvent(position *Position, now time.Time) *RiskEvent {
+	age := position.Age(now)
+	maxAge := rm.config.AlertThresholds.MaxPositionAge
+	return &RiskEvent{
+		ID:        uuid.New().String(),
+		Type:      "POSITION_AGE_EXCEEDED",
+		Severity:  RiskLevelMedium,
+		Message:   fmt.Sprintf("Position %s on %s held for %s, longer than the maximum %s", position.Symbol, position.Exchange, age.Round(time.Second), maxAge),
+		Symbol:    position.Symbol,
+		Exchange:  position.Exchange,
+		Value:     decimal.NewFromFloat(age.Hours()).Round(4),
+		Threshold: decimal.NewFromFloat(maxAge.Hours()).Round(4),
+		Timestamp: now,
+		Metadata: map[string]interface{}{
+			"opened_at": position.CreatedAt,
+			"age":       age.Round(time.Second).String(),
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPositionAgeAlert(t *testing.T) {
+	config := DefaultRiskConfig()
+	config.AlertThresholds.MaxPositionAge = 24 * time.Hour
+	rm := NewManager(config, nil)
+
+	received := make(chan *RiskEvent, 10)
+	rm.SubscribeToRiskEvents(func(event *RiskEvent) {
+		if event.Type == "POSITION_AGE_EXCEEDED" {
+			received <- event
+		}
+	})
+
+	now := time.Now()
+	for _, position := range []*Position{
+		{Symbol: "BTC/USD", Exchange: "binance", Side: "LONG", Quantity: decimal.NewFromInt(1), EntryPrice: decimal.NewFromInt(50000), CreatedAt: now.Add(-30 * time.Hour)},
+		{Symbol: "ETH/USD", Exchange: "binance", Side: "LONG", Quantity: decimal.NewFromInt(2), EntryPrice: decimal.NewFromInt(3000), CreatedAt: now.Add(-time.Hour)},
+		{Symbol: "SOL/USD", Exchange: "binance", Side: "LONG", Quantity: decimal.Zero, EntryPrice: decimal.NewFromInt(100), CreatedAt: now.Add(-48 * time.Hour)},
+	} {
+		if err := rm.AddPosition(position); err != nil {
+			t.Fatalf("AddPosition: %v", err)
+		}
+	}
+
+	rm.checkPositionAges(now)
+	events, _ := rm.GetRiskEvents(map[string]interface{}{"type": "POSITION_AGE_EXCEEDED"})
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 position age event, got %d", len(events))
+	}
+	if events[0].Symbol != "BTC/USD" || events[0].Severity != RiskLevelMedium {
+		t.Errorf("Expected a medium severity event for BTC/USD, got %s for %s", events[0].Severity, events[0].Symbol)
+	}
+	if !events[0].Value.Equal(decimal.NewFromInt(30)) || !events[0].Threshold.Equal(decimal.NewFromInt(24)) {
+		t.Errorf("Expected age 30h against 24h, got %s against %s", events[0].Value, events[0].Threshold)
+	}
+
+	select {
+	case event := <-received:
+		if event.Symbol != "BTC/USD" {
+			t.Errorf("Expected subscribers to be notified for BTC/USD, got %s", event.Symbol)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected subscribers to be notified of the aged position")
+	}
+
+	// The same position is flagged once
+	rm.checkPositionAges(now.Add(time.Minute))
+	events, _ = rm.GetRiskEvents(map[string]interface{}{"type": "POSITION_AGE_EXCEEDED"})
+	if len(events) != 1 {
+		t.Errorf("Expected the aged position to be flagged once, got %d events", len(events))
+	}
+
+	// The younger position ages past the limit too
+	rm.checkPositionAges(now.Add(24 * time.Hour))
+	events, _ = rm.GetRiskEvents(map[string]interface{}{"type": "POSITION_AGE_EXCEEDED"})
+	if len(events) != 2 || events[1].Symbol != "ETH/USD" {
+		t.Errorf("Expected ETH/USD to be flagged once past the limit, got %d events", len(events))
+	}
+
+	// Stateless check reports every aged position
+	if aged := rm.CheckPositionAges(now.Add(24 * time.Hour)); len(aged) != 2 {
+		t.Errorf("Expected 2 aged positions, got %d", len(aged))
+	}
+
+	// Disabled
+	rm.config.AlertThresholds.MaxPositionAge = 0
+	if aged := rm.CheckPositionAges(now.Add(24 * time.Hour)); len(aged) != 0 {
+		t.Errorf("Expected no aged positions with the limit disabled, got %d", len(aged))
+	}
+}
+
+func TestAddPositionKeepsOpeningTime(t *testing.T) {
+	rm := NewManager(DefaultRiskConfig(), nil)
+	opened := time.Now().Add(-time.Hour)
+
+	rm.AddPosition(&Position{Symbol: "BTC/USD", Exchange: "binance", Quantity: decimal.NewFromInt(1), CreatedAt: opened})
+	rm.AddPosition(&Position{Symbol: "BTC/USD", Exchange: "binance", Quantity: decimal.NewFromInt(2)})
+
+	if got := rm.GetPositions()["binance:BTC/USD"].CreatedAt; !got.Equal(opened) {
+		t.Errorf("Expected the position to keep its opening time %v, got %v", opened, got)
+	}
+
+	rm.AddPosition(&Position{Symbol: "ETH/USD", Exchange: "binance", Quantity: decimal.NewFromInt(1)})
+	if rm.GetPositions()["binance:ETH/USD"].CreatedAt.IsZero() {
+		t.Error("Expected a new position to be stamped with its opening time")
+	}
+}
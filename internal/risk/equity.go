@@ -0,0 +1,73 @@
+package risk
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxEquitySnapshots bounds the equity curve regardless of the retention period
+const maxEquitySnapshots = 10000
+
+// EquitySnapshot is the portfolio value at a point in time
+type EquitySnapshot struct {
+	Timestamp     time.Time       `json:"timestamp"`
+	TotalValue    decimal.Decimal `json:"total_value"`
+	CashBalance   decimal.Decimal `json:"cash_balance"`
+	UnrealizedPNL decimal.Decimal `json:"unrealized_pnl"`
+	RealizedPNL   decimal.Decimal `json:"realized_pnl"`
+}
+
+// recordEquitySnapshot adds the current portfolio value to the equity curve
+// once the snapshot interval has passed since the last snapshot, and drops
+// snapshots older than the retention period. The caller must hold the lock.
+func (rm *Manager) recordEquitySnapshot(now time.Time) {
+	interval := rm.config.EquitySnapshotInterval
+	if interval <= 0 {
+		return
+	}
+	if n := len(rm.equity); n > 0 && now.Sub(rm.equity[n-1].Timestamp) < interval {
+		return
+	}
+
+	rm.equity = append(rm.equity, EquitySnapshot{
+		Timestamp:     now,
+		TotalValue:    rm.portfolio.TotalValue,
+		CashBalance:   rm.portfolio.CashBalance,
+		UnrealizedPNL: rm.portfolio.UnrealizedPNL,
+		RealizedPNL:   rm.portfolio.RealizedPNL,
+	})
+
+	drop := 0
+	if retention := rm.config.EquityRetention; retention > 0 {
+		cutoff := now.Add(-retention)
+		for drop < len(rm.equity) && rm.equity[drop].Timestamp.Before(cutoff) {
+			drop++
+		}
+	}
+	if len(rm.equity)-drop > maxEquitySnapshots {
+		drop = len(rm.equity) - maxEquitySnapshots
+	}
+	if drop > 0 {
+		rm.equity = append([]EquitySnapshot(nil), rm.equity[drop:]...)
+	}
+}
+
+// EquityCurve returns the equity snapshots taken between from and to,
+// inclusive, oldest first. A zero from or to leaves that end open.
+func (rm *Manager) EquityCurve(from, to time.Time) []EquitySnapshot {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	curve := make([]EquitySnapshot, 0, len(rm.equity))
+	for _, snapshot := range rm.equity {
+		if !from.IsZero() && snapshot.Timestamp.Before(from) {
+			continue
+		}
+		if !to.IsZero() && snapshot.Timestamp.After(to) {
+			continue
+		}
+		curve = append(curve, snapshot)
+	}
+	return curve
+}
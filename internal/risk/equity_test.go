@@ -0,0 +1,78 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestEquitySnapshotsAccumulate(t *testing.T) {
+	config := DefaultRiskConfig()
+	config.UpdateInterval = 5 * time.Millisecond
+	config.EquitySnapshotInterval = 5 * time.Millisecond
+	rm := NewManager(config, nil)
+	if err := rm.UpdatePortfolio(&Portfolio{CashBalance: decimal.NewFromInt(1000), TotalValue: decimal.NewFromInt(1000), Positions: make(map[string]*Position)}); err != nil {
+		t.Fatalf("UpdatePortfolio: %v", err)
+	}
+
+	if err := rm.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer rm.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for len(rm.EquityCurve(time.Time{}, time.Time{})) < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	curve := rm.EquityCurve(time.Time{}, time.Time{})
+	if len(curve) < 3 {
+		t.Fatalf("Expected snapshots to accumulate while running, got %d", len(curve))
+	}
+	for i := 1; i < len(curve); i++ {
+		if !curve[i].Timestamp.After(curve[i-1].Timestamp) {
+			t.Errorf("Expected snapshots oldest first, got %v after %v", curve[i].Timestamp, curve[i-1].Timestamp)
+		}
+	}
+	if !curve[0].TotalValue.Equal(decimal.NewFromInt(1000)) {
+		t.Errorf("Expected snapshot of the portfolio value 1000, got %s", curve[0].TotalValue)
+	}
+}
+
+func TestEquitySnapshotInterval(t *testing.T) {
+	config := DefaultRiskConfig()
+	config.EquitySnapshotInterval = time.Minute
+	config.EquityRetention = time.Hour
+	rm := NewManager(config, nil)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 150; i++ {
+		rm.portfolio.TotalValue = decimal.NewFromInt(int64(1000 + i))
+		// Updates every 30s, of which every other one is snapshotted
+		rm.recordEquitySnapshot(start.Add(time.Duration(i) * 30 * time.Second))
+	}
+
+	// 75 minutes of snapshots, of which those within an hour of the last are retained
+	curve := rm.EquityCurve(time.Time{}, time.Time{})
+	if len(curve) != 61 {
+		t.Fatalf("Expected 61 snapshots within retention, got %d", len(curve))
+	}
+	if want := start.Add(14 * time.Minute); !curve[0].Timestamp.Equal(want) {
+		t.Errorf("Expected the oldest snapshot at %v, got %v", want, curve[0].Timestamp)
+	}
+
+	window := rm.EquityCurve(start.Add(20*time.Minute), start.Add(30*time.Minute))
+	if len(window) != 11 {
+		t.Fatalf("Expected 11 snapshots in the window, got %d", len(window))
+	}
+	if !window[0].TotalValue.Equal(decimal.NewFromInt(1040)) {
+		t.Errorf("Expected the window to start at value 1040, got %s", window[0].TotalValue)
+	}
+
+	rm.config.EquitySnapshotInterval = 0
+	rm.recordEquitySnapshot(start.Add(76 * time.Minute))
+	if got := len(rm.EquityCurve(time.Time{}, time.Time{})); got != 61 {
+		t.Errorf("Expected no snapshots when disabled, got %d", got)
+	}
+}
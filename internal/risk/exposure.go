@@ -0,0 +1,79 @@
+package risk
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// checkExchangeLimits checks an order against the per-exchange position count
+// and exposure caps. Exposure is the absolute market value of an exchange's
+// positions; orders that reduce it are always allowed. Must be called with rm.mu held.
+func (rm *Manager) checkExchangeLimits(symbol, exchange, side string, orderValue decimal.Decimal) *RiskEvent {
+	limits := rm.config.AlertThresholds
+	positionKey := fmt.Sprintf("%s:%s", exchange, symbol)
+
+	positions := 0
+	otherExposure := decimal.Zero
+	current := decimal.Zero // Signed value of the position the order trades
+	for key, position := range rm.portfolio.Positions {
+		if position.Exchange != exchange {
+			continue
+		}
+		positions++
+		if key != positionKey {
+			otherExposure = otherExposure.Add(position.MarketValue.Abs())
+			continue
+		}
+		current = position.MarketValue.Abs()
+		if strings.EqualFold(position.Side, "SHORT") {
+			current = current.Neg()
+		}
+	}
+
+	if maxPositions, ok := limits.MaxExchangePositions[exchange]; ok && maxPositions > 0 {
+		if _, exists := rm.portfolio.Positions[positionKey]; !exists && positions >= maxPositions {
+			return &RiskEvent{
+				ID:        uuid.New().String(),
+				Type:      "EXCHANGE_POSITIONS_EXCEEDED",
+				Severity:  RiskLevelHigh,
+				Message:   fmt.Sprintf("Exchange %s already has %d open positions (max %d)", exchange, positions, maxPositions),
+				Symbol:    symbol,
+				Exchange:  exchange,
+				Value:     decimal.NewFromInt(int64(positions + 1)),
+				Threshold: decimal.NewFromInt(int64(maxPositions)),
+				Timestamp: time.Now(),
+			}
+		}
+	}
+
+	maxExposure, ok := limits.MaxExchangeExposure[exchange]
+	if !ok || !maxExposure.IsPositive() {
+		return nil
+	}
+
+	signedOrder := orderValue
+	if strings.EqualFold(side, "sell") {
+		signedOrder = signedOrder.Neg()
+	}
+	exposure := otherExposure.Add(current.Abs())
+	newExposure := otherExposure.Add(current.Add(signedOrder).Abs())
+	if newExposure.LessThanOrEqual(maxExposure) || newExposure.LessThanOrEqual(exposure) {
+		return nil
+	}
+
+	return &RiskEvent{
+		ID:        uuid.New().String(),
+		Type:      "EXCHANGE_EXPOSURE_EXCEEDED",
+		Severity:  RiskLevelHigh,
+		Message:   fmt.Sprintf("Order would raise %s exposure to %s, above maximum %s", exchange, newExposure.String(), maxExposure.String()),
+		Symbol:    symbol,
+		Exchange:  exchange,
+		Value:     newExposure,
+		Threshold: maxExposure,
+		Timestamp: time.Now(),
+	}
+}
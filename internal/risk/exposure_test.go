@@ -0,0 +1,124 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// exposureManager returns a risk manager holding two binance positions worth
+// 30000 and 15000 and one coinbase position, with a 50000 binance exposure cap
+func exposureManager() *Manager {
+	config := DefaultRiskConfig()
+	config.AlertThresholds.MaxPositionSize = decimal.NewFromInt(100000)
+	config.AlertThresholds.MaxPortfolioValue = decimal.NewFromInt(1000000)
+	config.AlertThresholds.MaxConcentration = decimal.NewFromInt(1)
+	config.AlertThresholds.MaxExchangeExposure = map[string]decimal.Decimal{"binance": decimal.NewFromInt(50000)}
+
+	rm := NewManager(config, nil)
+	rm.portfolio = &Portfolio{
+		TotalValue: decimal.NewFromInt(200000),
+		Positions: map[string]*Position{
+			"binance:BTC/USD":  {Symbol: "BTC/USD", Exchange: "binance", Side: "LONG", MarketValue: decimal.NewFromInt(30000)},
+			"binance:ETH/USD":  {Symbol: "ETH/USD", Exchange: "binance", Side: "SHORT", MarketValue: decimal.NewFromInt(15000)},
+			"coinbase:BTC/USD": {Symbol: "BTC/USD", Exchange: "coinbase", Side: "LONG", MarketValue: decimal.NewFromInt(40000)},
+		},
+	}
+	return rm
+}
+
+func TestExchangeExposureCap(t *testing.T) {
+	tests := []struct {
+		name     string
+		symbol   string
+		exchange string
+		side     string
+		value    int64
+		wantType string
+	}{
+		{"within cap", "BTC/USD", "binance", "buy", 5000, ""},
+		{"new position breaches cap", "SOL/USD", "binance", "buy", 6000, "EXCHANGE_EXPOSURE_EXCEEDED"},
+		{"adding to long breaches cap", "BTC/USD", "binance", "buy", 6000, "EXCHANGE_EXPOSURE_EXCEEDED"},
+		{"adding to short breaches cap", "ETH/USD", "binance", "sell", 6000, "EXCHANGE_EXPOSURE_EXCEEDED"},
+		{"reducing long is allowed", "BTC/USD", "binance", "sell", 10000, ""},
+		{"flipping short to a smaller long is allowed", "ETH/USD", "binance", "buy", 20000, ""},
+		{"uncapped exchange is not limited", "ETH/USD", "coinbase", "buy", 90000, ""},
+	}
+
+	for _, tt := range tests {
+		rm := exposureManager()
+		event, err := rm.CheckOrderRisk(tt.symbol, tt.exchange, tt.side, decimal.NewFromInt(1), decimal.NewFromInt(tt.value))
+		if err != nil {
+			t.Fatalf("%s: CheckOrderRisk error: %v", tt.name, err)
+		}
+
+		switch {
+		case tt.wantType == "" && event != nil:
+			t.Errorf("%s: unexpected risk event %s: %s", tt.name, event.Type, event.Message)
+		case tt.wantType != "" && event == nil:
+			t.Errorf("%s: got no risk event, want %s", tt.name, tt.wantType)
+		case tt.wantType != "" && event.Type != tt.wantType:
+			t.Errorf("%s: got risk event %s, want %s", tt.name, event.Type, tt.wantType)
+		}
+	}
+}
+
+func TestExchangeExposureEventValues(t *testing.T) {
+	rm := exposureManager()
+	event, err := rm.CheckOrderRisk("SOL/USD", "binance", "buy", decimal.NewFromInt(2), decimal.NewFromInt(4000))
+	if err != nil {
+		t.Fatalf("CheckOrderRisk error: %v", err)
+	}
+	if event == nil {
+		t.Fatal("got no risk event, want exposure breach")
+	}
+	if !event.Value.Equal(decimal.NewFromInt(53000)) {
+		t.Errorf("event value = %s, want 53000", event.Value)
+	}
+	if !event.Threshold.Equal(decimal.NewFromInt(50000)) {
+		t.Errorf("event threshold = %s, want 50000", event.Threshold)
+	}
+	if event.Exchange != "binance" || event.Severity != RiskLevelHigh {
+		t.Errorf("event = %s/%s, want binance/%s", event.Exchange, event.Severity, RiskLevelHigh)
+	}
+}
+
+func TestExchangePositionCap(t *testing.T) {
+	rm := exposureManager()
+	rm.config.AlertThresholds.MaxExchangeExposure = nil
+	rm.config.AlertThresholds.MaxExchangePositions = map[string]int{"binance": 2}
+
+	// A third binance position is rejected
+	event, err := rm.CheckOrderRisk("SOL/USD", "binance", "buy", decimal.NewFromInt(1), decimal.NewFromInt(100))
+	if err != nil {
+		t.Fatalf("CheckOrderRisk error: %v", err)
+	}
+	if event == nil || event.Type != "EXCHANGE_POSITIONS_EXCEEDED" {
+		t.Fatalf("got %v, want EXCHANGE_POSITIONS_EXCEEDED", event)
+	}
+
+	// Trading an existing position or another exchange is not
+	for _, order := range [][2]string{{"BTC/USD", "binance"}, {"SOL/USD", "coinbase"}} {
+		event, err := rm.CheckOrderRisk(order[0], order[1], "buy", decimal.NewFromInt(1), decimal.NewFromInt(100))
+		if err != nil {
+			t.Fatalf("CheckOrderRisk error: %v", err)
+		}
+		if event != nil {
+			t.Errorf("%s on %s: unexpected risk event %s", order[0], order[1], event.Type)
+		}
+	}
+}
+
+func TestOrderRiskEmptyPortfolio(t *testing.T) {
+	rm := exposureManager()
+	rm.portfolio = &Portfolio{Positions: make(map[string]*Position)}
+
+	// No portfolio value to divide by: concentration cannot be measured
+	event, err := rm.CheckOrderRisk("BTC/USD", "binance", "buy", decimal.NewFromInt(1), decimal.NewFromInt(100))
+	if err != nil {
+		t.Fatalf("CheckOrderRisk error: %v", err)
+	}
+	if event != nil {
+		t.Errorf("got %v, want no event", event)
+	}
+}
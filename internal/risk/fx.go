@@ -0,0 +1,89 @@
+package risk
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// FXRateSource supplies exchange rates for portfolio valuation
+type FXRateSource interface {
+	// FXRate returns how much one unit of currency is worth in base
+	FXRate(currency, base string) (decimal.Decimal, bool)
+}
+
+// StaticFXRates is a fixed table of rates into the base currency, keyed by currency
+type StaticFXRates map[string]decimal.Decimal
+
+// FXRate returns the table's rate for a currency
+func (r StaticFXRates) FXRate(currency, base string) (decimal.Decimal, bool) {
+	rate, ok := r[strings.ToUpper(currency)]
+	if !ok {
+		rate, ok = r[currency]
+	}
+	return rate, ok
+}
+
+// SetFXRateSource replaces the configured FX rates with a live source
+func (rm *Manager) SetFXRateSource(source FXRateSource) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.fxRates = source
+	rm.updatePortfolioValue()
+}
+
+// quoteCurrency returns the currency a position's prices are quoted in,
+// taken from the quote side of a BASE/QUOTE or BASE-QUOTE symbol unless set
+func quoteCurrency(position *Position) string {
+	if position.QuoteCurrency != "" {
+		return position.QuoteCurrency
+	}
+	if i := strings.LastIndexAny(position.Symbol, "/-"); i >= 0 {
+		return position.Symbol[i+1:]
+	}
+	return ""
+}
+
+// fxRate returns the rate converting a position's values into the base
+// currency. Without a base currency, or for positions already in it or whose
+// currency cannot be told, values are used as they are. The caller holds rm.mu.
+func (rm *Manager) fxRate(position *Position) (decimal.Decimal, error) {
+	base := rm.config.BaseCurrency
+	currency := quoteCurrency(position)
+	if base == "" || currency == "" || strings.EqualFold(currency, base) {
+		return decimal.NewFromInt(1), nil
+	}
+
+	source := rm.fxRates
+	if source == nil {
+		source = StaticFXRates(rm.config.FXRates)
+	}
+	rate, ok := source.FXRate(currency, base)
+	if !ok || !rate.IsPositive() {
+		return decimal.Zero, fmt.Errorf("no %s/%s rate", currency, base)
+	}
+	return rate, nil
+}
+
+// baseMarketValue returns a position's market value in the base currency,
+// or false if it cannot be converted. The caller holds rm.mu.
+func (rm *Manager) baseMarketValue(position *Position) (decimal.Decimal, bool) {
+	rate, err := rm.fxRate(position)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	return position.MarketValue.Mul(rate), true
+}
+
+// logUnconverted reports positions left out of the portfolio value
+func logUnconverted(keys []string, base string) {
+	if len(keys) == 0 {
+		return
+	}
+	sort.Strings(keys)
+	log.Printf("Risk: no FX rate into %s for positions %s, left out of portfolio value", base, strings.Join(keys, ", "))
+}
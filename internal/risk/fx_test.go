@@ -0,0 +1,86 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// fxManager returns a risk manager valuing in USD with 1000 cash and EUR at 1.1
+func fxManager() *Manager {
+	config := DefaultRiskConfig()
+	config.BaseCurrency = "USD"
+	config.FXRates = map[string]decimal.Decimal{"EUR": decimal.NewFromFloat(1.1)}
+
+	rm := NewManager(config, nil)
+	rm.portfolio.CashBalance = decimal.NewFromInt(1000)
+	return rm
+}
+
+func TestPortfolioValueFXConversion(t *testing.T) {
+	rm := fxManager()
+
+	// 2 BTC at 100 USD, and 10 ETH at 50 EUR bought at 40 EUR
+	rm.AddPosition(&Position{Symbol: "BTC/USD", Exchange: "binance", Side: "LONG",
+		Quantity: decimal.NewFromInt(2), EntryPrice: decimal.NewFromInt(100), MarketValue: decimal.NewFromInt(200)})
+	rm.AddPosition(&Position{Symbol: "ETH/EUR", Exchange: "kraken", Side: "LONG",
+		Quantity: decimal.NewFromInt(10), EntryPrice: decimal.NewFromInt(40), MarketValue: decimal.NewFromInt(500),
+		UnrealizedPNL: decimal.NewFromInt(100)})
+
+	portfolio := rm.GetPortfolio()
+	// 1000 cash + 200 + 500 EUR * 1.1
+	if want := decimal.NewFromInt(1750); !portfolio.TotalValue.Equal(want) {
+		t.Errorf("total value = %s, want %s", portfolio.TotalValue, want)
+	}
+	if want := decimal.NewFromInt(640); !portfolio.InvestedValue.Equal(want) {
+		t.Errorf("invested value = %s, want %s", portfolio.InvestedValue, want)
+	}
+	if want := decimal.NewFromInt(110); !portfolio.UnrealizedPNL.Equal(want) {
+		t.Errorf("unrealized pnl = %s, want %s", portfolio.UnrealizedPNL, want)
+	}
+	if portfolio.BaseCurrency != "USD" || len(portfolio.Unconverted) != 0 {
+		t.Errorf("base = %q, unconverted = %v", portfolio.BaseCurrency, portfolio.Unconverted)
+	}
+
+	// Repricing the EUR position converts at the same rate
+	if err := rm.UpdatePosition("ETH/EUR", "kraken", decimal.NewFromInt(60)); err != nil {
+		t.Fatalf("UpdatePosition: %v", err)
+	}
+	if want := decimal.NewFromInt(1860); !rm.GetPortfolio().TotalValue.Equal(want) {
+		t.Errorf("repriced total value = %s, want %s", rm.GetPortfolio().TotalValue, want)
+	}
+}
+
+func TestPortfolioValueFXRateSource(t *testing.T) {
+	rm := fxManager()
+	rm.AddPosition(&Position{Symbol: "ETH-GBP", Exchange: "kraken", MarketValue: decimal.NewFromInt(100)})
+
+	// GBP has no configured rate, so the position is left out and reported
+	portfolio := rm.GetPortfolio()
+	if !portfolio.TotalValue.Equal(decimal.NewFromInt(1000)) {
+		t.Errorf("total value = %s, want cash only", portfolio.TotalValue)
+	}
+	if len(portfolio.Unconverted) != 1 || portfolio.Unconverted[0] != "kraken:ETH-GBP" {
+		t.Errorf("unconverted = %v, want kraken:ETH-GBP", portfolio.Unconverted)
+	}
+
+	rm.SetFXRateSource(StaticFXRates{"GBP": decimal.NewFromFloat(1.25)})
+	portfolio = rm.GetPortfolio()
+	if want := decimal.NewFromInt(1125); !portfolio.TotalValue.Equal(want) {
+		t.Errorf("total value = %s, want %s", portfolio.TotalValue, want)
+	}
+	if len(portfolio.Unconverted) != 0 {
+		t.Errorf("unconverted = %v, want none", portfolio.Unconverted)
+	}
+}
+
+func TestPortfolioValueWithoutBaseCurrency(t *testing.T) {
+	rm := NewManager(DefaultRiskConfig(), nil)
+	rm.AddPosition(&Position{Symbol: "BTC/USD", Exchange: "binance", MarketValue: decimal.NewFromInt(200)})
+	rm.AddPosition(&Position{Symbol: "ETH/EUR", Exchange: "kraken", MarketValue: decimal.NewFromInt(500)})
+
+	// Without a base currency values are summed as they are
+	if want := decimal.NewFromInt(700); !rm.GetPortfolio().TotalValue.Equal(want) {
+		t.Errorf("total value = %s, want %s", rm.GetPortfolio().TotalValue, want)
+	}
+}
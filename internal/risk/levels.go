@@ -0,0 +1,139 @@
+package risk
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Risk event types raised when a position's own price levels are breached
+const (
+	EventPositionTakeProfit = "POSITION_TAKE_PROFIT"
+	EventPositionStopLoss   = "POSITION_STOP_LOSS"
+)
+
+// LiquidationHandler closes a position whose take-profit or stop-loss was
+// breached, e.g. by submitting a market order for it
+type LiquidationHandler func(position Position, event *RiskEvent)
+
+// SetLiquidationHandler sets what closes positions that breach their levels.
+// It is only called for stop-losses with AutoStopLoss, and for take-profits
+// with AutoTakeProfit, set. Levels are monitored on the positions the risk
+// manager holds, which are those of backtest portfolios: live order manager
+// positions are not fed to it, so the live process sets no handler.
+func (rm *Manager) SetLiquidationHandler(handler LiquidationHandler) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.liquidate = handler
+}
+
+// SetPositionLevels sets the take-profit and stop-loss prices of an open
+// position; zero clears a level
+func (rm *Manager) SetPositionLevels(symbol, exchange string, takeProfit, stopLoss decimal.Decimal) error {
+	if takeProfit.IsNegative() || stopLoss.IsNegative() {
+		return fmt.Errorf("take-profit and stop-loss cannot be negative")
+	}
+
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	key := fmt.Sprintf("%s:%s", exchange, symbol)
+	position, exists := rm.portfolio.Positions[key]
+	if !exists {
+		return fmt.Errorf("position not found: %s", key)
+	}
+
+	position.TakeProfit = takeProfit
+	position.StopLoss = stopLoss
+	delete(rm.breachedLevels, key)
+	return nil
+}
+
+// LevelBreached returns the level event type the position's current price
+// breaches, or "" if it breaches neither. A long position takes profit at or
+// above its take-profit and stops out at or below its stop-loss; a short one
+// the other way round.
+func (p *Position) LevelBreached() string {
+	price := p.CurrentPrice
+	if !price.IsPositive() {
+		return ""
+	}
+
+	short := p.Side == "SHORT"
+	if p.StopLoss.IsPositive() {
+		if (!short && price.LessThanOrEqual(p.StopLoss)) || (short && price.GreaterThanOrEqual(p.StopLoss)) {
+			return EventPositionStopLoss
+		}
+	}
+	if p.TakeProfit.IsPositive() {
+		if (!short && price.GreaterThanOrEqual(p.TakeProfit)) || (short && price.LessThanOrEqual(p.TakeProfit)) {
+			return EventPositionTakeProfit
+		}
+	}
+	return ""
+}
+
+// checkPositionLevels returns the level event of a position that has just
+// breached its take-profit or stop-loss, and whether to liquidate it. Each
+// breach is raised once, until the price moves back inside the levels. Must
+// be called with rm.mu held.
+func (rm *Manager) checkPositionLevels(key string, position *Position) (*RiskEvent, bool) {
+	breached := position.LevelBreached()
+	if breached == "" || position.Quantity.IsZero() {
+		delete(rm.breachedLevels, key)
+		return nil, false
+	}
+	if rm.breachedLevels[key] == breached {
+		return nil, false
+	}
+	if rm.breachedLevels == nil {
+		rm.breachedLevels = make(map[string]string)
+	}
+	rm.breachedLevels[key] = breached
+
+	event := &RiskEvent{
+		ID:        uuid.New().String(),
+		Type:      breached,
+		Symbol:    position.Symbol,
+		Exchange:  position.Exchange,
+		Value:     position.CurrentPrice,
+		Timestamp: time.Now(),
+		Metadata: map[string]interface{}{
+			"side":           position.Side,
+			"quantity":       position.Quantity.String(),
+			"unrealized_pnl": position.UnrealizedPNL.String(),
+		},
+	}
+	liquidate := false
+	if breached == EventPositionStopLoss {
+		event.Severity = RiskLevelHigh
+		event.Threshold = position.StopLoss
+		event.Message = fmt.Sprintf("Stop-loss %s hit for %s on %s at %s", position.StopLoss, position.Symbol, position.Exchange, position.CurrentPrice)
+		liquidate = rm.config.AutoStopLoss
+	} else {
+		event.Severity = RiskLevelLow
+		event.Threshold = position.TakeProfit
+		event.Message = fmt.Sprintf("Take-profit %s hit for %s on %s at %s", position.TakeProfit, position.Symbol, position.Exchange, position.CurrentPrice)
+		liquidate = rm.config.AutoTakeProfit
+	}
+	liquidate = liquidate && rm.liquidate != nil
+	event.Metadata["liquidate"] = liquidate
+	return event, liquidate
+}
+
+// raiseLevelEvent records a level event and, if asked, liquidates the position
+func (rm *Manager) raiseLevelEvent(event *RiskEvent, position Position, liquidate bool) {
+	rm.addRiskEvent(event)
+	if !liquidate {
+		return
+	}
+
+	rm.mu.RLock()
+	handler := rm.liquidate
+	rm.mu.RUnlock()
+	if handler != nil {
+		handler(position, event)
+	}
+}
@@ -0,0 +1,167 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// levelTestManager returns a risk manager holding a long BTC position with
+// a take-profit at 55000 and a stop-loss at 48000, and a channel of the level
+// events it raises
+func levelTestManager(t *testing.T, config RiskConfig) (*Manager, chan *RiskEvent) {
+	t.Helper()
+
+	rm := NewManager(config, nil)
+	received := make(chan *RiskEvent, 10)
+	rm.SubscribeToRiskEvents(func(event *RiskEvent) {
+		if event.Type == EventPositionTakeProfit || event.Type == EventPositionStopLoss {
+			received <- event
+		}
+	})
+
+	err := rm.AddPosition(&Position{
+		Symbol:     "BTC/USD",
+		Exchange:   "binance",
+		Side:       "LONG",
+		Quantity:   decimal.NewFromInt(1),
+		EntryPrice: decimal.NewFromInt(50000),
+		TakeProfit: decimal.NewFromInt(55000),
+		StopLoss:   decimal.NewFromInt(48000),
+	})
+	if err != nil {
+		t.Fatalf("AddPosition: %v", err)
+	}
+	return rm, received
+}
+
+func waitLevelEvent(t *testing.T, received chan *RiskEvent) *RiskEvent {
+	t.Helper()
+
+	select {
+	case event := <-received:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("Expected a level event")
+		return nil
+	}
+}
+
+func TestPositionTakeProfit(t *testing.T) {
+	config := DefaultRiskConfig()
+	config.AutoTakeProfit = false
+	rm, received := levelTestManager(t, config)
+	rm.SetLiquidationHandler(func(position Position, event *RiskEvent) {
+		t.Errorf("Expected no liquidation without auto take-profit, got one for %s", position.Symbol)
+	})
+
+	rm.UpdatePosition("BTC/USD", "binance", decimal.NewFromInt(54000))
+	rm.UpdatePosition("BTC/USD", "binance", decimal.NewFromInt(55500))
+
+	event := waitLevelEvent(t, received)
+	if event.Type != EventPositionTakeProfit || event.Severity != RiskLevelLow {
+		t.Errorf("Expected a low severity take-profit event, got %s %s", event.Severity, event.Type)
+	}
+	if !event.Value.Equal(decimal.NewFromInt(55500)) || !event.Threshold.Equal(decimal.NewFromInt(55000)) {
+		t.Errorf("Expected 55500 against 55000, got %s against %s", event.Value, event.Threshold)
+	}
+	if event.Metadata["liquidate"] != false {
+		t.Errorf("Expected the event not to liquidate, got %v", event.Metadata["liquidate"])
+	}
+
+	// A breach is raised once while the price stays past the level
+	rm.UpdatePosition("BTC/USD", "binance", decimal.NewFromInt(56000))
+	select {
+	case event := <-received:
+		t.Errorf("Expected one take-profit event, got another %s", event.Type)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestPositionStopLoss(t *testing.T) {
+	rm, received := levelTestManager(t, DefaultRiskConfig())
+	liquidated := make(chan Position, 1)
+	rm.SetLiquidationHandler(func(position Position, event *RiskEvent) {
+		liquidated <- position
+	})
+
+	rm.UpdatePosition("BTC/USD", "binance", decimal.NewFromInt(47500))
+
+	event := waitLevelEvent(t, received)
+	if event.Type != EventPositionStopLoss || event.Severity != RiskLevelHigh {
+		t.Errorf("Expected a high severity stop-loss event, got %s %s", event.Severity, event.Type)
+	}
+	if !event.Threshold.Equal(decimal.NewFromInt(48000)) {
+		t.Errorf("Expected a threshold of 48000, got %s", event.Threshold)
+	}
+
+	select {
+	case position := <-liquidated:
+		if position.Symbol != "BTC/USD" || !position.CurrentPrice.Equal(decimal.NewFromInt(47500)) {
+			t.Errorf("Expected BTC/USD liquidated at 47500, got %s at %s", position.Symbol, position.CurrentPrice)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the position to be liquidated with auto stop-loss")
+	}
+
+	events, _ := rm.GetRiskEvents(map[string]interface{}{"type": EventPositionStopLoss})
+	if len(events) != 1 {
+		t.Errorf("Expected 1 stop-loss event, got %d", len(events))
+	}
+}
+
+func TestPositionLevelBreached(t *testing.T) {
+	tests := []struct {
+		name  string
+		side  string
+		price int64
+		want  string
+	}{
+		{"long inside the levels", "LONG", 100, ""},
+		{"long at take-profit", "LONG", 110, EventPositionTakeProfit},
+		{"long at stop-loss", "LONG", 90, EventPositionStopLoss},
+		{"short below take-profit", "SHORT", 85, EventPositionTakeProfit},
+		{"short above stop-loss", "SHORT", 115, EventPositionStopLoss},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position := &Position{Side: tt.side, CurrentPrice: decimal.NewFromInt(tt.price)}
+			if tt.side == "LONG" {
+				position.TakeProfit, position.StopLoss = decimal.NewFromInt(110), decimal.NewFromInt(90)
+			} else {
+				position.TakeProfit, position.StopLoss = decimal.NewFromInt(90), decimal.NewFromInt(110)
+			}
+			if got := position.LevelBreached(); got != tt.want {
+				t.Errorf("LevelBreached() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := (&Position{Side: "LONG", CurrentPrice: decimal.NewFromInt(1)}).LevelBreached(); got != "" {
+		t.Errorf("Expected no breach without levels, got %q", got)
+	}
+}
+
+func TestSetPositionLevels(t *testing.T) {
+	rm, received := levelTestManager(t, DefaultRiskConfig())
+
+	if err := rm.SetPositionLevels("ETH/USD", "binance", decimal.NewFromInt(1), decimal.Zero); err == nil {
+		t.Error("Expected an error for an unknown position")
+	}
+	if err := rm.SetPositionLevels("BTC/USD", "binance", decimal.Zero, decimal.NewFromInt(49000)); err != nil {
+		t.Fatalf("SetPositionLevels: %v", err)
+	}
+
+	position := rm.GetPositions()["binance:BTC/USD"]
+	if !position.TakeProfit.IsZero() || !position.StopLoss.Equal(decimal.NewFromInt(49000)) {
+		t.Errorf("Expected levels 0/49000, got %s/%s", position.TakeProfit, position.StopLoss)
+	}
+
+	rm.UpdatePosition("BTC/USD", "binance", decimal.NewFromInt(60000))
+	rm.UpdatePosition("BTC/USD", "binance", decimal.NewFromInt(48500))
+	if event := waitLevelEvent(t, received); event.Type != EventPositionStopLoss {
+		t.Errorf("Expected the raised stop-loss to trigger, got %s", event.Type)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"velocimex/internal/metrics"
+	"velocimex/internal/numeric"
 )
 
 // Manager implements the RiskManager interface
@@ -19,6 +20,13 @@ type Manager struct {
 	riskMetrics   *RiskMetrics
 	riskEvents    []*RiskEvent
 	eventCallbacks []func(*RiskEvent)
+	lastValue     decimal.Decimal
+	returns       []decimal.Decimal // Portfolio returns between metric updates, for historical VaR
+	equity        []EquitySnapshot  // Periodic portfolio values, oldest first
+	agedPositions map[string]time.Time // Opening time of each position already flagged as aged
+	breachedLevels map[string]string  // Level event type already raised for each position, until its price moves back
+	liquidate     LiquidationHandler   // Closes positions whose take-profit or stop-loss is breached
+	fxRates       FXRateSource      // Overrides the configured FX rates when set
 	metrics       *metrics.Wrapper
 	running       bool
 	mu            sync.RWMutex
@@ -57,12 +65,17 @@ func (rm *Manager) GetConfig() RiskConfig {
 	return rm.config
 }
 
-// UpdatePortfolio updates the portfolio state
+// UpdatePortfolio updates the portfolio state. The manager keeps its own
+// copy, so the caller may go on modifying the portfolio it passed in.
 func (rm *Manager) UpdatePortfolio(portfolio *Portfolio) error {
+	if portfolio == nil {
+		return fmt.Errorf("portfolio cannot be nil")
+	}
+	
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
 	
-	rm.portfolio = portfolio
+	rm.portfolio = portfolio.Clone()
 	rm.portfolio.LastUpdated = time.Now()
 	
 	// Update risk metrics
@@ -74,18 +87,19 @@ func (rm *Manager) UpdatePortfolio(portfolio *Portfolio) error {
 	return nil
 }
 
-// GetPortfolio returns the current portfolio
+// GetPortfolio returns a copy of the current portfolio
 func (rm *Manager) GetPortfolio() *Portfolio {
 	rm.mu.RLock()
 	defer rm.mu.RUnlock()
-	return rm.portfolio
+	return rm.portfolio.Clone()
 }
 
-// GetRiskMetrics returns the current risk metrics
+// GetRiskMetrics returns a copy of the current risk metrics
 func (rm *Manager) GetRiskMetrics() *RiskMetrics {
 	rm.mu.RLock()
 	defer rm.mu.RUnlock()
-	return rm.riskMetrics
+	metrics := *rm.riskMetrics
+	return &metrics
 }
 
 // AddPosition adds a new position to the portfolio
@@ -94,7 +108,15 @@ func (rm *Manager) AddPosition(position *Position) error {
 	defer rm.mu.Unlock()
 	
 	key := fmt.Sprintf("%s:%s", position.Exchange, position.Symbol)
-	rm.portfolio.Positions[key] = position
+	copied := *position
+	// A position's age runs from when it was first added, unless it says otherwise
+	if copied.CreatedAt.IsZero() {
+		copied.CreatedAt = time.Now()
+		if existing, ok := rm.portfolio.Positions[key]; ok && !existing.CreatedAt.IsZero() {
+			copied.CreatedAt = existing.CreatedAt
+		}
+	}
+	rm.portfolio.Positions[key] = &copied
 	
 	// Update portfolio value
 	rm.updatePortfolioValue()
@@ -118,12 +140,17 @@ func (rm *Manager) UpdatePosition(symbol, exchange string, price decimal.Decimal
 	
 	position.CurrentPrice = price
 	position.MarketValue = position.Quantity.Mul(price)
-	position.UnrealizedPNL = position.MarketValue.Sub(position.Quantity.Mul(position.EntryPrice))
+	position.UnrealizedPNL = numeric.Round(position.MarketValue.Sub(position.Quantity.Mul(position.EntryPrice)))
 	position.UpdatedAt = time.Now()
 	
 	// Update portfolio value
 	rm.updatePortfolioValue()
 	
+	// Check the position's take-profit and stop-loss levels
+	if event, liquidate := rm.checkPositionLevels(key, position); event != nil {
+		go rm.raiseLevelEvent(event, *position, liquidate)
+	}
+	
 	return nil
 }
 
@@ -134,6 +161,7 @@ func (rm *Manager) RemovePosition(symbol, exchange string) error {
 	
 	key := fmt.Sprintf("%s:%s", exchange, symbol)
 	delete(rm.portfolio.Positions, key)
+	delete(rm.breachedLevels, key)
 	
 	// Update portfolio value
 	rm.updatePortfolioValue()
@@ -141,11 +169,11 @@ func (rm *Manager) RemovePosition(symbol, exchange string) error {
 	return nil
 }
 
-// GetPositions returns all positions
+// GetPositions returns a copy of all positions
 func (rm *Manager) GetPositions() map[string]*Position {
 	rm.mu.RLock()
 	defer rm.mu.RUnlock()
-	return rm.portfolio.Positions
+	return clonePositions(rm.portfolio.Positions)
 }
 
 // CheckOrderRisk checks if an order meets risk requirements
@@ -186,6 +214,11 @@ func (rm *Manager) CheckOrderRisk(symbol, exchange, side string, quantity, price
 		}, nil
 	}
 	
+	// Check per-exchange position and exposure limits
+	if event := rm.checkExchangeLimits(symbol, exchange, side, orderValue); event != nil {
+		return event, nil
+	}
+	
 	// Check concentration risk
 	positionKey := fmt.Sprintf("%s:%s", exchange, symbol)
 	existingPosition, exists := rm.portfolio.Positions[positionKey]
@@ -196,6 +229,10 @@ func (rm *Manager) CheckOrderRisk(symbol, exchange, side string, quantity, price
 		totalPositionValue = orderValue
 	}
 	
+	// An empty portfolio has no concentration to measure against
+	if !rm.portfolio.TotalValue.IsPositive() {
+		return nil, nil
+	}
 	concentrationRatio := totalPositionValue.Div(rm.portfolio.TotalValue)
 	if concentrationRatio.GreaterThan(rm.config.AlertThresholds.MaxConcentration) {
 		return &RiskEvent{
@@ -376,16 +413,26 @@ func (rm *Manager) IsRunning() bool {
 
 // Private methods
 
+// updatePortfolioValue totals the positions, converted into the base
+// currency, with the cash balance, which is held in the base currency
 func (rm *Manager) updatePortfolioValue() {
 	rm.portfolio.TotalValue = rm.portfolio.CashBalance
 	rm.portfolio.InvestedValue = decimal.Zero
 	rm.portfolio.UnrealizedPNL = decimal.Zero
-	
-	for _, position := range rm.portfolio.Positions {
-		rm.portfolio.TotalValue = rm.portfolio.TotalValue.Add(position.MarketValue)
-		rm.portfolio.InvestedValue = rm.portfolio.InvestedValue.Add(position.Quantity.Mul(position.EntryPrice))
-		rm.portfolio.UnrealizedPNL = rm.portfolio.UnrealizedPNL.Add(position.UnrealizedPNL)
+	rm.portfolio.BaseCurrency = rm.config.BaseCurrency
+	rm.portfolio.Unconverted = nil
+	
+	for key, position := range rm.portfolio.Positions {
+		rate, err := rm.fxRate(position)
+		if err != nil {
+			rm.portfolio.Unconverted = append(rm.portfolio.Unconverted, key)
+			continue
+		}
+		rm.portfolio.TotalValue = rm.portfolio.TotalValue.Add(position.MarketValue.Mul(rate))
+		rm.portfolio.InvestedValue = rm.portfolio.InvestedValue.Add(position.Quantity.Mul(position.EntryPrice).Mul(rate))
+		rm.portfolio.UnrealizedPNL = rm.portfolio.UnrealizedPNL.Add(position.UnrealizedPNL.Mul(rate))
 	}
+	logUnconverted(rm.portfolio.Unconverted, rm.config.BaseCurrency)
 }
 
 func (rm *Manager) calculateRiskMetrics() {
@@ -395,7 +442,7 @@ func (rm *Manager) calculateRiskMetrics() {
 	
 	// Calculate leverage
 	if rm.portfolio.CashBalance.GreaterThan(decimal.Zero) {
-		rm.riskMetrics.Leverage = rm.portfolio.InvestedValue.Div(rm.portfolio.CashBalance)
+		rm.riskMetrics.Leverage = numeric.Round(rm.portfolio.InvestedValue.Div(rm.portfolio.CashBalance))
 	} else {
 		rm.riskMetrics.Leverage = decimal.Zero
 	}
@@ -403,17 +450,25 @@ func (rm *Manager) calculateRiskMetrics() {
 	// Calculate concentration risk (max position as % of portfolio)
 	maxPositionValue := decimal.Zero
 	for _, position := range rm.portfolio.Positions {
-		if position.MarketValue.GreaterThan(maxPositionValue) {
-			maxPositionValue = position.MarketValue
+		value, ok := rm.baseMarketValue(position)
+		if ok && value.GreaterThan(maxPositionValue) {
+			maxPositionValue = value
 		}
 	}
 	
 	if rm.portfolio.TotalValue.GreaterThan(decimal.Zero) {
-		rm.riskMetrics.ConcentrationRisk = maxPositionValue.Div(rm.portfolio.TotalValue)
+		rm.riskMetrics.ConcentrationRisk = numeric.Round(maxPositionValue.Div(rm.portfolio.TotalValue))
 	} else {
 		rm.riskMetrics.ConcentrationRisk = decimal.Zero
 	}
 	
+	// Historical VaR and expected shortfall, in portfolio currency
+	rm.recordReturn(rm.portfolio.TotalValue)
+	rm.riskMetrics.VaR95 = numeric.Round(HistoricalVaR(rm.returns, 0.95).Mul(rm.portfolio.TotalValue))
+	rm.riskMetrics.VaR99 = numeric.Round(HistoricalVaR(rm.returns, 0.99).Mul(rm.portfolio.TotalValue))
+	rm.riskMetrics.CVaR95 = numeric.Round(ExpectedShortfall(rm.returns, 0.95).Mul(rm.portfolio.TotalValue))
+	rm.riskMetrics.CVaR99 = numeric.Round(ExpectedShortfall(rm.returns, 0.99).Mul(rm.portfolio.TotalValue))
+	
 	// Update metrics
 	if rm.metrics != nil {
 		rm.metrics.RecordPortfolioValue(rm.portfolio.TotalValue.InexactFloat64())
@@ -422,6 +477,17 @@ func (rm *Manager) calculateRiskMetrics() {
 	}
 }
 
+// recordReturn adds the return since the previous portfolio value to the history
+func (rm *Manager) recordReturn(value decimal.Decimal) {
+	if rm.lastValue.IsPositive() {
+		rm.returns = append(rm.returns, numeric.FractionChange(rm.lastValue, value))
+		if len(rm.returns) > maxReturnHistory {
+			rm.returns = rm.returns[len(rm.returns)-maxReturnHistory:]
+		}
+	}
+	rm.lastValue = value
+}
+
 func (rm *Manager) checkPortfolioRisk() {
 	events, err := rm.CheckPortfolioRisk()
 	if err != nil {
@@ -503,8 +569,12 @@ func (rm *Manager) riskMonitoringLoop() {
 	for {
 		select {
 		case <-ticker.C:
+			rm.mu.Lock()
 			rm.calculateRiskMetrics()
+			rm.recordEquitySnapshot(time.Now())
+			rm.mu.Unlock()
 			rm.checkPortfolioRisk()
+			rm.checkPositionAges(time.Now())
 		case <-rm.ctx.Done():
 			return
 		}
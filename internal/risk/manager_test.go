@@ -0,0 +1,149 @@
+package risk
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// copyTestManager returns a risk manager holding one BTC position
+func copyTestManager(t *testing.T) *Manager {
+	t.Helper()
+
+	rm := NewManager(DefaultRiskConfig(), nil)
+	err := rm.UpdatePortfolio(&Portfolio{
+		CashBalance: decimal.NewFromInt(50000),
+		TotalValue:  decimal.NewFromInt(100000),
+		Positions:   make(map[string]*Position),
+	})
+	if err != nil {
+		t.Fatalf("UpdatePortfolio: %v", err)
+	}
+	err = rm.AddPosition(&Position{
+		Symbol:      "BTC/USD",
+		Exchange:    "binance",
+		Side:        "LONG",
+		Quantity:    decimal.NewFromInt(1),
+		EntryPrice:  decimal.NewFromInt(50000),
+		MarketValue: decimal.NewFromInt(50000),
+	})
+	if err != nil {
+		t.Fatalf("AddPosition: %v", err)
+	}
+	return rm
+}
+
+func TestGetPortfolioReturnsCopy(t *testing.T) {
+	rm := copyTestManager(t)
+
+	portfolio := rm.GetPortfolio()
+	portfolio.CashBalance = decimal.Zero
+	portfolio.Positions["binance:BTC/USD"].Quantity = decimal.NewFromInt(99)
+	portfolio.Positions["binance:ETH/USD"] = &Position{Symbol: "ETH/USD", Exchange: "binance"}
+	delete(portfolio.Positions, "binance:BTC/USD")
+
+	again := rm.GetPortfolio()
+	if !again.CashBalance.Equal(decimal.NewFromInt(50000)) {
+		t.Errorf("cash balance = %s, want 50000", again.CashBalance)
+	}
+	if len(again.Positions) != 1 {
+		t.Fatalf("positions = %d, want 1", len(again.Positions))
+	}
+	if qty := again.Positions["binance:BTC/USD"].Quantity; !qty.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("BTC quantity = %s, want 1", qty)
+	}
+}
+
+func TestGetPositionsReturnsCopy(t *testing.T) {
+	rm := copyTestManager(t)
+
+	positions := rm.GetPositions()
+	positions["binance:BTC/USD"].MarketValue = decimal.Zero
+	delete(positions, "binance:BTC/USD")
+
+	again := rm.GetPositions()
+	position, ok := again["binance:BTC/USD"]
+	if !ok {
+		t.Fatal("BTC position removed from manager through returned map")
+	}
+	if !position.MarketValue.Equal(decimal.NewFromInt(50000)) {
+		t.Errorf("BTC market value = %s, want 50000", position.MarketValue)
+	}
+}
+
+func TestUpdatePortfolioKeepsOwnCopy(t *testing.T) {
+	rm := copyTestManager(t)
+
+	portfolio := rm.GetPortfolio()
+	if err := rm.UpdatePortfolio(portfolio); err != nil {
+		t.Fatalf("UpdatePortfolio: %v", err)
+	}
+	portfolio.Positions["binance:BTC/USD"].Quantity = decimal.NewFromInt(5)
+	portfolio.CashBalance = decimal.NewFromInt(1)
+
+	again := rm.GetPortfolio()
+	if !again.CashBalance.Equal(decimal.NewFromInt(50000)) {
+		t.Errorf("cash balance = %s, want 50000", again.CashBalance)
+	}
+	if qty := again.Positions["binance:BTC/USD"].Quantity; !qty.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("BTC quantity = %s, want 1", qty)
+	}
+
+	if err := rm.UpdatePortfolio(nil); err == nil {
+		t.Error("expected error for nil portfolio")
+	}
+}
+
+// TestPortfolioConcurrentAccess exercises readers and writers together; run with -race
+func TestPortfolioConcurrentAccess(t *testing.T) {
+	config := DefaultRiskConfig()
+	config.UpdateInterval = time.Millisecond
+	rm := NewManager(config, nil)
+	if err := rm.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer rm.Stop()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				symbol := fmt.Sprintf("SYM%d", i%5)
+				rm.AddPosition(&Position{
+					Symbol:      symbol,
+					Exchange:    fmt.Sprintf("ex%d", w),
+					Quantity:    decimal.NewFromInt(int64(i)),
+					MarketValue: decimal.NewFromInt(int64(i * 10)),
+				})
+				rm.UpdatePosition(symbol, fmt.Sprintf("ex%d", w), decimal.NewFromInt(int64(i)))
+				if i%20 == 0 {
+					rm.RemovePosition(symbol, fmt.Sprintf("ex%d", w))
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				portfolio := rm.GetPortfolio()
+				total := portfolio.CashBalance
+				for _, position := range portfolio.Positions {
+					total = total.Add(position.MarketValue)
+					position.Quantity = decimal.Zero
+				}
+				for _, position := range rm.GetPositions() {
+					position.MarketValue = decimal.Zero
+				}
+				_ = rm.GetRiskMetrics().PortfolioValue
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -4,6 +4,7 @@ import (
 	"math"
 
 	"github.com/shopspring/decimal"
+	"velocimex/internal/numeric"
 )
 
 // PositionSizingCalculator calculates optimal position sizes
@@ -288,7 +289,7 @@ func (psc *PositionSizingCalculator) CalculateMaxDrawdown(
 		return decimal.Zero
 	}
 	
-	drawdown := peakValue.Sub(portfolio.TotalValue).Div(peakValue)
+	drawdown := numeric.FractionChange(peakValue, portfolio.TotalValue).Neg()
 	
 	if drawdown.LessThan(decimal.Zero) {
 		return decimal.Zero
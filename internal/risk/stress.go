@@ -0,0 +1,197 @@
+package risk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"velocimex/internal/numeric"
+)
+
+// StressScenario is a set of hypothetical price moves, as fractions of the
+// current price (-0.2 is a 20% fall). A symbol's own shock takes precedence
+// over the uniform shock applied to every other symbol.
+type StressScenario struct {
+	Shocks  map[string]decimal.Decimal `json:"shocks,omitempty"`
+	Uniform decimal.Decimal            `json:"uniform"`
+}
+
+// Validate checks that no shock would take a price below zero
+func (s StressScenario) Validate() error {
+	minShock := decimal.NewFromInt(-1)
+	if s.Uniform.LessThan(minShock) {
+		return fmt.Errorf("uniform shock %s is below -1", s.Uniform.String())
+	}
+	for symbol, shock := range s.Shocks {
+		if shock.LessThan(minShock) {
+			return fmt.Errorf("shock %s for %s is below -1", shock.String(), symbol)
+		}
+	}
+	return nil
+}
+
+// shock returns the price move applied to a symbol
+func (s StressScenario) shock(symbol string) decimal.Decimal {
+	if shock, ok := s.Shocks[symbol]; ok {
+		return shock
+	}
+	return s.Uniform
+}
+
+// PositionStress is the projected effect of a scenario on one position
+type PositionStress struct {
+	Symbol         string          `json:"symbol"`
+	Exchange       string          `json:"exchange"`
+	Side           string          `json:"side"`
+	Shock          decimal.Decimal `json:"shock"`
+	CurrentPrice   decimal.Decimal `json:"current_price"`
+	ShockedPrice   decimal.Decimal `json:"shocked_price"`
+	ProjectedValue decimal.Decimal `json:"projected_value"`
+	PnL            decimal.Decimal `json:"pnl"`
+}
+
+// LimitBreach is a risk limit the portfolio would breach under a scenario.
+// Type matches the risk event the breach would raise.
+type LimitBreach struct {
+	Type      string          `json:"type"`
+	Symbol    string          `json:"symbol,omitempty"`
+	Exchange  string          `json:"exchange,omitempty"`
+	Value     decimal.Decimal `json:"value"`
+	Threshold decimal.Decimal `json:"threshold"`
+}
+
+// StressResult is the projected portfolio under a stress scenario
+type StressResult struct {
+	CurrentValue   decimal.Decimal  `json:"current_value"`
+	ProjectedValue decimal.Decimal  `json:"projected_value"`
+	PnL            decimal.Decimal  `json:"pnl"`
+	Positions      []PositionStress `json:"positions"`
+	Breaches       []LimitBreach    `json:"breaches"`
+}
+
+// Breached reports whether the scenario breaches any risk limit
+func (r *StressResult) Breached() bool {
+	return len(r.Breaches) > 0
+}
+
+// StressTest applies a scenario to the current positions and reports the
+// projected portfolio value and PnL and the limits that would be breached.
+// The portfolio itself is not changed.
+func (rm *Manager) StressTest(scenario StressScenario) (*StressResult, error) {
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	return stressPortfolio(rm.portfolio, rm.config.AlertThresholds, scenario), nil
+}
+
+// stressPortfolio projects a portfolio under a scenario and checks the
+// projection against the limits
+func stressPortfolio(portfolio *Portfolio, limits RiskLimits, scenario StressScenario) *StressResult {
+	result := &StressResult{
+		CurrentValue: portfolio.TotalValue,
+		Positions:    make([]PositionStress, 0, len(portfolio.Positions)),
+		Breaches:     make([]LimitBreach, 0),
+	}
+
+	pnl := decimal.Zero
+	for _, position := range portfolio.Positions {
+		shock := scenario.shock(position.Symbol)
+		value := position.MarketValue.Abs()
+
+		// Shorts gain when the price falls
+		positionPNL := value.Mul(shock)
+		if strings.EqualFold(position.Side, "SHORT") {
+			positionPNL = positionPNL.Neg()
+		}
+		pnl = pnl.Add(positionPNL)
+
+		result.Positions = append(result.Positions, PositionStress{
+			Symbol:         position.Symbol,
+			Exchange:       position.Exchange,
+			Side:           position.Side,
+			Shock:          shock,
+			CurrentPrice:   position.CurrentPrice,
+			ShockedPrice:   numeric.Round(position.CurrentPrice.Mul(decimal.NewFromInt(1).Add(shock))),
+			ProjectedValue: numeric.Round(value.Mul(decimal.NewFromInt(1).Add(shock))),
+			PnL:            numeric.Round(positionPNL),
+		})
+	}
+	sort.Slice(result.Positions, func(i, j int) bool {
+		if result.Positions[i].Exchange != result.Positions[j].Exchange {
+			return result.Positions[i].Exchange < result.Positions[j].Exchange
+		}
+		return result.Positions[i].Symbol < result.Positions[j].Symbol
+	})
+
+	result.PnL = numeric.Round(pnl)
+	result.ProjectedValue = numeric.Round(portfolio.TotalValue.Add(pnl))
+	result.Breaches = stressBreaches(portfolio, limits, result)
+	return result
+}
+
+// stressBreaches checks a projected portfolio against the risk limits
+func stressBreaches(portfolio *Portfolio, limits RiskLimits, result *StressResult) []LimitBreach {
+	breaches := make([]LimitBreach, 0)
+
+	// The scenario's loss counts towards today's
+	dailyPNL := portfolio.DailyPNL.Add(result.PnL)
+	if limits.MaxDailyLoss.IsPositive() && dailyPNL.LessThan(limits.MaxDailyLoss.Neg()) {
+		breaches = append(breaches, LimitBreach{
+			Type:      "DAILY_LOSS_EXCEEDED",
+			Value:     dailyPNL,
+			Threshold: limits.MaxDailyLoss.Neg(),
+		})
+	}
+
+	// Drawdown from the current portfolio value
+	if limits.MaxDrawdown.IsPositive() && result.PnL.IsNegative() && portfolio.TotalValue.IsPositive() {
+		drawdown := numeric.Round(result.PnL.Neg().Div(portfolio.TotalValue))
+		if drawdown.GreaterThan(limits.MaxDrawdown) {
+			breaches = append(breaches, LimitBreach{
+				Type:      "DRAWDOWN_EXCEEDED",
+				Value:     drawdown,
+				Threshold: limits.MaxDrawdown,
+			})
+		}
+	}
+
+	if limits.MaxPortfolioValue.IsPositive() && result.ProjectedValue.GreaterThan(limits.MaxPortfolioValue) {
+		breaches = append(breaches, LimitBreach{
+			Type:      "PORTFOLIO_VALUE_EXCEEDED",
+			Value:     result.ProjectedValue,
+			Threshold: limits.MaxPortfolioValue,
+		})
+	}
+
+	for _, position := range result.Positions {
+		if limits.MaxPositionSize.IsPositive() && position.ProjectedValue.GreaterThan(limits.MaxPositionSize) {
+			breaches = append(breaches, LimitBreach{
+				Type:      "POSITION_SIZE_EXCEEDED",
+				Symbol:    position.Symbol,
+				Exchange:  position.Exchange,
+				Value:     position.ProjectedValue,
+				Threshold: limits.MaxPositionSize,
+			})
+		}
+
+		if limits.MaxConcentration.IsPositive() && result.ProjectedValue.IsPositive() {
+			concentration := numeric.Round(position.ProjectedValue.Div(result.ProjectedValue))
+			if concentration.GreaterThan(limits.MaxConcentration) {
+				breaches = append(breaches, LimitBreach{
+					Type:      "CONCENTRATION_RISK",
+					Symbol:    position.Symbol,
+					Exchange:  position.Exchange,
+					Value:     concentration,
+					Threshold: limits.MaxConcentration,
+				})
+			}
+		}
+	}
+
+	return breaches
+}
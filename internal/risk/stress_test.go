@@ -0,0 +1,117 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// stressManager returns a risk manager holding a 30000 long and a 10000 short
+// in a 100000 portfolio that has already lost 1500 today
+func stressManager() *Manager {
+	config := DefaultRiskConfig()
+	config.AlertThresholds.MaxPositionSize = decimal.NewFromInt(25000)
+
+	rm := NewManager(config, nil)
+	rm.portfolio = &Portfolio{
+		TotalValue:  decimal.NewFromInt(100000),
+		CashBalance: decimal.NewFromInt(60000),
+		DailyPNL:    decimal.NewFromInt(-1500),
+		Positions: map[string]*Position{
+			"binance:BTC/USD": {Symbol: "BTC/USD", Exchange: "binance", Side: "LONG", Quantity: decimal.NewFromFloat(0.6),
+				CurrentPrice: decimal.NewFromInt(50000), MarketValue: decimal.NewFromInt(30000)},
+			"binance:ETH/USD": {Symbol: "ETH/USD", Exchange: "binance", Side: "SHORT", Quantity: decimal.NewFromInt(5),
+				CurrentPrice: decimal.NewFromInt(2000), MarketValue: decimal.NewFromInt(10000)},
+		},
+	}
+	return rm
+}
+
+func breachTypes(result *StressResult) map[string]bool {
+	types := make(map[string]bool)
+	for _, breach := range result.Breaches {
+		types[breach.Type] = true
+	}
+	return types
+}
+
+func TestStressTestUniformShock(t *testing.T) {
+	rm := stressManager()
+
+	result, err := rm.StressTest(StressScenario{Uniform: decimal.NewFromFloat(-0.2)})
+	if err != nil {
+		t.Fatalf("StressTest error: %v", err)
+	}
+
+	// The long loses 6000 and the short gains 2000
+	if !result.PnL.Equal(decimal.NewFromInt(-4000)) {
+		t.Errorf("PnL = %s, want -4000", result.PnL)
+	}
+	if !result.ProjectedValue.Equal(decimal.NewFromInt(96000)) {
+		t.Errorf("projected value = %s, want 96000", result.ProjectedValue)
+	}
+	if len(result.Positions) != 2 {
+		t.Fatalf("got %d positions, want 2", len(result.Positions))
+	}
+	btc := result.Positions[0]
+	if btc.Symbol != "BTC/USD" || !btc.ShockedPrice.Equal(decimal.NewFromInt(40000)) || !btc.PnL.Equal(decimal.NewFromInt(-6000)) {
+		t.Errorf("BTC stress = %+v, want shocked price 40000 and PnL -6000", btc)
+	}
+	if eth := result.Positions[1]; !eth.PnL.Equal(decimal.NewFromInt(2000)) {
+		t.Errorf("ETH PnL = %s, want 2000", eth.PnL)
+	}
+
+	// -1500 - 4000 breaches the 5000 daily loss limit, and the 24000 long is
+	// 25% of the projected portfolio against a 20% concentration limit. The 4%
+	// drawdown and the position sizes stay within limits.
+	if !result.Breached() {
+		t.Fatal("expected limit breaches")
+	}
+	breaches := breachTypes(result)
+	want := map[string]bool{"DAILY_LOSS_EXCEEDED": true, "CONCENTRATION_RISK": true}
+	if len(breaches) != len(want) {
+		t.Errorf("breaches = %v, want %v", breaches, want)
+	}
+	for breach := range want {
+		if !breaches[breach] {
+			t.Errorf("missing breach %s, got %v", breach, breaches)
+		}
+	}
+
+	// The portfolio itself is untouched
+	if portfolio := rm.GetPortfolio(); !portfolio.TotalValue.Equal(decimal.NewFromInt(100000)) {
+		t.Errorf("portfolio value changed to %s", portfolio.TotalValue)
+	}
+}
+
+func TestStressTestSymbolShock(t *testing.T) {
+	rm := stressManager()
+
+	// The ETH shock overrides the uniform one, so only BTC moves
+	result, err := rm.StressTest(StressScenario{
+		Uniform: decimal.NewFromFloat(-0.2),
+		Shocks:  map[string]decimal.Decimal{"ETH/USD": decimal.Zero},
+	})
+	if err != nil {
+		t.Fatalf("StressTest error: %v", err)
+	}
+	if !result.PnL.Equal(decimal.NewFromInt(-6000)) {
+		t.Errorf("PnL = %s, want -6000", result.PnL)
+	}
+
+	// A fall in ETH alone profits the short and leaves the daily loss within limits
+	result, err = rm.StressTest(StressScenario{Shocks: map[string]decimal.Decimal{"ETH/USD": decimal.NewFromFloat(-0.1)}})
+	if err != nil {
+		t.Fatalf("StressTest error: %v", err)
+	}
+	if !result.PnL.Equal(decimal.NewFromInt(1000)) {
+		t.Errorf("PnL = %s, want 1000", result.PnL)
+	}
+	if breaches := breachTypes(result); breaches["DAILY_LOSS_EXCEEDED"] || breaches["DRAWDOWN_EXCEEDED"] {
+		t.Errorf("unexpected loss breaches %v", breaches)
+	}
+
+	if _, err := rm.StressTest(StressScenario{Uniform: decimal.NewFromFloat(-1.5)}); err == nil {
+		t.Error("expected error for a shock below -1")
+	}
+}
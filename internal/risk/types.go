@@ -41,6 +41,10 @@ type Position struct {
 	MarketValue  decimal.Decimal `json:"market_value"`
 	UnrealizedPNL decimal.Decimal `json:"unrealized_pnl"`
 	RealizedPNL  decimal.Decimal `json:"realized_pnl"`
+	QuoteCurrency string         `json:"quote_currency,omitempty"` // Currency prices are quoted in; empty takes it from the symbol
+	// Price levels at which the position is taken off; zero leaves the level unset
+	TakeProfit   decimal.Decimal `json:"take_profit"`
+	StopLoss     decimal.Decimal `json:"stop_loss"`
 	CreatedAt    time.Time       `json:"created_at"`
 	UpdatedAt    time.Time       `json:"updated_at"`
 }
@@ -54,9 +58,36 @@ type Portfolio struct {
 	RealizedPNL    decimal.Decimal `json:"realized_pnl"`
 	DailyPNL       decimal.Decimal `json:"daily_pnl"`
 	Positions      map[string]*Position `json:"positions"`
+	BaseCurrency   string          `json:"base_currency,omitempty"`
+	// Positions left out of the totals for want of an FX rate into the base currency
+	Unconverted    []string        `json:"unconverted,omitempty"`
 	LastUpdated    time.Time       `json:"last_updated"`
 }
 
+// Clone returns a deep copy of the portfolio, including its positions
+func (p *Portfolio) Clone() *Portfolio {
+	if p == nil {
+		return nil
+	}
+	clone := *p
+	clone.Positions = clonePositions(p.Positions)
+	clone.Unconverted = append([]string(nil), p.Unconverted...)
+	return &clone
+}
+
+// clonePositions returns a copy of a position map with every position copied
+func clonePositions(positions map[string]*Position) map[string]*Position {
+	clone := make(map[string]*Position, len(positions))
+	for key, position := range positions {
+		if position == nil {
+			continue
+		}
+		copied := *position
+		clone[key] = &copied
+	}
+	return clone
+}
+
 // RiskLimits represents risk management limits
 type RiskLimits struct {
 	MaxPositionSize     decimal.Decimal `json:"max_position_size"`
@@ -67,6 +98,11 @@ type RiskLimits struct {
 	MaxLeverage         decimal.Decimal `json:"max_leverage"`
 	StopLossPercentage  decimal.Decimal `json:"stop_loss_percentage"`
 	TakeProfitPercentage decimal.Decimal `json:"take_profit_percentage"`
+	// Per-exchange caps keyed by exchange; exchanges without an entry are not limited
+	MaxExchangeExposure  map[string]decimal.Decimal `json:"max_exchange_exposure"`
+	MaxExchangePositions map[string]int             `json:"max_exchange_positions"`
+	// MaxPositionAge is how long a position may be held before it is flagged as possibly forgotten; zero disables it
+	MaxPositionAge      time.Duration   `json:"max_position_age"`
 }
 
 // RiskMetrics represents calculated risk metrics
@@ -77,6 +113,8 @@ type RiskMetrics struct {
 	ConcentrationRisk  decimal.Decimal `json:"concentration_risk"`
 	VaR95             decimal.Decimal `json:"var_95"` // Value at Risk 95%
 	VaR99             decimal.Decimal `json:"var_99"` // Value at Risk 99%
+	CVaR95            decimal.Decimal `json:"cvar_95"` // Expected shortfall beyond the 95% VaR
+	CVaR99            decimal.Decimal `json:"cvar_99"` // Expected shortfall beyond the 99% VaR
 	MaxDrawdown       decimal.Decimal `json:"max_drawdown"`
 	SharpeRatio       decimal.Decimal `json:"sharpe_ratio"`
 	SortinoRatio      decimal.Decimal `json:"sortino_ratio"`
@@ -92,6 +130,7 @@ type RiskConfig struct {
 	Enabled             bool            `json:"enabled"`
 	UpdateInterval      time.Duration   `json:"update_interval"`
 	AlertThresholds     RiskLimits      `json:"alert_thresholds"`
+	// AutoStopLoss and AutoTakeProfit liquidate a position through the liquidation handler when its level is breached
 	AutoStopLoss        bool            `json:"auto_stop_loss"`
 	AutoTakeProfit      bool            `json:"auto_take_profit"`
 	MaxOpenPositions    int             `json:"max_open_positions"`
@@ -99,6 +138,14 @@ type RiskConfig struct {
 	DefaultPositionSize decimal.Decimal `json:"default_position_size"`
 	RiskFreeRate        decimal.Decimal `json:"risk_free_rate"`
 	LookbackPeriod      int             `json:"lookback_period"` // Days for historical calculations
+	// BaseCurrency is the currency the portfolio is valued in; empty sums positions unconverted
+	BaseCurrency        string          `json:"base_currency"`
+	// FXRates converts one unit of each currency into the base currency, unless SetFXRateSource replaces them
+	FXRates             map[string]decimal.Decimal `json:"fx_rates"`
+	// EquitySnapshotInterval is how often the portfolio value is recorded for the equity curve; zero disables it
+	EquitySnapshotInterval time.Duration `json:"equity_snapshot_interval"`
+	// EquityRetention is how long equity snapshots are kept; zero keeps them up to the snapshot cap
+	EquityRetention     time.Duration   `json:"equity_retention"`
 }
 
 // DefaultRiskConfig returns default risk management configuration
@@ -123,6 +170,8 @@ func DefaultRiskConfig() RiskConfig {
 		DefaultPositionSize: decimal.NewFromFloat(0.02), // 2% of portfolio
 		RiskFreeRate:        decimal.NewFromFloat(0.02), // 2% risk-free rate
 		LookbackPeriod:      30, // 30 days
+		EquitySnapshotInterval: 1 * time.Minute,
+		EquityRetention:     7 * 24 * time.Hour,
 	}
 }
 
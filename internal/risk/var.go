@@ -0,0 +1,70 @@
+package risk
+
+import (
+	"math"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxReturnHistory bounds the portfolio returns kept for historical VaR
+const maxReturnHistory = 1000
+
+// HistoricalVaR returns the Value at Risk of a series of period returns at the
+// given confidence level (0.95 for 95%), as a positive loss fraction. It is the
+// loss at the cutoff beyond which the worst (1-confidence) of returns fall.
+// Fewer than two returns, or no loss at the cutoff, yield zero.
+func HistoricalVaR(returns []decimal.Decimal, confidenceLevel float64) decimal.Decimal {
+	sorted, tail := returnTail(returns, confidenceLevel)
+	if sorted == nil {
+		return decimal.Zero
+	}
+	return lossOf(sorted[tail])
+}
+
+// ExpectedShortfall returns the Conditional Value at Risk of a series of period
+// returns at the given confidence level, as a positive loss fraction: the average
+// loss of the returns beyond the VaR cutoff. It is never less than the VaR.
+func ExpectedShortfall(returns []decimal.Decimal, confidenceLevel float64) decimal.Decimal {
+	sorted, tail := returnTail(returns, confidenceLevel)
+	if sorted == nil {
+		return decimal.Zero
+	}
+
+	sum := decimal.Zero
+	for _, ret := range sorted[:tail] {
+		sum = sum.Add(ret)
+	}
+	return lossOf(sum.Div(decimal.NewFromInt(int64(tail))))
+}
+
+// returnTail sorts returns from worst to best and returns how many of them lie
+// beyond the VaR cutoff. The tail holds at least one return and the cutoff
+// return itself is sorted[tail].
+func returnTail(returns []decimal.Decimal, confidenceLevel float64) ([]decimal.Decimal, int) {
+	if len(returns) < 2 || confidenceLevel <= 0 || confidenceLevel >= 1 {
+		return nil, 0
+	}
+
+	sorted := make([]decimal.Decimal, len(returns))
+	copy(sorted, returns)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].LessThan(sorted[j]) })
+
+	// Round to absorb float error in (1-confidence), e.g. 0.05*100 = 4.999...
+	tail := int(math.Floor((1-confidenceLevel)*float64(len(sorted)) + 1e-9))
+	if tail < 1 {
+		tail = 1
+	}
+	if tail >= len(sorted) {
+		tail = len(sorted) - 1
+	}
+	return sorted, tail
+}
+
+// lossOf converts a return to a positive loss; gains are no loss
+func lossOf(ret decimal.Decimal) decimal.Decimal {
+	if ret.IsNegative() {
+		return ret.Neg()
+	}
+	return decimal.Zero
+}
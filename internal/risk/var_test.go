@@ -0,0 +1,83 @@
+package risk
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// knownReturns returns 100 period returns: losses of 1% through 10% and 90 gains of 1%, shuffled
+func knownReturns() []decimal.Decimal {
+	returns := make([]decimal.Decimal, 0, 100)
+	for i := 1; i <= 10; i++ {
+		returns = append(returns, decimal.NewFromFloat(-0.01*float64(i)))
+	}
+	for i := 0; i < 90; i++ {
+		returns = append(returns, decimal.NewFromFloat(0.01))
+	}
+	rand.New(rand.NewSource(1)).Shuffle(len(returns), func(i, j int) {
+		returns[i], returns[j] = returns[j], returns[i]
+	})
+	return returns
+}
+
+func TestHistoricalVaRAndExpectedShortfall(t *testing.T) {
+	returns := knownReturns()
+
+	tests := []struct {
+		confidence float64
+		wantVaR    string
+		wantCVaR   string
+	}{
+		// The five worst returns (-10%..-6%) lie beyond the 95% cutoff at -5%
+		{0.95, "0.05", "0.08"},
+		// Only -10% lies beyond the 99% cutoff at -9%
+		{0.99, "0.09", "0.1"},
+	}
+
+	for _, tt := range tests {
+		gotVaR := HistoricalVaR(returns, tt.confidence)
+		gotCVaR := ExpectedShortfall(returns, tt.confidence)
+
+		if !gotVaR.Equal(decimal.RequireFromString(tt.wantVaR)) {
+			t.Errorf("HistoricalVaR(%v) = %s, want %s", tt.confidence, gotVaR, tt.wantVaR)
+		}
+		if !gotCVaR.Equal(decimal.RequireFromString(tt.wantCVaR)) {
+			t.Errorf("ExpectedShortfall(%v) = %s, want %s", tt.confidence, gotCVaR, tt.wantCVaR)
+		}
+		if !gotCVaR.GreaterThan(gotVaR) {
+			t.Errorf("ExpectedShortfall(%v) = %s, want more than VaR %s", tt.confidence, gotCVaR, gotVaR)
+		}
+	}
+}
+
+func TestHistoricalVaRWithoutLosses(t *testing.T) {
+	tests := []struct {
+		name    string
+		returns []decimal.Decimal
+	}{
+		{"no returns", nil},
+		{"single return", []decimal.Decimal{decimal.NewFromFloat(-0.5)}},
+		{"only gains", []decimal.Decimal{decimal.NewFromFloat(0.01), decimal.NewFromFloat(0.02), decimal.NewFromFloat(0.03)}},
+	}
+
+	for _, tt := range tests {
+		if got := HistoricalVaR(tt.returns, 0.95); !got.IsZero() {
+			t.Errorf("%s: HistoricalVaR = %s, want 0", tt.name, got)
+		}
+		if got := ExpectedShortfall(tt.returns, 0.95); !got.IsZero() {
+			t.Errorf("%s: ExpectedShortfall = %s, want 0", tt.name, got)
+		}
+	}
+}
+
+func TestHistoricalVaRDoesNotReorderInput(t *testing.T) {
+	returns := []decimal.Decimal{decimal.NewFromFloat(0.02), decimal.NewFromFloat(-0.03), decimal.NewFromFloat(0.01)}
+	HistoricalVaR(returns, 0.95)
+	ExpectedShortfall(returns, 0.95)
+
+	if !returns[0].Equal(decimal.NewFromFloat(0.02)) || !returns[1].Equal(decimal.NewFromFloat(-0.03)) {
+		t.Errorf("input returns were reordered: %v", returns)
+	}
+}
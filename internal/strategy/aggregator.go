@@ -0,0 +1,151 @@
+package strategy
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SignalWindow is the signals collected for one exchange and symbol since the
+// window opened
+type SignalWindow struct {
+	Opened  time.Time `json:"opened"`
+	Signals []*Signal `json:"signals"`
+}
+
+// SignalAggregator collects signals per exchange and symbol over a window and
+// nets each window into a single signal, so strategies that change their mind
+// within the window do not churn orders. Opposing signals of equal size
+// cancel out. Time is passed in by the caller, so the aggregator works on
+// both the live and the simulated backtest clock. It is not safe for
+// concurrent use.
+type SignalAggregator struct {
+	window  time.Duration
+	pending map[string]*SignalWindow
+}
+
+// NewSignalAggregator creates an aggregator netting signals over window
+func NewSignalAggregator(window time.Duration) *SignalAggregator {
+	return &SignalAggregator{
+		window:  window,
+		pending: make(map[string]*SignalWindow),
+	}
+}
+
+// Add collects signals generated at now and returns the netted signals of the
+// windows that have closed. A window opens with the first signal for its
+// exchange and symbol and closes window later.
+func (a *SignalAggregator) Add(now time.Time, signals ...*Signal) []*Signal {
+	due := a.Due(now)
+	for _, signal := range signals {
+		key := signal.Exchange + ":" + signal.Symbol
+		pending, ok := a.pending[key]
+		if !ok {
+			pending = &SignalWindow{Opened: now}
+			a.pending[key] = pending
+		}
+		pending.Signals = append(pending.Signals, signal)
+	}
+	return due
+}
+
+// Due returns the netted signals of the windows that have closed by now
+func (a *SignalAggregator) Due(now time.Time) []*Signal {
+	return a.release(func(window *SignalWindow) bool {
+		return !now.Before(window.Opened.Add(a.window))
+	})
+}
+
+// Flush closes every window and returns the netted signals
+func (a *SignalAggregator) Flush() []*Signal {
+	return a.release(func(*SignalWindow) bool { return true })
+}
+
+// Pending returns the open windows, keyed exchange:symbol
+func (a *SignalAggregator) Pending() map[string]SignalWindow {
+	pending := make(map[string]SignalWindow, len(a.pending))
+	for key, window := range a.pending {
+		pending[key] = SignalWindow{Opened: window.Opened, Signals: append([]*Signal(nil), window.Signals...)}
+	}
+	return pending
+}
+
+// Restore replaces the open windows, e.g. with those saved by Pending
+func (a *SignalAggregator) Restore(pending map[string]SignalWindow) {
+	a.pending = make(map[string]*SignalWindow, len(pending))
+	for key, window := range pending {
+		window := window
+		a.pending[key] = &window
+	}
+}
+
+// release removes the windows matching closed and returns their netted
+// signals, ordered by key
+func (a *SignalAggregator) release(closed func(*SignalWindow) bool) []*Signal {
+	keys := make([]string, 0, len(a.pending))
+	for key, window := range a.pending {
+		if closed(window) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var netted []*Signal
+	for _, key := range keys {
+		if signal := netSignals(a.pending[key].Signals); signal != nil {
+			netted = append(netted, signal)
+		}
+		delete(a.pending, key)
+	}
+	return netted
+}
+
+// netSignals nets the signals for one exchange and symbol into a single
+// signal on the side with the larger total, priced at the quantity-weighted
+// average of that side's signals. It returns nil when the sides cancel out.
+func netSignals(signals []*Signal) *Signal {
+	if len(signals) == 0 {
+		return nil
+	}
+
+	var buyQty, sellQty, buyNotional, sellNotional decimal.Decimal
+	var lastBuy, lastSell *Signal
+	for _, signal := range signals {
+		notional := signal.Quantity.Mul(signal.Price)
+		if signal.Side == "SELL" {
+			sellQty = sellQty.Add(signal.Quantity)
+			sellNotional = sellNotional.Add(notional)
+			lastSell = signal
+		} else {
+			buyQty = buyQty.Add(signal.Quantity)
+			buyNotional = buyNotional.Add(notional)
+			lastBuy = signal
+		}
+	}
+
+	net := buyQty.Sub(sellQty)
+	if net.IsZero() {
+		return nil
+	}
+
+	side, quantity, notional, last := "BUY", buyQty, buyNotional, lastBuy
+	if net.IsNegative() {
+		side, quantity, notional, last = "SELL", sellQty, sellNotional, lastSell
+	}
+
+	metadata := make(map[string]interface{}, len(last.Metadata)+1)
+	for key, value := range last.Metadata {
+		metadata[key] = value
+	}
+	metadata["aggregated_signals"] = len(signals)
+
+	return &Signal{
+		Symbol:   last.Symbol,
+		Exchange: last.Exchange,
+		Side:     side,
+		Quantity: net.Abs(),
+		Price:    notional.Div(quantity),
+		Metadata: metadata,
+	}
+}
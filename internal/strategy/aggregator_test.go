@@ -0,0 +1,99 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSignal(exchange, symbol, side string, quantity, price int64) *Signal {
+	return &Signal{
+		Symbol:   symbol,
+		Exchange: exchange,
+		Side:     side,
+		Quantity: decimal.NewFromInt(quantity),
+		Price:    decimal.NewFromInt(price),
+	}
+}
+
+func TestSignalAggregatorNetsOpposingSignals(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	aggregator := NewSignalAggregator(time.Minute)
+
+	// Equal and opposite signals within the window cancel out
+	assert.Empty(t, aggregator.Add(start, testSignal("binance", "BTC/USD", "BUY", 2, 100)))
+	assert.Empty(t, aggregator.Add(start.Add(30*time.Second), testSignal("binance", "BTC/USD", "SELL", 2, 101)))
+	assert.Empty(t, aggregator.Due(start.Add(time.Minute)))
+	assert.Empty(t, aggregator.Pending())
+
+	// Unequal ones net to the difference on the larger side
+	aggregator.Add(start, testSignal("binance", "BTC/USD", "BUY", 1, 100))
+	aggregator.Add(start.Add(10*time.Second), testSignal("binance", "BTC/USD", "SELL", 3, 102))
+	netted := aggregator.Due(start.Add(time.Minute))
+	require.Len(t, netted, 1)
+	assert.Equal(t, "SELL", netted[0].Side)
+	assert.True(t, netted[0].Quantity.Equal(decimal.NewFromInt(2)))
+	assert.True(t, netted[0].Price.Equal(decimal.NewFromInt(102)))
+	assert.Equal(t, 2, netted[0].Metadata["aggregated_signals"])
+}
+
+func TestSignalAggregatorSumsSameSide(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	aggregator := NewSignalAggregator(time.Minute)
+
+	aggregator.Add(start, testSignal("binance", "BTC/USD", "BUY", 1, 100))
+	aggregator.Add(start.Add(20*time.Second), testSignal("binance", "BTC/USD", "BUY", 3, 104))
+
+	// Nothing is released before the window closes
+	assert.Empty(t, aggregator.Due(start.Add(59*time.Second)))
+
+	netted := aggregator.Due(start.Add(time.Minute))
+	require.Len(t, netted, 1)
+	assert.Equal(t, "BUY", netted[0].Side)
+	assert.True(t, netted[0].Quantity.Equal(decimal.NewFromInt(4)))
+	// Quantity-weighted average of 1 at 100 and 3 at 104
+	assert.True(t, netted[0].Price.Equal(decimal.NewFromInt(103)), netted[0].Price.String())
+}
+
+func TestSignalAggregatorWindowsPerSymbol(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	aggregator := NewSignalAggregator(time.Minute)
+
+	aggregator.Add(start, testSignal("binance", "BTC/USD", "BUY", 1, 100))
+	aggregator.Add(start.Add(30*time.Second),
+		testSignal("binance", "ETH/USD", "SELL", 5, 10),
+		testSignal("coinbase", "BTC/USD", "SELL", 1, 100))
+
+	// Only the first window has closed; the signal arriving with it starts a new one
+	netted := aggregator.Add(start.Add(time.Minute), testSignal("binance", "BTC/USD", "SELL", 1, 100))
+	require.Len(t, netted, 1)
+	assert.Equal(t, "BTC/USD", netted[0].Symbol)
+	assert.Equal(t, "BUY", netted[0].Side)
+	assert.Len(t, aggregator.Pending(), 3)
+
+	// Flush releases the rest in key order
+	netted = aggregator.Flush()
+	require.Len(t, netted, 3)
+	assert.Equal(t, "binance", netted[0].Exchange)
+	assert.Equal(t, "BTC/USD", netted[0].Symbol)
+	assert.Equal(t, "ETH/USD", netted[1].Symbol)
+	assert.Equal(t, "coinbase", netted[2].Exchange)
+	assert.Empty(t, aggregator.Pending())
+}
+
+func TestSignalAggregatorRestore(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	aggregator := NewSignalAggregator(time.Minute)
+	aggregator.Add(start, testSignal("binance", "BTC/USD", "BUY", 1, 100))
+
+	restored := NewSignalAggregator(time.Minute)
+	restored.Restore(aggregator.Pending())
+	restored.Add(start.Add(30*time.Second), testSignal("binance", "BTC/USD", "BUY", 1, 100))
+
+	netted := restored.Due(start.Add(time.Minute))
+	require.Len(t, netted, 1)
+	assert.True(t, netted[0].Quantity.Equal(decimal.NewFromInt(2)))
+}
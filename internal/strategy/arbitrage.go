@@ -5,6 +5,7 @@ import (
         "fmt"
         "log"
         "math"
+        "strings"
         "sync"
         "time"
 
@@ -24,7 +25,37 @@ type ArbitrageConfig struct {
         MaxExecutionLatency  int64              `yaml:"maxExecutionLatency"`
         SimultaneousExchanges int               `yaml:"simultaneousExchanges"`
         ExchangeFees         map[string]float64 `yaml:"exchangeFees"`
+        FeeSchedules         map[string]FeeSchedule `yaml:"feeSchedules"` // Maker/taker fees; exchanges without one use ExchangeFees for both
+        MakerLeg             string             `yaml:"makerLeg"`     // "buy", "sell", "none", or empty to pick the cheaper leg
         RiskLimit            float64            `yaml:"riskLimit"`
+        MinVolume            float64            `yaml:"minVolume"`
+        AllowedPairs         []ExchangePair     `yaml:"allowedPairs"`
+}
+
+// ExchangePair is a directed buy/sell exchange pair
+type ExchangePair struct {
+        Buy  string `yaml:"buy" json:"buy"`
+        Sell string `yaml:"sell" json:"sell"`
+}
+
+// FeeSchedule holds an exchange's maker and taker fees as fractions of notional
+type FeeSchedule struct {
+        Maker float64 `yaml:"maker" json:"maker"`
+        Taker float64 `yaml:"taker" json:"taker"`
+}
+
+// Which arbitrage leg rests on the book and pays the maker fee
+const (
+        MakerLegBuy  = "buy"
+        MakerLegSell = "sell"
+        MakerLegNone = "none" // Both legs cross the spread and pay taker fees
+)
+
+// ArbitrageThresholds are the detection thresholds that can be changed at runtime
+type ArbitrageThresholds struct {
+        MinProfitThreshold float64        `json:"minProfitThreshold"`
+        MinVolume          float64        `json:"minVolume"`
+        AllowedPairs       []ExchangePair `json:"allowedPairs"` // Empty allows every pair
 }
 
 // ArbitrageOpportunity represents a potential arbitrage opportunity
@@ -35,8 +66,12 @@ type ArbitrageOpportunity struct {
         BuyPrice        float64   `json:"buyPrice"`
         SellPrice       float64   `json:"sellPrice"`
         MaxVolume       float64   `json:"maxVolume"`
-        ProfitPercent   float64   `json:"profitPercent"`
-        EstimatedProfit float64   `json:"estimatedProfit"`
+        GrossProfitPercent float64 `json:"grossProfitPercent"`
+        ProfitPercent   float64   `json:"profitPercent"`   // Net of maker/taker fees
+        EstimatedProfit float64   `json:"estimatedProfit"` // Net of maker/taker fees
+        BuyFee          float64   `json:"buyFee"`
+        SellFee         float64   `json:"sellFee"`
+        MakerLeg        string    `json:"makerLeg,omitempty"`
         Timestamp       time.Time `json:"timestamp"`
         LatencyEstimate int64     `json:"latencyEstimate"`
         IsValid         bool      `json:"isValid"`
@@ -51,6 +86,9 @@ type ArbitrageStrategy struct {
         ctx         context.Context
         cancel      context.CancelFunc
         
+        // Guards the runtime-adjustable thresholds in config
+        muConfig     sync.RWMutex
+        
         // Store current opportunities
         muOpps       sync.RWMutex
         opportunities []ArbitrageOpportunity
@@ -105,6 +143,40 @@ func (s *ArbitrageStrategy) GetName() string {
         return s.config.Name
 }
 
+// WithParameters returns a new arbitrage strategy with the given parameters
+// applied on top of the current configuration
+func (s *ArbitrageStrategy) WithParameters(params map[string]interface{}) (Strategy, error) {
+        config := s.configSnapshot()
+        for name, value := range params {
+                var v float64
+                switch n := value.(type) {
+                case float64:
+                        v = n
+                case int:
+                        v = float64(n)
+                default:
+                        return nil, fmt.Errorf("parameter %s must be numeric", name)
+                }
+                
+                switch name {
+                case "minimumSpread":
+                        config.MinimumSpread = v
+                case "maxSlippage":
+                        config.MaxSlippage = v
+                case "minProfitThreshold":
+                        config.MinProfitThreshold = v
+                case "riskLimit":
+                        config.RiskLimit = v
+                default:
+                        return nil, fmt.Errorf("unknown arbitrage parameter: %s", name)
+                }
+        }
+        
+        strategy := NewArbitrageStrategy(config)
+        strategy.SetOrderBookManager(s.orderBooks)
+        return strategy, nil
+}
+
 // Start begins strategy execution
 func (s *ArbitrageStrategy) Start(ctx context.Context) error {
         s.muResults.Lock()
@@ -162,6 +234,77 @@ func (s *ArbitrageStrategy) GetResults() StrategyResults {
         return results
 }
 
+// GetThresholds returns the current detection thresholds
+func (s *ArbitrageStrategy) GetThresholds() ArbitrageThresholds {
+        s.muConfig.RLock()
+        defer s.muConfig.RUnlock()
+        
+        pairs := make([]ExchangePair, len(s.config.AllowedPairs))
+        copy(pairs, s.config.AllowedPairs)
+        
+        return ArbitrageThresholds{
+                MinProfitThreshold: s.config.MinProfitThreshold,
+                MinVolume:          s.config.MinVolume,
+                AllowedPairs:       pairs,
+        }
+}
+
+// configSnapshot returns a copy of the configuration, taken under the lock
+// that guards the thresholds SetThresholds changes at runtime
+func (s *ArbitrageStrategy) configSnapshot() ArbitrageConfig {
+        s.muConfig.RLock()
+        defer s.muConfig.RUnlock()
+        
+        config := s.config
+        config.AllowedPairs = make([]ExchangePair, len(s.config.AllowedPairs))
+        copy(config.AllowedPairs, s.config.AllowedPairs)
+        return config
+}
+
+// SetThresholds replaces the detection thresholds; they apply from the next detection pass
+func (s *ArbitrageStrategy) SetThresholds(thresholds ArbitrageThresholds) error {
+        if thresholds.MinVolume < 0 {
+                return fmt.Errorf("minimum volume cannot be negative")
+        }
+        for _, pair := range thresholds.AllowedPairs {
+                if pair.Buy == "" || pair.Sell == "" {
+                        return fmt.Errorf("exchange pair requires both buy and sell exchanges")
+                }
+                if pair.Buy == pair.Sell {
+                        return fmt.Errorf("exchange pair %s/%s must use different exchanges", pair.Buy, pair.Sell)
+                }
+        }
+        
+        pairs := make([]ExchangePair, len(thresholds.AllowedPairs))
+        copy(pairs, thresholds.AllowedPairs)
+        
+        s.muConfig.Lock()
+        s.config.MinProfitThreshold = thresholds.MinProfitThreshold
+        s.config.MinVolume = thresholds.MinVolume
+        s.config.AllowedPairs = pairs
+        s.muConfig.Unlock()
+        
+        log.Printf("Updated %s thresholds: min profit %.4f%%, min volume %.4f, %d allowed pairs",
+                s.config.Name, thresholds.MinProfitThreshold, thresholds.MinVolume, len(pairs))
+        return nil
+}
+
+// meetsThresholds checks an opportunity against the detection thresholds
+func (t ArbitrageThresholds) meetsThresholds(opportunity ArbitrageOpportunity) bool {
+        if opportunity.ProfitPercent < t.MinProfitThreshold || opportunity.MaxVolume < t.MinVolume {
+                return false
+        }
+        if len(t.AllowedPairs) == 0 {
+                return true
+        }
+        for _, pair := range t.AllowedPairs {
+                if pair.Buy == opportunity.BuyExchange && pair.Sell == opportunity.SellExchange {
+                        return true
+                }
+        }
+        return false
+}
+
 // GetOpportunities returns the current arbitrage opportunities
 func (s *ArbitrageStrategy) GetOpportunities() []ArbitrageOpportunity {
         s.muOpps.RLock()
@@ -195,6 +338,7 @@ func (s *ArbitrageStrategy) updateOpportunities() {
         // Get the configured symbols and exchanges
         symbols := s.config.Symbols
         exchanges := s.config.Exchanges
+        thresholds := s.GetThresholds()
         
         // Create a new slice to store opportunities
         newOpps := make([]ArbitrageOpportunity, 0)
@@ -210,7 +354,7 @@ func (s *ArbitrageStrategy) updateOpportunities() {
                                 }
                                 
                                 // Look for an arbitrage opportunity
-                                opportunity, found := s.detectOpportunity(symbol, buyExchange, sellExchange)
+                                opportunity, found := s.detectOpportunity(symbol, buyExchange, sellExchange, thresholds)
                                 if found && opportunity.IsValid {
                                         newOpps = append(newOpps, opportunity)
                                         
@@ -233,7 +377,7 @@ func (s *ArbitrageStrategy) updateOpportunities() {
 }
 
 // detectOpportunity checks for an arbitrage opportunity between two exchanges
-func (s *ArbitrageStrategy) detectOpportunity(symbol, buyExchange, sellExchange string) (ArbitrageOpportunity, bool) {
+func (s *ArbitrageStrategy) detectOpportunity(symbol, buyExchange, sellExchange string, thresholds ArbitrageThresholds) (ArbitrageOpportunity, bool) {
         // This is a simplified implementation. In a real system, you would need to:
         // 1. Get the actual order books for the exchanges
         // 2. Calculate the exact volume you can trade at each price level
@@ -259,25 +403,78 @@ func (s *ArbitrageStrategy) detectOpportunity(symbol, buyExchange, sellExchange
                 LatencyEstimate: 50, // ms
         }
         
-        // Calculate fees
-        buyFee := s.config.ExchangeFees[buyExchange]
-        sellFee := s.config.ExchangeFees[sellExchange]
-        
-        // Calculate profit percentage after fees
-        costBasis := opportunity.BuyPrice * (1 + buyFee)
-        sellProceeds := opportunity.SellPrice * (1 - sellFee)
-        profitPercent := (sellProceeds - costBasis) / costBasis * 100
-        
-        opportunity.ProfitPercent = profitPercent
-        opportunity.EstimatedProfit = (sellProceeds - costBasis) * opportunity.MaxVolume
+        // Calculate profit after maker/taker fees
+        s.applyFees(&opportunity)
         
         // Check if the opportunity is valid
-        opportunity.IsValid = profitPercent >= s.config.MinProfitThreshold &&
+        opportunity.IsValid = opportunity.ProfitPercent > 0 &&
+                thresholds.meetsThresholds(opportunity) &&
                 opportunity.LatencyEstimate <= s.config.MaxExecutionLatency
         
         return opportunity, true
 }
 
+// feeSchedule returns the maker/taker fees for an exchange, falling back to
+// the flat exchange fee for both sides
+func (s *ArbitrageStrategy) feeSchedule(exchange string) FeeSchedule {
+        if schedule, ok := s.config.FeeSchedules[exchange]; ok {
+                return schedule
+        }
+        fee := s.config.ExchangeFees[exchange]
+        return FeeSchedule{Maker: fee, Taker: fee}
+}
+
+// netProfit returns the per-unit profit and profit percentage after fees
+func netProfit(buyPrice, sellPrice, buyFee, sellFee float64) (float64, float64) {
+        costBasis := buyPrice * (1 + buyFee)
+        sellProceeds := sellPrice * (1 - sellFee)
+        if costBasis <= 0 {
+                return 0, 0
+        }
+        profit := sellProceeds - costBasis
+        return profit, profit / costBasis * 100
+}
+
+// applyFees fills in the fee-aware profit for an opportunity. One leg rests as a
+// maker and the other crosses as a taker; with no configured maker leg the
+// assignment with the higher net profit is used.
+func (s *ArbitrageStrategy) applyFees(opportunity *ArbitrageOpportunity) {
+        buySchedule := s.feeSchedule(opportunity.BuyExchange)
+        sellSchedule := s.feeSchedule(opportunity.SellExchange)
+        
+        if opportunity.BuyPrice > 0 {
+                opportunity.GrossProfitPercent = (opportunity.SellPrice - opportunity.BuyPrice) / opportunity.BuyPrice * 100
+        }
+        
+        var legs []string
+        switch s.config.MakerLeg {
+        case MakerLegBuy, MakerLegSell, MakerLegNone:
+                legs = []string{s.config.MakerLeg}
+        default:
+                legs = []string{MakerLegBuy, MakerLegSell}
+        }
+        
+        for i, leg := range legs {
+                buyFee, sellFee := buySchedule.Taker, sellSchedule.Taker
+                switch leg {
+                case MakerLegBuy:
+                        buyFee = buySchedule.Maker
+                case MakerLegSell:
+                        sellFee = sellSchedule.Maker
+                }
+                
+                profit, percent := netProfit(opportunity.BuyPrice, opportunity.SellPrice, buyFee, sellFee)
+                if i > 0 && percent <= opportunity.ProfitPercent {
+                        continue
+                }
+                opportunity.MakerLeg = leg
+                opportunity.BuyFee = buyFee
+                opportunity.SellFee = sellFee
+                opportunity.ProfitPercent = percent
+                opportunity.EstimatedProfit = profit * opportunity.MaxVolume
+        }
+}
+
 // generateSignal creates trading signals from an arbitrage opportunity
 func (s *ArbitrageStrategy) generateSignal(opportunity ArbitrageOpportunity) {
         // Create buy signal
@@ -362,9 +559,10 @@ func (s *ArbitrageStrategy) GenerateSignals(orderBooks map[string]*orderbook.Ord
         
         // Find arbitrage opportunities
         opportunities := s.findArbitrageOpportunities(orderBooks)
+        thresholds := s.GetThresholds()
         
         for _, opportunity := range opportunities {
-                if opportunity.IsValid && opportunity.ProfitPercent > s.config.MinProfitThreshold {
+                if opportunity.IsValid && thresholds.meetsThresholds(opportunity) {
                         // Generate buy signal
                         buySignal := &Signal{
                                 Symbol:   opportunity.Symbol,
@@ -376,6 +574,7 @@ func (s *ArbitrageStrategy) GenerateSignals(orderBooks map[string]*orderbook.Ord
                                         "opportunity_id": fmt.Sprintf("%s_%s_%s", opportunity.BuyExchange, opportunity.SellExchange, opportunity.Symbol),
                                         "profit_percent": opportunity.ProfitPercent,
                                         "estimated_profit": opportunity.EstimatedProfit,
+                                        "confidence": calculateConfidence(opportunity),
                                 },
                         }
                         signals = append(signals, buySignal)
@@ -391,6 +590,7 @@ func (s *ArbitrageStrategy) GenerateSignals(orderBooks map[string]*orderbook.Ord
                                         "opportunity_id": fmt.Sprintf("%s_%s_%s", opportunity.BuyExchange, opportunity.SellExchange, opportunity.Symbol),
                                         "profit_percent": opportunity.ProfitPercent,
                                         "estimated_profit": opportunity.EstimatedProfit,
+                                        "confidence": calculateConfidence(opportunity),
                                 },
                         }
                         signals = append(signals, sellSignal)
@@ -400,81 +600,57 @@ func (s *ArbitrageStrategy) GenerateSignals(orderBooks map[string]*orderbook.Ord
         return signals, nil
 }
 
-// findArbitrageOpportunities finds arbitrage opportunities from order books
+// findArbitrageOpportunities finds arbitrage opportunities from order books keyed by exchange:symbol
 func (s *ArbitrageStrategy) findArbitrageOpportunities(orderBooks map[string]*orderbook.OrderBook) []ArbitrageOpportunity {
         var opportunities []ArbitrageOpportunity
         
+        // Collect the symbols present in the books
+        symbols := make(map[string]bool)
+        for key := range orderBooks {
+                if parts := strings.SplitN(key, ":", 2); len(parts) == 2 {
+                        symbols[parts[1]] = true
+                }
+        }
+        
         // Simple implementation - compare prices across exchanges
-        for symbol := range orderBooks {
-                for _, exchange1 := range s.config.Exchanges {
-                        for _, exchange2 := range s.config.Exchanges {
-                                if exchange1 == exchange2 {
+        for symbol := range symbols {
+                for _, buyExchange := range s.config.Exchanges {
+                        for _, sellExchange := range s.config.Exchanges {
+                                if buyExchange == sellExchange {
                                         continue
                                 }
                                 
-                                key1 := fmt.Sprintf("%s:%s", exchange1, symbol)
-                                key2 := fmt.Sprintf("%s:%s", exchange2, symbol)
-                                
-                                book1, exists1 := orderBooks[key1]
-                                book2, exists2 := orderBooks[key2]
-                                
+                                buyBook, exists1 := orderBooks[fmt.Sprintf("%s:%s", buyExchange, symbol)]
+                                sellBook, exists2 := orderBooks[fmt.Sprintf("%s:%s", sellExchange, symbol)]
                                 if !exists1 || !exists2 {
                                         continue
                                 }
                                 
-                                // Get best bid and ask prices
-                                bestBid1 := book1.GetBestBid()
-                                bestAsk1 := book1.GetBestAsk()
-                                bestBid2 := book2.GetBestBid()
-                                bestAsk2 := book2.GetBestAsk()
-                                
-                                if bestBid1 == nil || bestAsk1 == nil || bestBid2 == nil || bestAsk2 == nil {
+                                // Buy at the ask on one exchange, sell at the bid on the other
+                                bestAsk := buyBook.GetBestAsk()
+                                bestBid := sellBook.GetBestBid()
+                                if bestAsk == nil || bestBid == nil || bestBid.Price <= bestAsk.Price {
                                         continue
                                 }
                                 
-                                // Check for arbitrage opportunity
-                                if bestBid1.Price > bestAsk2.Price {
-                                        // Buy on exchange2, sell on exchange1
-                                        profit := bestBid1.Price - bestAsk2.Price
-                                        profitPercent := (profit / bestAsk2.Price) * 100
-                                        
-                                        opportunity := ArbitrageOpportunity{
-                                                BuyExchange:     exchange2,
-                                                SellExchange:    exchange1,
-                                                Symbol:          symbol,
-                                                BuyPrice:        bestAsk2.Price,
-                                                SellPrice:       bestBid1.Price,
-                                                MaxVolume:       math.Min(bestAsk2.Volume, bestBid1.Volume),
-                                                ProfitPercent:   profitPercent,
-                                                EstimatedProfit: profit * math.Min(bestAsk2.Volume, bestBid1.Volume),
-                                                Timestamp:       time.Now(),
-                                                IsValid:         profitPercent > s.config.MinimumSpread,
-                                        }
-                                        opportunities = append(opportunities, opportunity)
+                                opportunity := ArbitrageOpportunity{
+                                        BuyExchange:  buyExchange,
+                                        SellExchange: sellExchange,
+                                        Symbol:       symbol,
+                                        BuyPrice:     bestAsk.Price,
+                                        SellPrice:    bestBid.Price,
+                                        MaxVolume:    math.Min(bestAsk.Volume, bestBid.Volume),
+                                        Timestamp:    time.Now(),
                                 }
+                                s.applyFees(&opportunity)
                                 
-                                if bestBid2.Price > bestAsk1.Price {
-                                        // Buy on exchange1, sell on exchange2
-                                        profit := bestBid2.Price - bestAsk1.Price
-                                        profitPercent := (profit / bestAsk1.Price) * 100
-                                        
-                                        opportunity := ArbitrageOpportunity{
-                                                BuyExchange:     exchange1,
-                                                SellExchange:    exchange2,
-                                                Symbol:          symbol,
-                                                BuyPrice:        bestAsk1.Price,
-                                                SellPrice:       bestBid2.Price,
-                                                MaxVolume:       math.Min(bestAsk1.Volume, bestBid2.Volume),
-                                                ProfitPercent:   profitPercent,
-                                                EstimatedProfit: profit * math.Min(bestAsk1.Volume, bestBid2.Volume),
-                                                Timestamp:       time.Now(),
-                                                IsValid:         profitPercent > s.config.MinimumSpread,
-                                        }
-                                        opportunities = append(opportunities, opportunity)
-                                }
+                                // Only surface opportunities that stay profitable after fees
+                                opportunity.IsValid = opportunity.GrossProfitPercent > s.config.MinimumSpread &&
+                                        opportunity.ProfitPercent > 0
+                                opportunities = append(opportunities, opportunity)
                         }
                 }
         }
         
         return opportunities
-}
\ No newline at end of file
+}
@@ -0,0 +1,165 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/normalizer"
+	"velocimex/internal/orderbook"
+)
+
+// crossedBooks returns books where buying on binance and selling on coinbase
+// captures a 0.5% gross spread
+func crossedBooks() map[string]*orderbook.OrderBook {
+	binance := orderbook.NewOrderBook("BTC/USD")
+	binance.Update(
+		[]normalizer.PriceLevel{{Price: 99.9, Volume: 2}},
+		[]normalizer.PriceLevel{{Price: 100.0, Volume: 2}},
+	)
+	coinbase := orderbook.NewOrderBook("BTC/USD")
+	coinbase.Update(
+		[]normalizer.PriceLevel{{Price: 100.5, Volume: 1}},
+		[]normalizer.PriceLevel{{Price: 100.6, Volume: 1}},
+	)
+	return map[string]*orderbook.OrderBook{
+		"binance:BTC/USD":  binance,
+		"coinbase:BTC/USD": coinbase,
+	}
+}
+
+func newFeeArbitrage(schedules map[string]FeeSchedule, makerLeg string) *ArbitrageStrategy {
+	return NewArbitrageStrategy(ArbitrageConfig{
+		Name:          "arb",
+		Exchanges:     []string{"binance", "coinbase"},
+		MinimumSpread: 0.1,
+		FeeSchedules:  schedules,
+		MakerLeg:      makerLeg,
+	})
+}
+
+// TestArbitrageNetProfitFilter tests that fee schedules decide which opportunities are actionable
+func TestArbitrageNetProfitFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		schedules  map[string]FeeSchedule
+		makerLeg   string
+		actionable bool
+		wantLeg    string
+	}{
+		{
+			name: "low fees keep the spread profitable",
+			schedules: map[string]FeeSchedule{
+				"binance":  {Maker: 0, Taker: 0.001},
+				"coinbase": {Maker: 0, Taker: 0.001},
+			},
+			actionable: true,
+		},
+		{
+			name: "high fees consume the spread",
+			schedules: map[string]FeeSchedule{
+				"binance":  {Maker: 0.002, Taker: 0.004},
+				"coinbase": {Maker: 0.002, Taker: 0.004},
+			},
+			actionable: false,
+		},
+		{
+			name: "resting the buy leg avoids the expensive taker fee",
+			schedules: map[string]FeeSchedule{
+				"binance":  {Maker: 0, Taker: 0.005},
+				"coinbase": {Maker: 0.003, Taker: 0.003},
+			},
+			actionable: true,
+			wantLeg:    MakerLegBuy,
+		},
+		{
+			name: "forcing the sell leg to rest pays the expensive taker fee",
+			schedules: map[string]FeeSchedule{
+				"binance":  {Maker: 0, Taker: 0.005},
+				"coinbase": {Maker: 0.003, Taker: 0.003},
+			},
+			makerLeg:   MakerLegSell,
+			actionable: false,
+			wantLeg:    MakerLegSell,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFeeArbitrage(tt.schedules, tt.makerLeg)
+
+			opportunities := s.findArbitrageOpportunities(crossedBooks())
+			require.Len(t, opportunities, 1)
+			opportunity := opportunities[0]
+
+			assert.Equal(t, "binance", opportunity.BuyExchange)
+			assert.Equal(t, "coinbase", opportunity.SellExchange)
+			assert.InDelta(t, 0.5, opportunity.GrossProfitPercent, 1e-9)
+			assert.Less(t, opportunity.ProfitPercent, opportunity.GrossProfitPercent)
+			assert.Equal(t, tt.actionable, opportunity.IsValid)
+			assert.Equal(t, tt.actionable, opportunity.ProfitPercent > 0)
+			if tt.wantLeg != "" {
+				assert.Equal(t, tt.wantLeg, opportunity.MakerLeg)
+			}
+
+			signals, err := s.GenerateSignals(crossedBooks())
+			require.NoError(t, err)
+			if tt.actionable {
+				assert.Len(t, signals, 2)
+			} else {
+				assert.Empty(t, signals)
+			}
+		})
+	}
+}
+
+// TestArbitrageFlatFeeFallback tests that exchanges without a schedule use the flat fee for both legs
+func TestArbitrageFlatFeeFallback(t *testing.T) {
+	s := NewArbitrageStrategy(ArbitrageConfig{
+		Exchanges:    []string{"binance", "coinbase"},
+		ExchangeFees: map[string]float64{"binance": 0.001, "coinbase": 0.002},
+	})
+
+	opportunity := ArbitrageOpportunity{
+		BuyExchange:  "binance",
+		SellExchange: "coinbase",
+		BuyPrice:     100,
+		SellPrice:    101,
+		MaxVolume:    2,
+	}
+	s.applyFees(&opportunity)
+
+	cost := 100 * 1.001
+	proceeds := 101 * 0.998
+	assert.Equal(t, 0.001, opportunity.BuyFee)
+	assert.Equal(t, 0.002, opportunity.SellFee)
+	assert.InDelta(t, (proceeds-cost)/cost*100, opportunity.ProfitPercent, 1e-9)
+	assert.InDelta(t, (proceeds-cost)*2, opportunity.EstimatedProfit, 1e-9)
+}
+
+// TestArbitrageWithParametersThresholds tests that parameter variants take the
+// current thresholds and can be built while the thresholds are being changed
+func TestArbitrageWithParametersThresholds(t *testing.T) {
+	s := newFeeArbitrage(nil, "")
+	pairs := []ExchangePair{{Buy: "binance", Sell: "coinbase"}}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			require.NoError(t, s.SetThresholds(ArbitrageThresholds{MinProfitThreshold: float64(i), AllowedPairs: pairs}))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_, err := s.WithParameters(map[string]interface{}{"minimumSpread": 0.2})
+		require.NoError(t, err)
+	}
+	<-done
+
+	variant, err := s.WithParameters(map[string]interface{}{"riskLimit": 5})
+	require.NoError(t, err)
+	arbitrage := variant.(*ArbitrageStrategy)
+	assert.Equal(t, 99.0, arbitrage.GetThresholds().MinProfitThreshold)
+	assert.Equal(t, pairs, arbitrage.GetThresholds().AllowedPairs)
+	assert.Equal(t, 5.0, arbitrage.config.RiskLimit)
+}
@@ -0,0 +1,65 @@
+package strategy
+
+import "fmt"
+
+// ConfidenceGateConfig ignores signals whose confidence, reported in the
+// "confidence" metadata on a 0-1 scale, is below a minimum. Signals that do not
+// report a confidence always pass.
+type ConfidenceGateConfig struct {
+	// MinConfidence applies to every strategy without its own minimum; zero disables the gate
+	MinConfidence float64 `json:"min_confidence" yaml:"minConfidence"`
+	// Strategies overrides MinConfidence, keyed by strategy ID
+	Strategies map[string]float64 `json:"strategies,omitempty" yaml:"strategies"`
+}
+
+// Validate checks that every minimum is on the 0-1 confidence scale
+func (c ConfidenceGateConfig) Validate() error {
+	if c.MinConfidence < 0 || c.MinConfidence > 1 {
+		return fmt.Errorf("min confidence must be between 0 and 1")
+	}
+	for strategyID, minimum := range c.Strategies {
+		if minimum < 0 || minimum > 1 {
+			return fmt.Errorf("min confidence for strategy %s must be between 0 and 1", strategyID)
+		}
+	}
+	return nil
+}
+
+// Minimum returns the minimum confidence of a strategy's signals
+func (c ConfidenceGateConfig) Minimum(strategyID string) float64 {
+	if minimum, ok := c.Strategies[strategyID]; ok {
+		return minimum
+	}
+	return c.MinConfidence
+}
+
+// Filter returns the signals of a strategy that meet its minimum confidence
+func (c ConfidenceGateConfig) Filter(strategyID string, signals []*Signal) []*Signal {
+	minimum := c.Minimum(strategyID)
+	if minimum <= 0 {
+		return signals
+	}
+
+	passed := make([]*Signal, 0, len(signals))
+	for _, signal := range signals {
+		if confidence, ok := SignalConfidence(signal); ok && confidence < minimum {
+			continue
+		}
+		passed = append(passed, signal)
+	}
+	return passed
+}
+
+// SignalConfidence returns the confidence a signal reports in its metadata
+func SignalConfidence(signal *Signal) (float64, bool) {
+	switch confidence := signal.Metadata["confidence"].(type) {
+	case float64:
+		return confidence, true
+	case float32:
+		return float64(confidence), true
+	case int:
+		return float64(confidence), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,35 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfidenceGateFilter(t *testing.T) {
+	signal := func(confidence interface{}) *Signal {
+		metadata := map[string]interface{}{}
+		if confidence != nil {
+			metadata["confidence"] = confidence
+		}
+		return &Signal{Symbol: "BTC/USD", Side: "BUY", Metadata: metadata}
+	}
+	low, high, unreported := signal(0.3), signal(0.8), signal(nil)
+	signals := []*Signal{low, high, unreported}
+
+	gate := ConfidenceGateConfig{MinConfidence: 0.5, Strategies: map[string]float64{"strict": 0.9, "open": 0}}
+	assert.Equal(t, []*Signal{high, unreported}, gate.Filter("arbitrage", signals))
+	assert.Equal(t, []*Signal{unreported}, gate.Filter("strict", signals))
+	assert.Equal(t, signals, gate.Filter("open", signals))
+	assert.Equal(t, signals, ConfidenceGateConfig{}.Filter("arbitrage", signals))
+
+	confidence, ok := SignalConfidence(signal(1))
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, confidence)
+	_, ok = SignalConfidence(signal("high"))
+	assert.False(t, ok)
+
+	assert.NoError(t, gate.Validate())
+	assert.Error(t, ConfidenceGateConfig{MinConfidence: -0.1}.Validate())
+	assert.Error(t, ConfidenceGateConfig{Strategies: map[string]float64{"strict": 2}}.Validate())
+}
@@ -0,0 +1,343 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"velocimex/internal/orderbook"
+)
+
+// ImbalanceConfig contains configuration for the order book imbalance strategy
+type ImbalanceConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Name     string   `yaml:"name"`
+	Exchange string   `yaml:"exchange"` // Exchange whose books are read and receive the orders
+	Symbols  []string `yaml:"symbols"`
+	// Depth is the number of levels per side imbalance is measured over; default orderbook.ImbalanceDepth
+	Depth int `yaml:"depth"`
+	// Threshold is the imbalance, between 0 and 1, past which the strategy goes long or short;
+	// imbalances within it are the flat zone, where the position is held
+	Threshold      float64       `yaml:"threshold"`
+	Quantity       float64       `yaml:"quantity"` // Position size per symbol
+	UpdateInterval time.Duration `yaml:"updateInterval"`
+}
+
+// Validate checks the imbalance strategy configuration
+func (c ImbalanceConfig) Validate() error {
+	if c.Exchange == "" {
+		return fmt.Errorf("imbalance strategy requires an exchange")
+	}
+	if len(c.Symbols) == 0 {
+		return fmt.Errorf("imbalance strategy requires at least one symbol")
+	}
+	if c.Threshold <= 0 || c.Threshold >= 1 {
+		return fmt.Errorf("imbalance threshold %v out of range (0, 1)", c.Threshold)
+	}
+	if c.Quantity <= 0 {
+		return fmt.Errorf("imbalance quantity must be positive")
+	}
+	if c.Depth < 0 {
+		return fmt.Errorf("imbalance depth cannot be negative")
+	}
+	return nil
+}
+
+// ImbalanceStrategy trades the order book imbalance: it goes long when bid
+// volume outweighs ask volume past the threshold and short when ask volume
+// does, holding its position while the imbalance is in the flat zone between.
+// It tracks positions by assuming its own orders fill, so a signal is only
+// emitted when the side changes.
+type ImbalanceStrategy struct {
+	config     ImbalanceConfig
+	orderBooks *orderbook.Manager
+	running    bool
+	ctx        context.Context
+	cancel     context.CancelFunc
+
+	muPositions sync.Mutex
+	positions   map[string]float64 // Symbol -> signed position
+
+	muResults sync.RWMutex
+	results   StrategyResults
+}
+
+// NewImbalanceStrategy creates a new order book imbalance strategy
+func NewImbalanceStrategy(config ImbalanceConfig) *ImbalanceStrategy {
+	if config.Name == "" {
+		config.Name = "imbalance"
+	}
+	if config.Depth <= 0 {
+		config.Depth = orderbook.ImbalanceDepth
+	}
+
+	return &ImbalanceStrategy{
+		config:    config,
+		positions: make(map[string]float64),
+		results: StrategyResults{
+			Name:             config.Name,
+			RecentSignals:    make([]TradeSignal, 0),
+			CurrentPositions: make([]Position, 0),
+		},
+	}
+}
+
+// SetOrderBookManager sets the order book manager used in live trading
+func (s *ImbalanceStrategy) SetOrderBookManager(manager *orderbook.Manager) {
+	s.orderBooks = manager
+}
+
+// Positions returns a copy of the tracked signed positions
+func (s *ImbalanceStrategy) Positions() map[string]float64 {
+	s.muPositions.Lock()
+	defer s.muPositions.Unlock()
+
+	positions := make(map[string]float64, len(s.positions))
+	for symbol, quantity := range s.positions {
+		positions[symbol] = quantity
+	}
+	return positions
+}
+
+// GetID returns the ID of the strategy
+func (s *ImbalanceStrategy) GetID() string {
+	return "imbalance"
+}
+
+// GetName returns the name of the strategy
+func (s *ImbalanceStrategy) GetName() string {
+	return s.config.Name
+}
+
+// WithParameters returns a new imbalance strategy with the given parameters
+// applied on top of the current configuration
+func (s *ImbalanceStrategy) WithParameters(params map[string]interface{}) (Strategy, error) {
+	config := s.config
+	for name, value := range params {
+		var v float64
+		switch n := value.(type) {
+		case float64:
+			v = n
+		case int:
+			v = float64(n)
+		default:
+			return nil, fmt.Errorf("parameter %s must be numeric", name)
+		}
+
+		switch name {
+		case "threshold":
+			config.Threshold = v
+		case "depth":
+			config.Depth = int(v)
+		case "quantity":
+			config.Quantity = v
+		default:
+			return nil, fmt.Errorf("unknown imbalance parameter: %s", name)
+		}
+	}
+
+	strategy := NewImbalanceStrategy(config)
+	strategy.SetOrderBookManager(s.orderBooks)
+	return strategy, nil
+}
+
+// Start begins strategy execution
+func (s *ImbalanceStrategy) Start(ctx context.Context) error {
+	s.muResults.Lock()
+	defer s.muResults.Unlock()
+
+	if s.running {
+		return nil
+	}
+
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.running = true
+	s.results.Running = true
+	s.results.StartTime = time.Now()
+
+	go s.run(s.ctx)
+
+	log.Printf("Started %s strategy", s.config.Name)
+	return nil
+}
+
+// Stop halts strategy execution
+func (s *ImbalanceStrategy) Stop() error {
+	s.muResults.Lock()
+	defer s.muResults.Unlock()
+
+	if !s.running {
+		return nil
+	}
+
+	s.cancel()
+	s.running = false
+	s.results.Running = false
+
+	log.Printf("Stopped %s strategy", s.config.Name)
+	return nil
+}
+
+// IsRunning returns whether the strategy is currently running
+func (s *ImbalanceStrategy) IsRunning() bool {
+	s.muResults.RLock()
+	defer s.muResults.RUnlock()
+	return s.running
+}
+
+// GetResults returns the current strategy results
+func (s *ImbalanceStrategy) GetResults() StrategyResults {
+	s.muResults.RLock()
+	defer s.muResults.RUnlock()
+
+	results := s.results
+	results.RecentSignals = append([]TradeSignal(nil), s.results.RecentSignals...)
+	results.LastUpdate = time.Now()
+	return results
+}
+
+// run checks the live books' imbalance on every update interval
+func (s *ImbalanceStrategy) run(ctx context.Context) {
+	interval := s.config.UpdateInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.orderBooks == nil {
+				continue
+			}
+			signals, err := s.GenerateSignals(s.orderBooks.GetAllOrderBooks())
+			if err != nil {
+				log.Printf("Imbalance strategy %s: %v", s.config.Name, err)
+				continue
+			}
+			s.recordSignals(signals)
+		}
+	}
+}
+
+// recordSignals adds live imbalance orders to the strategy results
+func (s *ImbalanceStrategy) recordSignals(signals []*Signal) {
+	if len(signals) == 0 {
+		return
+	}
+
+	s.muResults.Lock()
+	defer s.muResults.Unlock()
+
+	now := time.Now()
+	for _, signal := range signals {
+		price, _ := signal.Price.Float64()
+		volume, _ := signal.Quantity.Float64()
+		side := "buy"
+		if signal.Side == "SELL" {
+			side = "sell"
+		}
+
+		// Keep only the most recent signals (max 10)
+		if len(s.results.RecentSignals) >= 10 {
+			s.results.RecentSignals = s.results.RecentSignals[1:]
+		}
+		s.results.RecentSignals = append(s.results.RecentSignals, TradeSignal{
+			Strategy:   s.config.Name,
+			Symbol:     signal.Symbol,
+			Side:       side,
+			Price:      price,
+			Volume:     volume,
+			Exchange:   signal.Exchange,
+			Timestamp:  now,
+			Confidence: signal.Metadata["confidence"].(float64),
+			Reason:     fmt.Sprintf("Order book imbalance %.2f on %s", signal.Metadata["imbalance"].(float64), signal.Symbol),
+		})
+		s.results.SignalsGenerated++
+	}
+}
+
+// GenerateSignals returns the orders that move each symbol to the side its
+// imbalance calls for, or none while every symbol is already there or in
+// the flat zone
+func (s *ImbalanceStrategy) GenerateSignals(orderBooks map[string]*orderbook.OrderBook) ([]*Signal, error) {
+	return s.trade(orderBooks, true)
+}
+
+// PreviewSignals returns the orders GenerateSignals would, without assuming
+// they fill
+func (s *ImbalanceStrategy) PreviewSignals(orderBooks map[string]*orderbook.OrderBook) ([]*Signal, error) {
+	return s.trade(orderBooks, false)
+}
+
+// trade computes the imbalance orders, updating the tracked positions as if
+// they filled when apply is set
+func (s *ImbalanceStrategy) trade(orderBooks map[string]*orderbook.OrderBook, apply bool) ([]*Signal, error) {
+	symbols := append([]string(nil), s.config.Symbols...)
+	sort.Strings(symbols)
+
+	s.muPositions.Lock()
+	defer s.muPositions.Unlock()
+
+	var signals []*Signal
+	for _, symbol := range symbols {
+		book, ok := orderBooks[fmt.Sprintf("%s:%s", s.config.Exchange, symbol)]
+		if !ok {
+			continue
+		}
+		bid, ask := book.GetBestBid(), book.GetBestAsk()
+		if bid == nil || ask == nil || bid.Price <= 0 || ask.Price <= 0 {
+			continue
+		}
+
+		imbalance := book.Stats().Imbalance
+		if s.config.Depth != orderbook.ImbalanceDepth {
+			bids, asks := book.GetDepth(s.config.Depth)
+			imbalance = orderbook.Imbalance(bids, asks, s.config.Depth)
+		}
+
+		var target float64
+		switch {
+		case imbalance >= s.config.Threshold:
+			target = s.config.Quantity
+		case imbalance <= -s.config.Threshold:
+			target = -s.config.Quantity
+		default:
+			continue
+		}
+
+		delta := target - s.positions[symbol]
+		if delta == 0 {
+			continue
+		}
+		side, price := "BUY", ask.Price
+		if delta < 0 {
+			side, price = "SELL", bid.Price
+		}
+
+		signals = append(signals, &Signal{
+			Symbol:   symbol,
+			Exchange: s.config.Exchange,
+			Side:     side,
+			Quantity: decimal.NewFromFloat(math.Abs(delta)),
+			Price:    decimal.NewFromFloat(price),
+			Metadata: map[string]interface{}{
+				"imbalance":  imbalance,
+				"confidence": math.Abs(imbalance),
+			},
+		})
+		if apply {
+			s.positions[symbol] = target
+		}
+	}
+
+	return signals, nil
+}
@@ -0,0 +1,154 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/normalizer"
+	"velocimex/internal/orderbook"
+)
+
+// imbalanceBooks returns a binance BTC/USD book quoted at 99/101 with the
+// given volume on each side
+func imbalanceBooks(bidVolume, askVolume float64) map[string]*orderbook.OrderBook {
+	book := orderbook.NewOrderBook("BTC/USD")
+	book.Update(
+		[]normalizer.PriceLevel{{Price: 99, Volume: bidVolume}},
+		[]normalizer.PriceLevel{{Price: 101, Volume: askVolume}},
+	)
+	return map[string]*orderbook.OrderBook{"binance:BTC/USD": book}
+}
+
+func newTestImbalance() *ImbalanceStrategy {
+	return NewImbalanceStrategy(ImbalanceConfig{
+		Exchange:  "binance",
+		Symbols:   []string{"BTC/USD"},
+		Threshold: 0.5,
+		Quantity:  2,
+	})
+}
+
+// TestImbalanceSignalSide tests that bid-heavy books go long and ask-heavy books go short
+func TestImbalanceSignalSide(t *testing.T) {
+	s := newTestImbalance()
+
+	// 80 against 20 is an imbalance of 0.6
+	signals, err := s.GenerateSignals(imbalanceBooks(80, 20))
+	require.NoError(t, err)
+	require.Len(t, signals, 1)
+	assert.Equal(t, "BUY", signals[0].Side)
+	assert.Equal(t, "binance", signals[0].Exchange)
+	assert.True(t, signals[0].Price.Equal(decimal.NewFromInt(101)), "price %s", signals[0].Price)
+	assert.True(t, signals[0].Quantity.Equal(decimal.NewFromInt(2)), "quantity %s", signals[0].Quantity)
+	assert.InDelta(t, 0.6, signals[0].Metadata["imbalance"], 1e-9)
+
+	// Already long, so the same skew does not buy again
+	signals, err = s.GenerateSignals(imbalanceBooks(80, 20))
+	require.NoError(t, err)
+	assert.Empty(t, signals)
+
+	// Flipping short sells the long position and the short one
+	signals, err = s.GenerateSignals(imbalanceBooks(10, 90))
+	require.NoError(t, err)
+	require.Len(t, signals, 1)
+	assert.Equal(t, "SELL", signals[0].Side)
+	assert.True(t, signals[0].Price.Equal(decimal.NewFromInt(99)), "price %s", signals[0].Price)
+	assert.True(t, signals[0].Quantity.Equal(decimal.NewFromInt(4)), "quantity %s", signals[0].Quantity)
+	assert.Equal(t, map[string]float64{"BTC/USD": -2}, s.Positions())
+}
+
+// TestImbalanceFlatZone tests that imbalances within the threshold produce no signal
+func TestImbalanceFlatZone(t *testing.T) {
+	s := newTestImbalance()
+
+	for _, volumes := range [][2]float64{{50, 50}, {70, 30}, {30, 70}, {0, 0}} {
+		signals, err := s.GenerateSignals(imbalanceBooks(volumes[0], volumes[1]))
+		require.NoError(t, err)
+		assert.Empty(t, signals, "bids %v asks %v", volumes[0], volumes[1])
+	}
+	assert.Empty(t, s.Positions())
+
+	// A position taken outside the flat zone is held inside it
+	_, err := s.GenerateSignals(imbalanceBooks(10, 90))
+	require.NoError(t, err)
+	signals, err := s.GenerateSignals(imbalanceBooks(50, 50))
+	require.NoError(t, err)
+	assert.Empty(t, signals)
+	assert.Equal(t, map[string]float64{"BTC/USD": -2}, s.Positions())
+}
+
+// TestImbalanceDepth tests that imbalance is measured over the configured depth
+func TestImbalanceDepth(t *testing.T) {
+	book := orderbook.NewOrderBook("BTC/USD")
+	book.Update(
+		[]normalizer.PriceLevel{{Price: 99, Volume: 10}, {Price: 98, Volume: 200}},
+		[]normalizer.PriceLevel{{Price: 101, Volume: 40}, {Price: 102, Volume: 1}},
+	)
+	books := map[string]*orderbook.OrderBook{"binance:BTC/USD": book}
+
+	// Across both levels bids dominate
+	deep := newTestImbalance()
+	signals, err := deep.PreviewSignals(books)
+	require.NoError(t, err)
+	require.Len(t, signals, 1)
+	assert.Equal(t, "BUY", signals[0].Side)
+
+	// At the top level alone asks do
+	top := NewImbalanceStrategy(ImbalanceConfig{Exchange: "binance", Symbols: []string{"BTC/USD"}, Depth: 1, Threshold: 0.5, Quantity: 2})
+	signals, err = top.PreviewSignals(books)
+	require.NoError(t, err)
+	require.Len(t, signals, 1)
+	assert.Equal(t, "SELL", signals[0].Side)
+
+	// Previews do not take positions
+	assert.Empty(t, deep.Positions())
+	assert.Empty(t, top.Positions())
+}
+
+// TestImbalanceLive tests that a running strategy trades the live books
+func TestImbalanceLive(t *testing.T) {
+	books := orderbook.NewManager()
+	books.UpdateOrderBook("binance", "BTC/USD", []normalizer.PriceLevel{{Price: 99, Volume: 90}}, []normalizer.PriceLevel{{Price: 101, Volume: 10}})
+
+	engine := NewEngine(books)
+	s := NewImbalanceStrategy(ImbalanceConfig{
+		Exchange:       "binance",
+		Symbols:        []string{"BTC/USD"},
+		Threshold:      0.5,
+		Quantity:       1,
+		UpdateInterval: 5 * time.Millisecond,
+	})
+	engine.RegisterStrategy(s)
+	require.NoError(t, s.Start(context.Background()))
+	defer s.Stop()
+
+	require.Eventually(t, func() bool {
+		return s.GetResults().SignalsGenerated == 1
+	}, time.Second, 5*time.Millisecond)
+	signal := s.GetResults().RecentSignals[0]
+	assert.Equal(t, "buy", signal.Side)
+	assert.InDelta(t, 0.8, signal.Confidence, 1e-9)
+}
+
+// TestImbalanceConfigValidate tests the imbalance strategy configuration checks
+func TestImbalanceConfigValidate(t *testing.T) {
+	valid := ImbalanceConfig{Exchange: "binance", Symbols: []string{"BTC/USD"}, Threshold: 0.3, Quantity: 1}
+	assert.NoError(t, valid.Validate())
+
+	for name, mutate := range map[string]func(*ImbalanceConfig){
+		"no exchange":      func(c *ImbalanceConfig) { c.Exchange = "" },
+		"no symbols":       func(c *ImbalanceConfig) { c.Symbols = nil },
+		"zero threshold":   func(c *ImbalanceConfig) { c.Threshold = 0 },
+		"threshold of one": func(c *ImbalanceConfig) { c.Threshold = 1 },
+		"zero quantity":    func(c *ImbalanceConfig) { c.Quantity = 0 },
+		"negative depth":   func(c *ImbalanceConfig) { c.Depth = -1 },
+	} {
+		config := valid
+		mutate(&config)
+		assert.Error(t, config.Validate(), name)
+	}
+}
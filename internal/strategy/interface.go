@@ -79,18 +79,60 @@ type StrategyResults struct {
 	Metrics          StrategyMetrics `json:"metrics"`
 }
 
+// orderBookConsumer is a strategy that reads the live order books
+type orderBookConsumer interface {
+	SetOrderBookManager(manager *orderbook.Manager)
+}
+
+// signalPreviewer is a strategy whose GenerateSignals changes its own state,
+// which can also evaluate its signals without doing so
+type signalPreviewer interface {
+	PreviewSignals(orderBooks map[string]*orderbook.OrderBook) ([]*Signal, error)
+}
+
+// topOfBookConsumer is a strategy notified when a book's best bid or ask changes
+type topOfBookConsumer interface {
+	OnTopOfBook(event orderbook.TopOfBookEvent)
+}
+
 // Engine manages all trading strategies
 type Engine struct {
-	orderBooks *orderbook.Manager
-	strategies map[string]Strategy
-	mu         sync.RWMutex
+	orderBooks   *orderbook.Manager
+	strategies   map[string]Strategy
+	killSwitch   KillSwitchConfig
+	killStates   map[string]*killSwitchState
+	onKillSwitch func(event KillSwitchEvent)
+	tradeHistory []TradeResult // Realized trades, for the leaderboard
+	mu           sync.RWMutex
 }
 
 // NewEngine creates a new strategy engine
 func NewEngine(bookManager *orderbook.Manager) *Engine {
-	return &Engine{
+	e := &Engine{
 		orderBooks: bookManager,
 		strategies: make(map[string]Strategy),
+		killSwitch: DefaultKillSwitchConfig(),
+		killStates: make(map[string]*killSwitchState),
+	}
+	if bookManager != nil {
+		bookManager.SubscribeTopOfBook(e.dispatchTopOfBook)
+	}
+	return e
+}
+
+// dispatchTopOfBook forwards a top-of-book change to the strategies that consume it
+func (e *Engine) dispatchTopOfBook(event orderbook.TopOfBookEvent) {
+	e.mu.RLock()
+	consumers := make([]topOfBookConsumer, 0, len(e.strategies))
+	for _, strategy := range e.strategies {
+		if consumer, ok := strategy.(topOfBookConsumer); ok {
+			consumers = append(consumers, consumer)
+		}
+	}
+	e.mu.RUnlock()
+	
+	for _, consumer := range consumers {
+		consumer.OnTopOfBook(event)
 	}
 }
 
@@ -101,9 +143,9 @@ func (e *Engine) RegisterStrategy(strategy Strategy) {
 	
 	e.strategies[strategy.GetName()] = strategy
 	
-	// If the strategy is an ArbitrageStrategy, set its order book manager
-	if arbStrategy, ok := strategy.(*ArbitrageStrategy); ok {
-		arbStrategy.SetOrderBookManager(e.orderBooks)
+	// Strategies that poll the live books get the engine's order book manager
+	if consumer, ok := strategy.(orderBookConsumer); ok {
+		consumer.SetOrderBookManager(e.orderBooks)
 	}
 }
 
@@ -150,6 +192,31 @@ func (e *Engine) GetAllResults() map[string]StrategyResults {
 	return results
 }
 
+// CurrentSignals evaluates a strategy against the current order books and
+// returns the signals it would emit, without acting on them. It reports
+// false if no strategy has the name.
+func (e *Engine) CurrentSignals(name string) ([]*Signal, bool, error) {
+	strategy, exists := e.GetStrategy(name)
+	if !exists {
+		return nil, false, nil
+	}
+	
+	books := make(map[string]*orderbook.OrderBook)
+	if e.orderBooks != nil {
+		books = e.orderBooks.GetAllOrderBooks()
+	}
+	
+	generate := strategy.GenerateSignals
+	if previewer, ok := strategy.(signalPreviewer); ok {
+		generate = previewer.PreviewSignals
+	}
+	signals, err := generate(books)
+	if err != nil {
+		return nil, true, err
+	}
+	return signals, true, nil
+}
+
 // StartAll starts all registered strategies
 func (e *Engine) StartAll(ctx context.Context) error {
 	e.mu.RLock()
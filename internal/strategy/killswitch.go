@@ -0,0 +1,187 @@
+package strategy
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// KillSwitchConfig configures the per-strategy loss guard. Zero limits are disabled.
+type KillSwitchConfig struct {
+	Enabled              bool          `json:"enabled" yaml:"enabled"`
+	MaxConsecutiveLosses int           `json:"maxConsecutiveLosses" yaml:"maxConsecutiveLosses"`
+	MaxLoss              float64       `json:"maxLoss" yaml:"maxLoss"` // Net realized loss within Window that stops the strategy
+	Window               time.Duration `json:"window" yaml:"window"`
+}
+
+// DefaultKillSwitchConfig returns default kill-switch configuration
+func DefaultKillSwitchConfig() KillSwitchConfig {
+	return KillSwitchConfig{
+		Enabled:              false,
+		MaxConsecutiveLosses: 5,
+		MaxLoss:              0,
+		Window:               time.Hour,
+	}
+}
+
+// TradeResult is a realized trade attributed to a strategy
+type TradeResult struct {
+	Strategy  string    `json:"strategy"`
+	Symbol    string    `json:"symbol"`
+	PnL       float64   `json:"pnl"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// KillSwitchEvent describes a strategy stopped by the kill switch
+type KillSwitchEvent struct {
+	Strategy          string    `json:"strategy"`
+	Reason            string    `json:"reason"`
+	ConsecutiveLosses int       `json:"consecutiveLosses"`
+	WindowPnL         float64   `json:"windowPnl"`
+	Timestamp         time.Time `json:"timestamp"`
+}
+
+// KillSwitchStatus reports the kill-switch state of a strategy
+type KillSwitchStatus struct {
+	Tripped           bool      `json:"tripped"`
+	Reason            string    `json:"reason,omitempty"`
+	ConsecutiveLosses int       `json:"consecutiveLosses"`
+	WindowPnL         float64   `json:"windowPnl"`
+	TrippedAt         time.Time `json:"trippedAt,omitempty"`
+}
+
+// killSwitchState tracks realized results for one strategy
+type killSwitchState struct {
+	consecutiveLosses int
+	trades            []TradeResult // Trades inside the loss window
+	tripped           bool
+	reason            string
+	trippedAt         time.Time
+}
+
+// windowPnL drops trades older than the window and returns the net realized PnL
+func (s *killSwitchState) windowPnL(now time.Time, window time.Duration) float64 {
+	if window > 0 {
+		cutoff := now.Add(-window)
+		kept := s.trades[:0]
+		for _, trade := range s.trades {
+			if trade.Timestamp.After(cutoff) {
+				kept = append(kept, trade)
+			}
+		}
+		s.trades = kept
+	}
+
+	pnl := 0.0
+	for _, trade := range s.trades {
+		pnl += trade.PnL
+	}
+	return pnl
+}
+
+// SetKillSwitch configures the per-strategy loss guard
+func (e *Engine) SetKillSwitch(config KillSwitchConfig) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.killSwitch = config
+}
+
+// SetKillSwitchHandler sets the callback used to alert when a strategy is stopped
+func (e *Engine) SetKillSwitchHandler(handler func(event KillSwitchEvent)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.onKillSwitch = handler
+}
+
+// RecordTradeResult feeds a realized trade into the strategy's kill switch,
+// stopping the strategy if it breaches the configured limits. The trade is
+// also kept for the leaderboard.
+func (e *Engine) RecordTradeResult(result TradeResult) {
+	if result.Timestamp.IsZero() {
+		result.Timestamp = time.Now()
+	}
+
+	e.mu.Lock()
+	e.recordTradeHistory(result)
+	strategy, exists := e.strategies[result.Strategy]
+	if !exists || !e.killSwitch.Enabled {
+		e.mu.Unlock()
+		return
+	}
+
+	state := e.killStates[result.Strategy]
+	if state == nil {
+		state = &killSwitchState{}
+		e.killStates[result.Strategy] = state
+	}
+	if state.tripped {
+		e.mu.Unlock()
+		return
+	}
+
+	switch {
+	case result.PnL < 0:
+		state.consecutiveLosses++
+	case result.PnL > 0:
+		state.consecutiveLosses = 0
+	}
+	state.trades = append(state.trades, result)
+	windowPnL := state.windowPnL(result.Timestamp, e.killSwitch.Window)
+
+	reason := ""
+	if limit := e.killSwitch.MaxConsecutiveLosses; limit > 0 && state.consecutiveLosses >= limit {
+		reason = fmt.Sprintf("%d consecutive losing trades", state.consecutiveLosses)
+	} else if limit := e.killSwitch.MaxLoss; limit > 0 && windowPnL <= -limit {
+		reason = fmt.Sprintf("realized loss %.2f within %s exceeds %.2f", -windowPnL, e.killSwitch.Window, limit)
+	}
+	if reason == "" {
+		e.mu.Unlock()
+		return
+	}
+
+	state.tripped = true
+	state.reason = reason
+	state.trippedAt = result.Timestamp
+	event := KillSwitchEvent{
+		Strategy:          result.Strategy,
+		Reason:            reason,
+		ConsecutiveLosses: state.consecutiveLosses,
+		WindowPnL:         windowPnL,
+		Timestamp:         result.Timestamp,
+	}
+	handler := e.onKillSwitch
+	e.mu.Unlock()
+
+	log.Printf("Kill switch stopped strategy %s: %s", event.Strategy, reason)
+	if err := strategy.Stop(); err != nil {
+		log.Printf("Error stopping strategy %s: %v", event.Strategy, err)
+	}
+	if handler != nil {
+		handler(event)
+	}
+}
+
+// KillSwitchStatus returns the kill-switch state of a strategy
+func (e *Engine) KillSwitchStatus(name string) KillSwitchStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	state := e.killStates[name]
+	if state == nil {
+		return KillSwitchStatus{}
+	}
+	return KillSwitchStatus{
+		Tripped:           state.tripped,
+		Reason:            state.reason,
+		ConsecutiveLosses: state.consecutiveLosses,
+		WindowPnL:         state.windowPnL(time.Now(), e.killSwitch.Window),
+		TrippedAt:         state.trippedAt,
+	}
+}
+
+// ResetKillSwitch clears a tripped kill switch so the strategy can be restarted
+func (e *Engine) ResetKillSwitch(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.killStates, name)
+}
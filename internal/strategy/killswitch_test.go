@@ -0,0 +1,122 @@
+package strategy
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/orderbook"
+)
+
+// stubStrategy records whether it has been stopped
+type stubStrategy struct {
+	name    string
+	mu      sync.Mutex
+	running bool
+}
+
+func (s *stubStrategy) GetID() string   { return s.name }
+func (s *stubStrategy) GetName() string { return s.name }
+
+func (s *stubStrategy) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = true
+	return nil
+}
+
+func (s *stubStrategy) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = false
+	return nil
+}
+
+func (s *stubStrategy) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+func (s *stubStrategy) GetResults() StrategyResults { return StrategyResults{Name: s.name} }
+
+func (s *stubStrategy) GenerateSignals(orderBooks map[string]*orderbook.OrderBook) ([]*Signal, error) {
+	return nil, nil
+}
+
+func newKillSwitchEngine(t *testing.T, config KillSwitchConfig) (*Engine, *stubStrategy, *[]KillSwitchEvent) {
+	t.Helper()
+
+	engine := NewEngine(orderbook.NewManager())
+	strategy := &stubStrategy{name: "pairs"}
+	engine.RegisterStrategy(strategy)
+	require.NoError(t, strategy.Start(context.Background()))
+
+	events := &[]KillSwitchEvent{}
+	engine.SetKillSwitch(config)
+	engine.SetKillSwitchHandler(func(event KillSwitchEvent) {
+		*events = append(*events, event)
+	})
+	return engine, strategy, events
+}
+
+// TestKillSwitchConsecutiveLosses tests that a strategy stops at the configured losing streak
+func TestKillSwitchConsecutiveLosses(t *testing.T) {
+	engine, strategy, events := newKillSwitchEngine(t, KillSwitchConfig{Enabled: true, MaxConsecutiveLosses: 3})
+	now := time.Now()
+
+	// A win resets the streak
+	engine.RecordTradeResult(TradeResult{Strategy: "pairs", PnL: -10, Timestamp: now})
+	engine.RecordTradeResult(TradeResult{Strategy: "pairs", PnL: -10, Timestamp: now})
+	engine.RecordTradeResult(TradeResult{Strategy: "pairs", PnL: 5, Timestamp: now})
+	engine.RecordTradeResult(TradeResult{Strategy: "pairs", PnL: -10, Timestamp: now})
+	engine.RecordTradeResult(TradeResult{Strategy: "pairs", PnL: -10, Timestamp: now})
+	assert.True(t, strategy.IsRunning())
+	assert.Empty(t, *events)
+	assert.Equal(t, 2, engine.KillSwitchStatus("pairs").ConsecutiveLosses)
+
+	engine.RecordTradeResult(TradeResult{Strategy: "pairs", PnL: -10, Timestamp: now})
+	assert.False(t, strategy.IsRunning())
+	require.Len(t, *events, 1)
+	assert.Equal(t, "pairs", (*events)[0].Strategy)
+	assert.Equal(t, 3, (*events)[0].ConsecutiveLosses)
+
+	status := engine.KillSwitchStatus("pairs")
+	assert.True(t, status.Tripped)
+	assert.Contains(t, status.Reason, "3 consecutive")
+
+	// Further losses do not re-alert; a reset re-arms the guard
+	engine.RecordTradeResult(TradeResult{Strategy: "pairs", PnL: -10, Timestamp: now})
+	assert.Len(t, *events, 1)
+	engine.ResetKillSwitch("pairs")
+	assert.False(t, engine.KillSwitchStatus("pairs").Tripped)
+}
+
+// TestKillSwitchWindowLoss tests that a net loss inside the window stops the strategy
+func TestKillSwitchWindowLoss(t *testing.T) {
+	engine, strategy, events := newKillSwitchEngine(t, KillSwitchConfig{Enabled: true, MaxLoss: 100, Window: time.Minute})
+	start := time.Now()
+
+	// Losses that age out of the window no longer count
+	engine.RecordTradeResult(TradeResult{Strategy: "pairs", PnL: -80, Timestamp: start})
+	engine.RecordTradeResult(TradeResult{Strategy: "pairs", PnL: -50, Timestamp: start.Add(2 * time.Minute)})
+	assert.True(t, strategy.IsRunning())
+
+	engine.RecordTradeResult(TradeResult{Strategy: "pairs", PnL: -60, Timestamp: start.Add(2*time.Minute + time.Second)})
+	assert.False(t, strategy.IsRunning())
+	require.Len(t, *events, 1)
+	assert.InDelta(t, -110, (*events)[0].WindowPnL, 1e-9)
+}
+
+// TestKillSwitchDisabled tests that results are ignored when the guard is off or the strategy is unknown
+func TestKillSwitchDisabled(t *testing.T) {
+	engine, strategy, events := newKillSwitchEngine(t, KillSwitchConfig{MaxConsecutiveLosses: 1})
+
+	engine.RecordTradeResult(TradeResult{Strategy: "pairs", PnL: -10})
+	engine.RecordTradeResult(TradeResult{Strategy: "unknown", PnL: -10})
+	assert.True(t, strategy.IsRunning())
+	assert.Empty(t, *events)
+}
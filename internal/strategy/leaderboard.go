@@ -0,0 +1,161 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Leaderboard ranking metrics
+const (
+	RankBySharpe  = "sharpe"
+	RankByReturn  = "return"
+	RankByWinRate = "win_rate"
+)
+
+// maxTradeHistory bounds the realized trades kept for the leaderboard
+const maxTradeHistory = 10000
+
+// PerformanceStats summarises a set of realized trades
+type PerformanceStats struct {
+	Trades  int     `json:"trades"`
+	Return  float64 `json:"return"`  // Net realized PnL
+	WinRate float64 `json:"winRate"` // Fraction of trades with positive PnL
+	Sharpe  float64 `json:"sharpe"`  // Mean over standard deviation of trade PnL
+}
+
+// metric returns the value of a ranking metric
+func (s PerformanceStats) metric(name string) float64 {
+	switch name {
+	case RankByReturn:
+		return s.Return
+	case RankByWinRate:
+		return s.WinRate
+	default:
+		return s.Sharpe
+	}
+}
+
+// LeaderboardEntry is a strategy's place on the leaderboard. Combined pools
+// its live and backtest trades and is what the strategy is ranked on.
+type LeaderboardEntry struct {
+	Rank     int              `json:"rank"`
+	Strategy string           `json:"strategy"`
+	Value    float64          `json:"value"`
+	Combined PerformanceStats `json:"combined"`
+	Live     PerformanceStats `json:"live"`
+	Backtest PerformanceStats `json:"backtest"`
+}
+
+// ValidRankMetric reports whether the leaderboard can rank by a metric
+func ValidRankMetric(metric string) bool {
+	switch metric {
+	case RankBySharpe, RankByReturn, RankByWinRate:
+		return true
+	}
+	return false
+}
+
+// Performance computes the stats of a set of trades
+func Performance(trades []TradeResult) PerformanceStats {
+	stats := PerformanceStats{Trades: len(trades)}
+	if len(trades) == 0 {
+		return stats
+	}
+
+	wins := 0
+	for _, trade := range trades {
+		stats.Return += trade.PnL
+		if trade.PnL > 0 {
+			wins++
+		}
+	}
+	stats.WinRate = float64(wins) / float64(len(trades))
+
+	// Sample standard deviation; a single trade has no Sharpe
+	if len(trades) > 1 {
+		mean := stats.Return / float64(len(trades))
+		variance := 0.0
+		for _, trade := range trades {
+			variance += (trade.PnL - mean) * (trade.PnL - mean)
+		}
+		if stdDev := math.Sqrt(variance / float64(len(trades)-1)); stdDev > 0 {
+			stats.Sharpe = mean / stdDev
+		}
+	}
+	return stats
+}
+
+// recordTradeHistory keeps a realized trade for the leaderboard. The caller
+// must hold the lock.
+func (e *Engine) recordTradeHistory(result TradeResult) {
+	e.tradeHistory = append(e.tradeHistory, result)
+	if len(e.tradeHistory) > maxTradeHistory {
+		e.tradeHistory = e.tradeHistory[len(e.tradeHistory)-maxTradeHistory:]
+	}
+}
+
+// Leaderboard ranks strategies by a metric over their live trades within
+// window of now, which is unbounded when zero, together with the given
+// backtest trades. Every registered strategy is listed, as is any strategy
+// with backtest trades; ties rank by name.
+func (e *Engine) Leaderboard(metric string, window time.Duration, backtest []TradeResult) ([]LeaderboardEntry, error) {
+	if !ValidRankMetric(metric) {
+		return nil, fmt.Errorf("unknown leaderboard metric: %s", metric)
+	}
+	if window < 0 {
+		return nil, fmt.Errorf("leaderboard window cannot be negative")
+	}
+
+	live := make(map[string][]TradeResult)
+	cutoff := time.Now().Add(-window)
+
+	e.mu.RLock()
+	for name := range e.strategies {
+		live[name] = nil
+	}
+	for _, trade := range e.tradeHistory {
+		if window == 0 || trade.Timestamp.After(cutoff) {
+			live[trade.Strategy] = append(live[trade.Strategy], trade)
+		}
+	}
+	e.mu.RUnlock()
+
+	backtested := make(map[string][]TradeResult)
+	for _, trade := range backtest {
+		backtested[trade.Strategy] = append(backtested[trade.Strategy], trade)
+	}
+
+	names := make(map[string]bool)
+	for name := range live {
+		names[name] = true
+	}
+	for name := range backtested {
+		names[name] = true
+	}
+
+	entries := make([]LeaderboardEntry, 0, len(names))
+	for name := range names {
+		combined := append(append([]TradeResult(nil), live[name]...), backtested[name]...)
+		entry := LeaderboardEntry{
+			Strategy: name,
+			Combined: Performance(combined),
+			Live:     Performance(live[name]),
+			Backtest: Performance(backtested[name]),
+		}
+		entry.Value = entry.Combined.metric(metric)
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Value != entries[j].Value {
+			return entries[i].Value > entries[j].Value
+		}
+		return entries[i].Strategy < entries[j].Strategy
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries, nil
+}
@@ -0,0 +1,97 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/orderbook"
+)
+
+func trades(strategy string, at time.Time, pnls ...float64) []TradeResult {
+	results := make([]TradeResult, 0, len(pnls))
+	for _, pnl := range pnls {
+		results = append(results, TradeResult{Strategy: strategy, Symbol: "BTC/USD", PnL: pnl, Timestamp: at})
+	}
+	return results
+}
+
+func leaderboardOrder(entries []LeaderboardEntry) []string {
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Strategy)
+	}
+	return names
+}
+
+// TestLeaderboardRanking tests that strategies are ranked by the chosen
+// metric over their pooled live and backtest trades
+func TestLeaderboardRanking(t *testing.T) {
+	engine := NewEngine(orderbook.NewManager())
+	for _, name := range []string{"steady", "volatile", "loser", "idle"} {
+		engine.RegisterStrategy(&stubStrategy{name: name})
+	}
+
+	now := time.Now()
+	// Steady wins small amounts consistently, volatile makes more but erratically
+	for _, trade := range trades("steady", now, 10, 12, 11) {
+		engine.RecordTradeResult(trade)
+	}
+	for _, trade := range trades("volatile", now, 100, -40, 80) {
+		engine.RecordTradeResult(trade)
+	}
+	for _, trade := range trades("loser", now, -5, 1) {
+		engine.RecordTradeResult(trade)
+	}
+	backtest := append(trades("steady", now, 9), trades("backtest-only", now, 20, -20, 20)...)
+
+	entries, err := engine.Leaderboard(RankBySharpe, 0, backtest)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"steady", "volatile", "backtest-only", "idle", "loser"}, leaderboardOrder(entries))
+	for i, entry := range entries {
+		assert.Equal(t, i+1, entry.Rank)
+	}
+
+	steady := entries[0]
+	assert.Equal(t, 4, steady.Combined.Trades)
+	assert.Equal(t, 3, steady.Live.Trades)
+	assert.Equal(t, 1, steady.Backtest.Trades)
+	assert.InDelta(t, 42, steady.Combined.Return, 1e-9)
+
+	entries, err = engine.Leaderboard(RankByReturn, 0, backtest)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"volatile", "steady", "backtest-only", "idle", "loser"}, leaderboardOrder(entries))
+	assert.InDelta(t, 140, entries[0].Value, 1e-9)
+
+	entries, err = engine.Leaderboard(RankByWinRate, 0, backtest)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"steady", "backtest-only", "volatile", "loser", "idle"}, leaderboardOrder(entries))
+
+	_, err = engine.Leaderboard("sortino", 0, nil)
+	assert.Error(t, err)
+}
+
+// TestLeaderboardWindow tests that live trades older than the window are left out
+func TestLeaderboardWindow(t *testing.T) {
+	engine := NewEngine(orderbook.NewManager())
+	engine.RegisterStrategy(&stubStrategy{name: "early"})
+	engine.RegisterStrategy(&stubStrategy{name: "recent"})
+
+	now := time.Now()
+	for _, trade := range trades("early", now.Add(-2*time.Hour), 100) {
+		engine.RecordTradeResult(trade)
+	}
+	for _, trade := range trades("recent", now, 10) {
+		engine.RecordTradeResult(trade)
+	}
+
+	entries, err := engine.Leaderboard(RankByReturn, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"early", "recent"}, leaderboardOrder(entries))
+
+	entries, err = engine.Leaderboard(RankByReturn, time.Hour, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"recent", "early"}, leaderboardOrder(entries))
+	assert.Equal(t, 0, entries[1].Live.Trades)
+}
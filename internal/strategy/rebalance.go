@@ -0,0 +1,365 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"velocimex/internal/orderbook"
+)
+
+// RebalanceConfig contains configuration for the portfolio rebalancing strategy
+type RebalanceConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Name     string `yaml:"name"`
+	Exchange string `yaml:"exchange"` // Exchange whose books value the portfolio and receive the orders
+	// TargetWeights maps symbol -> fraction of portfolio value; the remainder is held as cash
+	TargetWeights map[string]float64 `yaml:"targetWeights"`
+	// DriftThreshold is how far, as a fraction of portfolio value, any weight may drift before rebalancing
+	DriftThreshold   float64            `yaml:"driftThreshold"`
+	MinTradeValue    float64            `yaml:"minTradeValue"`    // Smallest order notional worth sending
+	MinTradeQuantity map[string]float64 `yaml:"minTradeQuantity"` // Symbol -> smallest order quantity
+	InitialCash      float64            `yaml:"initialCash"`
+	InitialHoldings  map[string]float64 `yaml:"initialHoldings"` // Symbol -> quantity
+	UpdateInterval   time.Duration      `yaml:"updateInterval"`
+}
+
+// RebalanceStrategy keeps a portfolio at its target weights, trading back to
+// them whenever any weight drifts past the threshold. It tracks holdings by
+// assuming its own orders fill; SetHoldings resynchronises them with the
+// positions actually held.
+type RebalanceStrategy struct {
+	config     RebalanceConfig
+	orderBooks *orderbook.Manager
+	running    bool
+	ctx        context.Context
+	cancel     context.CancelFunc
+
+	muHoldings sync.Mutex
+	cash       float64
+	holdings   map[string]float64
+
+	muResults sync.RWMutex
+	results   StrategyResults
+}
+
+// NewRebalanceStrategy creates a new portfolio rebalancing strategy
+func NewRebalanceStrategy(config RebalanceConfig) *RebalanceStrategy {
+	if config.Name == "" {
+		config.Name = "rebalance"
+	}
+
+	holdings := make(map[string]float64, len(config.InitialHoldings))
+	for symbol, quantity := range config.InitialHoldings {
+		holdings[symbol] = quantity
+	}
+
+	return &RebalanceStrategy{
+		config:   config,
+		cash:     config.InitialCash,
+		holdings: holdings,
+		results: StrategyResults{
+			Name:             config.Name,
+			RecentSignals:    make([]TradeSignal, 0),
+			CurrentPositions: make([]Position, 0),
+		},
+	}
+}
+
+// SetOrderBookManager sets the order book manager used in live trading
+func (s *RebalanceStrategy) SetOrderBookManager(manager *orderbook.Manager) {
+	s.orderBooks = manager
+}
+
+// SetHoldings replaces the tracked cash and holdings, e.g. with the positions reported by the order manager
+func (s *RebalanceStrategy) SetHoldings(cash float64, holdings map[string]float64) {
+	s.muHoldings.Lock()
+	defer s.muHoldings.Unlock()
+
+	s.cash = cash
+	s.holdings = make(map[string]float64, len(holdings))
+	for symbol, quantity := range holdings {
+		s.holdings[symbol] = quantity
+	}
+}
+
+// Holdings returns the tracked cash and a copy of the holdings
+func (s *RebalanceStrategy) Holdings() (float64, map[string]float64) {
+	s.muHoldings.Lock()
+	defer s.muHoldings.Unlock()
+
+	holdings := make(map[string]float64, len(s.holdings))
+	for symbol, quantity := range s.holdings {
+		holdings[symbol] = quantity
+	}
+	return s.cash, holdings
+}
+
+// GetID returns the ID of the strategy
+func (s *RebalanceStrategy) GetID() string {
+	return "rebalance"
+}
+
+// GetName returns the name of the strategy
+func (s *RebalanceStrategy) GetName() string {
+	return s.config.Name
+}
+
+// WithParameters returns a new rebalancing strategy with the given parameters
+// applied on top of the current configuration
+func (s *RebalanceStrategy) WithParameters(params map[string]interface{}) (Strategy, error) {
+	config := s.config
+	for name, value := range params {
+		var v float64
+		switch n := value.(type) {
+		case float64:
+			v = n
+		case int:
+			v = float64(n)
+		default:
+			return nil, fmt.Errorf("parameter %s must be numeric", name)
+		}
+
+		switch name {
+		case "driftThreshold":
+			config.DriftThreshold = v
+		case "minTradeValue":
+			config.MinTradeValue = v
+		default:
+			return nil, fmt.Errorf("unknown rebalance parameter: %s", name)
+		}
+	}
+
+	strategy := NewRebalanceStrategy(config)
+	strategy.SetOrderBookManager(s.orderBooks)
+	return strategy, nil
+}
+
+// Start begins strategy execution
+func (s *RebalanceStrategy) Start(ctx context.Context) error {
+	s.muResults.Lock()
+	defer s.muResults.Unlock()
+
+	if s.running {
+		return nil
+	}
+
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.running = true
+	s.results.Running = true
+	s.results.StartTime = time.Now()
+
+	go s.run(s.ctx)
+
+	log.Printf("Started %s strategy", s.config.Name)
+	return nil
+}
+
+// Stop halts strategy execution
+func (s *RebalanceStrategy) Stop() error {
+	s.muResults.Lock()
+	defer s.muResults.Unlock()
+
+	if !s.running {
+		return nil
+	}
+
+	s.cancel()
+	s.running = false
+	s.results.Running = false
+
+	log.Printf("Stopped %s strategy", s.config.Name)
+	return nil
+}
+
+// IsRunning returns whether the strategy is currently running
+func (s *RebalanceStrategy) IsRunning() bool {
+	s.muResults.RLock()
+	defer s.muResults.RUnlock()
+	return s.running
+}
+
+// GetResults returns the current strategy results
+func (s *RebalanceStrategy) GetResults() StrategyResults {
+	s.muResults.RLock()
+	defer s.muResults.RUnlock()
+
+	results := s.results
+	results.RecentSignals = append([]TradeSignal(nil), s.results.RecentSignals...)
+	results.LastUpdate = time.Now()
+	return results
+}
+
+// run checks the live books for drift on every update interval
+func (s *RebalanceStrategy) run(ctx context.Context) {
+	interval := s.config.UpdateInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.orderBooks == nil {
+				continue
+			}
+			signals, err := s.GenerateSignals(s.orderBooks.GetAllOrderBooks())
+			if err != nil {
+				log.Printf("Rebalance strategy %s: %v", s.config.Name, err)
+				continue
+			}
+			s.recordSignals(signals)
+		}
+	}
+}
+
+// recordSignals adds live rebalancing orders to the strategy results
+func (s *RebalanceStrategy) recordSignals(signals []*Signal) {
+	if len(signals) == 0 {
+		return
+	}
+
+	s.muResults.Lock()
+	defer s.muResults.Unlock()
+
+	now := time.Now()
+	for _, signal := range signals {
+		price, _ := signal.Price.Float64()
+		volume, _ := signal.Quantity.Float64()
+		side := "buy"
+		if signal.Side == "SELL" {
+			side = "sell"
+		}
+
+		// Keep only the most recent signals (max 10)
+		if len(s.results.RecentSignals) >= 10 {
+			s.results.RecentSignals = s.results.RecentSignals[1:]
+		}
+		s.results.RecentSignals = append(s.results.RecentSignals, TradeSignal{
+			Strategy:   s.config.Name,
+			Symbol:     signal.Symbol,
+			Side:       side,
+			Price:      price,
+			Volume:     volume,
+			Exchange:   signal.Exchange,
+			Timestamp:  now,
+			Confidence: 1,
+			Reason:     fmt.Sprintf("Rebalance %s to %.2f%% target weight", signal.Symbol, signal.Metadata["target_weight"].(float64)*100),
+		})
+		s.results.SignalsGenerated++
+	}
+}
+
+// rebalanceQuote is the pricing of one target symbol
+type rebalanceQuote struct {
+	bid, ask, mid float64
+}
+
+// GenerateSignals returns the orders that bring the portfolio back to its
+// target weights, or none while every weight is within the drift threshold
+func (s *RebalanceStrategy) GenerateSignals(orderBooks map[string]*orderbook.OrderBook) ([]*Signal, error) {
+	return s.rebalance(orderBooks, true)
+}
+
+// PreviewSignals returns the orders GenerateSignals would, without assuming
+// they fill
+func (s *RebalanceStrategy) PreviewSignals(orderBooks map[string]*orderbook.OrderBook) ([]*Signal, error) {
+	return s.rebalance(orderBooks, false)
+}
+
+// rebalance computes the rebalancing orders, updating the tracked holdings
+// as if they filled when apply is set
+func (s *RebalanceStrategy) rebalance(orderBooks map[string]*orderbook.OrderBook, apply bool) ([]*Signal, error) {
+	symbols := make([]string, 0, len(s.config.TargetWeights))
+	for symbol := range s.config.TargetWeights {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	// The portfolio cannot be valued until every target symbol has a price
+	quotes := make(map[string]rebalanceQuote, len(symbols))
+	for _, symbol := range symbols {
+		book, ok := orderBooks[fmt.Sprintf("%s:%s", s.config.Exchange, symbol)]
+		if !ok {
+			return nil, nil
+		}
+		bid, ask := book.GetBestBid(), book.GetBestAsk()
+		if bid == nil || ask == nil || bid.Price <= 0 || ask.Price <= 0 {
+			return nil, nil
+		}
+		quotes[symbol] = rebalanceQuote{bid: bid.Price, ask: ask.Price, mid: (bid.Price + ask.Price) / 2}
+	}
+
+	s.muHoldings.Lock()
+	defer s.muHoldings.Unlock()
+
+	total := s.cash
+	for _, symbol := range symbols {
+		total += s.holdings[symbol] * quotes[symbol].mid
+	}
+	if total <= 0 {
+		return nil, nil
+	}
+
+	drifted := false
+	for _, symbol := range symbols {
+		weight := s.holdings[symbol] * quotes[symbol].mid / total
+		if math.Abs(weight-s.config.TargetWeights[symbol]) > s.config.DriftThreshold {
+			drifted = true
+			break
+		}
+	}
+	if !drifted {
+		return nil, nil
+	}
+
+	// Sells come first so their proceeds fund the buys
+	var sells, buys []*Signal
+	for _, symbol := range symbols {
+		quote := quotes[symbol]
+		target := s.config.TargetWeights[symbol]
+		delta := (target*total - s.holdings[symbol]*quote.mid) / quote.mid
+
+		side, price := "BUY", quote.ask
+		if delta < 0 {
+			side, price = "SELL", quote.bid
+		}
+		quantity := math.Abs(delta)
+		if quantity == 0 || quantity < s.config.MinTradeQuantity[symbol] || quantity*price < s.config.MinTradeValue {
+			continue
+		}
+
+		signal := &Signal{
+			Symbol:   symbol,
+			Exchange: s.config.Exchange,
+			Side:     side,
+			Quantity: decimal.NewFromFloat(quantity),
+			Price:    decimal.NewFromFloat(price),
+			Metadata: map[string]interface{}{
+				"target_weight":  target,
+				"current_weight": s.holdings[symbol] * quote.mid / total,
+			},
+		}
+		if side == "SELL" {
+			sells = append(sells, signal)
+			quantity = -quantity
+		} else {
+			buys = append(buys, signal)
+		}
+		if apply {
+			s.holdings[symbol] += quantity
+			s.cash -= quantity * price
+		}
+	}
+
+	return append(sells, buys...), nil
+}
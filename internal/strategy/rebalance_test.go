@@ -0,0 +1,155 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/normalizer"
+	"velocimex/internal/orderbook"
+)
+
+// rebalanceBooks returns binance books with BTC/USD at 100 and ETH/USD at 10
+func rebalanceBooks() map[string]*orderbook.OrderBook {
+	books := make(map[string]*orderbook.OrderBook)
+	for symbol, price := range map[string]float64{"BTC/USD": 100, "ETH/USD": 10} {
+		book := orderbook.NewOrderBook(symbol)
+		book.Update(
+			[]normalizer.PriceLevel{{Price: price, Volume: 1000}},
+			[]normalizer.PriceLevel{{Price: price, Volume: 1000}},
+		)
+		books["binance:"+symbol] = book
+	}
+	return books
+}
+
+func newTestRebalance(holdings map[string]float64, minQuantity map[string]float64) *RebalanceStrategy {
+	return NewRebalanceStrategy(RebalanceConfig{
+		Name:             "rebalance",
+		Exchange:         "binance",
+		TargetWeights:    map[string]float64{"BTC/USD": 0.5, "ETH/USD": 0.5},
+		DriftThreshold:   0.05,
+		MinTradeQuantity: minQuantity,
+		InitialHoldings:  holdings,
+	})
+}
+
+// TestRebalanceDriftedWeights tests that drifted weights produce orders back to target
+func TestRebalanceDriftedWeights(t *testing.T) {
+	// 600 in BTC and 400 in ETH against a 50/50 target
+	s := newTestRebalance(map[string]float64{"BTC/USD": 6, "ETH/USD": 40}, nil)
+
+	signals, err := s.GenerateSignals(rebalanceBooks())
+	require.NoError(t, err)
+	require.Len(t, signals, 2)
+
+	// The sell comes first so it funds the buy
+	assert.Equal(t, "BTC/USD", signals[0].Symbol)
+	assert.Equal(t, "SELL", signals[0].Side)
+	assert.Equal(t, "binance", signals[0].Exchange)
+	assert.True(t, signals[0].Quantity.Equal(decimal.NewFromInt(1)), "quantity %s", signals[0].Quantity)
+	assert.True(t, signals[0].Price.Equal(decimal.NewFromInt(100)))
+
+	assert.Equal(t, "ETH/USD", signals[1].Symbol)
+	assert.Equal(t, "BUY", signals[1].Side)
+	assert.True(t, signals[1].Quantity.Equal(decimal.NewFromInt(10)), "quantity %s", signals[1].Quantity)
+
+	// Tracked holdings assume the orders fill, so the portfolio is now on target
+	cash, holdings := s.Holdings()
+	assert.InDelta(t, 0, cash, 1e-9)
+	assert.InDelta(t, 5, holdings["BTC/USD"], 1e-9)
+	assert.InDelta(t, 50, holdings["ETH/USD"], 1e-9)
+
+	signals, err = s.GenerateSignals(rebalanceBooks())
+	require.NoError(t, err)
+	assert.Empty(t, signals)
+}
+
+// TestRebalanceWithinThreshold tests that drift inside the threshold does not trade
+func TestRebalanceWithinThreshold(t *testing.T) {
+	// 530 in BTC and 470 in ETH is 3% off target
+	s := newTestRebalance(map[string]float64{"BTC/USD": 5.3, "ETH/USD": 47}, nil)
+
+	signals, err := s.GenerateSignals(rebalanceBooks())
+	require.NoError(t, err)
+	assert.Empty(t, signals)
+}
+
+// TestRebalanceMinTradeSize tests that orders below the minimum trade size are skipped
+func TestRebalanceMinTradeSize(t *testing.T) {
+	s := newTestRebalance(map[string]float64{"BTC/USD": 6, "ETH/USD": 40}, map[string]float64{"BTC/USD": 2})
+
+	signals, err := s.GenerateSignals(rebalanceBooks())
+	require.NoError(t, err)
+	require.Len(t, signals, 1)
+	assert.Equal(t, "ETH/USD", signals[0].Symbol)
+	assert.Equal(t, "BUY", signals[0].Side)
+
+	// The skipped BTC sell stays in the tracked holdings
+	_, holdings := s.Holdings()
+	assert.InDelta(t, 6, holdings["BTC/USD"], 1e-9)
+}
+
+// TestRebalanceFromCash tests that cash is invested at the target weights
+func TestRebalanceFromCash(t *testing.T) {
+	s := NewRebalanceStrategy(RebalanceConfig{
+		Exchange:       "binance",
+		TargetWeights:  map[string]float64{"BTC/USD": 0.6, "ETH/USD": 0.2},
+		DriftThreshold: 0.05,
+		MinTradeValue:  10,
+		InitialCash:    1000,
+	})
+
+	signals, err := s.GenerateSignals(rebalanceBooks())
+	require.NoError(t, err)
+	require.Len(t, signals, 2)
+	assert.True(t, signals[0].Quantity.Equal(decimal.NewFromInt(6)), "quantity %s", signals[0].Quantity)
+	assert.True(t, signals[1].Quantity.Equal(decimal.NewFromInt(20)), "quantity %s", signals[1].Quantity)
+
+	cash, _ := s.Holdings()
+	assert.InDelta(t, 200, cash, 1e-9)
+}
+
+// TestRebalanceMissingPrice tests that nothing trades until every target symbol is priced
+func TestRebalanceMissingPrice(t *testing.T) {
+	s := newTestRebalance(map[string]float64{"BTC/USD": 6, "ETH/USD": 40}, nil)
+	books := rebalanceBooks()
+	delete(books, "binance:ETH/USD")
+
+	signals, err := s.GenerateSignals(books)
+	require.NoError(t, err)
+	assert.Empty(t, signals)
+}
+
+// TestEngineCurrentSignals tests that a dry evaluation returns the signals a
+// strategy would emit without changing its tracked holdings
+func TestEngineCurrentSignals(t *testing.T) {
+	books := orderbook.NewManager()
+	books.UpdateOrderBook("binance", "BTC/USD", []normalizer.PriceLevel{{Price: 100, Volume: 1000}}, []normalizer.PriceLevel{{Price: 100, Volume: 1000}})
+	books.UpdateOrderBook("binance", "ETH/USD", []normalizer.PriceLevel{{Price: 10, Volume: 1000}}, []normalizer.PriceLevel{{Price: 10, Volume: 1000}})
+
+	engine := NewEngine(books)
+	s := newTestRebalance(map[string]float64{"BTC/USD": 6, "ETH/USD": 40}, nil)
+	engine.RegisterStrategy(s)
+
+	for i := 0; i < 2; i++ {
+		signals, exists, err := engine.CurrentSignals("rebalance")
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Len(t, signals, 2)
+		assert.Equal(t, "SELL", signals[0].Side)
+		assert.True(t, signals[0].Quantity.Equal(decimal.NewFromInt(1)))
+		assert.Equal(t, "BUY", signals[1].Side)
+		assert.True(t, signals[1].Quantity.Equal(decimal.NewFromInt(10)))
+	}
+
+	cash, holdings := s.Holdings()
+	assert.Zero(t, cash)
+	assert.Equal(t, 6.0, holdings["BTC/USD"])
+	assert.Equal(t, 40.0, holdings["ETH/USD"])
+
+	_, exists, err := engine.CurrentSignals("missing")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
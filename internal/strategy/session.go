@@ -0,0 +1,266 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"velocimex/internal/orders"
+)
+
+// SessionConfig configures the trading session of intraday strategies. At
+// End every open position is flattened with market orders and the running
+// strategies are stopped; they are started again at the next Start.
+type SessionConfig struct {
+	Enabled  bool   `json:"enabled" yaml:"enabled"`
+	Start    string `json:"start" yaml:"start"`       // Time of day the session opens, "HH:MM"
+	End      string `json:"end" yaml:"end"`           // Time of day the session closes, "HH:MM"; before Start for a session spanning midnight
+	Timezone string `json:"timezone" yaml:"timezone"` // IANA zone for Start and End; empty uses UTC
+}
+
+// DefaultSessionConfig returns default session configuration
+func DefaultSessionConfig() SessionConfig {
+	return SessionConfig{
+		Enabled: false,
+		Start:   "09:30",
+		End:     "16:00",
+	}
+}
+
+// Validate checks the session times and timezone
+func (c SessionConfig) Validate() error {
+	_, _, err := c.parse()
+	return err
+}
+
+// parse returns the session's opening and closing minute of the day and its location
+func (c SessionConfig) parse() ([2]int, *time.Location, error) {
+	var minutes [2]int
+	for i, value := range []string{c.Start, c.End} {
+		var hour, minute int
+		if _, err := fmt.Sscanf(value, "%d:%d", &hour, &minute); err != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+			return minutes, nil, fmt.Errorf("invalid session time %q, want HH:MM", value)
+		}
+		minutes[i] = hour*60 + minute
+	}
+	if minutes[0] == minutes[1] {
+		return minutes, nil, fmt.Errorf("session start and end cannot both be %s", c.Start)
+	}
+
+	location := time.UTC
+	if c.Timezone != "" {
+		loc, err := time.LoadLocation(c.Timezone)
+		if err != nil {
+			return minutes, nil, fmt.Errorf("invalid session timezone: %w", err)
+		}
+		location = loc
+	}
+	return minutes, location, nil
+}
+
+// PositionFlattener closes open positions with market orders
+type PositionFlattener interface {
+	ClosePositions(ctx context.Context, filters map[string]interface{}) *orders.CloseAllResult
+}
+
+// SessionEndEvent describes the positions flattened and strategies stopped at session end
+type SessionEndEvent struct {
+	Stopped   []string                      `json:"stopped"`
+	Orders    []*orders.Order               `json:"orders"`
+	Failed    []orders.PositionCloseFailure `json:"failed"`
+	Timestamp time.Time                     `json:"timestamp"`
+}
+
+// SessionScheduler flattens positions and stops strategies when the trading
+// session ends, and restarts the strategies it stopped when the next one begins
+type SessionScheduler struct {
+	config    SessionConfig
+	location  *time.Location
+	opensAt   int // Minute of the day the session opens
+	closesAt  int // Minute of the day the session closes
+	engine    *Engine
+	flattener PositionFlattener
+
+	// Overridden in tests to drive the schedule
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+
+	mu      sync.Mutex
+	parent  context.Context // Context strategies are restarted with
+	stopped []string        // Strategies stopped at the last session end
+	onEnd   func(event SessionEndEvent)
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewSessionScheduler creates a session scheduler for the engine's strategies
+func NewSessionScheduler(config SessionConfig, engine *Engine, flattener PositionFlattener) (*SessionScheduler, error) {
+	if engine == nil || flattener == nil {
+		return nil, fmt.Errorf("session scheduler requires a strategy engine and a position flattener")
+	}
+	minutes, location, err := config.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionScheduler{
+		config:    config,
+		location:  location,
+		opensAt:   minutes[0],
+		closesAt:  minutes[1],
+		engine:    engine,
+		flattener: flattener,
+		now:       time.Now,
+		after:     time.After,
+	}, nil
+}
+
+// SetSessionEndHandler sets the callback used to report each session end
+func (s *SessionScheduler) SetSessionEndHandler(handler func(event SessionEndEvent)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onEnd = handler
+}
+
+// Start begins ending and resuming sessions at the configured times
+func (s *SessionScheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return fmt.Errorf("session scheduler already running")
+	}
+
+	s.parent = ctx
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.wg.Add(1)
+	go s.run(ctx)
+
+	log.Printf("Trading session scheduled %s-%s %s", s.config.Start, s.config.End, s.location)
+	return nil
+}
+
+// Stop stops the scheduler. Strategies it stopped stay stopped.
+func (s *SessionScheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		s.wg.Wait()
+	}
+}
+
+// run waits for each session boundary and ends or begins the session
+func (s *SessionScheduler) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	for {
+		now := s.now()
+		next, ending := s.nextTransition(now)
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.after(next.Sub(now)):
+			if ending {
+				s.EndSession(ctx)
+			} else {
+				s.BeginSession()
+			}
+		}
+	}
+}
+
+// EndSession stops the running strategies, so they cannot reopen positions,
+// then flattens every open position with market orders
+func (s *SessionScheduler) EndSession(ctx context.Context) SessionEndEvent {
+	event := SessionEndEvent{Stopped: make([]string, 0), Timestamp: s.now()}
+	for _, strategy := range s.engine.GetAllStrategies() {
+		if !strategy.IsRunning() {
+			continue
+		}
+		if err := strategy.Stop(); err != nil {
+			log.Printf("Session end: error stopping strategy %s: %v", strategy.GetName(), err)
+			continue
+		}
+		event.Stopped = append(event.Stopped, strategy.GetName())
+	}
+	sort.Strings(event.Stopped)
+
+	result := s.flattener.ClosePositions(ctx, nil)
+	event.Orders = result.Orders
+	event.Failed = result.Failed
+	for _, failure := range result.Failed {
+		log.Printf("Session end: failed to close %s %s on %s: %s", failure.Side, failure.Symbol, failure.Exchange, failure.Error)
+	}
+
+	s.mu.Lock()
+	s.stopped = append(s.stopped, event.Stopped...)
+	handler := s.onEnd
+	s.mu.Unlock()
+
+	log.Printf("Session ended: stopped %d strategies, submitted %d closing orders", len(event.Stopped), len(event.Orders))
+	if handler != nil {
+		handler(event)
+	}
+	return event
+}
+
+// BeginSession restarts the strategies stopped at the last session end,
+// except those the kill switch has since tripped
+func (s *SessionScheduler) BeginSession() {
+	s.mu.Lock()
+	stopped := s.stopped
+	s.stopped = nil
+	ctx := s.parent
+	s.mu.Unlock()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for _, name := range stopped {
+		strategy, exists := s.engine.GetStrategy(name)
+		if !exists || strategy.IsRunning() || s.engine.KillSwitchStatus(name).Tripped {
+			continue
+		}
+		if err := strategy.Start(ctx); err != nil {
+			log.Printf("Session start: error starting strategy %s: %v", name, err)
+		}
+	}
+}
+
+// InSession reports whether t falls within the trading session
+func (s *SessionScheduler) InSession(t time.Time) bool {
+	local := t.In(s.location)
+	minute := local.Hour()*60 + local.Minute()
+	if s.opensAt < s.closesAt {
+		return minute >= s.opensAt && minute < s.closesAt
+	}
+	return minute >= s.opensAt || minute < s.closesAt
+}
+
+// nextTransition returns the first session boundary strictly after now and
+// whether it is the session end
+func (s *SessionScheduler) nextTransition(now time.Time) (time.Time, bool) {
+	open := s.nextAt(now, s.opensAt)
+	end := s.nextAt(now, s.closesAt)
+	if end.Before(open) {
+		return end, true
+	}
+	return open, false
+}
+
+// nextAt returns the first time strictly after now at the given minute of the day
+func (s *SessionScheduler) nextAt(now time.Time, minute int) time.Time {
+	local := now.In(s.location)
+	next := time.Date(local.Year(), local.Month(), local.Day(), minute/60, minute%60, 0, 0, s.location)
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -0,0 +1,195 @@
+package strategy
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/orderbook"
+	"velocimex/internal/orders"
+)
+
+// fakeBook holds positions and flattens them as ClosePositions would
+type fakeBook struct {
+	mu        sync.Mutex
+	positions map[string]decimal.Decimal // Signed quantity by symbol
+}
+
+func newFakeBook(positions map[string]decimal.Decimal) *fakeBook {
+	return &fakeBook{positions: positions}
+}
+
+func (b *fakeBook) ClosePositions(ctx context.Context, filters map[string]interface{}) *orders.CloseAllResult {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	result := &orders.CloseAllResult{Orders: make([]*orders.Order, 0), Failed: make([]orders.PositionCloseFailure, 0)}
+	for symbol, quantity := range b.positions {
+		side := orders.OrderSideSell
+		if quantity.IsNegative() {
+			side = orders.OrderSideBuy
+		}
+		result.Orders = append(result.Orders, &orders.Order{Symbol: symbol, Side: side, Type: orders.OrderTypeMarket, Quantity: quantity.Abs()})
+		delete(b.positions, symbol)
+	}
+	return result
+}
+
+func (b *fakeBook) open() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.positions)
+}
+
+func newSessionEngine(t *testing.T) (*Engine, *stubStrategy, *stubStrategy) {
+	t.Helper()
+
+	engine := NewEngine(orderbook.NewManager())
+	running := &stubStrategy{name: "momentum"}
+	idle := &stubStrategy{name: "pairs"}
+	engine.RegisterStrategy(running)
+	engine.RegisterStrategy(idle)
+	require.NoError(t, running.Start(context.Background()))
+	return engine, running, idle
+}
+
+// TestSessionEndFlattensPositions tests that ending the session closes every
+// position and stops the running strategies until the next session
+func TestSessionEndFlattensPositions(t *testing.T) {
+	engine, running, idle := newSessionEngine(t)
+	book := newFakeBook(map[string]decimal.Decimal{
+		"BTC/USD": decimal.NewFromInt(2),
+		"ETH/USD": decimal.NewFromInt(-5),
+	})
+	scheduler, err := NewSessionScheduler(SessionConfig{Enabled: true, Start: "09:30", End: "16:00"}, engine, book)
+	require.NoError(t, err)
+
+	event := scheduler.EndSession(context.Background())
+	assert.Zero(t, book.open())
+	require.Len(t, event.Orders, 2)
+	for _, order := range event.Orders {
+		assert.Equal(t, orders.OrderTypeMarket, order.Type)
+	}
+	assert.Equal(t, []string{"momentum"}, event.Stopped)
+	assert.False(t, running.IsRunning())
+
+	// Only the strategy the session end stopped resumes
+	scheduler.BeginSession()
+	assert.True(t, running.IsRunning())
+	assert.False(t, idle.IsRunning())
+}
+
+// TestSessionEndSkipsKillSwitchedStrategies tests that a strategy tripped
+// while the session was closed is not resumed
+func TestSessionEndSkipsKillSwitchedStrategies(t *testing.T) {
+	engine, running, _ := newSessionEngine(t)
+	scheduler, err := NewSessionScheduler(SessionConfig{Enabled: true, Start: "09:30", End: "16:00"}, engine, newFakeBook(nil))
+	require.NoError(t, err)
+
+	scheduler.EndSession(context.Background())
+	engine.SetKillSwitch(KillSwitchConfig{Enabled: true, MaxConsecutiveLosses: 1})
+	engine.RecordTradeResult(TradeResult{Strategy: "momentum", PnL: -10})
+
+	scheduler.BeginSession()
+	assert.False(t, running.IsRunning())
+}
+
+// TestSessionSchedulerTriggers tests that the schedule ends the session at the
+// configured time and resumes it at the next start
+func TestSessionSchedulerTriggers(t *testing.T) {
+	engine, running, _ := newSessionEngine(t)
+	book := newFakeBook(map[string]decimal.Decimal{"BTC/USD": decimal.NewFromInt(1)})
+	scheduler, err := NewSessionScheduler(SessionConfig{Enabled: true, Start: "09:30", End: "16:00"}, engine, book)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	now := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	waits := make(chan time.Duration, 10)
+	ticks := make(chan time.Time)
+	scheduler.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	scheduler.after = func(d time.Duration) <-chan time.Time {
+		waits <- d
+		return ticks
+	}
+	// advance waits for the scheduler to be waiting, then moves the clock to the tick
+	advance := func(to time.Time, wait time.Duration) {
+		select {
+		case d := <-waits:
+			assert.Equal(t, wait, d)
+		case <-time.After(time.Second):
+			t.Fatal("scheduler is not waiting for the next session boundary")
+		}
+		mu.Lock()
+		now = to
+		mu.Unlock()
+		ticks <- to
+	}
+
+	ended := make(chan SessionEndEvent, 1)
+	scheduler.SetSessionEndHandler(func(event SessionEndEvent) { ended <- event })
+	require.NoError(t, scheduler.Start(context.Background()))
+	defer scheduler.Stop()
+
+	// 16:00 closes the session
+	advance(time.Date(2024, 3, 1, 16, 0, 0, 0, time.UTC), time.Hour)
+	select {
+	case event := <-ended:
+		assert.Len(t, event.Orders, 1)
+	case <-time.After(time.Second):
+		t.Fatal("session did not end on schedule tick")
+	}
+	assert.Zero(t, book.open())
+	assert.False(t, running.IsRunning())
+
+	// 09:30 the next day opens it again
+	advance(time.Date(2024, 3, 2, 9, 30, 0, 0, time.UTC), 17*time.Hour+30*time.Minute)
+	require.Eventually(t, running.IsRunning, time.Second, 5*time.Millisecond)
+}
+
+func TestSessionNextTransition(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		now        time.Time
+		want       time.Time
+		ending     bool
+	}{
+		{"before the open", "09:30", "16:00", time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC), false},
+		{"during the session", "09:30", "16:00", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 16, 0, 0, 0, time.UTC), true},
+		{"after the close", "09:30", "16:00", time.Date(2024, 3, 1, 16, 0, 0, 0, time.UTC), time.Date(2024, 3, 2, 9, 30, 0, 0, time.UTC), false},
+		{"overnight session", "22:00", "06:00", time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC), time.Date(2024, 3, 2, 6, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler, err := NewSessionScheduler(SessionConfig{Start: tt.start, End: tt.end}, NewEngine(nil), newFakeBook(nil))
+			require.NoError(t, err)
+			next, ending := scheduler.nextTransition(tt.now)
+			assert.True(t, next.Equal(tt.want), "got %s, want %s", next, tt.want)
+			assert.Equal(t, tt.ending, ending)
+			assert.Equal(t, ending, scheduler.InSession(tt.now))
+		})
+	}
+}
+
+func TestSessionConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultSessionConfig().Validate())
+	assert.NoError(t, SessionConfig{Start: "22:00", End: "06:00", Timezone: "America/New_York"}.Validate())
+
+	for _, config := range []SessionConfig{
+		{Start: "9am", End: "16:00"},
+		{Start: "09:30", End: "24:00"},
+		{Start: "09:30", End: "09:30"},
+		{Start: "09:30", End: "16:00", Timezone: "Mars/Olympus"},
+	} {
+		assert.Error(t, config.Validate(), "%+v", config)
+	}
+}
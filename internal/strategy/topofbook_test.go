@@ -0,0 +1,46 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"velocimex/internal/normalizer"
+	"velocimex/internal/orderbook"
+)
+
+// quoteStrategy records the top-of-book changes it is notified of
+type quoteStrategy struct {
+	stubStrategy
+	events []orderbook.TopOfBookEvent
+}
+
+func (s *quoteStrategy) OnTopOfBook(event orderbook.TopOfBookEvent) {
+	s.events = append(s.events, event)
+}
+
+// TestEngineDispatchesTopOfBook tests that consuming strategies receive top-of-book changes
+func TestEngineDispatchesTopOfBook(t *testing.T) {
+	books := orderbook.NewManager()
+	engine := NewEngine(books)
+	consumer := &quoteStrategy{stubStrategy: stubStrategy{name: "quotes"}}
+	engine.RegisterStrategy(consumer)
+	engine.RegisterStrategy(&stubStrategy{name: "other"})
+
+	bids := []normalizer.PriceLevel{{Price: 100, Volume: 1}, {Price: 99, Volume: 2}}
+	asks := []normalizer.PriceLevel{{Price: 101, Volume: 1}}
+	books.UpdateOrderBook("binance", "BTCUSDT", bids, asks)
+
+	// A deeper level changes only
+	bids = []normalizer.PriceLevel{{Price: 100, Volume: 1}, {Price: 98, Volume: 3}}
+	books.UpdateOrderBook("binance", "BTCUSDT", bids, []normalizer.PriceLevel{{Price: 101, Volume: 1}})
+
+	require.Len(t, consumer.events, 1)
+	assert.Equal(t, "binance", consumer.events[0].Exchange)
+	assert.Equal(t, 100.0, consumer.events[0].NewBid.Price)
+
+	// Unregistered strategies stop receiving events
+	engine.UnregisterStrategy("quotes")
+	books.UpdateOrderBook("binance", "BTCUSDT", []normalizer.PriceLevel{{Price: 100.5, Volume: 1}}, nil)
+	assert.Len(t, consumer.events, 1)
+}